@@ -2,8 +2,9 @@ package cmd
 
 import (
 	"os"
+	"time"
 
-	"github.com/grovetools/docgen/pkg/aggregator"
+	"github.com/grovetools/docgen/pkg/docgen"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +15,12 @@ func newAggregateCmd() *cobra.Command {
 		defaultMode = "dev"
 	}
 
+	var reports reportFlags
+	var timeout time.Duration
+	var packages, categories []string
+	var strict bool
+	var audiences []string
+
 	cmd := &cobra.Command{
 		Use:   "aggregate",
 		Short: "Generate and aggregate documentation from all workspace packages",
@@ -23,21 +30,82 @@ The --mode flag controls which documentation status levels are included:
   dev: Includes draft, dev, and production sections (for dev website)
   prod: Only includes production sections (for production website)
 
-Mode can also be set via the DOCGEN_MODE environment variable.
+Mode can also be set via the DOCGEN_MODE environment variable. Sections,
+concepts and sidebar packages are filtered exactly as docgen watch filters
+them. The manifest records the mode, and each section records its status.
+A --packages/--category run refuses to patch a manifest built in the other
+mode.
+
+The --profile flag selects what the output is for:
+  internal: Everything, for the internal notebook site (default)
+  public: Leaves out sections with internal: true and profile=internal
+          conditional blocks, and replaces the settings.scrub patterns
+          (internal hostnames, emails) in every published file
+
+The --audience flag builds a site for some audiences only, such as a public
+docs site and an internal handbook from the same sources: sections whose
+audience field (in the config or the doc's frontmatter) lists none of them
+are left out. Sections without an audience are published for every audience.
+The manifest records each section's audience.
 
 The --transform flag applies output-specific transformations to the documentation:
-  astro: Rewrites asset paths and adds Astro-compatible frontmatter for the Grove website`,
+  astro: Rewrites asset paths and callouts and adds Astro-compatible frontmatter
+         for the Grove website
+
+The --packages and --category flags rebuild only the selected packages and
+update their entries in the existing manifest, leaving the other packages as
+they are:
+  docgen aggregate -o dist --packages flow,cx
+  docgen aggregate -o dist --category "Core Tools"
+
+Every run writes validation-report.json to the output directory, listing
+sections published as placeholders or missing, packages skipped and why,
+broken links, missing assets, and docs older than their prompts. --strict
+fails the run when the report has any error-level issue.
+
+The --report json flag emits a run report (sections copied, skipped and failed,
+with durations and the files written) to stdout or --report-file.
+
+On a terminal, a progress line below the logs shows the sections copied out of
+the total, the section in progress, and the elapsed time and an ETA.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			outputDir, _ := cmd.Flags().GetString("output-dir")
 			mode, _ := cmd.Flags().GetString("mode")
 			transform, _ := cmd.Flags().GetString("transform")
+			profile, _ := cmd.Flags().GetString("profile")
 
-			agg := aggregator.New(getLogger())
-			return agg.Aggregate(outputDir, mode, transform)
+			rec, err := reports.recorder("aggregate")
+			if err != nil {
+				return err
+			}
+			ctx, cancel := withTimeout(cmd, timeout)
+			defer cancel()
+			p, stopProgress := startProgress("Aggregating")
+			err = docgen.Aggregate(ctx, outputDir, docgen.AggregateOptions{
+				Mode:       mode,
+				Transform:  transform,
+				Profile:    profile,
+				Audiences:  audiences,
+				Packages:   packages,
+				Categories: categories,
+				Strict:     strict,
+				Logger:     getLogger(),
+				Report:     rec,
+				Progress:   p,
+			})
+			stopProgress()
+			return reports.finish(rec, err)
 		},
 	}
 	cmd.Flags().StringP("output-dir", "o", "dist", "Directory to save the aggregated documentation")
 	cmd.Flags().StringP("mode", "m", defaultMode, "Aggregation mode: 'dev' (all statuses) or 'prod' (production only)")
+	cmd.Flags().String("profile", "internal", "Output profile: 'internal' (everything) or 'public' (internal content stripped and scrubbed)")
+	cmd.Flags().StringSliceVar(&audiences, "audience", nil, "Publish only sections for these audiences (comma-separated) and those without one")
 	cmd.Flags().String("transform", "", "Apply transformations to output (e.g., 'astro' for website builds)")
+	cmd.Flags().StringSliceVar(&packages, "packages", nil, "Rebuild only these packages (comma-separated) and patch their manifest entries")
+	cmd.Flags().StringSliceVar(&categories, "category", nil, "Rebuild only packages in these categories and patch their manifest entries")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort after this long, e.g. 10m (0 means no limit)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail when the validation report has errors (placeholders, missing sections, broken links or assets)")
+	addReportFlags(cmd, &reports)
 	return cmd
 }
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/progress"
+)
+
+// terminalAnswers returns the function that asks the user the questions
+// sections ask with settings.questions, when stdin and stderr are terminals
+// and logs are text. Elsewhere it returns nil and unanswered questions go to
+// answers.yml. The progress line p is hidden while a question waits.
+func terminalAnswers(p *progress.Tracker) generator.AnswerFunc {
+	if clilog.Current().Format != clilog.FormatText || !progress.IsTerminal(os.Stdin) || !progress.IsTerminal(os.Stderr) {
+		return nil
+	}
+	var mu sync.Mutex // Packages of --all generate concurrently
+	in := bufio.NewReader(os.Stdin)
+	return func(section, question string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		p.Pause()
+		defer p.Resume()
+		fmt.Fprintf(os.Stderr, "\n%s asks: %s\nAnswer (empty to answer later in answers.yml): ", section, question)
+		line, err := in.ReadString('\n')
+		if err != nil && line == "" {
+			return "", nil
+		}
+		return strings.TrimSpace(line), nil
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/credentials"
+	"github.com/grovetools/docgen/pkg/progress"
+	"github.com/spf13/cobra"
+)
+
+func newAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the API keys of LLM providers",
+		Long: `Stores the API keys of LLM providers (anthropic, gemini, openai) in the OS
+keychain, or in an age-encrypted file when there is none, instead of
+environment variables.
+
+Generation passes the stored keys to grove llm and the native Anthropic
+client. An API key variable in the environment (such as OPENAI_API_KEY)
+takes precedence over a stored key, so CI keeps working unchanged.
+
+Keys are stored per profile. A package or ecosystem selects its profile with
+settings.credentials in docgen.config.yml; keys the profile does not have
+come from the default profile.
+
+The store is the macOS keychain (security), the Secret Service on Linux
+(secret-tool), or else credentials.age in the docgen config directory,
+encrypted with age to an identity generated next to it. Set
+DOCGEN_CREDENTIALS=keychain or file to choose, and DOCGEN_CREDENTIALS_DIR to
+move the file.`,
+	}
+
+	cmd.AddCommand(newAuthLoginCmd())
+	cmd.AddCommand(newAuthLogoutCmd())
+	cmd.AddCommand(newAuthStatusCmd())
+	return cmd
+}
+
+func newAuthLoginCmd() *cobra.Command {
+	var provider, profile, store string
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Store the API key of a provider",
+		Long: `Reads the API key of a provider from stdin, without echoing it on a
+terminal, and stores it.
+
+Examples:
+  docgen auth login --provider openai
+  docgen auth login --provider anthropic --profile grovetools
+  pass show openai | docgen auth login --provider openai --store file`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := credentials.LookupProvider(provider)
+			if err != nil {
+				return err
+			}
+			s, err := credentials.Open(store)
+			if err != nil {
+				return err
+			}
+			key, err := readSecret(fmt.Sprintf("%s API key: ", p.Name))
+			if err != nil {
+				return err
+			}
+			if key == "" {
+				return fmt.Errorf("no API key given")
+			}
+			if err := s.Set(credentials.Account(profile, p.Name), key); err != nil {
+				return err
+			}
+			ulog.Success("Stored API key").
+				Field("provider", p.Name).
+				Field("profile", profileName(profile)).
+				Field("store", s.Name()).
+				Emit()
+			if v := p.EnvKey(); v != "" {
+				ulog.Warn("The environment sets the key, which takes precedence over the stored one").
+					Field("variable", v).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&provider, "provider", "", "Provider: anthropic, gemini or openai")
+	cmd.Flags().StringVar(&profile, "profile", credentials.DefaultProfile, "Credentials profile (see settings.credentials)")
+	cmd.Flags().StringVar(&store, "store", credentials.BackendAuto, "Store: auto, keychain or file")
+	_ = cmd.MarkFlagRequired("provider")
+	return cmd
+}
+
+func newAuthLogoutCmd() *cobra.Command {
+	var provider, profile, store string
+
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored API key of a provider",
+		Long: `Removes the stored API key of a provider from a profile.
+
+Examples:
+  docgen auth logout --provider openai
+  docgen auth logout --provider anthropic --profile grovetools`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := credentials.LookupProvider(provider)
+			if err != nil {
+				return err
+			}
+			s, err := credentials.Open(store)
+			if err != nil {
+				return err
+			}
+			err = s.Delete(credentials.Account(profile, p.Name))
+			if errors.Is(err, credentials.ErrNotFound) {
+				return fmt.Errorf("no %s key stored in profile %s", p.Name, profileName(profile))
+			}
+			if err != nil {
+				return err
+			}
+			ulog.Success("Removed API key").
+				Field("provider", p.Name).
+				Field("profile", profileName(profile)).
+				Field("store", s.Name()).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&provider, "provider", "", "Provider: anthropic, gemini or openai")
+	cmd.Flags().StringVar(&profile, "profile", credentials.DefaultProfile, "Credentials profile")
+	cmd.Flags().StringVar(&store, "store", credentials.BackendAuto, "Store: auto, keychain or file")
+	_ = cmd.MarkFlagRequired("provider")
+	return cmd
+}
+
+func newAuthStatusCmd() *cobra.Command {
+	var profile, store string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show where the key of each provider comes from",
+		Long: `Shows, for each provider, whether generation with the profile gets its API
+key from the environment, the profile, the default profile, or nowhere.
+Keys are never printed.
+
+Examples:
+  docgen auth status
+  docgen auth status --profile grovetools`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := credentials.Open(store)
+			if err != nil {
+				return err
+			}
+			for _, p := range credentials.Providers {
+				source := "not set"
+				if v := p.EnvKey(); v != "" {
+					source = "environment (" + v + ")"
+				} else if _, err := s.Get(credentials.Account(profile, p.Name)); err == nil {
+					source = "profile " + profileName(profile)
+				} else if !errors.Is(err, credentials.ErrNotFound) {
+					return err
+				} else if _, err := s.Get(p.Name); err == nil {
+					source = "profile " + credentials.DefaultProfile
+				} else if !errors.Is(err, credentials.ErrNotFound) {
+					return err
+				}
+				ulog.Info(p.Name).
+					Field("key", source).
+					Emit()
+			}
+			ulog.Info("Credential store").
+				Field("store", s.Name()).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", credentials.DefaultProfile, "Credentials profile")
+	cmd.Flags().StringVar(&store, "store", credentials.BackendAuto, "Store: auto, keychain or file")
+	return cmd
+}
+
+func profileName(profile string) string {
+	if profile == "" {
+		return credentials.DefaultProfile
+	}
+	return profile
+}
+
+// readSecret reads one line from stdin. On a terminal it prompts on stderr
+// and turns off echo while the line is typed.
+func readSecret(prompt string) (string, error) {
+	if progress.IsTerminal(os.Stdin) {
+		fmt.Fprint(os.Stderr, prompt)
+		if echo(false) == nil {
+			defer func() {
+				_ = echo(true)
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read the API key: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// echo turns terminal echo on or off.
+func echo(on bool) error {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"time"
 
 	"github.com/grovetools/docgen/pkg/capture"
 	"github.com/spf13/cobra"
@@ -12,6 +15,8 @@ func newCaptureCmd() *cobra.Command {
 	var output string
 	var depth int
 	var format string
+	var timeout time.Duration
+	var cobraJSON bool
 
 	cmd := &cobra.Command{
 		Use:   "capture <binary>",
@@ -19,7 +24,14 @@ func newCaptureCmd() *cobra.Command {
 		Long: `Recursively executes a binary with --help to capture and compile a complete command reference.
 
 This is useful for generating documentation for CLI tools that use Cobra or similar frameworks.
-It parses the "COMMANDS" section of the help output to discover subcommands.
+It parses the "COMMANDS" section of the help output ("Commands:", "Available Commands:",
+"Subcommands:" and similar) to discover subcommands. With --cobra-json the tool is asked
+instead: a hidden "docs json" command printing its command tree, or cobra's __complete,
+with help parsing as the fallback.
+
+On Windows, <binary> resolves through PATHEXT (so "grove" finds grove.exe), help falls
+back to -h and /? when --help prints nothing, and CRLF output is normalized. PowerShell
+scripts (.ps1) run through pwsh or powershell and are asked for help with -?.
 
 Output formats:
   markdown  Plain text in markdown code blocks (default)
@@ -34,8 +46,8 @@ Examples:
 			binary := args[0]
 
 			// Verify binary exists
-			if _, err := exec.LookPath(binary); err != nil {
-				return fmt.Errorf("binary '%s' not found in PATH", binary)
+			if _, err := capture.Resolve(binary); err != nil {
+				return err
 			}
 
 			// Determine format
@@ -61,11 +73,14 @@ Examples:
 
 			capturer := capture.New(getLogger())
 			opts := capture.Options{
-				MaxDepth: depth,
-				Format:   captureFormat,
+				MaxDepth:  depth,
+				Format:    captureFormat,
+				CobraJSON: cobraJSON,
 			}
 
-			if err := capturer.Capture(binary, output, opts); err != nil {
+			ctx, cancel := withTimeout(cmd, timeout)
+			defer cancel()
+			if err := capturer.CaptureContext(ctx, binary, output, opts); err != nil {
 				return err
 			}
 
@@ -80,6 +95,100 @@ Examples:
 	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default: commands.md or commands.html)")
 	cmd.Flags().IntVarP(&depth, "depth", "d", 5, "Maximum recursion depth")
 	cmd.Flags().StringVarP(&format, "format", "f", "markdown", "Output format: markdown, html")
+	cmd.Flags().BoolVar(&cobraJSON, "cobra-json", false, "Discover subcommands from the tool (docs json or __complete) instead of parsing help text")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort after this long, e.g. 2m (0 means no limit)")
+
+	cmd.AddCommand(newCaptureTUICmd())
+
+	return cmd
+}
+
+func newCaptureTUICmd() *cobra.Command {
+	var (
+		imagesDir string
+		name      string
+		format    string
+		cols      int
+		rows      int
+		wait      time.Duration
+		keys      []string
+		timeout   time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tui <command> [args...]",
+		Short: "Capture a screenshot of an interactive terminal UI",
+		Long: `Runs a TUI in a pseudo-terminal, waits for its screen to settle, and saves
+the frame it drew, complementing the help-text capture for interactive tools.
+
+The frame is saved as SVG images in light and dark variants
+(<name>-light.svg and <name>-dark.svg), or with --format html as a terminal
+block using the same term-* classes as "capture --format html", so the
+website's terminal theme applies.
+
+Keys are sent after the screen first settles, each followed by another wait:
+names (enter, tab, esc, space, backspace, up, down, left, right, home, end,
+pgup, pgdown), ctrl+<letter>, or literal text. Put -- before the command when
+it has flags of its own.
+
+Examples:
+  docgen capture tui flow tui
+  docgen capture tui --keys down,down,enter --name nb-browse -- nb tui --all
+  docgen capture tui --format html --images-dir docs/images hooks tui`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := exec.LookPath(args[0]); err != nil {
+				return fmt.Errorf("binary '%s' not found in PATH", args[0])
+			}
+			if format != "svg" && format != "html" {
+				return fmt.Errorf("unsupported format %q (supported: svg, html)", format)
+			}
+			if name == "" {
+				name = filepath.Base(args[0]) + "-tui"
+			}
+
+			ctx, cancel := withTimeout(cmd, timeout)
+			defer cancel()
+			frame, err := capture.New(getLogger()).CaptureTUI(ctx, args, capture.TUIOptions{
+				Cols: cols,
+				Rows: rows,
+				Wait: wait,
+				Keys: keys,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(imagesDir, 0o755); err != nil { //nolint:gosec // internal doc tool
+				return fmt.Errorf("failed to create %s: %w", imagesDir, err)
+			}
+			files := map[string]string{}
+			if format == "html" {
+				files[filepath.Join(imagesDir, name+".html")] = frame.HTML()
+			} else {
+				for _, theme := range []capture.TerminalTheme{capture.LightTheme, capture.DarkTheme} {
+					files[filepath.Join(imagesDir, name+"-"+theme.Name+".svg")] = frame.SVG(theme)
+				}
+			}
+			for path, content := range files {
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil { //nolint:gosec // internal doc tool output
+					return fmt.Errorf("failed to write %s: %w", path, err)
+				}
+				ulog.Success("TUI frame captured").Field("file", path).Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&imagesDir, "images-dir", "images", "Directory the frame is saved to")
+	cmd.Flags().StringVar(&name, "name", "", "Base name of the saved files (default: <binary>-tui)")
+	cmd.Flags().StringVarP(&format, "format", "f", "svg", "Output format: svg (light and dark images) or html")
+	cmd.Flags().IntVar(&cols, "cols", 100, "Terminal width in columns")
+	cmd.Flags().IntVar(&rows, "rows", 30, "Terminal height in rows")
+	cmd.Flags().DurationVar(&wait, "wait", 5*time.Second, "Longest wait for the screen to settle, before and after each key")
+	cmd.Flags().StringSliceVar(&keys, "keys", nil, "Keys to send before capturing (e.g. down,down,enter)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort after this long, e.g. 2m (0 means no limit)")
 
 	return cmd
 }
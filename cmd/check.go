@@ -0,0 +1,22 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Verify generated docs are still in sync with their sources",
+		Long: `Check commands compare generated documentation against the sources it was
+built from and exit non-zero when they have drifted, so stale docs can be
+caught in CI.`,
+	}
+
+	cmd.AddCommand(newCheckKeymapsCmd())
+	cmd.AddCommand(newCheckExamplesCmd())
+	cmd.AddCommand(newCheckSnippetsCmd())
+	cmd.AddCommand(newCheckManifestCmd())
+	cmd.AddCommand(newCheckFrontmatterCmd())
+	cmd.AddCommand(newCheckReferencesCmd())
+
+	return cmd
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newCheckExamplesCmd() *cobra.Command {
+	var (
+		sections   []string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "examples",
+		Short: "Fail when Go examples in generated docs no longer compile",
+		Long: `Extracts the ` + "```go" + ` blocks from the sections' generated docs, wraps each
+into its own package in a temporary module that requires the current module
+through a replace directive, and runs go build and go vet over them. Exits
+non-zero when any section's examples no longer compile.
+
+Blocks that are complete files are compiled as-is; bare declarations get a
+package clause and bare statements are wrapped in a function. Missing imports
+of the standard library and the module's own packages are added. Mark a block
+` + "```go nocheck" + ` to skip it.
+
+Examples:
+  docgen check examples                # Check all sections
+  docgen check examples -s quickstart  # Check one section
+  docgen check examples --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			gen := newGenerator()
+			report, err := gen.CheckExamples(cwd, sections)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal example report: %w", err)
+				}
+				ulog.Info("Go examples").
+					Field("failing", len(report.Failing)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, r := range report.Results {
+					if r.OK {
+						continue
+					}
+					ulog.Warn("Example does not compile").
+						Field("section", r.Section).
+						Field("file", r.File).
+						Field("line", r.Line).
+						Field("errors", strings.Join(r.Errors, "; ")).
+						Emit()
+				}
+			}
+
+			if len(report.Failing) > 0 {
+				return fmt.Errorf("examples no longer compile in %d section(s): %s", len(report.Failing), strings.Join(report.Failing, ", "))
+			}
+			if !jsonOutput {
+				ulog.Success("Go examples compile").
+					Field("examples", len(report.Results)).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&sections, "section", "s", nil, "Check only the specified sections (by name)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the example report as JSON")
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/frontmatter"
+	"github.com/spf13/cobra"
+)
+
+func newCheckFrontmatterCmd() *cobra.Command {
+	var (
+		dir        string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "frontmatter",
+		Short: "Validate the frontmatter of aggregated docs against the website's schema",
+		Long: `Checks the frontmatter of every markdown file in an aggregate's output
+directory against the schema under 'frontmatter:' in the local
+docgen.config.yml, which mirrors the website's content collection schema.
+Exits non-zero when a required field is missing, a field has the wrong type
+or value, or (with strict: true) a field is not in the schema.
+
+Without a frontmatter schema, the fields written by --transform astro are
+checked, with title required.
+
+Examples:
+  docgen aggregate -o dist --transform astro && docgen check frontmatter -o dist
+  docgen check frontmatter -o website/src/content/docs --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema := frontmatter.DefaultSchema()
+			if cwd, err := os.Getwd(); err == nil {
+				if cfg, err := config.Load(cwd); err == nil && cfg.Frontmatter != nil {
+					schema = cfg.Frontmatter
+				}
+			}
+
+			problems, files, err := frontmatter.CheckDir(dir, schema)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(struct {
+					Files    int                   `json:"files"`
+					Problems []frontmatter.Problem `json:"problems"`
+				}{files, append([]frontmatter.Problem{}, problems...)}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal frontmatter check: %w", err)
+				}
+				ulog.Info("Frontmatter check").
+					Field("problems", len(problems)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, p := range problems {
+					ulog.Warn("Invalid frontmatter").
+						Field("file", p.File).
+						Field("field", p.Field).
+						Field("problem", p.Message).
+						Emit()
+				}
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("%d frontmatter problem(s) in %s", len(problems), dir)
+			}
+			if !jsonOutput {
+				ulog.Success("Frontmatter is valid").
+					Field("files", files).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "output-dir", "o", "dist", "Directory of aggregated docs to check")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the check as JSON")
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newCheckKeymapsCmd() *cobra.Command {
+	var (
+		sections   []string
+		showDiff   bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "keymaps",
+		Short: "Fail when TUI keymaps docs no longer match the keybinding registry",
+		Long: `Re-renders every tui_keymaps section from the current keybinding registry
+(registry_file, registry_cmd, or grove keys dump) and compares it with the
+generated doc on disk. Exits non-zero when any section is stale; run
+'docgen generate' to refresh it.
+
+Examples:
+  docgen check keymaps                 # Check all tui_keymaps sections
+  docgen check keymaps -s keybindings  # Check one section
+  docgen check keymaps --diff          # Show what changed`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			gen := newGenerator()
+			drifts, err := gen.CheckTUIKeymaps(cwd, sections)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(drifts, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal drift report: %w", err)
+				}
+				ulog.Info("Keymaps drift").
+					Field("stale", len(drifts)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, d := range drifts {
+					if d.Missing {
+						ulog.Warn("Keymaps doc has not been generated").
+							Field("section", d.Section).
+							Field("output", d.Output).
+							Emit()
+						continue
+					}
+					ulog.Warn("Keymaps doc is stale").
+						Field("section", d.Section).
+						Field("output", d.Output).
+						Field("added", d.Added).
+						Field("removed", d.Removed).
+						Emit()
+					if showDiff {
+						ulog.Info("Keymaps diff").
+							Field("section", d.Section).
+							PrettyOnly().
+							Pretty(d.Diff).
+							Emit()
+					}
+				}
+			}
+
+			if len(drifts) > 0 {
+				return fmt.Errorf("%d tui_keymaps file(s) out of date; run 'docgen generate' to refresh", len(drifts))
+			}
+			if !jsonOutput {
+				ulog.Success("TUI keymaps docs are up to date").Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&sections, "section", "s", nil, "Check only the specified sections (by name)")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "Show a unified diff for stale sections")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the drift report as JSON")
+
+	return cmd
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/grovetools/docgen/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+func newCheckManifestCmd() *cobra.Command {
+	var (
+		dir        string
+		since      string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Verify an aggregate's files against the checksums in its manifest",
+		Long: `Checks the manifest.json written by 'docgen aggregate' against its digest
+and every file it lists against its recorded sha256. Exits non-zero when the
+manifest was edited or a file is missing, truncated or changed.
+
+With --since, also lists the files added, changed and removed relative to a
+previous build's manifest, for incremental deploys.
+
+Examples:
+  docgen check manifest -o dist
+  docgen check manifest -o dist --since previous/manifest.json --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := manifest.Load(filepath.Join(dir, manifest.ManifestFile))
+			if err != nil {
+				return fmt.Errorf("failed to load manifest: %w", err)
+			}
+			problems, err := m.Verify(dir)
+			if err != nil {
+				return err
+			}
+
+			var diff *manifest.FileDiff
+			if since != "" {
+				prev, err := manifest.Load(since)
+				if err != nil {
+					return fmt.Errorf("failed to load previous manifest: %w", err)
+				}
+				d := m.DiffFiles(prev)
+				diff = &d
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(struct {
+					Files    int                `json:"files"`
+					Problems []string           `json:"problems"`
+					Diff     *manifest.FileDiff `json:"diff,omitempty"`
+				}{len(m.Files), append([]string{}, problems...), diff}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal manifest check: %w", err)
+				}
+				ulog.Info("Manifest check").
+					Field("problems", len(problems)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, p := range problems {
+					ulog.Warn("Integrity problem").Field("detail", p).Emit()
+				}
+				if diff != nil {
+					for _, p := range diff.Added {
+						ulog.Info("Added").Field("file", p).Emit()
+					}
+					for _, p := range diff.Changed {
+						ulog.Info("Changed").Field("file", p).Emit()
+					}
+					for _, p := range diff.Removed {
+						ulog.Info("Removed").Field("file", p).Emit()
+					}
+				}
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("%d integrity problem(s) in %s", len(problems), dir)
+			}
+			if !jsonOutput {
+				ulog.Success("Manifest verified").
+					Field("files", len(m.Files)).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "output-dir", "o", "dist", "Aggregate output directory holding manifest.json")
+	cmd.Flags().StringVar(&since, "since", "", "Previous build's manifest.json to list changed files against")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the check as JSON")
+
+	return cmd
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newCheckReferencesCmd() *cobra.Command {
+	var (
+		sections   []string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "references",
+		Short: "Fail when docs mention commands, flags or config keys that do not exist",
+		Long: `Lints the generated docs against the package's CLI and config surface: the
+command trees its capture sections crawl and the JSON schemas of its
+schema_to_md and schema_table sections. Command lines in shell code blocks
+and code spans are walked down the command tree, and config keys in YAML and
+TOML code blocks and dotted keys in code spans are looked up in the schemas.
+Exits non-zero when a doc mentions a subcommand, flag or config key that does
+not exist. Hidden commands are not in the captured help and are reported.
+
+'docgen generate' runs the same check on each section it writes and reports
+the findings as warnings (errors with --strict).
+
+Examples:
+  docgen check references              # Check all sections
+  docgen check references -s usage     # Check one section
+  docgen check references --json       # Output the lint report as JSON`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			gen := newGenerator()
+			issues, err := gen.CheckReferences(cwd, sections)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(issues, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal reference report: %w", err)
+				}
+				ulog.Info("Reference report").
+					Field("issues", len(issues)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, issue := range issues {
+					ulog.Warn(issue.Message).
+						Field("section", issue.Section).
+						Field("file", issue.File).
+						Field("line", issue.Line).
+						Field("rule", issue.Rule).
+						Emit()
+				}
+			}
+
+			if len(issues) > 0 {
+				return fmt.Errorf("%d reference(s) to commands, flags or config keys that do not exist", len(issues))
+			}
+			if !jsonOutput {
+				ulog.Success("Docs reference only existing commands, flags and config keys").Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&sections, "section", "s", nil, "Check only the specified sections (by name)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the lint report as JSON")
+
+	return cmd
+}
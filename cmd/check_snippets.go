@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newCheckSnippetsCmd() *cobra.Command {
+	var (
+		sections   []string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "snippets",
+		Short: "Fail when prompts or docs reference snippets that no longer exist",
+		Long: `Scans the package's sources for regions marked with docgen:snippet:<name> ...
+docgen:snippet:end comments, then checks every {{snippet name}} reference in
+the sections' prompts and generated docs. Exits non-zero when a referenced
+snippet was removed or renamed.
+
+Examples:
+  docgen check snippets                  # Check all sections
+  docgen check snippets -s introduction  # Check one section
+  docgen check snippets --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			gen := newGenerator()
+			report, err := gen.CheckSnippets(cwd, sections)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal snippet report: %w", err)
+				}
+				ulog.Info("Snippet references").
+					Field("missing", len(report.Missing)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, ref := range report.Missing {
+					ulog.Warn("Snippet not found").
+						Field("snippet", ref.Name).
+						Field("file", ref.File).
+						Field("line", ref.Line).
+						Emit()
+				}
+			}
+
+			if len(report.Missing) > 0 {
+				return fmt.Errorf("%d snippet reference(s) point to snippets that no longer exist", len(report.Missing))
+			}
+			if !jsonOutput {
+				ulog.Success("Snippet references are valid").
+					Field("snippets", report.Snippets).
+					Field("references", len(report.Refs)).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&sections, "section", "s", nil, "Check only the specified sections (by name)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the snippet report as JSON")
+
+	return cmd
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/ci"
+	"github.com/spf13/cobra"
+)
+
+func newCICmd() *cobra.Command {
+	defaultMode := os.Getenv("DOCGEN_MODE")
+	if defaultMode == "" {
+		defaultMode = "dev"
+	}
+
+	var (
+		opts        ci.Options
+		annotations bool
+		summaryFile string
+		jsonOutput  bool
+		timeout     time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ci",
+		Short: "Validate, generate, aggregate and check docs in one CI run",
+		Long: `Runs docgen's checks as one pipeline, for pull requests:
+
+  1. validate     the docgen config loads, its sections have outputs and
+                  prompts, and its context rules resolve
+  2. generate     replay (default): call no LLM and report docs that are
+                  missing or older than their prompt or config;
+                  live: regenerate the docs
+  3. aggregate    build the site into --output-dir, reporting placeholders,
+                  missing sections, broken links and missing assets
+  4. frontmatter  check the aggregated docs' frontmatter
+
+Generate is skipped when validation fails, and the frontmatter check when
+aggregate fails; the other steps always run, so one run reports every
+problem. Each problem is printed as a GitHub Actions annotation
+(::error file=...::message) when running in GitHub Actions, or with
+--annotations, and the run is appended to the job summary
+($GITHUB_STEP_SUMMARY, or --summary-file) as a markdown table.
+
+Exits non-zero when a step has errors, or, with --strict, warnings.
+
+Examples:
+  docgen ci                                # Replay: check the committed docs
+  docgen ci --generate live --only-stale   # Regenerate stale docs first
+  docgen ci --all -m prod --transform astro -o dist
+  docgen ci --skip-aggregate --strict      # Just this package, warnings fail`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if opts.Root == "" {
+				opts.Root = os.Getenv("GITHUB_WORKSPACE")
+			}
+			opts.Logger = getLogger()
+			opts.UnifiedLog = true
+
+			ctx, cancel := withTimeout(cmd, timeout)
+			defer cancel()
+			result, runErr := ci.Run(ctx, cwd, opts)
+			if result == nil {
+				return runErr
+			}
+
+			for _, a := range result.Annotations {
+				switch {
+				case annotations:
+					fmt.Println(a.Command())
+				case a.Level == ci.LevelError:
+					ulog.Error(a.Title).Field("step", a.Step).Field("file", a.File).Field("problem", a.Message).Emit()
+				default:
+					ulog.Warn(a.Title).Field("step", a.Step).Field("file", a.File).Field("problem", a.Message).Emit()
+				}
+			}
+
+			if summaryFile != "" {
+				if err := appendSummary(summaryFile, result.Summary()); err != nil {
+					return err
+				}
+			}
+			if jsonOutput {
+				data, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal ci result: %w", err)
+				}
+				ulog.Info("CI").
+					Field("failed", result.Failed()).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, s := range result.Steps {
+					entry := ulog.Info(s.Name)
+					if s.Status == ci.StepPassed {
+						entry = ulog.Success(s.Name)
+					} else if s.Status == ci.StepFailed {
+						entry = ulog.Error(s.Name)
+					}
+					entry.Field("status", s.Status).
+						Field("errors", s.Errors).
+						Field("warnings", s.Warnings).
+						Field("detail", s.Detail).
+						Emit()
+				}
+			}
+
+			if runErr != nil {
+				return runErr
+			}
+			if result.Failed() {
+				var failed []string
+				for _, s := range result.Steps {
+					if s.Status == ci.StepFailed {
+						failed = append(failed, s.Name)
+					}
+				}
+				return fmt.Errorf("ci failed in %s", strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Validate and generate every docgen-enabled package of the configured ecosystems")
+	cmd.Flags().StringVar(&opts.Generate, "generate", ci.GenerateReplay, "Generate step: 'replay' (check the committed docs; no LLM calls) or 'live'")
+	cmd.Flags().BoolVar(&opts.OnlyStale, "only-stale", false, "With --generate live, regenerate only stale sections")
+	cmd.Flags().StringVar(&opts.Model, "model", "", "With --generate live, override the model for all sections")
+	cmd.Flags().StringVarP(&opts.OutputDir, "output-dir", "o", "dist", "Directory to aggregate the documentation into")
+	cmd.Flags().StringVarP(&opts.Mode, "mode", "m", defaultMode, "Aggregation mode: 'dev' (all statuses) or 'prod' (production only)")
+	cmd.Flags().StringVar(&opts.Transform, "transform", "", "Apply transformations to the aggregated output (e.g., 'astro')")
+	cmd.Flags().BoolVar(&opts.SkipAggregate, "skip-aggregate", false, "Skip aggregate and the checks of its output")
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "Fail on warnings too (stale docs, placeholders, generation warnings)")
+	cmd.Flags().StringVar(&opts.Root, "root", "", "Directory annotation paths are relative to (default $GITHUB_WORKSPACE, else the current directory)")
+	cmd.Flags().BoolVar(&annotations, "annotations", os.Getenv("GITHUB_ACTIONS") == "true", "Print problems as GitHub Actions annotations (default when running in GitHub Actions)")
+	cmd.Flags().StringVar(&summaryFile, "summary-file", os.Getenv("GITHUB_STEP_SUMMARY"), "Append a markdown summary of the run to this file (default $GITHUB_STEP_SUMMARY)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the run as JSON")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort the run after this long, e.g. 30m (0 means no limit)")
+
+	return cmd
+}
+
+// appendSummary appends markdown to a job summary file, which other steps
+// of the job may also write to.
+func appendSummary(path, markdown string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gosec // CI job summary
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	if _, err := f.WriteString(markdown + "\n"); err != nil {
+		f.Close() //nolint:errcheck // the write error is returned
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Generate the shell completion script",
+		Long: `Prints a completion script for the given shell. Besides commands and flags it
+completes section names for --section from the package's docgen.config.yml and
+workspace names for --package.
+
+Examples:
+  # bash (add to ~/.bashrc)
+  source <(docgen completion bash)
+
+  # zsh (add to ~/.zshrc, after compinit)
+  source <(docgen completion zsh)
+
+  # fish
+  docgen completion fish > ~/.config/fish/completions/docgen.fish`,
+		Args:                  cobra.ExactArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletionV2(out, true)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(out)
+			case "fish":
+				return cmd.Root().GenFishCompletion(out, true)
+			case "powershell":
+				return cmd.Root().GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("unsupported shell %q: use bash, zsh, fish or powershell", args[0])
+		},
+	}
+	return cmd
+}
+
+// registerCompletions attaches name completion to every --section, --package
+// and --packages flag in the command tree, so flags added later complete too.
+func registerCompletions(cmd *cobra.Command) {
+	completions := map[string]func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective){
+		"section":  completeSections,
+		"package":  completePackages,
+		"packages": completePackages,
+	}
+	for name, fn := range completions {
+		if cmd.Flags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, fn) //nolint:errcheck // only fails when already registered
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
+
+// completeSections completes section names from the docgen config of the
+// package given with --package, or of the current directory.
+func completeSections(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	pkg, _ := cmd.Flags().GetString("package")
+	packageDir, err := resolvePackageDir(pkg)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	targets, err := generator.ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	candidates := make(map[string]string, len(targets))
+	for _, t := range targets {
+		candidates[t.Name] = t.Section.Title
+	}
+	return completeList(candidates, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePackages completes the names of discovered workspaces.
+func completePackages(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	projects, err := workspace.GetProjects(getLogger())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	candidates := make(map[string]string, len(projects))
+	for _, p := range projects {
+		candidates[p.Name] = ""
+	}
+	return completeList(candidates, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeList returns the sorted candidates matching toComplete, with their
+// descriptions. For comma-separated slice flags only the last element is
+// completed and the earlier ones are kept as a prefix.
+func completeList(candidates map[string]string, toComplete string) []string {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+	var out []string
+	for name, desc := range candidates {
+		if !strings.HasPrefix(name, toComplete) {
+			continue
+		}
+		if desc != "" {
+			out = append(out, prefix+name+"\t"+desc)
+		} else {
+			out = append(out, prefix+name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+func newConceptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "concept",
+		Short: "Reconcile published nb concept docs with their notebook source",
+		Long: `nb_concept sections copy notebook concept files into the docs output. docgen
+records a hash of each copy, and 'docgen generate' will not overwrite a copy
+that was edited after it was published.
+
+Use 'concept status' to see which copies diverged from their source.
+Use 'concept pull' to move edits from the published copies back into the notebook.
+Use 'concept push' to overwrite edited copies with the notebook source.`,
+	}
+
+	cmd.AddCommand(newConceptStatusCmd())
+	cmd.AddCommand(newConceptPullCmd())
+	cmd.AddCommand(newConceptPushCmd())
+
+	return cmd
+}
+
+// conceptSyncDirs finds the output directories holding concept sync state for
+// the package in the current directory: the notebook docgen directory in
+// notebook mode, otherwise the repo's output directory.
+func conceptSyncDirs() ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	cfg, configPath, err := config.LoadWithNotebook(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load docgen config: %w", err)
+	}
+
+	root := filepath.Dir(configPath)
+	if strings.HasPrefix(configPath, cwd) {
+		root = filepath.Join(cwd, "docs")
+		if cfg.Settings.OutputDir != "" {
+			root = filepath.Join(cwd, cfg.Settings.OutputDir)
+		}
+	}
+
+	dirs, err := generator.FindConceptSyncDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no published concept docs found under %s (run 'docgen generate' first)", root)
+	}
+	return dirs, nil
+}
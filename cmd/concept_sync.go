@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+func newConceptStatusCmd() *cobra.Command {
+	var (
+		opts       generator.ConceptSyncOptions
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status [files...]",
+		Short: "Show published concept docs that diverged from their source",
+		Long: `Compares each published concept doc against the hashes recorded when it was
+last generated and reports its state:
+
+  clean           unchanged on both sides
+  source-changed  the notebook concept changed; the next generate updates the copy
+  edited          the published copy was edited; pull to keep the edits
+  conflict        both sides changed since the last sync
+  missing         the source or the published copy no longer exists`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Paths = args
+			dirs, err := conceptSyncDirs()
+			if err != nil {
+				return err
+			}
+
+			var all []generator.ConceptFileStatus
+			for _, dir := range dirs {
+				statuses, err := generator.ConceptStatus(dir, opts)
+				if err != nil {
+					return err
+				}
+				all = append(all, statuses...)
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(all, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal status: %w", err)
+				}
+				ulog.Info("Concept sync status").
+					Field("files", len(all)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+				return nil
+			}
+
+			diverged := 0
+			for _, s := range all {
+				if s.State == generator.ConceptClean {
+					continue
+				}
+				diverged++
+				entry := ulog.Info(s.Path)
+				if s.State == generator.ConceptEdited || s.State == generator.ConceptConflict {
+					entry = ulog.Warn(s.Path)
+				}
+				entry.Field("state", s.State).Field("concept", s.Concept).Field("source", s.Source).Emit()
+			}
+			ulog.Success("Concept docs checked").
+				Field("files", len(all)).
+				Field("diverged", diverged).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.PublishedDir, "published-dir", "", "Compare against copies in this directory instead of the output directory")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output status as JSON")
+
+	return cmd
+}
+
+func newConceptPullCmd() *cobra.Command {
+	var opts generator.ConceptSyncOptions
+
+	cmd := &cobra.Command{
+		Use:   "pull [files...]",
+		Short: "Copy edits from published concept docs back into the notebook",
+		Long: `Writes the body of each edited published concept doc back into its notebook
+concept file, keeping the concept's own frontmatter. Files changed on both sides
+are skipped unless --force is given, in which case the published copy wins.
+With --published-dir, the copies in the output directory are updated as well.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Paths = args
+			return runConceptSync(opts, generator.PullConcepts, "Pulled concept doc")
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.PublishedDir, "published-dir", "", "Pull from copies in this directory instead of the output directory")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Overwrite notebook changes when both sides were edited")
+
+	return cmd
+}
+
+func newConceptPushCmd() *cobra.Command {
+	var opts generator.ConceptSyncOptions
+
+	cmd := &cobra.Command{
+		Use:   "push [files...]",
+		Short: "Overwrite published concept docs with the notebook source",
+		Long: `Rewrites published concept docs from their notebook concept files, discarding
+edits made to the published copies. Files changed on both sides are skipped
+unless --force is given, in which case the notebook source wins. With
+--published-dir, the copies in the output directory are rewritten as well.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Paths = args
+			return runConceptSync(opts, generator.PushConcepts, "Pushed concept doc")
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.PublishedDir, "published-dir", "", "Push to copies in this directory instead of the output directory")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Overwrite published edits when both sides were edited")
+
+	return cmd
+}
+
+func runConceptSync(opts generator.ConceptSyncOptions, sync func(string, generator.ConceptSyncOptions) ([]generator.ConceptFileStatus, error), message string) error {
+	dirs, err := conceptSyncDirs()
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, dir := range dirs {
+		synced, err := sync(dir, opts)
+		for _, s := range synced {
+			ulog.Success(message).
+				Field("file", s.Path).
+				Field("source", s.Source).
+				Emit()
+		}
+		total += len(synced)
+		if err != nil {
+			return err
+		}
+	}
+
+	if total == 0 {
+		ulog.Info("Nothing to sync").Emit()
+	}
+	return nil
+}
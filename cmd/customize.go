@@ -12,6 +12,7 @@ import (
 	"github.com/grovetools/core/pkg/workspace"
 	"github.com/grovetools/core/util/delegation"
 	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/generator"
 	"github.com/grovetools/docgen/pkg/recipes"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -33,6 +34,8 @@ This command:
 Available recipe types:
 - agent: Uses AI agents for interactive customization (default)
 - prompts: Uses structured prompts for customization
+- sections: One job per configured section, each running docgen generate
+  for it, then a review job to approve the docs or send sections back
 
 The resulting flow plan will have jobs specific to the chosen recipe type.
 
@@ -44,6 +47,7 @@ Examples:
   docgen customize                        # Create plan with default agent recipe
   docgen customize --recipe-type agent    # Create plan with agent recipe
   docgen customize --recipe-type prompts  # Create plan with prompts recipe
+  docgen customize --recipe-type sections # Create plan with a job per section
   flow run                                # Run the plan after creation`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Check if this is the print-recipes subcommand
@@ -73,11 +77,20 @@ Examples:
 				recipeName = "docgen-customize-agent"
 			case "prompts":
 				recipeName = "docgen-customize-prompts"
+			case "sections":
+				recipeName = recipes.SectionsRecipeName
+				targets, err := generator.ResolveSectionTargets(cwd)
+				if err != nil {
+					return err
+				}
+				if _, err := recipes.SectionsRecipe(targets); err != nil {
+					return fmt.Errorf("cannot build the %s recipe: %w", recipeName, err)
+				}
 			default:
 				ulog.Error("Invalid recipe type").
 					Field("recipe_type", recipeType).
 					Emit()
-				ulog.Info("Valid options are: agent, prompts").Emit()
+				ulog.Info("Valid options are: agent, prompts, sections").Emit()
 				return fmt.Errorf("invalid recipe type: %s", recipeType)
 			}
 
@@ -152,12 +165,18 @@ Examples:
 				Pretty("\nNext steps:\n  1. Run 'flow run' to start the customization process").
 				Emit()
 
-			if recipeType == "agent" {
+			switch recipeType {
+			case "sections":
+				ulog.Info("Sections generation").
+					PrettyOnly().
+					Pretty("  2. Each section is generated by its own job\n  3. The review job lists the sections to check; regenerate any that need another pass before approving").
+					Emit()
+			case "agent":
 				ulog.Info("Agent customization").
 					PrettyOnly().
 					Pretty("  2. The agent will interactively help you customize and generate documentation").
 					Emit()
-			} else {
+			default:
 				ulog.Info("Prompts customization").
 					PrettyOnly().
 					Pretty("  2. Follow the prompts to customize your documentation structure\n  3. The generation job will create documentation based on your customizations").
@@ -169,7 +188,7 @@ Examples:
 	}
 
 	// Add flags
-	cmd.Flags().StringVarP(&recipeType, "recipe-type", "r", "agent", "Recipe type to use: 'agent' or 'prompts'")
+	cmd.Flags().StringVarP(&recipeType, "recipe-type", "r", "agent", "Recipe type to use: 'agent', 'prompts' or 'sections'")
 
 	return cmd
 }
@@ -212,6 +231,8 @@ func printRecipes() error {
 	}
 	collection["docgen-customize-prompts"] = promptsRecipe
 
+	addSectionsRecipe(collection)
+
 	// Output as JSON
 	jsonData, err := json.MarshalIndent(collection, "", "  ")
 	if err != nil {
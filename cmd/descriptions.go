@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newDescriptionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "descriptions",
+		Short: "Manage stored LLM descriptions",
+		Long: `tui_describe, schema_describe, and capture sections (with 'descriptions' set)
+keep their LLM-generated descriptions in a descriptions store: a JSON file of
+entries keyed by stable IDs such as "tui:flow", "cli:grove flow run", or
+"schema:settings.model".
+
+Each entry records a hash of the registry entry, command help, or property it
+was generated from. 'docgen generate' only asks the LLM for entries that are
+missing or whose source changed; use 'descriptions refresh' to regenerate
+entries on demand.`,
+	}
+
+	cmd.AddCommand(newDescriptionsRefreshCmd())
+
+	return cmd
+}
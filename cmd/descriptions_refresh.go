@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newDescriptionsRefreshCmd() *cobra.Command {
+	var (
+		only       []string
+		sections   []string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Regenerate stored LLM descriptions",
+		Long: `Invalidates entries in the descriptions stores of the current package and
+regenerates them with the LLM. Without --only every entry is regenerated.
+
+--only takes store IDs or globs. A pattern without a kind prefix matches the
+key in any kind.
+
+Examples:
+  docgen descriptions refresh                          # Regenerate everything
+  docgen descriptions refresh --only tui:flow          # One TUI
+  docgen descriptions refresh --only 'schema:settings.*'
+  docgen descriptions refresh -s tui-descriptions      # One section`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+
+			gen := newGenerator()
+			results, err := gen.RefreshDescriptions(cwd, sections, only)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal refresh results: %w", err)
+				}
+				ulog.Info("Refreshed descriptions").
+					Field("sections", len(results)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+				return nil
+			}
+
+			for _, r := range results {
+				ulog.Success("Refreshed descriptions").
+					Field("section", r.Section).
+					Field("store", r.Store).
+					Field("entries", len(r.Invalidated)).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Regenerate only these entry IDs or globs (e.g. tui:flow)")
+	cmd.Flags().StringSliceVarP(&sections, "section", "s", nil, "Refresh only the specified sections (by name)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the refresh results as JSON")
+
+	return cmd
+}
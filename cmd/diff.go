@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/internal/fsutil"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// prCommentMarker starts every PR comment, so a workflow can find and
+	// update its previous comment instead of adding another.
+	prCommentMarker = "<!-- docgen-diff -->"
+	// prCommentMaxDiffLines caps each file's diff in a PR comment.
+	prCommentMaxDiffLines = 300
+	// prCommentMaxBytes keeps a PR comment under GitHub's 65536-character
+	// limit; diffs past it are left out.
+	prCommentMaxBytes = 60000
+)
+
+func newDiffCmd() *cobra.Command {
+	var (
+		base     string
+		format   string
+		buildCmd string
+		sections []string
+		output   string
+		exitCode bool
+		timeout  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show how a code change affects the generated reference docs",
+		Long: `Regenerates the deterministic sections (capture, schema_to_md and
+tui_keymaps) from a temporary worktree of --base and from the working tree,
+and diffs the results, to show the documentation impact of a code change
+without calling an LLM or touching the docs on disk.
+
+schema_to_md sections are rendered as deterministic tables whatever their
+llm setting. capture sections run the package's binary, so they need
+--build-cmd, run in both trees, to build it into bin/; without it they are
+skipped. tui_keymaps sections differ only when their registry comes from the
+tree (registry_file or registry_cmd), not from the installed grove.
+
+--format pr-comment renders a markdown comment for the pull request: a table
+of the sections and a collapsed diff per changed file. It starts with the
+marker <!-- docgen-diff --> so a workflow can update its previous comment.
+
+Examples:
+  docgen diff                                      # Against origin/main
+  docgen diff --base main --build-cmd "make build"
+  docgen diff --format pr-comment -O comment.md && gh pr comment --body-file comment.md
+  docgen diff --exit-code                          # Exit 1 when the docs change`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "text", "pr-comment", "json":
+			default:
+				return fmt.Errorf("invalid --format %q: must be 'text', 'pr-comment' or 'json'", format)
+			}
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := withTimeout(cmd, timeout)
+			defer cancel()
+			d, err := newGenerator().DiffDocs(ctx, cwd, generator.DocDiffOptions{
+				Base:     base,
+				BuildCmd: buildCmd,
+				Sections: sections,
+			})
+			if err != nil {
+				return err
+			}
+
+			var body string
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(d, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal doc diff: %w", err)
+				}
+				body = string(data)
+			case "pr-comment":
+				body = prComment(d)
+			}
+			switch {
+			case output != "":
+				if format == "text" {
+					body = textDiff(d)
+				}
+				if err := fsutil.WriteFileAtomic(output, []byte(body), 0o644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", output, err)
+				}
+				ulog.Success("Wrote doc diff").Field("path", output).Field("changed", d.Changed()).Emit()
+			case format == "text":
+				emitTextDiff(d)
+			default:
+				ulog.Info("Doc diff").
+					Field("changed", d.Changed()).
+					PrettyOnly().
+					Pretty(body).
+					Emit()
+			}
+
+			if exitCode && d.Changed() > 0 {
+				return fmt.Errorf("%d section(s) of generated docs change against %s", d.Changed(), base)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&base, "base", "origin/main", "Git ref to compare the working tree against")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: 'text', 'pr-comment' (markdown) or 'json'")
+	cmd.Flags().StringVar(&buildCmd, "build-cmd", "", "Shell command building the package's binaries into bin/, run in both trees (needed for capture sections)")
+	cmd.Flags().StringSliceVarP(&sections, "section", "s", nil, "Diff only these sections (by name)")
+	cmd.Flags().StringVarP(&output, "output", "O", "", "Write the diff to this file instead of stdout")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit non-zero when any section changes")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort after this long, e.g. 10m (0 means no limit)")
+
+	return cmd
+}
+
+// emitTextDiff prints each section's outcome and the diffs of its files.
+func emitTextDiff(d *generator.DocDiff) {
+	for _, s := range d.Sections {
+		switch {
+		case s.Skipped != "":
+			ulog.Info("Skipped").Field("section", s.Section).Field("reason", s.Skipped).Emit()
+		case s.Error != "":
+			ulog.Error("Could not render").Field("section", s.Section).Field("error", s.Error).Emit()
+		case !s.Changed():
+			ulog.Success("Unchanged").Field("section", s.Section).Emit()
+		default:
+			for _, f := range s.Files {
+				ulog.Warn("Changed").
+					Field("section", s.Section).
+					Field("file", f.Path).
+					Field("status", f.Status).
+					Field("added", f.Added).
+					Field("removed", f.Removed).
+					PrettyOnly().
+					Pretty(f.Diff).
+					Emit()
+			}
+		}
+	}
+	ulog.Info("Doc diff").
+		Field("base", fmt.Sprintf("%s (%s)", d.Base, d.BaseRev)).
+		Field("sections", len(d.Sections)).
+		Field("changed", d.Changed()).
+		Emit()
+}
+
+// textDiff renders the diff as plain text: the sections, then every diff.
+func textDiff(d *generator.DocDiff) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Generated docs against %s (%s): %d of %d section(s) changed\n", d.Base, d.BaseRev, d.Changed(), len(d.Sections))
+	for _, s := range d.Sections {
+		fmt.Fprintf(&sb, "  %s (%s): %s\n", s.Section, s.Type, sectionChange(s))
+	}
+	for _, s := range d.Sections {
+		for _, f := range s.Files {
+			sb.WriteString("\n" + f.Diff)
+		}
+	}
+	return sb.String()
+}
+
+// sectionChange summarizes a section's diff in a few words.
+func sectionChange(s generator.DocSectionDiff) string {
+	switch {
+	case s.Skipped != "":
+		return "skipped: " + s.Skipped
+	case s.Error != "":
+		return "could not render: " + firstLine(s.Error)
+	case !s.Changed():
+		return "no change"
+	}
+	added, removed := 0, 0
+	for _, f := range s.Files {
+		added += f.Added
+		removed += f.Removed
+	}
+	change := fmt.Sprintf("%d file(s), +%d / -%d", len(s.Files), added, removed)
+	if s.BaseError != "" {
+		change += " (new: not renderable at base)"
+	}
+	return change
+}
+
+// prComment renders the diff as a markdown pull request comment.
+func prComment(d *generator.DocDiff) string {
+	var sb strings.Builder
+	sb.WriteString(prCommentMarker + "\n### Documentation impact\n\n")
+	if d.Changed() == 0 {
+		fmt.Fprintf(&sb, "No changes to the %d generated reference section(s) compared with `%s` (`%s`).\n\n", len(d.Sections), d.Base, d.BaseRev)
+	} else {
+		fmt.Fprintf(&sb, "This change affects **%d** of %d generated reference section(s) compared with `%s` (`%s`).\n\n", d.Changed(), len(d.Sections), d.Base, d.BaseRev)
+	}
+	sb.WriteString("| Section | Type | Change |\n| :--- | :--- | :--- |\n")
+	for _, s := range d.Sections {
+		fmt.Fprintf(&sb, "| `%s` | %s | %s |\n", s.Section, s.Type, strings.ReplaceAll(sectionChange(s), "|", `\|`))
+	}
+
+	omitted := 0
+	for _, s := range d.Sections {
+		for _, f := range s.Files {
+			var block strings.Builder
+			fmt.Fprintf(&block, "\n<details>\n<summary><code>%s</code>: <code>%s</code> (%s, +%d / -%d)</summary>\n\n", s.Section, f.Path, f.Status, f.Added, f.Removed)
+			block.WriteString("```diff\n" + truncateLines(f.Diff, prCommentMaxDiffLines) + "```\n\n</details>\n")
+			if sb.Len()+block.Len() > prCommentMaxBytes {
+				omitted++
+				continue
+			}
+			sb.WriteString(block.String())
+		}
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&sb, "\n%d more diff(s) left out to fit the comment; run `docgen diff --base %s` to see them.\n", omitted, d.Base)
+	}
+	sb.WriteString("\n<sub>Generated by `docgen diff`.</sub>\n")
+	return sb.String()
+}
+
+// truncateLines keeps the first limit lines of s, noting how many were
+// cut. The result ends with a newline.
+func truncateLines(s string, limit int) string {
+	lines := strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n")
+	if len(lines) <= limit {
+		return strings.TrimSuffix(s, "\n") + "\n"
+	}
+	return strings.Join(lines[:limit], "") + fmt.Sprintf("... %d more line(s)\n", len(lines)-limit)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
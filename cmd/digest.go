@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+func newDigestCmd() *cobra.Command {
+	// Default mode from DOCGEN_MODE env var, fallback to "dev"
+	defaultMode := os.Getenv("DOCGEN_MODE")
+	if defaultMode == "" {
+		defaultMode = "dev"
+	}
+
+	var (
+		since  string
+		output string
+		opts   generator.DigestOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Summarize recent doc changes and releases into a \"What's new\" page",
+		Long: `Collects the sections whose generated docs were modified and the changelog
+releases dated within the --since window across all workspace packages, and
+asks the LLM to summarize them into a single "What's new" page.
+
+The page is written to whats-new.md in the docs of the website's overview
+section by default. Add a section with output: whats-new.md to the overview
+section's docgen.config.yml for aggregate to publish it.
+
+--since takes a number of days ("30d") or weeks ("2w"), a Go duration
+("72h") or a date ("2026-03-01"). Only sections published in --mode are
+considered.
+
+Examples:
+  docgen digest                         # Last 30 days
+  docgen digest --since 2w
+  docgen digest --since 2026-03-01 -o whats-new.md`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.CheckMode(opts.Mode); err != nil {
+				return err
+			}
+			start, err := parseSince(since, time.Now())
+			if err != nil {
+				return err
+			}
+			opts.Since = start
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+			gen := newGenerator()
+			if output == "" {
+				path, declared, err := gen.DigestPath(cwd)
+				if err != nil {
+					return err
+				}
+				if !declared {
+					ulog.Warn("The overview section does not declare the digest page; aggregate will not publish it").
+						Field("output", generator.DigestFile).
+						Emit()
+				}
+				output = path
+			}
+
+			page, err := gen.Digest(cwd, opts)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil { //nolint:gosec // docs directory
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+			if err := os.WriteFile(output, []byte(page), 0o644); err != nil { //nolint:gosec // internal doc tool output
+				return fmt.Errorf("failed to write digest: %w", err)
+			}
+
+			ulog.Success("Wrote digest").
+				Field("path", output).
+				Field("since", start.Format(time.DateOnly)).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", "Include changes since this long ago (30d, 2w, 72h) or this date (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write (default: whats-new.md in the website's overview section)")
+	cmd.Flags().StringVarP(&opts.Mode, "mode", "m", defaultMode, "Only include sections published in this mode: dev or prod (env: DOCGEN_MODE)")
+	cmd.Flags().StringVar(&opts.Model, "model", "", "LLM model to summarize with (default: settings.model)")
+
+	return cmd
+}
+
+// parseSince returns the start of a --since window ending at now.
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, since, now.Location()); err == nil {
+		return t, nil
+	}
+	if n := len(since); n > 1 && (since[n-1] == 'd' || since[n-1] == 'w') {
+		count, err := strconv.Atoi(since[:n-1])
+		if err == nil && count >= 0 {
+			if since[n-1] == 'w' {
+				count *= 7
+			}
+			return now.AddDate(0, 0, -count), nil
+		}
+	}
+	if d, err := time.ParseDuration(since); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: want a duration such as 30d, 2w or 72h, or a date (YYYY-MM-DD)", strings.TrimSpace(since))
+}
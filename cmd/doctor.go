@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd() *cobra.Command {
+	var (
+		pkg        string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment documentation generation depends on",
+		Long: `Checks everything generation needs and suggests a fix for each problem:
+
+  - grove, cx and flow on PATH
+  - a provider for every configured model: grove llm with the provider's API
+    key, or the native Anthropic client for Claude models, with the key in
+    the environment or stored with docgen auth login; for ollama/<model>
+    local models, a reachable Ollama server that has the model
+  - workspace registration and notebook locator resolution
+  - a valid docgen.config.yml with resolvable prompts and context rules
+  - writable output directories
+  - fonts for docgen logo
+
+Exits non-zero when a check fails; warnings do not fail.
+
+Examples:
+  docgen doctor                # Check the current package
+  docgen doctor -p flow        # Check a workspace package by name
+  docgen doctor --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageDir, err := resolvePackageDir(pkg)
+			if err != nil {
+				return err
+			}
+
+			report := newGenerator().Doctor(packageDir)
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal doctor report: %w", err)
+				}
+				ulog.Info("Doctor").
+					Field("failed", report.Failed).
+					Field("warnings", report.Warnings).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, c := range report.Checks {
+					switch c.Status {
+					case generator.DoctorOK:
+						ulog.Success(c.Name).Field("detail", c.Detail).Emit()
+					case generator.DoctorWarn:
+						ulog.Warn(c.Name).Field("detail", c.Detail).Field("fix", c.Fix).Emit()
+					default:
+						ulog.Error(c.Name).Field("detail", c.Detail).Field("fix", c.Fix).Emit()
+					}
+				}
+			}
+
+			if report.Failed > 0 {
+				return fmt.Errorf("%d check(s) failed, %d warning(s)", report.Failed, report.Warnings)
+			}
+			if !jsonOutput {
+				ulog.Success("Ready to generate").Field("warnings", report.Warnings).Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&pkg, "package", "p", "", "Check a workspace package by name instead of the current directory")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the checks as JSON")
+
+	return cmd
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a package's generated docs for offline reading",
+		Long: `Packages the generated documentation of a package into a single file.
+Sections are included in their configured order; run 'docgen generate' first.`,
+	}
+
+	cmd.AddCommand(newExportEPUBCmd())
+
+	return cmd
+}
+
+// resolvePackageDir returns the directory of the named workspace package, or
+// the current directory when name is empty.
+func resolvePackageDir(name string) (string, error) {
+	if name == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		return cwd, nil
+	}
+
+	projects, err := workspace.GetProjects(getLogger())
+	if err != nil {
+		return "", fmt.Errorf("could not discover workspaces: %w", err)
+	}
+	for _, project := range projects {
+		if project.Name == name {
+			return project.Path, nil
+		}
+	}
+	return "", fmt.Errorf("package '%s' not found in any workspace", name)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/grovetools/docgen/pkg/export"
+	"github.com/spf13/cobra"
+)
+
+func newExportEPUBCmd() *cobra.Command {
+	var (
+		pkg      string
+		output   string
+		author   string
+		language string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "epub",
+		Short: "Export a package's docs as an EPUB",
+		Long: `Builds an EPUB 3 book from a package's generated docs: one chapter per
+markdown section, with local images embedded and links between sections
+rewritten to the matching chapter. Title, description and category come from
+the package's docgen config.
+
+Examples:
+  docgen export epub                        # Current package
+  docgen export epub --package flow         # A package in the workspace
+  docgen export epub --package flow -o dist/flow.epub`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageDir, err := resolvePackageDir(pkg)
+			if err != nil {
+				return err
+			}
+
+			book, err := export.LoadBook(packageDir)
+			if err != nil {
+				return err
+			}
+			book.Author = author
+			book.Language = language
+
+			if output == "" {
+				output = filepath.Base(packageDir) + ".epub"
+			}
+			if err := export.WriteEPUB(output, book); err != nil {
+				return err
+			}
+
+			ulog.Success("Exported EPUB").
+				Field("title", book.Title).
+				Field("chapters", len(book.Chapters)).
+				Field("output", output).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&pkg, "package", "p", "", "Workspace package to export (default: current directory)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default: <package>.epub)")
+	cmd.Flags().StringVar(&author, "author", "", "Author recorded in the EPUB metadata")
+	cmd.Flags().StringVar(&language, "language", "en", "Language of the docs (BCP 47 tag)")
+
+	return cmd
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"time"
 
-	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/docgen"
+	docgenJobs "github.com/grovetools/docgen/pkg/jobs"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +16,16 @@ func newGenerateCmd() *cobra.Command {
 		model     string
 		cacheTTL  string
 		usageJSON string
+		failFast  bool
+		timeout   time.Duration
+		strict    bool
+		isolate   bool
+		onlyStale bool
+		batch     bool
+		all       bool
+		jobs      int
+		enqueue   string
+		reports   reportFlags
 	)
 
 	cmd := &cobra.Command{
@@ -30,25 +43,112 @@ Examples:
   docgen generate --section introduction           # Generate only introduction
   docgen generate -s intro -s core                 # Generate multiple specific sections
   docgen generate --model claude-haiku-4-5         # Claude cache fan-out for all sections
-  docgen generate --model claude-haiku-4-5 --cache-ttl 1h`,
+  docgen generate --model claude-haiku-4-5 --cache-ttl 1h
+  docgen generate --report json --report-file build/docs-report.json
+  docgen generate --fail-fast --strict             # CI: stop on the first failure, fail on warnings
+  docgen generate --timeout 30m                    # Abort a run that hangs
+  docgen generate --isolate                        # Build context in a temporary worktree
+  docgen generate --only-stale                     # Regenerate docs older than their prompt or config
+  docgen generate --all --only-stale -j 4          # Every package of the configured ecosystems
+  docgen generate --all --enqueue                  # Queue every package's sections in the background
+  docgen generate --all --batch                    # Nightly: batch APIs at about half the price
+
+--all generates every docgen-enabled package of the ecosystems configured in
+the current directory's docgen config, the packages aggregate and watch work
+on, --jobs at a time. Its run report covers every package. A section is stale
+when its doc is missing or older than its prompt or the docgen config.
+
+--enqueue runs the generation in the background, so it survives the terminal
+that started it. --enqueue (or --enqueue=local) queues the sections as a job
+of docgen's local queue, run one section at a time by a detached worker;
+follow it with 'docgen jobs status' and 'docgen jobs attach'. --enqueue=flow
+creates a grove-flow plan with a job per section of the current package
+instead, for 'flow run' to run and track.
+
+On a terminal, a progress line below the logs shows the sections done out of
+the total, the section in progress, the elapsed time and an ETA, and the token
+throughput; piped or with --log-format json, the logs are plain.
+
+--batch submits the prose sections' requests through the providers' batch
+APIs (Anthropic, Gemini and OpenAI) instead of one at a time, which costs
+about half as much but may take up to 24 hours. The command waits for the
+batches, polling every 30 seconds, and writes the docs when they are done;
+an interrupted run resumes waiting on the same batches when run again with
+the same sections. Requests that depend on a response and those of models
+without a batch API are made directly afterwards. --batch does not use the
+cache fan-out.
+
+With settings.questions, a section may ask questions instead of guessing when
+its context is not enough. On a terminal they are asked here; otherwise, or
+when left empty, they are added to answers.yml next to the docgen config and
+the section fails until they are answered there.
+
+Any failed section makes the command exit non-zero after the remaining sections
+have run, with a summary naming the failed sections.`,
 		// A generation failure is a runtime error, not a usage error — dumping
 		// the flag reference after "15 section(s) failed" buries the cause.
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gen := generator.New(getLogger())
-
+			rec, err := reports.recorder("generate")
+			if err != nil {
+				return err
+			}
 			cwd, err := os.Getwd()
 			if err != nil {
 				return err
 			}
 
-			opts := generator.GenerateOptions{
+			opts := docgen.GenerateOptions{
 				Sections:      sections,
 				Model:         model,
 				CacheTTL:      cacheTTL,
 				UsageJSONPath: usageJSON,
+				FailFast:      failFast,
+				Strict:        strict,
+				Isolate:       isolate,
+				OnlyStale:     onlyStale,
+				Batch:         batch,
+				Logger:        getLogger(),
+				UnifiedLog:    true,
+				Report:        rec,
+			}
+			if batch && enqueue != "" {
+				return fmt.Errorf("--batch waits for its batches in this process: it cannot be combined with --enqueue")
+			}
+			switch enqueue {
+			case "":
+			case "local":
+				if usageJSON != "" || reports.format != "" || timeout > 0 {
+					return fmt.Errorf("--usage-json, --report and --timeout cover a run in this process: they cannot be combined with --enqueue")
+				}
+				return enqueueLocal(cwd, sections, onlyStale, all, docgenJobs.Options{
+					Model:    model,
+					CacheTTL: cacheTTL,
+					FailFast: failFast,
+					Strict:   strict,
+					Isolate:  isolate,
+				})
+			case "flow":
+				if all || len(sections) > 0 || onlyStale || model != "" {
+					return fmt.Errorf("--enqueue=flow plans every section of the current package with its configured model: it cannot be combined with --all, --section, --only-stale or --model")
+				}
+				return enqueueFlow(cwd)
+			default:
+				return fmt.Errorf("invalid --enqueue %q: must be 'local' or 'flow'", enqueue)
+			}
+
+			ctx, cancel := withTimeout(cmd, timeout)
+			defer cancel()
+			var stopProgress func()
+			opts.Progress, stopProgress = startProgress("Generating")
+			opts.Answer = terminalAnswers(opts.Progress)
+			if all {
+				err = docgen.GenerateAll(ctx, cwd, docgen.GenerateAllOptions{GenerateOptions: opts, Concurrency: jobs})
+			} else {
+				err = docgen.Generate(ctx, cwd, opts)
 			}
-			return gen.GenerateWithOptions(cwd, opts)
+			stopProgress()
+			return reports.finish(rec, err)
 		},
 	}
 
@@ -56,6 +156,20 @@ Examples:
 	cmd.Flags().StringVar(&model, "model", "", "Override the model for all sections; a claude-* model enables the shared-prefix cache fan-out")
 	cmd.Flags().StringVar(&cacheTTL, "cache-ttl", "", "Cache TTL for the fan-out shared prefix: 5m (default) or 1h")
 	cmd.Flags().StringVar(&usageJSON, "usage-json", "", "Write a machine-readable per-section cache/usage report (JSON) to this file at end of run")
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop at the first failed section instead of generating the rest")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail the run on warnings that leave docs incomplete (unreadable system prompt, sub-config or structured output errors)")
+	cmd.Flags().BoolVar(&isolate, "isolate", false, "Build context in a temporary git worktree so the checkout is left untouched")
+	cmd.Flags().BoolVar(&onlyStale, "only-stale", false, "Generate only sections whose doc is missing or older than its prompt or config")
+	cmd.Flags().BoolVar(&batch, "batch", false, "Submit the sections' requests through the providers' batch APIs and wait for them")
+	cmd.Flags().BoolVar(&all, "all", false, "Generate every docgen-enabled package of the configured ecosystems")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 1, "Packages generated at once with --all")
+	cmd.Flags().StringVar(&enqueue, "enqueue", "", "Run in the background as a queued job: 'local' (the default) or 'flow'")
+	cmd.Flags().Lookup("enqueue").NoOptDefVal = "local"
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort the run after this long, e.g. 30m (0 means no limit)")
+	cmd.MarkFlagsMutuallyExclusive("all", "section")
+	cmd.MarkFlagsMutuallyExclusive("all", "usage-json")
+	cmd.MarkFlagsMutuallyExclusive("only-stale", "section")
+	addReportFlags(cmd, &reports)
 
 	return cmd
 }
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newGlossaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "glossary",
+		Short: "Build and enforce the shared terminology glossary",
+		Long: `The glossary (glossary.yml) lists the domain terms used across the docs, each
+with a definition, accepted aliases, and conflicting names to avoid.
+
+'docgen generate' injects the nearest glossary.yml (or settings.glossary) into
+every section prompt so terminology stays consistent; 'glossary lint' flags
+docs that use an avoided name or introduce a term the glossary does not define.`,
+	}
+
+	cmd.AddCommand(newGlossaryBuildCmd())
+	cmd.AddCommand(newGlossaryLintCmd())
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/grovetools/docgen/pkg/glossary"
+	"github.com/spf13/cobra"
+)
+
+func newGlossaryBuildCmd() *cobra.Command {
+	var (
+		output   string
+		packages []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Extract domain terms from all packages' docs into glossary.yml",
+		Long: `Reads the generated docs of every workspace package and asks the LLM for the
+domain terms they use, then merges them into glossary.yml. Terms already in
+the glossary keep their (possibly hand-edited) definitions; new aliases,
+avoided names and packages are added.
+
+The glossary is written to the ecosystem root by default, where every
+package's generation picks it up.
+
+Examples:
+  docgen glossary build                        # All packages
+  docgen glossary build --packages flow,cx     # Only these packages
+  docgen glossary build -o docs/glossary.yml`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				root, err := workspace.FindEcosystemRoot("")
+				if err != nil || root == "" {
+					if root, err = os.Getwd(); err != nil {
+						return fmt.Errorf("failed to get current directory: %w", err)
+					}
+				}
+				output = filepath.Join(root, glossary.DefaultFile)
+			}
+
+			var packageDirs []string
+			if len(packages) > 0 {
+				for _, name := range packages {
+					dir, err := resolvePackageDir(name)
+					if err != nil {
+						return err
+					}
+					packageDirs = append(packageDirs, dir)
+				}
+			} else {
+				projects, err := workspace.GetProjects(getLogger())
+				if err != nil {
+					return fmt.Errorf("could not discover workspaces: %w", err)
+				}
+				for _, project := range projects {
+					packageDirs = append(packageDirs, project.Path)
+				}
+			}
+
+			gl := &glossary.Glossary{}
+			if _, err := os.Stat(output); err == nil {
+				if gl, err = glossary.Load(output); err != nil {
+					return err
+				}
+			}
+			before := len(gl.Terms)
+
+			if err := newGenerator().BuildGlossary(packageDirs, gl); err != nil {
+				return err
+			}
+			if err := gl.Save(output); err != nil {
+				return err
+			}
+
+			ulog.Success("Wrote glossary").
+				Field("path", output).
+				Field("terms", len(gl.Terms)).
+				Field("new", len(gl.Terms)-before).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Glossary file to write (default: glossary.yml at the ecosystem root)")
+	cmd.Flags().StringSliceVar(&packages, "packages", nil, "Extract terms from these workspace packages only")
+
+	return cmd
+}
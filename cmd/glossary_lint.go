@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/glossary"
+	"github.com/spf13/cobra"
+)
+
+func newGlossaryLintCmd() *cobra.Command {
+	var (
+		pkg          string
+		glossaryPath string
+		jsonOutput   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [files...]",
+		Short: "Flag undefined or conflicting term usage in docs",
+		Long: `Checks markdown against the glossary and exits non-zero on any issue:
+
+  conflicting-term  a name the glossary lists under 'avoid' is used
+  undefined-term    a term is introduced in bold but is not in the glossary
+
+Code blocks, inline code and link targets are ignored. Without files, the
+package's generated markdown sections are checked.
+
+Examples:
+  docgen glossary lint                     # Current package's docs
+  docgen glossary lint --package flow      # A workspace package
+  docgen glossary lint README.md docs/*.md`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageDir, err := resolvePackageDir(pkg)
+			if err != nil {
+				return err
+			}
+
+			if glossaryPath == "" {
+				cfg, _, _ := config.LoadWithNotebook(packageDir)
+				if cfg != nil {
+					glossaryPath = cfg.Settings.Glossary
+				}
+			}
+			path := glossary.Find(packageDir, glossaryPath)
+			if path == "" {
+				return fmt.Errorf("no %s found in %s or its parents (run 'docgen glossary build')", glossary.DefaultFile, packageDir)
+			}
+			gl, err := glossary.Load(path)
+			if err != nil {
+				return err
+			}
+
+			files := args
+			if len(files) == 0 {
+				targets, err := generator.ResolveSectionTargets(packageDir)
+				if err != nil {
+					return err
+				}
+				for _, t := range targets {
+					if t.Section.Type == "concat" || !strings.HasSuffix(t.Section.Output, ".md") {
+						continue
+					}
+					file := filepath.Join(t.OutputDir, t.Section.Output)
+					if _, err := os.Stat(file); err == nil {
+						files = append(files, file)
+					}
+				}
+			}
+
+			linter := glossary.NewLinter(gl)
+			issues := []glossary.Issue{}
+			for _, file := range files {
+				data, err := os.ReadFile(file) //nolint:gosec // file from args or config
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", file, err)
+				}
+				issues = append(issues, linter.Lint(file, string(data))...)
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(issues, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal lint issues: %w", err)
+				}
+				ulog.Info("Glossary lint").
+					Field("issues", len(issues)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, issue := range issues {
+					ulog.Warn("Glossary issue").
+						Field("file", issue.File).
+						Field("line", issue.Line).
+						Field("rule", issue.Rule).
+						Field("message", issue.Message).
+						Emit()
+				}
+			}
+
+			if len(issues) > 0 {
+				return fmt.Errorf("%d glossary issue(s) in %d file(s)", len(issues), len(files))
+			}
+			if !jsonOutput {
+				ulog.Success("Docs follow the glossary").
+					Field("glossary", path).
+					Field("files", len(files)).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&pkg, "package", "p", "", "Workspace package to lint (default: current directory)")
+	cmd.Flags().StringVar(&glossaryPath, "glossary", "", "Glossary file (default: settings.glossary or the nearest glossary.yml)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the lint issues as JSON")
+
+	return cmd
+}
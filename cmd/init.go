@@ -2,13 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	coreConfig "github.com/grovetools/core/config"
+	"github.com/grovetools/core/pkg/workspace"
 	"github.com/grovetools/docgen/internal/scaffold"
 	"github.com/spf13/cobra"
 )
 
 func newInitCmd() *cobra.Command {
 	var projectType string
+	var interactive bool
 	var opts scaffold.InitOptions
 
 	cmd := &cobra.Command{
@@ -20,21 +25,46 @@ This command provides a starting point for your documentation generation. It cop
 
 It will not overwrite existing files.
 
+With --interactive, a short wizard asks for the project type, the sections
+wanted, the model, the output mode (package docs or website sections) and
+whether the config and prompts live in the repo or the workspace notebook, then
+writes a config and prompts tailored to the answers.
+
 Examples:
   docgen init                                    # Initialize with defaults
+  docgen init --interactive                      # Answer a few questions first
+  docgen init --type cli                         # CLI tool with a captured command reference
   docgen init --model gemini-2.0-flash-latest    # Use a specific model
   docgen init --rules-file custom.rules          # Use a custom rules file
   docgen init --output-dir generated-docs        # Output to a different directory`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// For now, only 'library' is a valid type. This can be expanded later.
-			if projectType != "library" {
-				return fmt.Errorf("invalid project type '%s'. Currently, only 'library' is supported", projectType)
+			if interactive {
+				answers, err := scaffold.NewWizard(cmd.InOrStdin(), cmd.OutOrStdout()).Run(projectType, opts)
+				if err != nil {
+					return err
+				}
+				projectType, opts = answers.ProjectType, answers.Options
+				if answers.Notebook {
+					if opts.DocgenDir, opts.PromptsDir, err = notebookDocgenDirs(); err != nil {
+						return err
+					}
+				}
+			}
+
+			types := scaffold.ProjectTypes()
+			valid := false
+			for _, t := range types {
+				valid = valid || t == projectType
+			}
+			if !valid {
+				return fmt.Errorf("invalid project type '%s'. Available types: %s", projectType, strings.Join(types, ", "))
 			}
 			return scaffold.InitWithOptions(projectType, opts, getLogger())
 		},
 	}
 
-	cmd.Flags().StringVar(&projectType, "type", "library", "Type of project to initialize (e.g., library)")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Ask for project type, sections, model, output mode and layout")
+	cmd.Flags().StringVar(&projectType, "type", "library", "Type of project to initialize: "+strings.Join(scaffold.ProjectTypes(), ", "))
 	cmd.Flags().StringVar(&opts.Model, "model", "", "LLM model to use for generation")
 	cmd.Flags().StringVar(&opts.RegenerationMode, "regeneration-mode", "", "Regeneration mode: scratch or reference")
 	cmd.Flags().StringVar(&opts.RulesFile, "rules-file", "", "Rules file for context generation")
@@ -44,3 +74,30 @@ Examples:
 
 	return cmd
 }
+
+// notebookDocgenDirs resolves the notebook directories that hold the current
+// workspace's docgen config and prompts.
+func notebookDocgenDirs() (string, string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	node, err := workspace.GetProjectByPath(cwd)
+	if err != nil {
+		return "", "", fmt.Errorf("could not resolve workspace for the notebook layout: %w", err)
+	}
+	coreCfg, err := coreConfig.LoadDefault()
+	if err != nil {
+		return "", "", fmt.Errorf("could not load config: %w", err)
+	}
+	locator := workspace.NewNotebookLocator(coreCfg)
+	docgenDir, err := locator.GetDocgenDir(node)
+	if err != nil {
+		return "", "", fmt.Errorf("could not resolve notebook docgen directory: %w", err)
+	}
+	promptsDir, err := locator.GetDocgenPromptsDir(node)
+	if err != nil {
+		return "", "", fmt.Errorf("could not resolve notebook prompts directory: %w", err)
+	}
+	return docgenDir, promptsDir, nil
+}
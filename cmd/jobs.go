@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/core/util/delegation"
+	"github.com/grovetools/docgen/pkg/docgen"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/jobs"
+	"github.com/grovetools/docgen/pkg/recipes"
+	"github.com/spf13/cobra"
+)
+
+func newJobsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Track generation jobs queued with generate --enqueue",
+		Long: `generate --enqueue records the sections to generate as a job and runs it in a
+detached worker, so it survives the terminal that started it. Jobs commands
+show the queue and follow a running job.
+
+Job state files and logs are kept in $DOCGEN_JOBS_DIR, or docgen/jobs in the
+user's cache directory. A job ID may be shortened to any unique prefix.`,
+	}
+
+	cmd.AddCommand(newJobsStatusCmd())
+	cmd.AddCommand(newJobsAttachCmd())
+	cmd.AddCommand(newJobsRunCmd())
+
+	return cmd
+}
+
+// newJobsRunCmd is the worker generate --enqueue starts for a job.
+func newJobsRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "run <id>",
+		Short:        "Run a queued job (started by generate --enqueue)",
+		Hidden:       true,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := jobs.Load(args[0])
+			if err != nil {
+				return err
+			}
+			ctx, cancel := withTimeout(cmd, 0)
+			defer cancel()
+			log := getLogger()
+			log.Infof("Running job %s: %d section(s)", job.ID, len(job.Tasks))
+			return job.Run(ctx, func(ctx context.Context, t jobs.Task) error {
+				log.Infof("Generating %s/%s", t.Package, t.Section)
+				return docgen.Generate(ctx, t.Dir, docgen.GenerateOptions{
+					Sections:   []string{t.Section},
+					Model:      job.Options.Model,
+					CacheTTL:   job.Options.CacheTTL,
+					Strict:     job.Options.Strict,
+					Isolate:    job.Options.Isolate,
+					Logger:     log,
+					UnifiedLog: true,
+				})
+			})
+		},
+	}
+}
+
+// enqueueLocal queues the sections a generate run would cover as a local
+// job and starts its worker. With all, every package's sections are queued;
+// otherwise those of the package in cwd, narrowed to sections when given.
+func enqueueLocal(cwd string, sections []string, onlyStale, all bool, opts jobs.Options) error {
+	type pkgDir struct{ name, dir string }
+	packages := []pkgDir{{filepath.Base(cwd), cwd}}
+	if all {
+		found, err := newGenerator().Packages(cwd)
+		if err != nil {
+			return err
+		}
+		packages = packages[:0]
+		for _, p := range found {
+			packages = append(packages, pkgDir{p.Name, p.Path})
+		}
+	}
+
+	var tasks []jobs.Task
+	for _, p := range packages {
+		names := sections
+		if len(names) == 0 {
+			var err error
+			if names, err = enqueueSections(p.dir, onlyStale); err != nil {
+				return fmt.Errorf("%s: %w", p.name, err)
+			}
+		}
+		for _, name := range names {
+			tasks = append(tasks, jobs.Task{Package: p.name, Dir: p.dir, Section: name})
+		}
+	}
+	if len(tasks) == 0 && onlyStale {
+		ulog.Info("No stale sections to enqueue").Emit()
+		return nil
+	}
+
+	job, err := jobs.New(tasks, opts)
+	if err != nil {
+		return err
+	}
+	if err := job.Start(); err != nil {
+		job.Status, job.Error = jobs.StatusFailed, err.Error()
+		_ = job.Save()
+		return err
+	}
+	ulog.Success("Enqueued generation job").
+		Field("job", job.ID).
+		Field("sections", len(tasks)).
+		Field("log", job.Log).
+		Emit()
+	ulog.Info("Next steps").
+		PrettyOnly().
+		Pretty(fmt.Sprintf("\nThe job runs in the background and survives this terminal:\n  docgen jobs status %s\n  docgen jobs attach %s", job.ID, job.ID)).
+		Emit()
+	return nil
+}
+
+// enqueueSections returns the names of the sections of the package in dir
+// to queue: the stale ones with onlyStale, else all of them.
+func enqueueSections(dir string, onlyStale bool) ([]string, error) {
+	if onlyStale {
+		return newGenerator().StaleSections(dir)
+	}
+	targets, err := generator.ResolveSectionTargets(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+// enqueueFlow creates a grove-flow plan from the docgen-sections recipe for
+// the package in cwd, one job per section, for flow to run and track.
+func enqueueFlow(cwd string) error {
+	targets, err := generator.ResolveSectionTargets(cwd)
+	if err != nil {
+		return err
+	}
+	if _, err := recipes.SectionsRecipe(targets); err != nil {
+		return fmt.Errorf("cannot build the %s recipe: %w", recipes.SectionsRecipeName, err)
+	}
+
+	planName := fmt.Sprintf("%s-%s", recipes.SectionsRecipeName, filepath.Base(cwd))
+	flowCmd := delegation.Command("flow", "plan", "init", planName,
+		"--recipe", recipes.SectionsRecipeName,
+		"--recipe-cmd", "docgen recipe print")
+	flowCmd.Stdout = os.Stdout
+	flowCmd.Stderr = os.Stderr
+	flowCmd.Stdin = os.Stdin
+	if err := flowCmd.Run(); err != nil {
+		return fmt.Errorf("failed to create flow plan: %w", err)
+	}
+
+	ulog.Success("Enqueued sections as a flow plan").
+		Field("plan_name", planName).
+		Field("sections", len(targets)).
+		Field("location", fmt.Sprintf("plans/%s", planName)).
+		Emit()
+	ulog.Info("Next steps").
+		PrettyOnly().
+		Pretty("\nRun 'flow run' to generate the sections; flow tracks each section's job").
+		Emit()
+	return nil
+}
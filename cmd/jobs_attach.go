@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/jobs"
+	"github.com/spf13/cobra"
+)
+
+// attachPollInterval is how often jobs attach checks for new log output and
+// progress.
+const attachPollInterval = 500 * time.Millisecond
+
+func newJobsAttachCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attach [id]",
+		Short: "Follow a generation job's output until it finishes",
+		Long: `Prints the job's log so far and follows it until the job finishes, then
+prints a summary. Without an ID, attaches to the newest job.
+
+Ctrl-C detaches: the job keeps running and can be attached to again. The
+command exits non-zero when the job failed or was interrupted.
+
+Examples:
+  docgen jobs attach
+  docgen jobs attach 20261016-142233`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var job *jobs.Job
+			if len(args) == 1 {
+				var err error
+				if job, err = jobs.Load(args[0]); err != nil {
+					return err
+				}
+			} else {
+				all, err := jobs.List()
+				if err != nil {
+					return err
+				}
+				if len(all) == 0 {
+					return fmt.Errorf("no jobs queued: start one with docgen generate --enqueue")
+				}
+				job = all[0]
+			}
+
+			// The worker may not have created the log yet
+			var logFile *os.File
+			follow := func() {
+				if logFile == nil {
+					f, err := os.Open(job.Log)
+					if err != nil {
+						return
+					}
+					logFile = f
+				}
+				_, _ = io.Copy(os.Stdout, logFile)
+			}
+			defer func() {
+				if logFile != nil {
+					logFile.Close() //nolint:errcheck // read-only
+				}
+			}()
+
+			ctx, cancel := withTimeout(cmd, 0)
+			defer cancel()
+			id := job.ID
+			var err error
+			for {
+				follow()
+				if job.Finished() {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					ulog.Info("Detached; the job keeps running").Field("job", id).Emit()
+					return nil
+				case <-time.After(attachPollInterval):
+				}
+				if job, err = jobs.Load(id); err != nil {
+					return err
+				}
+			}
+			follow()
+
+			counts := job.Counts()
+			state := job.State()
+			ulog.Info("Job finished").
+				Field("job", job.ID).
+				Field("status", state).
+				Field("ok", counts[jobs.TaskOK]).
+				Field("failed", counts[jobs.TaskFailed]).
+				Field("skipped", counts[jobs.TaskSkipped]).
+				Emit()
+			switch state {
+			case jobs.StatusFailed:
+				return fmt.Errorf("job %s failed: %s", job.ID, job.Error)
+			case jobs.StatusInterrupted:
+				return fmt.Errorf("job %s was interrupted after %d of %d section(s): see docgen jobs status %s for the sections left", job.ID, counts[jobs.TaskOK], len(job.Tasks), job.ID)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/jobs"
+	"github.com/spf13/cobra"
+)
+
+func newJobsStatusCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status [id]",
+		Short: "Show queued generation jobs and their progress",
+		Long: `Without an ID, lists every job, newest first, with its status and how many of
+its sections are done. With an ID, shows each section of that job.
+
+A job whose worker exited without finishing (killed, or the machine
+restarted) is shown as interrupted.
+
+Examples:
+  docgen jobs status
+  docgen jobs status 20261016-142233-9f3c1a
+  docgen jobs status 20261016 --json`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				job, err := jobs.Load(args[0])
+				if err != nil {
+					return err
+				}
+				return showJob(job, jsonOutput)
+			}
+
+			all, err := jobs.List()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return emitListJSON("Jobs", len(all), all)
+			}
+
+			var sb strings.Builder
+			tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tSTATUS\tDONE\tFAILED\tCREATED")
+			for _, j := range all {
+				counts := j.Counts()
+				fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%d\t%s\n", j.ID, j.State(), counts[jobs.TaskOK], len(j.Tasks), counts[jobs.TaskFailed], j.CreatedAt.Local().Format("2006-01-02 15:04"))
+			}
+			tw.Flush() //nolint:errcheck // writes to a strings.Builder
+			ulog.Info("Jobs").
+				Field("count", len(all)).
+				PrettyOnly().
+				Pretty(sb.String()).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the jobs as JSON")
+
+	return cmd
+}
+
+// showJob prints one job's sections as a table, or the job as JSON.
+func showJob(job *jobs.Job, jsonOutput bool) error {
+	job.Status = job.State()
+	if jsonOutput {
+		return emitListJSON("Job", len(job.Tasks), job)
+	}
+
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tSECTION\tSTATUS\tDURATION\tERROR")
+	for _, t := range job.Tasks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Package, t.Section, t.Status, taskDuration(t), orDash(t.Error))
+	}
+	tw.Flush() //nolint:errcheck // writes to a strings.Builder
+	ulog.Info("Job").
+		Field("job", job.ID).
+		Field("status", job.Status).
+		Field("log", job.Log).
+		PrettyOnly().
+		Pretty(fmt.Sprintf("Job %s: %s\nLog: %s\n\n%s", job.ID, job.Status, job.Log, sb.String())).
+		Emit()
+	if job.Error != "" {
+		ulog.Warn("Job failed").Field("job", job.ID).Field("error", job.Error).Emit()
+	}
+	return nil
+}
+
+// taskDuration formats how long a task ran, or has been running.
+func taskDuration(t jobs.Task) string {
+	if t.StartedAt == nil {
+		return "-"
+	}
+	end := time.Now()
+	if t.FinishedAt != nil {
+		end = *t.FinishedAt
+	}
+	return end.Sub(*t.StartedAt).Round(time.Second).String()
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List configured sections and documented packages",
+		Long: `List commands summarize docgen configuration as a table, or as JSON with
+--json for tooling.`,
+	}
+
+	cmd.AddCommand(newListSectionsCmd())
+	cmd.AddCommand(newListPackagesCmd())
+
+	return cmd
+}
+
+func newListSectionsCmd() *cobra.Command {
+	var (
+		pkg        string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sections",
+		Short: "List the current package's sections",
+		Long: `Lists every section of the package's docgen config with its type, status,
+output file, effective model and when the output was last generated.
+
+Examples:
+  docgen list sections
+  docgen list sections -p flow --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageDir, err := resolvePackageDir(pkg)
+			if err != nil {
+				return err
+			}
+			sections, err := generator.ListSections(packageDir)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return emitListJSON("Sections", len(sections), sections)
+			}
+
+			var sb strings.Builder
+			tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tTYPE\tSTATUS\tOUTPUT\tMODEL\tLAST GENERATED")
+			for _, s := range sections {
+				output := s.Output
+				if rel, err := filepath.Rel(packageDir, output); err == nil && !strings.HasPrefix(rel, "..") {
+					output = rel
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.Type, s.Status, output, orDash(s.Model), lastGenerated(s.LastGenerated))
+			}
+			tw.Flush() //nolint:errcheck // writes to a strings.Builder
+			ulog.Info("Sections").
+				Field("count", len(sections)).
+				PrettyOnly().
+				Pretty(sb.String()).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&pkg, "package", "p", "", "List a workspace package by name instead of the current directory")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the sections as JSON")
+
+	return cmd
+}
+
+func newListPackagesCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "packages",
+		Short: "List documented packages across the ecosystem",
+		Long: `Lists every discovered workspace that has a docgen config, with whether docs
+are enabled, its category and its section counts by status.
+
+Examples:
+  docgen list packages
+  docgen list packages --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projects, err := workspace.GetProjects(getLogger())
+			if err != nil {
+				return fmt.Errorf("could not discover workspaces: %w", err)
+			}
+
+			var packages []*generator.PackageInfo
+			for _, project := range projects {
+				info, err := generator.DescribePackage(project.Name, project.Path)
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					ulog.Warn("Skipping package").Field("package", project.Name).Err(err).Emit()
+					continue
+				}
+				packages = append(packages, info)
+			}
+			sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+
+			if jsonOutput {
+				return emitListJSON("Packages", len(packages), packages)
+			}
+
+			var sb strings.Builder
+			tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "PACKAGE\tENABLED\tCATEGORY\tSECTIONS\tPRODUCTION\tDEV\tDRAFT")
+			for _, p := range packages {
+				name := p.Name
+				if p.OutputMode == "sections" {
+					name += " (website sections)"
+				}
+				fmt.Fprintf(tw, "%s\t%t\t%s\t%d\t%d\t%d\t%d\n", name, p.Enabled, orDash(p.Category), p.Sections, p.Production, p.Dev, p.Draft)
+			}
+			tw.Flush() //nolint:errcheck // writes to a strings.Builder
+			ulog.Info("Packages").
+				Field("count", len(packages)).
+				PrettyOnly().
+				Pretty(sb.String()).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the packages as JSON")
+
+	return cmd
+}
+
+// emitListJSON emits a list command's result as indented JSON.
+func emitListJSON(title string, count int, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", strings.ToLower(title), err)
+	}
+	ulog.Info(title).
+		Field("count", count).
+		PrettyOnly().
+		Pretty(string(data)).
+		Emit()
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// lastGenerated formats an output's modification time for the sections table.
+func lastGenerated(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
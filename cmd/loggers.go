@@ -1,16 +1,25 @@
 package cmd
 
 import (
-	"github.com/grovetools/core/logging"
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/generator"
 	"github.com/sirupsen/logrus"
 )
 
 var (
-	log  = logging.NewLogger("grove-docgen")
-	ulog = logging.NewUnifiedLogger("grove-docgen")
+	log  = logrus.NewEntry(clilog.Logger())
+	ulog = clilog.New(clilog.Component)
 )
 
 // getLogger returns the logrus.Logger for use with packages that expect it
 func getLogger() *logrus.Logger {
-	return log.Logger
+	return clilog.Logger()
+}
+
+// newGenerator returns a generator logging to the CLI's logger, with its
+// milestones going to grove's unified log as well.
+func newGenerator() *generator.Generator {
+	gen := generator.New(getLogger())
+	gen.SetEventLogger(ulog)
+	return gen
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -16,6 +17,7 @@ func newLogoCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(newLogoGenerateCmd())
+	cmd.AddCommand(newLogoBatchCmd())
 
 	return cmd
 }
@@ -24,12 +26,18 @@ func newLogoGenerateCmd() *cobra.Command {
 	var (
 		text      string
 		textColor string
-		fontPath  string
+		fonts     []string
 		fontSize  float64
 		spacing   float64
 		textScale float64
 		width     float64
 		output    string
+		raster    string
+		sizes     []int
+		iconBg    string
+		layout    string
+		letterSp  float64
+		weight    string
 	)
 
 	cmd := &cobra.Command{
@@ -42,11 +50,36 @@ the font to be installed on the viewer's system.
 This is useful for generating README-friendly logos that include the product name,
 since GitHub READMEs cannot rely on custom fonts being available.
 
+With --raster png, the combined logo is also written as a PNG (at twice
+--width, for high-density screens) and the input logo is rasterized into an
+icon set next to the output: <logo>-<size>.png for each of --sizes, a
+favicon.ico holding the sizes up to 64px, and an apple-touch-icon.png.
+
+Text can span several lines: separate them with \n. The first line is scaled
+to --text-scale and longer lines shrink to its width, so a long name does not
+shrink the whole wordmark. With --layout right the text sits next to the
+logo, vertically centered, and --text-scale sets its height instead.
+
+Lines are measured by the font's advance widths, ascent and descent rather
+than by their ink, so descenders are never clipped, stacked lines of a
+monospace font line up, and centering does not depend on the letters used.
+Repeat --font to add fallback fonts: each glyph is taken from the first font
+that has it (e.g. a symbol font after the main one).
+
 Example:
-  docgen logo generate logo-dark.svg --text "grove flow" --font /path/to/FiraCode.ttf --color "#589ac7" -o logo-with-text-dark.svg`,
+  docgen logo generate logo-dark.svg --text "grove flow" --font /path/to/FiraCode.ttf --color "#589ac7" -o logo-with-text-dark.svg
+  docgen logo generate logo.svg --text "grove\nflow" --layout right --letter-spacing 0.05 --font-weight bold --font /path/to/FiraCode.ttf
+  docgen logo generate logo.svg --text "grove flow" --font /path/to/FiraCode.ttf --raster png --sizes 16,32,180,512 -o public/logo-with-text.svg`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			inputPath := args[0]
+			fontWeight, err := logo.ParseFontWeight(weight)
+			if err != nil {
+				return err
+			}
+			if raster != "" && raster != "png" {
+				return fmt.Errorf("unsupported --raster format %q (supported: png)", raster)
+			}
 
 			// Default output path based on input
 			if output == "" {
@@ -56,15 +89,19 @@ Example:
 			}
 
 			cfg := logo.Config{
-				InputPath:  inputPath,
-				OutputPath: output,
-				Text:       text,
-				TextColor:  textColor,
-				FontPath:   fontPath,
-				FontSize:   fontSize,
-				Spacing:    spacing,
-				TextScale:  textScale,
-				Width:      width,
+				InputPath:     inputPath,
+				OutputPath:    output,
+				Text:          strings.ReplaceAll(text, `\n`, "\n"),
+				TextColor:     textColor,
+				FontPath:      fonts[0],
+				FallbackFonts: fonts[1:],
+				FontSize:      fontSize,
+				Spacing:       spacing,
+				TextScale:     textScale,
+				Width:         width,
+				Layout:        layout,
+				LetterSpacing: letterSp,
+				FontWeight:    fontWeight,
 			}
 
 			gen := logo.New(getLogger())
@@ -73,21 +110,98 @@ Example:
 			}
 
 			ulog.Success("Generated logo with text paths").Field("output", output).Emit()
+
+			if raster == "" {
+				return nil
+			}
+			pngPath, err := gen.ExportPNG(cfg)
+			if err != nil {
+				return err
+			}
+			ulog.Success("Rasterized logo").Field("output", pngPath).Emit()
+
+			icons, err := gen.GenerateIconSet(logo.IconSetConfig{
+				InputPath:  inputPath,
+				OutputDir:  filepath.Dir(output),
+				Sizes:      sizes,
+				Background: iconBg,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to generate icon set: %w", err)
+			}
+			ulog.Success("Generated icon set").Field("files", len(icons)).Field("dir", filepath.Dir(output)).Emit()
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&text, "text", "", "Text to display below the logo (required)")
 	cmd.Flags().StringVar(&textColor, "color", "", "Text color (hex, empty for auto-detect from source SVG)")
-	cmd.Flags().StringVar(&fontPath, "font", "", "Path to TTF/OTF font file (required)")
+	cmd.Flags().StringArrayVar(&fonts, "font", nil, "Path to TTF/OTF font file (required; repeat to add fallbacks for glyphs the first font lacks)")
 	cmd.Flags().Float64Var(&fontSize, "size", 48, "Font size in pixels")
 	cmd.Flags().Float64Var(&spacing, "spacing", 20, "Spacing between logo and text in pixels")
-	cmd.Flags().Float64Var(&textScale, "text-scale", 0.8, "Text width as proportion of logo width (e.g., 1.0 = same width)")
+	cmd.Flags().Float64Var(&textScale, "text-scale", 0, "Text width as proportion of logo width (e.g., 1.0 = same width), or text height as proportion of logo height with --layout right (default 0.8 below, 0.6 right)")
 	cmd.Flags().Float64Var(&width, "width", 200, "Output SVG width in pixels")
 	cmd.Flags().StringVarP(&output, "output", "o", "", "Output path (defaults to input-with-text.svg)")
+	cmd.Flags().StringVar(&layout, "layout", logo.LayoutBelow, "Text placement: below or right of the logo")
+	cmd.Flags().Float64Var(&letterSp, "letter-spacing", 0, "Extra space between letters in em (e.g., 0.05)")
+	cmd.Flags().StringVar(&weight, "font-weight", "normal", "Font weight: normal, bold, or 400-900 (heavier weights embolden the glyph outlines)")
+	cmd.Flags().StringVar(&raster, "raster", "", "Also export raster images and an icon set (png)")
+	cmd.Flags().IntSliceVar(&sizes, "sizes", logo.DefaultIconSizes, "Icon sizes in pixels for --raster")
+	cmd.Flags().StringVar(&iconBg, "icon-background", "#ffffff", "Background of the apple-touch-icon, which iOS shows black when transparent")
 
 	_ = cmd.MarkFlagRequired("text")
 	_ = cmd.MarkFlagRequired("font")
 
 	return cmd
 }
+
+func newLogoBatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch <manifest.yml>",
+		Short: "Generate the combined logos of every package from a manifest",
+		Long: `Generates combined logo+text SVGs for every logo listed in a manifest, with
+their theme variants, so a rebrand or font change regenerates all assets in
+one run. Paths in the manifest are relative to it.
+
+Manifest:
+  font: ~/.local/share/fonts/FiraCode-Regular.ttf
+  width: 200
+  raster: png            # optional: PNGs and an icon set, as --raster png
+  logos:
+    - input: flow/docs/images/logo.svg
+      text: grove flow
+      output: flow/docs/images/logo-with-text.svg
+      themes:
+        - { name: dark, input: flow/docs/images/logo-dark.svg, color: "#589ac7" }
+
+A theme's output defaults to <input>-with-text-<name>.svg. A logo that fails
+is reported and the others are still generated.
+
+Example:
+  docgen logo batch logos.yml`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := logo.LoadManifest(args[0])
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, r := range logo.New(getLogger()).GenerateBatch(manifest) {
+				if r.Err != nil {
+					failed++
+					ulog.Warn("Failed to generate logo").Field("output", r.Output).Field("error", r.Err.Error()).Emit()
+					continue
+				}
+				ulog.Success("Generated logo").Field("output", r.Output).Field("raster_files", len(r.Files)).Emit()
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d logo(s) failed", failed)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/progress"
+)
+
+// startProgress shows a progress line titled title below the logs when
+// stderr is a terminal and logs are text, and returns it with the function
+// that removes it. Elsewhere it returns a nil tracker and logs stay plain.
+func startProgress(title string) (*progress.Tracker, func()) {
+	if clilog.Current().Format != clilog.FormatText {
+		return nil, func() {}
+	}
+	p := progress.New(os.Stderr, title)
+	if p == nil {
+		return nil, func() {}
+	}
+	clilog.SetStatusLine(p)
+	return p, func() {
+		clilog.SetStatusLine(nil)
+		p.Stop()
+	}
+}
@@ -71,7 +71,7 @@ Examples:
     --followup "merge the CLI pages" --transcript ./proposal/transcript.json`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gen := generator.New(getLogger())
+			gen := newGenerator()
 
 			cwd, err := os.Getwd()
 			if err != nil {
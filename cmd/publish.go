@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newPublishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Publish a package's generated docs to an external host",
+		Long: `Pushes the generated documentation of a package to a hosted docs target.
+Run 'docgen generate' first; only sections that have been generated are published.`,
+	}
+
+	cmd.AddCommand(newPublishWikiCmd())
+
+	return cmd
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grovetools/docgen/pkg/publish"
+	"github.com/spf13/cobra"
+)
+
+func newPublishWikiCmd() *cobra.Command {
+	var (
+		pkg        string
+		opts       publish.WikiOptions
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wiki",
+		Short: "Publish generated docs to the repository's GitHub wiki",
+		Long: `Clones the repository's GitHub wiki, writes each generated section as a wiki
+page, and pushes the update. The first section becomes Home; the others are
+named after their titles. Links between sections are rewritten to wiki links
+([[Title|Page]]), local images are copied into images/, linked downloads into
+downloads/ (with their size and hash added to the link text), and a
+_Sidebar.md listing every page in section order is generated.
+
+The wiki remote defaults to origin's URL with .wiki.git. The wiki must already
+exist: GitHub only creates the wiki repository after its first page is saved.
+
+Examples:
+  docgen publish wiki                      # Publish the current package
+  docgen publish wiki --package flow       # Publish a workspace package
+  docgen publish wiki --dry-run            # Write pages to a local clone only`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageDir, err := resolvePackageDir(pkg)
+			if err != nil {
+				return err
+			}
+
+			result, err := publish.New(getLogger()).Wiki(packageDir, opts)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal publish result: %w", err)
+				}
+				ulog.Info("Wiki publish").
+					Field("pages", len(result.Pages)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+				return nil
+			}
+
+			switch {
+			case !result.Changed:
+				ulog.Info("Wiki is already up to date").
+					Field("remote", result.Remote).
+					Field("pages", len(result.Pages)).
+					Emit()
+			case opts.DryRun:
+				ulog.Info("Wrote wiki pages (dry run, nothing committed)").
+					Field("dir", result.Dir).
+					Field("pages", len(result.Pages)).
+					Emit()
+			case result.Pushed:
+				ulog.Success("Published wiki").
+					Field("remote", result.Remote).
+					Field("pages", len(result.Pages)).
+					Emit()
+			default:
+				ulog.Success("Committed wiki update (not pushed)").
+					Field("dir", result.Dir).
+					Field("pages", len(result.Pages)).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&pkg, "package", "p", "", "Workspace package to publish (default: current directory)")
+	cmd.Flags().StringVar(&opts.Remote, "remote", "", "Wiki git remote (default: origin with .wiki.git)")
+	cmd.Flags().StringVar(&opts.Dir, "dir", "", "Clone the wiki into this directory and keep it (default: a temp dir)")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Commit message for the wiki update")
+	cmd.Flags().BoolVar(&opts.NoPush, "no-push", false, "Commit the update without pushing it")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Write the pages without committing or pushing")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the publish result as JSON")
+
+	return cmd
+}
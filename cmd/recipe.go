@@ -3,7 +3,9 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
+	"github.com/grovetools/docgen/pkg/generator"
 	"github.com/grovetools/docgen/pkg/recipes"
 	"github.com/spf13/cobra"
 )
@@ -24,7 +26,11 @@ func newRecipePrintCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "print",
 		Short: "Print available recipes in JSON format",
-		Long:  "Print all available documentation recipes in a format suitable for grove-flow integration",
+		Long: `Print all available documentation recipes in a format suitable for grove-flow integration.
+
+Run in a package with a docgen config, the collection also holds the
+docgen-sections recipe, built from the configured sections: one job per
+section and a review job.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			collection := make(recipes.RecipeCollection)
 
@@ -49,6 +55,9 @@ func newRecipePrintCmd() *cobra.Command {
 			}
 			collection["add-readme-template"] = readmeRecipe
 
+			// Build the docgen-sections recipe from the current package's config
+			addSectionsRecipe(collection)
+
 			// Output as JSON
 			jsonData, err := json.MarshalIndent(collection, "", "  ")
 			if err != nil {
@@ -67,3 +76,24 @@ func newRecipePrintCmd() *cobra.Command {
 
 	return cmd
 }
+
+// addSectionsRecipe adds the docgen-sections recipe for the package in the
+// current directory to collection. Outside a configured package it is left
+// out, since there are no sections to build it from.
+func addSectionsRecipe(collection recipes.RecipeCollection) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	targets, err := generator.ResolveSectionTargets(cwd)
+	if err != nil {
+		log.Debugf("No docgen-sections recipe: %v", err)
+		return
+	}
+	recipe, err := recipes.SectionsRecipe(targets)
+	if err != nil {
+		log.Debugf("No docgen-sections recipe: %v", err)
+		return
+	}
+	collection[recipes.SectionsRecipeName] = recipe
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grovetools/docgen/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+// reportFlags holds the --report and --report-file flags shared by generate,
+// aggregate and watch.
+type reportFlags struct {
+	format string
+	file   string
+}
+
+func addReportFlags(cmd *cobra.Command, f *reportFlags) {
+	cmd.Flags().StringVar(&f.format, "report", "", "Emit a machine-readable run report: json")
+	cmd.Flags().StringVar(&f.file, "report-file", "", "Write the run report to this file instead of stdout")
+}
+
+// recorder returns a report recorder for command, or nil when no report was
+// asked for.
+func (f *reportFlags) recorder(command string) (*report.Recorder, error) {
+	switch f.format {
+	case "":
+		return nil, nil
+	case "json":
+		return report.New(command), nil
+	}
+	return nil, fmt.Errorf("unsupported report format %q: use json", f.format)
+}
+
+// finish closes rec with the run's result and writes the report, returning
+// runErr unchanged so a report never masks the run's own outcome.
+func (f *reportFlags) finish(rec *report.Recorder, runErr error) error {
+	if rec == nil {
+		return runErr
+	}
+	if err := rec.Finish(runErr).Write(f.file); err != nil && runErr == nil {
+		return err
+	}
+	return runErr
+}
+
+// stream finishes rec and emits it as one of a series of reports, such as one
+// per watch rebuild: a JSON line on stdout, or the latest report in
+// --report-file.
+func (f *reportFlags) stream(rec *report.Recorder) error {
+	if rec == nil {
+		return nil
+	}
+	run := rec.Finish(nil)
+	if f.file != "" {
+		return run.Write(f.file)
+	}
+	return run.WriteLine(os.Stdout)
+}
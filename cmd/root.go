@@ -1,7 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/grovetools/core/cli"
+	"github.com/grovetools/core/version"
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
 
@@ -9,13 +20,17 @@ var rootCmd *cobra.Command
 
 func init() {
 	rootCmd = cli.NewStandardCommand("docgen", "LLM-powered, workspace-aware documentation generator.")
+	addLogFlags(rootCmd)
 
 	// Add commands
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newGenerateCmd())
+	rootCmd.AddCommand(newJobsCmd())
 	rootCmd.AddCommand(newProposeCmd())
 	rootCmd.AddCommand(newAggregateCmd())
 	rootCmd.AddCommand(newInitCmd())
+	rootCmd.AddCommand(newDoctorCmd())
+	rootCmd.AddCommand(newListCmd())
 	rootCmd.AddCommand(newRegenJSONCmd())
 	rootCmd.AddCommand(newCustomizeCmd())
 	rootCmd.AddCommand(newRecipeCmd())
@@ -25,10 +40,107 @@ func init() {
 	rootCmd.AddCommand(newMigrateConfigCmd())
 	rootCmd.AddCommand(newSyncCmd())
 	rootCmd.AddCommand(newWatchCmd())
+	rootCmd.AddCommand(newTuiCmd())
 	rootCmd.AddCommand(newLogoCmd())
 	rootCmd.AddCommand(newCaptureCmd())
+	rootCmd.AddCommand(newConceptCmd())
+	rootCmd.AddCommand(newCheckCmd())
+	rootCmd.AddCommand(newCICmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newDescriptionsCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newPublishCmd())
+	rootCmd.AddCommand(newTranslateCmd())
+	rootCmd.AddCommand(newGlossaryCmd())
+	rootCmd.AddCommand(newDigestCmd())
+	rootCmd.AddCommand(newAuthCmd())
+	rootCmd.AddCommand(newCompletionCmd())
+
+	registerCompletions(rootCmd)
 }
 
+// Execute runs the root command. SIGINT and SIGTERM cancel the command's
+// context, so long-running commands stop their LLM requests and subprocesses
+// and exit without leaving partial output behind.
+//
+// When an OTLP endpoint is configured (see telemetry.FromEnv), the run is
+// traced under a root span named after the command, and its spans and
+// counters are exported before Execute returns.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	provider, err := telemetry.FromEnv(version.GetInfo().Version)
+	if err != nil {
+		getLogger().Warnf("Telemetry is off: %v", err)
+	}
+	for _, w := range provider.Warnings() {
+		getLogger().Warnf("Telemetry: %s", w)
+	}
+	if provider == nil {
+		return rootCmd.ExecuteContext(ctx)
+	}
+	telemetry.SetProvider(provider)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(shutdownCtx); err != nil {
+			getLogger().Warnf("Telemetry export failed: %v", err)
+		}
+	}()
+
+	name := "docgen"
+	if c, _, err := rootCmd.Find(os.Args[1:]); err == nil {
+		name = c.CommandPath()
+	}
+	ctx, span := telemetry.Start(ctx, name, telemetry.String("docgen.args", strings.Join(os.Args[1:], " ")))
+	err = rootCmd.ExecuteContext(ctx)
+	span.RecordError(err)
+	span.End()
+	return err
+}
+
+// addLogFlags adds the global logging flags and applies them before any
+// command runs. --verbose is shared with the standard grove flags when the
+// command already has it.
+func addLogFlags(root *cobra.Command) {
+	flags := root.PersistentFlags()
+	flags.BoolP("quiet", "q", false, "Only print warnings, errors and command output")
+	if flags.Lookup("verbose") == nil {
+		flags.BoolP("verbose", "v", false, "Enable verbose logging")
+	}
+	flags.String("log-format", clilog.FormatText, "Log format: text or json")
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		format, _ := cmd.Flags().GetString("log-format")
+		if quiet && verbose {
+			return fmt.Errorf("--quiet and --verbose cannot be used together")
+		}
+		opts := clilog.Options{Verbosity: clilog.Normal, Format: format}
+		switch {
+		case quiet:
+			opts.Verbosity = clilog.Quiet
+		case verbose:
+			opts.Verbosity = clilog.Verbose
+		}
+		return clilog.Configure(opts)
+	}
+}
+
+// telemetryShutdownTimeout bounds the final telemetry export, so an
+// unreachable collector cannot hold up the exit.
+const telemetryShutdownTimeout = 5 * time.Second
+
+// withTimeout derives the context for a command run from the command's
+// (signal-cancelled) context, bounded by timeout when it is positive.
+func withTimeout(cmd *cobra.Command, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
 }
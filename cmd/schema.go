@@ -6,11 +6,13 @@ func newSchemaCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "schema",
 		Short: "Manage and process JSON schemas",
-		Long:  "Provides tools for generating, enriching, and documenting JSON schemas.",
+		Long:  "Provides tools for generating, enriching, validating, and documenting JSON schemas.",
 	}
 
 	cmd.AddCommand(newSchemaEnrichCmd())
 	cmd.AddCommand(newSchemaGenerateCmd())
+	cmd.AddCommand(newSchemaCheckCmd())
+	cmd.AddCommand(newSchemaReflectCmd())
 
 	return cmd
 }
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grovetools/docgen/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCheckCmd() *cobra.Command {
+	var (
+		opts       = schema.DefaultCheckOptions()
+		noExamples bool
+		strict     bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check <path/to/schema.json>",
+		Short: "Validate that a JSON schema is fully documented",
+		Long: `Verifies that every property in a JSON schema has a description and (for leaf
+properties) examples, and flags descriptions that violate style rules: banned
+words and minimum/maximum length.
+
+Missing descriptions and examples are errors; style violations are warnings.
+The command exits non-zero when any error is found, or any warning with --strict,
+so it can gate schema quality in CI.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schemaPath := args[0]
+			if noExamples {
+				opts.RequireExamples = false
+			}
+
+			parser, err := schema.NewParser(schemaPath)
+			if err != nil {
+				return err
+			}
+			issues, err := parser.Check(opts)
+			if err != nil {
+				return fmt.Errorf("failed to check schema: %w", err)
+			}
+
+			var errorCount, warningCount int
+			for _, issue := range issues {
+				if issue.Severity == schema.SeverityError {
+					errorCount++
+				} else {
+					warningCount++
+				}
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(issues, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal issues: %w", err)
+				}
+				ulog.Info("Schema check issues").
+					Field("errors", errorCount).
+					Field("warnings", warningCount).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+			} else {
+				for _, issue := range issues {
+					entry := ulog.Warn(issue.Message)
+					if issue.Severity == schema.SeverityError {
+						entry = ulog.Error(issue.Message)
+					}
+					entry.Field("path", issue.Path).Field("rule", issue.Rule).Emit()
+				}
+			}
+
+			if errorCount > 0 || (strict && warningCount > 0) {
+				return fmt.Errorf("schema check failed for %s: %d error(s), %d warning(s)", schemaPath, errorCount, warningCount)
+			}
+			if !jsonOutput {
+				ulog.Success("Schema check passed").
+					Field("schema", schemaPath).
+					Field("warnings", warningCount).
+					Emit()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noExamples, "no-examples", false, "Do not require examples on leaf properties")
+	cmd.Flags().StringSliceVar(&opts.BannedWords, "banned-words", opts.BannedWords, "Words that may not appear in descriptions")
+	cmd.Flags().IntVar(&opts.MinLength, "min-length", opts.MinLength, "Minimum description length in characters (0 disables)")
+	cmd.Flags().IntVar(&opts.MaxLength, "max-length", opts.MaxLength, "Maximum description length in characters (0 disables)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on style warnings as well as errors")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output issues as JSON")
+
+	return cmd
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSchemaCheckExit runs `docgen schema check`: errors, and warnings under
+// --strict, return an error so main exits non-zero.
+func TestSchemaCheckExit(t *testing.T) {
+	const (
+		clean   = `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Port to listen on.", "examples": [8080]}}}`
+		warning = `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Just the port to listen on.", "examples": [8080]}}}`
+		failing = `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Port to listen on."}}}`
+	)
+	cases := []struct {
+		name    string
+		schema  string
+		flags   []string
+		wantErr string
+	}{
+		{name: "clean", schema: clean},
+		{name: "warning", schema: warning},
+		{name: "warning strict", schema: warning, flags: []string{"--strict"}, wantErr: "0 error(s), 1 warning(s)"},
+		{name: "error", schema: failing, wantErr: "1 error(s), 0 warning(s)"},
+		{name: "error json", schema: failing, flags: []string{"--json"}, wantErr: "1 error(s), 0 warning(s)"},
+		{name: "examples not required", schema: failing, flags: []string{"--no-examples"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "schema.json")
+			if err := os.WriteFile(path, []byte(c.schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cmd := newSchemaCheckCmd()
+			cmd.SetArgs(append([]string{path}, c.flags...))
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+
+			err := cmd.Execute()
+			if c.wantErr == "" {
+				if err != nil {
+					t.Fatalf("schema check failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("schema check error = %v, want %q", err, c.wantErr)
+			}
+		})
+	}
+
+	cmd := newSchemaCheckCmd()
+	cmd.SetArgs([]string{filepath.Join(t.TempDir(), "missing.json")})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil {
+		t.Error("schema check passed for a missing schema file")
+	}
+}
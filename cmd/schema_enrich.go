@@ -3,20 +3,31 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/grovetools/docgen/internal/clilog"
 	"github.com/grovetools/docgen/pkg/schema_enricher"
 	"github.com/spf13/cobra"
 )
 
 func newSchemaEnrichCmd() *cobra.Command {
-	var inPlace bool
+	var opts schema_enricher.EnrichOptions
+	var timeout time.Duration
 
 	cmd := &cobra.Command{
-		Use:   "enrich <path/to/schema.json>",
+		Use:   "enrich <path/to/schema>",
 		Short: "Enrich a JSON schema with AI-generated descriptions",
 		Long: `Analyzes a JSON schema file, identifies properties lacking descriptions, and uses an LLM with project context to generate and insert those descriptions.
 
-The enriched schema is printed to stdout unless the --in-place flag is used.`,
+Schemas may be JSON or YAML. Annotated example config files (e.g. grove.yml, grove.toml) are also accepted: keys without a comment get a generated description written as a comment above them. Output keeps the input's format, and existing comments are preserved.
+
+The enriched schema is printed to stdout unless the --in-place flag is used. Use --diff to review the proposed changes as a unified diff, and --interactive to accept or reject each property's change before it is applied.
+
+Large schemas are enriched in batches (see --batch-size). Progress is saved after each batch next to the schema, so a failed run resumes where it stopped when re-run. Use --only to restrict enrichment to specific property paths.
+
+Before calling the LLM, properties are matched to the Go struct fields they were reflected from (by yaml/json tag) and the fields' doc comments are used as descriptions. Definitions in $defs, which reflected schemas reference nested structs through, are matched to the struct they are named after. Only properties without source documentation are sent to the LLM.
+
+With --descriptions, descriptions already in a docgen descriptions store (for example the output of a schema_describe section) are reused, and newly generated ones are saved to it.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			schemaPath := args[0]
@@ -25,12 +36,24 @@ The enriched schema is printed to stdout unless the --in-place flag is used.`,
 				return fmt.Errorf("failed to get current directory: %w", err)
 			}
 
+			ctx, cancel := withTimeout(cmd, timeout)
+			defer cancel()
+
 			enricher := schema_enricher.New(getLogger())
-			return enricher.Enrich(cwd, schemaPath, inPlace)
+			enricher.SetEventLogger(clilog.New(clilog.Component + ".enricher"))
+			return enricher.EnrichContext(ctx, cwd, schemaPath, opts)
 		},
 	}
 
-	cmd.Flags().BoolVar(&inPlace, "in-place", false, "Modify the schema file directly instead of printing to stdout")
+	cmd.Flags().BoolVar(&opts.InPlace, "in-place", false, "Modify the schema file directly instead of printing to stdout")
+	cmd.Flags().BoolVar(&opts.Diff, "diff", false, "Print a unified diff of the proposed changes instead of the full schema")
+	cmd.Flags().BoolVarP(&opts.Interactive, "interactive", "i", false, "Accept or reject each proposed property change")
+	cmd.Flags().StringSliceVar(&opts.Only, "only", nil, "Only enrich these property paths and their children (e.g. settings.model)")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", schema_enricher.DefaultBatchSize, "Number of properties per LLM request")
+	cmd.Flags().BoolVar(&opts.NoResume, "no-resume", false, "Ignore progress saved by a previous failed run")
+	cmd.Flags().StringVar(&opts.DescriptionsStore, "descriptions", "", "Descriptions store to reuse and update (e.g. docs/schema-descriptions.json)")
+	cmd.Flags().BoolVar(&opts.NoSourceComments, "no-source-comments", false, "Do not describe properties from Go struct doc comments")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort after this long, e.g. 10m (0 means no limit)")
 
 	return cmd
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grovetools/docgen/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaReflectCmd() *cobra.Command {
+	var opts schema.ReflectOptions
+
+	cmd := &cobra.Command{
+		Use:   "reflect",
+		Short: "Generate a JSON schema from a Go type",
+		Long: `Reflects a Go struct type into a JSON schema using invopop/jsonschema.
+
+The type is compiled with the current module's dependencies by running a
+temporary program via 'go run', so the module must require
+github.com/invopop/jsonschema. This generalizes tools/schema-generator for any
+type and output path, and can be used from a go:generate directive:
+
+  //go:generate docgen schema reflect --type github.com/org/repo/pkg/config.Config --out schema/config.schema.json`,
+		Example: `  docgen schema reflect --type github.com/grovetools/docgen/pkg/config.DocgenConfig --out schema/docgen.config.schema.json
+  docgen schema reflect --type example.com/svc/config.Config --out config.schema.json --tag json --title "Service Config"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Type == "" || opts.Out == "" {
+				return fmt.Errorf("--type and --out are required")
+			}
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+
+			ulog.Info("Reflecting schema from Go type").
+				Field("type", opts.Type).
+				Field("out", opts.Out).
+				Emit()
+			if err := schema.Reflect(cwd, opts); err != nil {
+				return err
+			}
+			ulog.Success("Schema generated").
+				Field("out", opts.Out).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Type, "type", "", "Fully qualified Go type (<import/path>.<Type>)")
+	cmd.Flags().StringVarP(&opts.Out, "out", "o", "", "Output path for the schema JSON")
+	cmd.Flags().StringVar(&opts.FieldTag, "tag", "yaml", "Struct tag used for property names")
+	cmd.Flags().StringVar(&opts.Title, "title", "", "Schema title")
+	cmd.Flags().StringVar(&opts.Description, "description", "", "Schema description")
+	cmd.Flags().BoolVar(&opts.AdditionalProperties, "additional-properties", true, "Allow properties not declared in the schema")
+	cmd.Flags().BoolVar(&opts.ExpandedStruct, "expanded", true, "Inline the root struct instead of referencing it from $defs")
+
+	return cmd
+}
@@ -18,7 +18,9 @@ func newSyncReadmeCmd() *cobra.Command {
 		Short: "Generate the README.md from a template and a source documentation file",
 		Long: `Synchronizes the project's README.md based on the 'readme' configuration in docs/docgen.config.yml.
 
-This command reads a template file (e.g., README.md.tpl), injects a specified documentation section (like 'introduction') into it, replaces metadata placeholders, and writes the result to the output README.md file.
+This command reads a template file (e.g., README.md.tpl), renders it as a Go text/template, injects a specified documentation section (like 'introduction') into it, and writes the result to the output README.md file.
+
+Templates can reference .Title, .Description, .PackageName, .Version, .RepoURL, .Badges, .Sections and .Config, and use the upper, trimLines and include functions.
 
 It provides a single source of truth for your project's overview, keeping the README in sync with your formal documentation.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -39,7 +41,7 @@ It provides a single source of truth for your project's overview, keeping the RE
 				ulog.Info("Generating source section before sync").
 					Field("section", cfg.Readme.SourceSection).
 					Emit()
-				gen := generator.New(getLogger())
+				gen := newGenerator()
 				opts := generator.GenerateOptions{
 					Sections: []string{cfg.Readme.SourceSection},
 				}
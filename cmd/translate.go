@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/manifest"
+	docgenVersion "github.com/grovetools/docgen/pkg/version"
+	"github.com/grovetools/docgen/pkg/writer"
+	"github.com/spf13/cobra"
+)
+
+func newTranslateCmd() *cobra.Command {
+	var (
+		pkg        string
+		opts       generator.TranslateOptions
+		websiteDir string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "translate",
+		Short: "Translate generated docs into other languages",
+		Long: `Runs each generated markdown section through the LLM with a translation
+prompt and writes the result to docs/{lang}/. Code, commands, paths and link
+targets are kept as-is.
+
+The source hash of every translated section is recorded in
+docs/{lang}/.translations.json; later runs only retranslate sections whose
+source changed. Aggregation copies the translations next to the package's docs
+and lists the languages in the manifest.
+
+With --website-dir the translations are also written to the website through
+the Astro writer, under src/content/docs/{lang}/{package}/.
+
+Examples:
+  docgen translate --lang es,ja                  # Translate every section
+  docgen translate --lang es -s introduction     # One section
+  docgen translate --lang ja --force             # Retranslate everything
+  docgen translate --lang es --website-dir ../grove-website`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageDir, err := resolvePackageDir(pkg)
+			if err != nil {
+				return err
+			}
+
+			results, err := newGenerator().Translate(packageDir, opts)
+			if err != nil {
+				return err
+			}
+
+			if websiteDir != "" {
+				if err := writeTranslations(writer.NewAstro(websiteDir), packageDir, filepath.Base(packageDir)); err != nil {
+					return err
+				}
+			}
+
+			if jsonOutput {
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal translate results: %w", err)
+				}
+				ulog.Info("Translated docs").
+					Field("sections", len(results)).
+					PrettyOnly().
+					Pretty(string(data)).
+					Emit()
+				return nil
+			}
+
+			translated := 0
+			for _, r := range results {
+				if r.Status != generator.TranslationUpdated {
+					continue
+				}
+				translated++
+				ulog.Success("Translated section").
+					Field("section", r.Section).
+					Field("language", r.Language).
+					Field("path", r.Output).
+					Emit()
+			}
+			ulog.Info("Translation complete").
+				Field("translated", translated).
+				Field("unchanged", len(results)-translated).
+				Emit()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&pkg, "package", "p", "", "Workspace package to translate (default: current directory)")
+	cmd.Flags().StringSliceVar(&opts.Languages, "lang", nil, "Target languages as BCP 47 tags (e.g. es,ja)")
+	cmd.Flags().StringSliceVarP(&opts.Sections, "section", "s", nil, "Translate only the specified sections (by name)")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Retranslate sections even if their source is unchanged")
+	cmd.Flags().StringVar(&websiteDir, "website-dir", "", "Also write the translations to this website")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the translate results as JSON")
+	_ = cmd.MarkFlagRequired("lang")
+
+	return cmd
+}
+
+// writeTranslations writes every translated markdown section of the package
+// to the website under its language.
+func writeTranslations(w *writer.AstroWriter, packageDir, pkgName string) error {
+	targets, err := generator.ResolveSectionTargets(packageDir)
+	if err != nil {
+		return err
+	}
+
+	written := make(map[string]bool)
+	for _, t := range targets {
+		for _, lang := range manifest.Languages(t.OutputDir) {
+			filename := filepath.Join(lang, t.Section.Output)
+			content, err := os.ReadFile(filepath.Join(t.OutputDir, filename)) //nolint:gosec // path from config
+			if err != nil || written[filename] {
+				continue
+			}
+			written[filename] = true
+
+			meta := writer.DocMetadata{
+				Title:       t.Section.Title,
+				Description: t.Config.Description,
+				Category:    t.Config.Category,
+				Version:     docgenVersion.Resolve(packageDir),
+				Order:       t.Section.Order,
+				Package:     t.Config.Title,
+				Language:    lang,
+			}
+			transformed, err := w.TransformContent(content, pkgName, meta)
+			if err != nil {
+				return fmt.Errorf("failed to transform %s: %w", filename, err)
+			}
+			if err := w.WriteDoc(pkgName, t.Section.Output, transformed, meta); err != nil {
+				return fmt.Errorf("failed to write %s: %w", filename, err)
+			}
+		}
+	}
+	return nil
+}
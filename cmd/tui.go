@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grovetools/docgen/pkg/progress"
+	"github.com/grovetools/docgen/pkg/tui"
+	"github.com/spf13/cobra"
+)
+
+func newTuiCmd() *cobra.Command {
+	var websiteDir string
+	var mode string
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Open the interactive documentation control center",
+		Long: `Opens a full-screen terminal UI listing every package and its sections
+with their status and staleness: the packages of the ecosystems the current
+directory's docgen config names, or the package in the current directory.
+
+From the list:
+  enter  Generate the selected section, or the stale sections of a package
+  d      Show the selected section's diff: what its last generation changed,
+         or how its doc differs from the committed version
+  p      Promote the selected section (draft to dev, dev to production)
+  w      Start or stop watching, as docgen watch does, with --website-dir
+         and --mode
+  l      Show the full log
+  r      Refresh the list
+  q      Quit
+
+Generation and watch logs are shown in the log pane at the bottom.
+
+Example:
+  docgen tui --website-dir ../grove-website`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !progress.IsTerminal(os.Stdin) || !progress.IsTerminal(os.Stdout) {
+				return fmt.Errorf("docgen tui needs an interactive terminal")
+			}
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			return tui.Run(cmd.Context(), tui.Options{
+				Dir:        cwd,
+				WebsiteDir: websiteDir,
+				Mode:       mode,
+				Logger:     getLogger(),
+			})
+		},
+	}
+
+	defaultMode := os.Getenv("DOCGEN_MODE")
+	if defaultMode == "" {
+		defaultMode = "dev"
+	}
+
+	cmd.Flags().StringVar(&websiteDir, "website-dir", ".", "Path to grove-website root, for watching")
+	cmd.Flags().StringVar(&mode, "mode", defaultMode, "Build mode for watching: dev or prod")
+	return cmd
+}
@@ -1,40 +1,31 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"os"
-	"path/filepath"
-	"sort"
-	"strings"
-	"sync"
 	"time"
 
-	"github.com/fsnotify/fsnotify"
-	coreConfig "github.com/grovetools/core/config"
-	"github.com/grovetools/core/pkg/workspace"
-	"github.com/grovetools/docgen/pkg/config"
-	"github.com/grovetools/docgen/pkg/manifest"
-	"github.com/grovetools/docgen/pkg/transformer"
-	"github.com/grovetools/docgen/pkg/watcher"
-	"github.com/grovetools/docgen/pkg/writer"
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/docgen"
+	"github.com/grovetools/docgen/pkg/report"
+	"github.com/grovetools/docgen/pkg/watch"
 	"github.com/spf13/cobra"
 )
 
-// watchedPackage holds cached information about a package being watched
-type watchedPackage struct {
-	wsPath      string // workspace path (e.g., /path/to/grove-flow)
-	docgenDir   string // docgen dir in notebook (e.g., /path/to/nb/workspaces/flow/docgen)
-	conceptsDir string // concepts dir in notebook (e.g., /path/to/nb/workspaces/flow/concepts)
-	pkgName     string // package name (e.g., "flow")
-	config      *config.DocgenConfig
-}
+var (
+	// ErrInvalidMode is returned for a --mode other than dev or prod.
+	ErrInvalidMode = watch.ErrInvalidMode
+	// ErrNoPackages is returned when no docgen-enabled package with a
+	// notebook docgen directory was found to watch.
+	ErrNoPackages = watch.ErrNoPackages
+)
 
 func newWatchCmd() *cobra.Command {
 	var websiteDir string
 	var mode string
 	var debounceMs int
-	var quiet bool
+	var audiences []string
+	var reports reportFlags
 
 	cmd := &cobra.Command{
 		Use:   "watch",
@@ -50,9 +41,24 @@ The watch command will:
 1. Discover all packages with docgen enabled in configured ecosystems
 2. Watch their notebook docgen directories for changes
 3. On file change, rebuild only the affected package
-4. Write output directly to the Astro content directories`,
+4. Write output directly to the Astro content directories
+
+With --report json, every rebuild emits a run report listing the packages
+rebuilt, with durations and errors: one JSON line per rebuild on stdout, or the
+latest rebuild's report in --report-file.
+
+With the global --log-format json, every watch event is a JSON line on stderr
+with an event key (watch.watching, watch.ready, watch.rebuild.start,
+watch.rebuild.done, watch.rebuild.failed), for tools following the watcher.
+The global --quiet limits output to rebuild failures and other warnings.
+
+With --audience, only sections written for one of the given audiences (see
+the audience field of sections and frontmatter), and sections without one,
+are rebuilt, as docgen aggregate --audience publishes them.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runWatch(websiteDir, mode, time.Duration(debounceMs)*time.Millisecond, quiet)
+			ctx, cancel := withTimeout(cmd, 0)
+			defer cancel()
+			return runWatch(ctx, websiteDir, mode, audiences, time.Duration(debounceMs)*time.Millisecond, &reports)
 		},
 	}
 
@@ -64,810 +70,68 @@ The watch command will:
 
 	cmd.Flags().StringVar(&websiteDir, "website-dir", ".", "Path to grove-website root")
 	cmd.Flags().StringVar(&mode, "mode", defaultMode, "Build mode: dev or prod")
+	cmd.Flags().StringSliceVar(&audiences, "audience", nil, "Rebuild only sections for these audiences (comma-separated) and those without one")
 	cmd.Flags().IntVar(&debounceMs, "debounce", 100, "Debounce interval in milliseconds")
-	cmd.Flags().BoolVar(&quiet, "quiet", false, "Minimal output (for concurrent use with astro)")
+	addReportFlags(cmd, &reports)
 	return cmd
 }
 
-func runWatch(websiteDir, mode string, debounce time.Duration, quiet bool) error {
-	// Validate mode
-	if mode != "dev" && mode != "prod" {
-		return errorf("invalid mode '%s': must be 'dev' or 'prod'", mode)
-	}
-
-	w, err := watcher.New()
-	if err != nil {
-		return errorf("failed to create watcher: %w", err)
-	}
-	defer w.Close() //nolint:errcheck // best-effort close on exit
-
-	// Create Astro writer
-	astroWriter := writer.NewAstro(websiteDir)
-
-	// Load local config to get allowed packages and ecosystems
-	cwd, _ := os.Getwd()
-	localCfg, _, _ := config.LoadWithNotebook(cwd)
-
-	// Build set of allowed packages from sidebar config
-	allowedPackages := make(map[string]bool)
-	if localCfg != nil && localCfg.Sidebar != nil && localCfg.Sidebar.Categories != nil {
-		for _, cat := range localCfg.Sidebar.Categories {
-			for _, pkg := range cat.Packages {
-				allowedPackages[pkg] = true
-			}
-		}
-	}
-
-	// Discover packages and set up recursive watching
-	watchedPkgs := make(map[string]*watchedPackage) // docgenDir -> package info
-
-	ecosystems, err := discoverEcosystems(localCfg)
-	if err != nil {
-		return errorf("failed to discover ecosystems: %w", err)
-	}
-
-	// Load core config for notebook locator
-	coreCfg, err := coreConfig.LoadDefault()
-	if err != nil {
-		return errorf("failed to load core config: %w", err)
-	}
-	locator := workspace.NewNotebookLocator(coreCfg)
-
-	for _, eco := range ecosystems {
-		if err := setupWatchForEcosystem(eco, w, locator, allowedPackages, watchedPkgs, quiet); err != nil {
-			if !quiet {
-				ulog.Warn("Failed to setup watch for ecosystem").Field("ecosystem", eco.Name).Err(err).Emit()
-			}
-		}
-	}
-
-	if len(watchedPkgs) == 0 {
-		return errorf("no packages found to watch")
-	}
-
-	if !quiet {
-		ulog.Info("Watching for documentation changes").
-			Field("mode", mode).
-			Field("website", websiteDir).
-			Field("packages", len(watchedPkgs)).
-			Emit()
-	}
-
-	// Debounce state
-	var mu sync.Mutex
-	pending := make(map[string]bool) // docgenDir -> needs rebuild
-	var timer *time.Timer
-
-	// Track whether changes are to concepts or regular docs
-	pendingConcepts := make(map[string]bool) // docgenDir -> needs concept rebuild
-
-	processPending := func() {
-		mu.Lock()
-		toProcess := pending
-		toProcessConcepts := pendingConcepts
-		pending = make(map[string]bool)
-		pendingConcepts = make(map[string]bool)
-		mu.Unlock()
-
-		for docgenDir := range toProcess {
-			pkg := watchedPkgs[docgenDir]
-			if pkg == nil {
-				continue
-			}
-
-			if !quiet {
-				ulog.Info("Rebuilding").Field("package", pkg.pkgName).Emit()
-			}
-
-			if err := rebuildPackage(pkg, astroWriter, mode, localCfg, quiet); err != nil {
-				ulog.Error("Rebuild failed").Field("package", pkg.pkgName).Err(err).Emit()
-			} else if !quiet {
-				ulog.Info("Done").Field("package", pkg.pkgName).Emit()
-			}
-		}
-
-		for docgenDir := range toProcessConcepts {
-			pkg := watchedPkgs[docgenDir]
-			if pkg == nil {
-				continue
-			}
-
-			if !quiet {
-				ulog.Info("Rebuilding concepts").Field("package", pkg.pkgName).Emit()
-			}
-
-			if err := rebuildConcepts(pkg, astroWriter, mode, quiet); err != nil {
-				ulog.Error("Concept rebuild failed").Field("package", pkg.pkgName).Err(err).Emit()
-			} else if !quiet {
-				ulog.Info("Concepts done").Field("package", pkg.pkgName).Emit()
-			}
-		}
-	}
-
-	// Main event loop
-	for {
-		select {
-		case event, ok := <-w.Events:
-			if !ok {
-				return nil
-			}
-
-			// Handle new directory creation (add to watcher)
-			if event.Has(fsnotify.Create) {
-				wsPath := w.FindWorkspace(event.Name)
-				if wsPath != "" {
-					w.HandleNewDirectory(event, wsPath)
-				}
-			}
-
-			// Only process write and create events for relevant files
-			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
-				continue
-			}
-
-			// Check if it's a relevant file
-			if !watcher.IsRelevantFile(event.Name) {
-				// Also handle config file changes
-				if filepath.Base(event.Name) != "docgen.config.yml" {
-					continue
-				}
-			}
-
-			// Find the docgen directory this file belongs to
-			docgenDir := findDocgenDir(event.Name, watchedPkgs)
-			if docgenDir == "" {
-				continue
-			}
-
-			// Queue for debounced processing
-			mu.Lock()
-			if isConceptFile(event.Name, watchedPkgs) {
-				pendingConcepts[docgenDir] = true
-			} else {
-				pending[docgenDir] = true
-			}
-			if timer != nil {
-				timer.Stop()
-			}
-			timer = time.AfterFunc(debounce, processPending)
-			mu.Unlock()
-
-		case err, ok := <-w.Errors:
-			if !ok {
-				return nil
-			}
-			ulog.Error("Watcher error").Err(err).Emit()
-		}
-	}
-}
-
-// discoverEcosystems returns the ecosystems to process based on config
-func discoverEcosystems(localCfg *config.DocgenConfig) ([]workspace.Ecosystem, error) {
-	discoveryService := workspace.NewDiscoveryService(getLogger())
-	result, err := discoveryService.DiscoverAll()
-	if err != nil {
-		return nil, err
-	}
-
-	if localCfg != nil && len(localCfg.Settings.Ecosystems) > 0 {
-		// Filter to configured ecosystems
-		ecoByName := make(map[string]workspace.Ecosystem)
-		for _, eco := range result.Ecosystems {
-			ecoByName[eco.Name] = eco
-		}
-
-		var filtered []workspace.Ecosystem
-		for _, name := range localCfg.Settings.Ecosystems {
-			if eco, ok := ecoByName[name]; ok {
-				filtered = append(filtered, eco)
-			}
-		}
-		return filtered, nil
-	}
-
-	return result.Ecosystems, nil
-}
-
-// setupWatchForEcosystem sets up file watchers for all docgen-enabled packages in an ecosystem
-func setupWatchForEcosystem(
-	eco workspace.Ecosystem,
-	w *watcher.RecursiveWatcher,
-	locator *workspace.NotebookLocator,
-	allowedPackages map[string]bool,
-	watchedPkgs map[string]*watchedPackage,
-	quiet bool,
-) error {
-	// Load ecosystem config to get workspace paths
-	configPath, err := coreConfig.FindConfigFile(eco.Path)
-	if err != nil {
-		return fmt.Errorf("could not find config file in %s: %w", eco.Path, err)
-	}
-	cfg, err := coreConfig.Load(configPath)
-	if err != nil {
-		return err
-	}
-
-	// Get workspace paths from config (expand glob patterns)
-	var workspaces []string
-	for _, wsPattern := range cfg.Workspaces {
-		pattern := filepath.Join(eco.Path, wsPattern)
-		matches, err := filepath.Glob(pattern)
-		if err != nil {
-			continue
-		}
-		for _, match := range matches {
-			if info, err := os.Stat(match); err == nil && info.IsDir() {
-				workspaces = append(workspaces, match)
-			}
-		}
-	}
-
-	for _, wsPath := range workspaces {
-		wsName := filepath.Base(wsPath)
-
-		// Load docgen config - try notebook location first, then repo
-		docCfg, _, err := config.LoadWithNotebook(wsPath)
-		if err != nil || docCfg == nil || !docCfg.Enabled {
-			continue
-		}
-
-		// Skip packages not in allowed set (if filtering)
-		if len(allowedPackages) > 0 && !allowedPackages[wsName] {
-			if docCfg.Settings.OutputMode != "sections" {
-				continue
-			}
-		}
-
-		// Get workspace node for notebook locator
-		node, err := workspace.GetProjectByPath(wsPath)
-		if err != nil {
-			continue
-		}
-
-		// Get docgen directory in notebook
-		docgenDir, err := locator.GetDocgenDir(node)
-		if err != nil {
-			continue
-		}
-
-		// Check if docgen dir exists
-		if _, err := os.Stat(docgenDir); os.IsNotExist(err) {
-			continue
-		}
-
-		// Add recursive watch for docgen directory
-		if err := w.AddRecursive(docgenDir, wsPath); err != nil {
-			if !quiet {
-				ulog.Warn("Failed to watch").Field("package", wsName).Err(err).Emit()
-			}
-			continue
-		}
-
-		// Also watch concepts directory if it exists
-		// concepts is at the same level as docgen: {notebook}/workspaces/{name}/concepts/
-		workspaceDir := filepath.Dir(docgenDir)
-		conceptsDir := filepath.Join(workspaceDir, "concepts")
-		if _, err := os.Stat(conceptsDir); err == nil {
-			if err := w.AddRecursive(conceptsDir, wsPath); err != nil {
-				if !quiet {
-					ulog.Warn("Failed to watch concepts").Field("package", wsName).Err(err).Emit()
-				}
-			} else if !quiet {
-				ulog.Info("Watching concepts").Field("package", wsName).Field("dir", conceptsDir).Emit()
-			}
-		}
-
-		watchedPkgs[docgenDir] = &watchedPackage{
-			wsPath:      wsPath,
-			docgenDir:   docgenDir,
-			conceptsDir: conceptsDir,
-			pkgName:     wsName,
-			config:      docCfg,
-		}
-
-		if !quiet {
-			ulog.Info("Watching").Field("package", wsName).Field("dir", docgenDir).Emit()
-		}
-	}
-
-	return nil
-}
-
-// findDocgenDir finds the docgen directory that contains the given file path
-func findDocgenDir(filePath string, watchedPkgs map[string]*watchedPackage) string {
-	for docgenDir, pkg := range watchedPkgs {
-		if strings.HasPrefix(filePath, docgenDir) {
-			return docgenDir
-		}
-		// Also check concepts directory
-		if pkg.conceptsDir != "" && strings.HasPrefix(filePath, pkg.conceptsDir) {
-			return docgenDir // Return docgenDir as the key
-		}
-	}
-	return ""
-}
-
-// isConceptFile checks if a file path is within a concepts directory
-func isConceptFile(filePath string, watchedPkgs map[string]*watchedPackage) bool {
-	for _, pkg := range watchedPkgs {
-		if pkg.conceptsDir != "" && strings.HasPrefix(filePath, pkg.conceptsDir) {
-			return true
-		}
-	}
-	return false
-}
-
-// rebuildPackage rebuilds a single package and writes to the website
-func rebuildPackage(pkg *watchedPackage, w *writer.AstroWriter, mode string, localCfg *config.DocgenConfig, quiet bool) error {
-	// Reload config in case it changed - try notebook location first
-	docCfg, _, err := config.LoadWithNotebook(pkg.wsPath)
-	if err != nil || docCfg == nil {
+func runWatch(ctx context.Context, websiteDir, mode string, audiences []string, debounce time.Duration, reports *reportFlags) error {
+	// Validate the report format up front; each rebuild gets its own report
+	if _, err := reports.recorder("watch"); err != nil {
 		return err
 	}
 
-	// Handle "sections" output mode (website content like overview, concepts)
-	if docCfg.Settings.OutputMode == "sections" {
-		return rebuildWebsiteSections(pkg, w, mode, docCfg, localCfg, quiet)
-	}
-
-	// Filter sections by status
-	sectionsToProcess := make([]config.SectionConfig, 0, len(docCfg.Sections))
-	for _, section := range docCfg.Sections {
-		status := section.GetStatus()
-		if status == config.StatusDraft {
-			continue
-		}
-		if mode == "prod" && status == config.StatusDev {
-			continue
-		}
-		sectionsToProcess = append(sectionsToProcess, section)
-	}
-
-	if len(sectionsToProcess) == 0 {
-		return nil
-	}
-
-	// Sort sections by order
-	sort.Slice(sectionsToProcess, func(i, j int) bool {
-		return sectionsToProcess[i].Order < sectionsToProcess[j].Order
-	})
-
-	// Get version from git
-	version := getPackageVersion(pkg.wsPath)
-
-	// Process each section
-	docsDir := filepath.Join(pkg.docgenDir, "docs")
-	for i, section := range sectionsToProcess {
-		srcFile := filepath.Join(docsDir, section.Output)
-		content, err := os.ReadFile(srcFile)
-		if err != nil {
-			if !quiet {
-				ulog.Warn("Could not read section").
-					Field("package", pkg.pkgName).
-					Field("section", section.Output).
-					Err(err).Emit()
+	// Each debounced batch of rebuilds is reported on its own
+	var rec *report.Recorder
+	hooks := docgen.WatchHooks{
+		OnRebuildStart: func(ev docgen.WatchEvent) {
+			if rec == nil {
+				rec, _ = reports.recorder("watch")
 			}
-			continue
-		}
-
-		// Apply strip lines if configured
-		if section.AggStripLines > 0 {
-			lines := strings.Split(string(content), "\n")
-			if len(lines) > section.AggStripLines {
-				content = []byte(strings.Join(lines[section.AggStripLines:], "\n"))
+			name := ""
+			if ev.Kind == watch.KindConcepts {
+				name = "concepts"
 			}
-		}
-
-		meta := writer.DocMetadata{
-			Title:       section.Title,
-			Description: docCfg.Description,
-			Category:    docCfg.Category,
-			Version:     version,
-			Order:       i + 1,
-			Package:     docCfg.Title,
-		}
-
-		transformed, err := w.TransformContent(content, pkg.pkgName, meta)
-		if err != nil {
-			continue
-		}
-
-		if err := w.WriteDoc(pkg.pkgName, section.Output, transformed, meta); err != nil {
-			ulog.Error("Failed to write doc").Field("package", pkg.pkgName).Field("file", section.Output).Err(err).Emit()
-		}
-	}
-
-	// Copy assets
-	copyAssets(pkg.docgenDir, pkg.pkgName, w)
-
-	// Copy additional logos from config
-	copyLogos(docCfg.Logos, pkg.pkgName, w)
-
-	// Update manifest sidebar entry
-	updateManifestSidebar(pkg.pkgName, docCfg, mode, w, localCfg)
-
-	return nil
-}
-
-// rebuildConcepts rebuilds concepts for a package
-func rebuildConcepts(pkg *watchedPackage, w *writer.AstroWriter, mode string, quiet bool) error {
-	if pkg.conceptsDir == "" {
-		return nil
-	}
-
-	if _, err := os.Stat(pkg.conceptsDir); os.IsNotExist(err) {
-		return nil
-	}
-
-	// Reload config
-	docCfg, _, err := config.LoadWithNotebook(pkg.wsPath)
-	if err != nil || docCfg == nil {
-		return err
-	}
-
-	// Scan for concept subdirectories
-	entries, err := os.ReadDir(pkg.conceptsDir)
-	if err != nil {
-		return err
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		conceptID := entry.Name()
-		conceptDir := filepath.Join(pkg.conceptsDir, conceptID)
-
-		// Read concept manifest
-		manifestPath := filepath.Join(conceptDir, "concept-manifest.yml")
-		manifestData, err := os.ReadFile(manifestPath)
-		if err != nil {
-			continue
-		}
-
-		// Parse manifest (simple YAML parsing)
-		var title, publish string
-		var docgenOrder []string
-		for _, line := range strings.Split(string(manifestData), "\n") {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "title:") {
-				title = strings.Trim(strings.TrimPrefix(line, "title:"), " \"'")
-			} else if strings.HasPrefix(line, "docgen_publish:") {
-				publish = strings.TrimSpace(strings.Split(strings.TrimPrefix(line, "docgen_publish:"), "#")[0])
-			} else if strings.HasPrefix(line, "- ") && len(docgenOrder) > 0 || strings.HasPrefix(line, "docgen_order:") {
-				if strings.HasPrefix(line, "- ") {
-					docgenOrder = append(docgenOrder, strings.TrimPrefix(line, "- "))
-				}
-			}
-		}
-
-		// Check publish status
-		if publish == "" {
-			publish = config.StatusDraft
-		}
-		if publish == config.StatusDraft {
-			continue
-		}
-		if mode == "prod" && publish == config.StatusDev {
-			continue
-		}
-
-		if !quiet {
-			ulog.Info("Rebuilding concept").Field("package", pkg.pkgName).Field("concept", conceptID).Emit()
-		}
-
-		// Get list of .md files to process
-		var mdFiles []string
-		if len(docgenOrder) > 0 {
-			for _, f := range docgenOrder {
-				path := filepath.Join(conceptDir, f)
-				if _, err := os.Stat(path); err == nil {
-					mdFiles = append(mdFiles, path)
-				}
-			}
-		} else {
-			mdFiles, _ = filepath.Glob(filepath.Join(conceptDir, "*.md"))
-		}
-
-		// Process each .md file
-		for i, mdPath := range mdFiles {
-			mdFile := filepath.Base(mdPath)
-			content, err := os.ReadFile(mdPath)
-			if err != nil {
-				continue
-			}
-
-			// Strip existing frontmatter
-			body := stripFrontmatter(string(content))
-
-			// Generate title from filename
-			docTitle := formatConceptDocTitle(strings.TrimSuffix(mdFile, ".md"))
-
-			// Calculate order
-			order := 2000 + i + 1
-
-			// Build new content with frontmatter
-			newContent := fmt.Sprintf(`---
-title: "%s"
-package: "%s"
-category: "%s"
-order: %d
-concept_title: "%s"
-concept_id: "%s"
----
-
-%s`, docTitle, pkg.pkgName, docCfg.Category, order, title, conceptID, body)
-
-			// Write to website
-			destPath := filepath.Join(w.WebsiteDir(), "src/content/docs", pkg.pkgName, "concepts", conceptID, mdFile)
-			if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
-				continue
-			}
-			if err := os.WriteFile(destPath, []byte(newContent), 0o644); err != nil {
-				ulog.Error("Failed to write concept doc").Field("file", destPath).Err(err).Emit()
+			rec.StartSection(ev.Package, name, ev.Kind, "")
+			ulog.Info("Rebuilding").Event(clilog.EventWatchRebuildStart).
+				Field("package", ev.Package).Field("kind", ev.Kind).Emit()
+		},
+		OnRebuild: func(ev docgen.WatchEvent) {
+			if ev.Err != nil {
+				ulog.Error("Rebuild failed").Event(clilog.EventWatchRebuildFailed).
+					Field("package", ev.Package).Field("kind", ev.Kind).Err(ev.Err).Emit()
+				rec.FailSection(ev.Err)
+				return
+			}
+			rec.EndSection()
+			ulog.Info("Done").Event(clilog.EventWatchRebuildDone).
+				Field("package", ev.Package).Field("kind", ev.Kind).Emit()
+		},
+		OnBatch: func([]docgen.WatchEvent) {
+			if err := reports.stream(rec); err != nil {
+				ulog.Error("Failed to write run report").Err(err).Emit()
 			}
-		}
-	}
-
-	return nil
-}
-
-// stripFrontmatter removes YAML frontmatter from markdown content
-func stripFrontmatter(content string) string {
-	if !strings.HasPrefix(content, "---\n") {
-		return content
-	}
-	end := strings.Index(content[4:], "\n---")
-	if end == -1 {
-		return content
+			rec = nil
+		},
+		OnWatching: func(pkg, dir string) {
+			ulog.Info("Watching").Event(clilog.EventWatchWatching).Field("package", pkg).Field("dir", dir).Emit()
+		},
+		OnReady: func(packages int) {
+			ulog.Info("Watching for documentation changes").Event(clilog.EventWatchReady).
+				Field("mode", mode).
+				Field("website", websiteDir).
+				Field("packages", packages).
+				Emit()
+		},
 	}
-	return strings.TrimLeft(content[end+8:], "\n")
-}
 
-// formatConceptDocTitle formats a filename into a title
-func formatConceptDocTitle(name string) string {
-	parts := strings.FieldsFunc(name, func(r rune) bool {
-		return r == '-' || r == '_'
+	return docgen.Watch(ctx, docgen.WatchOptions{
+		WebsiteDir: websiteDir,
+		Mode:       mode,
+		Debounce:   debounce,
+		Audiences:  audiences,
+		Logger:     getLogger(),
+		Hooks:      hooks,
 	})
-
-	acronyms := map[string]string{
-		"cli": "CLI", "tui": "TUI", "api": "API",
-		"ui": "UI", "id": "ID", "llm": "LLM",
-	}
-
-	for i, part := range parts {
-		lower := strings.ToLower(part)
-		if acronym, ok := acronyms[lower]; ok {
-			parts[i] = acronym
-		} else if len(part) > 0 {
-			parts[i] = strings.ToUpper(string(part[0])) + part[1:]
-		}
-	}
-
-	return strings.Join(parts, " ")
-}
-
-// rebuildWebsiteSections handles output_mode: sections (overview, concepts)
-// Discovers section subdirectories with their own docgen.config.yml and processes them.
-func rebuildWebsiteSections(pkg *watchedPackage, w *writer.AstroWriter, mode string, docCfg *config.DocgenConfig, localCfg *config.DocgenConfig, quiet bool) error {
-	// Discover section subdirectories that have their own docgen.config.yml
-	entries, err := os.ReadDir(pkg.docgenDir)
-	if err != nil {
-		return err
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		sectionName := entry.Name()
-		sectionDir := filepath.Join(pkg.docgenDir, sectionName)
-
-		// Check if this subdirectory has its own docgen.config.yml
-		sectionConfigPath := filepath.Join(sectionDir, config.ConfigFileName)
-		if _, err := os.Stat(sectionConfigPath); os.IsNotExist(err) {
-			continue // Not a section directory
-		}
-
-		// Load the section's config
-		sectionCfg, err := config.LoadFromPath(sectionConfigPath)
-		if err != nil {
-			continue
-		}
-
-		if !sectionCfg.Enabled {
-			continue
-		}
-
-		// Resolve docs directory
-		docsSubdir := "docs"
-		if sectionCfg.Settings.OutputDir != "" {
-			docsSubdir = sectionCfg.Settings.OutputDir
-		}
-		docsDir := filepath.Join(sectionDir, docsSubdir)
-
-		// Process sections from the section's config
-		for _, sec := range sectionCfg.Sections {
-			status := sec.GetStatus()
-
-			if status == config.StatusDraft {
-				continue
-			}
-			if mode == "prod" && status == config.StatusDev {
-				continue
-			}
-
-			srcPath := filepath.Join(docsDir, sec.Output)
-			content, err := os.ReadFile(srcPath)
-			if err != nil {
-				continue
-			}
-
-			// Transform content (rewrite paths) using central transformer
-			transformed := transformWebsiteSection(content, sectionName, sectionCfg.Category)
-
-			// Write to website content collection
-			destPath := filepath.Join(w.WebsiteDir(), "src/content", sectionName, sec.Output)
-			if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
-				continue
-			}
-			if err := os.WriteFile(destPath, transformed, 0o644); err != nil {
-				ulog.Error("Failed to write section file").Field("file", destPath).Err(err).Emit()
-			}
-		}
-
-		// Copy assets for this section
-		copyWebsiteSectionAssets(sectionDir, sectionName, w)
-	}
-
-	return nil
-}
-
-// transformWebsiteSection transforms paths and augments frontmatter for website section content
-// using the central transformer package for consistency with aggregate command.
-func transformWebsiteSection(content []byte, sectionName, category string) []byte {
-	trans := transformer.NewAstroTransformer()
-	opts := transformer.TransformOptions{
-		SectionName: sectionName,
-		Category:    category,
-	}
-	return trans.TransformWebsiteSection(content, opts)
-}
-
-// copyAssets copies images, asciicasts, and videos to the website public directory
-func copyAssets(docgenDir, pkgName string, w *writer.AstroWriter) {
-	assetTypes := []string{"images", "asciicasts", "videos"}
-	for _, assetType := range assetTypes {
-		srcDir := filepath.Join(docgenDir, assetType)
-		if _, err := os.Stat(srcDir); os.IsNotExist(err) {
-			continue
-		}
-
-		_ = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil || info.IsDir() {
-				return nil
-			}
-			data, err := os.ReadFile(path)
-			if err != nil {
-				return nil
-			}
-			filename := filepath.Base(path)
-			_ = w.WriteAsset(pkgName, assetType, filename, data)
-			return nil
-		})
-	}
-}
-
-// copyLogos copies additional logo files specified in the logos: config
-func copyLogos(logos []string, pkgName string, w *writer.AstroWriter) {
-	for _, logoPath := range logos {
-		// Expand ~ in path
-		expandedPath := expandHomePath(logoPath)
-		data, err := os.ReadFile(expandedPath)
-		if err != nil {
-			ulog.Warn("Could not read logo file").Field("path", expandedPath).Err(err).Emit()
-			continue
-		}
-		filename := filepath.Base(expandedPath)
-		_ = w.WriteAsset(pkgName, "images", filename, data)
-	}
-}
-
-// expandHomePath expands ~ to user home directory
-func expandHomePath(p string) string {
-	if strings.HasPrefix(p, "~/") {
-		home, err := os.UserHomeDir()
-		if err == nil {
-			return filepath.Join(home, p[2:])
-		}
-	}
-	return p
-}
-
-// copyWebsiteSectionAssets copies assets for a website section
-func copyWebsiteSectionAssets(srcDir, sectionName string, w *writer.AstroWriter) {
-	assetTypes := []string{"images", "asciicasts", "videos"}
-	for _, assetType := range assetTypes {
-		assetDir := filepath.Join(srcDir, assetType)
-		if _, err := os.Stat(assetDir); os.IsNotExist(err) {
-			continue
-		}
-
-		_ = filepath.Walk(assetDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil || info.IsDir() {
-				return nil
-			}
-			data, err := os.ReadFile(path)
-			if err != nil {
-				return nil
-			}
-			filename := filepath.Base(path)
-			_ = w.WriteAsset(sectionName, assetType, filename, data)
-			return nil
-		})
-	}
-}
-
-// updateManifestSidebar updates the manifest with sidebar info for incremental builds
-func updateManifestSidebar(pkgName string, docCfg *config.DocgenConfig, mode string, w *writer.AstroWriter, localCfg *config.DocgenConfig) {
-	// Read existing manifest
-	manifestPath := filepath.Join(w.WebsiteDir(), "docgen-output/manifest.json")
-	data, err := os.ReadFile(manifestPath)
-	if err != nil {
-		return // Manifest doesn't exist yet, will be created by full aggregate
-	}
-
-	var m manifest.Manifest
-	if err := json.Unmarshal(data, &m); err != nil {
-		return
-	}
-
-	// Update or add the package in the manifest
-	// This is a simplified update - a full rebuild via aggregate is more accurate
-	// but this provides basic sidebar consistency during watch
-
-	// Save updated manifest
-	data, err = json.MarshalIndent(m, "", "  ")
-	if err != nil {
-		return
-	}
-	_ = os.WriteFile(manifestPath, data, 0o644)
-}
-
-// getPackageVersion gets version from git tags
-func getPackageVersion(wsPath string) string {
-	// Try git describe first
-	// Simplified - in production this would use exec.Command
-	return "latest"
-}
-
-// errorf creates a formatted error
-func errorf(format string, args ...interface{}) error {
-	return &watchError{msg: formatString(format, args...)}
-}
-
-type watchError struct {
-	msg string
-}
-
-func (e *watchError) Error() string {
-	return e.msg
-}
-
-func formatString(format string, args ...interface{}) string {
-	if len(args) == 0 {
-		return format
-	}
-	// Simple formatting - in production use fmt.Sprintf
-	result := format
-	for _, arg := range args {
-		if err, ok := arg.(error); ok {
-			result = strings.Replace(result, "%w", err.Error(), 1)
-		} else if s, ok := arg.(string); ok {
-			result = strings.Replace(result, "%s", s, 1)
-		}
-	}
-	return result
 }
@@ -0,0 +1,386 @@
+// Package clilog is docgen's logging facade. Commands and packages log
+// through it instead of configuring logrus or grove's unified logger
+// themselves, so the global --quiet, --verbose and --log-format flags apply
+// to every line docgen prints:
+//
+//   - Logger is the logrus logger handed to the packages (generator,
+//     aggregator, watch, ...) for their progress and debug lines.
+//   - New returns an event logger with the unified logger's builder API
+//     (Info, Success, Field, Pretty, Emit, ...), used for the user-facing
+//     messages of the commands.
+//
+// In text format, events are rendered by grove's unified logger as before.
+// In json format, every event and log line is one JSON object on stderr
+// with time, level, msg and component keys and the entry's fields; typed
+// events (see Entry.Event and the Event constants) carry an event key so
+// tools can follow watch and aggregate runs. Command output, such as a
+// table or a --json document (an entry with PrettyOnly and Pretty), is
+// still printed as is on stdout.
+package clilog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/grovetools/core/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Component is the component name docgen logs under.
+const Component = "grove-docgen"
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Verbosity levels.
+const (
+	Quiet   = -1 // Warnings, errors and command output only
+	Normal  = 0
+	Verbose = 1 // Debug lines too
+)
+
+// Options configures the facade.
+type Options struct {
+	Verbosity int
+	Format    string // FormatText (default) or FormatJSON
+}
+
+var (
+	mu      sync.RWMutex
+	current = Options{Format: FormatText}
+	std     = logging.NewLogger(Component)
+	// stdout receives command output in json format, or while captured (see
+	// Capture).
+	stdout io.Writer = os.Stdout
+)
+
+// Logger returns the logrus logger the packages log to.
+func Logger() *logrus.Logger {
+	return std.Logger
+}
+
+// Configure applies opts to the logger and to every event logger.
+func Configure(opts Options) error {
+	if opts.Format == "" {
+		opts.Format = FormatText
+	}
+	if opts.Format != FormatText && opts.Format != FormatJSON {
+		return fmt.Errorf("invalid log format %q: must be 'text' or 'json'", opts.Format)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current = opts
+
+	logger := Logger()
+	logger.SetLevel(threshold(opts.Verbosity))
+	if opts.Format == FormatJSON {
+		logger.SetOutput(os.Stderr)
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	}
+	return nil
+}
+
+// StatusLine is a line kept below the log output on a terminal, such as a
+// progress bar: it is cleared before each log line is written and redrawn
+// after it.
+type StatusLine interface {
+	Clear()
+	Redraw()
+}
+
+// status is the active status line, or nil; statusOut is the logger output
+// it replaced.
+var (
+	status    StatusLine
+	statusOut io.Writer
+)
+
+// SetStatusLine keeps s below the log output until SetStatusLine(nil).
+func SetStatusLine(s StatusLine) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger := Logger()
+	if statusOut != nil {
+		logger.SetOutput(statusOut)
+		statusOut = nil
+	}
+	status = s
+	if s != nil {
+		statusOut = logger.Out
+		logger.SetOutput(statusWriter{w: statusOut, s: s})
+	}
+}
+
+// statusWriter writes log lines above a status line.
+type statusWriter struct {
+	w io.Writer
+	s StatusLine
+}
+
+func (w statusWriter) Write(p []byte) (int, error) {
+	w.s.Clear()
+	defer w.s.Redraw()
+	return w.w.Write(p)
+}
+
+// capture is the writer that receives the logs and command output instead
+// of the terminal while a full-screen UI runs (see Capture), or nil.
+var capture io.Writer
+
+// Capture sends every log line and event, and command output, to w as plain
+// text lines until the returned function is called, for a full-screen UI
+// that shows them in a pane.
+func Capture(w io.Writer) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger := Logger()
+	out, formatter, prevStdout := logger.Out, logger.Formatter, stdout
+	logger.SetOutput(w)
+	logger.SetFormatter(&logrus.TextFormatter{DisableColors: true, FullTimestamp: true, TimestampFormat: "15:04:05"})
+	stdout = w
+	capture = w
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		logger.SetOutput(out)
+		logger.SetFormatter(formatter)
+		stdout = prevStdout
+		capture = nil
+	}
+}
+
+// Current returns the facade's options.
+func Current() Options {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// threshold returns the least severe level shown at a verbosity.
+func threshold(verbosity int) logrus.Level {
+	switch {
+	case verbosity < Normal:
+		return logrus.WarnLevel
+	case verbosity > Normal:
+		return logrus.DebugLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// EventLogger logs a component's user-facing events. It is a drop-in for
+// grove's unified logger. A nil EventLogger discards its events, so packages
+// can hold one that only the commands set.
+type EventLogger struct {
+	component string
+	unified   *logging.UnifiedLogger
+}
+
+// New returns the event logger of a component.
+func New(component string) *EventLogger {
+	return &EventLogger{component: component, unified: logging.NewUnifiedLogger(component)}
+}
+
+// Entry kinds beyond the logrus level, rendered with their own icon in text
+// format and recorded as a status field in json format.
+const (
+	kindPlain    = ""
+	kindSuccess  = "success"
+	kindProgress = "progress"
+)
+
+func (l *EventLogger) entry(level logrus.Level, kind, msg string) *Entry {
+	return &Entry{logger: l, level: level, kind: kind, msg: msg, fields: logrus.Fields{}}
+}
+
+// Debug returns an entry shown only with --verbose.
+func (l *EventLogger) Debug(msg string) *Entry {
+	return l.entry(logrus.DebugLevel, kindPlain, msg)
+}
+
+// Info returns an informational entry.
+func (l *EventLogger) Info(msg string) *Entry { return l.entry(logrus.InfoLevel, kindPlain, msg) }
+
+// Success returns an entry for something that completed.
+func (l *EventLogger) Success(msg string) *Entry {
+	return l.entry(logrus.InfoLevel, kindSuccess, msg)
+}
+
+// Progress returns an entry for something in progress.
+func (l *EventLogger) Progress(msg string) *Entry {
+	return l.entry(logrus.InfoLevel, kindProgress, msg)
+}
+
+// Warn returns a warning entry; shown even with --quiet.
+func (l *EventLogger) Warn(msg string) *Entry { return l.entry(logrus.WarnLevel, kindPlain, msg) }
+
+// Error returns an error entry; shown even with --quiet.
+func (l *EventLogger) Error(msg string) *Entry {
+	return l.entry(logrus.ErrorLevel, kindPlain, msg)
+}
+
+// Entry is an event being built; Emit writes it.
+type Entry struct {
+	logger     *EventLogger
+	level      logrus.Level
+	kind       string
+	msg        string
+	event      string
+	fields     logrus.Fields
+	err        error
+	icon       string
+	pretty     string
+	prettyOnly bool
+	structOnly bool
+}
+
+// Field adds a field.
+func (e *Entry) Field(key string, value any) *Entry {
+	e.fields[key] = value
+	return e
+}
+
+// Fields adds fields.
+func (e *Entry) Fields(fields map[string]any) *Entry {
+	for k, v := range fields {
+		e.fields[k] = v
+	}
+	return e
+}
+
+// Err attaches an error, recorded as the error field.
+func (e *Entry) Err(err error) *Entry {
+	if err != nil {
+		e.err = err
+		e.fields["error"] = err.Error()
+	}
+	return e
+}
+
+// Event types the entry, for tools reading json-format logs.
+func (e *Entry) Event(event string) *Entry {
+	e.event = event
+	return e
+}
+
+// Icon overrides the entry's icon in text format.
+func (e *Entry) Icon(icon string) *Entry {
+	e.icon = icon
+	return e
+}
+
+// Pretty sets the text shown in text format instead of the message.
+func (e *Entry) Pretty(text string) *Entry {
+	e.pretty = text
+	return e
+}
+
+// PrettyOnly leaves the entry out of structured logs. With Pretty it marks
+// command output, which json format prints as is.
+func (e *Entry) PrettyOnly() *Entry {
+	e.prettyOnly = true
+	return e
+}
+
+// StructuredOnly leaves the entry out of text output.
+func (e *Entry) StructuredOnly() *Entry {
+	e.structOnly = true
+	return e
+}
+
+// output reports whether the entry is command output rather than a log
+// line; output is printed whatever the verbosity.
+func (e *Entry) output() bool {
+	return e.prettyOnly && e.pretty != ""
+}
+
+// Emit writes the entry as the facade is configured.
+func (e *Entry) Emit() {
+	if e.logger == nil {
+		return
+	}
+	opts := Current()
+	if e.level > threshold(opts.Verbosity) && !e.output() {
+		return
+	}
+	mu.RLock()
+	s, captured, out := status, capture != nil, stdout
+	mu.RUnlock()
+	if s != nil {
+		s.Clear()
+		defer s.Redraw()
+	}
+	if opts.Format == FormatJSON || captured {
+		e.emitStructured(out)
+		return
+	}
+	e.emitText()
+}
+
+// emitStructured writes the entry as a line through the logger, or command
+// output as is to out: JSON lines in json format, plain lines while
+// captured.
+func (e *Entry) emitStructured(out io.Writer) {
+	if e.output() {
+		fmt.Fprintln(out, e.pretty)
+		return
+	}
+	if e.prettyOnly {
+		return
+	}
+	entry := Logger().WithFields(e.fields).WithField("component", e.logger.component)
+	if e.event != "" {
+		entry = entry.WithField("event", e.event)
+	}
+	if e.kind != kindPlain {
+		entry = entry.WithField("status", e.kind)
+	}
+	entry.Log(e.level, e.msg)
+}
+
+// emitText hands the entry to grove's unified logger.
+func (e *Entry) emitText() {
+	u := e.logger.unified
+	out := u.Info(e.msg)
+	switch {
+	case e.level == logrus.DebugLevel:
+		out = u.Debug(e.msg)
+	case e.level == logrus.WarnLevel:
+		out = u.Warn(e.msg)
+	case e.level <= logrus.ErrorLevel:
+		out = u.Error(e.msg)
+	case e.kind == kindSuccess:
+		out = u.Success(e.msg)
+	case e.kind == kindProgress:
+		out = u.Progress(e.msg)
+	}
+	for k, v := range e.fields {
+		if k != "error" || e.err == nil {
+			out = out.Field(k, v)
+		}
+	}
+	if e.event != "" {
+		out = out.Field("event", e.event)
+	}
+	if e.err != nil {
+		out = out.Err(e.err)
+	}
+	if e.icon != "" {
+		out = out.Icon(e.icon)
+	}
+	if e.pretty != "" {
+		out = out.Pretty(e.pretty)
+	}
+	if e.prettyOnly {
+		out = out.PrettyOnly()
+	}
+	if e.structOnly {
+		out = out.StructuredOnly()
+	}
+	out.Emit()
+}
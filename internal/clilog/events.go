@@ -0,0 +1,17 @@
+package clilog
+
+// Event types, recorded as the event key of json-format log lines.
+const (
+	// watch
+	EventWatchWatching      = "watch.watching"       // A directory is watched; package, dir
+	EventWatchReady         = "watch.ready"          // Setup is done; mode, website, packages
+	EventWatchRebuildStart  = "watch.rebuild.start"  // A package is rebuilding; package, kind
+	EventWatchRebuildDone   = "watch.rebuild.done"   // A rebuild succeeded; package, kind
+	EventWatchRebuildFailed = "watch.rebuild.failed" // A rebuild failed; package, kind, error
+
+	// aggregate
+	EventAggregatePackage = "aggregate.package" // A package is aggregated; package, output_mode
+	EventAggregateSection = "aggregate.section" // A section was published or failed; package, section, status, error
+	EventAggregateIssue   = "aggregate.issue"   // A validation issue was found; severity, kind, package, section
+	EventAggregateDone    = "aggregate.done"    // The run finished; packages, sections, errors, warnings, duration
+)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -22,6 +23,25 @@ type InitOptions struct {
 	StructuredOutputFile string
 	SystemPrompt         string
 	OutputDir            string
+
+	// Sections limits the scaffolded sections to these names. Empty keeps
+	// every section of the template.
+	Sections []string
+	// OutputMode is "package" (default) or "sections", which scaffolds each
+	// section as a website content directory with its own config and prompts.
+	OutputMode string
+	// DocgenDir and PromptsDir, when set, receive the config and prompts
+	// instead of the repository's docs/ directory (notebook layout). Prompt
+	// paths in the config are then basenames.
+	DocgenDir  string
+	PromptsDir string
+}
+
+// isZero reports whether no option is set, in which case the template config
+// is copied verbatim with its comments.
+func (o InitOptions) isZero() bool {
+	return o.Model == "" && o.RegenerationMode == "" && o.RulesFile == "" && o.StructuredOutputFile == "" &&
+		o.SystemPrompt == "" && o.OutputDir == "" && len(o.Sections) == 0 && o.OutputMode == "" && o.DocgenDir == ""
 }
 
 // Init scaffolds a new docgen configuration in the current directory with default options.
@@ -36,37 +56,61 @@ func InitWithOptions(projectType string, opts InitOptions, logger *logrus.Logger
 		return fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
+	// Templates for website content default to the sections output mode
+	tmpl, err := templateConfig(projectType)
+	if err != nil {
+		return err
+	}
+	if opts.OutputMode == "" {
+		opts.OutputMode = tmpl.OutputMode
+	}
+
 	docsDir := filepath.Join(cwd, "docs")
-	promptsDir := filepath.Join(docsDir, "prompts")
+	configDir, promptsDir := docsDir, filepath.Join(docsDir, "prompts")
+	if opts.DocgenDir != "" {
+		configDir, promptsDir = opts.DocgenDir, opts.PromptsDir
+		if promptsDir == "" {
+			promptsDir = filepath.Join(configDir, "prompts")
+		}
+	}
 
 	// 1. Check for existing config to prevent overwrite
-	configDest := filepath.Join(docsDir, "docgen.config.yml")
+	configDest := filepath.Join(configDir, "docgen.config.yml")
 	if _, err := os.Stat(configDest); err == nil {
 		return fmt.Errorf("docgen configuration already exists at %s", configDest)
 	}
 
 	// 2. Create destination directories
-	logger.Debugf("Creating directory: %s", promptsDir)
-	if err := os.MkdirAll(promptsDir, 0o755); err != nil { //nolint:gosec // internal scaffold tool
-		return fmt.Errorf("failed to create directories: %w", err)
+	dirs := []string{docsDir, configDir}
+	if opts.OutputMode != "sections" {
+		dirs = append(dirs, promptsDir)
+	}
+	for _, dir := range dirs {
+		logger.Debugf("Creating directory: %s", dir)
+		if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // internal scaffold tool
+			return fmt.Errorf("failed to create directories: %w", err)
+		}
 	}
 
 	// 3. Copy and customize config file
 	configSrcPath := filepath.Join("templates", projectType, "docgen.config.yml")
 	logger.Debugf("Copying %s to %s", configSrcPath, configDest)
-	if err := copyAndCustomizeConfig(configSrcPath, configDest, opts); err != nil {
+	sections, err := copyAndCustomizeConfig(configSrcPath, configDest, opts)
+	if err != nil {
 		return err
 	}
-	logger.Infof("* Created configuration file: %s", filepath.Join("docs", "docgen.config.yml"))
+	logger.Infof("* Created configuration file: %s", displayPath(cwd, configDest))
 
-	// 4. Copy README.md.tpl to docs directory
+	// 4. Copy README.md.tpl to docs directory, for templates that sync a README
 	readmeTplSrc := filepath.Join("templates", projectType, "docs", "README.md.tpl")
 	readmeTplDest := filepath.Join(docsDir, "README.md.tpl")
-	if _, err := os.Stat(readmeTplDest); os.IsNotExist(err) {
-		if err := copyFileFromFS(readmeTplSrc, readmeTplDest); err != nil {
-			return fmt.Errorf("failed to copy README.md.tpl: %w", err)
+	if _, err := templatesFS.ReadFile(readmeTplSrc); err == nil {
+		if _, err := os.Stat(readmeTplDest); os.IsNotExist(err) {
+			if err := copyReadmeTemplate(readmeTplSrc, readmeTplDest, configDest); err != nil {
+				return fmt.Errorf("failed to copy README.md.tpl: %w", err)
+			}
+			logger.Infof("* Created README template: %s", filepath.Join("docs", "README.md.tpl"))
 		}
-		logger.Infof("* Created README template: %s", filepath.Join("docs", "README.md.tpl"))
 	}
 
 	// 5. Create rules file if specified
@@ -88,38 +132,89 @@ func InitWithOptions(projectType string, opts InitOptions, logger *logrus.Logger
 
 	// 6. Copy prompt files
 	promptsSrcDir := filepath.Join("templates", projectType, "prompts")
-	entries, err := templatesFS.ReadDir(promptsSrcDir)
-	if err != nil {
-		return fmt.Errorf("failed to read embedded prompts directory: %w", err)
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			src := filepath.Join(promptsSrcDir, entry.Name())
-			dest := filepath.Join(promptsDir, entry.Name())
-			logger.Debugf("Copying %s to %s", src, dest)
-			if err := copyFileFromFS(src, dest); err != nil {
+	if opts.OutputMode == "sections" {
+		// Each section gets a content directory with its own config and prompt
+		for _, section := range sections {
+			name, _ := section["name"].(string)
+			sectionDir := filepath.Join(configDir, name)
+			if err := os.MkdirAll(filepath.Join(sectionDir, "prompts"), 0o755); err != nil { //nolint:gosec // internal scaffold tool
+				return fmt.Errorf("failed to create directories: %w", err)
+			}
+			if err := writeConfig(filepath.Join(sectionDir, "docgen.config.yml"), map[string]interface{}{
+				"enabled":  true,
+				"title":    section["title"],
+				"sections": []interface{}{section},
+			}); err != nil {
 				return err
 			}
-			logger.Infof("* Created prompt file: %s", filepath.Join("docs", "prompts", entry.Name()))
+			if prompt, ok := section["prompt"].(string); ok {
+				dest := filepath.Join(sectionDir, "prompts", prompt)
+				if err := copyFileFromFS(filepath.Join(promptsSrcDir, prompt), dest); err != nil {
+					return err
+				}
+			}
+			logger.Infof("* Created section directory: %s", displayPath(cwd, sectionDir))
+		}
+	} else {
+		entries, err := templatesFS.ReadDir(promptsSrcDir)
+		if err != nil {
+			return fmt.Errorf("failed to read embedded prompts directory: %w", err)
+		}
+
+		wanted := promptFiles(sections)
+		for _, entry := range entries {
+			if !entry.IsDir() && (sections == nil || wanted[entry.Name()]) {
+				src := filepath.Join(promptsSrcDir, entry.Name())
+				dest := filepath.Join(promptsDir, entry.Name())
+				logger.Debugf("Copying %s to %s", src, dest)
+				if err := copyFileFromFS(src, dest); err != nil {
+					return err
+				}
+				logger.Infof("* Created prompt file: %s", displayPath(cwd, dest))
+			}
 		}
 	}
 
 	logger.Info(" Docgen initialized successfully.")
-	logger.Info("* Created docs/prompts/ with starter prompts")
-	logger.Info("")
-	logger.Info("Tip: If you use grove-notebook, run 'docgen migrate-prompts' to move prompts to your notebook")
+	if opts.DocgenDir == "" {
+		if opts.OutputMode != "sections" {
+			logger.Info("* Created docs/prompts/ with starter prompts")
+		}
+		logger.Info("")
+		logger.Info("Tip: If you use grove-notebook, run 'docgen migrate-prompts' to move prompts to your notebook")
+	} else {
+		logger.Infof("* Created config and prompts in the notebook: %s", configDir)
+	}
 	logger.Info("")
-	logger.Info("   Next steps: 1. Edit docs/docgen.config.yml to match your project.")
+	logger.Infof("   Next steps: 1. Edit %s to match your project.", displayPath(cwd, configDest))
 	if opts.RulesFile != "" {
 		logger.Infof("               2. Review and customize the rules in docs/%s.", opts.RulesFile)
 	}
-	logger.Info("               3. Review and customize the prompts in docs/prompts/.")
+	logger.Info("               3. Review and customize the prompts.")
 	logger.Info("               4. Run 'make generate-docs' to create documentation and sync your README.")
 
 	return nil
 }
 
+// displayPath shortens paths inside the project to relative ones for output.
+func displayPath(cwd, path string) string {
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// promptFiles returns the base names of the prompts the sections use.
+func promptFiles(sections []map[string]interface{}) map[string]bool {
+	files := make(map[string]bool)
+	for _, section := range sections {
+		if prompt, ok := section["prompt"].(string); ok {
+			files[filepath.Base(prompt)] = true
+		}
+	}
+	return files
+}
+
 func copyFileFromFS(src, dest string) error {
 	content, err := templatesFS.ReadFile(src)
 	if err != nil {
@@ -131,22 +226,65 @@ func copyFileFromFS(src, dest string) error {
 	return nil
 }
 
-// copyAndCustomizeConfig copies the config template and applies any custom options
-func copyAndCustomizeConfig(src, dest string, opts InitOptions) error {
+// copyReadmeTemplate copies the README template with its section markers
+// renamed to the readme source section of the written config, which differs
+// from the template's when that section was not selected.
+func copyReadmeTemplate(src, dest, configPath string) error {
 	content, err := templatesFS.ReadFile(src)
 	if err != nil {
 		return fmt.Errorf("failed to read embedded file %s: %w", src, err)
 	}
+	data, err := os.ReadFile(configPath) //nolint:gosec // config written by this scaffold
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	var cfg struct {
+		Readme struct {
+			SourceSection string `yaml:"source_section"`
+		} `yaml:"readme"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	if source := cfg.Readme.SourceSection; source != "" {
+		content = markerRe.ReplaceAllFunc(content, func(m []byte) []byte {
+			sub := markerRe.FindSubmatch(m)
+			if string(sub[1]) == "TOC" {
+				return m
+			}
+			return []byte("DOCGEN:" + strings.ToUpper(source) + ":" + string(sub[2]))
+		})
+		content = sourceNoteRe.ReplaceAll(content, []byte("content of your '"+source+"' documentation section"))
+	}
+	if err := os.WriteFile(dest, content, 0o644); err != nil { //nolint:gosec // internal scaffold tool
+		return fmt.Errorf("failed to write file %s: %w", dest, err)
+	}
+	return nil
+}
+
+var (
+	markerRe     = regexp.MustCompile(`DOCGEN:([A-Z0-9_-]+):(START|END)`)
+	sourceNoteRe = regexp.MustCompile(`content of your '[^']*' documentation section`)
+)
+
+// copyAndCustomizeConfig copies the config template and applies any custom
+// options. It returns the sections the config keeps, or nil when the template
+// was copied as-is.
+func copyAndCustomizeConfig(src, dest string, opts InitOptions) ([]map[string]interface{}, error) {
+	content, err := templatesFS.ReadFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded file %s: %w", src, err)
+	}
 
 	// If no options are provided, just write the file as-is
-	if opts == (InitOptions{}) {
-		return os.WriteFile(dest, content, 0o644) //nolint:gosec // internal scaffold tool
+	if opts.isZero() {
+		return nil, os.WriteFile(dest, content, 0o644) //nolint:gosec // internal scaffold tool
 	}
 
 	// Parse the YAML
 	var config map[string]interface{}
 	if err := yaml.Unmarshal(content, &config); err != nil {
-		return fmt.Errorf("failed to parse config template: %w", err)
+		return nil, fmt.Errorf("failed to parse config template: %w", err)
 	}
 
 	// Get or create settings section
@@ -175,8 +313,87 @@ func copyAndCustomizeConfig(src, dest string, opts InitOptions) error {
 	if opts.OutputDir != "" {
 		settings["output_dir"] = opts.OutputDir
 	}
+	if opts.OutputMode == "package" {
+		delete(settings, "output_mode")
+	} else if opts.OutputMode != "" {
+		settings["output_mode"] = opts.OutputMode
+	}
+
+	sections, err := selectSections(config, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.OutputMode == "sections" {
+		// Sections live in their own content directories
+		delete(config, "sections")
+	} else {
+		list := make([]interface{}, len(sections))
+		for i, section := range sections {
+			list[i] = section
+		}
+		config["sections"] = list
+	}
+
+	return sections, writeConfig(dest, config)
+}
+
+// selectSections filters the template's sections to opts.Sections, in
+// template order, and points the readme at a kept section. Prompt paths become
+// basenames when prompts do not live in docs/prompts.
+func selectSections(config map[string]interface{}, opts InitOptions) ([]map[string]interface{}, error) {
+	list, _ := config["sections"].([]interface{})
+	wanted := make(map[string]bool, len(opts.Sections))
+	for _, name := range opts.Sections {
+		wanted[name] = true
+	}
+
+	var sections []map[string]interface{}
+	for _, item := range list {
+		section, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := section["name"].(string)
+		if len(wanted) > 0 && !wanted[name] {
+			continue
+		}
+		delete(wanted, name)
+		if prompt, ok := section["prompt"].(string); ok && (opts.DocgenDir != "" || opts.OutputMode == "sections") {
+			section["prompt"] = filepath.Base(prompt)
+		}
+		sections = append(sections, section)
+	}
+	if len(wanted) > 0 {
+		var unknown []string
+		for _, name := range opts.Sections {
+			if wanted[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		return nil, fmt.Errorf("unknown section(s) %s in template", strings.Join(unknown, ", "))
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no sections selected")
+	}
+
+	if readme, ok := config["readme"].(map[string]interface{}); ok {
+		source, _ := readme["source_section"].(string)
+		kept := false
+		for _, section := range sections {
+			if section["name"] == source {
+				kept = true
+			}
+		}
+		if !kept {
+			readme["source_section"] = sections[0]["name"]
+		}
+	}
+	return sections, nil
+}
 
-	// Marshal back to YAML
+// writeConfig marshals a config with the schema comment on top.
+func writeConfig(dest string, config map[string]interface{}) error {
 	updatedContent, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal updated config: %w", err)
@@ -0,0 +1,22 @@
+# {{ .Title }}
+
+{{ .Description }}
+
+<!-- DOCGEN:INTRODUCTION:START -->
+<!-- This content will be automatically replaced by the content of your 'introduction' documentation section. -->
+<!-- To update, run 'docgen sync-readme'. -->
+<!-- DOCGEN:INTRODUCTION:END -->
+
+## Documentation
+
+<!-- DOCGEN:TOC:START -->
+<!-- Automatically generated table of contents will be injected here -->
+<!-- DOCGEN:TOC:END -->
+
+## Packages
+
+*TODO: List the packages of this repository.*
+
+## Development
+
+See the development tasks in the documentation above.
@@ -0,0 +1,24 @@
+# {{ .Title }}
+
+{{ .Description }}
+
+<!-- DOCGEN:INTRODUCTION:START -->
+<!-- This content will be automatically replaced by the content of your 'introduction' documentation section. -->
+<!-- To update, run 'docgen sync-readme'. -->
+<!-- DOCGEN:INTRODUCTION:END -->
+
+## Documentation
+
+<!-- DOCGEN:TOC:START -->
+<!-- Automatically generated table of contents will be injected here -->
+<!-- DOCGEN:TOC:END -->
+
+## Running
+
+```bash
+go run ./cmd/{{ .PackageName }}
+```
+
+## Usage
+
+*TODO: Add a request example here.*
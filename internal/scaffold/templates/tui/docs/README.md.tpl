@@ -0,0 +1,24 @@
+# {{ .Title }}
+
+{{ .Description }}
+
+<!-- DOCGEN:INTRODUCTION:START -->
+<!-- This content will be automatically replaced by the content of your 'introduction' documentation section. -->
+<!-- To update, run 'docgen sync-readme'. -->
+<!-- DOCGEN:INTRODUCTION:END -->
+
+## Documentation
+
+<!-- DOCGEN:TOC:START -->
+<!-- Automatically generated table of contents will be injected here -->
+<!-- DOCGEN:TOC:END -->
+
+## Installation
+
+```bash
+grove install {{ .PackageName }}
+```
+
+## Usage
+
+Run `{{ .PackageName }}` and press `?` for the keybindings of the current view.
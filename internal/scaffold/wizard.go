@@ -0,0 +1,272 @@
+package scaffold
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// projectTypeDescriptions describe the project templates in the wizard.
+var projectTypeDescriptions = map[string]string{
+	"cli":              "Command-line tool: getting started, workflows and a captured CLI reference",
+	"library":          "Go library or SDK: concepts, usage patterns and best practices",
+	"monorepo":         "Repository root with several packages: layout, contributing, tasks and CI",
+	"service":          "Backend service: architecture, deployment, HTTP API and config reference",
+	"tui":              "Terminal UI app: getting started, keybindings and TUI descriptions",
+	"website-sections": "Website content (output_mode: sections): overview, concepts and guides",
+}
+
+// TemplateSection is one section of a project template.
+type TemplateSection struct {
+	Name  string `yaml:"name"`
+	Title string `yaml:"title"`
+}
+
+// ProjectTypes returns the available project templates, sorted.
+func ProjectTypes() []string {
+	entries, err := templatesFS.ReadDir("templates")
+	if err != nil {
+		return nil
+	}
+	var types []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			types = append(types, entry.Name())
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// templateInfo is the part of a template config the wizard offers.
+type templateInfo struct {
+	Model      string
+	OutputMode string
+	Sections   []TemplateSection
+}
+
+// templateConfig reads the parts of a template config the wizard offers.
+func templateConfig(projectType string) (*templateInfo, error) {
+	data, err := templatesFS.ReadFile(filepath.Join("templates", projectType, "docgen.config.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown project type '%s'", projectType)
+	}
+	var cfg struct {
+		Settings struct {
+			Model      string `yaml:"model"`
+			OutputMode string `yaml:"output_mode"`
+		} `yaml:"settings"`
+		Sections []TemplateSection `yaml:"sections"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config template: %w", err)
+	}
+	return &templateInfo{Model: cfg.Settings.Model, OutputMode: cfg.Settings.OutputMode, Sections: cfg.Sections}, nil
+}
+
+// Answers is the outcome of the init wizard.
+type Answers struct {
+	ProjectType string
+	Options     InitOptions
+	// Notebook asks for the config and prompts to be written to the
+	// workspace's notebook; the caller resolves its directories.
+	Notebook bool
+}
+
+// Wizard asks the questions of `docgen init --interactive` and turns the
+// answers into a tailored scaffold. Every question has a default taken with
+// Enter.
+type Wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	eof bool
+}
+
+// NewWizard creates a wizard reading answers from in and writing questions to out.
+func NewWizard(in io.Reader, out io.Writer) *Wizard {
+	return &Wizard{in: bufio.NewReader(in), out: out}
+}
+
+// Run asks for the project type, the sections wanted, the model, the output
+// mode and the layout. Options already set in opts are kept and offered as
+// defaults.
+func (w *Wizard) Run(projectType string, opts InitOptions) (*Answers, error) {
+	types := ProjectTypes()
+	if len(types) == 0 {
+		return nil, errors.New("no project templates available")
+	}
+	labels := make([]string, len(types))
+	def := 0
+	for i, t := range types {
+		labels[i] = t
+		if desc := projectTypeDescriptions[t]; desc != "" {
+			labels[i] = fmt.Sprintf("%-18s %s", t, desc)
+		}
+		if t == projectType {
+			def = i
+		}
+	}
+	choice, err := w.choose("Project type", labels, def)
+	if err != nil {
+		return nil, err
+	}
+	answers := &Answers{ProjectType: types[choice], Options: opts}
+
+	tmpl, err := templateConfig(answers.ProjectType)
+	if err != nil {
+		return nil, err
+	}
+	sections := tmpl.Sections
+	labels = make([]string, len(sections))
+	for i, s := range sections {
+		labels[i] = fmt.Sprintf("%-18s %s", s.Name, s.Title)
+	}
+	picked, err := w.chooseMany("Sections", labels)
+	if err != nil {
+		return nil, err
+	}
+	if len(picked) < len(sections) {
+		for _, i := range picked {
+			answers.Options.Sections = append(answers.Options.Sections, sections[i].Name)
+		}
+	}
+
+	model := tmpl.Model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	if model, err = w.ask("Model", model); err != nil {
+		return nil, err
+	}
+	answers.Options.Model = model
+
+	modes := []string{"package", "sections"}
+	def = 0
+	if opts.OutputMode == "sections" || opts.OutputMode == "" && tmpl.OutputMode == "sections" {
+		def = 1
+	}
+	choice, err = w.choose("Output mode", []string{
+		"package            standalone docs for this package",
+		"sections           website content, one directory per section",
+	}, def)
+	if err != nil {
+		return nil, err
+	}
+	answers.Options.OutputMode = modes[choice]
+
+	choice, err = w.choose("Layout", []string{
+		"repo               config and prompts in docs/",
+		"notebook           config and prompts in the workspace notebook",
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	answers.Notebook = choice == 1
+
+	return answers, nil
+}
+
+// readLine reads one answer. Once input is exhausted every answer is empty,
+// so the remaining questions take their defaults.
+func (w *Wizard) readLine() (string, error) {
+	line, err := w.in.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		w.eof = true
+	} else if err != nil {
+		return "", fmt.Errorf("init wizard aborted: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ask asks a free-form question.
+func (w *Wizard) ask(question, def string) (string, error) {
+	fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	answer, err := w.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// choose asks for one of options by number.
+func (w *Wizard) choose(question string, options []string, def int) (int, error) {
+	for {
+		fmt.Fprintf(w.out, "%s:\n", question)
+		for i, o := range options {
+			fmt.Fprintf(w.out, "  %d) %s\n", i+1, o)
+		}
+		fmt.Fprintf(w.out, "Choose [%d]: ", def+1)
+		answer, err := w.readLine()
+		if err != nil {
+			return 0, err
+		}
+		if answer == "" {
+			return def, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		if w.eof {
+			return 0, fmt.Errorf("invalid choice %q for %s", answer, strings.ToLower(question))
+		}
+		fmt.Fprintf(w.out, "Please enter a number from 1 to %d.\n", len(options))
+	}
+}
+
+// chooseMany asks for any of options as a comma- or space-separated list of
+// numbers; the default is all of them.
+func (w *Wizard) chooseMany(question string, options []string) ([]int, error) {
+	for {
+		fmt.Fprintf(w.out, "%s:\n", question)
+		for i, o := range options {
+			fmt.Fprintf(w.out, "  %d) %s\n", i+1, o)
+		}
+		fmt.Fprint(w.out, "Choose, e.g. 1,3 [all]: ")
+		answer, err := w.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" || strings.EqualFold(answer, "all") {
+			all := make([]int, len(options))
+			for i := range all {
+				all[i] = i
+			}
+			return all, nil
+		}
+		if picked, ok := parseChoices(answer, len(options)); ok {
+			return picked, nil
+		}
+		if w.eof {
+			return nil, fmt.Errorf("invalid choice %q for %s", answer, strings.ToLower(question))
+		}
+		fmt.Fprintf(w.out, "Please enter numbers from 1 to %d.\n", len(options))
+	}
+}
+
+// parseChoices parses a list of 1-based choices into sorted, distinct indexes.
+func parseChoices(answer string, n int) ([]int, bool) {
+	seen := make(map[int]bool)
+	var picked []int
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		i, err := strconv.Atoi(field)
+		if err != nil || i < 1 || i > n {
+			return nil, false
+		}
+		if !seen[i-1] {
+			seen[i-1] = true
+			picked = append(picked, i-1)
+		}
+	}
+	sort.Ints(picked)
+	return picked, len(picked) > 0
+}
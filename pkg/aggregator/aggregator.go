@@ -1,6 +1,8 @@
 package aggregator
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -16,133 +18,172 @@ import (
 
 	"github.com/grovetools/core/config"
 	"github.com/grovetools/core/pkg/workspace"
+	"github.com/grovetools/docgen/internal/clilog"
 	"github.com/grovetools/docgen/pkg/capture"
+	"github.com/grovetools/docgen/pkg/citations"
 	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/discovery"
+	"github.com/grovetools/docgen/pkg/frontmatter"
 	"github.com/grovetools/docgen/pkg/manifest"
+	"github.com/grovetools/docgen/pkg/progress"
+	"github.com/grovetools/docgen/pkg/report"
+	"github.com/grovetools/docgen/pkg/snippets"
+	"github.com/grovetools/docgen/pkg/telemetry"
 	"github.com/grovetools/docgen/pkg/transformer"
+	docgenVersion "github.com/grovetools/docgen/pkg/version"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
 
 type Aggregator struct {
-	logger *logrus.Logger
+	logger   *logrus.Logger
+	report   *report.Recorder
+	progress *progress.Tracker
+	filter   Filter
+	strict   bool
+
+	// issues collects, per run, the problems for the validation report, and
+	// links the links checked at its end.
+	issues []Issue
+	links  []pendingLinks
+
+	// selected collects, per run, the packages the filter selected, whose
+	// manifest entries the run replaces.
+	selected map[string]bool
+
+	// commits caches, per run, the git commit of each package directory
+	// for the build metadata.
+	commits map[string]string
+
+	// section is the section being aggregated, for tracing and progress.
+	section openSection
+
+	// components are, per run, the component mappings of the website's
+	// config, which every package's mappings extend.
+	components []docgenConfig.ComponentMapping
+
+	// profile is the output profile set by SetProfile.
+	profile string
+	// audiences are the audiences set by SetAudiences.
+	audiences []string
 }
 
 func New(logger *logrus.Logger) *Aggregator {
 	return &Aggregator{logger: logger}
 }
 
+// SetReport makes the aggregator record each section it copies in r; the
+// caller finishes and writes the report.
+func (a *Aggregator) SetReport(r *report.Recorder) {
+	a.report = r
+}
+
+// SetProgress makes the aggregator show the sections it copies on p; the
+// caller stops it.
+func (a *Aggregator) SetProgress(p *progress.Tracker) {
+	a.progress = p
+}
+
+// SetFilter limits aggregation to the packages f selects and makes it patch
+// the existing manifest instead of replacing it.
+func (a *Aggregator) SetFilter(f Filter) {
+	a.filter = f
+}
+
 // Aggregate collects documentation from ecosystems specified in the local docgen.config.yml.
 // If no ecosystems are specified, it falls back to the current ecosystem only and warns the user.
 // The transform parameter specifies output transformations (e.g., "astro" for website builds).
 func (a *Aggregator) Aggregate(outputDir string, mode string, transform string) error {
-	// Validate mode
-	if mode != "dev" && mode != "prod" {
-		return fmt.Errorf("invalid mode '%s': must be 'dev' or 'prod'", mode)
-	}
+	return a.AggregateContext(context.Background(), outputDir, mode, transform)
+}
 
-	a.logger.Infof("Aggregating documentation in %s mode", mode)
+// AggregateContext is Aggregate bounded by ctx: once ctx is cancelled or
+// times out, in-flight CLI captures are killed and no further packages are
+// processed. The manifest is only written by a complete run, so a cancelled
+// aggregate never publishes a partial one.
+func (a *Aggregator) AggregateContext(ctx context.Context, outputDir string, mode string, transform string) (err error) {
+	if err := docgenConfig.CheckMode(mode); err != nil {
+		return err
+	}
+	if err := docgenConfig.CheckProfile(a.outputProfile()); err != nil {
+		return err
+	}
+	ctx, span := telemetry.Start(ctx, "docgen.aggregate",
+		telemetry.String("docgen.mode", mode),
+		telemetry.String("docgen.profile", a.outputProfile()),
+		telemetry.String("docgen.transform", transform))
+	defer func() {
+		a.endSectionSpan()
+		span.RecordError(err)
+		span.End()
+	}()
+
+	a.logger.Infof("Aggregating documentation in %s mode with the %s profile", mode, a.outputProfile())
+	started := time.Now()
 
 	// Try to load local docgen.config.yml to get ecosystems list
 	// Uses LoadWithNotebook to check notebook location first, then repo
 	cwd, _ := os.Getwd()
 	localCfg, _, _ := docgenConfig.LoadWithNotebook(cwd)
 
-	var ecosystemsToProcess []workspace.Ecosystem
-
-	if localCfg != nil && len(localCfg.Settings.Ecosystems) > 0 {
-		// Use explicitly configured ecosystems
-		a.logger.Infof("Using ecosystems from local config: %v", localCfg.Settings.Ecosystems)
-
-		// Discover all ecosystems to match by name
-		discoveryService := workspace.NewDiscoveryService(a.logger)
-		result, err := discoveryService.DiscoverAll()
-		if err != nil {
-			return fmt.Errorf("could not discover ecosystems: %w", err)
-		}
-
-		// Build a map for lookup
-		ecoByName := make(map[string]workspace.Ecosystem)
-		for _, eco := range result.Ecosystems {
-			ecoByName[eco.Name] = eco
-		}
-
-		// GROVE_ECOSYSTEM_PATH override: when set (e.g. by `grove release apply
-		// --website`), the ecosystem checkout at that path WINS over the
-		// groves-registry resolution for its own name. This closes the changelog
-		// hop: the aggregator reads each repo's CHANGELOG.md straight from the
-		// checkout that the release just published to, rather than whatever
-		// same-named checkout the registry happens to resolve (which may be a
-		// different clone or a stale worktree). Docs are notebook-first and so
-		// already shared across checkouts; changelogs live only in the repo tree,
-		// so without this override a release run from a worktree/XDG location would
-		// build the site against another checkout's changelogs.
-		if envPath := os.Getenv("GROVE_ECOSYSTEM_PATH"); envPath != "" {
-			if root, err := workspace.FindEcosystemRoot(envPath); err == nil && root != "" {
-				name := filepath.Base(root)
-				if cfg, err := config.LoadFrom(root); err == nil && cfg.Name != "" {
-					name = cfg.Name
-				}
-				a.logger.Infof("GROVE_ECOSYSTEM_PATH override: ecosystem %q -> %s", name, root)
-				ecoByName[name] = workspace.Ecosystem{Name: name, Path: root}
-			} else {
-				a.logger.Debugf("GROVE_ECOSYSTEM_PATH=%s is not inside an ecosystem root; ignoring override", envPath)
-			}
-		}
-
-		// Filter to only requested ecosystems
-		for _, name := range localCfg.Settings.Ecosystems {
-			if eco, ok := ecoByName[name]; ok {
-				ecosystemsToProcess = append(ecosystemsToProcess, eco)
-			} else {
-				a.logger.Warnf("Ecosystem '%s' not found in groves config", name)
-			}
-		}
-
-		if len(ecosystemsToProcess) == 0 {
-			return fmt.Errorf("none of the specified ecosystems were found: %v", localCfg.Settings.Ecosystems)
-		}
-	} else {
-		// No ecosystems specified - fall back to current ecosystem only
-		rootDir, err := workspace.FindEcosystemRoot("")
-		if err != nil {
-			return fmt.Errorf("could not find ecosystem root: %w", err)
-		}
-
-		a.logger.Warnf("No 'ecosystems' specified in docgen.config.yml - using current ecosystem only (%s)", filepath.Base(rootDir))
-		a.logger.Warnf("To aggregate from multiple ecosystems, add 'settings.ecosystems' to your docgen.config.yml")
-
-		ecosystemsToProcess = []workspace.Ecosystem{{Name: filepath.Base(rootDir), Path: rootDir}}
+	disc := discovery.New(a.logger)
+	ecosystemsToProcess, err := disc.Ecosystems(localCfg)
+	if err != nil {
+		return err
 	}
 
 	a.logger.Infof("Processing %d ecosystem(s)", len(ecosystemsToProcess))
 
-	// Build set of allowed packages from sidebar config
 	// Only packages listed in sidebar.categories.*.packages will be aggregated
-	allowedPackages := make(map[string]bool)
-	if localCfg != nil && localCfg.Sidebar != nil && localCfg.Sidebar.Categories != nil {
-		for _, cat := range localCfg.Sidebar.Categories {
-			for _, pkg := range cat.Packages {
-				allowedPackages[pkg] = true
-			}
-		}
+	allowedPackages := discovery.AllowedPackages(localCfg)
+	if len(allowedPackages) > 0 {
 		a.logger.Infof("Filtering to %d allowed packages from sidebar config", len(allowedPackages))
 	}
 
+	// --packages / --category narrow the run further
+	match := a.filter.matcher(localCfg)
+	a.selected = make(map[string]bool)
+	a.issues, a.links = nil, nil
+	a.commits = nil
+	a.components = nil
+	if localCfg != nil {
+		a.components = localCfg.Settings.Components
+	}
+	var scrub []scrubRule
+	if a.public() && localCfg != nil {
+		if scrub, err = compileScrub(localCfg.Settings.Scrub); err != nil {
+			return err
+		}
+	}
+	if !a.filter.IsZero() {
+		a.logger.Infof("Filtering to packages %v and categories %v", a.filter.Packages, a.filter.Categories)
+	}
+
 	m := &manifest.Manifest{
 		Packages:        []manifest.PackageManifest{},
 		WebsiteSections: []manifest.WebsiteSection{},
+		Mode:            mode,
+		Profile:         a.outputProfile(),
+		Audiences:       a.audiences,
 	}
 
 	// Aggregate from each ecosystem
 	for _, eco := range ecosystemsToProcess {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("aggregation cancelled: %w", err)
+		}
 		a.logger.Infof("Processing ecosystem: %s (%s)", eco.Name, eco.Path)
-		if err := a.aggregateEcosystem(eco.Path, m, outputDir, mode, transform, allowedPackages); err != nil {
+		if err := a.aggregateEcosystem(ctx, disc, eco, m, outputDir, mode, transform, allowedPackages, match); err != nil {
 			a.logger.Warnf("Error aggregating ecosystem %s: %v", eco.Name, err)
+			a.report.AddError(fmt.Errorf("ecosystem %s: %w", eco.Name, err))
 			// Continue with other ecosystems
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("aggregation cancelled: %w", err)
+	}
+
 	// Include sidebar configuration if present in local config
 	if localCfg != nil && localCfg.Sidebar != nil {
 		m.Sidebar = a.buildSidebarManifest(localCfg.Sidebar, mode)
@@ -157,9 +198,70 @@ func (a *Aggregator) Aggregate(outputDir string, mode string, transform string)
 	}
 
 	// Save the manifest
-	manifestPath := filepath.Join(outputDir, "manifest.json")
+	manifestPath := filepath.Join(outputDir, manifest.ManifestFile)
+	if !a.filter.IsZero() {
+		for _, name := range a.filter.Packages {
+			if !a.selected[name] {
+				a.logger.Warnf("Package '%s' was not found in the configured ecosystems", name)
+			}
+		}
+		if len(a.selected) == 0 {
+			return fmt.Errorf("no packages match --packages %v / --category %v", a.filter.Packages, a.filter.Categories)
+		}
+		if m, err = mergePartial(manifestPath, m, a.selected); err != nil {
+			return err
+		}
+		a.logger.Infof("Updating manifest entries for %d package(s)", len(a.selected))
+	}
+	a.checkLinks()
+	validation, err := a.writeValidationReport(outputDir, mode)
+	if err != nil {
+		return err
+	}
+	a.logger.Infof("Validation: %d error(s), %d warning(s), see %s", validation.Errors, validation.Warnings, ValidationFile)
+
+	// Scrub the public build before its files are hashed
+	if a.public() {
+		manifestFile, _ := filepath.Abs(manifestPath)
+		n, err := scrubDir(outputDir, scrub, func(path string) bool {
+			abs, _ := filepath.Abs(path)
+			return abs == manifestFile
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scrub output: %w", err)
+		}
+		if err := scrubManifest(m, scrub); err != nil {
+			return err
+		}
+		a.logger.Infof("Scrubbed %d file(s) for the public profile", n)
+	}
+
+	// Build the navigation tree from the final package set, so a partial
+	// build's tree covers the packages kept from the previous manifest too
+	m.BuildNav(outputDir)
+	m.RecordAnchors(outputDir)
+	// Record checksums of everything in the output for verification and
+	// incremental deploys
+	if err := m.HashFiles(outputDir); err != nil {
+		return err
+	}
 	a.logger.Infof("Saving manifest with %d packages and %d website sections", len(m.Packages), len(m.WebsiteSections))
-	return m.Save(manifestPath)
+	if err := m.Save(manifestPath); err != nil {
+		return err
+	}
+	a.report.AddOutput(manifestPath)
+	a.logger.WithFields(logrus.Fields{
+		"event":    clilog.EventAggregateDone,
+		"packages": len(m.Packages),
+		"sections": len(m.WebsiteSections),
+		"errors":   validation.Errors,
+		"warnings": validation.Warnings,
+		"duration": time.Since(started).Round(time.Millisecond).String(),
+	}).Infof("Aggregated %d package(s) into %s", len(m.Packages), outputDir)
+	if a.strict && validation.Errors > 0 {
+		return fmt.Errorf("strict mode: %d validation error(s), see %s", validation.Errors, filepath.Join(outputDir, ValidationFile))
+	}
+	return nil
 }
 
 // buildSidebarManifest creates the manifest sidebar config from the source config,
@@ -191,16 +293,8 @@ func (a *Aggregator) buildSidebarManifest(src *docgenConfig.SidebarConfig, mode
 				status = docgenConfig.StatusProduction // Default to production
 			}
 
-			// Filter by status:
-			// - draft: excluded from all builds
-			// - dev: included in dev mode only
-			// - production: included in all builds
-			if status == docgenConfig.StatusDraft {
-				a.logger.Debugf("Excluding package %s from sidebar (status: draft)", name)
-				continue
-			}
-			if mode == "prod" && status == docgenConfig.StatusDev {
-				a.logger.Debugf("Excluding package %s from sidebar (status: dev, mode: prod)", name)
+			if !docgenConfig.Published(status, mode) {
+				a.logger.Debugf("Excluding package %s from sidebar (status: %s, mode: %s)", name, status, mode)
 				continue
 			}
 
@@ -218,75 +312,46 @@ func (a *Aggregator) buildSidebarManifest(src *docgenConfig.SidebarConfig, mode
 // aggregateEcosystem processes a single ecosystem and adds its docs to the manifest
 // If allowedPackages is non-empty, only packages in that set will be included.
 // The transform parameter specifies output transformations (e.g., "astro" for website builds).
-func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, outputDir, mode, transform string, allowedPackages map[string]bool) error {
-	// Load the ecosystem config to get workspace paths
-	configPath, err := config.FindConfigFile(rootDir)
-	if err != nil {
-		return fmt.Errorf("could not find config file in %s: %w", rootDir, err)
-	}
-	cfg, err := config.Load(configPath)
-	if err != nil {
-		return fmt.Errorf("could not load ecosystem config from %s: %w", configPath, err)
-	}
-
-	a.logger.Debugf("Loaded ecosystem config with %d workspace patterns", len(cfg.Workspaces))
-
-	// Get workspace paths from config (expand glob patterns)
-	var workspaces []string
-	for _, wsPattern := range cfg.Workspaces {
-		pattern := filepath.Join(rootDir, wsPattern)
-
-		matches, err := filepath.Glob(pattern)
-		if err != nil {
-			a.logger.Warnf("Failed to expand pattern %s: %v", wsPattern, err)
-			continue
-		}
-
-		for _, match := range matches {
-			// Only include directories
-			if info, err := os.Stat(match); err == nil && info.IsDir() {
-				a.logger.Debugf("  Found workspace: %s", match)
-				workspaces = append(workspaces, match)
+// match further selects packages (see Filter).
+func (a *Aggregator) aggregateEcosystem(ctx context.Context, disc *discovery.Discoverer, eco workspace.Ecosystem, m *manifest.Manifest, outputDir, mode, transform string, allowedPackages map[string]bool, match func(string, *docgenConfig.DocgenConfig) bool) error {
+	packages, err := disc.Packages([]workspace.Ecosystem{eco}, discovery.Options{
+		Allowed: allowedPackages,
+		Skipped: func(name, reason string, err error) {
+			if err != nil {
+				a.addIssue(LevelError, IssueSkippedPackage, name, "", "", "%s: %v", reason, err)
+			} else {
+				a.addIssue(LevelInfo, IssueSkippedPackage, name, "", "", "%s", reason)
 			}
-		}
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	a.logger.Debugf("Total workspaces in ecosystem: %d", len(workspaces))
-
-	for _, wsPath := range workspaces {
-		wsName := filepath.Base(wsPath)
-		docCfg, err := docgenConfig.Load(wsPath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				a.logger.Debugf("Skipping %s: no docgen.config.yml found", wsName)
-			} else {
-				a.logger.Warnf("Skipping %s: could not load config: %v", wsName, err)
-			}
-			continue
+	for _, pkg := range packages {
+		if ctx.Err() != nil {
+			return nil // AggregateContext reports the cancellation
 		}
-
-		if !docCfg.Enabled {
-			a.logger.Infof("Skipping %s: documentation is disabled in config", wsName)
+		wsPath, wsName, docCfg := pkg.Path, pkg.Name, pkg.Config
+		if !match(wsName, docCfg) {
+			a.logger.Debugf("Skipping %s: not selected by --packages/--category", wsName)
 			continue
 		}
-
-		// Skip packages not in the allowed set (if filtering is enabled)
-		if len(allowedPackages) > 0 && !allowedPackages[wsName] {
-			// Also check if this is a "sections" mode config (website content) - always allow those
-			if docCfg.Settings.OutputMode != "sections" {
-				a.logger.Debugf("Skipping %s: not in allowed packages list", wsName)
-				continue
-			}
-		}
+		a.selected[wsName] = true
+		a.logger.WithFields(logrus.Fields{
+			"event":       clilog.EventAggregatePackage,
+			"package":     wsName,
+			"output_mode": docCfg.Settings.OutputMode,
+		}).Debugf("Aggregating package %s", wsName)
 
 		// Handle "sections" output mode (for website content like overview, concepts)
 		if docCfg.Settings.OutputMode == "sections" {
-			a.processWebsiteSections(wsPath, docCfg, m, outputDir, mode, transform)
+			a.processWebsiteSections(ctx, wsPath, docCfg, m, outputDir, mode, transform)
 			continue
 		}
 
 		// Get version and repo URL
-		version := a.getPackageVersion(wsPath)
+		version := docgenVersion.Resolve(wsPath)
 		repoURL := a.getRepoURL(wsPath)
 
 		// Add to manifest
@@ -304,22 +369,25 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 		// Resolve docs directory (notebook or repo)
 		docsDir := a.resolveDocsDirForWorkspace(wsPath)
 
-		// Filter sections based on status:
-		// - draft: excluded from all builds
-		// - dev: included in dev mode only
-		// - production: included in all builds
+		// Filter sections based on status (see docgenConfig.Published),
+		// profile and audience, keeping the audience of each
 		var sectionsToAggregate []docgenConfig.SectionConfig
+		audiences := make(map[string][]string)
 		for _, section := range docCfg.Sections {
-			status := section.GetStatus()
-
-			if status == docgenConfig.StatusDraft {
-				a.logger.Debugf("Skipping %s/%s (status: draft)", wsName, section.Output)
+			if status := section.GetStatus(); !docgenConfig.Published(status, mode) {
+				a.logger.Debugf("Skipping %s/%s (status: %s, mode: %s)", wsName, section.Output, status, mode)
+				continue
+			}
+			if !section.InProfile(a.outputProfile()) {
+				a.logger.Debugf("Skipping internal section %s/%s", wsName, section.Output)
 				continue
 			}
-			if mode == "prod" && status == docgenConfig.StatusDev {
-				a.logger.Debugf("Skipping %s/%s (status: dev, mode: prod)", wsName, section.Output)
+			audience := frontmatter.SectionAudience(section, filepath.Join(docsDir, section.Output))
+			if !docgenConfig.ForAudience(audience, a.audiences) {
+				a.logger.Debugf("Skipping %s/%s (audience: %s)", wsName, section.Output, strings.Join(audience, ", "))
 				continue
 			}
+			audiences[section.Output] = audience
 
 			sectionsToAggregate = append(sectionsToAggregate, section)
 		}
@@ -327,6 +395,7 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 		// Skip this package entirely if no sections are available after filtering
 		if len(sectionsToAggregate) == 0 {
 			a.logger.Infof("Skipping package %s: no sections available in %s mode", wsName, mode)
+			a.addIssue(LevelWarning, IssueSkippedPackage, wsName, "", "", "no sections available in %s mode", mode)
 			continue
 		}
 
@@ -339,14 +408,27 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 			continue
 		}
 
+		// Per-TUI pages written by tui_keymaps split: per_tui, keyed by section output
+		splitPages := make(map[string][]manifest.SectionManifest)
+		// Sections published as placeholders, and sections not published at
+		// all, which the manifest leaves out
+		placeholders := make(map[string]bool)
+		unpublished := make(map[string]bool)
+		// Names of the pages published as MDX, keyed by section output
+		published := make(map[string]string)
+
+		a.progress.AddTotal(len(sectionsToAggregate))
 		for _, section := range sectionsToAggregate {
 			srcFile := filepath.Join(docsDir, section.Output)
 			destFile := filepath.Join(distDest, section.Output)
+			a.startSection(ctx, wsName, section.Name, section.Type, destFile)
 
 			// Handle capture sections - generate on-the-fly during aggregation
 			if section.Type == "capture" {
 				if section.Binary == "" {
 					a.logger.Warnf("Capture section %s/%s missing 'binary' field, skipping", wsName, section.Name)
+					a.report.SkipSection("capture section has no binary")
+					a.addIssue(LevelError, IssueSectionFailed, wsName, section.Name, "", "capture section has no binary")
 					continue
 				}
 
@@ -368,34 +450,50 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 					MaxDepth:        depth,
 					Format:          format,
 					SubcommandOrder: section.SubcommandOrder,
+					CobraJSON:       section.CobraJSON,
 				}
 
-				if err := capturer.Capture(section.Binary, destFile, opts); err != nil {
+				if err := capturer.CaptureContext(ctx, section.Binary, destFile, opts); err != nil {
 					a.logger.WithError(err).Errorf("Failed to capture CLI for %s/%s", wsName, section.Name)
+					a.report.FailSection(err)
+					a.addIssue(LevelError, IssueSectionFailed, wsName, section.Name, destFile, "capture failed: %v", err)
 					continue
 				}
 
-				// Apply Astro transformations if requested
-				if transform == "astro" {
+				// Apply Astro transformations or the build metadata if requested
+				metadata := a.buildMetadata(wsPath, docCfg, section, destFile)
+				if transform == "astro" || metadata != nil {
 					srcData, err := os.ReadFile(destFile) //nolint:gosec // path from config
 					if err != nil {
 						a.logger.WithError(err).Errorf("Failed to read captured file %s", destFile)
+						a.report.FailSection(err)
+						a.addIssue(LevelError, IssueSectionFailed, wsName, section.Name, destFile, "%v", err)
 						continue
 					}
 
-					trans := transformer.NewAstroTransformer()
-					opts := transformer.TransformOptions{
-						PackageName: wsName,
-						Title:       section.Title,
-						Description: docCfg.Description,
-						Version:     version,
-						Category:    docCfg.Category,
-						Order:       section.Order,
+					var processedData []byte
+					if transform == "astro" {
+						trans := transformer.NewAstroTransformer()
+						opts := transformer.TransformOptions{
+							PackageName: wsName,
+							Title:       section.Title,
+							Description: docCfg.Description,
+							Version:     version,
+							Category:    docCfg.Category,
+							Order:       section.Order,
+							AssetDirs:   docgenConfig.AssetDirs(docCfg.AssetTypes()),
+							Image:       a.socialCard(distDest, wsName, docCfg, section),
+							Metadata:    metadata,
+						}
+						processedData = trans.TransformStandardDoc(srcData, opts)
+					} else {
+						processedData = transformer.InjectMetadata(srcData, metadata)
 					}
-					processedData := trans.TransformStandardDoc(srcData, opts)
 
 					if err := os.WriteFile(destFile, processedData, 0o644); err != nil { //nolint:gosec // internal doc tool output
 						a.logger.WithError(err).Errorf("Failed to write transformed %s", destFile)
+						a.report.FailSection(err)
+						a.addIssue(LevelError, IssueSectionFailed, wsName, section.Name, destFile, "%v", err)
 						continue
 					}
 				}
@@ -403,56 +501,142 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 				continue
 			}
 
+			// Contributors pages are rebuilt from the current git history and
+			// CODEOWNERS; the last generated page is kept if that fails
+			if section.Type == "contributors" {
+				a.refreshContributors(ctx, wsPath, wsName, section, srcFile, destFile)
+			}
+
 			// Check if the actual documentation file exists
 			if _, err := os.Stat(srcFile); os.IsNotExist(err) {
-				// Try to use the prompt file as a placeholder
-				// Resolve prompt using notebook location first
-				promptData, promptErr := a.resolvePromptForWorkspace(wsPath, section.Prompt)
-				if promptErr == nil {
-					a.logger.Infof("Using prompt file as placeholder for %s/%s", wsName, section.Output)
-
-					// Add a header to indicate this is a placeholder
-					placeholder := fmt.Sprintf("# %s\n\n*Note: This is a placeholder generated from the prompt file. Full documentation is pending.*\n\n---\n\n%s", section.Title, string(promptData))
+				// Publish a placeholder page, as settings.placeholders asks
+				placeholder, err := a.placeholderPage(wsPath, section, docCfg, mode)
+				if err != nil {
+					a.logger.Warnf("No documentation for %s/%s: %v", wsName, section.Output, err)
+					a.report.SkipSection(err.Error())
+					a.addIssue(LevelError, IssueMissingSection, wsName, section.Name, "", "%s was not generated: %v", section.Output, err)
+					unpublished[section.Output] = true
+					continue
+				}
+				a.logger.Infof("Publishing a placeholder for %s/%s", wsName, section.Output)
+				a.addIssue(LevelError, IssuePlaceholder, wsName, section.Name, destFile, "%s was not generated; a placeholder was published", section.Output)
 
-					if err := os.WriteFile(destFile, []byte(placeholder), 0o644); err != nil { //nolint:gosec // internal doc tool output
-						a.logger.WithError(err).Errorf("Failed to write placeholder %s", destFile)
-						continue
-					}
-				} else {
-					a.logger.Warnf("No documentation or prompt found for %s/%s: %v", wsName, section.Output, promptErr)
+				if transform == "astro" {
+					trans := transformer.NewAstroTransformer()
+					placeholder = trans.TransformStandardDoc(placeholder, transformer.TransformOptions{
+						PackageName: wsName,
+						Title:       section.Title,
+						Description: docCfg.Description,
+						Version:     version,
+						Category:    docCfg.Category,
+						Order:       section.Order,
+						AssetDirs:   docgenConfig.AssetDirs(docCfg.AssetTypes()),
+						Placeholder: true,
+					})
+				}
+				if err := os.WriteFile(destFile, placeholder, 0o644); err != nil { //nolint:gosec // internal doc tool output
+					a.logger.WithError(err).Errorf("Failed to write placeholder %s", destFile)
+					a.report.FailSection(err)
+					a.addIssue(LevelError, IssueSectionFailed, wsName, section.Name, destFile, "%v", err)
+					unpublished[section.Output] = true
 					continue
 				}
+				placeholders[section.Output] = true
 			} else {
 				// Copy the actual documentation file
 				a.logger.Infof("Copying documentation for %s/%s", wsName, section.Output)
+				a.checkStale(wsPath, wsName, section.Name, section.Prompt, srcFile)
 
 				srcData, err := os.ReadFile(srcFile) //nolint:gosec // path from config
 				if err != nil {
 					a.logger.WithError(err).Errorf("Failed to read %s", srcFile)
+					a.report.FailSection(err)
+					a.addIssue(LevelError, IssueSectionFailed, wsName, section.Name, destFile, "%v", err)
 					continue
 				}
 
 				// Apply agg_strip_lines if configured for this section
 				processedData := a.applyStripLines(srcData, section.AggStripLines, wsName, section.Output)
+				if mode == "prod" {
+					processedData = citations.Strip(processedData)
+				}
+
+				// Inline snippet references left in hand-written docs
+				if snippets.HasRefs(string(processedData)) {
+					if idx, err := snippets.Scan(wsPath); err != nil {
+						a.logger.Warnf("Failed to scan snippets for %s: %v", wsName, err)
+					} else {
+						expanded, missing := snippets.Expand(string(processedData), idx)
+						if len(missing) > 0 {
+							a.logger.Warnf("Unknown snippets in %s/%s: %s", wsName, section.Output, strings.Join(missing, ", "))
+						}
+						processedData = []byte(expanded)
+					}
+				}
+				processedData = a.expandIncludes(wsPath, wsName, section.Name, destFile, processedData)
+				processedData = a.applyConditions(wsName, section.Name, destFile, processedData, mode, transform)
+
+				// A page using mapped components is published as MDX
+				components := docgenConfig.Components(docCfg.ComponentMappings(a.components))
+				if transform == "astro" && transformer.UsesComponents(string(processedData), components) {
+					destFile = transformer.MDXPath(destFile)
+					published[section.Output] = transformer.MDXPath(section.Output)
+				}
+				a.removeSibling(destFile)
+
+				// Links are checked against the source paths, before Astro
+				// rewrites them, at the end of the run
+				if !strings.HasSuffix(section.Output, ".json") {
+					a.links = append(a.links, pendingLinks{
+						pkg:       wsName,
+						section:   section.Name,
+						file:      destFile,
+						assetDirs: docgenConfig.AssetDirs(docCfg.AssetTypes()),
+						links:     transformer.LocalLinks(processedData),
+					})
+				}
 
 				// Apply Astro transformations if requested (skip JSON files)
-				if transform == "astro" && !strings.HasSuffix(section.Output, ".json") {
-					trans := transformer.NewAstroTransformer()
-					opts := transformer.TransformOptions{
-						PackageName: wsName,
-						Title:       section.Title,
-						Description: docCfg.Description,
-						Version:     version,
-						Category:    docCfg.Category,
-						Order:       section.Order,
+				if !strings.HasSuffix(section.Output, ".json") {
+					metadata := a.buildMetadata(wsPath, docCfg, section, srcFile)
+					if transform == "astro" {
+						trans := transformer.NewAstroTransformer()
+						opts := transformer.TransformOptions{
+							PackageName: wsName,
+							Title:       section.Title,
+							Description: docCfg.Description,
+							Version:     version,
+							Category:    docCfg.Category,
+							Order:       section.Order,
+							AssetDirs:   docgenConfig.AssetDirs(docCfg.AssetTypes()),
+							Image:       a.socialCard(distDest, wsName, docCfg, section),
+							Metadata:    metadata,
+							Components:  components,
+							MDX:         transformer.IsMDX(section.Output),
+						}
+						processedData = trans.TransformStandardDoc(processedData, opts)
+					} else {
+						processedData = transformer.InjectMetadata(processedData, metadata)
 					}
-					processedData = trans.TransformStandardDoc(processedData, opts)
 				}
 
 				if err := os.WriteFile(destFile, processedData, 0o644); err != nil { //nolint:gosec // internal doc tool output
 					a.logger.WithError(err).Errorf("Failed to write %s", destFile)
+					a.report.FailSection(err)
+					a.addIssue(LevelError, IssueSectionFailed, wsName, section.Name, destFile, "%v", err)
 					continue
 				}
+				if transform == "astro" && transformer.IsMDX(destFile) {
+					a.checkMDX(wsName, section.Name, destFile, processedData)
+				}
+				if broken := transformer.BrokenAnchorLinks(processedData); len(broken) > 0 && !strings.HasSuffix(section.Output, ".json") {
+					a.logger.Warnf("Broken anchor links in %s/%s: %s", wsName, section.Output, strings.Join(broken, ", "))
+					a.addIssue(LevelError, IssueBrokenLink, wsName, section.Name, destFile, "broken anchor links: %s", strings.Join(broken, ", "))
+				}
+
+				if section.Type == "tui_keymaps" && section.Split == "per_tui" {
+					splitPages[section.Output] = a.aggregateSplitPages(docsDir, distDest, wsName, section, docCfg, version, transform)
+				}
 
 				// If this is a markdown file with format: json, also copy the companion JSON file
 				if section.Format == "json" && strings.HasSuffix(section.Output, ".md") {
@@ -476,35 +660,22 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 			}
 		}
 
-		// Copy images directory - try notebook location first, then docs/
-		imagesSrcPath := a.resolveAssetsDirForWorkspace(wsPath, "images")
-		if imagesSrcPath != "" {
-			imagesDestPath := filepath.Join(distDest, "images")
-			a.logger.Infof("Copying images for %s from %s to %s", wsName, imagesSrcPath, imagesDestPath)
-			if err := copyDir(imagesSrcPath, imagesDestPath); err != nil {
-				a.logger.WithError(err).Errorf("Failed to copy images directory for %s", wsName)
-				// Log error but continue
-			}
-		}
+		a.endSection()
 
-		// Copy asciicasts directory - try notebook location first, then docs/
-		asciicastsSrcPath := a.resolveAssetsDirForWorkspace(wsPath, "asciicasts")
-		if asciicastsSrcPath != "" {
-			asciicastsDestPath := filepath.Join(distDest, "asciicasts")
-			a.logger.Infof("Copying asciicasts for %s from %s to %s", wsName, asciicastsSrcPath, asciicastsDestPath)
-			if err := copyDir(asciicastsSrcPath, asciicastsDestPath); err != nil {
-				a.logger.WithError(err).Errorf("Failed to copy asciicasts directory for %s", wsName)
-				// Log error but continue
-			}
-		}
+		// Copy translations written by docgen translate
+		pkgManifest.Languages = a.aggregateTranslations(docsDir, distDest, wsName, sectionsToAggregate, docCfg, version, mode, transform)
 
-		// Copy videos directory - try notebook location first, then docs/
-		videosSrcPath := a.resolveAssetsDirForWorkspace(wsPath, "videos")
-		if videosSrcPath != "" {
-			videosDestPath := filepath.Join(distDest, "videos")
-			a.logger.Infof("Copying videos for %s from %s to %s", wsName, videosSrcPath, videosDestPath)
-			if err := copyDir(videosSrcPath, videosDestPath); err != nil {
-				a.logger.WithError(err).Errorf("Failed to copy videos directory for %s", wsName)
+		// Copy asset directories (images, asciicasts, videos, downloads and
+		// any from settings.assets) - try notebook location first, then docs/
+		for _, assetType := range docCfg.AssetTypes() {
+			assetSrcPath := a.resolveAssetsDirForWorkspace(wsPath, assetType.Dir)
+			if assetSrcPath == "" {
+				continue
+			}
+			assetDestPath := filepath.Join(distDest, assetType.Dir)
+			a.logger.Infof("Copying %s for %s from %s to %s", assetType.Dir, wsName, assetSrcPath, assetDestPath)
+			if err := copyDir(assetSrcPath, assetDestPath); err != nil {
+				a.logger.WithError(err).Errorf("Failed to copy %s directory for %s", assetType.Dir, wsName)
 				// Log error but continue
 			}
 		}
@@ -520,6 +691,7 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 					expandedPath := expandPath(logoPath)
 					if _, err := os.Stat(expandedPath); os.IsNotExist(err) {
 						a.logger.Warnf("Logo file not found for %s: %s", wsName, expandedPath)
+						a.addIssue(LevelWarning, IssueMissingAsset, wsName, "", expandedPath, "logo file not found")
 						continue
 					}
 					logoDestPath := filepath.Join(imagesDestPath, filepath.Base(expandedPath))
@@ -536,51 +708,60 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 			a.logger.Warnf("Failed to aggregate concepts for %s: %v", wsName, err)
 		}
 
+		// Annotate download links with the size and hash of the copied files
+		if err := transformer.AnnotateDownloadsInDir(distDest, filepath.Join(distDest, transformer.DownloadsDir)); err != nil {
+			a.logger.WithError(err).Warnf("Failed to annotate download links for %s", wsName)
+		}
+		a.publishVideos(ctx, distDest, docCfg, transform)
+
 		sort.Slice(sectionsToAggregate, func(i, j int) bool {
 			return sectionsToAggregate[i].Order < sectionsToAggregate[j].Order
 		})
 
 		for _, sec := range sectionsToAggregate {
+			if unpublished[sec.Output] {
+				continue
+			}
+			// When the doc was last generated; captured sections only exist in the output
+			modified := manifest.FileModTime(filepath.Join(docsDir, sec.Output))
+			if modified.IsZero() {
+				modified = manifest.FileModTime(filepath.Join(distDest, sec.Output))
+			}
+			output := sec.Output
+			if name, ok := published[sec.Output]; ok {
+				output = name
+			}
 			pkgManifest.Sections = append(pkgManifest.Sections, manifest.SectionManifest{
-				Title: sec.Title,
-				Path:  fmt.Sprintf("./%s/%s", wsName, sec.Output),
+				Title:       sec.Title,
+				Path:        fmt.Sprintf("./%s/%s", wsName, output),
+				Modified:    modified,
+				Status:      sec.GetStatus(),
+				Audience:    audiences[sec.Output],
+				Placeholder: placeholders[sec.Output],
 			})
+			for _, page := range splitPages[sec.Output] {
+				page.Path = fmt.Sprintf("./%s/%s", wsName, page.Path)
+				page.Status = sec.GetStatus()
+				page.Audience = audiences[sec.Output]
+				pkgManifest.Sections = append(pkgManifest.Sections, page)
+			}
 		}
 
-		// Check for and copy CHANGELOG.md if it exists
-		changelogSrc := filepath.Join(wsPath, "CHANGELOG.md")
-		if _, err := os.Stat(changelogSrc); err == nil {
-			changelogDest := filepath.Join(distDest, "CHANGELOG.md")
+		a.aggregateChangelog(wsPath, wsName, distDest, docCfg, version, mode, transform, &pkgManifest)
 
-			// Copy the CHANGELOG.md file
-			changelogData, err := os.ReadFile(changelogSrc) //nolint:gosec // path from workspace
+		// A package's own scrub rules apply to its files
+		if a.public() && len(docCfg.Settings.Scrub) > 0 {
+			rules, err := compileScrub(docCfg.Settings.Scrub)
+			if err == nil {
+				_, err = scrubDir(distDest, rules, nil)
+			}
 			if err != nil {
-				a.logger.WithError(err).Errorf("Failed to read CHANGELOG.md for %s", wsName)
-			} else {
-				// Apply Astro transformations if requested
-				if transform == "astro" {
-					trans := transformer.NewAstroTransformer()
-					opts := transformer.TransformOptions{
-						PackageName: wsName,
-						Title:       fmt.Sprintf("Changelog for %s", docCfg.Title),
-						Description: "",
-						Version:     version,
-						Category:    docCfg.Category,
-						Order:       999, // Changelogs go at the end
-					}
-					changelogData = trans.TransformStandardDoc(changelogData, opts)
-				}
-
-				if err := os.WriteFile(changelogDest, changelogData, 0o644); err != nil { //nolint:gosec // internal doc tool output
-					a.logger.WithError(err).Errorf("Failed to write CHANGELOG.md for %s", wsName)
-				} else {
-					// Update the manifest with the changelog path
-					pkgManifest.ChangelogPath = fmt.Sprintf("./%s/CHANGELOG.md", wsName)
-					a.logger.Infof("Copied CHANGELOG.md for %s", wsName)
-				}
+				// Unscrubbed files must not be published
+				a.logger.WithError(err).Errorf("Failed to scrub %s; leaving it out", wsName)
+				a.addIssue(LevelError, IssueSkippedPackage, wsName, "", "", "scrub failed: %v", err)
+				_ = os.RemoveAll(distDest)
+				continue
 			}
-		} else {
-			a.logger.Debugf("No CHANGELOG.md found for %s", wsName)
 		}
 
 		m.Packages = append(m.Packages, pkgManifest)
@@ -589,30 +770,6 @@ func (a *Aggregator) aggregateEcosystem(rootDir string, m *manifest.Manifest, ou
 	return nil
 }
 
-// getPackageVersion attempts to get the version from git tags or grove.yml
-func (a *Aggregator) getPackageVersion(wsPath string) string {
-	// Try to get version from git tags
-	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
-	cmd.Dir = wsPath
-	output, err := cmd.Output()
-	if err == nil {
-		version := strings.TrimSpace(string(output))
-		if version != "" {
-			return version
-		}
-	}
-
-	// Fall back to checking grove config for version info
-	if configPath, err := config.FindConfigFile(wsPath); err == nil {
-		if cfg, err := config.Load(configPath); err == nil && cfg.Version != "" {
-			return cfg.Version
-		}
-	}
-
-	// Default to "latest" if no version found
-	return "latest"
-}
-
 // getRepoURL attempts to get the repository URL from git remote
 func (a *Aggregator) getRepoURL(wsPath string) string {
 	cmd := exec.Command("git", "remote", "get-url", "origin")
@@ -633,6 +790,41 @@ func (a *Aggregator) getRepoURL(wsPath string) string {
 	return url
 }
 
+// expandIncludes inlines the include directives of a doc of the package at
+// repoDir (see transformer.ExpandIncludes), reporting those that cannot be
+// resolved as validation errors.
+func (a *Aggregator) expandIncludes(repoDir, pkg, section, file string, data []byte) []byte {
+	if !transformer.HasIncludes(string(data)) {
+		return data
+	}
+	expanded, errs := transformer.ExpandIncludes(string(data), docgenConfig.NewIncludeResolver(repoDir, a.logger))
+	for _, err := range errs {
+		a.logger.Warnf("Unresolved include in %s/%s: %v", pkg, filepath.Base(file), err)
+		a.addIssue(LevelError, IssueBrokenInclude, pkg, section, file, "%v", err)
+	}
+	return []byte(expanded)
+}
+
+// applyConditions keeps the conditional blocks of a doc that hold for the
+// build's mode and writer (see transformer.ApplyConditions), reporting
+// malformed ones as validation errors.
+func (a *Aggregator) applyConditions(pkg, section, file string, data []byte, mode, transform string) []byte {
+	if !transformer.HasConditions(string(data)) {
+		return data
+	}
+	target := transformer.TargetMarkdown
+	if transform == "astro" {
+		target = transformer.TargetAstro
+	}
+	out, err := transformer.ApplyConditions(string(data), transformer.Conditions{Target: target, Mode: mode, Profile: a.outputProfile()})
+	if err != nil {
+		a.logger.Warnf("Invalid conditional block in %s/%s: %v", pkg, filepath.Base(file), err)
+		a.addIssue(LevelError, IssueBadCondition, pkg, section, file, "%v", err)
+		return data
+	}
+	return []byte(out)
+}
+
 // resolveDocsDirForWorkspace finds the docs directory for a given workspace,
 // trying notebook location first, then falling back to repo docs/ directory.
 // Returns the path to the docs directory to use for reading documentation files.
@@ -713,6 +905,12 @@ func (a *Aggregator) resolveAssetsDirForWorkspace(wsPath, assetType string) stri
 // resolvePromptForWorkspace finds and reads a prompt file for a given workspace,
 // trying notebook location first, then falling back to legacy location.
 func (a *Aggregator) resolvePromptForWorkspace(wsPath, promptFile string) ([]byte, error) {
+	return os.ReadFile(a.resolvePromptPathForWorkspace(wsPath, promptFile)) //nolint:gosec // path from workspace
+}
+
+// resolvePromptPathForWorkspace returns where a workspace's prompt file is:
+// in the notebook if it exists there, otherwise the legacy location.
+func (a *Aggregator) resolvePromptPathForWorkspace(wsPath, promptFile string) string {
 	// Extract basename only for backward compatibility
 	promptBaseName := filepath.Base(promptFile)
 
@@ -721,8 +919,7 @@ func (a *Aggregator) resolvePromptForWorkspace(wsPath, promptFile string) ([]byt
 	if err != nil {
 		// Fallback: Can't resolve workspace, use legacy path
 		a.logger.Debugf("Could not resolve workspace for %s, trying legacy path", wsPath)
-		legacyPath := filepath.Join(wsPath, "docs", "prompts", promptFile)
-		return os.ReadFile(legacyPath) //nolint:gosec // path from workspace
+		return filepath.Join(wsPath, "docs", "prompts", promptFile)
 	}
 
 	// 2. Try notebook path first
@@ -733,9 +930,9 @@ func (a *Aggregator) resolvePromptForWorkspace(wsPath, promptFile string) ([]byt
 
 		if err == nil {
 			notebookPath := filepath.Join(notebookPromptsDir, promptBaseName)
-			if data, err := os.ReadFile(notebookPath); err == nil { //nolint:gosec // path from notebook
-				a.logger.Debugf("Loaded prompt '%s' from notebook: %s", promptBaseName, notebookPath)
-				return data, nil
+			if _, err := os.Stat(notebookPath); err == nil {
+				a.logger.Debugf("Found prompt '%s' in notebook: %s", promptBaseName, notebookPath)
+				return notebookPath
 			}
 		}
 	}
@@ -743,7 +940,7 @@ func (a *Aggregator) resolvePromptForWorkspace(wsPath, promptFile string) ([]byt
 	// 3. Fallback to legacy path
 	legacyPath := filepath.Join(wsPath, "docs", "prompts", promptFile)
 	a.logger.Debugf("Prompt not found in notebook, trying legacy path: %s", legacyPath)
-	return os.ReadFile(legacyPath) //nolint:gosec // path from workspace
+	return legacyPath
 }
 
 // applyStripLines removes specified number of lines from the beginning of content during aggregation
@@ -801,7 +998,7 @@ func copyDir(src, dst string) error {
 //
 // Each section subdirectory should have its own docgen.config.yml (like a mini-package),
 // mirroring the structure of package docgen directories (docs/, prompts/, images/, etc.)
-func (a *Aggregator) processWebsiteSections(wsPath string, cfg *docgenConfig.DocgenConfig, m *manifest.Manifest, outputDir, mode, transform string) {
+func (a *Aggregator) processWebsiteSections(ctx context.Context, wsPath string, cfg *docgenConfig.DocgenConfig, m *manifest.Manifest, outputDir, mode, transform string) {
 	wsName := filepath.Base(wsPath)
 	a.logger.Infof("Processing website sections for %s", wsName)
 
@@ -809,6 +1006,7 @@ func (a *Aggregator) processWebsiteSections(wsPath string, cfg *docgenConfig.Doc
 	node, err := workspace.GetProjectByPath(wsPath)
 	if err != nil {
 		a.logger.Warnf("Could not resolve workspace for %s, skipping sections: %v", wsPath, err)
+		a.addIssue(LevelError, IssueSkippedPackage, wsName, "", "", "could not resolve workspace: %v", err)
 		return
 	}
 
@@ -833,6 +1031,7 @@ func (a *Aggregator) processWebsiteSections(wsPath string, cfg *docgenConfig.Doc
 	entries, err := os.ReadDir(baseDocgenDir)
 	if err != nil {
 		a.logger.Warnf("Failed to read docgen dir %s: %v", baseDocgenDir, err)
+		a.addIssue(LevelError, IssueSkippedPackage, wsName, "", baseDocgenDir, "failed to read docgen dir: %v", err)
 		return
 	}
 
@@ -854,11 +1053,13 @@ func (a *Aggregator) processWebsiteSections(wsPath string, cfg *docgenConfig.Doc
 		sectionCfg, err := docgenConfig.LoadFromPath(sectionConfigPath)
 		if err != nil {
 			a.logger.Warnf("Failed to load config for section %s: %v", sectionName, err)
+			a.addIssue(LevelError, IssueSkippedPackage, sectionName, "", sectionConfigPath, "could not load config: %v", err)
 			continue
 		}
 
 		if !sectionCfg.Enabled {
 			a.logger.Debugf("Skipping section %s: disabled in config", sectionName)
+			a.addIssue(LevelInfo, IssueSkippedPackage, sectionName, "", "", "disabled in config")
 			continue
 		}
 
@@ -878,7 +1079,7 @@ func (a *Aggregator) processWebsiteSections(wsPath string, cfg *docgenConfig.Doc
 		}
 
 		// Copy assets from section directory
-		for _, assetType := range []string{"images", "asciicasts", "videos"} {
+		for _, assetType := range docgenConfig.AssetDirs(sectionCfg.AssetTypes()) {
 			assetSrc := filepath.Join(sectionDir, assetType)
 			if dirExists(assetSrc) {
 				assetDest := filepath.Join(destDir, assetType)
@@ -900,20 +1101,24 @@ func (a *Aggregator) processWebsiteSections(wsPath string, cfg *docgenConfig.Doc
 		// Process sections from the section's config (like a mini-package)
 		for _, sec := range sectionCfg.Sections {
 			status := sec.GetStatus()
-
-			// Filter by status
-			if status == docgenConfig.StatusDraft {
-				a.logger.Debugf("Skipping %s/%s (status: draft)", sectionName, sec.Output)
+			if !docgenConfig.Published(status, mode) {
+				a.logger.Debugf("Skipping %s/%s (status: %s, mode: %s)", sectionName, sec.Output, status, mode)
 				continue
 			}
-			if mode == "prod" && status == docgenConfig.StatusDev {
-				a.logger.Debugf("Skipping %s/%s (status: dev, mode: prod)", sectionName, sec.Output)
+			if !sec.InProfile(a.outputProfile()) {
+				a.logger.Debugf("Skipping internal section %s/%s", sectionName, sec.Output)
 				continue
 			}
 
 			srcFile := filepath.Join(docsDir, sec.Output)
+			audience := frontmatter.SectionAudience(sec, srcFile)
+			if !docgenConfig.ForAudience(audience, a.audiences) {
+				a.logger.Debugf("Skipping %s/%s (audience: %s)", sectionName, sec.Output, strings.Join(audience, ", "))
+				continue
+			}
 			if _, err := os.Stat(srcFile); os.IsNotExist(err) {
 				a.logger.Warnf("Doc file not found: %s", srcFile)
+				a.addIssue(LevelError, IssueMissingSection, sectionName, sec.Name, srcFile, "%s was not generated", sec.Output)
 				continue
 			}
 
@@ -921,34 +1126,70 @@ func (a *Aggregator) processWebsiteSections(wsPath string, cfg *docgenConfig.Doc
 			content, err := os.ReadFile(srcFile) //nolint:gosec // path from config
 			if err != nil {
 				a.logger.Warnf("Failed to read %s: %v", sec.Output, err)
+				a.addIssue(LevelError, IssueSectionFailed, sectionName, sec.Name, srcFile, "%v", err)
 				continue
 			}
+			destPath := filepath.Join(destDir, sec.Output)
+			content = a.expandIncludes(wsPath, sectionName, sec.Name, destPath, content)
+			content = a.applyConditions(sectionName, sec.Name, destPath, content, mode, transform)
+			output := sec.Output
+			components := docgenConfig.Components(sectionCfg.ComponentMappings(a.components))
+			if transform == "astro" && transformer.UsesComponents(string(content), components) {
+				output = transformer.MDXPath(sec.Output)
+				destPath = filepath.Join(destDir, output)
+			}
+			a.removeSibling(destPath)
+			a.links = append(a.links, pendingLinks{
+				pkg:       sectionName,
+				section:   sec.Name,
+				file:      destPath,
+				assetDirs: docgenConfig.AssetDirs(sectionCfg.AssetTypes()),
+				links:     transformer.LocalLinks(content),
+			})
 
 			// Apply Astro transformations if requested
+			metadata := a.buildMetadata(sectionDir, sectionCfg, sec, srcFile)
 			if transform == "astro" {
 				trans := transformer.NewAstroTransformer()
 				opts := transformer.TransformOptions{
 					SectionName: sectionName,
 					Category:    sectionCfg.Category,
+					AssetDirs:   docgenConfig.AssetDirs(sectionCfg.AssetTypes()),
+					Metadata:    metadata,
+					Components:  components,
+					MDX:         transformer.IsMDX(sec.Output),
 				}
 				content = trans.TransformWebsiteSection(content, opts)
+			} else {
+				content = transformer.InjectMetadata(content, metadata)
 			}
 
 			// Write file
-			destPath := filepath.Join(destDir, sec.Output)
 			if err := os.WriteFile(destPath, content, 0o644); err != nil { //nolint:gosec // internal doc tool output
 				a.logger.Warnf("Failed to write %s: %v", sec.Output, err)
+				a.addIssue(LevelError, IssueSectionFailed, sectionName, sec.Name, destPath, "%v", err)
 				continue
 			}
+			if transform == "astro" && transformer.IsMDX(destPath) {
+				a.checkMDX(sectionName, sec.Name, destPath, content)
+			}
 
 			websiteSection.Files = append(websiteSection.Files, manifest.SectionManifest{
-				Name:  sec.Output,
-				Title: sec.Title,
-				Order: sec.Order,
-				Path:  fmt.Sprintf("./%s/%s", sectionName, sec.Output),
+				Name:     sec.Output,
+				Title:    sec.Title,
+				Order:    sec.Order,
+				Path:     fmt.Sprintf("./%s/%s", sectionName, output),
+				Status:   status,
+				Audience: audience,
 			})
 		}
 
+		// Annotate download links with the size and hash of the copied files
+		if err := transformer.AnnotateDownloadsInDir(destDir, filepath.Join(destDir, transformer.DownloadsDir)); err != nil {
+			a.logger.Warnf("Failed to annotate download links for section %s: %v", sectionName, err)
+		}
+		a.publishVideos(ctx, destDir, sectionCfg, transform)
+
 		// Sort files by order
 		sort.Slice(websiteSection.Files, func(i, j int) bool {
 			return websiteSection.Files[i].Order < websiteSection.Files[j].Order
@@ -1034,12 +1275,8 @@ func (a *Aggregator) aggregateConcepts(wsPath string, wsName string, docCfg *doc
 			publishStatus = docgenConfig.StatusDraft // Default to draft (not published)
 		}
 
-		if publishStatus == docgenConfig.StatusDraft {
-			a.logger.Debugf("Skipping concept %s (docgen_publish: draft)", conceptID)
-			continue
-		}
-		if mode == "prod" && publishStatus == docgenConfig.StatusDev {
-			a.logger.Debugf("Skipping concept %s (docgen_publish: dev, mode: prod)", conceptID)
+		if !docgenConfig.Published(publishStatus, mode) {
+			a.logger.Debugf("Skipping concept %s (docgen_publish: %s, mode: %s)", conceptID, publishStatus, mode)
 			continue
 		}
 
@@ -1120,6 +1357,111 @@ concept_id: "%s"
 	return nil
 }
 
+// aggregateSplitPages copies the per-TUI pages of a split tui_keymaps section
+// listed in its page manifest and returns their manifest entries.
+func (a *Aggregator) aggregateSplitPages(docsDir, distDest, wsName string, section docgenConfig.SectionConfig, docCfg *docgenConfig.DocgenConfig, version, transform string) []manifest.SectionManifest {
+	splitDir := strings.TrimSuffix(section.Output, filepath.Ext(section.Output))
+	manifestPath := filepath.Join(docsDir, splitDir, manifest.SplitPagesFile)
+	data, err := os.ReadFile(manifestPath) //nolint:gosec // path from config
+	if err != nil {
+		a.logger.Warnf("No per-TUI page manifest for %s/%s: %v", wsName, section.Output, err)
+		return nil
+	}
+	var pages []manifest.SectionManifest
+	if err := json.Unmarshal(data, &pages); err != nil {
+		a.logger.Warnf("Invalid per-TUI page manifest %s: %v", manifestPath, err)
+		return nil
+	}
+
+	var copied []manifest.SectionManifest
+	for _, page := range pages {
+		srcData, err := os.ReadFile(filepath.Join(docsDir, page.Path)) //nolint:gosec // path from page manifest
+		if err != nil {
+			a.logger.Warnf("Failed to read per-TUI page %s: %v", page.Path, err)
+			continue
+		}
+		if transform == "astro" {
+			trans := transformer.NewAstroTransformer()
+			srcData = trans.TransformStandardDoc(srcData, transformer.TransformOptions{
+				PackageName: wsName,
+				Title:       page.Title,
+				Description: docCfg.Description,
+				Version:     version,
+				Category:    docCfg.Category,
+				Order:       page.Order,
+				AssetDirs:   docgenConfig.AssetDirs(docCfg.AssetTypes()),
+			})
+		}
+		destFile := filepath.Join(distDest, page.Path)
+		if err := os.MkdirAll(filepath.Dir(destFile), 0o755); err != nil { //nolint:gosec // internal doc tool
+			a.logger.WithError(err).Errorf("Failed to create directory for %s", destFile)
+			continue
+		}
+		if err := os.WriteFile(destFile, srcData, 0o644); err != nil { //nolint:gosec // internal doc tool output
+			a.logger.WithError(err).Errorf("Failed to write %s", destFile)
+			continue
+		}
+		copied = append(copied, page)
+	}
+	a.logger.Infof("Copied %d per-TUI pages for %s/%s", len(copied), wsName, section.Output)
+	return copied
+}
+
+// aggregateTranslations copies each language's translation of the aggregated
+// sections from docs/{lang}/ to {dist}/{lang}/ and returns the languages that
+// had at least one translated section.
+func (a *Aggregator) aggregateTranslations(docsDir, distDest, wsName string, sections []docgenConfig.SectionConfig, docCfg *docgenConfig.DocgenConfig, version, mode, transform string) []string {
+	var languages []string
+	for _, lang := range manifest.Languages(docsDir) {
+		copied := 0
+		for _, section := range sections {
+			srcFile := filepath.Join(docsDir, lang, section.Output)
+			srcData, err := os.ReadFile(srcFile) //nolint:gosec // path from config
+			if err != nil {
+				continue
+			}
+			if mode == "prod" {
+				srcData = citations.Strip(srcData)
+			}
+			srcData = a.applyConditions(wsName, section.Name, srcFile, srcData, mode, transform)
+			destFile := filepath.Join(distDest, lang, section.Output)
+			if transform == "astro" {
+				components := docgenConfig.Components(docCfg.ComponentMappings(a.components))
+				if transformer.UsesComponents(string(srcData), components) {
+					destFile = transformer.MDXPath(destFile)
+				}
+				a.removeSibling(destFile)
+				trans := transformer.NewAstroTransformer()
+				srcData = trans.TransformStandardDoc(srcData, transformer.TransformOptions{
+					PackageName: wsName,
+					Title:       section.Title,
+					Description: docCfg.Description,
+					Version:     version,
+					Category:    docCfg.Category,
+					Order:       section.Order,
+					AssetDirs:   docgenConfig.AssetDirs(docCfg.AssetTypes()),
+					Components:  components,
+					MDX:         transformer.IsMDX(section.Output),
+				})
+			}
+			if err := os.MkdirAll(filepath.Dir(destFile), 0o755); err != nil { //nolint:gosec // internal doc tool
+				a.logger.WithError(err).Errorf("Failed to create directory for %s", destFile)
+				continue
+			}
+			if err := os.WriteFile(destFile, srcData, 0o644); err != nil { //nolint:gosec // internal doc tool output
+				a.logger.WithError(err).Errorf("Failed to write %s", destFile)
+				continue
+			}
+			copied++
+		}
+		if copied > 0 {
+			a.logger.Infof("Copied %d %s translations for %s", copied, lang, wsName)
+			languages = append(languages, lang)
+		}
+	}
+	return languages
+}
+
 // stripMarkdownFrontmatter removes YAML frontmatter from markdown content
 func stripMarkdownFrontmatter(content string) string {
 	re := regexp.MustCompile(`(?s)^---\n.*?\n---\n*`)
@@ -1151,6 +1493,18 @@ func formatConceptTitle(name string) string {
 }
 
 // copyFile copies a single file from src to dst
+// removeSibling removes the page an earlier run published for the doc
+// whose page is path in the other format (see transformer.PageSibling).
+func (a *Aggregator) removeSibling(path string) {
+	sibling := transformer.PageSibling(path)
+	if sibling == "" {
+		return
+	}
+	if err := os.Remove(sibling); err != nil && !os.IsNotExist(err) {
+		a.logger.WithError(err).Warnf("Failed to remove %s", sibling)
+	}
+}
+
 func copyFile(src, dst string) error {
 	srcFile, err := os.Open(src)
 	if err != nil {
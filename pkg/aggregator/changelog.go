@@ -0,0 +1,68 @@
+package aggregator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/docgen/pkg/changelog"
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/manifest"
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// aggregateChangelog publishes the package's CHANGELOG.md, if it has one.
+// A changelog in the Keep a Changelog format is rewritten as a page with an
+// anchor per version, and its releases are recorded in the manifest; any
+// other is copied as it is. The unreleased changes are left out in prod
+// mode.
+func (a *Aggregator) aggregateChangelog(wsPath, wsName, distDest string, docCfg *docgenConfig.DocgenConfig, version, mode, transform string, pkgManifest *manifest.PackageManifest) {
+	changelogSrc := filepath.Join(wsPath, "CHANGELOG.md")
+	changelogData, err := os.ReadFile(changelogSrc) //nolint:gosec // path from workspace
+	if os.IsNotExist(err) {
+		a.logger.Debugf("No CHANGELOG.md found for %s", wsName)
+		return
+	}
+	if err != nil {
+		a.logger.WithError(err).Errorf("Failed to read CHANGELOG.md for %s", wsName)
+		return
+	}
+
+	title := fmt.Sprintf("Changelog for %s", docCfg.Title)
+	releases := changelog.Parse(changelogData)
+	if mode == "prod" {
+		releases = changelog.Released(releases)
+	}
+	if len(releases) > 0 {
+		changelogData = []byte(changelog.Render(title, releases))
+	}
+
+	// Apply Astro transformations if requested
+	if transform == "astro" {
+		trans := transformer.NewAstroTransformer()
+		opts := transformer.TransformOptions{
+			PackageName: wsName,
+			Title:       title,
+			Description: "",
+			Version:     version,
+			Category:    docCfg.Category,
+			Order:       999, // Changelogs go at the end
+			AssetDirs:   docgenConfig.AssetDirs(docCfg.AssetTypes()),
+		}
+		changelogData = trans.TransformStandardDoc(changelogData, opts)
+	}
+
+	changelogDest := filepath.Join(distDest, "CHANGELOG.md")
+	if err := os.WriteFile(changelogDest, changelogData, 0o644); err != nil { //nolint:gosec // internal doc tool output
+		a.logger.WithError(err).Errorf("Failed to write CHANGELOG.md for %s", wsName)
+		return
+	}
+	// Update the manifest with the changelog path and releases
+	pkgManifest.ChangelogPath = fmt.Sprintf("./%s/CHANGELOG.md", wsName)
+	pkgManifest.Releases = changelog.Released(releases)
+	if len(releases) > 0 {
+		a.logger.Infof("Published the changelog of %s with %d release(s)", wsName, len(pkgManifest.Releases))
+	} else {
+		a.logger.Infof("Copied CHANGELOG.md for %s", wsName)
+	}
+}
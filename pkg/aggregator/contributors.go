@@ -0,0 +1,42 @@
+package aggregator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/contributors"
+)
+
+// refreshContributors rebuilds a contributors section's page in the
+// package's docs, so every aggregate publishes the current maintainers and
+// contributors, and publishes its avatars next to destFile.
+func (a *Aggregator) refreshContributors(ctx context.Context, wsPath, wsName string, section docgenConfig.SectionConfig, srcFile, destFile string) {
+	page, err := contributors.Build(ctx, wsPath, contributors.Options{
+		Title:   section.Title,
+		Limit:   section.Limit,
+		Avatars: section.Avatars,
+	})
+	if err != nil {
+		a.logger.Warnf("Could not refresh contributors of %s/%s, publishing the last generated page: %v", wsName, section.Name, err)
+		return
+	}
+	for _, login := range page.Failed {
+		a.logger.Warnf("Could not fetch the avatar of %s for %s/%s", login, wsName, section.Name)
+	}
+	if err := os.MkdirAll(filepath.Dir(srcFile), 0o755); err != nil { //nolint:gosec // internal doc tool
+		a.logger.WithError(err).Errorf("Failed to create docs directory for %s", wsName)
+		return
+	}
+	if err := os.WriteFile(srcFile, []byte(page.Markdown), 0o644); err != nil { //nolint:gosec // generated doc
+		a.logger.WithError(err).Errorf("Failed to write %s", srcFile)
+		return
+	}
+	for _, dir := range []string{filepath.Dir(srcFile), filepath.Dir(destFile)} {
+		if err := page.WriteAvatars(dir); err != nil {
+			a.logger.WithError(err).Errorf("Failed to write avatars for %s/%s", wsName, section.Name)
+		}
+	}
+	a.logger.Infof("Refreshed contributors of %s/%s", wsName, section.Name)
+}
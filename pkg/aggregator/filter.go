@@ -0,0 +1,113 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/manifest"
+)
+
+// Filter limits an aggregate to some packages, so CI can rebuild part of a
+// site. Only the selected packages are copied, and only their manifest
+// entries are replaced; the rest of an existing manifest is kept. The zero
+// Filter selects every package.
+type Filter struct {
+	// Packages selects packages by workspace directory name, e.g. "grove-flow".
+	Packages []string
+	// Categories selects packages by category: the category of their config,
+	// their sidebar.package_category_override, or the sidebar category
+	// listing them.
+	Categories []string
+}
+
+// IsZero reports whether f selects every package.
+func (f Filter) IsZero() bool {
+	return len(f.Packages) == 0 && len(f.Categories) == 0
+}
+
+// matcher returns whether a package, by name and config, is selected.
+// Website section packages (output_mode: sections) are only selected by
+// name, since they belong to no category.
+func (f Filter) matcher(localCfg *docgenConfig.DocgenConfig) func(name string, cfg *docgenConfig.DocgenConfig) bool {
+	if f.IsZero() {
+		return func(string, *docgenConfig.DocgenConfig) bool { return true }
+	}
+	names := make(map[string]bool, len(f.Packages))
+	for _, p := range f.Packages {
+		names[p] = true
+	}
+	categories := make(map[string]bool, len(f.Categories))
+	for _, c := range f.Categories {
+		categories[strings.ToLower(c)] = true
+	}
+	var sidebar *docgenConfig.SidebarConfig
+	if localCfg != nil {
+		sidebar = localCfg.Sidebar
+	}
+
+	return func(name string, cfg *docgenConfig.DocgenConfig) bool {
+		if names[name] {
+			return true
+		}
+		if len(categories) == 0 || cfg.Settings.OutputMode == "sections" {
+			return false
+		}
+		if categories[strings.ToLower(cfg.Category)] {
+			return true
+		}
+		if sidebar == nil {
+			return false
+		}
+		if override, ok := sidebar.PackageCategoryOverride[name]; ok && categories[strings.ToLower(override)] {
+			return true
+		}
+		for catName, cat := range sidebar.Categories {
+			if !categories[strings.ToLower(catName)] {
+				continue
+			}
+			for _, p := range cat.Packages {
+				if p == name {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// mergePartial merges the manifest of a filtered aggregate into the manifest
+// already at path (see manifest.Merge). Without an existing manifest, partial
+// is returned as is.
+func mergePartial(path string, partial *manifest.Manifest, selected map[string]bool) (*manifest.Manifest, error) {
+	existing, err := manifest.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return partial, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing manifest: %w", err)
+	}
+	// Patching a dev manifest into a prod one would publish dev sections
+	if existing.Mode != "" && existing.Mode != partial.Mode {
+		return nil, fmt.Errorf("existing manifest was aggregated in %s mode, not %s: rebuild it without --packages/--category or use --mode %s", existing.Mode, partial.Mode, existing.Mode)
+	}
+	// Patching an internal manifest into a public one would publish internal
+	// sections
+	if existing.Profile != "" && existing.Profile != partial.Profile {
+		return nil, fmt.Errorf("existing manifest was aggregated with the %s profile, not %s: rebuild it without --packages/--category or use --profile %s", existing.Profile, partial.Profile, existing.Profile)
+	}
+	if !slices.Equal(existing.Audiences, partial.Audiences) {
+		return nil, fmt.Errorf("existing manifest was aggregated for audiences %v, not %v: rebuild it without --packages/--category or use the same --audience", existing.Audiences, partial.Audiences)
+	}
+	rebuilt := make([]string, 0, len(selected))
+	for name := range selected {
+		rebuilt = append(rebuilt, name)
+	}
+	sort.Strings(rebuilt)
+	existing.Merge(partial, rebuilt)
+	return existing, nil
+}
@@ -0,0 +1,70 @@
+package aggregator
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/grovetools/core/version"
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// buildMetadata returns the build metadata settings.metadata asks to record
+// for a section's page, or nil when it is off. doc is the generated file;
+// its modification time is when it was generated, and a doc that does not
+// exist yet (a capture) is generated now.
+func (a *Aggregator) buildMetadata(pkgDir string, cfg *docgenConfig.DocgenConfig, section docgenConfig.SectionConfig, doc string) *transformer.BuildMetadata {
+	m := cfg.Settings.Metadata
+	if m == nil {
+		return nil
+	}
+	meta := &transformer.BuildMetadata{MetaTags: m.MetaTags}
+	if m.Includes(docgenConfig.MetadataCommit) {
+		meta.Commit = a.commit(pkgDir)
+	}
+	if m.Includes(docgenConfig.MetadataVersion) {
+		meta.Version = version.GetInfo().Version
+	}
+	if m.Includes(docgenConfig.MetadataGeneratedAt) {
+		generated := time.Now()
+		if info, err := os.Stat(doc); err == nil {
+			generated = info.ModTime()
+		}
+		meta.GeneratedAt = generated.UTC().Format(time.RFC3339)
+	}
+	// Only prompt-driven sections are written by a model
+	if m.Includes(docgenConfig.MetadataModel) && section.Prompt != "" {
+		meta.Model = section.Model
+		if meta.Model == "" {
+			meta.Model = cfg.Settings.Model
+		}
+	}
+	return meta
+}
+
+// commit returns the git commit checked out at dir, suffixed with -dirty when
+// the tree has uncommitted changes, or "" outside a repository. Results are
+// cached for the run.
+func (a *Aggregator) commit(dir string) string {
+	if c, ok := a.commits[dir]; ok {
+		return c
+	}
+	var c string
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil {
+		c = strings.TrimSpace(string(out))
+		status := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+		status.Dir = dir
+		if out, err := status.Output(); err == nil && len(strings.TrimSpace(string(out))) > 0 {
+			c += "-dirty"
+		}
+	}
+	if a.commits == nil {
+		a.commits = make(map[string]string)
+	}
+	a.commits[dir] = c
+	return c
+}
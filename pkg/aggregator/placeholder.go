@@ -0,0 +1,40 @@
+package aggregator
+
+import (
+	"fmt"
+	"strings"
+
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+)
+
+// placeholderPage returns the page published in place of a section's doc
+// that was not generated, as settings.placeholders asks, or an error saying
+// why nothing is published. The page carries placeholder: true in its
+// frontmatter and a warning banner. Placeholders are never published in
+// prod mode.
+func (a *Aggregator) placeholderPage(wsPath string, section docgenConfig.SectionConfig, docCfg *docgenConfig.DocgenConfig, mode string) ([]byte, error) {
+	if mode == "prod" {
+		return nil, fmt.Errorf("placeholders are not published in prod mode")
+	}
+
+	var body string
+	switch placeholders := docCfg.PlaceholderMode(); placeholders {
+	case docgenConfig.PlaceholdersOff:
+		return nil, fmt.Errorf("placeholders are off")
+	case docgenConfig.PlaceholdersTodoPage:
+		body = "> [!WARNING]\n> This page is a placeholder. Its documentation has not been written yet.\n"
+	case docgenConfig.PlaceholdersPrompt:
+		promptData, err := a.resolvePromptForWorkspace(wsPath, section.Prompt)
+		if err != nil {
+			return nil, fmt.Errorf("no prompt to publish as a placeholder: %w", err)
+		}
+		body = fmt.Sprintf("> [!WARNING]\n> This page is a placeholder. Its documentation has not been generated yet; below is the prompt it will be generated from.\n\n---\n\n%s", promptData)
+	default:
+		return nil, fmt.Errorf("unknown settings.placeholders %q (want off, prompt or todo-page)", placeholders)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "---\ntitle: %q\nplaceholder: true\n---\n\n", section.Title)
+	fmt.Fprintf(&sb, "# %s\n\n%s", section.Title, body)
+	return []byte(sb.String()), nil
+}
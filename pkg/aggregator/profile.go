@@ -0,0 +1,156 @@
+package aggregator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/manifest"
+)
+
+// defaultScrubReplacement replaces the matches of a scrub rule without one.
+const defaultScrubReplacement = "[redacted]"
+
+// scrubExts are the extensions of the published files scrub rules apply to;
+// other files, such as images, are left alone.
+var scrubExts = map[string]bool{
+	".md": true, ".mdx": true, ".json": true, ".yml": true, ".yaml": true,
+	".txt": true, ".html": true, ".svg": true, ".cast": true,
+}
+
+// SetProfile sets the output profile: docgenConfig.ProfileInternal (the
+// default) publishes everything; docgenConfig.ProfilePublic leaves out
+// internal sections and blocks and applies the scrub rules.
+func (a *Aggregator) SetProfile(profile string) {
+	a.profile = profile
+}
+
+// SetAudiences limits the run to the sections written for one of audiences
+// (see docgenConfig.ForAudience); none publishes every section.
+func (a *Aggregator) SetAudiences(audiences []string) {
+	a.audiences = audiences
+}
+
+// outputProfile returns the run's output profile.
+func (a *Aggregator) outputProfile() string {
+	if a.profile == "" {
+		return docgenConfig.ProfileInternal
+	}
+	return a.profile
+}
+
+// public reports whether the run publishes for the public site.
+func (a *Aggregator) public() bool {
+	return a.outputProfile() == docgenConfig.ProfilePublic
+}
+
+// scrubRule is a compiled ScrubRule.
+type scrubRule struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+// compileScrub compiles scrub rules. An invalid pattern is an error, so a
+// public build never publishes what a rule meant to remove.
+func compileScrub(rules []docgenConfig.ScrubRule) ([]scrubRule, error) {
+	compiled := make([]scrubRule, 0, len(rules))
+	for _, r := range rules {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrub pattern %q: %w", r.Pattern, err)
+		}
+		replace := r.Replace
+		if replace == "" {
+			replace = defaultScrubReplacement
+		}
+		compiled = append(compiled, scrubRule{re: re, replace: replace})
+	}
+	return compiled, nil
+}
+
+// scrubDir applies scrub rules to the text files under dir, except those
+// skip returns true for, and returns the number of files changed.
+func scrubDir(dir string, rules []scrubRule, skip func(path string) bool) (int, error) {
+	if len(rules) == 0 {
+		return 0, nil
+	}
+	changed := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !scrubExts[strings.ToLower(filepath.Ext(path))] || (skip != nil && skip(path)) {
+			return nil
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // file in the output directory
+		if err != nil {
+			return err
+		}
+		scrubbed := data
+		for _, r := range rules {
+			scrubbed = r.re.ReplaceAll(scrubbed, []byte(r.replace))
+		}
+		if string(scrubbed) == string(data) {
+			return nil
+		}
+		changed++
+		return os.WriteFile(path, scrubbed, 0o644) //nolint:gosec // internal doc tool output
+	})
+	return changed, err
+}
+
+// scrubManifest applies scrub rules to the text of a manifest, such as
+// package descriptions and changelog entries. The rules see each string as
+// it reads, not JSON-escaped, so patterns with quotes or angle brackets
+// match.
+func scrubManifest(m *manifest.Manifest, rules []scrubRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if data, err = json.Marshal(scrubValue(doc, rules)); err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	scrubbed := &manifest.Manifest{}
+	if err := json.Unmarshal(data, scrubbed); err != nil {
+		return fmt.Errorf("scrub rules broke the manifest: %w", err)
+	}
+	*m = *scrubbed
+	return nil
+}
+
+// scrubValue applies scrub rules to the strings of a decoded JSON value.
+// Object keys are left alone.
+func scrubValue(v any, rules []scrubRule) any {
+	switch v := v.(type) {
+	case string:
+		for _, r := range rules {
+			v = r.re.ReplaceAllString(v, r.replace)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = scrubValue(v[i], rules)
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = scrubValue(v[k], rules)
+		}
+	}
+	return v
+}
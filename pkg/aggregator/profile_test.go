@@ -0,0 +1,114 @@
+package aggregator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/manifest"
+)
+
+func TestCompileScrub(t *testing.T) {
+	rules, err := compileScrub([]docgenConfig.ScrubRule{
+		{Pattern: `\bcorp\.internal\b`},
+		{Pattern: `ops@example\.com`, Replace: "support@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].replace != defaultScrubReplacement || rules[1].replace != "support@example.com" {
+		t.Errorf("replacements = %q, %q", rules[0].replace, rules[1].replace)
+	}
+	if _, err := compileScrub([]docgenConfig.ScrubRule{{Pattern: "("}}); err == nil {
+		t.Error("invalid pattern compiled")
+	}
+}
+
+func TestScrubManifest(t *testing.T) {
+	rules, err := compileScrub([]docgenConfig.ScrubRule{
+		{Pattern: `<[a-z.]+@corp\.internal>`, Replace: "<team@example.com>"},
+		{Pattern: `"codename [A-Z][a-z]+"`},
+		{Pattern: `a & b`, Replace: `c \ "d"`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &manifest.Manifest{Packages: []manifest.PackageManifest{{
+		Name:        "api",
+		Description: `Maintained by <ops.team@corp.internal>, known as "codename Falcon".`,
+		Sections:    []manifest.SectionManifest{{Name: "intro", Title: "a & b", Order: 2}},
+	}}}
+	if err := scrubManifest(m, rules); err != nil {
+		t.Fatal(err)
+	}
+	pkg := m.Packages[0]
+	if want := `Maintained by <team@example.com>, known as [redacted].`; pkg.Description != want {
+		t.Errorf("Description = %q, want %q", pkg.Description, want)
+	}
+	if want := `c \ "d"`; pkg.Sections[0].Title != want {
+		t.Errorf("Title = %q, want %q", pkg.Sections[0].Title, want)
+	}
+	if pkg.Name != "api" || pkg.Sections[0].Order != 2 {
+		t.Errorf("unmatched fields changed: %+v", pkg)
+	}
+}
+
+func TestScrubDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"docs/intro.md":    "Deploy to build.corp.internal.\n",
+		"docs/clean.md":    "Nothing to scrub.\n",
+		"docs/logo.png":    "build.corp.internal",
+		"docs/skipped.md":  "build.corp.internal\n",
+		"data/config.json": `{"host": "build.corp.internal"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rules, err := compileScrub([]docgenConfig.ScrubRule{{Pattern: `[a-z]+\.corp\.internal`, Replace: "example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := scrubDir(dir, rules, func(path string) bool { return strings.HasSuffix(path, "skipped.md") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 2 {
+		t.Errorf("changed = %d, want 2", changed)
+	}
+	want := map[string]string{
+		"docs/intro.md":    "Deploy to example.com.\n",
+		"docs/clean.md":    "Nothing to scrub.\n",
+		"docs/logo.png":    "build.corp.internal",
+		"docs/skipped.md":  "build.corp.internal\n",
+		"data/config.json": `{"host": "example.com"}`,
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestOutputProfile(t *testing.T) {
+	a := &Aggregator{}
+	if a.outputProfile() != docgenConfig.ProfileInternal || a.public() {
+		t.Errorf("default profile = %q, want internal", a.outputProfile())
+	}
+	a.SetProfile(docgenConfig.ProfilePublic)
+	if !a.public() {
+		t.Error("public profile not public")
+	}
+}
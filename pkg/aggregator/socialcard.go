@@ -0,0 +1,53 @@
+package aggregator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/logo"
+)
+
+// socialCard renders the Open Graph image for a section to distDest's og/
+// directory and returns its URL, or "" when social cards are off or the card
+// could not be rendered.
+func (a *Aggregator) socialCard(distDest, wsName string, cfg *docgenConfig.DocgenConfig, section docgenConfig.SectionConfig) string {
+	cards := cfg.SocialCards
+	if cards == nil {
+		return ""
+	}
+	logoPath := expandPath(cards.Logo)
+	if logoPath == "" {
+		for _, l := range cfg.Logos {
+			if strings.EqualFold(filepath.Ext(l), ".svg") {
+				logoPath = expandPath(l)
+				break
+			}
+		}
+	}
+	data, err := logo.New(a.logger).GenerateCard(logo.CardConfig{
+		Title:      section.Title,
+		Package:    wsName,
+		Category:   cfg.Category,
+		LogoPath:   logoPath,
+		FontPath:   expandPath(cards.Font),
+		Background: cards.Background,
+		TextColor:  cards.TextColor,
+		Accent:     cards.Accent,
+	})
+	if err != nil {
+		a.logger.Warnf("Failed to render social card for %s/%s: %v", wsName, section.Name, err)
+		return ""
+	}
+	ogDir := filepath.Join(distDest, "og")
+	if err := os.MkdirAll(ogDir, 0o755); err != nil { //nolint:gosec // internal doc tool
+		a.logger.Warnf("Failed to create og directory for %s: %v", wsName, err)
+		return ""
+	}
+	if err := os.WriteFile(filepath.Join(ogDir, section.Name+".png"), data, 0o644); err != nil { //nolint:gosec // internal doc tool output
+		a.logger.Warnf("Failed to write social card for %s/%s: %v", wsName, section.Name, err)
+		return ""
+	}
+	return strings.TrimRight(cards.SiteURL, "/") + "/docs/" + wsName + "/og/" + section.Name + ".png"
+}
@@ -0,0 +1,88 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/progress"
+	"github.com/grovetools/docgen/pkg/telemetry"
+	"github.com/sirupsen/logrus"
+)
+
+// openSection is the section being aggregated, traced from startSection
+// until the next one starts or endSection.
+type openSection struct {
+	pkg, name string
+	span      *telemetry.Span
+	task      *progress.Task
+	failure   string // Message of the error that failed the section
+}
+
+// startSection opens a section in the run report and the progress line and
+// starts its span under ctx, ending the previous section's.
+func (a *Aggregator) startSection(ctx context.Context, pkg, name, typ, dest string) {
+	a.report.StartSection(pkg, name, typ, dest)
+	a.endSectionSpan()
+	if typ == "" {
+		typ = "prose"
+	}
+	_, span := telemetry.Start(ctx, "docgen.aggregate.section",
+		telemetry.String("docgen.package", pkg),
+		telemetry.String("docgen.section", name),
+		telemetry.String("docgen.section.type", typ),
+		telemetry.String("docgen.output", dest))
+	a.section = openSection{pkg: pkg, name: name, span: span, task: a.progress.Start(pkg + "/" + name)}
+}
+
+// endSection closes the open section as succeeded.
+func (a *Aggregator) endSection() {
+	a.report.EndSection()
+	a.endSectionSpan()
+}
+
+// endSectionSpan ends the open section's span and progress entry and logs
+// its outcome as an aggregate.section event.
+func (a *Aggregator) endSectionSpan() {
+	a.section.span.SetOK()
+	a.section.span.End()
+	a.section.task.End()
+	if s := a.section; s.pkg != "" {
+		entry := a.logger.WithFields(logrus.Fields{
+			"event":   clilog.EventAggregateSection,
+			"package": s.pkg,
+			"section": s.name,
+			"status":  "ok",
+		})
+		if s.failure != "" {
+			entry.WithField("status", "failed").WithField("error", s.failure).Debugf("Section %s/%s failed", s.pkg, s.name)
+		} else {
+			entry.Debugf("Section %s/%s done", s.pkg, s.name)
+		}
+	}
+	a.section = openSection{}
+}
+
+// traceIssue logs an issue as an aggregate.issue event. An error about the
+// open section marks it, and its span, as failed; failed sections are
+// counted.
+func (a *Aggregator) traceIssue(issue Issue) {
+	a.logger.WithFields(logrus.Fields{
+		"event":    clilog.EventAggregateIssue,
+		"severity": issue.Level,
+		"kind":     issue.Kind,
+		"package":  issue.Package,
+		"section":  issue.Section,
+	}).Debug(issue.Message)
+	if issue.Level != LevelError {
+		return
+	}
+	if issue.Package == a.section.pkg && issue.Section == a.section.name {
+		a.section.failure = issue.Message
+		a.section.task.Fail()
+		a.section.span.RecordError(errors.New(issue.Message))
+	}
+	if issue.Kind == IssueSectionFailed {
+		telemetry.Add(telemetry.MetricSectionFailure, "{section}", 1, telemetry.String("command", "aggregate"))
+	}
+}
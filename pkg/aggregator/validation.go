@@ -0,0 +1,179 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// ValidationFile is the validation report aggregate writes to the output
+// directory.
+const ValidationFile = "validation-report.json"
+
+// Issue levels. Error-level issues fail an aggregate run in strict mode.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelInfo    = "info"
+)
+
+// Issue kinds.
+const (
+	IssuePlaceholder    = "placeholder"     // Doc not generated; its prompt was published in its place
+	IssueMissingSection = "missing_section" // Neither a doc nor a prompt to publish
+	IssueSectionFailed  = "section_failed"  // The section could not be captured, read or written
+	IssueSkippedPackage = "skipped_package"
+	IssueBrokenLink     = "broken_link"
+	IssueMissingAsset   = "missing_asset"
+	IssueStaleDoc       = "stale_doc"      // The prompt changed after the doc was generated
+	IssueBrokenInclude  = "broken_include" // An include directive names a file that cannot be read
+	IssueBadCondition   = "bad_condition"  // A conditional block is malformed or unbalanced
+	IssueInvalidMDX     = "invalid_mdx"    // An MDX page does not parse, or uses a component it does not import
+)
+
+// Issue is a problem found while aggregating.
+type Issue struct {
+	Level   string `json:"level"`
+	Kind    string `json:"kind"`
+	Package string `json:"package,omitempty"`
+	Section string `json:"section,omitempty"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationReport lists the issues of an aggregate run. Issues is always
+// present, empty for a clean run.
+type ValidationReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Mode        string    `json:"mode"`
+	Errors      int       `json:"errors"`
+	Warnings    int       `json:"warnings"`
+	Issues      []Issue   `json:"issues"`
+}
+
+// SetStrict makes aggregation fail when the validation report has any
+// error-level issue. The manifest and report are still written.
+func (a *Aggregator) SetStrict(strict bool) {
+	a.strict = strict
+}
+
+// addIssue records an issue for the validation report.
+func (a *Aggregator) addIssue(level, kind, pkg, section, file, format string, args ...any) {
+	issue := Issue{
+		Level:   level,
+		Kind:    kind,
+		Package: pkg,
+		Section: section,
+		File:    file,
+		Message: fmt.Sprintf(format, args...),
+	}
+	a.issues = append(a.issues, issue)
+	a.traceIssue(issue)
+}
+
+// writeValidationReport writes the run's issues to outputDir and returns
+// the report.
+func (a *Aggregator) writeValidationReport(outputDir, mode string) (*ValidationReport, error) {
+	r := &ValidationReport{GeneratedAt: time.Now(), Mode: mode, Issues: append([]Issue{}, a.issues...)}
+	for _, issue := range r.Issues {
+		switch issue.Level {
+		case LevelError:
+			r.Errors++
+		case LevelWarning:
+			r.Warnings++
+		}
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal validation report: %w", err)
+	}
+	path := filepath.Join(outputDir, ValidationFile)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil { //nolint:gosec // internal doc tool output
+		return nil, fmt.Errorf("failed to write validation report: %w", err)
+	}
+	a.report.AddOutput(path)
+	return r, nil
+}
+
+// pendingLinks are the local links of a copied doc. They are checked once
+// every package is in the output, since docs link across packages.
+type pendingLinks struct {
+	pkg       string
+	section   string
+	file      string   // The doc in the output directory
+	assetDirs []string // The package's asset directories
+	links     []transformer.LocalLink
+}
+
+// checkLinks records the links of the run's docs whose target is not in the
+// output: embeds and links into asset directories as missing assets, other
+// links as broken.
+func (a *Aggregator) checkLinks() {
+	for _, doc := range a.links {
+		isAsset := func(target string) bool {
+			first, _, _ := strings.Cut(strings.TrimPrefix(filepath.ToSlash(target), "./"), "/")
+			for _, dir := range doc.assetDirs {
+				if first == dir {
+					return true
+				}
+			}
+			return false
+		}
+		seen := make(map[string]bool)
+		for _, link := range doc.links {
+			if seen[link.Target] {
+				continue
+			}
+			seen[link.Target] = true
+			target := filepath.Join(filepath.Dir(doc.file), filepath.FromSlash(link.Target))
+			if _, err := os.Stat(target); err == nil {
+				continue
+			}
+			// A linked doc may have been published as MDX
+			if _, err := os.Stat(transformer.MDXPath(target)); err == nil && strings.HasSuffix(target, ".md") {
+				continue
+			}
+			if link.Embed || isAsset(link.Target) {
+				a.addIssue(LevelError, IssueMissingAsset, doc.pkg, doc.section, doc.file, "%s is not in the output", link.Target)
+			} else {
+				a.addIssue(LevelError, IssueBrokenLink, doc.pkg, doc.section, doc.file, "link target %s is not in the output", link.Target)
+			}
+		}
+	}
+}
+
+// checkMDX records an error for each problem that keeps the MDX page at
+// file from building: a syntax error or a component it does not import.
+func (a *Aggregator) checkMDX(pkg, section, file string, data []byte) {
+	for _, err := range transformer.ValidateMDX(string(data)) {
+		a.addIssue(LevelError, IssueInvalidMDX, pkg, section, file, "%v", err)
+	}
+	for _, name := range transformer.UndefinedComponents(string(data)) {
+		a.addIssue(LevelError, IssueInvalidMDX, pkg, section, file, "component %s is not imported; map it in settings.components", name)
+	}
+}
+
+// checkStale records a warning when a section's prompt was modified after
+// its doc was generated.
+func (a *Aggregator) checkStale(wsPath, pkg, section, prompt, doc string) {
+	if prompt == "" {
+		return
+	}
+	promptPath := a.resolvePromptPathForWorkspace(wsPath, prompt)
+	promptInfo, err := os.Stat(promptPath)
+	if err != nil {
+		return
+	}
+	docInfo, err := os.Stat(doc)
+	if err != nil {
+		return
+	}
+	if promptInfo.ModTime().After(docInfo.ModTime()) {
+		a.addIssue(LevelWarning, IssueStaleDoc, pkg, section, doc, "prompt %s changed after the doc was generated", prompt)
+	}
+}
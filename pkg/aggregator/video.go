@@ -0,0 +1,183 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	docgenConfig "github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// videoFormat is a transcode generated next to a video's mp4.
+type videoFormat struct {
+	suffix string // Appended to the video's base name
+	mime   string
+	args   []string // ffmpeg output options
+}
+
+// videoFormats are the supported transcodes, keyed by their name in
+// settings.assets.video.formats.
+var videoFormats = map[string]videoFormat{
+	"av1": {
+		suffix: ".av1.mp4",
+		mime:   `video/mp4; codecs="av01.0.05M.08"`,
+		args:   []string{"-c:v", "libaom-av1", "-crf", "35", "-b:v", "0", "-cpu-used", "6", "-row-mt", "1", "-c:a", "aac", "-movflags", "+faststart"},
+	},
+	"webm": {
+		suffix: ".webm",
+		mime:   "video/webm",
+		args:   []string{"-c:v", "libvpx-vp9", "-crf", "33", "-b:v", "0", "-row-mt", "1", "-c:a", "libopus"},
+	},
+}
+
+// videoFormatOrder is the order sources are offered in: the smallest first,
+// the original mp4 last as the fallback every browser plays.
+var videoFormatOrder = []string{"av1", "webm"}
+
+// publishVideos generates poster frames and transcodes for the mp4 videos in
+// distDest's videos/ directory and, for the Astro target, rewrites the
+// video embeds of the docs under distDest into <video> elements. It does
+// nothing unless settings.assets.video is set.
+func (a *Aggregator) publishVideos(ctx context.Context, distDest string, cfg *docgenConfig.DocgenConfig, transform string) {
+	if cfg.Settings.Assets == nil || cfg.Settings.Assets.Video == nil {
+		return
+	}
+	videosDir := filepath.Join(distDest, "videos")
+	if !dirExists(videosDir) {
+		return
+	}
+	variants := a.processVideos(ctx, videosDir, cfg.Settings.Assets.Video)
+	if transform != "astro" || len(variants) == 0 {
+		return
+	}
+	err := transformer.RewriteMarkdownFiles(distDest, func(content string) string {
+		return transformer.VideoElements(content, func(name string) (transformer.VideoVariants, bool) {
+			v, ok := variants[name]
+			return v, ok
+		})
+	})
+	if err != nil {
+		a.logger.WithError(err).Warnf("Failed to embed videos in %s", distDest)
+	}
+}
+
+// processVideos writes each video's poster frame (name.poster.jpg) and
+// transcodes (name.webm, name.av1.mp4) next to it and returns the files
+// published for each video, keyed by its file name. Outputs are cached by
+// the video's content hash, so unchanged videos are not transcoded again.
+// A variant ffmpeg fails to produce is left out with a warning.
+func (a *Aggregator) processVideos(ctx context.Context, videosDir string, opts *docgenConfig.VideoOptions) map[string]transformer.VideoVariants {
+	entries, err := os.ReadDir(videosDir)
+	if err != nil {
+		return nil
+	}
+	ffmpeg := opts.FFmpeg
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	if _, err := exec.LookPath(ffmpeg); err != nil {
+		a.logger.Warnf("%s not found; videos are published without posters or transcodes", ffmpeg)
+		ffmpeg = ""
+	}
+	posterAt := opts.PosterAt
+	if posterAt == "" {
+		posterAt = "00:00:01"
+	}
+	formats := append([]string(nil), opts.Formats...)
+	sort.SliceStable(formats, func(i, j int) bool {
+		return indexOf(videoFormatOrder, formats[i]) < indexOf(videoFormatOrder, formats[j])
+	})
+
+	variants := make(map[string]transformer.VideoVariants)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(name), ".mp4") || strings.HasSuffix(name, videoFormats["av1"].suffix) {
+			continue
+		}
+		src := filepath.Join(videosDir, name)
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		var v transformer.VideoVariants
+		if ffmpeg != "" {
+			info, err := transformer.DescribeDownload(src)
+			if err != nil {
+				a.logger.Warnf("Failed to read video %s: %v", src, err)
+				continue
+			}
+			hash := info.SHA256
+			if opts.PosterEnabled() {
+				poster := base + ".poster.jpg"
+				if err := a.videoVariant(ctx, ffmpeg, src, hash, "poster.jpg", filepath.Join(videosDir, poster),
+					[]string{"-ss", posterAt}, []string{"-frames:v", "1", "-q:v", "3"}); err != nil {
+					a.logger.Warnf("Failed to extract a poster frame from %s: %v", name, err)
+				} else {
+					v.Poster = poster
+				}
+			}
+			for _, f := range formats {
+				format, ok := videoFormats[f]
+				if !ok {
+					a.logger.Warnf("Unknown video format %q (supported: av1, webm)", f)
+					continue
+				}
+				out := base + format.suffix
+				if err := a.videoVariant(ctx, ffmpeg, src, hash, f+format.suffix, filepath.Join(videosDir, out), nil, format.args); err != nil {
+					a.logger.Warnf("Failed to transcode %s to %s: %v", name, f, err)
+					continue
+				}
+				v.Sources = append(v.Sources, transformer.VideoSource{Name: out, Type: format.mime})
+			}
+		}
+		v.Sources = append(v.Sources, transformer.VideoSource{Name: name, Type: "video/mp4"})
+		variants[name] = v
+	}
+	return variants
+}
+
+// videoVariant writes one ffmpeg output for the video src to dest, from the
+// cache when the video was processed before.
+func (a *Aggregator) videoVariant(ctx context.Context, ffmpeg, src, hash, variant, dest string, inputArgs, outputArgs []string) error {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	cached := filepath.Join(cacheDir, "docgen", "videos", hash[:16], variant)
+	if info, err := os.Stat(cached); err != nil || info.Size() == 0 {
+		if err := os.MkdirAll(filepath.Dir(cached), 0o755); err != nil { //nolint:gosec // cache directory
+			return fmt.Errorf("failed to create video cache: %w", err)
+		}
+		a.logger.Infof("Generating %s for %s", variant, filepath.Base(src))
+		tmp := cached + ".tmp" + filepath.Ext(cached)
+		args := append([]string{"-y", "-loglevel", "error"}, inputArgs...)
+		args = append(append(append(args, "-i", src), outputArgs...), tmp)
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, ffmpeg, args...) //nolint:gosec // ffmpeg from the package's own config
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			_ = os.Remove(tmp)
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		if info, err := os.Stat(tmp); err != nil || info.Size() == 0 {
+			_ = os.Remove(tmp)
+			return fmt.Errorf("ffmpeg wrote no output (is the video shorter than the poster timestamp?)")
+		}
+		if err := os.Rename(tmp, cached); err != nil {
+			return fmt.Errorf("failed to cache %s: %w", variant, err)
+		}
+	}
+	return copyFile(cached, dest)
+}
+
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return len(list)
+}
@@ -0,0 +1,154 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AnthropicBaseURL is the root of the Anthropic API.
+const AnthropicBaseURL = "https://api.anthropic.com/v1"
+
+// anthropicMaxTokens is the output limit of requests that set none; the
+// Messages API requires one.
+const anthropicMaxTokens = 8192
+
+// anthropicClient uses the Message Batches API.
+type anthropicClient struct {
+	*httpClient
+	baseURL string
+	key     string
+}
+
+func (c *anthropicClient) header() http.Header {
+	h := http.Header{}
+	h.Set("x-api-key", c.key)
+	h.Set("anthropic-version", "2023-06-01")
+	return h
+}
+
+func (c *anthropicClient) Submit(ctx context.Context, model string, requests []Request) (string, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type params struct {
+		Model       string    `json:"model"`
+		MaxTokens   int       `json:"max_tokens"`
+		Messages    []message `json:"messages"`
+		Temperature *float32  `json:"temperature,omitempty"`
+		TopP        *float32  `json:"top_p,omitempty"`
+		TopK        *int32    `json:"top_k,omitempty"`
+	}
+	type item struct {
+		CustomID string `json:"custom_id"`
+		Params   params `json:"params"`
+	}
+	body := struct {
+		Requests []item `json:"requests"`
+	}{}
+	for _, r := range requests {
+		p := params{
+			Model:       model,
+			MaxTokens:   anthropicMaxTokens,
+			Messages:    []message{{Role: "user", Content: r.Prompt}},
+			Temperature: r.Temperature,
+			TopP:        r.TopP,
+			TopK:        r.TopK,
+		}
+		if r.MaxOutputTokens != nil {
+			p.MaxTokens = int(*r.MaxOutputTokens)
+		}
+		body.Requests = append(body.Requests, item{CustomID: r.Key, Params: p})
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, c.baseURL+"/messages/batches", c.header(), body, "", &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+type anthropicBatch struct {
+	ProcessingStatus string `json:"processing_status"`
+	RequestCounts    struct {
+		Processing int `json:"processing"`
+		Succeeded  int `json:"succeeded"`
+		Errored    int `json:"errored"`
+		Canceled   int `json:"canceled"`
+		Expired    int `json:"expired"`
+	} `json:"request_counts"`
+	ResultsURL string `json:"results_url"`
+}
+
+func (c *anthropicClient) get(ctx context.Context, id string) (*anthropicBatch, error) {
+	var b anthropicBatch
+	if err := c.do(ctx, http.MethodGet, c.baseURL+"/messages/batches/"+id, c.header(), nil, "", &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *anthropicClient) Status(ctx context.Context, id string) (Status, error) {
+	b, err := c.get(ctx, id)
+	if err != nil {
+		return Status{}, err
+	}
+	n := b.RequestCounts
+	return Status{
+		State:     b.ProcessingStatus,
+		Done:      b.ProcessingStatus == "ended",
+		Total:     n.Processing + n.Succeeded + n.Errored + n.Canceled + n.Expired,
+		Succeeded: n.Succeeded,
+		Errored:   n.Errored + n.Canceled + n.Expired,
+	}, nil
+}
+
+func (c *anthropicClient) Results(ctx context.Context, id string) (map[string]Result, error) {
+	b, err := c.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.ResultsURL == "" {
+		return nil, fmt.Errorf("batch %s has no results yet (%s)", id, b.ProcessingStatus)
+	}
+	data, err := c.raw(ctx, http.MethodGet, b.ResultsURL, c.header(), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]Result)
+	err = jsonLines(data, func(line []byte) error {
+		var r struct {
+			CustomID string `json:"custom_id"`
+			Result   struct {
+				Type    string `json:"type"`
+				Message struct {
+					Content []struct {
+						Type string `json:"type"`
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"message"`
+				Error json.RawMessage `json:"error"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(line, &r); err != nil {
+			return fmt.Errorf("failed to decode batch result: %w", err)
+		}
+		if r.Result.Type != "succeeded" {
+			results[r.CustomID] = Result{Err: strings.TrimSpace(r.Result.Type + " " + string(r.Result.Error))}
+			return nil
+		}
+		var sb strings.Builder
+		for _, part := range r.Result.Message.Content {
+			if part.Type == "text" {
+				sb.WriteString(part.Text)
+			}
+		}
+		results[r.CustomID] = Result{Text: sb.String()}
+		return nil
+	})
+	return results, err
+}
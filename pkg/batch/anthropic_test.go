@@ -0,0 +1,84 @@
+package batch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnthropicClient(t *testing.T) {
+	api, srv := newFakeAPI(t, map[string]string{
+		"POST /messages/batches": `{"id": "msgbatch_1"}`,
+		"GET /messages/batches/msgbatch_1": `{
+			"processing_status": "ended",
+			"request_counts": {"processing": 0, "succeeded": 2, "errored": 1, "canceled": 1, "expired": 1},
+			"results_url": "URL/results/msgbatch_1"
+		}`,
+		"GET /results/msgbatch_1": `{"custom_id": "a", "result": {"type": "succeeded", "message": {"content": [{"type": "text", "text": "Hello "}, {"type": "tool_use"}, {"type": "text", "text": "world"}]}}}
+{"custom_id": "b", "result": {"type": "errored", "error": {"type": "invalid_request_error", "message": "too long"}}}
+
+{"custom_id": "c", "result": {"type": "expired"}}
+`,
+	})
+	api.responses["GET /messages/batches/msgbatch_1"] = replaceURL(api.responses["GET /messages/batches/msgbatch_1"], srv.URL)
+	c := &anthropicClient{httpClient: &httpClient{http: srv.Client()}, baseURL: srv.URL, key: "secret"}
+
+	id, err := c.Submit(t.Context(), "claude-test", []Request{
+		{Key: "a", Prompt: "first"},
+		{Key: "b", Prompt: "second", Temperature: ptr(float32(0.5)), TopP: ptr(float32(0.9)), TopK: ptr(int32(40)), MaxOutputTokens: ptr(int32(100))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "msgbatch_1" {
+		t.Errorf("Submit() = %q, want msgbatch_1", id)
+	}
+	h := api.headers["POST /messages/batches"]
+	if h.Get("x-api-key") != "secret" || h.Get("anthropic-version") == "" || h.Get("Content-Type") != "application/json" {
+		t.Errorf("submit headers = %v", h)
+	}
+	jsonEqual(t, "submitted batch", api.bodies["POST /messages/batches"], `{"requests": [
+		{"custom_id": "a", "params": {"model": "claude-test", "max_tokens": 8192, "messages": [{"role": "user", "content": "first"}]}},
+		{"custom_id": "b", "params": {"model": "claude-test", "max_tokens": 100, "messages": [{"role": "user", "content": "second"}],
+			"temperature": 0.5, "top_p": 0.9, "top_k": 40}}
+	]}`)
+
+	status, err := c.Status(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{State: "ended", Done: true, Total: 5, Succeeded: 2, Errored: 3}
+	if status != want {
+		t.Errorf("Status() = %+v, want %+v", status, want)
+	}
+
+	results, err := c.Results(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResults := map[string]Result{
+		"a": {Text: "Hello world"},
+		"b": {Err: `errored {"type": "invalid_request_error", "message": "too long"}`},
+		"c": {Err: "expired"},
+	}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("Results() = %+v, want %+v", results, wantResults)
+	}
+}
+
+func TestAnthropicResultsNotReady(t *testing.T) {
+	_, srv := newFakeAPI(t, map[string]string{
+		"GET /messages/batches/msgbatch_1": `{"processing_status": "in_progress", "request_counts": {"processing": 3}}`,
+	})
+	c := &anthropicClient{httpClient: &httpClient{http: srv.Client()}, baseURL: srv.URL, key: "secret"}
+
+	status, err := c.Status(t.Context(), "msgbatch_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Status{State: "in_progress", Total: 3}); status != want {
+		t.Errorf("Status() = %+v, want %+v", status, want)
+	}
+	if _, err := c.Results(t.Context(), "msgbatch_1"); err == nil {
+		t.Error("Results() of an unfinished batch succeeded")
+	}
+}
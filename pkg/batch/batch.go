@@ -0,0 +1,159 @@
+// Package batch submits LLM requests through the batch APIs of the Anthropic,
+// Gemini and OpenAI providers. A batch is processed asynchronously, within 24
+// hours, at about half the price of the same requests made one at a time.
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Request is one prompt of a batch.
+type Request struct {
+	// Key identifies the request's result: 1 to 64 letters, digits,
+	// hyphens or underscores, unique within the batch.
+	Key             string
+	Prompt          string
+	Temperature     *float32
+	TopP            *float32
+	TopK            *int32
+	MaxOutputTokens *int32
+}
+
+// Status is the progress of a submitted batch.
+type Status struct {
+	State     string // The provider's state, e.g. in_progress
+	Done      bool   // No request is processed any more
+	Failed    bool   // The batch as a whole failed, expired or was cancelled
+	Total     int
+	Succeeded int
+	Errored   int
+}
+
+// Result is the outcome of one request: its response text, or the error
+// the provider returned for it.
+type Result struct {
+	Text string
+	Err  string
+}
+
+// Client submits batches to one provider.
+type Client interface {
+	// Submit sends requests for model as one batch and returns its ID.
+	Submit(ctx context.Context, model string, requests []Request) (string, error)
+	Status(ctx context.Context, id string) (Status, error)
+	// Results returns the results of a finished batch by request key.
+	// Requests the batch did not process are missing.
+	Results(ctx context.Context, id string) (map[string]Result, error)
+}
+
+// Providers are the providers with a batch API.
+var Providers = []string{"anthropic", "gemini", "openai"}
+
+// Supported reports whether provider has a batch API.
+func Supported(provider string) bool {
+	for _, p := range Providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// New returns the batch client of provider authenticated with apiKey.
+func New(provider, apiKey string) (Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key for the %s batch API", provider)
+	}
+	h := &httpClient{http: &http.Client{Timeout: 5 * time.Minute}}
+	switch provider {
+	case "anthropic":
+		return &anthropicClient{httpClient: h, baseURL: AnthropicBaseURL, key: apiKey}, nil
+	case "gemini":
+		return &geminiClient{httpClient: h, baseURL: GeminiBaseURL, key: apiKey}, nil
+	case "openai":
+		return &openaiClient{httpClient: h, baseURL: OpenAIBaseURL, key: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("provider %q has no batch API (supported: %s)", provider, strings.Join(Providers, ", "))
+	}
+}
+
+// httpClient makes the JSON requests of the provider clients.
+type httpClient struct {
+	http *http.Client
+}
+
+// do sends a request and decodes a JSON response into out, when not nil.
+// body is JSON-encoded unless it is an io.Reader, sent as contentType.
+func (c *httpClient) do(ctx context.Context, method, url string, header http.Header, body any, contentType string, out any) error {
+	data, err := c.raw(ctx, method, url, header, body, contentType)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", url, err)
+	}
+	return nil
+}
+
+// raw sends a request and returns the response body.
+func (c *httpClient) raw(ctx context.Context, method, url string, header http.Header, body any, contentType string) ([]byte, error) {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", url, err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// jsonLines calls fn with each non-empty line of a JSONL document.
+func jsonLines(data []byte, fn func(line []byte) error) error {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package batch
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeAPI serves canned responses by "METHOD /path" and records what each
+// route was sent.
+type fakeAPI struct {
+	t         *testing.T
+	responses map[string]string
+	bodies    map[string][]byte
+	headers   map[string]http.Header
+}
+
+func newFakeAPI(t *testing.T, responses map[string]string) (*fakeAPI, *httptest.Server) {
+	api := &fakeAPI{t: t, responses: responses, bodies: map[string][]byte{}, headers: map[string]http.Header{}}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return api, srv
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := r.Method + " " + r.URL.Path
+	body, _ := io.ReadAll(r.Body)
+	a.bodies[route] = body
+	a.headers[route] = r.Header.Clone()
+	resp, ok := a.responses[route]
+	if !ok {
+		a.t.Errorf("unexpected request %s", route)
+		http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		return
+	}
+	_, _ = io.WriteString(w, resp)
+}
+
+// jsonEqual fails unless got and want are the same JSON value.
+func jsonEqual(t *testing.T, what string, got []byte, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("%s is not JSON: %v\n%s", what, err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("%s =\n%s\nwant\n%s", what, got, want)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestNew(t *testing.T) {
+	for _, provider := range Providers {
+		if !Supported(provider) {
+			t.Errorf("Supported(%q) = false", provider)
+		}
+		if _, err := New(provider, "key"); err != nil {
+			t.Errorf("New(%q) = %v", provider, err)
+		}
+		if _, err := New(provider, ""); err == nil {
+			t.Errorf("New(%q) accepted an empty API key", provider)
+		}
+	}
+	if Supported("ollama") {
+		t.Error("Supported(ollama) = true")
+	}
+	if _, err := New("ollama", "key"); err == nil {
+		t.Error("New(ollama) returned a client")
+	}
+}
+
+func TestHTTPErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"message": "overloaded"}}`, http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c := &anthropicClient{httpClient: &httpClient{http: srv.Client()}, baseURL: srv.URL, key: "k"}
+	_, err := c.Status(t.Context(), "msgbatch_1")
+	if err == nil || !strings.Contains(err.Error(), "503") || !strings.Contains(err.Error(), "overloaded") {
+		t.Errorf("Status() on a 503 = %v, want the status and body", err)
+	}
+}
+
+// replaceURL puts the fake server's URL into a canned response.
+func replaceURL(response, url string) string {
+	return strings.ReplaceAll(response, "URL", url)
+}
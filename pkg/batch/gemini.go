@@ -0,0 +1,189 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GeminiBaseURL is the root of the Gemini API.
+const GeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// geminiInlineLimit is the largest batch the Gemini API accepts inline; the
+// prompts are checked against it.
+const geminiInlineLimit = 20 << 20
+
+// geminiClient sends the requests inline to batchGenerateContent.
+type geminiClient struct {
+	*httpClient
+	baseURL string
+	key     string
+}
+
+func (c *geminiClient) header() http.Header {
+	h := http.Header{}
+	h.Set("x-goog-api-key", c.key)
+	return h
+}
+
+func (c *geminiClient) Submit(ctx context.Context, model string, requests []Request) (string, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role"`
+		Parts []part `json:"parts"`
+	}
+	type generationConfig struct {
+		Temperature     *float32 `json:"temperature,omitempty"`
+		TopP            *float32 `json:"topP,omitempty"`
+		TopK            *int32   `json:"topK,omitempty"`
+		MaxOutputTokens *int32   `json:"maxOutputTokens,omitempty"`
+	}
+	type request struct {
+		Request struct {
+			Contents         []content         `json:"contents"`
+			GenerationConfig *generationConfig `json:"generationConfig,omitempty"`
+		} `json:"request"`
+		Metadata map[string]string `json:"metadata"`
+	}
+	items := make([]request, 0, len(requests))
+	size := 0
+	for _, r := range requests {
+		var item request
+		item.Request.Contents = []content{{Role: "user", Parts: []part{{Text: r.Prompt}}}}
+		if r.Temperature != nil || r.TopP != nil || r.TopK != nil || r.MaxOutputTokens != nil {
+			item.Request.GenerationConfig = &generationConfig{r.Temperature, r.TopP, r.TopK, r.MaxOutputTokens}
+		}
+		item.Metadata = map[string]string{"key": r.Key}
+		items = append(items, item)
+		size += len(r.Prompt)
+	}
+	if size > geminiInlineLimit {
+		return "", fmt.Errorf("batch of %d requests is %d MB, over the %d MB the Gemini batch API accepts inline; batch fewer sections at a time",
+			len(requests), size>>20, geminiInlineLimit>>20)
+	}
+
+	body := map[string]any{
+		"batch": map[string]any{
+			"display_name": "docgen",
+			"input_config": map[string]any{
+				"requests": map[string]any{"requests": items},
+			},
+		},
+	}
+	var op struct {
+		Name string `json:"name"`
+	}
+	model = strings.TrimPrefix(model, "models/")
+	if err := c.do(ctx, http.MethodPost, c.baseURL+"/models/"+model+":batchGenerateContent", c.header(), body, "", &op); err != nil {
+		return "", err
+	}
+	return op.Name, nil
+}
+
+type geminiBatch struct {
+	Done     bool `json:"done"`
+	Metadata struct {
+		State      string `json:"state"`
+		BatchStats struct {
+			RequestCount           string `json:"requestCount"`
+			SuccessfulRequestCount string `json:"successfulRequestCount"`
+			FailedRequestCount     string `json:"failedRequestCount"`
+		} `json:"batchStats"`
+	} `json:"metadata"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Response struct {
+		InlinedResponses struct {
+			InlinedResponses []struct {
+				Response *struct {
+					Candidates []struct {
+						Content struct {
+							Parts []struct {
+								Text string `json:"text"`
+							} `json:"parts"`
+						} `json:"content"`
+						FinishReason string `json:"finishReason"`
+					} `json:"candidates"`
+				} `json:"response"`
+				Error *struct {
+					Message string `json:"message"`
+				} `json:"error"`
+				Metadata map[string]string `json:"metadata"`
+			} `json:"inlinedResponses"`
+		} `json:"inlinedResponses"`
+	} `json:"response"`
+}
+
+func (c *geminiClient) get(ctx context.Context, id string) (*geminiBatch, error) {
+	var b geminiBatch
+	if err := c.do(ctx, http.MethodGet, c.baseURL+"/"+id, c.header(), nil, "", &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *geminiClient) Status(ctx context.Context, id string) (Status, error) {
+	b, err := c.get(ctx, id)
+	if err != nil {
+		return Status{}, err
+	}
+	stats := b.Metadata.BatchStats
+	s := Status{
+		State:     strings.ToLower(strings.TrimPrefix(b.Metadata.State, "BATCH_STATE_")),
+		Done:      b.Done,
+		Total:     atoi(stats.RequestCount),
+		Succeeded: atoi(stats.SuccessfulRequestCount),
+		Errored:   atoi(stats.FailedRequestCount),
+	}
+	switch b.Metadata.State {
+	case "BATCH_STATE_FAILED", "BATCH_STATE_CANCELLED", "BATCH_STATE_EXPIRED":
+		s.Done, s.Failed = true, true
+	}
+	if b.Error != nil {
+		s.Failed = true
+		s.State += ": " + b.Error.Message
+	}
+	return s, nil
+}
+
+func (c *geminiClient) Results(ctx context.Context, id string) (map[string]Result, error) {
+	b, err := c.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !b.Done {
+		return nil, fmt.Errorf("batch %s has no results yet (%s)", id, b.Metadata.State)
+	}
+	results := make(map[string]Result)
+	for _, r := range b.Response.InlinedResponses.InlinedResponses {
+		key := r.Metadata["key"]
+		switch {
+		case r.Error != nil:
+			results[key] = Result{Err: r.Error.Message}
+		case r.Response == nil || len(r.Response.Candidates) == 0:
+			results[key] = Result{Err: "no candidates"}
+		default:
+			var sb strings.Builder
+			for _, p := range r.Response.Candidates[0].Content.Parts {
+				sb.WriteString(p.Text)
+			}
+			if sb.Len() == 0 {
+				results[key] = Result{Err: "empty response (finish reason " + r.Response.Candidates[0].FinishReason + ")"}
+				continue
+			}
+			results[key] = Result{Text: sb.String()}
+		}
+	}
+	return results, nil
+}
+
+// atoi parses the int64 counts the Gemini API sends as strings.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
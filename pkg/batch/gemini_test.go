@@ -0,0 +1,109 @@
+package batch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGeminiClient(t *testing.T) {
+	api, srv := newFakeAPI(t, map[string]string{
+		"POST /models/gemini-test:batchGenerateContent": `{"name": "batches/b1"}`,
+		"GET /batches/b1": `{
+			"done": true,
+			"metadata": {
+				"state": "BATCH_STATE_SUCCEEDED",
+				"batchStats": {"requestCount": "4", "successfulRequestCount": "1", "failedRequestCount": "3"}
+			},
+			"response": {"inlinedResponses": {"inlinedResponses": [
+				{"metadata": {"key": "a"}, "response": {"candidates": [{"content": {"parts": [{"text": "Hello "}, {"text": "world"}]}}]}},
+				{"metadata": {"key": "b"}, "error": {"message": "quota exceeded"}},
+				{"metadata": {"key": "c"}, "response": {"candidates": []}},
+				{"metadata": {"key": "d"}, "response": {"candidates": [{"content": {"parts": []}, "finishReason": "SAFETY"}]}}
+			]}}
+		}`,
+	})
+	c := &geminiClient{httpClient: &httpClient{http: srv.Client()}, baseURL: srv.URL, key: "secret"}
+
+	id, err := c.Submit(t.Context(), "models/gemini-test", []Request{
+		{Key: "a", Prompt: "first"},
+		{Key: "b", Prompt: "second", Temperature: ptr(float32(0.5)), TopK: ptr(int32(40)), MaxOutputTokens: ptr(int32(100))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "batches/b1" {
+		t.Errorf("Submit() = %q, want batches/b1", id)
+	}
+	route := "POST /models/gemini-test:batchGenerateContent"
+	if got := api.headers[route].Get("x-goog-api-key"); got != "secret" {
+		t.Errorf("submit x-goog-api-key = %q", got)
+	}
+	jsonEqual(t, "submitted batch", api.bodies[route], `{"batch": {"display_name": "docgen", "input_config": {"requests": {"requests": [
+		{"request": {"contents": [{"role": "user", "parts": [{"text": "first"}]}]}, "metadata": {"key": "a"}},
+		{"request": {"contents": [{"role": "user", "parts": [{"text": "second"}]}],
+			"generationConfig": {"temperature": 0.5, "topK": 40, "maxOutputTokens": 100}}, "metadata": {"key": "b"}}
+	]}}}}`)
+
+	status, err := c.Status(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{State: "succeeded", Done: true, Total: 4, Succeeded: 1, Errored: 3}
+	if status != want {
+		t.Errorf("Status() = %+v, want %+v", status, want)
+	}
+
+	results, err := c.Results(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResults := map[string]Result{
+		"a": {Text: "Hello world"},
+		"b": {Err: "quota exceeded"},
+		"c": {Err: "no candidates"},
+		"d": {Err: "empty response (finish reason SAFETY)"},
+	}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("Results() = %+v, want %+v", results, wantResults)
+	}
+}
+
+func TestGeminiStatus(t *testing.T) {
+	cases := []struct {
+		batch string
+		want  Status
+	}{
+		{`{"metadata": {"state": "BATCH_STATE_RUNNING", "batchStats": {"requestCount": "2"}}}`,
+			Status{State: "running", Total: 2}},
+		{`{"metadata": {"state": "BATCH_STATE_EXPIRED"}}`,
+			Status{State: "expired", Done: true, Failed: true}},
+		{`{"done": true, "metadata": {"state": "BATCH_STATE_FAILED"}, "error": {"message": "bad request"}}`,
+			Status{State: "failed: bad request", Done: true, Failed: true}},
+	}
+	for _, c := range cases {
+		_, srv := newFakeAPI(t, map[string]string{"GET /batches/b1": c.batch})
+		client := &geminiClient{httpClient: &httpClient{http: srv.Client()}, baseURL: srv.URL, key: "secret"}
+		got, err := client.Status(t.Context(), "batches/b1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("Status() of %s = %+v, want %+v", c.batch, got, c.want)
+		}
+		if !got.Done {
+			if _, err := client.Results(t.Context(), "batches/b1"); err == nil {
+				t.Errorf("Results() of unfinished %s succeeded", c.batch)
+			}
+		}
+	}
+}
+
+func TestGeminiInlineLimit(t *testing.T) {
+	c := &geminiClient{httpClient: &httpClient{}, baseURL: "http://unused.invalid", key: "secret"}
+	big := strings.Repeat("x", geminiInlineLimit/2+1)
+	_, err := c.Submit(t.Context(), "gemini-test", []Request{{Key: "a", Prompt: big}, {Key: "b", Prompt: big}})
+	if err == nil || !strings.Contains(err.Error(), "inline") {
+		t.Errorf("Submit() over the inline limit = %v", err)
+	}
+}
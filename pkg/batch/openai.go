@@ -0,0 +1,195 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+)
+
+// OpenAIBaseURL is the root of the OpenAI API.
+const OpenAIBaseURL = "https://api.openai.com/v1"
+
+// openaiClient uploads the requests as a JSONL file and runs them through
+// the Batch API's chat completions endpoint.
+type openaiClient struct {
+	*httpClient
+	baseURL string
+	key     string
+}
+
+func (c *openaiClient) header() http.Header {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer "+c.key)
+	return h
+}
+
+func (c *openaiClient) Submit(ctx context.Context, model string, requests []Request) (string, error) {
+	var lines bytes.Buffer
+	enc := json.NewEncoder(&lines)
+	for _, r := range requests {
+		body := map[string]any{
+			"model":    model,
+			"messages": []map[string]string{{"role": "user", "content": r.Prompt}},
+		}
+		if r.Temperature != nil {
+			body["temperature"] = *r.Temperature
+		}
+		if r.TopP != nil {
+			body["top_p"] = *r.TopP
+		}
+		if r.MaxOutputTokens != nil {
+			body["max_completion_tokens"] = *r.MaxOutputTokens
+		}
+		if err := enc.Encode(map[string]any{
+			"custom_id": r.Key,
+			"method":    "POST",
+			"url":       "/v1/chat/completions",
+			"body":      body,
+		}); err != nil {
+			return "", fmt.Errorf("failed to encode batch request: %w", err)
+		}
+	}
+
+	var form bytes.Buffer
+	w := multipart.NewWriter(&form)
+	if err := w.WriteField("purpose", "batch"); err != nil {
+		return "", err
+	}
+	part, err := w.CreateFormFile("file", "docgen-batch.jsonl")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(lines.Bytes()); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, c.baseURL+"/files", c.header(), &form, w.FormDataContentType(), &file); err != nil {
+		return "", fmt.Errorf("failed to upload batch requests: %w", err)
+	}
+
+	var batch struct {
+		ID string `json:"id"`
+	}
+	err = c.do(ctx, http.MethodPost, c.baseURL+"/batches", c.header(), map[string]string{
+		"input_file_id":     file.ID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	}, "", &batch)
+	if err != nil {
+		return "", err
+	}
+	return batch.ID, nil
+}
+
+type openaiBatch struct {
+	Status        string `json:"status"`
+	RequestCounts struct {
+		Total     int `json:"total"`
+		Completed int `json:"completed"`
+		Failed    int `json:"failed"`
+	} `json:"request_counts"`
+	OutputFileID string `json:"output_file_id"`
+	ErrorFileID  string `json:"error_file_id"`
+	Errors       *struct {
+		Data []struct {
+			Message string `json:"message"`
+		} `json:"data"`
+	} `json:"errors"`
+}
+
+func (c *openaiClient) get(ctx context.Context, id string) (*openaiBatch, error) {
+	var b openaiBatch
+	if err := c.do(ctx, http.MethodGet, c.baseURL+"/batches/"+id, c.header(), nil, "", &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *openaiClient) Status(ctx context.Context, id string) (Status, error) {
+	b, err := c.get(ctx, id)
+	if err != nil {
+		return Status{}, err
+	}
+	s := Status{
+		State:     b.Status,
+		Total:     b.RequestCounts.Total,
+		Succeeded: b.RequestCounts.Completed,
+		Errored:   b.RequestCounts.Failed,
+	}
+	switch b.Status {
+	case "completed":
+		s.Done = true
+	case "failed", "expired", "cancelled":
+		s.Done, s.Failed = true, true
+		if b.Errors != nil && len(b.Errors.Data) > 0 {
+			s.State += ": " + b.Errors.Data[0].Message
+		}
+	}
+	return s, nil
+}
+
+func (c *openaiClient) Results(ctx context.Context, id string) (map[string]Result, error) {
+	b, err := c.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]Result)
+	for _, fileID := range []string{b.OutputFileID, b.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		data, err := c.raw(ctx, http.MethodGet, c.baseURL+"/files/"+fileID+"/content", c.header(), nil, "")
+		if err != nil {
+			return nil, err
+		}
+		err = jsonLines(data, func(line []byte) error {
+			var r struct {
+				CustomID string `json:"custom_id"`
+				Response *struct {
+					StatusCode int `json:"status_code"`
+					Body       struct {
+						Choices []struct {
+							Message struct {
+								Content string `json:"content"`
+							} `json:"message"`
+						} `json:"choices"`
+						Error *struct {
+							Message string `json:"message"`
+						} `json:"error"`
+					} `json:"body"`
+				} `json:"response"`
+				Error *struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(line, &r); err != nil {
+				return fmt.Errorf("failed to decode batch result: %w", err)
+			}
+			switch {
+			case r.Error != nil:
+				results[r.CustomID] = Result{Err: r.Error.Message}
+			case r.Response == nil:
+				results[r.CustomID] = Result{Err: "no response"}
+			case r.Response.Body.Error != nil:
+				results[r.CustomID] = Result{Err: r.Response.Body.Error.Message}
+			case r.Response.StatusCode != http.StatusOK || len(r.Response.Body.Choices) == 0:
+				results[r.CustomID] = Result{Err: fmt.Sprintf("status %d without a completion", r.Response.StatusCode)}
+			default:
+				results[r.CustomID] = Result{Text: r.Response.Body.Choices[0].Message.Content}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
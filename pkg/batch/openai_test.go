@@ -0,0 +1,140 @@
+package batch
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIClient(t *testing.T) {
+	api, srv := newFakeAPI(t, map[string]string{
+		"POST /files":   `{"id": "file-in"}`,
+		"POST /batches": `{"id": "batch_1"}`,
+		"GET /batches/batch_1": `{
+			"status": "completed",
+			"request_counts": {"total": 5, "completed": 2, "failed": 3},
+			"output_file_id": "file-out",
+			"error_file_id": "file-err"
+		}`,
+		"GET /files/file-out/content": `{"custom_id": "a", "response": {"status_code": 200, "body": {"choices": [{"message": {"content": "Hello"}}]}}}
+{"custom_id": "b", "response": {"status_code": 400, "body": {"error": {"message": "context too long"}}}}
+{"custom_id": "c", "response": {"status_code": 500, "body": {}}}
+`,
+		"GET /files/file-err/content": `{"custom_id": "d", "error": {"message": "expired"}}
+
+{"custom_id": "e"}
+`,
+	})
+	c := &openaiClient{httpClient: &httpClient{http: srv.Client()}, baseURL: srv.URL, key: "secret"}
+
+	id, err := c.Submit(t.Context(), "gpt-test", []Request{
+		{Key: "a", Prompt: "first"},
+		{Key: "b", Prompt: "second", Temperature: ptr(float32(0.5)), TopP: ptr(float32(0.25)), TopK: ptr(int32(40)), MaxOutputTokens: ptr(int32(100))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "batch_1" {
+		t.Errorf("Submit() = %q, want batch_1", id)
+	}
+	if got := api.headers["POST /files"].Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("upload Authorization = %q", got)
+	}
+
+	// The requests are uploaded as a JSONL file for the batch to read
+	fields := readMultipart(t, api.headers["POST /files"].Get("Content-Type"), api.bodies["POST /files"])
+	if fields["purpose"] != "batch" {
+		t.Errorf("upload purpose = %q, want batch", fields["purpose"])
+	}
+	lines := strings.Split(strings.TrimSpace(fields["file"]), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("uploaded %d request lines, want 2:\n%s", len(lines), fields["file"])
+	}
+	jsonEqual(t, "first request line", []byte(lines[0]), `{"custom_id": "a", "method": "POST", "url": "/v1/chat/completions",
+		"body": {"model": "gpt-test", "messages": [{"role": "user", "content": "first"}]}}`)
+	jsonEqual(t, "second request line", []byte(lines[1]), `{"custom_id": "b", "method": "POST", "url": "/v1/chat/completions",
+		"body": {"model": "gpt-test", "messages": [{"role": "user", "content": "second"}],
+			"temperature": 0.5, "top_p": 0.25, "max_completion_tokens": 100}}`)
+	jsonEqual(t, "created batch", api.bodies["POST /batches"],
+		`{"input_file_id": "file-in", "endpoint": "/v1/chat/completions", "completion_window": "24h"}`)
+
+	status, err := c.Status(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{State: "completed", Done: true, Total: 5, Succeeded: 2, Errored: 3}
+	if status != want {
+		t.Errorf("Status() = %+v, want %+v", status, want)
+	}
+
+	results, err := c.Results(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResults := map[string]Result{
+		"a": {Text: "Hello"},
+		"b": {Err: "context too long"},
+		"c": {Err: "status 500 without a completion"},
+		"d": {Err: "expired"},
+		"e": {Err: "no response"},
+	}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("Results() = %+v, want %+v", results, wantResults)
+	}
+}
+
+func TestOpenAIStatus(t *testing.T) {
+	cases := []struct {
+		batch string
+		want  Status
+	}{
+		{`{"status": "in_progress", "request_counts": {"total": 4, "completed": 1}}`,
+			Status{State: "in_progress", Total: 4, Succeeded: 1}},
+		{`{"status": "failed", "errors": {"data": [{"message": "invalid JSONL"}]}}`,
+			Status{State: "failed: invalid JSONL", Done: true, Failed: true}},
+		{`{"status": "expired", "request_counts": {"total": 4, "completed": 3, "failed": 1}}`,
+			Status{State: "expired", Done: true, Failed: true, Total: 4, Succeeded: 3, Errored: 1}},
+		{`{"status": "cancelled"}`,
+			Status{State: "cancelled", Done: true, Failed: true}},
+	}
+	for _, c := range cases {
+		_, srv := newFakeAPI(t, map[string]string{"GET /batches/batch_1": c.batch})
+		client := &openaiClient{httpClient: &httpClient{http: srv.Client()}, baseURL: srv.URL, key: "secret"}
+		got, err := client.Status(t.Context(), "batch_1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("Status() of %s = %+v, want %+v", c.batch, got, c.want)
+		}
+	}
+}
+
+// readMultipart returns the fields and file contents of a multipart form.
+func readMultipart(t *testing.T, contentType string, body []byte) map[string]string {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	fields := make(map[string]string)
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return fields
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields[part.FormName()] = string(data)
+	}
+}
@@ -0,0 +1,244 @@
+package capture
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// termColor is a terminal color: colorDefault, an index into the
+// 256-color palette, or a 24-bit RGB value marked with colorRGB.
+type termColor int32
+
+const (
+	colorDefault termColor = -1
+	colorRGB     termColor = 1 << 24
+)
+
+func rgbColor(r, g, b int) termColor {
+	return colorRGB | termColor(clampByte(r)<<16|clampByte(g)<<8|clampByte(b))
+}
+
+func clampByte(v int) int {
+	return min(max(v, 0), 255)
+}
+
+// basic returns the color's index in the 16 ANSI colors the website theme
+// defines classes for.
+func (c termColor) basic() (int, bool) {
+	return int(c), c >= 0 && c < 16
+}
+
+// hex returns the color as #rrggbb. Indexes 16-255 map to the standard
+// xterm color cube and grayscale ramp; the 16 ANSI colors have no fixed
+// value and take palette's.
+func (c termColor) hex(palette *[16]string) string {
+	switch {
+	case c&colorRGB != 0:
+		return fmt.Sprintf("#%06x", int32(c&^colorRGB))
+	case c >= 0 && c < 16:
+		return palette[c]
+	case c >= 16 && c < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		i := int(c) - 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[i/36], levels[i/6%6], levels[i%6])
+	case c >= 232 && c < 256:
+		v := 8 + 10*(int(c)-232)
+		return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+	}
+	return ""
+}
+
+// cellStyle is the SGR (Select Graphic Rendition) state of terminal text.
+type cellStyle struct {
+	fg, bg                       termColor
+	bold, dim, italic, underline bool
+	reverse, strike              bool
+}
+
+var defaultStyle = cellStyle{fg: colorDefault, bg: colorDefault}
+
+// colors returns the style's foreground and background as drawn, swapped
+// by reverse video.
+func (st cellStyle) colors() (fg, bg termColor) {
+	if st.reverse {
+		return st.bg, st.fg
+	}
+	return st.fg, st.bg
+}
+
+// applySGR applies SGR parameters (the part of ESC[...m between [ and m)
+// to a style. Extended colors are accepted in both the 38;5;N form and the
+// 38:5:N sub-parameter form, as are 38;2;R;G;B and 38:2::R:G:B.
+func applySGR(style cellStyle, params string) cellStyle {
+	if params == "" {
+		return defaultStyle
+	}
+	parts := strings.Split(params, ";")
+	num := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	for i := 0; i < len(parts); i++ {
+		sub := strings.Split(parts[i], ":")
+		p := num(sub[0])
+		switch {
+		case p == 0:
+			style = defaultStyle
+		case p == 1:
+			style.bold = true
+		case p == 2:
+			style.dim = true
+		case p == 3:
+			style.italic = true
+		case p == 4:
+			style.underline = len(sub) < 2 || sub[1] != "0" // 4:0 turns underline off
+		case p == 7:
+			style.reverse = true
+		case p == 9:
+			style.strike = true
+		case p == 21:
+			style.underline = true // Double underline, drawn single
+		case p == 22:
+			style.bold, style.dim = false, false
+		case p == 23:
+			style.italic = false
+		case p == 24:
+			style.underline = false
+		case p == 27:
+			style.reverse = false
+		case p == 29:
+			style.strike = false
+		case p >= 30 && p <= 37:
+			style.fg = termColor(p - 30)
+		case p >= 90 && p <= 97:
+			style.fg = termColor(p - 90 + 8)
+		case p == 39:
+			style.fg = colorDefault
+		case p >= 40 && p <= 47:
+			style.bg = termColor(p - 40)
+		case p >= 100 && p <= 107:
+			style.bg = termColor(p - 100 + 8)
+		case p == 49:
+			style.bg = colorDefault
+		case p == 38 || p == 48:
+			var args []string
+			if len(sub) > 1 {
+				args = sub[1:]
+			} else {
+				// Semicolon form: the color's arguments are the next parameters
+				n := 2
+				if i+1 < len(parts) && parts[i+1] == "2" {
+					n = 4
+				}
+				end := min(i+1+n, len(parts))
+				args = parts[i+1 : end]
+				i = end - 1
+			}
+			color, ok := extendedColor(args, num)
+			if !ok {
+				continue
+			}
+			if p == 38 {
+				style.fg = color
+			} else {
+				style.bg = color
+			}
+		}
+	}
+	return style
+}
+
+// extendedColor parses the arguments of an extended color: 5;N or 2;R;G;B
+// (with an optional color space id before R in the colon form).
+func extendedColor(args []string, num func(string) int) (termColor, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	switch args[0] {
+	case "5":
+		if len(args) < 2 {
+			return 0, false
+		}
+		n := num(args[1])
+		return termColor(n), n >= 0 && n < 256
+	case "2":
+		rgb := args[1:]
+		if len(rgb) == 4 {
+			rgb = rgb[1:] // Color space id
+		}
+		if len(rgb) != 3 {
+			return 0, false
+		}
+		return rgbColor(num(rgb[0]), num(rgb[1]), num(rgb[2])), true
+	}
+	return 0, false
+}
+
+// classes returns the term-* CSS classes for a style. The 16 ANSI colors
+// are classes, so the website's terminal theme picks their values; other
+// colors are left to inlineStyle. Reverse video adds term-reverse, which
+// the theme uses to swap the default colors, and explicit colors are
+// swapped here.
+func (st cellStyle) classes() []string {
+	var classes []string
+	if st.bold {
+		classes = append(classes, "term-bold")
+	}
+	if st.dim {
+		classes = append(classes, "term-dim")
+	}
+	if st.italic {
+		classes = append(classes, "term-italic")
+	}
+	if st.underline {
+		classes = append(classes, "term-underline")
+	}
+	if st.strike {
+		classes = append(classes, "term-strike")
+	}
+	if st.reverse {
+		classes = append(classes, "term-reverse")
+	}
+	fg, bg := st.colors()
+	if n, ok := fg.basic(); ok {
+		classes = append(classes, "term-fg-"+strconv.Itoa(n))
+	}
+	if n, ok := bg.basic(); ok {
+		classes = append(classes, "term-bg-"+strconv.Itoa(n))
+	}
+	return classes
+}
+
+// inlineStyle returns CSS declarations for the colors that have no class:
+// 256-color indexes above 15 and 24-bit colors.
+func (st cellStyle) inlineStyle() string {
+	var decls []string
+	fg, bg := st.colors()
+	if _, ok := fg.basic(); !ok && fg != colorDefault {
+		decls = append(decls, "color:"+fg.hex(nil))
+	}
+	if _, ok := bg.basic(); !ok && bg != colorDefault {
+		decls = append(decls, "background-color:"+bg.hex(nil))
+	}
+	return strings.Join(decls, ";")
+}
+
+// openSpan returns the opening <span> for a style, or "" for plain text.
+func (st cellStyle) openSpan() string {
+	classes := st.classes()
+	inline := st.inlineStyle()
+	if len(classes) == 0 && inline == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("<span")
+	if len(classes) > 0 {
+		fmt.Fprintf(&sb, ` class="%s"`, strings.Join(classes, " "))
+	}
+	if inline != "" {
+		fmt.Fprintf(&sb, ` style="%s"`, inline)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
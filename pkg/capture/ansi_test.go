@@ -0,0 +1,57 @@
+package capture
+
+import "testing"
+
+func TestApplySGR(t *testing.T) {
+	cases := []struct {
+		params string
+		want   string // openSpan of the style applied to the default style
+	}{
+		{"", ""},
+		{"0", ""},
+		{"1;31", `<span class="term-bold term-fg-1">`},
+		{"94;42", `<span class="term-fg-12 term-bg-2">`},
+		{"38;5;3", `<span class="term-fg-3">`},
+		{"38;5;196", `<span style="color:#ff0000">`},
+		{"38:5:196", `<span style="color:#ff0000">`},
+		{"48;5;16", `<span style="background-color:#000000">`},
+		{"38;5;244", `<span style="color:#808080">`},
+		{"38;2;18;52;86", `<span style="color:#123456">`},
+		{"38:2::18:52:86", `<span style="color:#123456">`},
+		{"38:2:18:52:86", `<span style="color:#123456">`},
+		{"48;2;300;-1;0", `<span style="background-color:#ff0000">`},
+		{"38;5;196;1", `<span class="term-bold" style="color:#ff0000">`},
+		{"38;2;1;2;3;4", `<span class="term-underline" style="color:#010203">`},
+		{"38;5;300", ""},
+		{"38;5", ""},
+		{"4:0", ""},
+		{"7;31;48;5;200", `<span class="term-reverse term-bg-1" style="color:#ff00d7">`},
+		{"1;22", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.params, func(t *testing.T) {
+			if got := applySGR(defaultStyle, c.params).openSpan(); got != c.want {
+				t.Errorf("applySGR(%q) = %s, want %s", c.params, got, c.want)
+			}
+		})
+	}
+}
+
+func TestAnsiToHTML(t *testing.T) {
+	cases := []struct {
+		name, in, want string
+	}{
+		{"plain", "a < b", "a &lt; b"},
+		{"basic color", "\x1b[32mok\x1b[0m done", `<span class="term-fg-2">ok</span> done`},
+		{"state carries over", "\x1b[1mA\x1b[31mB\x1b[22mC", `<span class="term-bold">A</span><span class="term-bold term-fg-1">B</span><span class="term-fg-1">C</span>`},
+		{"truecolor", "\x1b[38;2;255;128;0mwarn\x1b[m", `<span style="color:#ff8000">warn</span>`},
+		{"escape without text", "\x1b[31m\x1b[0mx", "x"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ansiToHTML(c.in); got != c.want {
+				t.Errorf("ansiToHTML(%q) =\n%s\nwant\n%s", c.in, got, c.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,13 @@ package capture
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/grovetools/docgen/internal/fsutil"
 	"github.com/sirupsen/logrus"
 )
 
@@ -25,6 +25,7 @@ type Options struct {
 	MaxDepth        int
 	Format          Format
 	SubcommandOrder []string // Priority order for subcommands (rest alphabetical)
+	CobraJSON       bool     // Ask the tool for its commands (docs json or __complete) before parsing help text
 }
 
 // Capturer recursively captures help output from CLI tools.
@@ -43,83 +44,119 @@ type CommandNode struct {
 	FullName    string // e.g. "nb concept new"
 	HelpOutput  string // Plain text (ANSI stripped)
 	RawOutput   string // Raw output with ANSI codes
+	Description string // Optional prose rendered above the help output
 	SubCommands []*CommandNode
+
+	command []string // Command line that runs this command, see Resolve
+}
+
+// Walk calls fn for n and every command below it, depth-first.
+func (n *CommandNode) Walk(fn func(*CommandNode)) {
+	fn(n)
+	for _, child := range n.SubCommands {
+		child.Walk(fn)
+	}
 }
 
 // Capture crawls a binary's help output and generates documentation.
 func (c *Capturer) Capture(binaryPath, outputPath string, opts Options) error {
-	if opts.Format == "" {
-		opts.Format = FormatMarkdown
+	return c.CaptureContext(context.Background(), binaryPath, outputPath, opts)
+}
+
+// CaptureContext is Capture with cancellation: cancelling ctx kills the
+// running help command and leaves any previous output file untouched.
+func (c *Capturer) CaptureContext(ctx context.Context, binaryPath, outputPath string, opts Options) error {
+	root, err := c.CrawlContext(ctx, binaryPath, opts)
+	if err != nil {
+		return err
 	}
 
+	content := c.Render(root, opts)
+	if err := fsutil.WriteFileAtomic(outputPath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return nil
+}
+
+// Crawl captures the help output of a binary and its subcommands without
+// rendering it, so callers can annotate the tree before calling Render.
+func (c *Capturer) Crawl(binaryPath string, opts Options) (*CommandNode, error) {
+	return c.CrawlContext(context.Background(), binaryPath, opts)
+}
+
+// CrawlContext is Crawl with cancellation.
+func (c *Capturer) CrawlContext(ctx context.Context, binaryPath string, opts Options) (*CommandNode, error) {
 	root := &CommandNode{
 		Name:     binaryPath,
 		FullName: binaryPath,
 	}
+	if command, err := Resolve(binaryPath); err == nil {
+		root.command = command
+	} else {
+		c.logger.Debugf("%v; running it as given", err)
+		root.command = []string{binaryPath}
+	}
 
 	c.logger.Infof("Crawling %s...", binaryPath)
 	forceColor := opts.Format == FormatHTML
-	if err := c.crawl(root, 0, opts.MaxDepth, forceColor); err != nil {
-		return err
+	var cobra *cobraSource
+	if opts.CobraJSON {
+		cobra = c.newCobraSource(ctx, root.command)
+	}
+	if err := c.crawl(ctx, root, 0, opts.MaxDepth, forceColor, cobra); err != nil {
+		return nil, err
 	}
 
 	// Sort subcommands based on priority order
 	if len(opts.SubcommandOrder) > 0 {
 		c.sortSubcommands(root, opts.SubcommandOrder)
 	}
+	return root, nil
+}
 
+// Render renders a crawled command tree in opts.Format (default: Markdown).
+func (c *Capturer) Render(root *CommandNode, opts Options) string {
 	c.logger.Info("Rendering documentation...")
-	var content string
 	switch opts.Format {
 	case FormatHTML:
-		content = c.renderHTML(root)
+		return c.renderHTML(root)
 	default:
-		content = c.render(root)
+		return c.render(root)
 	}
-
-	if err := os.WriteFile(outputPath, []byte(content), 0o644); err != nil { //nolint:gosec // internal doc tool output
-		return fmt.Errorf("failed to write output file: %w", err)
-	}
-
-	return nil
 }
 
-func (c *Capturer) crawl(node *CommandNode, currentDepth, maxDepth int, forceColor bool) error {
+func (c *Capturer) crawl(ctx context.Context, node *CommandNode, currentDepth, maxDepth int, forceColor bool, cobra *cobraSource) error {
 	if currentDepth >= maxDepth {
 		return nil
 	}
-
-	// Run command with --help
-	args := strings.Fields(node.FullName)
-	if len(args) == 0 {
-		return fmt.Errorf("empty command name")
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("capture cancelled: %w", err)
 	}
 
-	binary := args[0]
-	cmdArgs := append(args[1:], "--help")
-
-	// Set environment to force standard width to avoid wrapping issues in docs
-	// COLUMNS=80 is standard for documentation
-	cmd := exec.Command(binary, cmdArgs...) //nolint:gosec // intentional: captures CLI help output
-	env := append(os.Environ(), "COLUMNS=80")
-	if forceColor {
-		// Force color output for tools that check TTY
-		env = append(env, "CLICOLOR_FORCE=1", "FORCE_COLOR=1")
+	// Nodes built by callers have no command line; their names are the command
+	command := node.command
+	if len(command) == 0 {
+		command = strings.Fields(node.FullName)
 	}
-	cmd.Env = env
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		c.logger.Debugf("Command '%s --help' returned error (common for some tools): %v", node.FullName, err)
-		// Continue even if error, as some tools exit 1 on help
+	if len(command) == 0 {
+		return fmt.Errorf("empty command name")
 	}
 
 	// Store both raw and cleaned output
-	node.RawOutput = string(output)
+	node.RawOutput = c.runHelp(ctx, command, node.FullName, forceColor)
 	node.HelpOutput = stripANSI(node.RawOutput)
 
-	// Find subcommands (always use cleaned output for parsing)
-	subCmdNames := parseSubCommands(node.HelpOutput)
+	// Find subcommands: from the tool itself when it can say, otherwise
+	// from its help (always use cleaned output for parsing)
+	var subCmdNames []string
+	fromTool := false
+	if cobra != nil {
+		subCmdNames, fromTool = cobra.subCommands(ctx, command)
+	}
+	if !fromTool {
+		subCmdNames = parseSubCommands(node.HelpOutput)
+	}
 
 	for _, name := range subCmdNames {
 		// Avoid infinite loops or standard utility subcommands
@@ -130,15 +167,22 @@ func (c *Capturer) crawl(node *CommandNode, currentDepth, maxDepth int, forceCol
 		subNode := &CommandNode{
 			Name:     name,
 			FullName: fmt.Sprintf("%s %s", node.FullName, name),
+			command:  append(append([]string(nil), command...), name),
 		}
 
-		c.logger.Debugf("Found subcommand: %s", subNode.FullName)
-		node.SubCommands = append(node.SubCommands, subNode)
-
 		// Recurse
-		if err := c.crawl(subNode, currentDepth+1, maxDepth, forceColor); err != nil {
+		if err := c.crawl(ctx, subNode, currentDepth+1, maxDepth, forceColor, cobra); err != nil {
 			return err
 		}
+
+		// A completion that is an argument, not a subcommand, shows the
+		// command's own help
+		if fromTool && subNode.HelpOutput != "" && subNode.HelpOutput == node.HelpOutput {
+			c.logger.Debugf("Skipping %s: not a subcommand", subNode.FullName)
+			continue
+		}
+		c.logger.Debugf("Found subcommand: %s", subNode.FullName)
+		node.SubCommands = append(node.SubCommands, subNode)
 	}
 
 	return nil
@@ -193,22 +237,28 @@ func stripANSI(str string) string {
 // parseSubCommands extracts subcommand names from help text.
 // It looks for a "COMMANDS" section and parses the lines following it.
 func parseSubCommands(helpText string) []string {
-	lines := strings.Split(helpText, "\n")
+	lines := strings.Split(normalizeNewlines(helpText), "\n")
 	var subcommands []string
 	inCommands := false
 
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
 		upper := strings.ToUpper(trimmed)
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
 
 		// Detect start of COMMANDS section
 		// Must be a section header, not just any line containing "commands"
 		// Grove tools use "COMMANDS" by itself (styled)
-		// Standard cobra uses "Available Commands:"
-		isCommandsHeader := trimmed == "COMMANDS" ||
-			upper == "COMMANDS" ||
-			upper == "AVAILABLE COMMANDS:" ||
-			strings.HasPrefix(upper, "AVAILABLE COMMANDS")
+		// Standard cobra uses "Available Commands:", or "<Group> Commands:" for
+		// command groups; System.CommandLine and clap use "Commands:", and
+		// others "Subcommands:"
+		heading := strings.TrimSuffix(upper, ":")
+		isCommandsHeader := !indented &&
+			(heading == "COMMANDS" ||
+				heading == "SUBCOMMANDS" ||
+				strings.HasPrefix(heading, "AVAILABLE COMMANDS") ||
+				strings.HasPrefix(heading, "AVAILABLE SUBCOMMANDS") ||
+				(strings.HasSuffix(heading, " COMMANDS") && strings.HasSuffix(trimmed, ":")))
 		if isCommandsHeader {
 			inCommands = true
 			continue
@@ -220,6 +270,10 @@ func parseSubCommands(helpText string) []string {
 			if strings.Contains(trimmed, "FLAGS") || strings.Contains(upper, "FLAGS:") {
 				break
 			}
+			// Unindented "Options:", "Arguments:" and similar headings
+			if !indented && strings.HasSuffix(trimmed, ":") {
+				break
+			}
 			// Check for other section headers (single word, all caps, length > 2)
 			if len(trimmed) > 2 && strings.ToUpper(trimmed) == trimmed && !strings.Contains(trimmed, " ") {
 				break
@@ -240,8 +294,9 @@ func parseSubCommands(helpText string) []string {
 			if len(fields) > 0 {
 				cmdName := fields[0]
 				// Filter out noise/descriptions
-				// Commands should be lowercase alphanumeric usually
-				if !strings.ContainsAny(cmdName, ":-.") && len(cmdName) > 1 {
+				// Commands should be lowercase alphanumeric usually, and
+				// /switches are cmd.exe-style options
+				if !strings.ContainsAny(cmdName, ":-./") && len(cmdName) > 1 {
 					subcommands = append(subcommands, cmdName)
 				}
 			}
@@ -266,6 +321,9 @@ func (c *Capturer) renderNode(buf *bytes.Buffer, node *CommandNode, level int) {
 	// Markdown Header
 	prefix := strings.Repeat("#", level)
 	buf.WriteString(fmt.Sprintf("%s %s\n\n", prefix, node.FullName))
+	if node.Description != "" {
+		buf.WriteString(node.Description + "\n\n")
+	}
 
 	// Help Output Block
 	buf.WriteString("```text\n")
@@ -300,6 +358,9 @@ func (c *Capturer) renderHTMLNode(buf *bytes.Buffer, node *CommandNode, level in
 	// Markdown header
 	prefix := strings.Repeat("#", level)
 	buf.WriteString(fmt.Sprintf("%s %s\n\n", prefix, node.FullName))
+	if node.Description != "" {
+		buf.WriteString(node.Description + "\n\n")
+	}
 
 	// Terminal output as embedded HTML
 	buf.WriteString("<div class=\"terminal\">\n")
@@ -324,142 +385,47 @@ func escapeHTML(s string) string {
 	return s
 }
 
-// ansiToHTML converts ANSI escape codes to HTML spans with CSS classes.
+// ansiToHTML converts ANSI escape codes to HTML spans. The 16 basic colors
+// and text attributes become term-* classes; 256-color and 24-bit colors,
+// which the terminal theme cannot enumerate, become inline styles.
 func ansiToHTML(s string) string {
 	var buf bytes.Buffer
-	var currentStyles []string
+	style := defaultStyle
+	open := ""
 
-	// Regex to match ANSI escape sequences
-	ansiPattern := regexp.MustCompile(`\x1b\[([0-9;]*)m`)
+	// Regex to match SGR escape sequences, including colon sub-parameters
+	ansiPattern := regexp.MustCompile(`\x1b\[([0-9;:]*)m`)
 
 	lastIndex := 0
-	for _, match := range ansiPattern.FindAllStringSubmatchIndex(s, -1) {
-		// Write text before this escape sequence
-		if match[0] > lastIndex {
-			text := s[lastIndex:match[0]]
-			buf.WriteString(escapeHTML(text))
+	flush := func(text string) {
+		if text == "" {
+			return
 		}
-
-		// Parse the SGR parameters
-		params := s[match[2]:match[3]]
-		newStyles := parseANSIParams(params)
-
-		// Close previous span if we had styles
-		if len(currentStyles) > 0 {
-			buf.WriteString("</span>")
-		}
-
-		// Open new span if we have styles
-		currentStyles = newStyles
-		if len(currentStyles) > 0 {
-			buf.WriteString("<span class=\"")
-			buf.WriteString(strings.Join(currentStyles, " "))
-			buf.WriteString("\">")
+		if span := style.openSpan(); span != open {
+			if open != "" {
+				buf.WriteString("</span>")
+			}
+			open = span
+			buf.WriteString(open)
 		}
+		buf.WriteString(escapeHTML(text))
+	}
+	for _, match := range ansiPattern.FindAllStringSubmatchIndex(s, -1) {
+		// Write text before this escape sequence
+		flush(s[lastIndex:match[0]])
 
+		// SGR state carries over until it is reset
+		style = applySGR(style, s[match[2]:match[3]])
 		lastIndex = match[1]
 	}
 
 	// Write remaining text
-	if lastIndex < len(s) {
-		buf.WriteString(escapeHTML(s[lastIndex:]))
-	}
+	flush(s[lastIndex:])
 
 	// Close any open span
-	if len(currentStyles) > 0 {
+	if open != "" {
 		buf.WriteString("</span>")
 	}
 
 	return buf.String()
 }
-
-// parseANSIParams converts SGR parameters to CSS class names.
-func parseANSIParams(params string) []string {
-	if params == "" || params == "0" {
-		return nil // Reset
-	}
-
-	var classes []string
-	parts := strings.Split(params, ";")
-
-	for _, p := range parts {
-		switch p {
-		case "0":
-			return nil // Reset
-		case "1":
-			classes = append(classes, "term-bold")
-		case "2":
-			classes = append(classes, "term-dim")
-		case "3":
-			classes = append(classes, "term-italic")
-		case "4":
-			classes = append(classes, "term-underline")
-		case "30":
-			classes = append(classes, "term-fg-0")
-		case "31":
-			classes = append(classes, "term-fg-1")
-		case "32":
-			classes = append(classes, "term-fg-2")
-		case "33":
-			classes = append(classes, "term-fg-3")
-		case "34":
-			classes = append(classes, "term-fg-4")
-		case "35":
-			classes = append(classes, "term-fg-5")
-		case "36":
-			classes = append(classes, "term-fg-6")
-		case "37":
-			classes = append(classes, "term-fg-7")
-		case "90":
-			classes = append(classes, "term-fg-8")
-		case "91":
-			classes = append(classes, "term-fg-9")
-		case "92":
-			classes = append(classes, "term-fg-10")
-		case "93":
-			classes = append(classes, "term-fg-11")
-		case "94":
-			classes = append(classes, "term-fg-12")
-		case "95":
-			classes = append(classes, "term-fg-13")
-		case "96":
-			classes = append(classes, "term-fg-14")
-		case "97":
-			classes = append(classes, "term-fg-15")
-		case "40":
-			classes = append(classes, "term-bg-0")
-		case "41":
-			classes = append(classes, "term-bg-1")
-		case "42":
-			classes = append(classes, "term-bg-2")
-		case "43":
-			classes = append(classes, "term-bg-3")
-		case "44":
-			classes = append(classes, "term-bg-4")
-		case "45":
-			classes = append(classes, "term-bg-5")
-		case "46":
-			classes = append(classes, "term-bg-6")
-		case "47":
-			classes = append(classes, "term-bg-7")
-		case "100":
-			classes = append(classes, "term-bg-8")
-		case "101":
-			classes = append(classes, "term-bg-9")
-		case "102":
-			classes = append(classes, "term-bg-10")
-		case "103":
-			classes = append(classes, "term-bg-11")
-		case "104":
-			classes = append(classes, "term-bg-12")
-		case "105":
-			classes = append(classes, "term-bg-13")
-		case "106":
-			classes = append(classes, "term-bg-14")
-		case "107":
-			classes = append(classes, "term-bg-15")
-		}
-	}
-
-	return classes
-}
@@ -0,0 +1,136 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// commandTree is the command tree a tool prints from a hidden "docs json"
+// command:
+//
+//	{"name": "grove", "commands": [{"name": "init", "commands": [...]}]}
+//
+// Tools that print cobra's Use line instead of a name are understood too.
+type commandTree struct {
+	Name     string         `json:"name"`
+	Use      string         `json:"use"`
+	Hidden   bool           `json:"hidden"`
+	Commands []*commandTree `json:"commands"`
+}
+
+func (t *commandTree) name() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	if fields := strings.Fields(t.Use); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// cobraSource finds subcommands by asking the tool instead of parsing its
+// help text.
+type cobraSource struct {
+	root []string     // Root command line; node paths are what follows it
+	tree *commandTree // The tool's "docs json" tree, nil if it has none
+}
+
+// newCobraSource queries root's "docs json" command. Tools without one are
+// asked per command with cobra's __complete instead.
+func (c *Capturer) newCobraSource(ctx context.Context, root []string) *cobraSource {
+	src := &cobraSource{root: root}
+	output, err := runQuiet(ctx, root, "docs", "json")
+	if err != nil {
+		c.logger.Debugf("No docs json command (%v); using __complete", err)
+		return src
+	}
+	var tree commandTree
+	if err := json.Unmarshal(output, &tree); err != nil || len(tree.Commands) == 0 {
+		c.logger.Debugf("docs json did not print a command tree; using __complete")
+		return src
+	}
+	c.logger.Debug("Using the command tree from docs json")
+	src.tree = &tree
+	return src
+}
+
+// subCommands returns the subcommands of the command run by command, and
+// false when the tool cannot say, so help text must be parsed.
+func (src *cobraSource) subCommands(ctx context.Context, command []string) ([]string, bool) {
+	if len(command) < len(src.root) {
+		return nil, false
+	}
+	path := command[len(src.root):]
+	if src.tree != nil {
+		return src.tree.lookup(path)
+	}
+	return completeSubCommands(ctx, src.root, path)
+}
+
+// lookup returns the visible subcommands of the command at path.
+func (t *commandTree) lookup(path []string) ([]string, bool) {
+	node := t
+	for _, name := range path {
+		var next *commandTree
+		for _, child := range node.Commands {
+			if child.name() == name {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil, false
+		}
+		node = next
+	}
+	var names []string
+	for _, child := range node.Commands {
+		if name := child.name(); name != "" && !child.Hidden {
+			names = append(names, name)
+		}
+	}
+	return names, true
+}
+
+// completeSubCommands asks cobra's hidden __complete command to complete
+// an empty argument after path. It prints one "name<TAB>description" line
+// per candidate and ends with a ":<directive>" line. Hidden commands are
+// left out. Candidates can include the command's valid arguments as well as
+// its subcommands; crawl drops those, since their help is the command's own.
+func completeSubCommands(ctx context.Context, root, path []string) ([]string, bool) {
+	args := append(append([]string{"__complete"}, path...), "")
+	output, err := runQuiet(ctx, root, args...)
+	if err != nil {
+		return nil, false
+	}
+	lines := strings.Split(strings.TrimSpace(normalizeNewlines(string(output))), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, ":") {
+		return nil, false
+	}
+	directive, err := strconv.Atoi(last[1:])
+	if err != nil || directive&1 != 0 { // ShellCompDirectiveError
+		return nil, false
+	}
+	var names []string
+	for _, line := range lines[:len(lines)-1] {
+		name, _, _ := strings.Cut(line, "\t")
+		if name == "" || strings.HasPrefix(name, "-") || strings.HasPrefix(name, "_") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, true
+}
+
+// runQuiet runs command with args and returns its standard output.
+func runQuiet(ctx context.Context, command []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, command[0], append(append([]string(nil), command[1:]...), args...)...) //nolint:gosec // intentional: queries a CLI's commands
+	cmd.Env = os.Environ()
+	detachConsole(cmd)
+	return cmd.Output()
+}
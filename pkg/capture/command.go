@@ -0,0 +1,97 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Resolve returns the command line that runs binary. Binaries are looked up
+// on PATH, which on Windows also tries the PATHEXT extensions, so "grove"
+// finds grove.exe. PowerShell scripts (.ps1) run through pwsh, or Windows
+// PowerShell when pwsh is not installed.
+func Resolve(binary string) ([]string, error) {
+	if strings.EqualFold(filepath.Ext(binary), ".ps1") {
+		if _, err := os.Stat(binary); err != nil {
+			return nil, fmt.Errorf("script %s not found: %w", binary, err)
+		}
+		shell, err := exec.LookPath("pwsh")
+		if err != nil {
+			if shell, err = exec.LookPath("powershell"); err != nil {
+				return nil, fmt.Errorf("running %s requires PowerShell (pwsh or powershell) on PATH", binary)
+			}
+		}
+		return []string{shell, "-NoProfile", "-NonInteractive", "-File", binary}, nil
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("binary '%s' not found in PATH", binary)
+	}
+	return []string{path}, nil
+}
+
+// helpFlags returns the flags tried, in order, to get a command's help.
+// PowerShell scripts use -?; Windows CLIs that predate GNU-style flags answer
+// -h or the cmd.exe convention /? instead of --help.
+func helpFlags(command []string) []string {
+	if len(command) > 0 && isPowerShell(command[0]) {
+		return []string{"-?"}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"--help", "-h", "/?"}
+	}
+	return []string{"--help"}
+}
+
+func isPowerShell(path string) bool {
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	return name == "pwsh" || name == "powershell"
+}
+
+// runHelp runs command with each help flag until one prints help. A run
+// that succeeds with output wins; failing runs (some tools exit 1 on help)
+// are kept in case no flag succeeds. Output has its line endings
+// normalized, so CRLF help text parses like LF.
+func (c *Capturer) runHelp(ctx context.Context, command []string, name string, forceColor bool) string {
+	var fallback string
+	for _, flag := range helpFlags(command) {
+		if ctx.Err() != nil {
+			break
+		}
+		args := append(append([]string(nil), command[1:]...), flag)
+		cmd := exec.CommandContext(ctx, command[0], args...) //nolint:gosec // intentional: captures CLI help output
+
+		// Set environment to force standard width to avoid wrapping issues in docs
+		// COLUMNS=80 is standard for documentation
+		env := append(os.Environ(), "COLUMNS=80")
+		if forceColor {
+			// Force color output for tools that check TTY
+			env = append(env, "CLICOLOR_FORCE=1", "FORCE_COLOR=1")
+		}
+		cmd.Env = env
+		detachConsole(cmd)
+
+		output, err := cmd.CombinedOutput()
+		text := normalizeNewlines(string(output))
+		if err != nil {
+			c.logger.Debugf("Command '%s %s' returned error (common for some tools): %v", name, flag, err)
+			if fallback == "" {
+				fallback = text
+			}
+			continue
+		}
+		if strings.TrimSpace(text) != "" {
+			return text
+		}
+	}
+	return fallback
+}
+
+// normalizeNewlines converts CRLF line endings to LF.
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(s, "\r\n", "\n")
+}
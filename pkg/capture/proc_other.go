@@ -0,0 +1,8 @@
+//go:build !windows
+
+package capture
+
+import "os/exec"
+
+// detachConsole is a no-op outside Windows, where COLUMNS sets the width.
+func detachConsole(cmd *exec.Cmd) {}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package capture
+
+import (
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachConsole runs cmd without a console. Console programs size their
+// output to the console window rather than COLUMNS, so without one they
+// fall back to their default width instead of the user's window's.
+func detachConsole(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.CREATE_NO_WINDOW
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package capture
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// startInPTY is not supported on this platform.
+func startInPTY(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	return nil, fmt.Errorf("capturing TUIs is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,35 @@
+//go:build linux || darwin
+
+package capture
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// startInPTY starts cmd as the session leader of a new cols x rows
+// pseudo-terminal and returns the terminal's master side.
+func startInPTY(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close() //nolint:errcheck // the child has its own copy
+
+	ws := &unix.Winsize{Col: uint16(cols), Row: uint16(rows)} //nolint:gosec // sizes are validated by the caller
+	fd := int(slave.Fd())                                     //nolint:gosec // file descriptors fit in an int
+	if err := unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, ws); err != nil {
+		_ = master.Close()
+		return nil, err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	if err := cmd.Start(); err != nil {
+		_ = master.Close()
+		return nil, err
+	}
+	return master, nil
+}
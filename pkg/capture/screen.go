@@ -0,0 +1,352 @@
+package capture
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type cell struct {
+	r     rune
+	style cellStyle
+}
+
+// screen is a minimal VT100/xterm emulator: enough cursor movement, erasing,
+// scrolling and alternate-screen handling to reconstruct what a TUI drew.
+type screen struct {
+	cols, rows   int
+	cells        [][]cell
+	main         [][]cell // Saved main screen while the alternate one is shown
+	x, y         int
+	wrapNext     bool // The cursor is past the last column; the next rune wraps
+	style        cellStyle
+	savedX       int
+	savedY       int
+	savedStyle   cellStyle
+	scrollTop    int
+	scrollBottom int
+
+	pending []byte // Incomplete escape sequence or rune from the last write
+}
+
+func newScreen(cols, rows int) *screen {
+	s := &screen{cols: cols, rows: rows, style: defaultStyle, scrollBottom: rows - 1}
+	s.cells = s.blank()
+	return s
+}
+
+func (s *screen) blank() [][]cell {
+	cells := make([][]cell, s.rows)
+	for i := range cells {
+		cells[i] = s.blankLine()
+	}
+	return cells
+}
+
+func (s *screen) blankLine() []cell {
+	line := make([]cell, s.cols)
+	for i := range line {
+		line[i] = cell{r: ' ', style: cellStyle{fg: colorDefault, bg: s.style.bg}}
+	}
+	return line
+}
+
+// Write feeds terminal output to the screen. Sequences split across writes
+// are completed by the next write.
+func (s *screen) Write(p []byte) (int, error) {
+	data := append(s.pending, p...)
+	s.pending = nil
+	i := 0
+	for i < len(data) {
+		b := data[i]
+		switch {
+		case b == 0x1b:
+			n := s.escape(data[i:])
+			if n == 0 {
+				s.pending = append([]byte(nil), data[i:]...)
+				return len(p), nil
+			}
+			i += n
+		case b < 0x20 || b == 0x7f:
+			s.control(b)
+			i++
+		default:
+			r, size := utf8.DecodeRune(data[i:])
+			if r == utf8.RuneError && size == 1 && !utf8.FullRune(data[i:]) {
+				s.pending = append([]byte(nil), data[i:]...)
+				return len(p), nil
+			}
+			s.put(r)
+			i += size
+		}
+	}
+	return len(p), nil
+}
+
+func (s *screen) control(b byte) {
+	switch b {
+	case '\r':
+		s.x, s.wrapNext = 0, false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\b':
+		if s.x > 0 {
+			s.x--
+		}
+		s.wrapNext = false
+	case '\t':
+		s.x = min((s.x/8+1)*8, s.cols-1)
+	}
+}
+
+func (s *screen) put(r rune) {
+	if s.wrapNext {
+		s.x, s.wrapNext = 0, false
+		s.lineFeed()
+	}
+	s.cells[s.y][s.x] = cell{r: r, style: s.style}
+	if s.x == s.cols-1 {
+		s.wrapNext = true
+	} else {
+		s.x++
+	}
+}
+
+func (s *screen) lineFeed() {
+	s.wrapNext = false
+	if s.y == s.scrollBottom {
+		s.scrollUp(1)
+	} else if s.y < s.rows-1 {
+		s.y++
+	}
+}
+
+// scrollUp scrolls the scrolling region up by n lines.
+func (s *screen) scrollUp(n int) {
+	for ; n > 0; n-- {
+		copy(s.cells[s.scrollTop:s.scrollBottom+1], s.cells[s.scrollTop+1:s.scrollBottom+1])
+		s.cells[s.scrollBottom] = s.blankLine()
+	}
+}
+
+// scrollDown scrolls the scrolling region down by n lines.
+func (s *screen) scrollDown(n int) {
+	for ; n > 0; n-- {
+		copy(s.cells[s.scrollTop+1:s.scrollBottom+1], s.cells[s.scrollTop:s.scrollBottom])
+		s.cells[s.scrollTop] = s.blankLine()
+	}
+}
+
+// escape handles the escape sequence at the start of data and returns its
+// length, or 0 when it is incomplete.
+func (s *screen) escape(data []byte) int {
+	if len(data) < 2 {
+		return 0
+	}
+	switch data[1] {
+	case '[':
+		for i := 2; i < len(data); i++ {
+			if data[i] >= 0x40 && data[i] <= 0x7e {
+				s.csi(string(data[2:i]), data[i])
+				return i + 1
+			}
+		}
+		return 0
+	case ']', 'P', '_', '^':
+		// OSC, DCS and friends end with BEL or ST (ESC \)
+		for i := 2; i < len(data); i++ {
+			if data[i] == 0x07 {
+				return i + 1
+			}
+			if data[i] == 0x1b && i+1 < len(data) && data[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return 0
+	case '(', ')', '*', '+', '#', '%':
+		if len(data) < 3 {
+			return 0
+		}
+		return 3
+	case '7':
+		s.savedX, s.savedY, s.savedStyle = s.x, s.y, s.style
+	case '8':
+		s.x, s.y, s.style, s.wrapNext = s.savedX, s.savedY, s.savedStyle, false
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.x = 0
+		s.lineFeed()
+	case 'M':
+		if s.y == s.scrollTop {
+			s.scrollDown(1)
+		} else if s.y > 0 {
+			s.y--
+		}
+	case 'c':
+		*s = *newScreen(s.cols, s.rows)
+	}
+	return 2
+}
+
+// csi handles a control sequence with its parameters and final byte.
+func (s *screen) csi(params string, final byte) {
+	private := strings.HasPrefix(params, "?")
+	params = strings.TrimLeft(params, "?<=>")
+	params = strings.TrimRight(params, " !\"#$%&'()*+,-./")
+	var args []int
+	for _, p := range strings.Split(params, ";") {
+		n, _ := strconv.Atoi(p)
+		args = append(args, n)
+	}
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+	s.wrapNext = false
+
+	switch final {
+	case 'A':
+		s.y = max(s.y-arg(0, 1), 0)
+	case 'B':
+		s.y = min(s.y+arg(0, 1), s.rows-1)
+	case 'C':
+		s.x = min(s.x+arg(0, 1), s.cols-1)
+	case 'D':
+		s.x = max(s.x-arg(0, 1), 0)
+	case 'E':
+		s.x, s.y = 0, min(s.y+arg(0, 1), s.rows-1)
+	case 'F':
+		s.x, s.y = 0, max(s.y-arg(0, 1), 0)
+	case 'G', '`':
+		s.x = clamp(arg(0, 1)-1, s.cols)
+	case 'd':
+		s.y = clamp(arg(0, 1)-1, s.rows)
+	case 'H', 'f':
+		s.y, s.x = clamp(arg(0, 1)-1, s.rows), clamp(arg(1, 1)-1, s.cols)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseLine(s.y, s.x, s.cols)
+			for y := s.y + 1; y < s.rows; y++ {
+				s.cells[y] = s.blankLine()
+			}
+		case 1:
+			for y := 0; y < s.y; y++ {
+				s.cells[y] = s.blankLine()
+			}
+			s.eraseLine(s.y, 0, s.x+1)
+		default:
+			s.cells = s.blank()
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseLine(s.y, s.x, s.cols)
+		case 1:
+			s.eraseLine(s.y, 0, s.x+1)
+		default:
+			s.eraseLine(s.y, 0, s.cols)
+		}
+	case 'X':
+		s.eraseLine(s.y, s.x, min(s.x+arg(0, 1), s.cols))
+	case 'P':
+		n := min(arg(0, 1), s.cols-s.x)
+		line := s.cells[s.y]
+		copy(line[s.x:], line[s.x+n:])
+		s.eraseLine(s.y, s.cols-n, s.cols)
+	case '@':
+		n := min(arg(0, 1), s.cols-s.x)
+		line := s.cells[s.y]
+		copy(line[s.x+n:], line[s.x:s.cols-n])
+		s.eraseLine(s.y, s.x, s.x+n)
+	case 'L', 'M':
+		if s.y < s.scrollTop || s.y > s.scrollBottom {
+			return
+		}
+		top := s.scrollTop
+		s.scrollTop = s.y
+		if final == 'L' {
+			s.scrollDown(arg(0, 1))
+		} else {
+			s.scrollUp(arg(0, 1))
+		}
+		s.scrollTop = top
+	case 'S':
+		s.scrollUp(arg(0, 1))
+	case 'T':
+		s.scrollDown(arg(0, 1))
+	case 'r':
+		top, bottom := clamp(arg(0, 1)-1, s.rows), clamp(arg(1, s.rows)-1, s.rows)
+		if top < bottom {
+			s.scrollTop, s.scrollBottom = top, bottom
+			s.x, s.y = 0, 0
+		}
+	case 's':
+		s.savedX, s.savedY = s.x, s.y
+	case 'u':
+		s.x, s.y = s.savedX, s.savedY
+	case 'm':
+		s.style = applySGR(s.style, params)
+	case 'h', 'l':
+		if !private {
+			return
+		}
+		for _, mode := range args {
+			if mode == 47 || mode == 1047 || mode == 1049 {
+				s.alternateScreen(final == 'h')
+			}
+		}
+	}
+}
+
+// alternateScreen switches to or back from the alternate screen that
+// full-screen programs draw on.
+func (s *screen) alternateScreen(on bool) {
+	if on && s.main == nil {
+		s.main = s.cells
+		s.cells = s.blank()
+		s.savedX, s.savedY = s.x, s.y
+	} else if !on && s.main != nil {
+		s.cells, s.main = s.main, nil
+		s.x, s.y = s.savedX, s.savedY
+	}
+}
+
+func (s *screen) eraseLine(y, from, to int) {
+	for x := max(from, 0); x < min(to, s.cols); x++ {
+		s.cells[y][x] = cell{r: ' ', style: cellStyle{fg: colorDefault, bg: s.style.bg}}
+	}
+}
+
+func clamp(v, n int) int {
+	return min(max(v, 0), n-1)
+}
+
+// trimmedRows returns the screen's rows without trailing blank rows.
+func (s *screen) trimmedRows() [][]cell {
+	rows := s.cells
+	for len(rows) > 0 && blankRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+func blankRow(row []cell) bool {
+	for _, c := range row {
+		if c.r != ' ' || c.style.bg != colorDefault {
+			return false
+		}
+	}
+	return true
+}
+
+// trimRow drops a row's trailing unstyled blanks.
+func trimRow(row []cell) []cell {
+	for len(row) > 0 && row[len(row)-1].r == ' ' && row[len(row)-1].style.bg == colorDefault {
+		row = row[:len(row)-1]
+	}
+	return row
+}
@@ -0,0 +1,89 @@
+package capture
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Flag is a flag a command's help lists.
+type Flag struct {
+	Name       string // Long name without dashes; empty for a short-only flag
+	Short      string // One-letter name without the dash, or empty
+	TakesValue bool   // The help shows a required value, e.g. "--config string"
+}
+
+var (
+	flagsHeaderRe = regexp.MustCompile(`^\s*(?:[A-Za-z][A-Za-z ]* )?(?:Flags|Options):\s*$`)
+	// longFlagRe matches "  -c, --config string   Description",
+	// "      --json   Description" and, for a flag whose value is optional,
+	// "      --enqueue string[="local"]   Description".
+	longFlagRe = regexp.MustCompile(`^\s+(?:-([A-Za-z0-9]),\s+)?--([A-Za-z0-9][\w-]*)(?:[ =]([A-Za-z<\[]\S*))?(?:\s{2,}|$)`)
+	// shortFlagRe matches "  -v   Description" and "  -n int   Description".
+	shortFlagRe = regexp.MustCompile(`^\s+-([A-Za-z0-9])(?:\s([A-Za-z<\[][\w<>\[\].-]*))?(?:\s{2,}|,|$)`)
+)
+
+// Flags returns the flags the command's help lists in its flag sections
+// ("Flags:", "Global Flags:", "Options:"), including inherited ones.
+func (n *CommandNode) Flags() []Flag {
+	var flags []Flag
+	inFlags := false
+	for _, line := range strings.Split(n.HelpOutput, "\n") {
+		switch {
+		case flagsHeaderRe.MatchString(line):
+			inFlags = true
+			continue
+		case strings.TrimSpace(line) == "":
+			inFlags = false
+			continue
+		case !inFlags:
+			continue
+		}
+		if m := longFlagRe.FindStringSubmatch(line); m != nil {
+			flags = append(flags, Flag{Name: m[2], Short: m[1], TakesValue: m[3] != "" && !strings.Contains(m[3], "[=")})
+		} else if m := shortFlagRe.FindStringSubmatch(line); m != nil {
+			flags = append(flags, Flag{Short: m[1], TakesValue: m[2] != ""})
+		}
+	}
+	return flags
+}
+
+// TakesArgs reports whether the command's usage lines take positional
+// arguments besides a subcommand, e.g. "docgen diff [base-ref] [flags]"
+// but not "docgen sync [command]". Without a usage line it assumes they do.
+func (n *CommandNode) TakesArgs() bool {
+	lines := strings.Split(n.HelpOutput, "\n")
+	found := false
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "Usage:" && !strings.HasPrefix(strings.TrimSpace(line), "Usage: ") {
+			continue
+		}
+		usage := []string{strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "Usage:"))}
+		for _, next := range lines[i+1:] {
+			if strings.TrimSpace(next) == "" {
+				break
+			}
+			usage = append(usage, strings.TrimSpace(next))
+		}
+		for _, u := range usage {
+			if u == "" {
+				continue
+			}
+			found = true
+			words := strings.Fields(u)
+			if skip := len(strings.Fields(n.FullName)); len(words) > skip {
+				words = words[skip:]
+			} else {
+				words = nil
+			}
+			for _, word := range words {
+				switch strings.ToLower(word) {
+				case "[flags]", "[command]", "[options]", "[global", "options]", "<command>", "[subcommand]":
+				default:
+					return true
+				}
+			}
+		}
+		break
+	}
+	return !found
+}
@@ -0,0 +1,366 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TUIOptions configures a TUI capture.
+type TUIOptions struct {
+	Cols int           // Terminal width (defaults to 100)
+	Rows int           // Terminal height (defaults to 30)
+	Wait time.Duration // Longest wait for the screen to settle (defaults to 5s)
+	Keys []string      // Keys sent before the frame is captured, see KeyBytes
+}
+
+// settleTime is how long a TUI's output must be quiet before its screen is
+// considered drawn.
+const settleTime = 500 * time.Millisecond
+
+// Frame is a captured terminal screen.
+type Frame struct {
+	rows [][]cell
+	cols int
+}
+
+// CaptureTUI runs command in a pseudo-terminal, waits for its screen to
+// settle, sends opts.Keys, and returns the screen as drawn. The program is
+// interrupted afterwards, and killed if it does not exit.
+func (c *Capturer) CaptureTUI(ctx context.Context, command []string, opts TUIOptions) (*Frame, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	if opts.Cols <= 0 {
+		opts.Cols = 100
+	}
+	if opts.Rows <= 0 {
+		opts.Rows = 30
+	}
+	if opts.Wait <= 0 {
+		opts.Wait = 5 * time.Second
+	}
+	if opts.Cols > 1000 || opts.Rows > 1000 {
+		return nil, fmt.Errorf("terminal size %dx%d is too large", opts.Cols, opts.Rows)
+	}
+	var keys [][]byte
+	for _, k := range opts.Keys {
+		b, err := KeyBytes(k)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, b)
+	}
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) //nolint:gosec // intentional: captures a CLI's TUI
+	cmd.Env = append(os.Environ(),
+		"TERM=xterm-256color",
+		fmt.Sprintf("COLUMNS=%d", opts.Cols),
+		fmt.Sprintf("LINES=%d", opts.Rows),
+	)
+	master, err := startInPTY(cmd, opts.Cols, opts.Rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s in a terminal: %w", command[0], err)
+	}
+	defer master.Close() //nolint:errcheck // best-effort cleanup
+
+	c.logger.Infof("Capturing TUI of %s (%dx%d)...", strings.Join(command, " "), opts.Cols, opts.Rows)
+	term := &tuiTerminal{screen: newScreen(opts.Cols, opts.Rows), last: time.Now()}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(term, master) // Ends with EIO once the program exits
+	}()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	wait := func() error {
+		deadline := time.Now().Add(opts.Wait)
+		for time.Now().Before(deadline) {
+			if term.quietFor() >= settleTime {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("capture cancelled: %w", ctx.Err())
+			case err := <-exited:
+				exited <- err
+				return nil // The program exited; capture what it left
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+		c.logger.Debugf("Screen did not settle within %s; capturing it anyway", opts.Wait)
+		return nil
+	}
+	if err := wait(); err != nil {
+		_ = cmd.Process.Kill()
+		return nil, err
+	}
+	for _, k := range keys {
+		if _, err := master.Write(k); err != nil {
+			break
+		}
+		term.touch()
+		if err := wait(); err != nil {
+			_ = cmd.Process.Kill()
+			return nil, err
+		}
+	}
+	frame := term.frame()
+
+	// Ask the program to quit, then make sure it does
+	_, _ = master.Write([]byte{0x03})
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		_ = cmd.Process.Kill()
+		<-exited
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+	return frame, nil
+}
+
+// tuiTerminal feeds a program's output to a screen, tracking when it last
+// wrote.
+type tuiTerminal struct {
+	mu     sync.Mutex
+	screen *screen
+	last   time.Time
+}
+
+func (t *tuiTerminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last = time.Now()
+	return t.screen.Write(p)
+}
+
+func (t *tuiTerminal) touch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last = time.Now()
+}
+
+func (t *tuiTerminal) quietFor() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return time.Since(t.last)
+}
+
+func (t *tuiTerminal) frame() *Frame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rows := make([][]cell, 0, t.screen.rows)
+	for _, row := range t.screen.trimmedRows() {
+		rows = append(rows, append([]cell(nil), row...))
+	}
+	return &Frame{rows: rows, cols: t.screen.cols}
+}
+
+// namedKeys are the key names KeyBytes understands.
+var namedKeys = map[string]string{
+	"enter":     "\r",
+	"tab":       "\t",
+	"esc":       "\x1b",
+	"space":     " ",
+	"backspace": "\x7f",
+	"up":        "\x1b[A",
+	"down":      "\x1b[B",
+	"right":     "\x1b[C",
+	"left":      "\x1b[D",
+	"home":      "\x1b[H",
+	"end":       "\x1b[F",
+	"pgup":      "\x1b[5~",
+	"pgdown":    "\x1b[6~",
+}
+
+// KeyBytes returns the bytes a terminal sends for a key: a name such as
+// enter, esc, up or pgdown, ctrl+<letter>, or literal text.
+func KeyBytes(key string) ([]byte, error) {
+	lower := strings.ToLower(key)
+	if seq, ok := namedKeys[lower]; ok {
+		return []byte(seq), nil
+	}
+	if letter, ok := strings.CutPrefix(lower, "ctrl+"); ok {
+		if len(letter) != 1 || letter[0] < 'a' || letter[0] > 'z' {
+			return nil, fmt.Errorf("unsupported key %q (ctrl+ takes a letter)", key)
+		}
+		return []byte{letter[0] - 'a' + 1}, nil
+	}
+	if key == "" {
+		return nil, fmt.Errorf("empty key")
+	}
+	return []byte(key), nil
+}
+
+// HTML renders the frame as a terminal block with the term-* classes of
+// the HTML capture format, so the website's terminal theme applies.
+func (f *Frame) HTML() string {
+	var buf bytes.Buffer
+	buf.WriteString("<div class=\"terminal\">\n")
+	for i, row := range f.rows {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		for _, run := range styleRuns(trimRow(row)) {
+			span := run.style.openSpan()
+			buf.WriteString(span)
+			buf.WriteString(escapeHTML(run.text))
+			if span != "" {
+				buf.WriteString("</span>")
+			}
+		}
+	}
+	buf.WriteString("\n</div>\n")
+	return buf.String()
+}
+
+// TerminalTheme holds the colors a frame is drawn with as SVG.
+type TerminalTheme struct {
+	Name       string
+	Background string
+	Foreground string
+	Palette    [16]string // The 16 ANSI colors, normal then bright
+}
+
+// Built-in themes for the light and dark variants of a TUI screenshot.
+var (
+	DarkTheme = TerminalTheme{
+		Name:       "dark",
+		Background: "#0d1117",
+		Foreground: "#e6edf3",
+		Palette: [16]string{
+			"#484f58", "#ff7b72", "#3fb950", "#d29922", "#58a6ff", "#bc8cff", "#39c5cf", "#b1bac4",
+			"#6e7681", "#ffa198", "#56d364", "#e3b341", "#79c0ff", "#d2a8ff", "#56d4dd", "#ffffff",
+		},
+	}
+	LightTheme = TerminalTheme{
+		Name:       "light",
+		Background: "#ffffff",
+		Foreground: "#1f2328",
+		Palette: [16]string{
+			"#24292f", "#cf222e", "#116329", "#4d2d00", "#0969da", "#8250df", "#1b7c83", "#6e7781",
+			"#57606a", "#a40e26", "#1a7f37", "#633c01", "#218bff", "#a475f9", "#3192aa", "#8c959f",
+		},
+	}
+)
+
+// SVG cell metrics, in pixels.
+const (
+	svgFontSize   = 14
+	svgCellWidth  = 8.4 // 0.6em, the advance of common monospace fonts
+	svgLineHeight = 18
+	svgPadding    = 16
+)
+
+// SVG renders the frame as an SVG image in theme's colors. Each run of text
+// is positioned by column, so the grid holds whatever monospace font the
+// viewer has. The 16 ANSI colors come from the theme; 256-color and 24-bit
+// colors are drawn as they are.
+func (f *Frame) SVG(theme TerminalTheme) string {
+	// color returns a style's fill colors, "" where the theme's defaults
+	// apply. Reverse video swaps them, so defaults are spelled out.
+	color := func(st cellStyle) (fg, bg string) {
+		fgc, bgc := st.colors()
+		if fgc != colorDefault {
+			fg = fgc.hex(&theme.Palette)
+		}
+		if bgc != colorDefault {
+			bg = bgc.hex(&theme.Palette)
+		}
+		if st.reverse {
+			if fg == "" {
+				fg = theme.Background
+			}
+			if bg == "" {
+				bg = theme.Foreground
+			}
+		}
+		return fg, bg
+	}
+	width := float64(f.cols)*svgCellWidth + 2*svgPadding
+	height := float64(len(f.rows)*svgLineHeight + 2*svgPadding)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.1f %.1f">`+"\n", width, height, width, height)
+	fmt.Fprintf(&buf, `  <rect width="100%%" height="100%%" rx="6" fill="%s"/>`+"\n", theme.Background)
+	fmt.Fprintf(&buf, `  <g font-family="ui-monospace, SFMono-Regular, Menlo, Consolas, monospace" font-size="%d" fill="%s">`+"\n", svgFontSize, theme.Foreground)
+	for i, row := range f.rows {
+		y := float64(svgPadding + i*svgLineHeight)
+		col := 0
+		var text strings.Builder
+		for _, run := range styleRuns(trimRow(row)) {
+			x := svgPadding + float64(col)*svgCellWidth
+			n := len([]rune(run.text))
+			col += n
+			fg, bg := color(run.style)
+			if bg != "" {
+				fmt.Fprintf(&buf, `    <rect x="%.1f" y="%.1f" width="%.1f" height="%d" fill="%s"/>`+"\n",
+					x, y, float64(n)*svgCellWidth, svgLineHeight, bg)
+			}
+			if strings.TrimSpace(run.text) == "" {
+				continue
+			}
+			fmt.Fprintf(&text, `<tspan x="%.1f"`, x)
+			if fg != "" {
+				fmt.Fprintf(&text, ` fill="%s"`, fg)
+			}
+			if run.style.bold {
+				text.WriteString(` font-weight="bold"`)
+			}
+			if run.style.italic {
+				text.WriteString(` font-style="italic"`)
+			}
+			var decorations []string
+			if run.style.underline {
+				decorations = append(decorations, "underline")
+			}
+			if run.style.strike {
+				decorations = append(decorations, "line-through")
+			}
+			if len(decorations) > 0 {
+				fmt.Fprintf(&text, ` text-decoration="%s"`, strings.Join(decorations, " "))
+			}
+			if run.style.dim {
+				text.WriteString(` fill-opacity="0.6"`)
+			}
+			fmt.Fprintf(&text, ">%s</tspan>", escapeHTML(run.text))
+		}
+		if text.Len() > 0 {
+			fmt.Fprintf(&buf, `    <text y="%.1f" xml:space="preserve">%s</text>`+"\n", y+svgLineHeight*0.75, text.String())
+		}
+	}
+	buf.WriteString("  </g>\n</svg>\n")
+	return buf.String()
+}
+
+// styleRun is a run of cells with the same style.
+type styleRun struct {
+	text  string
+	style cellStyle
+}
+
+func styleRuns(row []cell) []styleRun {
+	var runs []styleRun
+	var text strings.Builder
+	for i, c := range row {
+		if i > 0 && c.style != row[i-1].style {
+			runs = append(runs, styleRun{text: text.String(), style: row[i-1].style})
+			text.Reset()
+		}
+		text.WriteRune(c.r)
+	}
+	if text.Len() > 0 {
+		runs = append(runs, styleRun{text: text.String(), style: row[len(row)-1].style})
+	}
+	return runs
+}
@@ -0,0 +1,181 @@
+// Package changelog parses changelogs in the Keep a Changelog format
+// (https://keepachangelog.com) into releases:
+//
+//	## [1.2.0] - 2026-03-01
+//	### Added
+//	- The `--watch` flag.
+//
+//	[1.2.0]: https://github.com/grovetools/flow/compare/v1.1.0...v1.2.0
+//
+// Render writes them back as a page with a stable anchor per version,
+// which the manifest's releases link to.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Unreleased is the version of the changes not released yet.
+const Unreleased = "Unreleased"
+
+// Release is one version of a changelog.
+type Release struct {
+	Version    string   `json:"version"`
+	Date       string   `json:"date,omitempty"` // As written, normally YYYY-MM-DD
+	Anchor     string   `json:"anchor"`         // Of the version's heading on the changelog page
+	Yanked     bool     `json:"yanked,omitempty"`
+	CompareURL string   `json:"compare_url,omitempty"`
+	Notes      string   `json:"notes,omitempty"` // Text before the first group
+	Changes    []Change `json:"changes,omitempty"`
+}
+
+// Change is a group of a release's changes, such as Added or Fixed.
+type Change struct {
+	Type    string   `json:"type"`
+	Entries []string `json:"entries"`
+}
+
+var (
+	// releaseRe matches "## [1.2.0] - 2026-03-01", "## 1.2.0 (2026-03-01)",
+	// "## [Unreleased]" and "## [1.1.0] - 2026-02-01 [YANKED]".
+	releaseRe = regexp.MustCompile(`^##\s+\[?v?(\d[^\]\s]*|(?i:unreleased))\]?(?:\s*[-–(]\s*(\d{4}-\d{2}-\d{2})\)?)?\s*(\[YANKED\])?\s*$`)
+	groupRe   = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	entryRe   = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	linkRefRe = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+)\s*$`)
+	anchorRe  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Parse returns the releases of a changelog, newest first as written. It
+// returns none when content is not in the Keep a Changelog format.
+func Parse(content []byte) []Release {
+	var (
+		releases []Release
+		rel      *Release
+		change   *Change
+		notes    []string
+		links    = make(map[string]string)
+		fenced   bool
+	)
+	flush := func() {
+		if rel != nil {
+			rel.Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+			releases = append(releases, *rel)
+		}
+		rel, change, notes = nil, nil, nil
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		if !fenced {
+			if m := linkRefRe.FindStringSubmatch(trimmed); m != nil {
+				links[strings.ToLower(m[1])] = m[2]
+				continue
+			}
+			if m := releaseRe.FindStringSubmatch(line); m != nil {
+				flush()
+				version := m[1]
+				if strings.EqualFold(version, Unreleased) {
+					version = Unreleased
+				}
+				rel = &Release{Version: version, Date: m[2], Anchor: Anchor(version), Yanked: m[3] != ""}
+				continue
+			}
+			if strings.HasPrefix(line, "## ") || strings.HasPrefix(line, "# ") {
+				flush()
+				continue
+			}
+		}
+		if rel == nil {
+			continue
+		}
+		if m := groupRe.FindStringSubmatch(line); m != nil && !fenced {
+			rel.Changes = append(rel.Changes, Change{Type: m[1]})
+			change = &rel.Changes[len(rel.Changes)-1]
+			continue
+		}
+		if change == nil {
+			notes = append(notes, line)
+			continue
+		}
+		if m := entryRe.FindStringSubmatch(line); m != nil && !fenced {
+			change.Entries = append(change.Entries, m[1])
+			continue
+		}
+		// Continuation lines belong to the entry before them
+		if n := len(change.Entries); n > 0 && trimmed != "" {
+			change.Entries[n-1] += "\n" + strings.TrimPrefix(strings.TrimPrefix(line, "  "), "  ")
+		}
+	}
+	flush()
+
+	for i := range releases {
+		r := &releases[i]
+		r.CompareURL = links[strings.ToLower(r.Version)]
+		if r.CompareURL == "" {
+			r.CompareURL = links["v"+strings.ToLower(r.Version)]
+		}
+	}
+	return releases
+}
+
+// Anchor returns the anchor of a version's heading: "v1-2-0" for 1.2.0,
+// "unreleased" for the unreleased changes.
+func Anchor(version string) string {
+	if version == Unreleased {
+		return "unreleased"
+	}
+	return "v" + strings.Trim(anchorRe.ReplaceAllString(strings.ToLower(version), "-"), "-")
+}
+
+// Released returns releases without the unreleased changes.
+func Released(releases []Release) []Release {
+	out := make([]Release, 0, len(releases))
+	for _, r := range releases {
+		if r.Version != Unreleased {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Render writes releases as a changelog page titled title, each version
+// under a heading with its Anchor as explicit id.
+func Render(title string, releases []Release) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", title)
+	for _, r := range releases {
+		heading := r.Version
+		if r.Yanked {
+			heading += " (yanked)"
+		}
+		fmt.Fprintf(&sb, "\n## %s {#%s}\n", heading, r.Anchor)
+
+		var about []string
+		if r.Date != "" {
+			about = append(about, "Released "+r.Date)
+		}
+		if r.CompareURL != "" {
+			about = append(about, fmt.Sprintf("[Compare changes](%s)", r.CompareURL))
+		}
+		if len(about) > 0 {
+			fmt.Fprintf(&sb, "\n%s\n", strings.Join(about, " · "))
+		}
+		if r.Notes != "" {
+			fmt.Fprintf(&sb, "\n%s\n", r.Notes)
+		}
+		for _, c := range r.Changes {
+			if len(c.Entries) == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "\n### %s\n\n", c.Type)
+			for _, e := range c.Entries {
+				fmt.Fprintf(&sb, "- %s\n", strings.ReplaceAll(e, "\n", "\n  "))
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,106 @@
+package ci
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Annotation levels, as GitHub Actions workflow commands name them.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNotice  = "notice"
+)
+
+// maxSummaryAnnotations caps the annotations listed in the job summary; the
+// rest are counted.
+const maxSummaryAnnotations = 50
+
+// Annotation is one problem found by a CI run, attached to a file when it
+// has one.
+type Annotation struct {
+	Level   string `json:"level"`
+	Step    string `json:"step"`
+	File    string `json:"file,omitempty"` // Relative to the run's root
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message"`
+}
+
+// Command formats the annotation as a GitHub Actions workflow command, e.g.
+// "::error file=docs/intro.md,title=Broken link::...".
+func (a Annotation) Command() string {
+	var props []string
+	if a.File != "" {
+		props = append(props, "file="+escapeProperty(a.File))
+	}
+	if a.Title != "" {
+		props = append(props, "title="+escapeProperty(a.Title))
+	}
+	cmd := "::" + a.Level
+	if len(props) > 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+	return cmd + "::" + escapeData(a.Message)
+}
+
+// escapeData escapes a workflow command's message.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// Summary renders the run as GitHub-flavoured markdown for the job summary:
+// a table of the steps, then the annotations.
+func (r *Result) Summary() string {
+	var sb strings.Builder
+	outcome := "passed"
+	if r.Failed() {
+		outcome = "failed"
+	}
+	fmt.Fprintf(&sb, "## docgen ci: %s\n\n", outcome)
+	sb.WriteString("| Step | Status | Errors | Warnings | Duration | Detail |\n| :--- | :--- | ---: | ---: | ---: | :--- |\n")
+	for _, s := range r.Steps {
+		fmt.Fprintf(&sb, "| %s | %s | %d | %d | %.1fs | %s |\n", s.Name, statusCell(s.Status), s.Errors, s.Warnings, float64(s.DurationMs)/1000, markdownCell(s.Detail))
+	}
+	if len(r.Annotations) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\n### Problems\n\n| Level | Step | File | Problem |\n| :--- | :--- | :--- | :--- |\n")
+	for i, a := range r.Annotations {
+		if i == maxSummaryAnnotations {
+			fmt.Fprintf(&sb, "\n...and %d more; see the run's annotations.\n", len(r.Annotations)-maxSummaryAnnotations)
+			break
+		}
+		file := "-"
+		if a.File != "" {
+			file = "`" + a.File + "`"
+		}
+		problem := a.Message
+		if a.Title != "" {
+			problem = "**" + a.Title + "**: " + problem
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", a.Level, a.Step, file, markdownCell(problem))
+	}
+	return sb.String()
+}
+
+// statusCell renders a step status, failures in bold.
+func statusCell(status string) string {
+	if status == StepFailed {
+		return "**" + status + "**"
+	}
+	return status
+}
+
+// markdownCell makes s safe for a markdown table cell.
+func markdownCell(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.NewReplacer("|", `\|`, "\r", "", "\n", "<br>").Replace(s)
+}
@@ -0,0 +1,397 @@
+// Package ci runs docgen's checks as one pipeline for pull requests:
+// validate the docgen configs, generate (or, in replay mode, check that the
+// committed docs are current), aggregate, and check the aggregated docs'
+// links and frontmatter. Every problem becomes an Annotation, which docgen ci
+// prints as a GitHub Actions workflow command, and the run renders as a
+// markdown job summary.
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/aggregator"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/frontmatter"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/report"
+	"github.com/sirupsen/logrus"
+)
+
+// Generate modes.
+const (
+	// GenerateReplay calls no LLM: the committed docs are used as they are,
+	// and a doc missing or older than its prompt or config is reported.
+	GenerateReplay = "replay"
+	// GenerateLive regenerates the docs.
+	GenerateLive = "live"
+)
+
+// Step statuses.
+const (
+	StepPassed  = "passed"
+	StepFailed  = "failed"
+	StepSkipped = "skipped"
+)
+
+// Step names, in the order they run.
+const (
+	StepValidate    = "validate"
+	StepGenerate    = "generate"
+	StepAggregate   = "aggregate"
+	StepFrontmatter = "frontmatter"
+)
+
+// Options configures Run.
+type Options struct {
+	// All covers every docgen-enabled package of the configured ecosystems
+	// in validate and generate, instead of the package in the run's
+	// directory. Aggregate always covers them all.
+	All       bool
+	Generate  string // GenerateReplay (default) or GenerateLive
+	OnlyStale bool   // Live: regenerate only stale sections
+	Model     string // Live: override the model for all sections
+
+	OutputDir     string // Aggregate output directory
+	Mode          string // Aggregate mode: "dev" (default) or "prod"
+	Transform     string // Aggregate transform, e.g. "astro"
+	SkipAggregate bool   // Skip aggregate and the checks of its output
+
+	// Strict fails the run on warnings too: stale docs, placeholder pages
+	// and generation warnings.
+	Strict bool
+	// Root is the directory annotation paths are relative to, normally the
+	// repository checkout ($GITHUB_WORKSPACE). Files outside it are not
+	// attached to annotations.
+	Root   string
+	Logger *logrus.Logger
+	// UnifiedLog also logs generation milestones to grove's unified log,
+	// as the docgen commands do.
+	UnifiedLog bool
+}
+
+// Step is the outcome of one pipeline step.
+type Step struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Errors     int    `json:"errors"`
+	Warnings   int    `json:"warnings"`
+	DurationMs int64  `json:"duration_ms"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// Result is the outcome of a CI run. Annotations is always present.
+type Result struct {
+	Steps       []Step       `json:"steps"`
+	Annotations []Annotation `json:"annotations"`
+}
+
+// Failed reports whether any step failed.
+func (r *Result) Failed() bool {
+	for _, s := range r.Steps {
+		if s.Status == StepFailed {
+			return true
+		}
+	}
+	return false
+}
+
+type pkg struct{ name, path string }
+
+// runner carries a Run's state between its steps.
+type runner struct {
+	opts     Options
+	gen      *generator.Generator
+	result   *Result
+	step     *Step
+	packages []pkg
+}
+
+// Run runs the pipeline for the package at dir (or, with All, the packages
+// of dir's ecosystems). A step that fails does not stop the steps that do
+// not depend on it: generate is skipped when validation fails, and the
+// frontmatter check when aggregate fails. Run returns an error only when
+// the pipeline could not run; check Result.Failed for its outcome.
+func Run(ctx context.Context, dir string, opts Options) (*Result, error) {
+	switch opts.Generate {
+	case "":
+		opts.Generate = GenerateReplay
+	case GenerateReplay, GenerateLive:
+	default:
+		return nil, fmt.Errorf("invalid generate mode %q: must be '%s' or '%s'", opts.Generate, GenerateReplay, GenerateLive)
+	}
+	if opts.Generate == GenerateReplay && (opts.OnlyStale || opts.Model != "") {
+		return nil, fmt.Errorf("--only-stale and --model apply to live generation only")
+	}
+	if opts.Mode == "" {
+		opts.Mode = config.ModeDev
+	}
+	if err := config.CheckMode(opts.Mode); err != nil {
+		return nil, err
+	}
+	if opts.Root == "" {
+		opts.Root = dir
+	}
+	if opts.Logger == nil {
+		opts.Logger = logrus.New()
+	}
+
+	r := &runner{opts: opts, gen: generator.New(opts.Logger), result: &Result{Annotations: []Annotation{}}}
+	if opts.UnifiedLog {
+		r.gen.SetEventLogger(clilog.New(clilog.Component))
+	}
+	if opts.All {
+		found, err := r.gen.Packages(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range found {
+			r.packages = append(r.packages, pkg{p.Name, p.Path})
+		}
+	} else {
+		r.packages = []pkg{{filepath.Base(dir), dir}}
+	}
+
+	validated := r.run(StepValidate, "", r.validate)
+	if validated {
+		r.run(StepGenerate, "", func() string { return r.generate(ctx, dir) })
+	} else {
+		r.run(StepGenerate, "configuration is invalid", nil)
+	}
+	switch {
+	case opts.SkipAggregate:
+		r.run(StepAggregate, "--skip-aggregate", nil)
+		r.run(StepFrontmatter, "--skip-aggregate", nil)
+	case r.run(StepAggregate, "", func() string { return r.aggregate(ctx) }):
+		r.run(StepFrontmatter, "", func() string { return r.frontmatter(dir) })
+	default:
+		r.run(StepFrontmatter, "aggregate failed", nil)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return r.result, fmt.Errorf("ci run cancelled: %w", err)
+	}
+	return r.result, nil
+}
+
+// run runs one step and records it; fn returns the step's detail. A nil fn
+// records the step as skipped with skipReason. It reports whether the step
+// passed.
+func (r *runner) run(name, skipReason string, fn func() string) bool {
+	if fn == nil {
+		r.result.Steps = append(r.result.Steps, Step{Name: name, Status: StepSkipped, Detail: skipReason})
+		return false
+	}
+	r.opts.Logger.Infof("CI step: %s", name)
+	start := time.Now()
+	r.step = &Step{Name: name, Status: StepPassed}
+	r.step.Detail = fn()
+	r.step.DurationMs = time.Since(start).Milliseconds()
+	if r.step.Errors > 0 || (r.opts.Strict && r.step.Warnings > 0) {
+		r.step.Status = StepFailed
+	}
+	r.result.Steps = append(r.result.Steps, *r.step)
+	return r.step.Status == StepPassed
+}
+
+// annotate records a problem of the current step.
+func (r *runner) annotate(level, file, title, format string, args ...any) {
+	switch level {
+	case LevelError:
+		r.step.Errors++
+	case LevelWarning:
+		r.step.Warnings++
+	}
+	r.result.Annotations = append(r.result.Annotations, Annotation{
+		Level:   level,
+		Step:    r.step.Name,
+		File:    r.relative(file),
+		Title:   title,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// relative returns path relative to the run's root, or "" when it is
+// outside it.
+func (r *runner) relative(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	root, err := filepath.Abs(r.opts.Root)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// configFile returns the path of the docgen config of the package at dir,
+// or "" when it does not load.
+func configFile(dir string) string {
+	_, path, err := config.LoadWithNotebook(dir)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+func (r *runner) validate() string {
+	for _, p := range r.packages {
+		file := configFile(p.path)
+		for _, c := range r.gen.ValidateConfig(p.path).Checks {
+			message := c.Detail
+			if c.Fix != "" {
+				message += " (fix: " + c.Fix + ")"
+			}
+			switch c.Status {
+			case generator.DoctorFail:
+				r.annotate(LevelError, file, p.name+": "+c.Name, "%s", message)
+			case generator.DoctorWarn:
+				r.annotate(LevelWarning, file, p.name+": "+c.Name, "%s", message)
+			}
+		}
+	}
+	return fmt.Sprintf("%d package(s)", len(r.packages))
+}
+
+func (r *runner) generate(ctx context.Context, dir string) string {
+	if r.opts.Generate == GenerateReplay {
+		return r.replay()
+	}
+
+	rec := report.New("generate")
+	r.gen.SetReport(rec)
+	defer r.gen.SetReport(nil)
+	opts := generator.GenerateOptions{Model: r.opts.Model, OnlyStale: r.opts.OnlyStale, Strict: r.opts.Strict}
+	var err error
+	if r.opts.All {
+		err = r.gen.GenerateAllContext(ctx, dir, generator.AllOptions{GenerateOptions: opts})
+	} else {
+		err = r.gen.GenerateContext(ctx, dir, opts)
+	}
+	run := rec.Finish(err)
+
+	paths := make(map[string]string)
+	for _, p := range r.packages {
+		paths[p.name] = p.path
+	}
+	failed := 0
+	for _, s := range run.Sections {
+		if s.Status != report.StatusFailed {
+			continue
+		}
+		failed++
+		path, name := dir, s.Name
+		if s.Package != "" {
+			path, name = paths[s.Package], s.Package+"/"+s.Name
+		}
+		r.annotate(LevelError, configFile(path), "Section "+name+" failed", "%s", s.Error)
+	}
+	if err != nil && failed == 0 {
+		r.annotate(LevelError, "", "Generation failed", "%v", err)
+	}
+	return fmt.Sprintf("live: %d section(s), %d generated, %d failed, %d skipped", run.Totals.Sections, run.Totals.Succeeded, run.Totals.Failed, run.Totals.Skipped)
+}
+
+// replay reports, without generating, the docs that are missing or older
+// than their prompt or config.
+func (r *runner) replay() string {
+	total, stale := 0, 0
+	for _, p := range r.packages {
+		targets, err := generator.ResolveSectionTargets(p.path)
+		if err != nil {
+			r.annotate(LevelError, configFile(p.path), p.name+": sections", "%v", err)
+			continue
+		}
+		names, err := r.gen.StaleSections(p.path)
+		if err != nil {
+			r.annotate(LevelError, configFile(p.path), p.name+": sections", "%v", err)
+			continue
+		}
+		total += len(targets)
+		stale += len(names)
+		isStale := make(map[string]bool, len(names))
+		for _, name := range names {
+			isStale[name] = true
+		}
+		for _, t := range targets {
+			if !isStale[t.Name] {
+				continue
+			}
+			doc := filepath.Join(t.OutputDir, t.Section.Output)
+			if _, err := os.Stat(doc); err != nil {
+				r.annotate(LevelWarning, configFile(p.path), "Missing doc", "%s/%s has never been generated; run docgen generate --section %s", p.name, t.Name, t.Name)
+				continue
+			}
+			r.annotate(LevelWarning, doc, "Stale doc", "%s/%s is older than its prompt or config; run docgen generate --section %s", p.name, t.Name, t.Name)
+		}
+	}
+	return fmt.Sprintf("replay: %d of %d section(s) stale", stale, total)
+}
+
+func (r *runner) aggregate(ctx context.Context) string {
+	agg := aggregator.New(r.opts.Logger)
+	if err := agg.AggregateContext(ctx, r.opts.OutputDir, r.opts.Mode, r.opts.Transform); err != nil {
+		r.annotate(LevelError, "", "Aggregate failed", "%v", err)
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.opts.OutputDir, aggregator.ValidationFile)) //nolint:gosec // aggregate output
+	if err != nil {
+		r.annotate(LevelError, "", "Validation report", "%v", err)
+		return ""
+	}
+	var validation aggregator.ValidationReport
+	if err := json.Unmarshal(data, &validation); err != nil {
+		r.annotate(LevelError, "", "Validation report", "invalid %s: %v", aggregator.ValidationFile, err)
+		return ""
+	}
+	for _, issue := range validation.Issues {
+		level := LevelNotice
+		switch issue.Level {
+		case aggregator.LevelError:
+			level = LevelError
+		case aggregator.LevelWarning:
+			level = LevelWarning
+		}
+		title := strings.ReplaceAll(issue.Kind, "_", " ")
+		if issue.Package != "" {
+			title = issue.Package + ": " + title
+		}
+		r.annotate(level, issue.File, title, "%s", issue.Message)
+	}
+	return fmt.Sprintf("%s mode into %s", r.opts.Mode, r.opts.OutputDir)
+}
+
+func (r *runner) frontmatter(dir string) string {
+	schema := frontmatter.DefaultSchema()
+	if cfg, err := config.Load(dir); err == nil && cfg.Frontmatter != nil {
+		schema = cfg.Frontmatter
+	}
+	problems, files, err := frontmatter.CheckDir(r.opts.OutputDir, schema)
+	if err != nil {
+		r.annotate(LevelError, "", "Frontmatter check failed", "%v", err)
+		return ""
+	}
+	for _, p := range problems {
+		title := "Invalid frontmatter"
+		if p.Field != "" {
+			title += ": " + p.Field
+		}
+		r.annotate(LevelError, p.File, title, "%s", p.Message)
+	}
+	return fmt.Sprintf("%d file(s)", files)
+}
@@ -0,0 +1,245 @@
+// Package citations handles the source citations generated docs carry with
+// settings.citations: HTML comments such as
+//
+//	<!-- cite: pkg/cache/cache.go:40-72, cmd/root.go:12 -->
+//
+// after the claim they ground. Comments are invisible on the rendered page.
+// Verify checks each cited file and line range against the package,
+// WriteSidecar records the result next to the doc for review, and Strip
+// removes the comments from published pages.
+package citations
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Instructions is appended to a section's prompt when settings.citations is
+// on.
+const Instructions = `
+
+---
+
+Ground the documentation in the source code. After each paragraph, list item or table that states a fact about the code, cite the source files and lines it is based on in an HTML comment, with paths relative to the project root:
+
+<!-- cite: path/to/file.go:40-72, path/to/other.go:12 -->
+
+Cite only files you were given. Never put a citation inside a code block.`
+
+var (
+	commentRe = regexp.MustCompile(`[ \t]*<!--\s*cite:\s*(.*?)\s*-->`)
+	refRe     = regexp.MustCompile(`^(.+?)(?::(\d+)(?:-(\d+))?)?$`)
+	fenceRe   = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// Citation is one cited file or line range.
+type Citation struct {
+	File  string `json:"file"`
+	Start int    `json:"start,omitempty"` // First cited line; 0 cites the whole file
+	End   int    `json:"end,omitempty"`   // Last cited line; Start for a single line
+	Line  int    `json:"line"`            // Line of the doc the citation is on
+	Claim string `json:"claim"`           // The doc text the citation grounds
+	// Problem says why the citation does not hold, set by Verify; empty
+	// when it does.
+	Problem string `json:"problem,omitempty"`
+}
+
+// Parse returns the citations of a doc, in order. Comments in code blocks
+// are not citations.
+func Parse(doc string) []Citation {
+	var cites []Citation
+	var prev []string // Lines since the last blank line, for the claim
+	inFence := false
+	for i, line := range strings.Split(doc, "\n") {
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+		}
+		if inFence {
+			continue
+		}
+		matches := commentRe.FindAllStringSubmatchIndex(line, -1)
+		if len(matches) == 0 {
+			if strings.TrimSpace(line) == "" {
+				prev = prev[:0]
+			} else {
+				prev = append(prev, strings.TrimSpace(line))
+			}
+			continue
+		}
+		claim := strings.TrimSpace(commentRe.ReplaceAllString(line, ""))
+		if claim == "" {
+			claim = strings.Join(prev, " ")
+		}
+		for _, m := range matches {
+			for _, ref := range strings.Split(line[m[2]:m[3]], ",") {
+				if c, ok := parseRef(strings.TrimSpace(ref)); ok {
+					c.Line = i + 1
+					c.Claim = truncate(claim, 200)
+					cites = append(cites, c)
+				}
+			}
+		}
+	}
+	return cites
+}
+
+func parseRef(ref string) (Citation, bool) {
+	m := refRe.FindStringSubmatch(ref)
+	if m == nil || m[1] == "" {
+		return Citation{}, false
+	}
+	c := Citation{File: m[1]}
+	if m[2] != "" {
+		c.Start, _ = strconv.Atoi(m[2])
+		c.End = c.Start
+	}
+	if m[3] != "" {
+		c.End, _ = strconv.Atoi(m[3])
+	}
+	return c, true
+}
+
+// Verify checks every citation against the files under root, setting its
+// Problem when the file does not exist, lies outside root, or has fewer
+// lines than cited. It returns the citations that do not hold.
+func Verify(cites []Citation, root string) []Citation {
+	lines := make(map[string]int) // File line counts; -1 when unreadable
+	var bad []Citation
+	for i := range cites {
+		c := &cites[i]
+		rel := filepath.Clean(filepath.FromSlash(c.File))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			c.Problem = "outside the package"
+			bad = append(bad, *c)
+			continue
+		}
+		n, ok := lines[rel]
+		if !ok {
+			n = countLines(filepath.Join(root, rel))
+			lines[rel] = n
+		}
+		switch {
+		case n < 0:
+			c.Problem = "file does not exist"
+		case c.Start > c.End:
+			c.Problem = fmt.Sprintf("invalid line range %d-%d", c.Start, c.End)
+		case c.End > n:
+			c.Problem = fmt.Sprintf("file has %d lines", n)
+		}
+		if c.Problem != "" {
+			bad = append(bad, *c)
+		}
+	}
+	return bad
+}
+
+// countLines returns the number of lines of the file at path, or -1 when it
+// is not a readable file.
+func countLines(path string) int {
+	f, err := os.Open(path) //nolint:gosec // path from a citation, checked to be under the package
+	if err != nil {
+		return -1
+	}
+	defer func() { _ = f.Close() }()
+	if info, err := f.Stat(); err != nil || info.IsDir() {
+		return -1
+	}
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	n := 0
+	for s.Scan() {
+		n++
+	}
+	if s.Err() != nil {
+		return -1
+	}
+	return n
+}
+
+// SidecarPath returns the path of the citations sidecar of the doc at
+// docPath: docs/overview.md has docs/overview.citations.json.
+func SidecarPath(docPath string) string {
+	return strings.TrimSuffix(docPath, filepath.Ext(docPath)) + ".citations.json"
+}
+
+// WriteSidecar writes the citations of the doc at docPath to its sidecar,
+// or removes a stale sidecar when there are none.
+func WriteSidecar(docPath string, cites []Citation) error {
+	path := SidecarPath(docPath)
+	if len(cites) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(struct {
+		Doc       string     `json:"doc"`
+		Citations []Citation `json:"citations"`
+	}{filepath.Base(docPath), cites}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644) //nolint:gosec // review output, not secret
+}
+
+// Strip removes the citation comments from a doc, and the lines left empty
+// by comments on a line of their own. Code blocks are left as they are.
+func Strip(doc []byte) []byte {
+	if !bytes.Contains(doc, []byte("cite:")) {
+		return doc
+	}
+	lines := strings.Split(string(doc), "\n")
+	out := lines[:0]
+	inFence, dropped := false, false
+	for _, line := range lines {
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+		}
+		if inFence || !commentRe.MatchString(line) {
+			out = append(out, line)
+			continue
+		}
+		if stripped := commentRe.ReplaceAllString(line, ""); strings.TrimSpace(stripped) != "" {
+			out = append(out, stripped)
+			continue
+		}
+		dropped = true
+	}
+	out = collapseBlankLines(out, dropped)
+	return []byte(strings.Join(out, "\n"))
+}
+
+// collapseBlankLines turns the runs of blank lines left where comment lines
+// were dropped into single blank lines. Code blocks are left as they are.
+func collapseBlankLines(lines []string, dropped bool) []string {
+	if !dropped {
+		return lines
+	}
+	out := lines[:0]
+	inFence := false
+	for _, line := range lines {
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+		}
+		blank := strings.TrimSpace(line) == ""
+		if !inFence && blank && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "..."
+	}
+	return s
+}
@@ -3,14 +3,19 @@ package config
 //go:generate sh -c "cd ../.. && go run ./tools/schema-generator/"
 
 import (
+	"errors"
 	"fmt"
+	"mime"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	coreConfig "github.com/grovetools/core/config"
 	"github.com/grovetools/core/pkg/workspace"
 	cxcontext "github.com/grovetools/cx/pkg/context"
+	"github.com/grovetools/docgen/pkg/transformer"
+	"github.com/invopop/jsonschema"
 	"gopkg.in/yaml.v3"
 )
 
@@ -21,19 +26,61 @@ const (
 	StatusDraft      = "draft"      // Only in notebook, not synced anywhere
 	StatusDev        = "dev"        // Synced to dev website (from notebook)
 	StatusProduction = "production" // Synced to repo (and prod website)
+
+	// Build modes of aggregate and watch
+	ModeDev  = "dev"  // Dev website: dev and production content
+	ModeProd = "prod" // Production website: production content only
+
+	// What aggregate publishes for a section whose doc was not generated
+	PlaceholdersOff      = "off"       // Nothing; the section is left out
+	PlaceholdersPrompt   = "prompt"    // A placeholder page showing the section's prompt
+	PlaceholdersTodoPage = "todo-page" // A placeholder page saying the doc is pending
+
+	// Output profiles of aggregate
+	ProfileInternal = "internal" // Everything, for the internal notebook site
+	ProfilePublic   = "public"   // Internal content left out and scrub patterns applied
 )
 
 // DocgenConfig defines the structure for a package's documentation settings.
 type DocgenConfig struct {
-	Enabled     bool            `yaml:"enabled" jsonschema:"description=Whether documentation generation is enabled for this package" jsonschema_extras:"x-layer=project,x-priority=10"`
-	Title       string          `yaml:"title" jsonschema:"description=Title of the package documentation" jsonschema_extras:"x-layer=project,x-priority=11"`
-	Description string          `yaml:"description" jsonschema:"description=Brief description of the package" jsonschema_extras:"x-layer=project,x-priority=12"`
-	Category    string          `yaml:"category" jsonschema:"description=Category for grouping in documentation sidebar" jsonschema_extras:"x-layer=project,x-priority=15"`
-	Settings    SettingsConfig  `yaml:"settings,omitempty" jsonschema:"description=Generator-wide settings" jsonschema_extras:"x-layer=project,x-priority=20"`
-	Sections    []SectionConfig `yaml:"sections" jsonschema:"description=List of documentation sections to generate" jsonschema_extras:"x-layer=project,x-priority=30"`
-	Readme      *ReadmeConfig   `yaml:"readme,omitempty" jsonschema:"description=README synchronization configuration" jsonschema_extras:"x-layer=project,x-priority=40"`
-	Sidebar     *SidebarConfig  `yaml:"sidebar,omitempty" jsonschema:"description=Website sidebar configuration" jsonschema_extras:"x-layer=ecosystem,x-priority=50"`
-	Logos       []string        `yaml:"logos,omitempty" jsonschema:"description=Additional logo files to copy during aggregation (absolute paths with ~ expansion)" jsonschema_extras:"x-layer=project,x-priority=45"`
+	Enabled     bool               `yaml:"enabled" jsonschema:"description=Whether documentation generation is enabled for this package" jsonschema_extras:"x-layer=project,x-priority=10"`
+	Title       string             `yaml:"title" jsonschema:"description=Title of the package documentation" jsonschema_extras:"x-layer=project,x-priority=11"`
+	Description string             `yaml:"description" jsonschema:"description=Brief description of the package" jsonschema_extras:"x-layer=project,x-priority=12"`
+	Category    string             `yaml:"category" jsonschema:"description=Category for grouping in documentation sidebar" jsonschema_extras:"x-layer=project,x-priority=15"`
+	Settings    SettingsConfig     `yaml:"settings,omitempty" jsonschema:"description=Generator-wide settings" jsonschema_extras:"x-layer=project,x-priority=20"`
+	Sections    []SectionConfig    `yaml:"sections" jsonschema:"description=List of documentation sections to generate" jsonschema_extras:"x-layer=project,x-priority=30"`
+	Readme      *ReadmeConfig      `yaml:"readme,omitempty" jsonschema:"description=README synchronization configuration" jsonschema_extras:"x-layer=project,x-priority=40"`
+	Sidebar     *SidebarConfig     `yaml:"sidebar,omitempty" jsonschema:"description=Website sidebar configuration" jsonschema_extras:"x-layer=ecosystem,x-priority=50"`
+	Logos       []string           `yaml:"logos,omitempty" jsonschema:"description=Additional logo files to copy during aggregation (absolute paths with ~ expansion)" jsonschema_extras:"x-layer=project,x-priority=45"`
+	Frontmatter *FrontmatterConfig `yaml:"frontmatter,omitempty" jsonschema:"description=Frontmatter schema of the website's docs content collection, checked by docgen check frontmatter" jsonschema_extras:"x-layer=ecosystem,x-priority=55"`
+	SocialCards *SocialCardsConfig `yaml:"social_cards,omitempty" jsonschema:"description=Generate an Open Graph image for each doc page during aggregation" jsonschema_extras:"x-layer=project,x-priority=46"`
+}
+
+// SocialCardsConfig configures the social card (Open Graph image) rendered
+// for each doc page. Setting it turns the cards on.
+type SocialCardsConfig struct {
+	Font       string `yaml:"font" jsonschema:"description=Font file (TTF/OTF) for the card text (~ is expanded)"`
+	Logo       string `yaml:"logo,omitempty" jsonschema:"description=SVG logo drawn on the card (default: the first SVG in logos)"`
+	Background string `yaml:"background,omitempty" jsonschema:"description=Background color (default: #0d1117)"`
+	TextColor  string `yaml:"text_color,omitempty" jsonschema:"description=Title color (default: #f0f6fc)"`
+	Accent     string `yaml:"accent,omitempty" jsonschema:"description=Color of the package name and accent bar (default: the logo's color)"`
+	SiteURL    string `yaml:"site_url,omitempty" jsonschema:"description=Website origin prefixed to the image path, since crawlers expect an absolute og:image URL (e.g. https://grovetools.ai)"`
+}
+
+// FrontmatterConfig is the frontmatter schema generated docs must satisfy,
+// mirroring the website's content collection (zod) schema so that
+// `docgen check frontmatter` fails before the website build does.
+type FrontmatterConfig struct {
+	Fields map[string]FrontmatterField `yaml:"fields" jsonschema:"description=Frontmatter fields by name" jsonschema_extras:"x-layer=ecosystem,x-priority=55"`
+	Strict bool                        `yaml:"strict,omitempty" jsonschema:"description=Reject fields not in the schema, like zod's .strict()" jsonschema_extras:"x-layer=ecosystem,x-priority=56"`
+}
+
+// FrontmatterField is one field of a FrontmatterConfig.
+type FrontmatterField struct {
+	Type     string   `yaml:"type" jsonschema:"description=Field type,enum=string,enum=number,enum=boolean,enum=date,enum=array,enum=object" jsonschema_extras:"x-layer=ecosystem,x-priority=55"`
+	Required bool     `yaml:"required,omitempty" jsonschema:"description=The field must be present (zod fields without .optional())" jsonschema_extras:"x-layer=ecosystem,x-priority=56"`
+	Enum     []string `yaml:"enum,omitempty" jsonschema:"description=Allowed values (zod enum)" jsonschema_extras:"x-layer=ecosystem,x-priority=57"`
+	Pattern  string   `yaml:"pattern,omitempty" jsonschema:"description=Regular expression string values must match (zod regex)" jsonschema_extras:"x-layer=ecosystem,x-priority=58"`
 }
 
 // SidebarConfig defines the sidebar ordering and display configuration.
@@ -70,17 +117,32 @@ type GenerationConfig struct {
 
 // SettingsConfig holds generator-wide settings.
 type SettingsConfig struct {
-	Model                string   `yaml:"model,omitempty" jsonschema:"description=LLM model to use for generation" jsonschema_extras:"x-layer=project,x-priority=20"`
-	OutputMode           string   `yaml:"output_mode,omitempty" jsonschema:"description=Output mode: package (default) or sections for website content,enum=package,enum=sections" jsonschema_extras:"x-layer=project,x-priority=21"`
-	Ecosystems           []string `yaml:"ecosystems,omitempty" jsonschema:"description=List of ecosystem names to aggregate from" jsonschema_extras:"x-layer=ecosystem,x-priority=22"`
-	RegenerationMode     string   `yaml:"regeneration_mode,omitempty" jsonschema:"description=Regeneration mode: scratch or reference,enum=scratch,enum=reference" jsonschema_extras:"x-layer=project,x-priority=23"`
-	RulesFile            string   `yaml:"rules_file,omitempty" jsonschema:"description=Required docs context preset name (for example doc); explicit legacy .rules paths remain supported" jsonschema_extras:"x-layer=project,x-priority=24"`
-	StructuredOutputFile string   `yaml:"structured_output_file,omitempty" jsonschema:"description=Path for JSON output" jsonschema_extras:"x-layer=project,x-priority=29"`
-	SystemPrompt         string   `yaml:"system_prompt,omitempty" jsonschema:"description=Path to system prompt file or 'default' to use built-in" jsonschema_extras:"x-layer=project,x-priority=25"`
-	OutputDir            string   `yaml:"output_dir,omitempty" jsonschema:"description=Output directory for generated docs" jsonschema_extras:"x-layer=project,x-priority=26"`
-	TocDepth             int      `yaml:"toc_depth,omitempty" jsonschema:"description=Maximum heading level to show in Table of Contents (default: 3)" jsonschema_extras:"x-layer=project,x-priority=27"`
-	CacheFanout          bool     `yaml:"cache_fanout,omitempty" jsonschema:"description=Route claude-* section generation through the grove-anthropic shared-prefix cache fan-out (one cached repo-context prefix, per-section task requests) instead of shelling grove llm request. Only takes effect when the effective model is a Claude model." jsonschema_extras:"x-layer=project,x-priority=28"`
-	CacheTTL             string   `yaml:"cache_ttl,omitempty" jsonschema:"description=Cache TTL for the fan-out shared prefix: 5m (default) or 1h. A longer TTL pays off when a generation wave or repeated re-runs span more than five minutes,enum=5m,enum=1h" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Model                string               `yaml:"model,omitempty" jsonschema:"description=LLM model to use for generation" jsonschema_extras:"x-layer=project,x-priority=20"`
+	OutputMode           string               `yaml:"output_mode,omitempty" jsonschema:"description=Output mode: package (default) or sections for website content,enum=package,enum=sections" jsonschema_extras:"x-layer=project,x-priority=21"`
+	Ecosystems           []string             `yaml:"ecosystems,omitempty" jsonschema:"description=List of ecosystem names to aggregate from" jsonschema_extras:"x-layer=ecosystem,x-priority=22"`
+	RegenerationMode     string               `yaml:"regeneration_mode,omitempty" jsonschema:"description=Regeneration mode: scratch or reference,enum=scratch,enum=reference" jsonschema_extras:"x-layer=project,x-priority=23"`
+	RulesFile            string               `yaml:"rules_file,omitempty" jsonschema:"description=Required docs context preset name (for example doc); explicit legacy .rules paths remain supported" jsonschema_extras:"x-layer=project,x-priority=24"`
+	StructuredOutputFile string               `yaml:"structured_output_file,omitempty" jsonschema:"description=Path for JSON output" jsonschema_extras:"x-layer=project,x-priority=29"`
+	SystemPrompt         string               `yaml:"system_prompt,omitempty" jsonschema:"description=Path to system prompt file or 'default' to use built-in" jsonschema_extras:"x-layer=project,x-priority=25"`
+	OutputDir            string               `yaml:"output_dir,omitempty" jsonschema:"description=Output directory for generated docs" jsonschema_extras:"x-layer=project,x-priority=26"`
+	TocDepth             int                  `yaml:"toc_depth,omitempty" jsonschema:"description=Maximum heading level to show in Table of Contents (default: 3)" jsonschema_extras:"x-layer=project,x-priority=27"`
+	Glossary             string               `yaml:"glossary,omitempty" jsonschema:"description=Path to the glossary injected into generation prompts relative to the package root (default: the nearest glossary.yml in the package or a parent directory)" jsonschema_extras:"x-layer=project,x-priority=27"`
+	CacheFanout          bool                 `yaml:"cache_fanout,omitempty" jsonschema:"description=Route claude-* section generation through the grove-anthropic shared-prefix cache fan-out (one cached repo-context prefix, per-section task requests) instead of shelling grove llm request. Only takes effect when the effective model is a Claude model." jsonschema_extras:"x-layer=project,x-priority=28"`
+	CacheTTL             string               `yaml:"cache_ttl,omitempty" jsonschema:"description=Cache TTL for the fan-out shared prefix: 5m (default) or 1h. A longer TTL pays off when a generation wave or repeated re-runs span more than five minutes,enum=5m,enum=1h" jsonschema_extras:"x-layer=project,x-priority=29"`
+	SanitizeWithLLM      bool                 `yaml:"sanitize_with_llm,omitempty" jsonschema:"description=When a section's response still contains conversational text after cleanup, ask the model to extract the document from it (one extra request)" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Postprocess          []PostprocessStep    `yaml:"postprocess,omitempty" jsonschema:"description=Post-processors applied in order to each prompt-driven section's output before it is written: trim_whitespace, normalize, wrap, shift_headings or prettier" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Placeholders         string               `yaml:"placeholders,omitempty" jsonschema:"description=What aggregate publishes for a section whose doc was not generated: prompt (default) is a placeholder page showing the prompt; todo-page is a placeholder page saying the doc is pending; off publishes nothing. Placeholders are never published in prod mode,enum=off,enum=prompt,enum=todo-page" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Assets               *AssetsConfig        `yaml:"assets,omitempty" jsonschema:"description=Asset handling: extra asset directories and video processing" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Citations            bool                 `yaml:"citations,omitempty" jsonschema:"description=Ask the LLM to cite the source files and lines of its claims in HTML comments. Cited files are verified, the citations are recorded in a .citations.json file next to each doc, and aggregate strips them from pages in prod mode" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Questions            bool                 `yaml:"questions,omitempty" jsonschema:"description=Let the LLM answer with questions instead of guessing when the context is not enough to document something. Answers are read from answers.yml next to this config or asked for on a terminal, and the section is generated again with them" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Metadata             *MetadataConfig      `yaml:"metadata,omitempty" jsonschema:"description=Build metadata aggregate records in each published page's frontmatter so it can be traced back to the build that produced it" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Components           []ComponentMapping   `yaml:"components,omitempty" jsonschema:"description=Astro components that code blocks of a fence language are replaced with on the website. Pages using one are published as MDX with the component imports added. Mappings in the website's config apply to every package; a package mapping for the same language replaces it" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Scrub                []ScrubRule          `yaml:"scrub,omitempty" jsonschema:"description=Patterns replaced in every published file of an aggregate with --profile public (such as internal hostnames and email addresses). Rules in the website's config apply to every package and a package's rules to its own files" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Credentials          string               `yaml:"credentials,omitempty" jsonschema:"description=Credentials profile the provider API keys are read from (see docgen auth login --profile). Keys the profile does not have come from the default profile; API keys in the environment take precedence over stored ones" jsonschema_extras:"x-layer=ecosystem,x-priority=29"`
+	RateLimits           map[string]RateLimit `yaml:"rate_limits,omitempty" jsonschema:"description=Quotas of LLM providers (anthropic or gemini or openai) shared by every concurrent generation and the schema enricher. Requests over a quota wait until it has room" jsonschema_extras:"x-layer=ecosystem,x-priority=29"`
+	Context              *ContextBudget       `yaml:"context,omitempty" jsonschema:"description=Budget of the cx context sent with each section's prompt: the number of most relevant files to keep and a cap on tokens with the reductions applied over it; sections can override it" jsonschema_extras:"x-layer=project,x-priority=29"`
+	Ollama               *OllamaConfig        `yaml:"ollama,omitempty" jsonschema:"description=Server and context window of the local models named ollama/<model>" jsonschema_extras:"x-layer=ecosystem,x-priority=29"`
+	Tutorial             *TutorialConfig      `yaml:"tutorial,omitempty" jsonschema:"description=Whether and how tutorial sections run the commands the LLM writes. They are not run unless allow_exec is set" jsonschema_extras:"x-layer=project,x-priority=29"`
 	GenerationConfig     `yaml:",inline"`
 }
 
@@ -96,10 +158,16 @@ type SectionConfig struct {
 	Output           string             `yaml:"output" jsonschema:"description=Output markdown filename" jsonschema_extras:"x-layer=project,x-priority=34"`
 	OutputDir        string             `yaml:"output_dir,omitempty" jsonschema:"description=Output directory name for sections mode" jsonschema_extras:"x-layer=project,x-priority=34"`
 	JSONKey          string             `yaml:"json_key,omitempty" jsonschema:"description=Key for structured JSON output" jsonschema_extras:"x-layer=project,x-priority=38"`
-	Type             string             `yaml:"type,omitempty" jsonschema:"description=Type of generation: schema_to_md (LLM-generated), schema_table (deterministic table), schema_describe (generate descriptions JSON), schema_examples (generate example TOML snippets), doc_sections, capture, nb_concept, tui_keymaps, or tui_describe,enum=schema_to_md,enum=schema_table,enum=schema_describe,enum=schema_examples,enum=doc_sections,enum=capture,enum=nb_concept,enum=tui_keymaps,enum=tui_describe" jsonschema_extras:"x-layer=project,x-priority=30"`
+	Type             string             `yaml:"type,omitempty" jsonschema:"description=Type of generation: schema_to_md (LLM-generated), schema_table (deterministic table), schema_describe (generate descriptions JSON), schema_examples (generate example TOML snippets), doc_sections, capture, nb_concept, tui_keymaps, tui_describe, concat (combine other sections into one file), faq_from_issues (FAQ from closed GitHub questions), tutorial (walkthrough with verified commands), template (markdown skeleton in prompt whose {{llm}} placeholders the LLM fills in), error_reference (exported errors of a Go module), metrics_reference (Prometheus metrics table), http_routes (endpoints from router registrations), make_targets (Makefile/Taskfile/justfile targets), ci_workflows (GitHub Actions workflows), sql_schema (database tables and ER diagram), contributors (maintainers from CODEOWNERS and contributors from git history), or licenses (third-party notices of the Go module's dependencies),enum=schema_to_md,enum=schema_table,enum=schema_describe,enum=schema_examples,enum=doc_sections,enum=capture,enum=nb_concept,enum=tui_keymaps,enum=tui_describe,enum=concat,enum=faq_from_issues,enum=tutorial,enum=template,enum=error_reference,enum=metrics_reference,enum=http_routes,enum=make_targets,enum=ci_workflows,enum=sql_schema,enum=contributors,enum=licenses" jsonschema_extras:"x-layer=project,x-priority=30"`
 	TUIs             []TUIEntry         `yaml:"tuis,omitempty" jsonschema:"description=List of TUIs to include for tui_keymaps type. Each entry can be a string (TUI name) or object with name and command fields" jsonschema_extras:"x-layer=project,x-priority=40"`
-	Source           string             `yaml:"source,omitempty" jsonschema:"description=Source identifier. For schema_to_md: path to JSON schema file (deprecated: use schemas instead). For nb_concept: concept ID (e.g. my-concept or workspace:my-concept for cross-workspace)" jsonschema_extras:"x-layer=project,x-priority=35"`
-	Descriptions     string             `yaml:"descriptions,omitempty" jsonschema:"description=Path to JSON file with LLM-generated descriptions (for schema_table type)" jsonschema_extras:"x-layer=project,x-priority=39"`
+	Split            string             `yaml:"split,omitempty" jsonschema:"description=For tui_keymaps: per_tui writes one page per TUI next to an index page,enum=per_tui" jsonschema_extras:"x-layer=project,x-priority=41"`
+	CheatSheet       string             `yaml:"cheat_sheet,omitempty" jsonschema:"description=For tui_keymaps: output path for a printable cheat sheet with every binding in one table. A path ending in .pdf writes a PDF and any other a markdown page" jsonschema_extras:"x-layer=project,x-priority=41"`
+	RegistryFile     string             `yaml:"registry_file,omitempty" jsonschema:"description=For tui_keymaps and tui_describe: JSON keybinding registry file relative to the package root (instead of running grove keys dump)" jsonschema_extras:"x-layer=project,x-priority=41"`
+	RegistryCmd      string             `yaml:"registry_cmd,omitempty" jsonschema:"description=For tui_keymaps and tui_describe: shell command that prints the JSON keybinding registry (default: grove keys dump)" jsonschema_extras:"x-layer=project,x-priority=41"`
+	Source           SourceList         `yaml:"source,omitempty" jsonschema:"description=Source identifier. For schema_to_md: path to JSON schema file (deprecated: use schemas instead). For nb_concept: concept ID or glob (e.g. my-concept or workspace:cx-* for cross-workspace) or a list of them. For error_reference: package directories to scan relative to the package root (default: the whole module). For metrics_reference: package directories to scan and OpenMetrics descriptor files to read. For http_routes: package directories to scan and OpenAPI specs to merge. For make_targets: Makefiles/Taskfiles/justfiles to document (default: those found in the package root). For ci_workflows: workflow files or directories (default: .github/workflows). For sql_schema: migration files or directories (default: migrations/ or db/migrations/). For licenses: package patterns whose dependencies are listed (default: ./...)" jsonschema_extras:"x-layer=project,x-priority=35"`
+	Include          []string           `yaml:"include,omitempty" jsonschema:"description=For nb_concept: glob patterns of concept files to publish relative to the concept directory (default: all files). For concat: section names or globs to combine (default: all markdown sections)" jsonschema_extras:"x-layer=project,x-priority=36"`
+	Exclude          []string           `yaml:"exclude,omitempty" jsonschema:"description=For nb_concept: glob patterns of concept files to skip relative to the concept directory. For concat: section names or globs to leave out" jsonschema_extras:"x-layer=project,x-priority=36"`
+	Descriptions     string             `yaml:"descriptions,omitempty" jsonschema:"description=Descriptions store to read (schema_table and tui_keymaps) or to read and fill with command descriptions (capture)" jsonschema_extras:"x-layer=project,x-priority=39"`
 	Examples         string             `yaml:"examples,omitempty" jsonschema:"description=Path to JSON file with LLM-generated examples (for schema_table type with format: json)" jsonschema_extras:"x-layer=project,x-priority=39"`
 	ExamplesFormat   string             `yaml:"examples_format,omitempty" jsonschema:"description=Format of examples: toml (default) or yaml,enum=toml,enum=yaml" jsonschema_extras:"x-layer=project,x-priority=39"`
 	TomlSection      string             `yaml:"toml_section,omitempty" jsonschema:"description=TOML section name to wrap examples in (e.g. 'nav' produces [nav] header). For schema_examples type with format: toml" jsonschema_extras:"x-layer=project,x-priority=39"`
@@ -107,12 +175,312 @@ type SectionConfig struct {
 	Format           string             `yaml:"format,omitempty" jsonschema:"description=Output format. For capture: styled (default) or plain. For schema_table: markdown (default) or json,enum=styled,enum=plain,enum=markdown,enum=json" jsonschema_extras:"x-layer=project,x-priority=37"`
 	Depth            int                `yaml:"depth,omitempty" jsonschema:"description=Recursion depth for capture type (default: 5)" jsonschema_extras:"x-layer=project,x-priority=38"`
 	SubcommandOrder  []string           `yaml:"subcommand_order,omitempty" jsonschema:"description=Priority order for subcommands (rest alphabetical)" jsonschema_extras:"x-layer=project,x-priority=39"`
+	CobraJSON        bool               `yaml:"cobra_json,omitempty" jsonschema:"description=For capture: discover subcommands from the tool's docs json command or cobra's __complete instead of parsing help text (falls back to parsing)" jsonschema_extras:"x-layer=project,x-priority=39"`
 	Model            string             `yaml:"model,omitempty" jsonschema:"description=Per-section model override" jsonschema_extras:"x-layer=project,x-priority=25"`
 	RulesFile        string             `yaml:"rules_file,omitempty" jsonschema:"description=Context preset name or legacy .rules path for schema_describe and schema_examples" jsonschema_extras:"x-layer=project,x-priority=26"`
+	LLM              *bool              `yaml:"llm,omitempty" jsonschema:"description=For schema_to_md: set to false to render deterministic Markdown tables instead of calling the LLM. For error_reference: set to false to skip the LLM remediation guidance. For make_targets: set to false to leave targets without comments undescribed. For ci_workflows: set to false to skip the per-workflow summaries (default: true)" jsonschema_extras:"x-layer=project,x-priority=25"`
+	Repo             string             `yaml:"repo,omitempty" jsonschema:"description=For faq_from_issues: GitHub repository as owner/name (default: derived from the origin remote)" jsonschema_extras:"x-layer=project,x-priority=42"`
+	Labels           []string           `yaml:"labels,omitempty" jsonschema:"description=For faq_from_issues: labels an issue or discussion must carry (default: question)" jsonschema_extras:"x-layer=project,x-priority=42"`
+	Limit            int                `yaml:"limit,omitempty" jsonschema:"description=For faq_from_issues: maximum number of issues and of discussions to read (default: 100). For contributors: maximum number of contributors to list (default: all)" jsonschema_extras:"x-layer=project,x-priority=42"`
+	Avatars          bool               `yaml:"avatars,omitempty" jsonschema:"description=For contributors: copy the GitHub avatars of maintainers and contributors into images/avatars next to the page" jsonschema_extras:"x-layer=project,x-priority=42"`
+	Env              map[string]string  `yaml:"env,omitempty" jsonschema:"description=For tutorial: environment variables added to the minimal environment of the verified commands (PATH plus HOME and TMPDIR in the sandbox). $DOCGEN_TUTORIAL_DIR expands to the sandbox directory" jsonschema_extras:"x-layer=project,x-priority=43"`
+	Timeout          string             `yaml:"timeout,omitempty" jsonschema:"description=For tutorial: time limit for each command block as a Go duration (default: 2m)" jsonschema_extras:"x-layer=project,x-priority=43"`
+	DSN              string             `yaml:"dsn,omitempty" jsonschema:"description=For sql_schema: development database to introspect instead of replaying migrations (postgres:// or mysql:// URL or a SQLite file). Environment variables are expanded" jsonschema_extras:"x-layer=project,x-priority=44"`
+	Postprocess      []PostprocessStep  `yaml:"postprocess,omitempty" jsonschema:"description=Post-processors for this section, replacing settings.postprocess (an empty list turns them off)" jsonschema_extras:"x-layer=project,x-priority=40"`
+	Audience         []string           `yaml:"audience,omitempty" jsonschema:"description=Audiences the section is written for (e.g. public or internal or enterprise). Aggregate and watch with --audience publish it only for these. Without it the audience field of the doc's frontmatter applies; a section with neither is for every audience" jsonschema_extras:"x-layer=project,x-priority=33"`
+	Internal         bool               `yaml:"internal,omitempty" jsonschema:"description=Leave this section out of aggregates with --profile public; it is only published on the internal site" jsonschema_extras:"x-layer=project,x-priority=33"`
+	Context          *ContextBudget     `yaml:"context,omitempty" jsonschema:"description=Context budget of this section; its fields override those of settings.context" jsonschema_extras:"x-layer=project,x-priority=42"`
 	AggStripLines    int                `yaml:"agg_strip_lines,omitempty" jsonschema:"description=Number of lines to strip from the top during aggregation" jsonschema_extras:"x-layer=project,x-priority=40"`
 	GenerationConfig `yaml:",inline"`
 }
 
+// PostprocessStep is one post-processor of a section's output. It can be
+// written as just the name or as an object with options.
+type PostprocessStep struct {
+	Name    string   `yaml:"name" jsonschema:"description=Post-processor: trim_whitespace, normalize, wrap, shift_headings or prettier,enum=trim_whitespace,enum=normalize,enum=wrap,enum=shift_headings,enum=prettier"`
+	Width   int      `yaml:"width,omitempty" jsonschema:"description=For wrap: maximum line width (default: 80)"`
+	Levels  int      `yaml:"levels,omitempty" jsonschema:"description=For shift_headings: levels to shift headings by, negative to promote (default: 1)"`
+	Command []string `yaml:"command,omitempty" jsonschema:"description=For prettier: command to run, reading markdown on stdin (default: prettier --parser markdown)"`
+}
+
+// UnmarshalYAML implements custom unmarshaling to support both string and object formats.
+func (p *PostprocessStep) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		p.Name = name
+		return nil
+	}
+	type postprocessStepAlias PostprocessStep
+	var obj postprocessStepAlias
+	if err := unmarshal(&obj); err != nil {
+		return err
+	}
+	*p = PostprocessStep(obj)
+	return nil
+}
+
+// PostprocessSteps returns the post-processors of a section: its own list
+// when it sets one, otherwise the settings' list.
+func (c *DocgenConfig) PostprocessSteps(section SectionConfig) []PostprocessStep {
+	if section.Postprocess != nil {
+		return section.Postprocess
+	}
+	return c.Settings.Postprocess
+}
+
+// PlaceholderMode returns settings.placeholders, defaulting to
+// PlaceholdersPrompt.
+func (c *DocgenConfig) PlaceholderMode() string {
+	if c.Settings.Placeholders == "" {
+		return PlaceholdersPrompt
+	}
+	return c.Settings.Placeholders
+}
+
+// AssetsConfig configures the assets published next to the docs.
+type AssetsConfig struct {
+	Types []AssetType   `yaml:"types,omitempty" jsonschema:"description=Asset directories published in addition to images, videos, asciicasts and downloads (e.g. diagrams, fonts). An entry with the name of a built-in directory replaces its file types"`
+	Video *VideoOptions `yaml:"video,omitempty" jsonschema:"description=Generate poster frames and transcodes for videos/*.mp4 during aggregate and embed videos as <video> elements on the website"`
+}
+
+// ComponentMapping maps the code blocks of a fence language to an Astro
+// component.
+type ComponentMapping struct {
+	Lang      string `yaml:"lang" jsonschema:"description=Fence language of the code blocks to replace (e.g. asciinema, tabs, steps)"`
+	Component string `yaml:"component" jsonschema:"description=Name the component is imported as (e.g. Asciinema)"`
+	Import    string `yaml:"import" jsonschema:"description=Module the component is imported from: a component file (e.g. ~/components/Asciinema.astro) is imported as its default export and any other module (e.g. @astrojs/starlight/components) by name"`
+	Body      string `yaml:"body,omitempty" jsonschema:"description=How the block's content is passed: children (default) as markdown children; props as props read from a JSON or YAML object; code as a code string prop,enum=children,enum=props,enum=code"`
+}
+
+// RateLimit is the quota of an LLM provider. Zero means unlimited.
+type RateLimit struct {
+	RequestsPerMinute int `yaml:"requests_per_minute,omitempty" jsonschema:"description=Maximum requests per minute"`
+	TokensPerMinute   int `yaml:"tokens_per_minute,omitempty" jsonschema:"description=Maximum input tokens per minute (estimated from the prompt and context size)"`
+}
+
+// Context reduction strategies, applied in order to a context over its
+// budget.
+const (
+	ContextDropTests           = "drop_tests"
+	ContextSummarizeLargeFiles = "summarize_large_files"
+	ContextPrioritizeTopic     = "prioritize_topic"
+)
+
+// DefaultContextStrategies are the strategies of a budget that names none.
+var DefaultContextStrategies = []string{ContextDropTests, ContextSummarizeLargeFiles, ContextPrioritizeTopic}
+
+// ContextBudget caps the cx context sent with a section's prompt: to the
+// files most relevant to the section, and to a number of tokens.
+type ContextBudget struct {
+	TopFiles   int      `yaml:"top_files,omitempty" jsonschema:"description=Send only this many files of the context: those most relevant to the section by the words of its name and title and prompt; -1 in a section lifts the selection of settings.context"`
+	MaxTokens  int      `yaml:"max_tokens,omitempty" jsonschema:"description=Maximum tokens of context (estimated at 3 bytes per token); -1 in a section lifts the cap of settings.context"`
+	Strategies []string `yaml:"strategies,omitempty" jsonschema:"description=Reductions applied in order until the context fits (default: drop_tests then summarize_large_files then prioritize_topic),enum=drop_tests,enum=summarize_large_files,enum=prioritize_topic"`
+}
+
+// MergeContextBudget returns the budget of a section: the fields the
+// section sets, over those of the global budget. It is nil when neither
+// selects files or sets a cap.
+func MergeContextBudget(global, section *ContextBudget) *ContextBudget {
+	merged := ContextBudget{}
+	for _, b := range []*ContextBudget{global, section} {
+		if b == nil {
+			continue
+		}
+		if b.TopFiles != 0 {
+			merged.TopFiles = b.TopFiles
+		}
+		if b.MaxTokens != 0 {
+			merged.MaxTokens = b.MaxTokens
+		}
+		if len(b.Strategies) > 0 {
+			merged.Strategies = b.Strategies
+		}
+	}
+	merged.TopFiles = max(merged.TopFiles, 0)
+	merged.MaxTokens = max(merged.MaxTokens, 0)
+	if merged.TopFiles == 0 && merged.MaxTokens == 0 {
+		return nil
+	}
+	if len(merged.Strategies) == 0 {
+		merged.Strategies = DefaultContextStrategies
+	}
+	return &merged
+}
+
+// OllamaConfig configures the local models served by Ollama.
+type OllamaConfig struct {
+	Host          string `yaml:"host,omitempty" jsonschema:"description=Address of the Ollama server (default: $OLLAMA_HOST or http://localhost:11434)"`
+	ContextWindow int    `yaml:"context_window,omitempty" jsonschema:"description=Context window in tokens to run the models with; larger contexts need more memory (default: the model's num_ctx parameter or trained context length)"`
+}
+
+// TutorialConfig controls the execution of tutorial commands. The sandbox
+// they run in is a working directory and a minimal environment, not a
+// security boundary, so running them must be allowed explicitly.
+type TutorialConfig struct {
+	AllowExec bool     `yaml:"allow_exec,omitempty" jsonschema:"description=Run the commands of tutorial sections to verify them. They run as the user running docgen with its access to files and the network; the sandbox directory is no security boundary"`
+	Runner    []string `yaml:"runner,omitempty" jsonschema:"description=Command each tutorial block is run through instead of running it directly, such as a container: the block is appended as sh -e -c <block>. $DOCGEN_TUTORIAL_DIR expands to the sandbox directory (e.g. [docker, run, --rm, -v, $DOCGEN_TUTORIAL_DIR:/work, -w, /work, alpine])"`
+}
+
+// ScrubRule replaces the matches of a regular expression in public builds.
+type ScrubRule struct {
+	Pattern string `yaml:"pattern" jsonschema:"description=Regular expression (RE2 syntax) to replace (e.g. [a-z0-9-]+[.]corp[.]example[.]com)"`
+	Replace string `yaml:"replace,omitempty" jsonschema:"description=Replacement text; $1 expands to the first submatch (default: [redacted])"`
+}
+
+// Build metadata fields of settings.metadata.
+const (
+	MetadataCommit      = "commit"       // Git commit of the package the page was built from
+	MetadataVersion     = "version"      // Version of docgen that built the page
+	MetadataGeneratedAt = "generated_at" // When the page's doc was generated
+	MetadataModel       = "model"        // Model that generated the page's doc
+)
+
+// MetadataFields are the build metadata fields, in the order they are written.
+var MetadataFields = []string{MetadataCommit, MetadataVersion, MetadataGeneratedAt, MetadataModel}
+
+// MetadataConfig configures the build metadata aggregate writes into
+// published pages. Setting it turns the metadata on.
+type MetadataConfig struct {
+	Fields   []string `yaml:"fields,omitempty" jsonschema:"description=Fields to record (default: all),enum=commit,enum=version,enum=generated_at,enum=model"`
+	MetaTags bool     `yaml:"meta_tags,omitempty" jsonschema:"description=With --transform astro also write the fields as docgen:* meta tags in the page head"`
+}
+
+// Includes reports whether field is recorded.
+func (m *MetadataConfig) Includes(field string) bool {
+	return len(m.Fields) == 0 || slices.Contains(m.Fields, field)
+}
+
+// VideoOptions configures video processing during aggregation. Setting it
+// turns the processing on.
+type VideoOptions struct {
+	Poster   *bool    `yaml:"poster,omitempty" jsonschema:"description=Extract a poster frame for each video (default: true)"`
+	PosterAt string   `yaml:"poster_at,omitempty" jsonschema:"description=Timestamp of the poster frame (default: 00:00:01)"`
+	Formats  []string `yaml:"formats,omitempty" jsonschema:"description=Transcodes to generate in addition to the mp4,enum=webm,enum=av1"`
+	FFmpeg   string   `yaml:"ffmpeg,omitempty" jsonschema:"description=ffmpeg binary (default: ffmpeg on PATH)"`
+}
+
+// PosterEnabled reports whether poster frames are extracted.
+func (v *VideoOptions) PosterEnabled() bool {
+	return v.Poster == nil || *v.Poster
+}
+
+// AssetType is a directory of assets published next to the docs. Files are
+// routed to it by extension or MIME type, and relative links into it
+// (./{dir}/...) are rewritten to the published location.
+type AssetType struct {
+	Dir        string   `yaml:"dir" jsonschema:"description=Directory name under the docgen or docs directory (e.g. downloads)"`
+	Extensions []string `yaml:"extensions,omitempty" jsonschema:"description=File extensions routed to this directory (e.g. .pdf)"`
+	MIMETypes  []string `yaml:"mime_types,omitempty" jsonschema:"description=MIME types routed to this directory; a trailing /* matches a whole family (e.g. font/*)"`
+}
+
+// DefaultAssetTypes returns the built-in asset directories.
+func DefaultAssetTypes() []AssetType {
+	return []AssetType{
+		{Dir: "images", Extensions: []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp"}, MIMETypes: []string{"image/*"}},
+		{Dir: "asciicasts", Extensions: []string{".cast"}},
+		{Dir: "videos", Extensions: []string{".mp4", ".webm", ".mov"}, MIMETypes: []string{"video/*"}},
+		{Dir: "downloads", Extensions: []string{".zip", ".tgz", ".gz", ".tar", ".pdf"}, MIMETypes: []string{"application/zip", "application/gzip", "application/x-tar", "application/pdf"}},
+	}
+}
+
+// AssetTypes returns the built-in asset directories followed by the ones
+// from settings.assets.types. A configured directory with a built-in name
+// replaces the built-in one.
+func (c *DocgenConfig) AssetTypes() []AssetType {
+	types := DefaultAssetTypes()
+	if c == nil || c.Settings.Assets == nil {
+		return types
+	}
+	for _, t := range c.Settings.Assets.Types {
+		if t.Dir == "" {
+			continue
+		}
+		replaced := false
+		for i := range types {
+			if types[i].Dir == t.Dir {
+				types[i], replaced = t, true
+			}
+		}
+		if !replaced {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// AssetDirs returns the directory names of types.
+func AssetDirs(types []AssetType) []string {
+	dirs := make([]string, 0, len(types))
+	for _, t := range types {
+		dirs = append(dirs, t.Dir)
+	}
+	return dirs
+}
+
+// ComponentMappings returns defaults, the component mappings of the
+// website, followed by the ones from settings.components. A configured
+// mapping for the language of a default replaces it.
+func (c *DocgenConfig) ComponentMappings(defaults []ComponentMapping) []ComponentMapping {
+	mappings := append([]ComponentMapping(nil), defaults...)
+	if c == nil {
+		return mappings
+	}
+	for _, m := range c.Settings.Components {
+		if m.Lang == "" || m.Component == "" {
+			continue
+		}
+		replaced := false
+		for i := range mappings {
+			if mappings[i].Lang == m.Lang {
+				mappings[i], replaced = m, true
+			}
+		}
+		if !replaced {
+			mappings = append(mappings, m)
+		}
+	}
+	return mappings
+}
+
+// Components returns the transformer components of mappings.
+func Components(mappings []ComponentMapping) []transformer.Component {
+	components := make([]transformer.Component, 0, len(mappings))
+	for _, m := range mappings {
+		components = append(components, transformer.Component{Lang: m.Lang, Name: m.Component, Import: m.Import, Body: m.Body})
+	}
+	return components
+}
+
+// AssetTypeFor returns the directory a file is routed to, or "" when it is
+// not an asset. Extensions are matched before MIME types, so an explicit
+// extension wins over a broader MIME family.
+func AssetTypeFor(types []AssetType, path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return ""
+	}
+	for _, t := range types {
+		for _, e := range t.Extensions {
+			if strings.ToLower(e) == ext || "."+strings.ToLower(e) == ext {
+				return t.Dir
+			}
+		}
+	}
+	mediaType, _, err := mime.ParseMediaType(mime.TypeByExtension(ext))
+	if err != nil {
+		return ""
+	}
+	for _, t := range types {
+		for _, m := range t.MIMETypes {
+			m = strings.ToLower(m)
+			if m == mediaType || (strings.HasSuffix(m, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(m, "*"))) {
+				return t.Dir
+			}
+		}
+	}
+	return ""
+}
+
 // TUIEntry represents a TUI configuration for tui_keymaps generation.
 // It can be unmarshaled from either a string (just the name) or an object with name and command.
 type TUIEntry struct {
@@ -161,15 +529,146 @@ func (s *SectionConfig) GetStatus() string {
 	return s.Status
 }
 
+// ErrInvalidMode is returned for a build mode other than dev or prod.
+var ErrInvalidMode = errors.New("invalid mode")
+
+// CheckMode returns an error wrapping ErrInvalidMode unless mode is ModeDev
+// or ModeProd.
+func CheckMode(mode string) error {
+	if mode != ModeDev && mode != ModeProd {
+		return fmt.Errorf("%w '%s': must be '%s' or '%s'", ErrInvalidMode, mode, ModeDev, ModeProd)
+	}
+	return nil
+}
+
+// ErrInvalidProfile is returned for an output profile other than internal or
+// public.
+var ErrInvalidProfile = errors.New("invalid profile")
+
+// CheckProfile returns an error wrapping ErrInvalidProfile unless profile is
+// ProfileInternal or ProfilePublic.
+func CheckProfile(profile string) error {
+	if profile != ProfileInternal && profile != ProfilePublic {
+		return fmt.Errorf("%w '%s': must be '%s' or '%s'", ErrInvalidProfile, profile, ProfileInternal, ProfilePublic)
+	}
+	return nil
+}
+
+// InProfile reports whether a section is published with an output profile:
+// internal sections only are with ProfileInternal.
+func (s *SectionConfig) InProfile(profile string) bool {
+	return !s.Internal || profile != ProfilePublic
+}
+
+// ForAudience reports whether content written for audience is published in
+// a build for the audiences of filter: content without an audience, and
+// every content in a build without a filter, always is.
+func ForAudience(audience, filter []string) bool {
+	if len(audience) == 0 || len(filter) == 0 {
+		return true
+	}
+	for _, a := range audience {
+		for _, f := range filter {
+			if strings.EqualFold(a, f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Published reports whether content with the given publication status is
+// published in a build mode: draft content never is, dev content only in
+// dev mode, production content always. Aggregate and watch filter sections,
+// concepts and sidebar packages with it, so both publish the same set.
+func Published(status, mode string) bool {
+	switch status {
+	case StatusDraft:
+		return false
+	case StatusDev:
+		return mode != ModeProd
+	}
+	return true
+}
+
+// SourceList is a `source` value written either as a single string or as a
+// list of strings. Only nb_concept sections accept more than one entry.
+type SourceList []string
+
+// UnmarshalYAML accepts a scalar or a sequence.
+func (l *SourceList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value == "" {
+			*l = nil
+			return nil
+		}
+		*l = SourceList{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return fmt.Errorf("source must be a string or a list of strings: %w", err)
+	}
+	*l = list
+	return nil
+}
+
+// MarshalYAML writes a single entry back as a plain string.
+func (l SourceList) MarshalYAML() (interface{}, error) {
+	if len(l) == 1 {
+		return l[0], nil
+	}
+	return []string(l), nil
+}
+
+// String returns the single source value, or the entries joined by ", ".
+func (l SourceList) String() string {
+	return strings.Join(l, ", ")
+}
+
+// SchemaInputs returns one schema input per entry, for the schema types,
+// which document each source file in turn.
+func (l SourceList) SchemaInputs() []SchemaInput {
+	inputs := make([]SchemaInput, 0, len(l))
+	for _, path := range l {
+		inputs = append(inputs, SchemaInput{Path: path})
+	}
+	return inputs
+}
+
+// JSONSchema describes SourceList as a string or a list of strings.
+func (SourceList) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+}
+
+// UsesLLM reports whether the section should be generated by the LLM. Only
+// an explicit `llm: false` disables it.
+func (s *SectionConfig) UsesLLM() bool {
+	return s.LLM == nil || *s.LLM
+}
+
 // ReadmeConfig defines the settings for synchronizing the README.md.
 type ReadmeConfig struct {
-	Template      string      `yaml:"template" jsonschema:"description=Path to the README template, relative to package root" jsonschema_extras:"x-layer=project,x-priority=40"`
-	Output        string      `yaml:"output" jsonschema:"description=Path to the output README file, relative to package root" jsonschema_extras:"x-layer=project,x-priority=41"`
-	SourceSection string      `yaml:"source_section" jsonschema:"description=The name of the section to inject into the template" jsonschema_extras:"x-layer=project,x-priority=42"`
-	StripLines    int         `yaml:"strip_lines,omitempty" jsonschema:"description=Number of lines to strip from the top of source file (default: 0)" jsonschema_extras:"x-layer=project,x-priority=45"`
-	GenerateTOC   bool        `yaml:"generate_toc,omitempty" jsonschema:"description=Whether to generate a table of contents from sections" jsonschema_extras:"x-layer=project,x-priority=43"`
-	BaseURL       string      `yaml:"base_url,omitempty" jsonschema:"description=Base URL for converting root-relative paths to absolute URLs" jsonschema_extras:"x-layer=project,x-priority=44"`
-	Logo          *LogoConfig `yaml:"logo,omitempty" jsonschema:"description=Optional logo generation configuration" jsonschema_extras:"x-layer=project,x-priority=46"`
+	Template      string        `yaml:"template" jsonschema:"description=Path to the README template, relative to package root" jsonschema_extras:"x-layer=project,x-priority=40"`
+	Output        string        `yaml:"output" jsonschema:"description=Path to the output README file, relative to package root" jsonschema_extras:"x-layer=project,x-priority=41"`
+	SourceSection string        `yaml:"source_section" jsonschema:"description=The name of the section to inject into the template" jsonschema_extras:"x-layer=project,x-priority=42"`
+	StripLines    int           `yaml:"strip_lines,omitempty" jsonschema:"description=Number of lines to strip from the top of source file (default: 0)" jsonschema_extras:"x-layer=project,x-priority=45"`
+	GenerateTOC   bool          `yaml:"generate_toc,omitempty" jsonschema:"description=Whether to generate a table of contents from sections" jsonschema_extras:"x-layer=project,x-priority=43"`
+	BaseURL       string        `yaml:"base_url,omitempty" jsonschema:"description=Base URL for converting root-relative paths to absolute URLs" jsonschema_extras:"x-layer=project,x-priority=44"`
+	Logo          *LogoConfig   `yaml:"logo,omitempty" jsonschema:"description=Optional logo generation configuration" jsonschema_extras:"x-layer=project,x-priority=46"`
+	Badges        []BadgeConfig `yaml:"badges,omitempty" jsonschema:"description=Badges exposed to the README template as .Badges" jsonschema_extras:"x-layer=project,x-priority=47"`
+}
+
+// BadgeConfig defines a badge made available to README templates.
+type BadgeConfig struct {
+	Label string `yaml:"label" jsonschema:"description=Alt text for the badge image" jsonschema_extras:"x-layer=project,x-priority=47"`
+	Image string `yaml:"image" jsonschema:"description=URL of the badge image" jsonschema_extras:"x-layer=project,x-priority=48"`
+	Link  string `yaml:"link,omitempty" jsonschema:"description=Optional URL the badge links to" jsonschema_extras:"x-layer=project,x-priority=49"`
 }
 
 // LogoConfig defines settings for generating a combined logo+text SVG.
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	coreConfig "github.com/grovetools/core/config"
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+// IncludesDirName is the directory of a docgen directory holding the files
+// docs include with {{include "..."}}.
+const IncludesDirName = "includes"
+
+// notebookDocgenDir returns the notebook docgen directory of the package at
+// repoDir, {notebook}/workspaces/{name}/docgen, or "" without a notebook.
+func notebookDocgenDir(repoDir string) string {
+	node, err := workspace.GetProjectByPath(repoDir)
+	if err != nil {
+		return ""
+	}
+	cfg, err := coreConfig.LoadDefault()
+	if err != nil {
+		return ""
+	}
+	docgenDir, err := workspace.NewNotebookLocator(cfg).GetDocgenDir(node)
+	if err != nil {
+		return ""
+	}
+	return docgenDir
+}
+
+// IncludesDir returns the includes directory of the package at repoDir: in
+// its notebook docgen directory when it exists, else docs/includes of the
+// repository.
+func IncludesDir(repoDir string) string {
+	if docgenDir := notebookDocgenDir(repoDir); docgenDir != "" {
+		dir := filepath.Join(docgenDir, IncludesDirName)
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	return filepath.Join(repoDir, "docs", IncludesDirName)
+}
+
+// SharedIncludesDir returns the includes directory every package of the
+// notebook of the package at repoDir can include from,
+// {notebook}/docgen/includes, or "" without a notebook.
+func SharedIncludesDir(repoDir string) string {
+	docgenDir := notebookDocgenDir(repoDir)
+	if docgenDir == "" {
+		return ""
+	}
+	// docgenDir is {notebook}/workspaces/{name}/docgen
+	root := filepath.Dir(filepath.Dir(filepath.Dir(docgenDir)))
+	return filepath.Join(root, "docgen", IncludesDirName)
+}
+
+// NewIncludeResolver returns the resolver of the include directives in the
+// docs of the package at repoDir (see transformer.ExpandIncludes). A path is
+// looked up in the includes directory of the package, or of the workspace a
+// "pkg:" prefix names, and then in the notebook's shared includes directory.
+func NewIncludeResolver(repoDir string, logger *logrus.Logger) func(pkg, path string) ([]byte, error) {
+	shared := SharedIncludesDir(repoDir)
+	dirs := map[string]string{"": IncludesDir(repoDir)}
+	return func(pkg, path string) ([]byte, error) {
+		rel := filepath.Clean(filepath.FromSlash(path))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("path must be relative to the includes directory")
+		}
+		dir, ok := dirs[pkg]
+		if !ok {
+			projects, err := workspace.GetProjects(logger)
+			if err != nil {
+				return nil, fmt.Errorf("could not discover workspaces: %w", err)
+			}
+			for _, p := range projects {
+				if p.Name == pkg {
+					dir = IncludesDir(p.Path)
+					break
+				}
+			}
+			if dir == "" {
+				return nil, fmt.Errorf("workspace %q not found", pkg)
+			}
+			dirs[pkg] = dir
+		}
+		searched := []string{dir}
+		if shared != "" {
+			searched = append(searched, shared)
+		}
+		for _, d := range searched {
+			data, err := os.ReadFile(filepath.Join(d, rel)) //nolint:gosec // path checked to be under the includes directory
+			if err == nil {
+				return data, nil
+			}
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+		return nil, fmt.Errorf("not found in %s", strings.Join(searched, " or "))
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NextStatus returns the status a section at status is promoted to: draft to
+// dev and dev to production. Production is already the last status.
+func NextStatus(status string) (string, bool) {
+	switch status {
+	case "", StatusDraft:
+		return StatusDev, true
+	case StatusDev:
+		return StatusProduction, true
+	}
+	return status, false
+}
+
+// SetSectionStatus sets the status of the section named name in the docgen
+// config at configPath. Only the status line changes, or is added below the
+// section's name, so the file's comments and layout are kept.
+func SetSectionStatus(configPath, name, status string) error {
+	if status != StatusDraft && status != StatusDev && status != StatusProduction {
+		return fmt.Errorf("invalid status %q: must be '%s', '%s' or '%s'", status, StatusDraft, StatusDev, StatusProduction)
+	}
+	data, err := os.ReadFile(configPath) //nolint:gosec // path from trusted config discovery
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+
+	section := findSection(&doc, name)
+	if section == nil {
+		return fmt.Errorf("section %q not found in %s", name, configPath)
+	}
+	if section.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("section %q in %s is written inline: set its status by hand", name, configPath)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if value := mappingValue(section, "status"); value != nil {
+		line := lines[value.Line-1]
+		start := value.Column - 1
+		end := start + len(rawScalar(line[start:]))
+		lines[value.Line-1] = line[:start] + status + line[end:]
+	} else {
+		key := mappingKey(section, "name")
+		indent := strings.Repeat(" ", key.Column-1)
+		nameLine := lines[key.Line-1]
+		if !strings.HasSuffix(nameLine, "\n") {
+			nameLine += "\n"
+			lines[key.Line-1] = nameLine
+		}
+		lines = append(lines[:key.Line], append([]string{indent + "status: " + status + "\n"}, lines[key.Line:]...)...)
+	}
+
+	var out bytes.Buffer
+	for _, l := range lines {
+		out.WriteString(l)
+	}
+	return os.WriteFile(configPath, out.Bytes(), 0o644) //nolint:gosec // config files are not secret
+}
+
+// findSection returns the mapping of the section named name in a parsed
+// docgen config, or nil.
+func findSection(doc *yaml.Node, name string) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	sections := mappingValue(doc.Content[0], "sections")
+	if sections == nil || sections.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, s := range sections.Content {
+		if s.Kind != yaml.MappingNode {
+			continue
+		}
+		if v := mappingValue(s, "name"); v != nil && v.Value == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// mappingKey returns the key node of key in mapping m, or nil.
+func mappingKey(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i]
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value node of key in mapping m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// rawScalar returns the source text of the one-line scalar s starts with:
+// a quoted string, or the text up to a comment or the end of the line.
+func rawScalar(s string) string {
+	s = strings.TrimRight(s, "\r\n")
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		if end := strings.IndexByte(s[1:], s[0]); end >= 0 {
+			return s[:end+2]
+		}
+		return s
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, " \t")
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetSectionStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	src := `# Docs for grove-flow
+sections:
+  - name: overview
+    title: Overview
+    status: "draft" # promote when reviewed
+    output: overview.md
+  - name: usage
+    output: usage.md
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetSectionStatus(path, "overview", StatusDev); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSectionStatus(path, "usage", StatusProduction); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Docs for grove-flow
+sections:
+  - name: overview
+    title: Overview
+    status: dev # promote when reviewed
+    output: overview.md
+  - name: usage
+    status: production
+    output: usage.md
+`
+	if string(got) != want {
+		t.Fatalf("config =\n%s\nwant\n%s", got, want)
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := cfg.Sections[0].GetStatus(); s != StatusDev {
+		t.Errorf("overview status = %q; want %q", s, StatusDev)
+	}
+
+	if err := SetSectionStatus(path, "missing", StatusDev); err == nil {
+		t.Error("missing section: want error")
+	}
+	if err := SetSectionStatus(path, "usage", "published"); err == nil {
+		t.Error("invalid status: want error")
+	}
+}
+
+func TestNextStatus(t *testing.T) {
+	for status, want := range map[string]string{"": StatusDev, StatusDraft: StatusDev, StatusDev: StatusProduction} {
+		if got, ok := NextStatus(status); !ok || got != want {
+			t.Errorf("NextStatus(%q) = %q, %v; want %q", status, got, ok, want)
+		}
+	}
+	if _, ok := NextStatus(StatusProduction); ok {
+		t.Error("NextStatus(production): want no promotion")
+	}
+}
@@ -0,0 +1,267 @@
+// Package contributors builds a package's maintainers and contributors page
+// from its git history (git shortlog, which honors .mailmap) and CODEOWNERS,
+// optionally with the GitHub avatars of the people on it.
+package contributors
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/github"
+)
+
+// AvatarDir is where the page expects avatars, relative to the page.
+const AvatarDir = "images/avatars"
+
+// avatarSize is the size in pixels of the avatars fetched.
+const avatarSize = 64
+
+// codeOwnersPaths are where GitHub looks for CODEOWNERS, in order.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+var (
+	shortlogRe = regexp.MustCompile(`^\s*(\d+)\t(.+?)\s*<([^>]*)>\s*$`)
+	noreplyRe  = regexp.MustCompile(`^(?:\d+\+)?([A-Za-z0-9-]+)@users\.noreply\.github\.com$`)
+)
+
+// Contributor is an author of commits to the package.
+type Contributor struct {
+	Name    string
+	Email   string
+	Login   string // GitHub login, known from a noreply address
+	Commits int
+}
+
+// Maintainer is a code owner and the paths they own.
+type Maintainer struct {
+	Owner    string // @login, @org/team or an email address
+	Patterns []string
+}
+
+// Login returns the maintainer's GitHub login, or "" for teams and email
+// addresses.
+func (m Maintainer) Login() string {
+	if !strings.HasPrefix(m.Owner, "@") || strings.Contains(m.Owner, "/") {
+		return ""
+	}
+	return strings.TrimPrefix(m.Owner, "@")
+}
+
+// Options configures Build.
+type Options struct {
+	Title   string // Default: Contributors
+	Limit   int    // Maximum number of contributors listed (default: all)
+	Avatars bool   // Fetch the GitHub avatars of the people listed
+}
+
+// Page is a generated contributors page.
+type Page struct {
+	Markdown string
+	// Avatars are the PNG avatars the page shows, keyed by file name under
+	// AvatarDir.
+	Avatars map[string][]byte
+	// Failed lists the logins whose avatars could not be fetched.
+	Failed []string
+}
+
+// Contributors returns the authors of commits touching dir, most commits
+// first. Bots are left out.
+func Contributors(ctx context.Context, dir string) ([]Contributor, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "shortlog", "-sne", "HEAD", "--", ".").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git shortlog failed in %s: %w", dir, err)
+	}
+	var contributors []Contributor
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		m := shortlogRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		c := Contributor{Name: m[2], Email: m[3]}
+		c.Commits, _ = strconv.Atoi(m[1])
+		if strings.HasSuffix(c.Name, "[bot]") || strings.HasSuffix(c.Email, "[bot]@users.noreply.github.com") {
+			continue
+		}
+		if lm := noreplyRe.FindStringSubmatch(c.Email); lm != nil {
+			c.Login = lm[1]
+		}
+		contributors = append(contributors, c)
+	}
+	sort.SliceStable(contributors, func(i, j int) bool { return contributors[i].Commits > contributors[j].Commits })
+	return contributors, nil
+}
+
+// Maintainers returns the owners named in the CODEOWNERS file found in dir
+// or, failing that, at the root of its git repository, in the order they
+// first appear. It returns none when there is no CODEOWNERS file.
+func Maintainers(ctx context.Context, dir string) ([]Maintainer, error) {
+	roots := []string{dir}
+	if out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--show-toplevel").Output(); err == nil {
+		if top := strings.TrimSpace(string(out)); top != "" && top != dir {
+			roots = append(roots, top)
+		}
+	}
+	for _, root := range roots {
+		for _, p := range codeOwnersPaths {
+			data, err := os.ReadFile(filepath.Join(root, p)) //nolint:gosec // CODEOWNERS in the repository
+			if err == nil {
+				return ParseCodeOwners(string(data)), nil
+			}
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners returns the owners of a CODEOWNERS file with the patterns
+// each owns, in the order they first appear.
+func ParseCodeOwners(content string) []Maintainer {
+	var maintainers []Maintainer
+	index := make(map[string]int)
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, owner := range fields[1:] {
+			i, ok := index[strings.ToLower(owner)]
+			if !ok {
+				i = len(maintainers)
+				index[strings.ToLower(owner)] = i
+				maintainers = append(maintainers, Maintainer{Owner: owner})
+			}
+			maintainers[i].Patterns = append(maintainers[i].Patterns, fields[0])
+		}
+	}
+	return maintainers
+}
+
+// Build generates the contributors page of the package in dir. It fails
+// when the package has neither git history nor a CODEOWNERS file.
+func Build(ctx context.Context, dir string, opts Options) (*Page, error) {
+	maintainers, err := Maintainers(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	contributors, err := Contributors(ctx, dir)
+	if err != nil && len(maintainers) == 0 {
+		return nil, err
+	}
+	if len(contributors) == 0 && len(maintainers) == 0 {
+		return nil, fmt.Errorf("no contributors or code owners found in %s", dir)
+	}
+	if opts.Limit > 0 && len(contributors) > opts.Limit {
+		contributors = contributors[:opts.Limit]
+	}
+
+	page := &Page{Avatars: make(map[string][]byte)}
+	avatar := func(login string) string {
+		if !opts.Avatars || login == "" {
+			return ""
+		}
+		file := strings.ToLower(login) + ".png"
+		if _, ok := page.Avatars[file]; !ok {
+			data, err := github.NewClient("").Avatar(login, avatarSize)
+			if err != nil {
+				page.Failed = append(page.Failed, login)
+				page.Avatars[file] = nil
+				return ""
+			}
+			page.Avatars[file] = data
+		}
+		if page.Avatars[file] == nil {
+			return ""
+		}
+		return fmt.Sprintf(`<img src="./%s/%s" alt="" width="32" height="32" /> `, AvatarDir, file)
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = "Contributors"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", title)
+
+	if len(maintainers) > 0 {
+		sb.WriteString("\n## Maintainers\n\n")
+		sb.WriteString("Reviews of changes are requested from the code owners of the paths they touch.\n\n")
+		sb.WriteString("| Maintainer | Owns |\n")
+		sb.WriteString("| :--- | :--- |\n")
+		for _, m := range maintainers {
+			patterns := make([]string, len(m.Patterns))
+			for i, p := range m.Patterns {
+				patterns[i] = "`" + p + "`"
+			}
+			fmt.Fprintf(&sb, "| %s%s | %s |\n", avatar(m.Login()), ownerLink(m.Owner), strings.Join(patterns, ", "))
+		}
+	}
+
+	if len(contributors) > 0 {
+		sb.WriteString("\n## Contributors\n\n")
+		sb.WriteString("Everyone who has committed to this package, by number of commits.\n\n")
+		sb.WriteString("| Contributor | Commits |\n")
+		sb.WriteString("| :--- | ---: |\n")
+		for _, c := range contributors {
+			name := escapeCell(c.Name)
+			if c.Login != "" {
+				name = fmt.Sprintf("[%s](https://github.com/%s)", name, c.Login)
+			}
+			fmt.Fprintf(&sb, "| %s%s | %d |\n", avatar(c.Login), name, c.Commits)
+		}
+	}
+
+	for file, data := range page.Avatars {
+		if data == nil {
+			delete(page.Avatars, file)
+		}
+	}
+	page.Markdown = sb.String()
+	return page, nil
+}
+
+// WriteAvatars writes the page's avatars under AvatarDir in dir.
+func (p *Page) WriteAvatars(dir string) error {
+	if len(p.Avatars) == 0 {
+		return nil
+	}
+	avatarDir := filepath.Join(dir, AvatarDir)
+	if err := os.MkdirAll(avatarDir, 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create avatar directory: %w", err)
+	}
+	for file, data := range p.Avatars {
+		if err := os.WriteFile(filepath.Join(avatarDir, file), data, 0o644); err != nil { //nolint:gosec // generated asset
+			return fmt.Errorf("failed to write avatar %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// ownerLink links a CODEOWNERS owner to its GitHub profile or team.
+func ownerLink(owner string) string {
+	if !strings.HasPrefix(owner, "@") {
+		return escapeCell(owner)
+	}
+	name := strings.TrimPrefix(owner, "@")
+	if org, team, ok := strings.Cut(name, "/"); ok {
+		return fmt.Sprintf("[%s](https://github.com/orgs/%s/teams/%s)", owner, org, team)
+	}
+	return fmt.Sprintf("[%s](https://github.com/%s)", owner, name)
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
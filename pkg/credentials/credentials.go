@@ -0,0 +1,234 @@
+// Package credentials keeps the API keys of LLM providers out of the
+// environment: in the OS keychain (the macOS login keychain through
+// security, or the Secret Service through secret-tool) or in an
+// age-encrypted file. Keys are stored per profile, so each ecosystem can use
+// its own keys by naming a profile in settings.credentials.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Service is the name keys are stored under in the keychain.
+const Service = "docgen"
+
+// Backends of the store.
+const (
+	BackendAuto     = "auto"     // The keychain when there is one, else the encrypted file
+	BackendKeychain = "keychain" // macOS keychain or Secret Service
+	BackendFile     = "file"     // age-encrypted file in Dir
+)
+
+// DefaultProfile is the profile keys are stored in without --profile, and
+// the one every profile falls back to.
+const DefaultProfile = "default"
+
+var (
+	// ErrNotFound is returned for a key the store does not have.
+	ErrNotFound = errors.New("credential not found")
+	// ErrNoBackend is returned when neither a keychain nor age is available.
+	ErrNoBackend = errors.New("no credential store available: install age, or secret-tool on Linux")
+)
+
+// Provider is an LLM provider and the environment variables its clients
+// read the API key from, the first being the one docgen sets.
+type Provider struct {
+	Name     string
+	EnvVars  []string
+	Prefixes []string // Model name prefixes served by the provider
+}
+
+// Providers are the providers keys can be stored for.
+var Providers = []Provider{
+	{Name: "anthropic", EnvVars: []string{"ANTHROPIC_API_KEY"}, Prefixes: []string{"claude"}},
+	{Name: "gemini", EnvVars: []string{"GEMINI_API_KEY", "GOOGLE_API_KEY"}, Prefixes: []string{"gemini"}},
+	{Name: "openai", EnvVars: []string{"OPENAI_API_KEY"}, Prefixes: []string{"gpt", "o1", "o3", "o4"}},
+}
+
+// LookupProvider returns the provider called name.
+func LookupProvider(name string) (Provider, error) {
+	names := make([]string, len(Providers))
+	for i, p := range Providers {
+		if p.Name == strings.ToLower(name) {
+			return p, nil
+		}
+		names[i] = p.Name
+	}
+	return Provider{}, fmt.Errorf("unknown provider %q (expected one of: %s)", name, strings.Join(names, ", "))
+}
+
+// ProviderForModel returns the provider serving model.
+func ProviderForModel(model string) (Provider, bool) {
+	for _, p := range Providers {
+		for _, prefix := range p.Prefixes {
+			if strings.HasPrefix(model, prefix) {
+				return p, true
+			}
+		}
+	}
+	return Provider{}, false
+}
+
+// EnvKey returns the first of the provider's environment variables that is
+// set, or "".
+func (p Provider) EnvKey() string {
+	for _, v := range p.EnvVars {
+		if os.Getenv(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Store keeps API keys under account names (see Account).
+type Store interface {
+	// Name describes where the keys are kept.
+	Name() string
+	// Get returns the key of account, or ErrNotFound.
+	Get(account string) (string, error)
+	Set(account, key string) error
+	// Delete removes the key of account, or returns ErrNotFound.
+	Delete(account string) error
+}
+
+// Account returns the name a provider's key is stored under in profile.
+func Account(profile, provider string) string {
+	if profile == "" || profile == DefaultProfile {
+		return provider
+	}
+	return profile + "/" + provider
+}
+
+// Dir returns the directory of the encrypted credentials file:
+// $DOCGEN_CREDENTIALS_DIR, or docgen in the user's config directory.
+func Dir() (string, error) {
+	if dir := os.Getenv("DOCGEN_CREDENTIALS_DIR"); dir != "" {
+		return dir, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no config directory for credentials: %w", err)
+	}
+	return filepath.Join(configDir, "docgen"), nil
+}
+
+// Open returns the store of backend; BackendAuto (or "") picks the keychain
+// when one is available and the encrypted file otherwise. $DOCGEN_CREDENTIALS
+// overrides BackendAuto.
+func Open(backend string) (Store, error) {
+	if backend == "" || backend == BackendAuto {
+		backend = os.Getenv("DOCGEN_CREDENTIALS")
+	}
+	switch backend {
+	case "", BackendAuto:
+		if s, ok := newKeychain(); ok {
+			return s, nil
+		}
+		if s, ok := newFileStore(); ok {
+			return s, nil
+		}
+		return nil, ErrNoBackend
+	case BackendKeychain:
+		if s, ok := newKeychain(); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("no keychain available: docgen uses security on macOS and secret-tool on Linux")
+	case BackendFile:
+		if s, ok := newFileStore(); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("the encrypted credentials file needs age and age-keygen on PATH")
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (expected auto, keychain or file)", backend)
+	}
+}
+
+// Lookup returns the stored key of provider in profile, falling back to the
+// default profile. It returns ErrNotFound when neither has one.
+func Lookup(s Store, profile, provider string) (string, error) {
+	key, err := s.Get(Account(profile, provider))
+	if errors.Is(err, ErrNotFound) && Account(profile, provider) != provider {
+		key, err = s.Get(provider)
+	}
+	return key, err
+}
+
+var (
+	envMu    sync.Mutex
+	envCache = make(map[string]envResult)
+)
+
+type envResult struct {
+	env []string
+	err error
+}
+
+// Env returns the NAME=key environment entries of the keys stored for
+// profile (see Lookup) of the providers whose environment variables are all
+// unset: a key in the environment takes precedence over a stored one. The
+// store is read once per profile; no store means no entries.
+func Env(profile string) ([]string, error) {
+	envMu.Lock()
+	defer envMu.Unlock()
+	r, ok := envCache[profile]
+	if !ok {
+		r.env, r.err = storedEnv(profile)
+		envCache[profile] = r
+	}
+	return r.env, r.err
+}
+
+func storedEnv(profile string) ([]string, error) {
+	var env []string
+	s, err := Open("")
+	if errors.Is(err, ErrNoBackend) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range Providers {
+		if p.EnvKey() != "" {
+			continue
+		}
+		key, err := Lookup(s, profile, p.Name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s key from %s: %w", p.Name, s.Name(), err)
+		}
+		env = append(env, p.EnvVars[0]+"="+key)
+	}
+	return env, nil
+}
+
+// Key returns the key of provider for profile: from the provider's
+// environment variables when one is set, otherwise the key stored for
+// profile (see Env). It is empty when there is neither. Clients in this
+// process are given the key directly, so generations of packages with
+// different profiles never share one through the environment.
+func Key(profile, provider string) (string, error) {
+	p, err := LookupProvider(provider)
+	if err != nil {
+		return "", err
+	}
+	if v := p.EnvKey(); v != "" {
+		return os.Getenv(v), nil
+	}
+	env, err := Env(profile)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range env {
+		if name, key, _ := strings.Cut(e, "="); name == p.EnvVars[0] {
+			return key, nil
+		}
+	}
+	return "", nil
+}
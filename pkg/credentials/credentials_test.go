@@ -0,0 +1,111 @@
+package credentials
+
+import (
+	"errors"
+	"testing"
+)
+
+// mapStore is a Store holding its keys in memory.
+type mapStore map[string]string
+
+func (s mapStore) Name() string { return "memory" }
+
+func (s mapStore) Get(account string) (string, error) {
+	key, ok := s[account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func (s mapStore) Set(account, key string) error {
+	s[account] = key
+	return nil
+}
+
+func (s mapStore) Delete(account string) error {
+	if _, ok := s[account]; !ok {
+		return ErrNotFound
+	}
+	delete(s, account)
+	return nil
+}
+
+func TestLookup(t *testing.T) {
+	store := mapStore{
+		"anthropic":      "default-anthropic",
+		"gemini":         "default-gemini",
+		"work/anthropic": "work-anthropic",
+	}
+	cases := []struct {
+		profile, provider string
+		want              string
+		wantErr           error
+	}{
+		{"", "anthropic", "default-anthropic", nil},
+		{DefaultProfile, "gemini", "default-gemini", nil},
+		{"work", "anthropic", "work-anthropic", nil},
+		{"work", "gemini", "default-gemini", nil},
+		{"work", "openai", "", ErrNotFound},
+		{"", "openai", "", ErrNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.profile+"/"+c.provider, func(t *testing.T) {
+			got, err := Lookup(store, c.profile, c.provider)
+			if got != c.want || !errors.Is(err, c.wantErr) {
+				t.Errorf("Lookup = %q, %v; want %q, %v", got, err, c.want, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestProviderForModel(t *testing.T) {
+	cases := map[string]string{
+		"claude-sonnet-4-5":    "anthropic",
+		"gemini-3-pro-preview": "gemini",
+		"gpt-5":                "openai",
+		"o3-mini":              "openai",
+		"llama3.1:8b":          "",
+	}
+	for model, want := range cases {
+		p, ok := ProviderForModel(model)
+		if p.Name != want || ok != (want != "") {
+			t.Errorf("ProviderForModel(%q) = %q, %v; want %q", model, p.Name, ok, want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	for _, p := range Providers {
+		for _, v := range p.EnvVars {
+			t.Setenv(v, "")
+		}
+	}
+	// Keys stored for the profile, as Env would read them from the store
+	envMu.Lock()
+	envCache["test-key"] = envResult{env: []string{"ANTHROPIC_API_KEY=stored-anthropic", "GEMINI_API_KEY=stored-gemini"}}
+	envMu.Unlock()
+	t.Cleanup(func() {
+		envMu.Lock()
+		delete(envCache, "test-key")
+		envMu.Unlock()
+	})
+	t.Setenv("GOOGLE_API_KEY", "env-google")
+
+	cases := []struct {
+		provider, want string
+	}{
+		{"anthropic", "stored-anthropic"},
+		{"gemini", "env-google"}, // Any of the provider's variables takes precedence
+		{"openai", ""},
+	}
+	for _, c := range cases {
+		got, err := Key("test-key", c.provider)
+		if err != nil || got != c.want {
+			t.Errorf("Key(%s) = %q, %v; want %q", c.provider, got, err, c.want)
+		}
+	}
+	if _, err := Key("test-key", "mistral"); err == nil {
+		t.Error("Key of an unknown provider did not fail")
+	}
+}
@@ -0,0 +1,129 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// File names of the encrypted store in Dir.
+const (
+	credentialsFile = "credentials.age"
+	identityFile    = "identity.txt"
+)
+
+// fileStore keeps keys in a JSON object encrypted with age to an identity
+// generated on first use. The identity is readable only by the user, so the
+// file is safe to back up or sync without it.
+type fileStore struct {
+	dir string
+}
+
+func newFileStore() (*fileStore, bool) {
+	for _, tool := range []string{"age", "age-keygen"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return nil, false
+		}
+	}
+	dir, err := Dir()
+	if err != nil {
+		return nil, false
+	}
+	return &fileStore{dir: dir}, true
+}
+
+func (f *fileStore) Name() string {
+	return filepath.Join(f.dir, credentialsFile)
+}
+
+func (f *fileStore) Get(account string) (string, error) {
+	keys, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	key, ok := keys[account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func (f *fileStore) Set(account, key string) error {
+	keys, err := f.read()
+	if err != nil {
+		return err
+	}
+	keys[account] = key
+	return f.write(keys)
+}
+
+func (f *fileStore) Delete(account string) error {
+	keys, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := keys[account]; !ok {
+		return ErrNotFound
+	}
+	delete(keys, account)
+	return f.write(keys)
+}
+
+// read decrypts the stored keys; no file means none.
+func (f *fileStore) read() (map[string]string, error) {
+	keys := make(map[string]string)
+	path := f.Name()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return keys, nil
+	}
+	cmd := exec.Command("age", "--decrypt", "--identity", filepath.Join(f.dir, identityFile), path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	if err := json.Unmarshal(out, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// write encrypts keys to the identity, creating it on first use, and
+// replaces the file atomically.
+func (f *fileStore) write(keys map[string]string) error {
+	if err := os.MkdirAll(f.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	identity := filepath.Join(f.dir, identityFile)
+	if _, err := os.Stat(identity); os.IsNotExist(err) {
+		if out, err := exec.Command("age-keygen", "-o", identity).CombinedOutput(); err != nil {
+			return fmt.Errorf("age-keygen failed: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	recipient, err := exec.Command("age-keygen", "-y", identity).Output()
+	if err != nil {
+		return fmt.Errorf("failed to read the recipient of %s: %w", identity, err)
+	}
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+	tmp := f.Name() + ".tmp"
+	cmd := exec.Command("age", "--encrypt", "--recipient", strings.TrimSpace(string(recipient)), "--output", tmp)
+	cmd.Stdin = bytes.NewReader(data)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to encrypt credentials: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, f.Name())
+}
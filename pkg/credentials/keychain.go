@@ -0,0 +1,106 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// securityNotFound is the exit status of security for a missing item.
+const securityNotFound = 44
+
+// keychain stores keys in the macOS login keychain or, on Linux, in the
+// Secret Service (GNOME Keyring, KWallet) through secret-tool. Keys are
+// passed to the tools on stdin, never as arguments.
+type keychain struct {
+	tool string
+}
+
+func newKeychain() (*keychain, bool) {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return &keychain{tool: "security"}, true
+		}
+	case "linux", "freebsd", "openbsd":
+		// secret-tool needs a session bus to reach the Secret Service
+		if _, err := exec.LookPath("secret-tool"); err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+			return &keychain{tool: "secret-tool"}, true
+		}
+	}
+	return nil, false
+}
+
+func (k *keychain) Name() string {
+	if k.tool == "security" {
+		return "macOS keychain"
+	}
+	return "Secret Service keyring"
+}
+
+func (k *keychain) Get(account string) (string, error) {
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		cmd = exec.Command("security", "find-generic-password", "-s", Service, "-a", account, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", Service, "account", account)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var ee *exec.ExitError
+	switch {
+	case errors.As(err, &ee) && (k.tool != "security" || ee.ExitCode() == securityNotFound):
+		// secret-tool exits 1 without output for a missing item
+		if k.tool != "security" && strings.TrimSpace(stderr.String()) != "" {
+			return "", fmt.Errorf("secret-tool lookup failed: %s", strings.TrimSpace(stderr.String()))
+		}
+		return "", ErrNotFound
+	case err != nil:
+		return "", fmt.Errorf("%s failed: %w: %s", k.tool, err, strings.TrimSpace(stderr.String()))
+	}
+	key := strings.TrimRight(string(out), "\r\n")
+	if key == "" {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func (k *keychain) Set(account, key string) error {
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		// Interactive mode reads the command from stdin, keeping the key out
+		// of the process list
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -l %s -w %s\n",
+			strconv.Quote(Service), strconv.Quote(account), strconv.Quote(Service+" "+account), strconv.Quote(key)))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", Service+" "+account, "service", Service, "account", account)
+		cmd.Stdin = strings.NewReader(key)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed to store the key: %w: %s", k.tool, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (k *keychain) Delete(account string) error {
+	if _, err := k.Get(account); err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", Service, "-a", account)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", Service, "account", account)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed to delete the key: %w: %s", k.tool, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
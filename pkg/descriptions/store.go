@@ -0,0 +1,219 @@
+// Package descriptions implements the LLM descriptions store shared by TUI
+// docs, CLI capture and schema enrichment.
+//
+// A store is a JSON file of entries keyed by stable IDs of the form
+// "<kind>:<key>" (e.g. "tui:flow", "cli:grove flow run", "schema:settings.model").
+// Each entry records a hash of the registry entry, command or property it was
+// generated from, so callers regenerate only entries whose source changed.
+package descriptions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Entry kinds used as ID prefixes.
+const (
+	KindTUI    = "tui"
+	KindCLI    = "cli"
+	KindSchema = "schema"
+)
+
+// Entry is one stored description.
+type Entry struct {
+	Description  string            `json:"description"`
+	Capabilities []string          `json:"capabilities,omitempty"`
+	Sections     map[string]string `json:"sections,omitempty"`
+	SourceHash   string            `json:"source_hash,omitempty"` // Hash of the source the entry was generated from; empty once invalidated
+}
+
+// Store is a set of descriptions persisted as one JSON file.
+type Store struct {
+	Entries map[string]Entry `json:"entries"`
+
+	path string
+}
+
+// ID builds a store ID from a kind and key.
+func ID(kind, key string) string {
+	return kind + ":" + key
+}
+
+// Hash returns a stable hash of v's JSON encoding, for use as an entry's
+// source hash.
+func Hash(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", v))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// New returns an empty store that saves to path.
+func New(path string) *Store {
+	return &Store{Entries: make(map[string]Entry), path: path}
+}
+
+// Load reads the store at path. A missing file yields an empty store. Files
+// written before the store existed are converted on load: the tui_describe
+// shape {"tuis": {...}} becomes "tui:" entries and the schema_describe flat
+// map {"path": "description"} becomes "schema:" entries. Converted entries
+// have no source hash, so they are used as-is but regenerated on the next run.
+func Load(path string) (*Store, error) {
+	s := New(path)
+	data, err := os.ReadFile(path) //nolint:gosec // path from config
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read descriptions store %s: %w", path, err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptions store %s: %w", path, err)
+	}
+
+	switch {
+	case raw["entries"] != nil:
+		if err := json.Unmarshal(raw["entries"], &s.Entries); err != nil {
+			return nil, fmt.Errorf("failed to parse descriptions store %s: %w", path, err)
+		}
+		if s.Entries == nil {
+			s.Entries = make(map[string]Entry)
+		}
+	case raw["tuis"] != nil:
+		var tuis map[string]Entry
+		if err := json.Unmarshal(raw["tuis"], &tuis); err != nil {
+			return nil, fmt.Errorf("failed to parse TUI descriptions %s: %w", path, err)
+		}
+		for name, entry := range tuis {
+			s.Entries[ID(KindTUI, name)] = entry
+		}
+	default:
+		var flat map[string]string
+		if err := json.Unmarshal(data, &flat); err != nil {
+			return nil, fmt.Errorf("failed to parse descriptions %s: %w", path, err)
+		}
+		for key, desc := range flat {
+			s.Entries[ID(KindSchema, key)] = Entry{Description: desc}
+		}
+	}
+	return s, nil
+}
+
+// Path returns the file the store saves to.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Get returns the entry for id regardless of freshness.
+func (s *Store) Get(id string) (Entry, bool) {
+	entry, ok := s.Entries[id]
+	return entry, ok
+}
+
+// Stale reports whether id has no entry or was generated from a different
+// source than sourceHash.
+func (s *Store) Stale(id, sourceHash string) bool {
+	entry, ok := s.Entries[id]
+	return !ok || entry.SourceHash == "" || entry.SourceHash != sourceHash
+}
+
+// Put stores entry under id, recording the hash of the source it describes.
+func (s *Store) Put(id string, entry Entry, sourceHash string) {
+	entry.SourceHash = sourceHash
+	s.Entries[id] = entry
+}
+
+// Invalidate clears the source hash of every entry whose ID matches one of
+// the patterns, so the next run regenerates it. Patterns are exact IDs or
+// path.Match globs ("tui:*"); a bare key without a kind matches that key in
+// any kind. With no patterns every entry is invalidated. The matching IDs are
+// returned in sorted order.
+func (s *Store) Invalidate(patterns ...string) []string {
+	var ids []string
+	for id, entry := range s.Entries {
+		if len(patterns) > 0 && !matchID(id, patterns) {
+			continue
+		}
+		entry.SourceHash = ""
+		s.Entries[id] = entry
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func matchID(id string, patterns []string) bool {
+	_, key, _ := strings.Cut(id, ":")
+	for _, pattern := range patterns {
+		target := id
+		if !strings.Contains(pattern, ":") {
+			target = key
+		}
+		if pattern == target {
+			return true
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Kind returns the entries of one kind keyed without their kind prefix.
+func (s *Store) Kind(kind string) map[string]Entry {
+	entries := make(map[string]Entry)
+	prefix := kind + ":"
+	for id, entry := range s.Entries {
+		if key, ok := strings.CutPrefix(id, prefix); ok {
+			entries[key] = entry
+		}
+	}
+	return entries
+}
+
+// Descriptions returns the description text of every entry of one kind keyed
+// without the kind prefix.
+func (s *Store) Descriptions(kind string) map[string]string {
+	entries := s.Kind(kind)
+	descriptions := make(map[string]string, len(entries))
+	for key, entry := range entries {
+		descriptions[key] = entry.Description
+	}
+	return descriptions
+}
+
+// Save writes the store to its path.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return fmt.Errorf("descriptions store has no path")
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal descriptions store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create descriptions directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, append(data, '\n'), 0o644); err != nil { //nolint:gosec // non-sensitive descriptions file
+		return fmt.Errorf("failed to write descriptions store: %w", err)
+	}
+	return nil
+}
+
+// SchemaHash is the source hash for a schema property's entry. A property's
+// path is its ID, so only its type is hashed: renaming or retyping a property
+// invalidates its description, while edits to the description itself do not.
+func SchemaHash(propType string) string {
+	return Hash(map[string]string{"type": propType})
+}
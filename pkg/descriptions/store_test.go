@@ -0,0 +1,139 @@
+package descriptions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string // "" leaves the file missing
+		want    map[string]Entry
+		legacy  bool // Converted entries must be regenerated
+		wantErr bool
+	}{
+		{
+			name: "missing",
+			want: map[string]Entry{},
+		},
+		{
+			name:    "store",
+			content: `{"entries": {"cli:grove run": {"description": "Runs", "source_hash": "abc"}}}`,
+			want:    map[string]Entry{"cli:grove run": {Description: "Runs", SourceHash: "abc"}},
+		},
+		{
+			name:    "empty store",
+			content: `{"entries": null}`,
+			want:    map[string]Entry{},
+		},
+		{
+			name:    "legacy tui_describe",
+			legacy:  true,
+			content: `{"tuis": {"flow": {"description": "Flow TUI", "capabilities": ["plans"]}}}`,
+			want:    map[string]Entry{"tui:flow": {Description: "Flow TUI", Capabilities: []string{"plans"}}},
+		},
+		{
+			name:    "legacy schema_describe",
+			legacy:  true,
+			content: `{"settings.model": "Model to use", "settings.ttl": "Cache TTL"}`,
+			want: map[string]Entry{
+				"schema:settings.model": {Description: "Model to use"},
+				"schema:settings.ttl":   {Description: "Cache TTL"},
+			},
+		},
+		{
+			name:    "unknown shape",
+			content: `{"settings": {"model": "nested"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			content: `{"entries":`,
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "descriptions.json")
+			if c.content != "" {
+				if err := os.WriteFile(path, []byte(c.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s, err := Load(path)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("Load = %+v, want an error", s.Entries)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(s.Entries, c.want) {
+				t.Errorf("entries = %+v, want %+v", s.Entries, c.want)
+			}
+			for id, entry := range s.Entries {
+				if c.legacy && !s.Stale(id, entry.SourceHash) {
+					t.Errorf("converted entry %s is not stale", id)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadSavedLegacyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "descriptions.json")
+	if err := os.WriteFile(path, []byte(`{"tuis": {"nb": {"description": "Notebook"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Put(ID(KindTUI, "nb"), Entry{Description: "Notebook TUI"}, "h1")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Descriptions(KindTUI); !reflect.DeepEqual(got, map[string]string{"nb": "Notebook TUI"}) {
+		t.Errorf("descriptions = %v", got)
+	}
+	if reloaded.Stale(ID(KindTUI, "nb"), "h1") {
+		t.Error("entry saved with its hash is stale")
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		want     []string
+	}{
+		{nil, []string{"cli:grove run", "schema:model", "tui:flow", "tui:nb"}},
+		{[]string{"tui:*"}, []string{"tui:flow", "tui:nb"}},
+		{[]string{"tui:nb", "model"}, []string{"schema:model", "tui:nb"}},
+		{[]string{"grove *"}, []string{"cli:grove run"}},
+		{[]string{"cli:nothing"}, nil},
+	}
+	for _, c := range cases {
+		s := New("")
+		for _, id := range []string{"tui:flow", "tui:nb", "cli:grove run", "schema:model"} {
+			s.Put(id, Entry{Description: id}, "h")
+		}
+		got := s.Invalidate(c.patterns...)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Invalidate(%q) = %q, want %q", c.patterns, got, c.want)
+		}
+		for _, id := range got {
+			if !s.Stale(id, "h") {
+				t.Errorf("Invalidate(%q): %s not stale", c.patterns, id)
+			}
+		}
+	}
+}
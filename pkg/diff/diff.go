@@ -0,0 +1,198 @@
+// Package diff produces line-based unified diffs for reviewing generated output.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+	a, b int // 0-based line indices in the old and new text
+}
+
+// Unified returns a unified diff between oldText and newText, labelled with
+// oldName and newName. It returns "" when the texts are identical.
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	a := splitLines(oldText)
+	b := splitLines(newText)
+	ops := compute(a, b)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(ops) {
+		writeHunk(&out, ops[h[0]:h[1]])
+	}
+	return out.String()
+}
+
+// Stats returns the number of added and removed lines between two texts.
+func Stats(oldText, newText string) (added, removed int) {
+	for _, o := range compute(splitLines(oldText), splitLines(newText)) {
+		switch o.kind {
+		case opInsert:
+			added++
+		case opDelete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// maxLCSCells bounds the table of the longest common subsequence, which takes
+// time and memory proportional to the product of the line counts. Larger
+// changes, after their common prefix and suffix, are diffed as one
+// replacement.
+const maxLCSCells = 4 << 20
+
+// compute builds the edit script from the longest common subsequence of a and
+// b. Lines shared at the start and end are matched first, so only the lines
+// between them are compared pairwise.
+func compute(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: opEqual, line: a[i], a: i, b: i})
+	}
+	ops = append(ops, computeMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)...)
+	for k := suffix; k > 0; k-- {
+		i, j := len(a)-k, len(b)-k
+		ops = append(ops, op{kind: opEqual, line: a[i], a: i, b: j})
+	}
+	return ops
+}
+
+// computeMiddle diffs the lines of a and b that start at line offset of both
+// texts.
+func computeMiddle(a, b []string, offset int) []op {
+	n, m := len(a), len(b)
+	ops := make([]op, 0, n+m)
+	if n*m > maxLCSCells {
+		for i := range a {
+			ops = append(ops, op{kind: opDelete, line: a[i], a: offset + i, b: offset})
+		}
+		for j := range b {
+			ops = append(ops, op{kind: opInsert, line: b[j], a: offset + n, b: offset + j})
+		}
+		return ops
+	}
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, line: a[i], a: offset + i, b: offset + j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{kind: opDelete, line: a[i], a: offset + i, b: offset + j})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, line: b[j], a: offset + i, b: offset + j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, op{kind: opDelete, line: a[i], a: offset + i, b: offset + j})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, op{kind: opInsert, line: b[j], a: offset + i, b: offset + j})
+	}
+	return ops
+}
+
+// hunks groups changed ops with their surrounding context into [start, end)
+// ranges over ops, merging ranges whose context overlaps.
+func hunks(ops []op) [][2]int {
+	var result [][2]int
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+		start := max(i-contextLines, 0)
+		end := min(i+contextLines+1, len(ops))
+		if len(result) > 0 && start <= result[len(result)-1][1] {
+			result[len(result)-1][1] = end
+			continue
+		}
+		result = append(result, [2]int{start, end})
+	}
+	return result
+}
+
+func writeHunk(out *strings.Builder, ops []op) {
+	var oldCount, newCount int
+	for _, o := range ops {
+		if o.kind != opInsert {
+			oldCount++
+		}
+		if o.kind != opDelete {
+			newCount++
+		}
+	}
+	oldStart, newStart := ops[0].a+1, ops[0].b+1
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			out.WriteString(" " + o.line + "\n")
+		case opDelete:
+			out.WriteString("-" + o.line + "\n")
+		case opInsert:
+			out.WriteString("+" + o.line + "\n")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered returns the lines "1" to "n", with the lines in replace changed.
+func numbered(n int, replace map[int]string) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		if r, ok := replace[i]; ok {
+			sb.WriteString(r + "\n")
+			continue
+		}
+		fmt.Fprintf(&sb, "%d\n", i)
+	}
+	return sb.String()
+}
+
+func TestUnified(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{
+			name: "identical",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "both empty",
+			want: "",
+		},
+		{
+			name: "from empty",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "to empty",
+			old:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "change with context",
+			old:  numbered(10, nil),
+			new:  numbered(10, map[int]string{5: "five"}),
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "close changes share a hunk",
+			old:  numbered(12, nil),
+			new:  numbered(12, map[int]string{3: "three", 8: "eight"}),
+			want: "--- old\n+++ new\n@@ -1,11 +1,11 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n 7\n-8\n+eight\n 9\n 10\n 11\n",
+		},
+		{
+			name: "distant changes get their own hunks",
+			old:  numbered(20, nil),
+			new:  numbered(20, map[int]string{2: "two", 18: "eighteen"}),
+			want: "--- old\n+++ new\n@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n@@ -15,6 +15,6 @@\n 15\n 16\n 17\n-18\n+eighteen\n 19\n 20\n",
+		},
+		{
+			name: "insertion",
+			old:  "a\nc\n",
+			new:  "a\nb\nc\n",
+			want: "--- old\n+++ new\n@@ -1,2 +1,3 @@\n a\n+b\n c\n",
+		},
+		{
+			// Lines are compared without their final newline
+			name: "final newline only",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Unified("old", "new", c.old, c.new)
+			if got != c.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	added, removed := Stats(numbered(10, nil), numbered(12, map[int]string{4: "four"}))
+	if added != 3 || removed != 1 {
+		t.Errorf("Stats() = +%d -%d, want +3 -1", added, removed)
+	}
+}
+
+func TestLargeChangeIsOneReplacement(t *testing.T) {
+	const n = 3000 // n*n is over maxLCSCells
+	var oldText, newText strings.Builder
+	oldText.WriteString("header\n")
+	newText.WriteString("header\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&oldText, "old %d\n", i)
+		fmt.Fprintf(&newText, "new %d\n", i)
+	}
+	oldText.WriteString("footer\n")
+	newText.WriteString("footer\n")
+
+	added, removed := Stats(oldText.String(), newText.String())
+	if added != n || removed != n {
+		t.Errorf("Stats() = +%d -%d, want +%d -%d", added, removed, n, n)
+	}
+	got := Unified("old", "new", oldText.String(), newText.String())
+	if want := fmt.Sprintf("@@ -1,%d +1,%d @@\n header\n-old 0\n", n+2, n+2); !strings.Contains(got, want) {
+		t.Errorf("Unified() does not start with one replacement hunk: %q", got[:min(len(got), 80)])
+	}
+	if strings.Count(got, "@@ -") != 1 {
+		t.Errorf("Unified() has %d hunks, want 1", strings.Count(got, "@@ -"))
+	}
+}
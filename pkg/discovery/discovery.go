@@ -0,0 +1,227 @@
+// Package discovery finds the ecosystems and docgen packages that aggregate,
+// watch and cross-package sections work on, so every command sees the same
+// package set.
+package discovery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	coreConfig "github.com/grovetools/core/config"
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Package is a workspace with a docgen config.
+type Package struct {
+	Name      string // Workspace directory name, e.g. "grove-flow"
+	Path      string // Workspace path
+	Ecosystem string // Name of the ecosystem it belongs to
+	Config    *config.DocgenConfig
+}
+
+// Options filters the packages returned by Packages.
+type Options struct {
+	// Allowed, when non-empty, keeps only the named packages (see
+	// AllowedPackages). Packages with output_mode: sections hold website
+	// content rather than a package's docs and are always kept.
+	Allowed map[string]bool
+	// IncludeDisabled keeps packages whose config has enabled: false.
+	IncludeDisabled bool
+	// Skipped, when set, is called for each package left out, with the
+	// reason and, for a config that failed to load, the error. Workspaces
+	// without a docgen config are not packages and are not reported.
+	Skipped func(name, reason string, err error)
+}
+
+func (o Options) skipped(name, reason string, err error) {
+	if o.Skipped != nil {
+		o.Skipped(name, reason, err)
+	}
+}
+
+// Discoverer resolves ecosystems, workspaces and packages. Discovery results
+// and workspace listings are cached for the Discoverer's lifetime, so a
+// long-running command (watch) or a run visiting many packages only scans
+// once; create a new Discoverer to pick up new workspaces.
+type Discoverer struct {
+	logger *logrus.Logger
+
+	mu         sync.Mutex
+	all        []workspace.Ecosystem
+	workspaces map[string][]string // ecosystem path -> workspace paths
+}
+
+// New creates a Discoverer.
+func New(logger *logrus.Logger) *Discoverer {
+	return &Discoverer{logger: logger, workspaces: make(map[string][]string)}
+}
+
+// AllEcosystems returns every ecosystem known to grove.
+func (d *Discoverer) AllEcosystems() ([]workspace.Ecosystem, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.all != nil {
+		return d.all, nil
+	}
+	result, err := workspace.NewDiscoveryService(d.logger).DiscoverAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not discover ecosystems: %w", err)
+	}
+	d.all = result.Ecosystems
+	if d.all == nil {
+		d.all = []workspace.Ecosystem{}
+	}
+	return d.all, nil
+}
+
+// Ecosystems returns the ecosystems to work on for the local docgen config:
+// those named in settings.ecosystems, or, when none are named, the ecosystem
+// containing the current directory.
+//
+// GROVE_ECOSYSTEM_PATH (set by e.g. `grove release apply --website`) makes the
+// ecosystem checkout at that path win over the registry's for its own name,
+// so changelogs are read from the checkout a release just published to
+// rather than another clone or a stale worktree of the same ecosystem.
+func (d *Discoverer) Ecosystems(localCfg *config.DocgenConfig) ([]workspace.Ecosystem, error) {
+	if localCfg == nil || len(localCfg.Settings.Ecosystems) == 0 {
+		rootDir, err := workspace.FindEcosystemRoot("")
+		if err != nil {
+			return nil, fmt.Errorf("could not find ecosystem root: %w", err)
+		}
+		d.logger.Warnf("No 'ecosystems' specified in docgen.config.yml - using current ecosystem only (%s)", filepath.Base(rootDir))
+		d.logger.Warnf("To aggregate from multiple ecosystems, add 'settings.ecosystems' to your docgen.config.yml")
+		return []workspace.Ecosystem{{Name: filepath.Base(rootDir), Path: rootDir}}, nil
+	}
+
+	d.logger.Infof("Using ecosystems from local config: %v", localCfg.Settings.Ecosystems)
+	all, err := d.AllEcosystems()
+	if err != nil {
+		return nil, err
+	}
+	ecoByName := make(map[string]workspace.Ecosystem, len(all))
+	for _, eco := range all {
+		ecoByName[eco.Name] = eco
+	}
+
+	if envPath := os.Getenv("GROVE_ECOSYSTEM_PATH"); envPath != "" {
+		if root, err := workspace.FindEcosystemRoot(envPath); err == nil && root != "" {
+			name := filepath.Base(root)
+			if cfg, err := coreConfig.LoadFrom(root); err == nil && cfg.Name != "" {
+				name = cfg.Name
+			}
+			d.logger.Infof("GROVE_ECOSYSTEM_PATH override: ecosystem %q -> %s", name, root)
+			ecoByName[name] = workspace.Ecosystem{Name: name, Path: root}
+		} else {
+			d.logger.Debugf("GROVE_ECOSYSTEM_PATH=%s is not inside an ecosystem root; ignoring override", envPath)
+		}
+	}
+
+	var ecosystems []workspace.Ecosystem
+	for _, name := range localCfg.Settings.Ecosystems {
+		if eco, ok := ecoByName[name]; ok {
+			ecosystems = append(ecosystems, eco)
+		} else {
+			d.logger.Warnf("Ecosystem '%s' not found in groves config", name)
+		}
+	}
+	if len(ecosystems) == 0 {
+		return nil, fmt.Errorf("none of the specified ecosystems were found: %v", localCfg.Settings.Ecosystems)
+	}
+	return ecosystems, nil
+}
+
+// Workspaces returns the workspace directories of an ecosystem, expanded
+// from the workspaces globs of its grove.yml.
+func (d *Discoverer) Workspaces(eco workspace.Ecosystem) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if cached, ok := d.workspaces[eco.Path]; ok {
+		return cached, nil
+	}
+
+	configPath, err := coreConfig.FindConfigFile(eco.Path)
+	if err != nil {
+		return nil, fmt.Errorf("could not find config file in %s: %w", eco.Path, err)
+	}
+	cfg, err := coreConfig.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load ecosystem config from %s: %w", configPath, err)
+	}
+	d.logger.Debugf("Loaded ecosystem config with %d workspace patterns", len(cfg.Workspaces))
+
+	workspaces := []string{}
+	for _, wsPattern := range cfg.Workspaces {
+		matches, err := filepath.Glob(filepath.Join(eco.Path, wsPattern))
+		if err != nil {
+			d.logger.Warnf("Failed to expand pattern %s: %v", wsPattern, err)
+			continue
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				d.logger.Debugf("  Found workspace: %s", match)
+				workspaces = append(workspaces, match)
+			}
+		}
+	}
+	d.logger.Debugf("Total workspaces in ecosystem %s: %d", eco.Name, len(workspaces))
+
+	d.workspaces[eco.Path] = workspaces
+	return workspaces, nil
+}
+
+// Packages returns the workspaces of ecosystems that have a docgen config
+// (notebook first, then the repo), filtered by opts.
+func (d *Discoverer) Packages(ecosystems []workspace.Ecosystem, opts Options) ([]Package, error) {
+	var packages []Package
+	for _, eco := range ecosystems {
+		workspaces, err := d.Workspaces(eco)
+		if err != nil {
+			return nil, err
+		}
+		for _, wsPath := range workspaces {
+			wsName := filepath.Base(wsPath)
+			docCfg, _, err := config.LoadWithNotebook(wsPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					d.logger.Debugf("Skipping %s: no docgen.config.yml found", wsName)
+				} else {
+					d.logger.Warnf("Skipping %s: could not load config: %v", wsName, err)
+					opts.skipped(wsName, "could not load config", err)
+				}
+				continue
+			}
+			if !docCfg.Enabled && !opts.IncludeDisabled {
+				d.logger.Infof("Skipping %s: documentation is disabled in config", wsName)
+				opts.skipped(wsName, "documentation is disabled in config", nil)
+				continue
+			}
+			if len(opts.Allowed) > 0 && !opts.Allowed[wsName] && docCfg.Settings.OutputMode != "sections" {
+				d.logger.Debugf("Skipping %s: not in allowed packages list", wsName)
+				opts.skipped(wsName, "not in the sidebar's packages", nil)
+				continue
+			}
+			packages = append(packages, Package{Name: wsName, Path: wsPath, Ecosystem: eco.Name, Config: docCfg})
+		}
+	}
+	return packages, nil
+}
+
+// AllowedPackages returns the packages listed in the local config's
+// sidebar.categories.*.packages, the set a website build is limited to. It
+// is empty (no filtering) without a sidebar.
+func AllowedPackages(localCfg *config.DocgenConfig) map[string]bool {
+	allowed := make(map[string]bool)
+	if localCfg == nil || localCfg.Sidebar == nil {
+		return allowed
+	}
+	for _, cat := range localCfg.Sidebar.Categories {
+		for _, pkg := range cat.Packages {
+			allowed[pkg] = true
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,209 @@
+// Package docgen is the programmatic API of docgen, for tools that embed
+// documentation generation rather than shelling out to the docgen binary.
+// The CLI commands are thin wrappers around it.
+//
+//	err := docgen.Generate(ctx, "/path/to/package", docgen.GenerateOptions{
+//		Sections: []string{"overview"},
+//	})
+//
+// Generate, GenerateAll, Aggregate and Watch and their options are the stable surface; the
+// packages they are built on (generator, aggregator, ...) may change between
+// releases.
+package docgen
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/aggregator"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/progress"
+	"github.com/grovetools/docgen/pkg/report"
+	"github.com/grovetools/docgen/pkg/watch"
+	"github.com/sirupsen/logrus"
+)
+
+// GenerateOptions configures Generate. The zero value generates every
+// section with the models from the package's config.
+type GenerateOptions struct {
+	Sections []string // Section names to generate (empty means all)
+	Model    string   // Override the model for all sections
+	CacheTTL string   // Fan-out shared-prefix cache TTL: "5m" (default) or "1h"
+	// UsageJSONPath, when non-empty, receives the per-section cache/usage
+	// report at the end of the run.
+	UsageJSONPath string
+	FailFast      bool // Stop at the first failed section
+	Strict        bool // Fail the run on warnings
+	// Isolate builds context in a temporary git worktree so the package's
+	// checkout is left untouched and packages can generate concurrently.
+	Isolate bool
+	// OnlyStale generates only the sections whose doc is missing or older
+	// than its prompt or config.
+	OnlyStale bool
+	// Batch submits the sections' requests through the providers' batch
+	// APIs and waits for the results.
+	Batch bool
+
+	// Logger receives progress logs; nil discards them.
+	Logger *logrus.Logger
+	// UnifiedLog also logs milestones, such as each section written and
+	// the run summary, to grove's unified log, as the docgen commands do.
+	UnifiedLog bool
+	// Report, when set, records each section's outcome (see package report).
+	Report *report.Recorder
+	// Progress, when set, shows the sections' progress (see package
+	// progress); the caller stops it.
+	Progress *progress.Tracker
+	// Answer, when set, asks the user the questions sections ask with
+	// settings.questions that answers.yml does not answer.
+	Answer generator.AnswerFunc
+}
+
+// Generate generates the documentation of the package at packageDir,
+// honouring ctx for cancellation. It returns an error naming the failed
+// sections when any section fails.
+func Generate(ctx context.Context, packageDir string, opts GenerateOptions) error {
+	gen := newGenerator(opts)
+	return gen.GenerateContext(ctx, packageDir, generator.GenerateOptions{
+		Sections:      opts.Sections,
+		Model:         opts.Model,
+		CacheTTL:      opts.CacheTTL,
+		UsageJSONPath: opts.UsageJSONPath,
+		FailFast:      opts.FailFast,
+		Strict:        opts.Strict,
+		Isolate:       opts.Isolate,
+		OnlyStale:     opts.OnlyStale,
+		Batch:         opts.Batch,
+	})
+}
+
+// newGenerator returns a generator set up with opts' logger and sinks.
+func newGenerator(opts GenerateOptions) *generator.Generator {
+	gen := generator.New(loggerOrDiscard(opts.Logger))
+	gen.SetReport(opts.Report)
+	gen.SetProgress(opts.Progress)
+	gen.SetAnswerFunc(opts.Answer)
+	if opts.UnifiedLog {
+		gen.SetEventLogger(clilog.New(clilog.Component))
+	}
+	return gen
+}
+
+// GenerateAllOptions configures GenerateAll.
+type GenerateAllOptions struct {
+	// GenerateOptions applies to every package; Sections and UsageJSONPath
+	// must be empty. Report records the sections of every package.
+	GenerateOptions
+	Concurrency int // Packages generated at once; default 1
+}
+
+// GenerateAll generates every docgen-enabled package of the ecosystems
+// configured in dir's docgen config, the packages Aggregate and Watch work
+// on. It returns an error naming the failed packages when any fails.
+func GenerateAll(ctx context.Context, dir string, opts GenerateAllOptions) error {
+	gen := newGenerator(opts.GenerateOptions)
+	return gen.GenerateAllContext(ctx, dir, generator.AllOptions{
+		GenerateOptions: generator.GenerateOptions{
+			Model:     opts.Model,
+			CacheTTL:  opts.CacheTTL,
+			FailFast:  opts.FailFast,
+			Strict:    opts.Strict,
+			Isolate:   opts.Isolate,
+			OnlyStale: opts.OnlyStale,
+			Batch:     opts.Batch,
+		},
+		Concurrency: opts.Concurrency,
+	})
+}
+
+// AggregateOptions configures Aggregate.
+type AggregateOptions struct {
+	Mode      string // "dev" (default) includes draft and dev sections; "prod" only production
+	Transform string // Output transform, e.g. "astro"; empty copies docs as-is
+	// Profile is "internal" (default), publishing everything, or "public",
+	// leaving out internal sections and blocks and applying scrub rules.
+	Profile string
+	// Audiences, when set, publish only the sections written for one of
+	// them, plus those without an audience.
+	Audiences []string
+	// Packages and Categories, when set, rebuild only the selected packages
+	// and patch their entries into the existing manifest.
+	Packages   []string
+	Categories []string
+	// Strict fails the run when the validation report has errors, such as
+	// placeholder sections or broken links.
+	Strict bool
+
+	// Logger receives progress logs; nil discards them.
+	Logger *logrus.Logger
+	// Report, when set, records each aggregated section.
+	Report *report.Recorder
+	// Progress, when set, shows the aggregated sections' progress; the
+	// caller stops it.
+	Progress *progress.Tracker
+}
+
+// Aggregate collects the generated documentation of every package in the
+// configured ecosystems into outputDir and writes its manifest.
+func Aggregate(ctx context.Context, outputDir string, opts AggregateOptions) error {
+	mode := opts.Mode
+	if mode == "" {
+		mode = "dev"
+	}
+	agg := aggregator.New(loggerOrDiscard(opts.Logger))
+	agg.SetReport(opts.Report)
+	agg.SetProgress(opts.Progress)
+	agg.SetFilter(aggregator.Filter{Packages: opts.Packages, Categories: opts.Categories})
+	agg.SetStrict(opts.Strict)
+	agg.SetProfile(opts.Profile)
+	agg.SetAudiences(opts.Audiences)
+	return agg.AggregateContext(ctx, outputDir, mode, opts.Transform)
+}
+
+// WatchEvent describes one rebuild during Watch.
+type WatchEvent = watch.Event
+
+// WatchHooks are the callbacks Watch reports progress through: the
+// directories watched, each rebuild's start and outcome, and each debounced
+// batch. All are optional.
+type WatchHooks = watch.Hooks
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	WebsiteDir string        // Root of the Astro website written to
+	Mode       string        // "dev" (default) or "prod"
+	Debounce   time.Duration // Quiet period before rebuilding; default 100ms
+	// Audiences limit the rebuilt sections to those written for one of
+	// them; none rebuilds every section.
+	Audiences []string
+
+	// Logger receives progress and warnings; nil discards them.
+	Logger *logrus.Logger
+	Hooks  WatchHooks
+}
+
+// Watch watches the notebook docs of the packages Aggregate would build and
+// rebuilds a package into the website whenever its sources change, until ctx
+// is cancelled.
+func Watch(ctx context.Context, opts WatchOptions) error {
+	return watch.Run(ctx, watch.Options{
+		WebsiteDir: opts.WebsiteDir,
+		Mode:       opts.Mode,
+		Debounce:   opts.Debounce,
+		Audiences:  opts.Audiences,
+		Logger:     loggerOrDiscard(opts.Logger),
+		Hooks:      opts.Hooks,
+	})
+}
+
+// loggerOrDiscard returns logger, or a logger that discards everything.
+func loggerOrDiscard(logger *logrus.Logger) *logrus.Logger {
+	if logger != nil {
+		return logger
+	}
+	discard := logrus.New()
+	discard.SetOutput(io.Discard)
+	return discard
+}
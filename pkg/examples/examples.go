@@ -0,0 +1,273 @@
+// Package examples compiles the Go code blocks of generated docs against the
+// documented module, so examples that no longer build are caught.
+package examples
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Block is one ```go code block of a markdown file.
+type Block struct {
+	Section string `json:"section,omitempty"`
+	File    string `json:"file"`
+	Line    int    `json:"line"` // Line of the opening fence
+	Code    string `json:"-"`
+}
+
+// Result is the outcome of checking one block.
+type Result struct {
+	Block
+	OK     bool     `json:"ok"`
+	Errors []string `json:"errors,omitempty"` // Compiler and vet diagnostics
+}
+
+var (
+	fenceRe    = regexp.MustCompile("^\\s*(```+|~~~+)\\s*(\\S*)(.*)$")
+	packageRe  = regexp.MustCompile(`(?m)^package\s+\w+`)
+	declRe     = regexp.MustCompile(`(?m)^(func|type|var|const)\b`)
+	importRe   = regexp.MustCompile(`(?m)^import\s*(\([^)]*\)|"[^"]+"|\w+\s+"[^"]+")`)
+	selectorRe = regexp.MustCompile(`\b([a-z][a-z0-9]*)\.[A-Z]`)
+	unusedRe   = regexp.MustCompile(`declared and not used: (\w+)|(\w+) declared and not used`)
+	diagRe     = regexp.MustCompile(`^\.?/?ex(\d+)/[^:]+\.go:\d+(?::\d+)?: (.*)$`)
+)
+
+// stdlib maps package names commonly used in examples to their import paths,
+// for blocks that leave their imports out.
+var stdlib = map[string]string{
+	"bufio": "bufio", "bytes": "bytes", "context": "context", "errors": "errors",
+	"exec": "os/exec", "filepath": "path/filepath", "fmt": "fmt", "http": "net/http",
+	"io": "io", "json": "encoding/json", "log": "log", "math": "math", "os": "os",
+	"path": "path", "regexp": "regexp", "sort": "sort", "strconv": "strconv",
+	"strings": "strings", "sync": "sync", "time": "time", "url": "net/url",
+}
+
+// Extract returns the ```go blocks of a markdown file, attributed to section. Blocks whose fence info
+// contains "nocheck" are skipped, for deliberately partial examples.
+func Extract(section, file, content string) []Block {
+	var blocks []Block
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		m := fenceRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), m[1]) {
+			end++
+		}
+		if strings.EqualFold(m[2], "go") && !strings.Contains(m[3], "nocheck") && end < len(lines) {
+			blocks = append(blocks, Block{Section: section, File: file, Line: i + 1, Code: strings.Join(lines[i+1:end], "\n")})
+		}
+		i = end
+	}
+	return blocks
+}
+
+// Checker compiles blocks against a Go module.
+type Checker struct {
+	ModuleDir  string
+	modulePath string
+	packages   map[string]string // Package name -> import path within the module
+}
+
+// NewChecker prepares a checker for the module rooted at moduleDir.
+func NewChecker(moduleDir string) (*Checker, error) {
+	data, err := os.ReadFile(filepath.Join(moduleDir, "go.mod")) //nolint:gosec // module root from caller
+	if err != nil {
+		return nil, fmt.Errorf("no go.mod in %s: %w", moduleDir, err)
+	}
+	m := regexp.MustCompile(`(?m)^module\s+(\S+)`).FindSubmatch(data)
+	if m == nil {
+		return nil, fmt.Errorf("no module directive in %s/go.mod", moduleDir)
+	}
+	c := &Checker{ModuleDir: moduleDir, modulePath: string(m[1]), packages: make(map[string]string)}
+
+	// Map the module's package names to import paths so blocks that leave
+	// imports out can still reference them.
+	cmd := exec.Command("go", "list", "-f", "{{.Name}} {{.ImportPath}}", "./...")
+	cmd.Dir = moduleDir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	if out, err := cmd.Output(); err == nil {
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if name, path, ok := strings.Cut(line, " "); ok && name != "main" && !strings.Contains(path, "/internal/") {
+				c.packages[name] = path
+			}
+		}
+	}
+	return c, nil
+}
+
+// Check compiles every block as its own package in a temporary module that
+// requires the documented module through a replace directive, runs go build
+// and go vet over them, and reports each block's diagnostics.
+func (c *Checker) Check(blocks []Block) ([]Result, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	dir, err := os.MkdirTemp("", "docgen-examples-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp module: %w", err)
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck // best-effort temp cleanup
+
+	goMod := fmt.Sprintf("module docgen.examples\n\ngo 1.21\n\nrequire %[1]s v0.0.0\n\nreplace %[1]s => %[2]s\n", c.modulePath, c.ModuleDir)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil { //nolint:gosec // temp module
+		return nil, fmt.Errorf("failed to write temp module: %w", err)
+	}
+	if sum, err := os.ReadFile(filepath.Join(c.ModuleDir, "go.sum")); err == nil { //nolint:gosec // module go.sum
+		if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0o644); err != nil { //nolint:gosec // temp module
+			return nil, fmt.Errorf("failed to write temp module: %w", err)
+		}
+	}
+
+	wrapped := make([]bool, len(blocks))
+	sources := make([]string, len(blocks))
+	for i, b := range blocks {
+		sources[i], wrapped[i] = c.wrap(b.Code)
+	}
+
+	// Statement blocks often declare variables only to show them; silence
+	// "declared and not used" once so the example is judged on the rest.
+	var diags map[int][]string
+	for attempt := 0; attempt < 2; attempt++ {
+		for i, src := range sources {
+			pkgDir := filepath.Join(dir, fmt.Sprintf("ex%03d", i+1))
+			if err := os.MkdirAll(pkgDir, 0o755); err != nil { //nolint:gosec // temp module
+				return nil, fmt.Errorf("failed to write example: %w", err)
+			}
+			if err := os.WriteFile(filepath.Join(pkgDir, "example.go"), []byte(src), 0o644); err != nil { //nolint:gosec // temp module
+				return nil, fmt.Errorf("failed to write example: %w", err)
+			}
+		}
+		if diags, err = c.run(dir, "build"); err != nil {
+			return nil, err
+		}
+		retry := false
+		for i, errs := range diags {
+			if !wrapped[i] {
+				continue
+			}
+			var unused []string
+			for _, e := range errs {
+				if m := unusedRe.FindStringSubmatch(e); m != nil {
+					unused = append(unused, m[1]+m[2])
+				}
+			}
+			if len(unused) > 0 && attempt == 0 {
+				sources[i] = strings.Replace(sources[i], "\n}\n", "\n\t_ = "+strings.Join(unused, "\n\t_ = ")+"\n}\n", 1)
+				retry = true
+			}
+		}
+		if !retry {
+			break
+		}
+	}
+
+	vetDiags, err := c.run(dir, "vet")
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(blocks))
+	for i, b := range blocks {
+		errs := diags[i]
+		if len(errs) == 0 {
+			errs = vetDiags[i]
+		}
+		results[i] = Result{Block: b, OK: len(errs) == 0, Errors: errs}
+	}
+	return results, nil
+}
+
+// wrap turns a code block into a compilable file. Complete files are kept;
+// bare declarations get a package clause; bare statements are wrapped in a
+// function. Missing imports of the standard library and the module's own
+// packages are added. It reports whether the block was wrapped in a function.
+func (c *Checker) wrap(code string) (string, bool) {
+	if packageRe.MatchString(code) {
+		return code, false
+	}
+
+	imports := map[string]bool{}
+	for _, m := range importRe.FindAllString(code, -1) {
+		for _, q := range regexp.MustCompile(`"[^"]+"`).FindAllString(m, -1) {
+			imports[strings.Trim(q, `"`)] = true
+		}
+	}
+	body := importRe.ReplaceAllString(code, "")
+
+	for _, m := range selectorRe.FindAllStringSubmatch(body, -1) {
+		name := m[1]
+		if path, ok := c.packages[name]; ok {
+			imports[path] = true
+		} else if path, ok := stdlib[name]; ok {
+			imports[path] = true
+		}
+	}
+	// Drop guessed imports whose name is also declared in the block.
+	for path := range imports {
+		name := filepath.Base(path)
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*(:=|=|,)`).MatchString(body) {
+			delete(imports, path)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("package example\n\n")
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		sb.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&sb, "\t%q\n", path)
+		}
+		sb.WriteString(")\n\n")
+	}
+	if declRe.MatchString(body) {
+		sb.WriteString(strings.TrimSpace(body) + "\n")
+		return sb.String(), false
+	}
+	sb.WriteString("func _() {\n" + strings.TrimSpace(body) + "\n}\n")
+	return sb.String(), true
+}
+
+// run runs go build or go vet over every example package and returns the
+// diagnostics per block index.
+func (c *Checker) run(dir, tool string) (map[int][]string, error) {
+	args := []string{tool}
+	if tool == "build" {
+		args = append(args, "-o", os.DevNull)
+	}
+	cmd := exec.Command("go", append(args, "./...")...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	runErr := cmd.Run()
+
+	diags := make(map[int][]string)
+	for _, line := range strings.Split(out.String(), "\n") {
+		m := diagRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		var n int
+		fmt.Sscanf(m[1], "%d", &n) //nolint:errcheck // matched digits
+		diags[n-1] = append(diags[n-1], m[2])
+	}
+	if runErr != nil && len(diags) == 0 {
+		return nil, fmt.Errorf("go %s failed: %w\n%s", tool, runErr, strings.TrimSpace(out.String()))
+	}
+	return diags, nil
+}
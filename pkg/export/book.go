@@ -0,0 +1,69 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// LoadBook collects the generated markdown sections of the package at
+// packageDir, in section order, with metadata from its docgen config. Sections
+// that produce other formats (descriptions, examples), concat sections (which
+// repeat the others), and sections not generated yet are skipped.
+func LoadBook(packageDir string) (Book, error) {
+	targets, err := generator.ResolveSectionTargets(packageDir)
+	if err != nil {
+		return Book{}, err
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].Section.Order < targets[j].Section.Order
+	})
+
+	name := filepath.Base(packageDir)
+	book := Book{
+		Title:      name,
+		Identifier: "urn:docgen:" + name,
+	}
+	if len(targets) > 0 {
+		cfg := targets[0].Config
+		if cfg.Title != "" {
+			book.Title = cfg.Title
+		}
+		book.Description = cfg.Description
+		book.Subject = cfg.Category
+	}
+
+	for _, t := range targets {
+		if t.Section.Type == "concat" || !strings.HasSuffix(t.Section.Output, ".md") {
+			continue
+		}
+		source := filepath.Join(t.OutputDir, t.Section.Output)
+		data, err := os.ReadFile(source) //nolint:gosec // path from config
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return Book{}, fmt.Errorf("failed to read section '%s': %w", t.Name, err)
+		}
+		markdown, err := transformer.ApplyConditions(string(data), transformer.Conditions{Target: transformer.TargetEPUB, Mode: config.ModeProd, Profile: config.ProfilePublic})
+		if err != nil {
+			return Book{}, fmt.Errorf("invalid conditional block in section '%s': %w", t.Name, err)
+		}
+		title := t.Section.Title
+		if title == "" {
+			title = t.Section.Name
+		}
+		book.Chapters = append(book.Chapters, Chapter{Title: title, Source: source, Markdown: markdown})
+	}
+
+	if len(book.Chapters) == 0 {
+		return Book{}, fmt.Errorf("no generated markdown sections found for %s (run 'docgen generate' first)", packageDir)
+	}
+	return book, nil
+}
@@ -0,0 +1,285 @@
+// Package export packages a package's generated docs into offline formats.
+package export
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Book is a docs set ready to be exported.
+type Book struct {
+	Title       string
+	Description string
+	Author      string
+	Language    string // BCP 47 tag (default: en)
+	Identifier  string // Unique book identifier, e.g. urn:docgen:flow
+	Subject     string // Category of the docs
+	Modified    time.Time
+	Chapters    []Chapter
+}
+
+// Chapter is one section of a Book.
+type Chapter struct {
+	Title    string
+	Source   string // Path of the generated markdown file; relative image links resolve against it
+	Markdown string
+}
+
+// chapterFile is the name of a chapter inside the EPUB.
+func chapterFile(i int) string {
+	return fmt.Sprintf("chapter-%02d.xhtml", i+1)
+}
+
+var imageTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".svg":  "image/svg+xml",
+	".webp": "image/webp",
+}
+
+type epubImage struct {
+	name      string // Path inside OEBPS/
+	mediaType string
+	data      []byte
+}
+
+// WriteEPUB writes book as an EPUB 3 file to outputPath. Each chapter becomes
+// one XHTML document listed in the navigation document; local images
+// referenced by a chapter are embedded, and links between chapters' markdown
+// files are rewritten to point at the chapter documents. Remote images are
+// replaced by their alt text since EPUB readers work offline.
+func WriteEPUB(outputPath string, book Book) error {
+	if len(book.Chapters) == 0 {
+		return fmt.Errorf("book %q has no chapters", book.Title)
+	}
+	if book.Language == "" {
+		book.Language = "en"
+	}
+	if book.Identifier == "" {
+		book.Identifier = "urn:docgen:" + strings.ToLower(strings.ReplaceAll(book.Title, " ", "-"))
+	}
+	if book.Modified.IsZero() {
+		book.Modified = time.Now()
+	}
+
+	// Map each chapter's markdown filename to its XHTML document so
+	// cross-chapter links keep working.
+	chapterByFile := make(map[string]string)
+	for i, ch := range book.Chapters {
+		chapterByFile[filepath.Base(ch.Source)] = chapterFile(i)
+	}
+
+	var images []epubImage
+	imageBySource := make(map[string]string)
+	documents := make([]string, len(book.Chapters))
+	for i, ch := range book.Chapters {
+		baseDir := filepath.Dir(ch.Source)
+		r := &renderer{
+			link: func(href string) string {
+				target, fragment, _ := strings.Cut(href, "#")
+				if strings.Contains(target, "://") || !strings.HasSuffix(target, ".md") {
+					return href
+				}
+				if doc, ok := chapterByFile[path.Base(target)]; ok {
+					if fragment != "" {
+						return doc + "#" + fragment
+					}
+					return doc
+				}
+				return href
+			},
+			image: func(src string) string {
+				if strings.Contains(src, "://") || strings.HasPrefix(src, "data:") {
+					return ""
+				}
+				abs := filepath.Join(baseDir, filepath.FromSlash(src))
+				if name, ok := imageBySource[abs]; ok {
+					return name
+				}
+				mediaType, ok := imageTypes[strings.ToLower(filepath.Ext(abs))]
+				if !ok {
+					return ""
+				}
+				data, err := os.ReadFile(abs) //nolint:gosec // image referenced by generated docs
+				if err != nil {
+					return ""
+				}
+				name := fmt.Sprintf("images/image-%03d%s", len(images)+1, strings.ToLower(filepath.Ext(abs)))
+				images = append(images, epubImage{name: name, mediaType: mediaType, data: data})
+				imageBySource[abs] = name
+				return name
+			},
+		}
+		documents[i] = xhtmlDocument(ch.Title, book.Language, r.render(ch.Markdown))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	f, err := os.Create(outputPath) //nolint:gosec // output path from flag
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outputPath, err)
+	}
+	defer f.Close() //nolint:errcheck // close error surfaced by zw.Close
+
+	zw := zip.NewWriter(f)
+
+	// The mimetype entry must come first and be stored uncompressed.
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+	if _, err := io.WriteString(w, "application/epub+zip"); err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"META-INF/container.xml", []byte(containerXML)},
+		{"OEBPS/content.opf", []byte(packageDocument(book, images))},
+		{"OEBPS/nav.xhtml", []byte(navDocument(book))},
+		{"OEBPS/toc.ncx", []byte(ncxDocument(book))},
+		{"OEBPS/style.css", []byte(stylesheet)},
+	}
+	for i, doc := range documents {
+		files = append(files, struct {
+			name string
+			data []byte
+		}{"OEBPS/" + chapterFile(i), []byte(doc)})
+	}
+	for _, img := range images {
+		files = append(files, struct {
+			name string
+			data []byte
+		}{"OEBPS/" + img.name, img.data})
+	}
+
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to write EPUB entry %s: %w", file.name, err)
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return fmt.Errorf("failed to write EPUB entry %s: %w", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize EPUB: %w", err)
+	}
+	return f.Close()
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+const stylesheet = `body { font-family: serif; line-height: 1.5; }
+pre { white-space: pre-wrap; font-size: 0.85em; background: #f4f4f4; padding: 0.5em; }
+pre mark { background: #fff3b0; }
+.code-title { font-family: monospace; font-size: 0.85em; margin-bottom: 0; }
+code { font-family: monospace; }
+table { border-collapse: collapse; font-size: 0.9em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; vertical-align: top; }
+blockquote { margin-left: 1em; padding-left: 1em; border-left: 3px solid #ccc; }
+img { max-width: 100%; }
+`
+
+func xhtmlDocument(title, lang, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="%[2]s" lang="%[2]s">
+<head>
+  <meta charset="UTF-8"/>
+  <title>%[1]s</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+%[3]s</body>
+</html>
+`, html.EscapeString(title), lang, body)
+}
+
+func packageDocument(book Book, images []epubImage) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="%s">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">%s</dc:identifier>
+    <dc:title>%s</dc:title>
+    <dc:language>%s</dc:language>
+`, book.Language, html.EscapeString(book.Identifier), html.EscapeString(book.Title), book.Language)
+	if book.Description != "" {
+		fmt.Fprintf(&sb, "    <dc:description>%s</dc:description>\n", html.EscapeString(book.Description))
+	}
+	if book.Author != "" {
+		fmt.Fprintf(&sb, "    <dc:creator>%s</dc:creator>\n", html.EscapeString(book.Author))
+	}
+	if book.Subject != "" {
+		fmt.Fprintf(&sb, "    <dc:subject>%s</dc:subject>\n", html.EscapeString(book.Subject))
+	}
+	fmt.Fprintf(&sb, "    <meta property=\"dcterms:modified\">%s</meta>\n", book.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	sb.WriteString("  </metadata>\n  <manifest>\n")
+	sb.WriteString("    <item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	sb.WriteString("    <item id=\"ncx\" href=\"toc.ncx\" media-type=\"application/x-dtbncx+xml\"/>\n")
+	sb.WriteString("    <item id=\"style\" href=\"style.css\" media-type=\"text/css\"/>\n")
+	for i := range book.Chapters {
+		fmt.Fprintf(&sb, "    <item id=\"chapter-%02d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", i+1, chapterFile(i))
+	}
+	for i, img := range images {
+		fmt.Fprintf(&sb, "    <item id=\"image-%03d\" href=\"%s\" media-type=\"%s\"/>\n", i+1, img.name, img.mediaType)
+	}
+	sb.WriteString("  </manifest>\n  <spine toc=\"ncx\">\n")
+	for i := range book.Chapters {
+		fmt.Fprintf(&sb, "    <itemref idref=\"chapter-%02d\"/>\n", i+1)
+	}
+	sb.WriteString("  </spine>\n</package>\n")
+	return sb.String()
+}
+
+func navDocument(book Book) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<nav epub:type=\"toc\" id=\"toc\">\n<h1>%s</h1>\n<ol>\n", html.EscapeString(book.Title))
+	for i, ch := range book.Chapters {
+		fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a></li>\n", chapterFile(i), html.EscapeString(ch.Title))
+	}
+	sb.WriteString("</ol>\n</nav>\n")
+	return xhtmlDocument(book.Title, book.Language, sb.String())
+}
+
+// ncxDocument is the EPUB 2 table of contents, kept for older readers.
+func ncxDocument(book Book) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="%s"/>
+  </head>
+  <docTitle><text>%s</text></docTitle>
+  <navMap>
+`, html.EscapeString(book.Identifier), html.EscapeString(book.Title))
+	for i, ch := range book.Chapters {
+		fmt.Fprintf(&sb, `    <navPoint id="nav-%02[1]d" playOrder="%[1]d">
+      <navLabel><text>%[2]s</text></navLabel>
+      <content src="%[3]s"/>
+    </navPoint>
+`, i+1, html.EscapeString(ch.Title), chapterFile(i))
+	}
+	sb.WriteString("  </navMap>\n</ncx>\n")
+	return sb.String()
+}
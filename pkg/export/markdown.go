@@ -0,0 +1,216 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// The Markdown subset docgen sections produce: ATX headings, paragraphs,
+// fenced code, block quotes, flat lists, pipe tables, rules, and inline code,
+// emphasis, links and images. Lines starting with an HTML tag are passed
+// through verbatim (e.g. capture's terminal blocks).
+var (
+	headingRe     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fenceRe       = regexp.MustCompile("^(```|~~~)\\s*(.*)$")
+	ruleRe        = regexp.MustCompile(`^(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	unorderedRe   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRe     = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	tableSepRe    = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	htmlBlockRe   = regexp.MustCompile(`^\s*</?[a-zA-Z][^>]*>`)
+	frontmatterRe = regexp.MustCompile(`(?s)\A---\n.*?\n---\n`)
+
+	codeSpanRe = regexp.MustCompile("`([^`]+)`")
+	imageRe    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	linkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	strongRe   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	emRe       = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	brRe       = regexp.MustCompile(`&lt;br\s*/?&gt;`)
+	anchorRe   = regexp.MustCompile(`&lt;a id=&#34;([\w.-]+)&#34;&gt;&lt;/a&gt;`)
+)
+
+// renderer converts Markdown to XHTML. Links and images are passed through
+// the rewrite hooks so callers can remap chapter links and embed images.
+type renderer struct {
+	link  func(href string) string
+	image func(src string) string
+}
+
+// highlightLines escapes code lines, wrapping the highlighted (1-based)
+// lines in <mark>.
+func highlightLines(code []string, highlighted []int) string {
+	marked := make(map[int]bool, len(highlighted))
+	for _, n := range highlighted {
+		marked[n] = true
+	}
+	lines := make([]string, len(code))
+	for i, line := range code {
+		lines[i] = html.EscapeString(line)
+		if marked[i+1] {
+			lines[i] = "<mark>" + lines[i] + "</mark>"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// render converts a Markdown document to an XHTML body fragment.
+func (r *renderer) render(markdown string) string {
+	markdown = frontmatterRe.ReplaceAllString(strings.ReplaceAll(markdown, "\r\n", "\n"), "")
+	lines := strings.Split(markdown, "\n")
+
+	var out strings.Builder
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + r.inline(strings.Join(para, " ")) + "</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case fenceRe.MatchString(trimmed):
+			flush()
+			m := fenceRe.FindStringSubmatch(trimmed)
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]); i++ {
+				code = append(code, lines[i])
+			}
+			info := transformer.ParseFenceInfo(m[2])
+			class := ""
+			if info.Lang != "" {
+				class = fmt.Sprintf(" class=\"language-%s\"", html.EscapeString(info.Lang))
+			}
+			if info.Title != "" {
+				fmt.Fprintf(&out, "<p class=\"code-title\">%s</p>\n", html.EscapeString(info.Title))
+			}
+			fmt.Fprintf(&out, "<pre><code%s>%s</code></pre>\n", class, highlightLines(code, info.HighlightedLines()))
+
+		case headingRe.MatchString(trimmed):
+			flush()
+			m := headingRe.FindStringSubmatch(trimmed)
+			level := len(m[1])
+			fmt.Fprintf(&out, "<h%d>%s</h%d>\n", level, r.inline(m[2]), level)
+
+		case ruleRe.MatchString(trimmed):
+			flush()
+			out.WriteString("<hr/>\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			out.WriteString("<blockquote>\n" + r.render(strings.Join(quote, "\n")) + "</blockquote>\n")
+
+		case unorderedRe.MatchString(line) || orderedRe.MatchString(line):
+			flush()
+			re, tag := unorderedRe, "ul"
+			if !unorderedRe.MatchString(line) {
+				re, tag = orderedRe, "ol"
+			}
+			out.WriteString("<" + tag + ">\n")
+			for ; i < len(lines) && re.MatchString(lines[i]); i++ {
+				item := re.FindStringSubmatch(lines[i])[1]
+				// Indented continuation lines belong to the item.
+				for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") && !re.MatchString(lines[i+1]) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+					item += " " + strings.TrimSpace(lines[i])
+				}
+				out.WriteString("<li>" + r.inline(item) + "</li>\n")
+			}
+			i--
+			out.WriteString("</" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableSepRe.MatchString(lines[i+1]):
+			flush()
+			out.WriteString("<table>\n<thead><tr>")
+			for _, cell := range splitRow(trimmed) {
+				out.WriteString("<th>" + r.inline(cell) + "</th>")
+			}
+			out.WriteString("</tr></thead>\n<tbody>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				out.WriteString("<tr>")
+				for _, cell := range splitRow(strings.TrimSpace(lines[i])) {
+					out.WriteString("<td>" + r.inline(cell) + "</td>")
+				}
+				out.WriteString("</tr>\n")
+			}
+			i--
+			out.WriteString("</tbody>\n</table>\n")
+
+		case len(para) == 0 && htmlBlockRe.MatchString(line):
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+				out.WriteString(lines[i] + "\n")
+			}
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// splitRow splits a pipe table row into trimmed cells, honouring \| escapes.
+func splitRow(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	row = strings.ReplaceAll(row, `\|`, "\x00")
+	cells := strings.Split(row, "|")
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(strings.TrimSpace(cell), "\x00", "|")
+	}
+	return cells
+}
+
+// inline renders inline Markdown. Code spans are set aside first so their
+// contents are not formatted, then the text is escaped and the remaining
+// syntax converted.
+func (r *renderer) inline(text string) string {
+	var spans []string
+	text = codeSpanRe.ReplaceAllStringFunc(text, func(m string) string {
+		spans = append(spans, "<code>"+html.EscapeString(codeSpanRe.FindStringSubmatch(m)[1])+"</code>")
+		return fmt.Sprintf("\x00%d\x00", len(spans)-1)
+	})
+
+	text = html.EscapeString(text)
+	text = brRe.ReplaceAllString(text, "<br/>")
+	text = anchorRe.ReplaceAllString(text, `<a id="$1"></a>`)
+	text = imageRe.ReplaceAllStringFunc(text, func(m string) string {
+		sub := imageRe.FindStringSubmatch(m)
+		src := html.UnescapeString(sub[2])
+		if r.image != nil {
+			src = r.image(src)
+		}
+		if src == "" {
+			return sub[1]
+		}
+		return fmt.Sprintf(`<img src="%s" alt="%s"/>`, html.EscapeString(src), sub[1])
+	})
+	text = linkRe.ReplaceAllStringFunc(text, func(m string) string {
+		sub := linkRe.FindStringSubmatch(m)
+		href := html.UnescapeString(sub[2])
+		if r.link != nil {
+			href = r.link(href)
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), sub[1])
+	})
+	text = strongRe.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = emRe.ReplaceAllString(text, "<em>$1$2</em>")
+
+	for i, span := range spans {
+		text = strings.ReplaceAll(text, fmt.Sprintf("\x00%d\x00", i), span)
+	}
+	return text
+}
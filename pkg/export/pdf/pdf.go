@@ -0,0 +1,247 @@
+// Package pdf writes printable PDF documents of text tables, such as the
+// keymap cheat sheets of tui_keymaps sections. Pages are A4 landscape in
+// the standard Courier fonts, so the files need no embedded fonts and render
+// the same in every viewer.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page geometry in points: A4 landscape with half-inch margins, Courier at
+// 8 points, whose glyphs are 0.6 em wide.
+const (
+	pageWidth   = 842
+	pageHeight  = 595
+	margin      = 36
+	titleSize   = 14
+	fontSize    = 8
+	lineHeight  = 10
+	charWidth   = fontSize * 0.6
+	columnGap   = 2
+	maxLineChar = (pageWidth - 2*margin) * 10 / (fontSize * 6) // Characters of a line
+)
+
+// Table is a titled table. Cells too wide for the page wrap onto more lines,
+// and the header repeats on every page.
+type Table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// textReplacer spells out the characters of key names and prose that the
+// standard fonts' encoding lacks.
+var textReplacer = strings.NewReplacer(
+	"↑", "Up", "↓", "Down", "←", "Left", "→", "Right", "⏎", "Enter", "↵", "Enter",
+	"⇥", "Tab", "⌫", "Backspace", "⎋", "Esc", "⇧", "Shift+", "⌃", "Ctrl+", "⌥", "Alt+", "⌘", "Cmd+",
+	"—", "-", "–", "-", "…", "...", "“", `"`, "”", `"`, "‘", "'", "’", "'", "`", "",
+)
+
+// Render returns the table as a PDF document.
+func (t Table) Render() []byte {
+	widths := t.columnWidths()
+	var rule []string
+	for _, w := range widths {
+		rule = append(rule, strings.Repeat("-", w))
+	}
+	header := append(formatRow(t.Header, widths), formatRow(rule, widths)...)
+
+	// Lines left on a page below the title (first page) and the header
+	perPage := int((pageHeight-2*margin)/lineHeight) - len(header) - 1
+	firstPage := perPage - int(2*titleSize/lineHeight)
+	var pages [][]string
+	var page []string
+	limit := firstPage
+	for _, row := range t.Rows {
+		lines := formatRow(row, widths)
+		if len(page) > 0 && len(page)+len(lines) > limit {
+			pages = append(pages, page)
+			page, limit = nil, perPage
+		}
+		page = append(page, lines...)
+	}
+	pages = append(pages, page)
+
+	var streams []string
+	for i, lines := range pages {
+		var sb strings.Builder
+		y := float64(pageHeight - margin - fontSize)
+		if i == 0 && t.Title != "" {
+			fmt.Fprintf(&sb, "BT /F2 %d Tf %d %.0f Td (%s) Tj ET\n", titleSize, margin, y-titleSize+fontSize, escape(t.Title))
+			y -= 2 * titleSize
+		}
+		fmt.Fprintf(&sb, "BT /F2 %d Tf %d TL %d %.0f Td\n", fontSize, lineHeight, margin, y)
+		for _, line := range header {
+			fmt.Fprintf(&sb, "(%s) Tj T*\n", escape(line))
+		}
+		fmt.Fprintf(&sb, "/F1 %d Tf\n", fontSize)
+		for _, line := range lines {
+			fmt.Fprintf(&sb, "(%s) Tj T*\n", escape(line))
+		}
+		sb.WriteString("ET\n")
+		footer := fmt.Sprintf("%d / %d", i+1, len(pages))
+		fmt.Fprintf(&sb, "BT /F1 %d Tf %.1f %d Td (%s) Tj ET\n", fontSize, pageWidth-margin-float64(len(footer))*charWidth, margin/2, footer)
+		streams = append(streams, sb.String())
+	}
+	return document(streams)
+}
+
+// columnWidths returns the width of each column in characters: the width of
+// its widest cell, with the widest columns narrowed until the table fits the
+// page.
+func (t Table) columnWidths() []int {
+	widths := make([]int, len(t.Header))
+	for i, h := range t.Header {
+		widths[i] = max(utf8.RuneCountInString(clean(h)), 1)
+	}
+	for _, row := range t.Rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], utf8.RuneCountInString(clean(cell)))
+			}
+		}
+	}
+	total := func() int {
+		n := columnGap * (len(widths) - 1)
+		for _, w := range widths {
+			n += w
+		}
+		return n
+	}
+	for total() > maxLineChar {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 4 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// formatRow lays a row out in columns of widths, wrapping cells at spaces
+// where they can, and returns its lines.
+func formatRow(row []string, widths []int) []string {
+	cells := make([][]string, len(widths))
+	height := 1
+	for i, w := range widths {
+		var cell string
+		if i < len(row) {
+			cell = clean(row[i])
+		}
+		cells[i] = wrap(cell, w)
+		height = max(height, len(cells[i]))
+	}
+	lines := make([]string, height)
+	for l := range lines {
+		var sb strings.Builder
+		for i, w := range widths {
+			var part string
+			if l < len(cells[i]) {
+				part = cells[i][l]
+			}
+			sb.WriteString(part)
+			if i < len(widths)-1 {
+				sb.WriteString(strings.Repeat(" ", w-utf8.RuneCountInString(part)+columnGap))
+			}
+		}
+		lines[l] = strings.TrimRight(sb.String(), " ")
+	}
+	return lines
+}
+
+// wrap splits text into lines of at most width characters, at spaces where
+// it can.
+func wrap(text string, width int) []string {
+	var lines []string
+	runes := []rune(text)
+	for len(runes) > width {
+		cut := width
+		for i := width; i > 0; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	return append(lines, string(runes))
+}
+
+// clean prepares text for the standard fonts: markdown code spans are
+// unquoted, key symbols spelled out, and the characters the fonts' Latin-1
+// encoding lacks replaced with '?'.
+func clean(text string) string {
+	text = textReplacer.Replace(text)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n':
+			return ' '
+		case r < 0x20 || r > 0xff || (r >= 0x7f && r < 0xa0):
+			return '?'
+		}
+		return r
+	}, text)
+}
+
+// escape encodes text as the contents of a PDF string in Latin-1.
+func escape(text string) string {
+	var sb strings.Builder
+	for _, r := range clean(text) {
+		switch r {
+		case '\\', '(', ')':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		default:
+			if r >= 0x80 {
+				fmt.Fprintf(&sb, "\\%03o", r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// document assembles the PDF objects of pages with the given content
+// streams, with the cross-reference table readers locate them by.
+func document(streams []string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(streams))
+	for i := range streams {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(streams)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, stream := range streams {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,91 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenderStructure(t *testing.T) {
+	var rows [][]string
+	for i := 0; i < 120; i++ {
+		rows = append(rows, []string{"flow-status", "Navigation", fmt.Sprintf("Action %d (move)", i), "`↑`, `k`"})
+	}
+	doc := Table{Title: "Flow Cheat Sheet", Header: []string{"TUI", "Section", "Action", "Keys"}, Rows: rows}.Render()
+
+	if !bytes.HasPrefix(doc, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(doc, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	// Every offset of the cross-reference table points at its object
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllSubmatch(doc, -1)
+	for i, m := range xref {
+		off, _ := strconv.Atoi(string(m[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(doc[off:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, doc[off:min(off+12, len(doc))])
+		}
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(doc)
+	if off, _ := strconv.Atoi(string(startxref[1])); !bytes.HasPrefix(doc[off:], []byte("xref\n")) {
+		t.Error("startxref does not point at the xref table")
+	}
+	if !bytes.Contains(doc, []byte("/Count 3 >>")) {
+		t.Errorf("120 rows not laid out on 3 pages: %s", regexp.MustCompile(`/Count \d+`).Find(doc))
+	}
+	for _, want := range []string{"(Flow Cheat Sheet) Tj", "Action 7 \\(move\\)", "Up, k", "(3 / 3) Tj"} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("document lacks %q", want)
+		}
+	}
+	if bytes.Contains(doc, []byte("`")) {
+		t.Error("code span backticks were kept")
+	}
+}
+
+func TestFormatRow(t *testing.T) {
+	cases := []struct {
+		name   string
+		row    []string
+		widths []int
+		want   []string
+	}{
+		{"padded columns", []string{"a", "bb", "c"}, []int{3, 3, 1}, []string{"a    bb   c"}},
+		{"wraps at spaces", []string{"x", "open the file"}, []int{1, 8}, []string{"x  open the", "   file"}},
+		{"breaks long words", []string{"abcdefgh"}, []int{3}, []string{"abc", "def", "gh"}},
+		{"missing cells are blank", []string{"a"}, []int{1, 2}, []string{"a"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := formatRow(c.row, c.widths); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("formatRow() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestColumnWidthsFitThePage(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+	widths := Table{Header: []string{"A", "B"}, Rows: [][]string{{"short", long}}}.columnWidths()
+	if total := widths[0] + widths[1] + columnGap; total > maxLineChar {
+		t.Errorf("widths %v are %d characters, over the %d of a line", widths, total, maxLineChar)
+	}
+	if widths[0] != 5 {
+		t.Errorf("narrow column width = %d, want 5", widths[0])
+	}
+}
+
+func TestEscape(t *testing.T) {
+	cases := map[string]string{
+		`a (b) \c`: `a \(b\) \\c`,
+		"café":     `caf\351`,
+		"⌃c → ✓":   "Ctrl+c Right ?",
+	}
+	for in, want := range cases {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
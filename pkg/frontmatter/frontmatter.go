@@ -0,0 +1,260 @@
+// Package frontmatter validates the frontmatter of generated docs against
+// the schema of the website's content collection, so invalid or missing
+// fields are caught by docgen rather than by a failing website build.
+package frontmatter
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSchema is the schema used when the config has none: the fields the
+// Astro transform writes, with title required as in Starlight's docs schema.
+func DefaultSchema() *config.FrontmatterConfig {
+	return &config.FrontmatterConfig{
+		Fields: map[string]config.FrontmatterField{
+			"title":       {Type: "string", Required: true},
+			"description": {Type: "string"},
+			"package":     {Type: "string"},
+			"version":     {Type: "string"},
+			"category":    {Type: "string"},
+			"order":       {Type: "number"},
+			"head":        {Type: "array"},
+			"placeholder": {Type: "boolean"},
+			"audience":    {Type: "array"},
+		},
+	}
+}
+
+// Problem is one frontmatter field that does not satisfy the schema.
+type Problem struct {
+	File    string `json:"file"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Field == "" {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.File, p.Field, p.Message)
+}
+
+// CheckDir validates every markdown file (.md, .mdx) under dir and returns
+// the problems found, with paths relative to dir, and the number of files
+// checked.
+func CheckDir(dir string, schema *config.FrontmatterConfig) ([]Problem, int, error) {
+	var problems []Problem
+	files := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (filepath.Ext(path) != ".md" && filepath.Ext(path) != ".mdx") {
+			return nil
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // walking the docs directory
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files++
+		problems = append(problems, Check(filepath.ToSlash(rel), data, schema)...)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to check frontmatter in %s: %w", dir, err)
+	}
+	return problems, files, nil
+}
+
+// Check validates the frontmatter of one document.
+func Check(file string, content []byte, schema *config.FrontmatterConfig) []Problem {
+	fields, err := Parse(content)
+	if err != nil {
+		return []Problem{{File: file, Message: err.Error()}}
+	}
+	var problems []Problem
+	report := func(field, format string, args ...interface{}) {
+		problems = append(problems, Problem{File: file, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	names := make([]string, 0, len(schema.Fields))
+	for name := range schema.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec := schema.Fields[name]
+		value, ok := fields[name]
+		if !ok || value == nil {
+			if spec.Required {
+				report(name, "required field is missing")
+			}
+			continue
+		}
+		if msg := checkValue(value, spec); msg != "" {
+			report(name, "%s", msg)
+		}
+	}
+
+	if schema.Strict {
+		var unknown []string
+		for name := range fields {
+			if _, ok := schema.Fields[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			report(name, "field is not in the schema")
+		}
+	}
+	return problems
+}
+
+// Parse returns the frontmatter fields of a document; a document without
+// frontmatter has none.
+func Parse(content []byte) (map[string]interface{}, error) {
+	s := strings.ReplaceAll(string(content), "\r\n", "\n")
+	if !strings.HasPrefix(s, "---\n") {
+		return map[string]interface{}{}, nil
+	}
+	end := strings.Index(s[4:], "\n---")
+	if end == -1 {
+		return nil, fmt.Errorf("frontmatter is not closed")
+	}
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(s[4:4+end]), &fields); err != nil {
+		return nil, fmt.Errorf("invalid frontmatter YAML: %w", err)
+	}
+	return fields, nil
+}
+
+// Audience returns the audience field of a document's frontmatter, written
+// as a list or a single string; a document without one has none.
+func Audience(content []byte) []string {
+	fields, err := Parse(content)
+	if err != nil {
+		return nil
+	}
+	switch v := fields["audience"].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		audience := make([]string, 0, len(v))
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				audience = append(audience, s)
+			}
+		}
+		return audience
+	}
+	return nil
+}
+
+// SectionAudience returns the audience of a section: its config's, or else
+// that of the frontmatter of its doc at docPath.
+func SectionAudience(section config.SectionConfig, docPath string) []string {
+	if len(section.Audience) > 0 {
+		return section.Audience
+	}
+	content, err := os.ReadFile(docPath) //nolint:gosec // path from config
+	if err != nil {
+		return nil
+	}
+	return Audience(content)
+}
+
+// checkValue returns why value does not satisfy spec, or "".
+func checkValue(value interface{}, spec config.FrontmatterField) string {
+	switch spec.Type {
+	case "string", "":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("expected a string, got %s", typeName(value))
+		}
+		if len(spec.Enum) > 0 && !contains(spec.Enum, s) {
+			return fmt.Sprintf("%q is not one of %s", s, strings.Join(spec.Enum, ", "))
+		}
+		if spec.Pattern != "" {
+			re, err := regexp.Compile(spec.Pattern)
+			if err != nil {
+				return fmt.Sprintf("invalid pattern %q in schema: %v", spec.Pattern, err)
+			}
+			if !re.MatchString(s) {
+				return fmt.Sprintf("%q does not match %s", s, spec.Pattern)
+			}
+		}
+	case "number":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+		default:
+			return fmt.Sprintf("expected a number, got %s", typeName(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("expected a boolean, got %s", typeName(value))
+		}
+	case "date":
+		switch v := value.(type) {
+		case time.Time:
+		case string:
+			if _, err := time.Parse("2006-01-02", v); err != nil {
+				if _, err := time.Parse(time.RFC3339, v); err != nil {
+					return fmt.Sprintf("%q is not a date", v)
+				}
+			}
+		default:
+			return fmt.Sprintf("expected a date, got %s", typeName(value))
+		}
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			return fmt.Sprintf("expected a list, got %s", typeName(value))
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Sprintf("expected a mapping, got %s", typeName(value))
+		}
+	default:
+		return fmt.Sprintf("unknown type %q in schema", spec.Type)
+	}
+	return ""
+}
+
+// typeName names a decoded YAML value's type for messages.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "a string"
+	case int, int64, uint64, float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case time.Time:
+		return "a date"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a mapping"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/discovery"
+	"github.com/grovetools/docgen/pkg/report"
+)
+
+// AllOptions configures GenerateAllContext.
+type AllOptions struct {
+	// GenerateOptions is applied to every package. Sections and
+	// UsageJSONPath are per package and must be empty.
+	GenerateOptions
+	// Concurrency is the number of packages generated at once (default 1).
+	// Packages sharing a repository should also set Isolate, so their
+	// context builds do not overwrite each other.
+	Concurrency int
+}
+
+// GenerateAllContext generates every docgen-enabled package of the
+// ecosystems configured in dir's docgen config, the package set watch and
+// aggregate discover. Each package runs in its own Generator and records
+// into its own report, folded into g's report as it finishes. A failed
+// package does not stop the others unless FailFast is set; the returned
+// error names every failed package.
+func (g *Generator) GenerateAllContext(ctx context.Context, dir string, opts AllOptions) error {
+	if len(opts.Sections) > 0 || opts.UsageJSONPath != "" {
+		return fmt.Errorf("sections and usage reports are per package: they cannot be combined with generating every package")
+	}
+	packages, err := g.Packages(dir)
+	if err != nil {
+		return err
+	}
+
+	workers := opts.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(packages) {
+		workers = len(packages)
+	}
+	g.logger.Infof("Generating %d package(s), %d at a time", len(packages), workers)
+
+	var (
+		mu     sync.Mutex
+		failed []string
+		next   = make(chan discovery.Package)
+		wg     sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pkg := range next {
+				if err := g.generatePackage(ctx, pkg, opts); err != nil {
+					g.logger.Errorf("Package %s failed: %v", pkg.Name, err)
+					mu.Lock()
+					failed = append(failed, pkg.Name)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, pkg := range packages {
+		mu.Lock()
+		stop := opts.FailFast && len(failed) > 0
+		mu.Unlock()
+		if stop || ctx.Err() != nil {
+			break
+		}
+		next <- pkg
+	}
+	close(next)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("generation cancelled: %w", err)
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("%d of %d package(s) failed: %s", len(failed), len(packages), strings.Join(failed, ", "))
+	}
+	g.logger.Infof("Generated %d package(s)", len(packages))
+	return nil
+}
+
+// Packages returns the docgen-enabled packages of the ecosystems configured
+// in dir's docgen config, the packages GenerateAllContext generates.
+func (g *Generator) Packages(dir string) ([]discovery.Package, error) {
+	localCfg, _, _ := config.LoadWithNotebook(dir)
+	disc := discovery.New(g.logger)
+	ecosystems, err := disc.Ecosystems(localCfg)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := disc.Packages(ecosystems, discovery.Options{Allowed: discovery.AllowedPackages(localCfg)})
+	if err != nil {
+		return nil, err
+	}
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("no docgen-enabled packages found in %d ecosystem(s)", len(ecosystems))
+	}
+	return packages, nil
+}
+
+// generatePackage generates one package of GenerateAllContext.
+func (g *Generator) generatePackage(ctx context.Context, pkg discovery.Package, opts AllOptions) (err error) {
+	var rec *report.Recorder
+	if g.report != nil {
+		rec = report.New("generate")
+		defer func() { g.report.AddRun(pkg.Name, rec.Finish(err)) }()
+	}
+	child := New(g.logger)
+	child.SetReport(rec)
+	child.SetProgress(g.progress)
+	child.SetAnswerFunc(g.answer)
+	child.SetEventLogger(g.ulog)
+	g.logger.Infof("Generating %s (%s)", pkg.Name, pkg.Path)
+	return child.GenerateContext(ctx, pkg.Path, opts.GenerateOptions)
+}
@@ -0,0 +1,16 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// checkAnchors warns about in-page links in a generated section that point at
+// no heading, which models produce when they link to a heading they renamed
+// or never wrote.
+func (g *Generator) checkAnchors(section, output string) {
+	if broken := transformer.BrokenAnchorLinks([]byte(output)); len(broken) > 0 {
+		g.recordWarning("Section %q links to missing anchors: %s", section, strings.Join(broken, ", "))
+	}
+}
@@ -0,0 +1,337 @@
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/batch"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/credentials"
+	"github.com/grovetools/grove-anthropic/pkg/anthropic"
+	"github.com/sirupsen/logrus"
+)
+
+// BatchPollInterval is how often a batch run checks on its batches.
+var BatchPollInterval = 30 * time.Second
+
+// newBatchClient returns the batch client of a provider; tests replace it.
+var newBatchClient = batch.New
+
+// errBatchQueued fails a section's LLM request while a batch run collects
+// the requests to submit.
+var errBatchQueued = errors.New("request queued for the batch")
+
+// batchRun is the state of a --batch run. While collecting, CallLLM records
+// the requests batch APIs can serve and answers every request with
+// errBatchQueued; afterwards, it answers the recorded requests with their
+// batch results and makes the others directly.
+type batchRun struct {
+	collect  bool
+	requests []batchRequest
+	queued   map[string]bool
+	results  map[string]batch.Result
+}
+
+type batchRequest struct {
+	provider string
+	model    string
+	request  batch.Request
+}
+
+// collecting reports whether the run is collecting requests.
+func (b *batchRun) collecting() bool {
+	return b != nil && b.collect
+}
+
+// batchState records the batches submitted for a set of requests, so an
+// interrupted run resumes waiting on them instead of submitting them again.
+type batchState struct {
+	Package   string            `json:"package"`
+	Submitted time.Time         `json:"submitted"`
+	Batches   map[string]string `json:"batches"` // provider/model -> batch ID
+}
+
+// batchStatePath returns the state file of the requests of a package: in
+// docgen/batches in the user's cache directory, named after the requests.
+func batchStatePath(packageDir string, requests []batchRequest) (string, error) {
+	keys := make([]string, len(requests))
+	for i, r := range requests {
+		keys[i] = r.request.Key
+	}
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(packageDir + "\n" + strings.Join(keys, "\n")))
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no cache directory for batch state: %w", err)
+	}
+	return filepath.Join(cacheDir, "docgen", "batches", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// generateBatch runs GenerateContext in two passes around the providers'
+// batch APIs. The first pass builds every prose section's prompt without
+// writing anything and collects the requests; they are submitted as one
+// batch per provider and model, and waited on. The second pass generates as
+// usual, with the batched requests answered from the batch results.
+// Requests that depend on a response, such as follow-up question rounds,
+// and the requests of models without a batch API are made directly in the
+// second pass.
+func (g *Generator) generateBatch(ctx context.Context, packageDir string, opts GenerateOptions) error {
+	collect := opts
+	collect.FailFast = false
+	collect.UsageJSONPath = ""
+	logger, rec, prog := g.logger, g.report, g.progress
+	quiet := logrus.New()
+	quiet.SetOutput(io.Discard)
+	g.logger, g.report, g.progress = quiet, nil, nil
+	g.batch = &batchRun{collect: true, queued: make(map[string]bool)}
+	err := g.GenerateContext(ctx, packageDir, collect)
+	requests := g.batch.requests
+	g.logger, g.report, g.progress = logger, rec, prog
+	g.batch = nil
+	g.warnings, g.failedSections, g.failedSectionErrors = nil, nil, nil
+	if err != nil {
+		return err
+	}
+
+	results, statePath, err := g.runBatches(ctx, packageDir, requests)
+	if err != nil {
+		return err
+	}
+	g.batch = &batchRun{results: results}
+	defer func() { g.batch = nil }()
+	if err := g.GenerateContext(ctx, packageDir, opts); err != nil {
+		return err
+	}
+	if statePath != "" {
+		_ = os.Remove(statePath)
+	}
+	return nil
+}
+
+// runBatches submits requests, grouped by provider and model, or resumes
+// the batches an earlier run submitted for them, and waits for the results.
+func (g *Generator) runBatches(ctx context.Context, packageDir string, requests []batchRequest) (map[string]batch.Result, string, error) {
+	if len(requests) == 0 {
+		g.logger.Info("No requests to batch; generating directly")
+		return nil, "", nil
+	}
+	statePath, err := batchStatePath(packageDir, requests)
+	if err != nil {
+		return nil, "", err
+	}
+	state := &batchState{Package: packageDir, Submitted: time.Now(), Batches: make(map[string]string)}
+	if data, err := os.ReadFile(statePath); err == nil { //nolint:gosec // docgen's own state file
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, "", fmt.Errorf("failed to read batch state %s: %w", statePath, err)
+		}
+		g.logger.Infof("Resuming the batches submitted %s", state.Submitted.Format(time.RFC3339))
+	}
+
+	groups := make(map[string][]batchRequest)
+	var names []string
+	for _, r := range requests {
+		name := r.provider + "/" + r.model
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], r)
+	}
+	sort.Strings(names)
+
+	clients := make(map[string]batch.Client)
+	for _, name := range names {
+		provider := groups[name][0].provider
+		client, err := newBatchClient(provider, g.providerKey(provider))
+		if err != nil {
+			return nil, "", err
+		}
+		clients[name] = client
+		if state.Batches[name] != "" {
+			continue
+		}
+		reqs := make([]batch.Request, len(groups[name]))
+		for i, r := range groups[name] {
+			reqs[i] = r.request
+		}
+		id, err := client.Submit(ctx, groups[name][0].model, reqs)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to submit the %s batch: %w", name, err)
+		}
+		state.Batches[name] = id
+		if err := writeBatchState(statePath, state); err != nil {
+			return nil, "", err
+		}
+		g.ulog.Info("Submitted batch").
+			Field("batch", id).
+			Field("model", name).
+			Field("requests", len(reqs)).
+			Emit()
+	}
+
+	results := make(map[string]batch.Result)
+	for _, name := range names {
+		id := state.Batches[name]
+		status, err := g.waitBatch(ctx, clients[name], name, id)
+		if err != nil {
+			return nil, "", err
+		}
+		if status.Failed {
+			_ = os.Remove(statePath)
+			return nil, "", fmt.Errorf("batch %s (%s) did not complete: %s", id, name, status.State)
+		}
+		batchResults, err := clients[name].Results(ctx, id)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch the results of batch %s: %w", id, err)
+		}
+		for key, r := range batchResults {
+			results[key] = r
+		}
+		g.ulog.Success("Batch complete").
+			Field("batch", id).
+			Field("model", name).
+			Field("succeeded", status.Succeeded).
+			Field("errored", status.Errored).
+			Emit()
+	}
+	return results, statePath, nil
+}
+
+// waitBatch polls a batch until it is done, reporting its progress as it
+// changes.
+func (g *Generator) waitBatch(ctx context.Context, client batch.Client, name, id string) (batch.Status, error) {
+	last := ""
+	for {
+		status, err := client.Status(ctx, id)
+		if err != nil {
+			return status, fmt.Errorf("failed to check batch %s: %w", id, err)
+		}
+		if status.Done {
+			return status, nil
+		}
+		if line := fmt.Sprintf("%s %d/%d", status.State, status.Succeeded+status.Errored, status.Total); line != last {
+			last = line
+			g.ulog.Progress("Waiting for batch").
+				Field("batch", id).
+				Field("model", name).
+				Field("state", status.State).
+				Field("done", status.Succeeded+status.Errored).
+				Field("total", status.Total).
+				Emit()
+		}
+		timer := time.NewTimer(BatchPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, fmt.Errorf("stopped waiting for batch %s; run again to resume: %w", id, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func writeBatchState(path string, state *batchState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // cache directory
+		return fmt.Errorf("failed to create batch state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// providerKey returns the API key of provider from the environment or the
+// credential store.
+func (g *Generator) providerKey(provider string) string {
+	key, err := credentials.Key(g.credentials, provider)
+	if err != nil && !g.credentialsWarned {
+		g.credentialsWarned = true
+		g.recordWarning("Could not read provider keys from the credential store: %v", err)
+	}
+	return key
+}
+
+// batchCall answers an LLM request of a batch run. handled is false when
+// the request is to be made directly.
+func (g *Generator) batchCall(promptContent, model string, genConfig config.GenerationConfig, workDir string) (response string, handled bool, err error) {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\n%v|%v|%v|%v\n%s", model,
+		deref(genConfig.Temperature), deref(genConfig.TopP), deref(genConfig.TopK), deref(genConfig.MaxOutputTokens), promptContent))
+	key := hex.EncodeToString(sum[:])
+
+	if !g.batch.collect {
+		r, ok := g.batch.results[key]
+		if !ok {
+			return "", false, nil
+		}
+		if r.Err != "" {
+			return "", true, fmt.Errorf("batch request failed: %s", r.Err)
+		}
+		return r.Text, true, nil
+	}
+
+	p, ok := credentials.ProviderForModel(model)
+	if !ok || !batch.Supported(p.Name) || g.batch.queued[key] {
+		return "", true, errBatchQueued
+	}
+	cxContext, _, err := g.sectionContext(workDir)
+	if err != nil {
+		return "", true, err
+	}
+	prompt := withContext(cxContext, promptContent)
+	if p.Name == "anthropic" {
+		model = anthropic.ResolveModelAlias(model)
+	}
+	g.batch.queued[key] = true
+	g.batch.requests = append(g.batch.requests, batchRequest{
+		provider: p.Name,
+		model:    model,
+		request: batch.Request{
+			Key:             key,
+			Prompt:          prompt,
+			Temperature:     genConfig.Temperature,
+			TopP:            genConfig.TopP,
+			TopK:            genConfig.TopK,
+			MaxOutputTokens: genConfig.MaxOutputTokens,
+		},
+	})
+	return "", true, errBatchQueued
+}
+
+// workDirContext returns the cx context grove llm attaches to a request
+// made in workDir, for requests that do not go through grove llm.
+func (g *Generator) workDirContext(workDir string) (string, error) {
+	var sb strings.Builder
+	for _, f := range anthropic.WorkDirContextFiles(g.isolation.path(workDir)) {
+		data, err := os.ReadFile(f) //nolint:gosec // context file generated by cx
+		if err != nil {
+			return "", fmt.Errorf("failed to read context file %s: %w", f, err)
+		}
+		sb.Write(data)
+		sb.WriteString("\n\n")
+	}
+	return sb.String(), nil
+}
+
+// withContext prepends the cx context to a prompt.
+func withContext(cxContext, prompt string) string {
+	if cxContext == "" {
+		return prompt
+	}
+	return cxContext + "---\n\n" + prompt
+}
+
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
@@ -0,0 +1,242 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/batch"
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// fakeBatchClient is a provider batch API holding its batches in memory. A
+// batch reports in_progress for pending polls, then done.
+type fakeBatchClient struct {
+	provider  string
+	submitted map[string][]batch.Request // batch ID -> requests
+	models    map[string]string          // batch ID -> model
+	pending   int                        // Polls reporting in_progress
+	polls     int
+	failed    bool
+	errored   map[string]string // Request key -> error
+	dropped   map[string]bool   // Request keys the batch does not process
+}
+
+func (c *fakeBatchClient) Submit(_ context.Context, model string, requests []batch.Request) (string, error) {
+	id := fmt.Sprintf("%s-batch-%d", c.provider, len(c.submitted)+1)
+	c.submitted[id] = requests
+	c.models[id] = model
+	return id, nil
+}
+
+func (c *fakeBatchClient) Status(_ context.Context, id string) (batch.Status, error) {
+	reqs, ok := c.submitted[id]
+	if !ok {
+		return batch.Status{}, fmt.Errorf("no batch %s", id)
+	}
+	c.polls++
+	if c.polls <= c.pending {
+		return batch.Status{State: "in_progress", Total: len(reqs)}, nil
+	}
+	if c.failed {
+		return batch.Status{State: "expired", Done: true, Failed: true, Total: len(reqs)}, nil
+	}
+	return batch.Status{State: "ended", Done: true, Total: len(reqs), Succeeded: len(reqs) - len(c.errored), Errored: len(c.errored)}, nil
+}
+
+func (c *fakeBatchClient) Results(_ context.Context, id string) (map[string]batch.Result, error) {
+	results := make(map[string]batch.Result)
+	for _, r := range c.submitted[id] {
+		switch {
+		case c.dropped[r.Key]:
+		case c.errored[r.Key] != "":
+			results[r.Key] = batch.Result{Err: c.errored[r.Key]}
+		default:
+			results[r.Key] = batch.Result{Text: "answer to " + r.Prompt}
+		}
+	}
+	return results, nil
+}
+
+// useFakeBatches routes the batch clients of the test to fakes, one per
+// provider, and keeps batch state in a temporary cache directory.
+func useFakeBatches(t *testing.T) map[string]*fakeBatchClient {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	fakes := make(map[string]*fakeBatchClient)
+	for _, p := range batch.Providers {
+		fakes[p] = &fakeBatchClient{provider: p, submitted: make(map[string][]batch.Request), models: make(map[string]string)}
+	}
+	newBatchClient = func(provider, _ string) (batch.Client, error) {
+		return fakes[provider], nil
+	}
+	poll := BatchPollInterval
+	BatchPollInterval = time.Millisecond
+	t.Cleanup(func() {
+		newBatchClient = batch.New
+		BatchPollInterval = poll
+	})
+	return fakes
+}
+
+// collectBatch queues the prompts of models as a collecting batch run would
+// and returns the requests and the keys of the prompts.
+func collectBatch(t *testing.T, g *Generator, calls [][2]string) []string {
+	t.Helper()
+	g.batch = &batchRun{collect: true, queued: make(map[string]bool)}
+	keys := make([]string, len(calls))
+	for i, c := range calls {
+		_, handled, err := g.batchCall(c[1], c[0], config.GenerationConfig{}, t.TempDir())
+		if !handled || !errors.Is(err, errBatchQueued) {
+			t.Fatalf("batchCall(%s) = %v, %v; want the request queued", c[0], handled, err)
+		}
+		if n := len(g.batch.requests); n > 0 {
+			keys[i] = g.batch.requests[n-1].request.Key
+		}
+	}
+	return keys
+}
+
+func TestBatchCollect(t *testing.T) {
+	useFakeBatches(t)
+	g := newTestGenerator()
+	collectBatch(t, g, [][2]string{
+		{"gemini-3-pro-preview", "overview"},
+		{"gemini-3-pro-preview", "overview"}, // The same request is queued once
+		{"claude-sonnet-4-5", "usage"},
+		{"ollama/llama3.1:8b", "local"}, // No batch API: made directly later
+	})
+	var got []string
+	for _, r := range g.batch.requests {
+		got = append(got, r.provider+" "+r.request.Prompt)
+	}
+	if want := []string{"gemini overview", "anthropic usage"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queued %q, want %q", got, want)
+	}
+}
+
+func TestRunBatchesSubmitsPerModel(t *testing.T) {
+	fakes := useFakeBatches(t)
+	g := newTestGenerator()
+	collectBatch(t, g, [][2]string{
+		{"gemini-3-pro-preview", "overview"},
+		{"gemini-3-pro-preview", "usage"},
+		{"gemini-2.5-flash", "faq"},
+		{"gpt-5", "api"},
+	})
+	fakes["gemini"].pending = 2
+
+	results, statePath, err := g.runBatches(context.Background(), "/repo/pkg", g.batch.requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var models []string
+	for id, model := range fakes["gemini"].models {
+		models = append(models, fmt.Sprintf("%s:%d", model, len(fakes["gemini"].submitted[id])))
+	}
+	sort.Strings(models)
+	if want := []string{"gemini-2.5-flash:1", "gemini-3-pro-preview:2"}; !reflect.DeepEqual(models, want) {
+		t.Errorf("gemini batches = %q, want %q", models, want)
+	}
+	if len(fakes["openai"].submitted) != 1 || len(fakes["anthropic"].submitted) != 0 {
+		t.Errorf("batches: openai %d, anthropic %d", len(fakes["openai"].submitted), len(fakes["anthropic"].submitted))
+	}
+	if len(results) != 4 {
+		t.Errorf("%d results, want 4", len(results))
+	}
+
+	var state batchState
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("no batch state: %v", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Package != "/repo/pkg" || len(state.Batches) != 3 {
+		t.Errorf("state = %+v, want the 3 batches of /repo/pkg", state)
+	}
+}
+
+func TestRunBatchesPartialCompletion(t *testing.T) {
+	fakes := useFakeBatches(t)
+	g := newTestGenerator()
+	keys := collectBatch(t, g, [][2]string{
+		{"claude-sonnet-4-5", "overview"},
+		{"claude-sonnet-4-5", "usage"},
+		{"claude-sonnet-4-5", "faq"},
+	})
+	fakes["anthropic"].errored = map[string]string{keys[1]: "overloaded_error"}
+	fakes["anthropic"].dropped = map[string]bool{keys[2]: true}
+
+	results, _, err := g.runBatches(context.Background(), "/repo/pkg", g.batch.requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second pass answers from the results: a success, the request's
+	// error, and a direct request for the one the batch did not process
+	g.batch = &batchRun{results: results}
+	got, handled, err := g.batchCall("overview", "claude-sonnet-4-5", config.GenerationConfig{}, t.TempDir())
+	if !handled || err != nil || !strings.HasSuffix(got, "overview") {
+		t.Errorf("succeeded request = %q, %v, %v", got, handled, err)
+	}
+	if _, handled, err := g.batchCall("usage", "claude-sonnet-4-5", config.GenerationConfig{}, t.TempDir()); !handled || err == nil || !strings.Contains(err.Error(), "overloaded_error") {
+		t.Errorf("errored request = %v, %v; want its error", handled, err)
+	}
+	if _, handled, _ := g.batchCall("faq", "claude-sonnet-4-5", config.GenerationConfig{}, t.TempDir()); handled {
+		t.Error("unprocessed request not left to a direct request")
+	}
+}
+
+func TestRunBatchesResumes(t *testing.T) {
+	fakes := useFakeBatches(t)
+	g := newTestGenerator()
+	collectBatch(t, g, [][2]string{{"gemini-3-pro-preview", "overview"}})
+	requests := g.batch.requests
+
+	// An earlier run submitted the batch and was interrupted
+	id, _ := fakes["gemini"].Submit(context.Background(), "gemini-3-pro-preview", []batch.Request{requests[0].request})
+	statePath, err := batchStatePath("/repo/pkg", requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBatchState(statePath, &batchState{Package: "/repo/pkg", Batches: map[string]string{"gemini/gemini-3-pro-preview": id}}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, _, err := g.runBatches(context.Background(), "/repo/pkg", requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fakes["gemini"].submitted) != 1 {
+		t.Errorf("%d batches submitted, want the earlier one resumed", len(fakes["gemini"].submitted))
+	}
+	if results[requests[0].request.Key].Text == "" {
+		t.Error("no result from the resumed batch")
+	}
+}
+
+func TestRunBatchesFailedBatch(t *testing.T) {
+	fakes := useFakeBatches(t)
+	g := newTestGenerator()
+	collectBatch(t, g, [][2]string{{"gpt-5", "overview"}})
+	fakes["openai"].failed = true
+
+	_, _, err := g.runBatches(context.Background(), "/repo/pkg", g.batch.requests)
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("err = %v, want the batch's failure", err)
+	}
+	statePath, _ := batchStatePath("/repo/pkg", g.batch.requests)
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Error("state of a failed batch kept, so the next run would wait on it again")
+	}
+}
@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/reference"
+)
+
+const workflowSummaryPrompt = `You are writing contributor documentation for a software project.
+
+Below, as JSON, are the project's GitHub Actions workflows with their triggers, jobs, steps, secrets and artifacts. For each workflow write two or three sentences for contributors: what it checks or produces, when it runs, and what a contributor should do when it fails. Do not restate the trigger and job lists verbatim; they are shown next to your text.
+
+Respond with only a JSON object mapping each workflow's file to its summary as a markdown string.`
+
+// generateCIWorkflows documents the repository's GitHub Actions workflows:
+// triggers, jobs, required secrets and uploaded artifacts, parsed from the
+// workflow files, with an LLM summary per workflow unless llm is false.
+func (g *Generator) generateCIWorkflows(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating CI workflow docs: %s", section.Name)
+
+	paths := []string(section.Source)
+	if len(paths) == 0 {
+		paths = []string{reference.WorkflowDir}
+	}
+	workflows, err := reference.Workflows(packageDir, paths)
+	if err != nil {
+		return fmt.Errorf("failed to read workflows: %w", err)
+	}
+	if len(workflows) == 0 {
+		return fmt.Errorf("no workflows found in %s", strings.Join(paths, ", "))
+	}
+
+	var summaries map[string]string
+	if section.UsesLLM() {
+		if err := g.callReferenceLLM(packageDir, section, cfg, workflowSummaryPrompt, workflows, &summaries); err != nil {
+			return err
+		}
+	}
+
+	var sb strings.Builder
+	title := section.Title
+	if title == "" {
+		title = "CI Workflows"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	for _, w := range workflows {
+		fmt.Fprintf(&sb, "## %s\n\n", w.Name)
+		fmt.Fprintf(&sb, "Defined in `%s`.\n\n", w.File)
+		if summary := strings.TrimSpace(summaries[w.File]); summary != "" {
+			sb.WriteString(summary + "\n\n")
+		}
+
+		sb.WriteString("**Triggers:**\n\n")
+		for _, t := range w.Triggers {
+			fmt.Fprintf(&sb, "- %s\n", t)
+		}
+		sb.WriteString("\n")
+
+		sb.WriteString("| Job | Runs on | Needs | Steps |\n")
+		sb.WriteString("| :--- | :--- | :--- | :--- |\n")
+		for _, j := range w.Jobs {
+			name := "`" + j.ID + "`"
+			if j.Name != "" {
+				name = tableCell(j.Name) + " (" + name + ")"
+			}
+			steps := tableCell(strings.Join(j.Steps, "\n"))
+			if j.Uses != "" {
+				steps = "Calls `" + tableCell(j.Uses) + "`"
+			}
+			needs := make([]string, len(j.Needs))
+			for i, n := range j.Needs {
+				needs[i] = "`" + n + "`"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", name, tableCell(j.RunsOn), strings.Join(needs, ", "), steps)
+		}
+		sb.WriteString("\n")
+
+		if len(w.Secrets) > 0 {
+			sb.WriteString("**Required secrets:** ")
+			for i, s := range w.Secrets {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(&sb, "`%s`", s)
+			}
+			sb.WriteString("\n\n")
+		}
+		if len(w.Artifacts) > 0 {
+			sb.WriteString("**Artifacts:** ")
+			for i, a := range w.Artifacts {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(&sb, "`%s`", a)
+			}
+			sb.WriteString("\n\n")
+		}
+	}
+
+	outputPath, err := writeReference(section, outputBaseDir, strings.TrimRight(sb.String(), "\n")+"\n")
+	if err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote %d workflows to %s", len(workflows), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("workflows", len(workflows)).
+		Emit()
+	return nil
+}
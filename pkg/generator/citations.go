@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/grovetools/docgen/pkg/citations"
+)
+
+// checkCitations verifies the source citations of a section written to
+// outputPath with settings.citations, warning about each cited file or line
+// range that does not exist under packageDir, and records them in the doc's
+// citations sidecar for review.
+func (g *Generator) checkCitations(section, output, outputPath, packageDir string) {
+	cites := citations.Parse(output)
+	if len(cites) == 0 {
+		g.recordWarning("Section %q has no source citations", section)
+	}
+	for _, c := range citations.Verify(cites, packageDir) {
+		g.recordWarning("Section %q cites %s (line %d of the doc): %s", section, citeRef(c), c.Line, c.Problem)
+	}
+	if err := citations.WriteSidecar(outputPath, cites); err != nil {
+		g.recordWarning("Failed to write the citations of section %q: %v", section, err)
+		return
+	}
+	g.logger.Debugf("Recorded %d citation(s) of section '%s' in %s", len(cites), section, citations.SidecarPath(outputPath))
+}
+
+// citeRef formats a citation as it is written in the doc.
+func citeRef(c citations.Citation) string {
+	switch {
+	case c.Start == 0:
+		return c.File
+	case c.End == c.Start:
+		return fmt.Sprintf("%s:%d", c.File, c.Start)
+	}
+	return fmt.Sprintf("%s:%d-%d", c.File, c.Start, c.End)
+}
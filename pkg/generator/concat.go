@@ -0,0 +1,219 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+var (
+	concatHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	concatFenceRe   = regexp.MustCompile("^\\s*(```|~~~)")
+	slugUnsafeRe    = regexp.MustCompile(`[^\p{L}\p{N}\s_-]+`)
+)
+
+// concatLast returns sections with concat sections moved to the end, keeping
+// the relative order otherwise, so they read the other sections' fresh output.
+func concatLast(sections []config.SectionConfig) []config.SectionConfig {
+	ordered := make([]config.SectionConfig, 0, len(sections))
+	var concats []config.SectionConfig
+	for _, s := range sections {
+		if s.Type == "concat" {
+			concats = append(concats, s)
+			continue
+		}
+		ordered = append(ordered, s)
+	}
+	return append(ordered, concats...)
+}
+
+// concatSelection returns the markdown sections a concat section combines, in
+// section order: every other markdown section of the config, narrowed by the
+// section's include/exclude name globs.
+func concatSelection(section config.SectionConfig, cfg *config.DocgenConfig) []config.SectionConfig {
+	matches := func(patterns []string, name string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var selected []config.SectionConfig
+	for _, s := range cfg.Sections {
+		if s.Type == "concat" || !strings.HasSuffix(s.Output, ".md") {
+			continue
+		}
+		if len(section.Include) > 0 && !matches(section.Include, s.Name) {
+			continue
+		}
+		if matches(section.Exclude, s.Name) {
+			continue
+		}
+		selected = append(selected, s)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Order < selected[j].Order
+	})
+	return selected
+}
+
+// generateFromConcat writes one markdown file holding the selected sections'
+// generated output under a single title. Each section's headings are shifted
+// so its top level sits at H2 below the document title, and a table of
+// contents down to settings.toc_depth is generated from the shifted headings.
+func (g *Generator) generateFromConcat(section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating concatenated doc: %s", section.Name)
+
+	title := section.Title
+	if title == "" {
+		title = cfg.Title
+	}
+	if title == "" {
+		title = section.Name
+	}
+	tocDepth := cfg.Settings.TocDepth
+	if tocDepth <= 0 {
+		tocDepth = 3
+	}
+
+	slugs := map[string]int{}
+	slugify := func(text string) string {
+		slug := strings.ToLower(strings.TrimSpace(text))
+		slug = slugUnsafeRe.ReplaceAllString(slug, "")
+		slug = strings.ReplaceAll(slug, " ", "-")
+		n := slugs[slug]
+		slugs[slug] = n + 1
+		if n > 0 {
+			return fmt.Sprintf("%s-%d", slug, n)
+		}
+		return slug
+	}
+	slugify(title)
+	slugify("Contents")
+
+	var body, toc strings.Builder
+	included := 0
+	for _, s := range concatSelection(section, cfg) {
+		sourcePath := filepath.Join(outputBaseDir, s.Output)
+		data, err := os.ReadFile(sourcePath) //nolint:gosec // path from config
+		if err != nil {
+			if os.IsNotExist(err) {
+				g.logger.Warnf("Skipping section '%s' in concat: %s has not been generated", s.Name, sourcePath)
+				continue
+			}
+			return fmt.Errorf("failed to read section '%s': %w", s.Name, err)
+		}
+		included++
+
+		sectionTitle := s.Title
+		if sectionTitle == "" {
+			sectionTitle = formatTitle(s.Name)
+		}
+		for _, h := range shiftHeadings(&body, stripFrontmatter(string(data)), sectionTitle) {
+			anchor := slugify(h.text)
+			if h.level <= tocDepth {
+				fmt.Fprintf(&toc, "%s- [%s](#%s)\n", strings.Repeat("  ", h.level-2), h.text, anchor)
+			}
+		}
+		body.WriteString("\n")
+	}
+
+	if included == 0 {
+		return fmt.Errorf("concat section '%s' matched no generated markdown sections", section.Name)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# %s\n\n", title)
+	out.WriteString("## Contents\n\n")
+	out.WriteString(toc.String())
+	out.WriteString("\n")
+	out.WriteString(strings.TrimRight(body.String(), "\n") + "\n")
+
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(out.String()), 0o644); err != nil { //nolint:gosec // generated doc
+		return fmt.Errorf("failed to write concatenated doc: %w", err)
+	}
+
+	g.logger.Infof("Successfully concatenated %d sections into %s", included, outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Emit()
+	return nil
+}
+
+type concatHeading struct {
+	level int
+	text  string
+}
+
+// shiftHeadings writes content to sb with its headings shifted so the
+// shallowest one becomes H2 (capped at H6), and returns the shifted headings.
+// Content without a heading of its own gets an H2 with the section title.
+// Headings inside fenced code blocks are left alone.
+func shiftHeadings(sb *strings.Builder, content, sectionTitle string) []concatHeading {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+
+	minLevel := 0
+	inFence := false
+	for _, line := range lines {
+		if concatFenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if m := concatHeadingRe.FindStringSubmatch(line); m != nil && !inFence {
+			if minLevel == 0 || len(m[1]) < minLevel {
+				minLevel = len(m[1])
+			}
+		}
+	}
+
+	var headings []concatHeading
+	if minLevel == 0 || !startsWithHeading(lines) {
+		headings = append(headings, concatHeading{level: 2, text: sectionTitle})
+		fmt.Fprintf(sb, "## %s\n\n", sectionTitle)
+		if minLevel != 0 {
+			// The section's own headings nest under the added title.
+			minLevel--
+		}
+	}
+
+	inFence = false
+	for _, line := range lines {
+		if concatFenceRe.MatchString(line) {
+			inFence = !inFence
+		}
+		if m := concatHeadingRe.FindStringSubmatch(line); m != nil && !inFence {
+			level := len(m[1]) - minLevel + 2
+			if level > 6 {
+				level = 6
+			}
+			headings = append(headings, concatHeading{level: level, text: m[2]})
+			line = strings.Repeat("#", level) + " " + m[2]
+		}
+		sb.WriteString(line + "\n")
+	}
+	return headings
+}
+
+// startsWithHeading reports whether the first non-blank line is a heading.
+func startsWithHeading(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		return concatHeadingRe.MatchString(line)
+	}
+	return false
+}
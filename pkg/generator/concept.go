@@ -2,9 +2,12 @@ package generator
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"golang.org/x/text/cases"
@@ -21,28 +24,107 @@ type conceptManifest struct {
 	DocgenOrder []string `yaml:"docgen_order"`
 }
 
-// generateFromConcept copies documentation from an nb concept directory.
-// It copies all .md files found in the concept directory to the output location,
-// replacing any existing frontmatter with proper Astro-compatible frontmatter.
-// The source field should contain the concept ID, optionally prefixed with a workspace name
-// (e.g., "my-concept" for the current workspace or "core:my-concept" for cross-workspace).
+// generateFromConcept copies documentation from one or more nb concept directories.
+// It copies the .md files in each concept (including subdirectories) to the output
+// location, replacing any existing frontmatter with proper Astro-compatible frontmatter,
+// and copies other files (images, diagrams) alongside them as assets.
+// The source field holds a concept ID, a glob over concept IDs, or a list of either,
+// each optionally prefixed with a workspace name (e.g., "my-concept", "core:cx-*").
+// Include and exclude narrow the files published from each concept.
 // The output field should specify the base directory (e.g., "concepts/").
 func (g *Generator) generateFromConcept(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
-	g.logger.Infof("Copying concept docs: %s", section.Source)
+	sources := section.Source
+	g.logger.Infof("Copying concept docs: %s", strings.Join(sources, ", "))
 
-	if section.Source == "" {
-		return fmt.Errorf("section type 'nb_concept' requires a 'source' (concept ID)")
+	if len(sources) == 0 {
+		return fmt.Errorf("section type 'nb_concept' requires a 'source' (concept ID, glob, or list)")
 	}
 
-	// Parse source for optional workspace prefix (e.g., "core:my-concept")
-	conceptID := section.Source
-	targetWorkspace := ""
-	if strings.Contains(section.Source, ":") {
-		parts := strings.SplitN(section.Source, ":", 2)
-		targetWorkspace = parts[0]
-		conceptID = parts[1]
+	// Resolve every source pattern to concrete concept directories, keeping
+	// the order given in the config and dropping duplicates.
+	var concepts []conceptRef
+	seen := make(map[string]bool)
+	rootCache := make(map[string]string)
+	for _, source := range sources {
+		targetWorkspace, pattern := "", source
+		if strings.Contains(source, ":") {
+			parts := strings.SplitN(source, ":", 2)
+			targetWorkspace = parts[0]
+			pattern = parts[1]
+		}
+
+		conceptsRoot, ok := rootCache[targetWorkspace]
+		if !ok {
+			var err error
+			conceptsRoot, err = g.resolveConceptsRoot(packageDir, targetWorkspace)
+			if err != nil {
+				return err
+			}
+			rootCache[targetWorkspace] = conceptsRoot
+		}
+
+		ids, err := expandConceptPattern(conceptsRoot, pattern)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			g.logger.Warnf("Concept pattern %q matched no concepts in %s", source, conceptsRoot)
+			continue
+		}
+		for _, id := range ids {
+			dir := filepath.Join(conceptsRoot, id)
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			concepts = append(concepts, conceptRef{id: id, dir: dir})
+		}
+	}
+
+	if len(concepts) == 0 {
+		return fmt.Errorf("no concepts matched source %s", strings.Join(sources, ", "))
+	}
+
+	// Hash state for edit detection: published copies edited since the last
+	// generate are kept rather than overwritten (see `docgen concept`).
+	syncState := loadConceptSync(outputBaseDir)
+	var diverged []string
+
+	// Order counter is shared across concepts so files keep a stable, unique order.
+	order := section.Order * 100
+	for _, concept := range concepts {
+		copied, skipped, err := g.copyConcept(concept, section, cfg, outputBaseDir, len(concepts) == 1, &order, syncState)
+		diverged = append(diverged, skipped...)
+		if err != nil {
+			return err
+		}
+		if copied == 0 && len(skipped) == 0 {
+			g.logger.Warnf("No files published from concept %s", concept.id)
+		}
+	}
+
+	if err := syncState.save(outputBaseDir); err != nil {
+		g.logger.Warnf("Could not save concept sync state: %v", err)
 	}
+	if len(diverged) > 0 {
+		g.ulog.Warn("Published concept docs were edited; not overwriting").
+			Field("files", strings.Join(diverged, ", ")).
+			Emit()
+		g.ulog.Info("Run 'docgen concept pull' to keep the edits or 'docgen concept push' to discard them").Emit()
+	}
+
+	return nil
+}
 
+// conceptRef is a resolved concept directory.
+type conceptRef struct {
+	id  string
+	dir string
+}
+
+// resolveConceptsRoot returns the concepts directory of the named workspace, or
+// of the workspace containing packageDir when targetWorkspace is empty.
+func (g *Generator) resolveConceptsRoot(packageDir, targetWorkspace string) (string, error) {
 	// 1. Resolve Workspace Node
 	var node *workspace.WorkspaceNode
 	var err error
@@ -51,7 +133,7 @@ func (g *Generator) generateFromConcept(packageDir string, section config.Sectio
 		// Cross-workspace: find the target workspace by searching all projects
 		allProjects, err := workspace.GetProjects(g.logger)
 		if err != nil {
-			return fmt.Errorf("could not discover workspaces: %w", err)
+			return "", fmt.Errorf("could not discover workspaces: %w", err)
 		}
 
 		for _, project := range allProjects {
@@ -62,20 +144,20 @@ func (g *Generator) generateFromConcept(packageDir string, section config.Sectio
 		}
 
 		if node == nil {
-			return fmt.Errorf("could not find target workspace '%s'", targetWorkspace)
+			return "", fmt.Errorf("could not find target workspace '%s'", targetWorkspace)
 		}
 	} else {
 		// Current workspace: resolve from package directory
 		node, err = workspace.GetProjectByPath(packageDir)
 		if err != nil {
-			return fmt.Errorf("could not resolve workspace for %s: %w", packageDir, err)
+			return "", fmt.Errorf("could not resolve workspace for %s: %w", packageDir, err)
 		}
 	}
 
 	// 2. Resolve Concepts Directory
 	coreCfg, err := coreConfig.LoadDefault()
 	if err != nil {
-		return fmt.Errorf("could not load core config: %w", err)
+		return "", fmt.Errorf("could not load core config: %w", err)
 	}
 
 	locator := workspace.NewNotebookLocator(coreCfg)
@@ -83,52 +165,147 @@ func (g *Generator) generateFromConcept(packageDir string, section config.Sectio
 	// Get the docgen directory, then navigate up to the workspace level and into concepts
 	docgenDir, err := locator.GetDocgenDir(node)
 	if err != nil {
-		return fmt.Errorf("could not resolve docgen directory: %w", err)
+		return "", fmt.Errorf("could not resolve docgen directory: %w", err)
 	}
 
 	// docgenDir is {notebook_root}/workspaces/{name}/docgen
 	// so concepts is {notebook_root}/workspaces/{name}/concepts
-	workspaceDir := filepath.Dir(docgenDir)
-	conceptDir := filepath.Join(workspaceDir, "concepts", conceptID)
-	if _, err := os.Stat(conceptDir); os.IsNotExist(err) {
-		return fmt.Errorf("concept directory not found: %s", conceptDir)
+	return filepath.Join(filepath.Dir(docgenDir), "concepts"), nil
+}
+
+// expandConceptPattern resolves a concept ID or glob (e.g. "cx-*") to the
+// matching concept directory names, sorted. A plain ID must exist.
+func expandConceptPattern(conceptsRoot, pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		conceptDir := filepath.Join(conceptsRoot, pattern)
+		if _, err := os.Stat(conceptDir); os.IsNotExist(err) {
+			return nil, fmt.Errorf("concept directory not found: %s", conceptDir)
+		}
+		return []string{pattern}, nil
+	}
+
+	entries, err := os.ReadDir(conceptsRoot)
+	if err != nil {
+		return nil, fmt.Errorf("could not read concepts directory: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		matched, err := path.Match(pattern, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("invalid concept pattern %q: %w", pattern, err)
+		}
+		if matched {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// conceptFiles lists a concept's markdown files and assets as slash-separated
+// paths relative to the concept directory, filtered by include/exclude.
+// Markdown files follow the manifest's docgen_order when present.
+func conceptFiles(conceptDir string, include, exclude []string) (mdFiles, assets []string, err error) {
+	var all []string
+	err = filepath.WalkDir(conceptDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != conceptDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(conceptDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "manifest.yaml" || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		if len(include) > 0 && !matchConceptPath(include, rel) {
+			return nil
+		}
+		if matchConceptPath(exclude, rel) {
+			return nil
+		}
+		all = append(all, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read concept directory: %w", err)
 	}
 
-	// 3. Find .md files to process, respecting docgen_order if present
-	var mdFiles []string
+	available := make(map[string]bool)
+	for _, rel := range all {
+		if strings.HasSuffix(rel, ".md") {
+			available[rel] = true
+		} else {
+			assets = append(assets, rel)
+		}
+	}
 
 	// Try to read manifest for docgen_order
 	manifestPath := filepath.Join(conceptDir, "manifest.yaml")
-	if manifestData, err := os.ReadFile(manifestPath); err == nil {
+	if manifestData, err := os.ReadFile(manifestPath); err == nil { //nolint:gosec // path within concept dir
 		var cm conceptManifest
 		if err := yaml.Unmarshal(manifestData, &cm); err == nil && len(cm.DocgenOrder) > 0 {
 			// Use explicit order from manifest - only process listed files
 			for _, f := range cm.DocgenOrder {
-				if _, err := os.Stat(filepath.Join(conceptDir, f)); err == nil {
-					mdFiles = append(mdFiles, f)
+				if available[filepath.ToSlash(f)] {
+					mdFiles = append(mdFiles, filepath.ToSlash(f))
 				}
 			}
+			return mdFiles, assets, nil
 		}
 	}
 
-	// Fall back to all .md files if no docgen_order
-	if len(mdFiles) == 0 {
-		entries, err := os.ReadDir(conceptDir)
-		if err != nil {
-			return fmt.Errorf("could not read concept directory: %w", err)
+	// Fall back to all .md files (WalkDir yields lexical order)
+	for _, rel := range all {
+		if available[rel] {
+			mdFiles = append(mdFiles, rel)
 		}
-		for _, entry := range entries {
-			if entry.IsDir() {
-				continue
-			}
-			if strings.HasSuffix(entry.Name(), ".md") {
-				mdFiles = append(mdFiles, entry.Name())
+	}
+	return mdFiles, assets, nil
+}
+
+// matchConceptPath reports whether rel matches any pattern, either as a whole
+// path or by its base name (so "*.png" matches "images/a.png").
+func matchConceptPath(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+				return true
 			}
 		}
+		// A directory pattern like "drafts/" or "drafts" covers everything beneath it
+		if dir := strings.TrimSuffix(pattern, "/"); strings.HasPrefix(rel, dir+"/") {
+			return true
+		}
 	}
+	return false
+}
 
+// copyConcept publishes one concept's files. It returns the number written and
+// the output paths skipped because their published copy was edited.
+func (g *Generator) copyConcept(concept conceptRef, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string, single bool, order *int, syncState *conceptSyncState) (int, []string, error) {
+	mdFiles, assets, err := conceptFiles(concept.dir, section.Include, section.Exclude)
+	if err != nil {
+		return 0, nil, err
+	}
 	if len(mdFiles) == 0 {
-		return fmt.Errorf("no .md files found in concept directory: %s", conceptDir)
+		if single {
+			return 0, nil, fmt.Errorf("no .md files found in concept directory: %s", concept.dir)
+		}
+		return 0, nil, nil
 	}
 
 	// Determine output directory
@@ -138,18 +315,20 @@ func (g *Generator) generateFromConcept(packageDir string, section config.Sectio
 		outputDir = filepath.Dir(outputDir)
 	}
 	// Ensure it ends with the concept ID for proper nesting
-	if !strings.HasSuffix(outputDir, conceptID) {
-		outputDir = filepath.Join(outputDir, conceptID)
+	if !single || !strings.HasSuffix(outputDir, concept.id) {
+		outputDir = filepath.Join(outputDir, concept.id)
 	}
 
 	// Get package name from config title (e.g., "flow")
 	pkgName := cfg.Title
 	category := cfg.Category
 
-	// 4. Copy each .md file to output with proper frontmatter
-	for i, mdFile := range mdFiles {
-		srcPath := filepath.Join(conceptDir, mdFile)
-		content, err := os.ReadFile(srcPath)
+	copied := 0
+	var skipped []string
+	// Copy each .md file to output with proper frontmatter
+	for _, mdFile := range mdFiles {
+		srcPath := filepath.Join(concept.dir, filepath.FromSlash(mdFile))
+		content, err := os.ReadFile(srcPath) //nolint:gosec // path within concept dir
 		if err != nil {
 			g.logger.Warnf("Could not read %s: %v", srcPath, err)
 			continue
@@ -159,11 +338,11 @@ func (g *Generator) generateFromConcept(packageDir string, section config.Sectio
 		body := stripFrontmatter(string(content))
 
 		// Generate title from filename (e.g., "cli-output-destinations.md" -> "CLI Output Destinations")
-		title := formatTitle(strings.TrimSuffix(mdFile, ".md"))
+		title := formatTitle(strings.TrimSuffix(path.Base(mdFile), ".md"))
 
 		// Create new frontmatter
 		// Order: base order from section + file index
-		order := section.Order*100 + i + 1
+		*order++
 		newFrontmatter := fmt.Sprintf(`---
 title: "%s"
 package: "%s"
@@ -171,13 +350,19 @@ category: "%s"
 order: %d
 ---
 
-`, title, pkgName, category, order)
+`, title, pkgName, category, *order)
 
 		// Combine new frontmatter with body
 		newContent := newFrontmatter + body
 
-		// Write output
-		outputPath := filepath.Join(outputBaseDir, outputDir, mdFile)
+		// Write output, unless the published copy was edited since the last generate
+		rel := filepath.ToSlash(filepath.Join(outputDir, filepath.FromSlash(mdFile)))
+		outputPath := filepath.Join(outputBaseDir, outputDir, filepath.FromSlash(mdFile))
+		if syncState.diverged(rel, outputPath) {
+			g.logger.Warnf("Skipping %s: edited since last generate", outputPath)
+			skipped = append(skipped, rel)
+			continue
+		}
 		if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
 			g.logger.Errorf("Failed to create output directory for %s: %v", mdFile, err)
 			continue
@@ -186,22 +371,54 @@ order: %d
 			g.logger.Errorf("Failed to write output for %s: %v", mdFile, err)
 			continue
 		}
+		syncState.record(rel, conceptSyncEntry{Concept: concept.id, Source: srcPath, Frontmatter: newFrontmatter}, content, []byte(newContent))
+		copied++
 
 		g.logger.Infof("Copied concept doc: %s", outputPath)
-		ulog.Success("Copied concept doc").
+		g.ulog.Success("Copied concept doc").
+			Field("concept", concept.id).
 			Field("file", mdFile).
 			Field("path", outputPath).
 			Emit()
 	}
 
-	return nil
+	// Copy assets unchanged, preserving their subdirectories
+	for _, asset := range assets {
+		srcPath := filepath.Join(concept.dir, filepath.FromSlash(asset))
+		data, err := os.ReadFile(srcPath) //nolint:gosec // path within concept dir
+		if err != nil {
+			g.logger.Warnf("Could not read %s: %v", srcPath, err)
+			continue
+		}
+		rel := filepath.ToSlash(filepath.Join(outputDir, filepath.FromSlash(asset)))
+		outputPath := filepath.Join(outputBaseDir, outputDir, filepath.FromSlash(asset))
+		if syncState.diverged(rel, outputPath) {
+			g.logger.Warnf("Skipping %s: edited since last generate", outputPath)
+			skipped = append(skipped, rel)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+			g.logger.Errorf("Failed to create output directory for %s: %v", asset, err)
+			continue
+		}
+		if err := os.WriteFile(outputPath, data, 0o644); err != nil { //nolint:gosec // published asset
+			g.logger.Errorf("Failed to write asset %s: %v", asset, err)
+			continue
+		}
+		syncState.record(rel, conceptSyncEntry{Concept: concept.id, Source: srcPath}, data, data)
+		copied++
+		g.logger.Debugf("Copied concept asset: %s", outputPath)
+	}
+
+	return copied, skipped, nil
 }
 
+// frontmatterRe matches frontmatter: starts with ---, ends with ---
+var frontmatterRe = regexp.MustCompile(`(?s)^---\n.*?\n---\n*`)
+
 // stripFrontmatter removes YAML frontmatter from markdown content
 func stripFrontmatter(content string) string {
-	// Match frontmatter: starts with ---, ends with ---
-	re := regexp.MustCompile(`(?s)^---\n.*?\n---\n*`)
-	return re.ReplaceAllString(content, "")
+	return frontmatterRe.ReplaceAllString(content, "")
 }
 
 // formatTitle converts a filename to a title
@@ -0,0 +1,296 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConceptSyncFile is the state file written next to published concept docs.
+// It records the hash of each copied file on both sides so edits made to the
+// published copy can be detected instead of being overwritten.
+const ConceptSyncFile = ".concept-sync.json"
+
+// Concept sync states reported by ConceptStatus.
+const (
+	ConceptClean         = "clean"          // Source and published copy match the last sync
+	ConceptSourceChanged = "source-changed" // Source was edited; the next generate updates the copy
+	ConceptEdited        = "edited"         // Published copy was edited; pull to keep the edits
+	ConceptConflict      = "conflict"       // Both sides were edited since the last sync
+	ConceptMissing       = "missing"        // Source or published copy no longer exists
+)
+
+// conceptSyncEntry tracks one published concept file.
+type conceptSyncEntry struct {
+	Concept     string `json:"concept"`
+	Source      string `json:"source"`                // Absolute path of the concept file
+	SourceHash  string `json:"source_hash"`           // Hash of the source at the last sync
+	OutputHash  string `json:"output_hash"`           // Hash of the published copy at the last sync
+	Frontmatter string `json:"frontmatter,omitempty"` // Generated frontmatter for markdown files
+}
+
+type conceptSyncState struct {
+	Files map[string]conceptSyncEntry `json:"files"` // Keyed by slash path relative to the output dir
+}
+
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hashFile(path string) (string, bool) {
+	data, err := os.ReadFile(path) //nolint:gosec // path from sync state
+	if err != nil {
+		return "", false
+	}
+	return hashContent(data), true
+}
+
+func loadConceptSync(outputBaseDir string) *conceptSyncState {
+	state := &conceptSyncState{Files: make(map[string]conceptSyncEntry)}
+	data, err := os.ReadFile(filepath.Join(outputBaseDir, ConceptSyncFile)) //nolint:gosec // output dir from config
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, state); err != nil || state.Files == nil {
+		return &conceptSyncState{Files: make(map[string]conceptSyncEntry)}
+	}
+	return state
+}
+
+func (s *conceptSyncState) save(outputBaseDir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal concept sync state: %w", err)
+	}
+	if err := os.MkdirAll(outputBaseDir, 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputBaseDir, ConceptSyncFile), data, 0o644); err != nil { //nolint:gosec // non-sensitive state file
+		return fmt.Errorf("failed to write concept sync state: %w", err)
+	}
+	return nil
+}
+
+// diverged reports whether the published copy at outputPath was edited since
+// it was last written by docgen. Untracked files are treated as not diverged
+// so the first generate after upgrading takes ownership of them.
+func (s *conceptSyncState) diverged(rel, outputPath string) bool {
+	entry, ok := s.Files[rel]
+	if !ok {
+		return false
+	}
+	hash, exists := hashFile(outputPath)
+	return exists && hash != entry.OutputHash
+}
+
+// record stores the hashes for a file that was just written.
+func (s *conceptSyncState) record(rel string, entry conceptSyncEntry, source, output []byte) {
+	entry.SourceHash = hashContent(source)
+	entry.OutputHash = hashContent(output)
+	s.Files[rel] = entry
+}
+
+// ConceptFileStatus describes the sync state of one published concept file.
+type ConceptFileStatus struct {
+	Path    string `json:"path"`   // Relative to the output directory
+	Output  string `json:"output"` // Absolute path of the published copy
+	Source  string `json:"source"`
+	Concept string `json:"concept"`
+	State   string `json:"state"`
+}
+
+// ConceptSyncOptions configures ConceptStatus, PullConcepts and PushConcepts.
+type ConceptSyncOptions struct {
+	// PublishedDir is where the published copies live when they are not in
+	// the output directory itself (e.g. a website repo's docs/). Paths in the
+	// sync state are resolved relative to it.
+	PublishedDir string
+	// Paths limits the operation to these files (relative to the output dir).
+	Paths []string
+	// Force allows pull/push to resolve conflicts by overwriting the other side.
+	Force bool
+}
+
+// FindConceptSyncDirs returns every directory under root holding a concept
+// sync state file.
+func FindConceptSyncDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && d.Name() == ConceptSyncFile {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// ConceptStatus compares every tracked file in outputBaseDir's sync state
+// against its source and published copy.
+func ConceptStatus(outputBaseDir string, opts ConceptSyncOptions) ([]ConceptFileStatus, error) {
+	state := loadConceptSync(outputBaseDir)
+	if len(state.Files) == 0 {
+		return nil, fmt.Errorf("no concept sync state found in %s", outputBaseDir)
+	}
+
+	filter := make(map[string]bool)
+	for _, p := range opts.Paths {
+		filter[filepath.ToSlash(p)] = true
+	}
+
+	publishedDir := outputBaseDir
+	if opts.PublishedDir != "" {
+		publishedDir = opts.PublishedDir
+	}
+
+	rels := make([]string, 0, len(state.Files))
+	for rel := range state.Files {
+		if len(filter) == 0 || filter[rel] {
+			rels = append(rels, rel)
+		}
+	}
+	sort.Strings(rels)
+
+	statuses := make([]ConceptFileStatus, 0, len(rels))
+	for _, rel := range rels {
+		entry := state.Files[rel]
+		status := ConceptFileStatus{
+			Path:    rel,
+			Output:  filepath.Join(publishedDir, filepath.FromSlash(rel)),
+			Source:  entry.Source,
+			Concept: entry.Concept,
+		}
+		sourceHash, sourceOK := hashFile(entry.Source)
+		outputHash, outputOK := hashFile(status.Output)
+		sourceChanged := sourceHash != entry.SourceHash
+		outputChanged := outputHash != entry.OutputHash
+		switch {
+		case !sourceOK || !outputOK:
+			status.State = ConceptMissing
+		case sourceChanged && outputChanged:
+			status.State = ConceptConflict
+		case outputChanged:
+			status.State = ConceptEdited
+		case sourceChanged:
+			status.State = ConceptSourceChanged
+		default:
+			status.State = ConceptClean
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// PullConcepts copies edits made to published concept docs back into the
+// notebook concept files. Markdown keeps the source's own frontmatter and takes
+// the edited body; assets are copied as-is. Conflicts require opts.Force.
+// With opts.PublishedDir, the copies in outputBaseDir are updated to the
+// published ones as well.
+func PullConcepts(outputBaseDir string, opts ConceptSyncOptions) ([]ConceptFileStatus, error) {
+	return syncConcepts(outputBaseDir, opts, true)
+}
+
+// PushConcepts overwrites edited published copies with the current concept
+// source, discarding the edits. Conflicts require opts.Force. With
+// opts.PublishedDir, the copies in outputBaseDir are rewritten too.
+func PushConcepts(outputBaseDir string, opts ConceptSyncOptions) ([]ConceptFileStatus, error) {
+	return syncConcepts(outputBaseDir, opts, false)
+}
+
+func syncConcepts(outputBaseDir string, opts ConceptSyncOptions, pull bool) ([]ConceptFileStatus, error) {
+	statuses, err := ConceptStatus(outputBaseDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	state := loadConceptSync(outputBaseDir)
+
+	var synced, conflicts []ConceptFileStatus
+	for _, status := range statuses {
+		switch status.State {
+		case ConceptEdited:
+		case ConceptSourceChanged:
+			if pull {
+				continue
+			}
+		case ConceptConflict:
+			if !opts.Force {
+				conflicts = append(conflicts, status)
+				continue
+			}
+		default:
+			continue
+		}
+
+		entry := state.Files[status.Path]
+		source, err := os.ReadFile(entry.Source) //nolint:gosec // path from sync state
+		if err != nil {
+			return synced, fmt.Errorf("failed to read %s: %w", entry.Source, err)
+		}
+		output, err := os.ReadFile(status.Output) //nolint:gosec // path from sync state
+		if err != nil {
+			return synced, fmt.Errorf("failed to read %s: %w", status.Output, err)
+		}
+
+		markdown := strings.HasSuffix(status.Path, ".md")
+		if pull {
+			pulled := output
+			if markdown {
+				pulled = []byte(frontmatterRe.FindString(string(source)) + stripFrontmatter(string(output)))
+			}
+			if err := os.WriteFile(entry.Source, pulled, 0o644); err != nil { //nolint:gosec // notebook concept file
+				return synced, fmt.Errorf("failed to write %s: %w", entry.Source, err)
+			}
+			source = pulled
+		} else {
+			output = source
+			if markdown {
+				output = []byte(entry.Frontmatter + stripFrontmatter(string(source)))
+			}
+			if err := os.WriteFile(status.Output, output, 0o644); err != nil { //nolint:gosec // published doc
+				return synced, fmt.Errorf("failed to write %s: %w", status.Output, err)
+			}
+		}
+		// The state records one output hash for the output directory and
+		// the published directory alike, so the copy in the output
+		// directory must match the published one again: left stale, a
+		// plain status would report it edited and a plain pull would write
+		// it back over the source
+		if local := filepath.Join(outputBaseDir, filepath.FromSlash(status.Path)); local != status.Output {
+			if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil { //nolint:gosec // internal doc tool
+				return synced, fmt.Errorf("failed to create directory for %s: %w", local, err)
+			}
+			if err := os.WriteFile(local, output, 0o644); err != nil { //nolint:gosec // generated doc
+				return synced, fmt.Errorf("failed to write %s: %w", local, err)
+			}
+		}
+		state.record(status.Path, entry, source, output)
+		synced = append(synced, status)
+	}
+
+	if len(synced) > 0 {
+		if err := state.save(outputBaseDir); err != nil {
+			return synced, err
+		}
+	}
+	if len(conflicts) > 0 {
+		paths := make([]string, 0, len(conflicts))
+		for _, c := range conflicts {
+			paths = append(paths, c.Path)
+		}
+		return synced, fmt.Errorf("%d file(s) changed on both sides (use --force to overwrite): %s", len(conflicts), strings.Join(paths, ", "))
+	}
+	return synced, nil
+}
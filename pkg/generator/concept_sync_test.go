@@ -0,0 +1,238 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	conceptSourceFM = "---\ntitle: Cache\nstatus: draft\n---\n"
+	conceptOutputFM = "---\ntitle: Cache\norder: 1\n---\n"
+	conceptRel      = "concepts/cache.md"
+)
+
+// conceptFixture is a concept published by a generate run: its notebook
+// source, the copy in the output directory and one in a website's published
+// directory, with the sync state generate records.
+type conceptFixture struct {
+	outDir, pubDir, source string
+}
+
+func newConceptFixture(t *testing.T) conceptFixture {
+	t.Helper()
+	f := conceptFixture{outDir: t.TempDir(), pubDir: t.TempDir(), source: filepath.Join(t.TempDir(), "cache.md")}
+	source := conceptSourceFM + "Body v1\n"
+	output := conceptOutputFM + "Body v1\n"
+	writeTestFile(t, f.source, source)
+	writeTestFile(t, f.output(false), output)
+	writeTestFile(t, f.output(true), output)
+
+	state := &conceptSyncState{Files: make(map[string]conceptSyncEntry)}
+	state.record(conceptRel, conceptSyncEntry{Concept: "cache", Source: f.source, Frontmatter: conceptOutputFM}, []byte(source), []byte(output))
+	if err := state.save(f.outDir); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func (f conceptFixture) output(published bool) string {
+	if published {
+		return filepath.Join(f.pubDir, filepath.FromSlash(conceptRel))
+	}
+	return filepath.Join(f.outDir, filepath.FromSlash(conceptRel))
+}
+
+func (f conceptFixture) state(t *testing.T, published bool) string {
+	t.Helper()
+	var opts ConceptSyncOptions
+	if published {
+		opts.PublishedDir = f.pubDir
+	}
+	statuses, err := ConceptStatus(f.outDir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 {
+		t.Fatalf("statuses = %+v, want one", statuses)
+	}
+	return statuses[0].State
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestConceptStatus(t *testing.T) {
+	cases := []struct {
+		name           string
+		source, output string // Bodies written now; "-" removes the file
+		want           string
+	}{
+		{"clean", "", "", ConceptClean},
+		{"source edited", "Body v2\n", "", ConceptSourceChanged},
+		{"published copy edited", "", "Body v2\n", ConceptEdited},
+		{"both edited", "Body v2\n", "Body v3\n", ConceptConflict},
+		{"source removed", "-", "", ConceptMissing},
+		{"published copy removed", "", "-", ConceptMissing},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newConceptFixture(t)
+			for path, body := range map[string]string{f.source: c.source, f.output(false): c.output} {
+				switch body {
+				case "":
+				case "-":
+					if err := os.Remove(path); err != nil {
+						t.Fatal(err)
+					}
+				default:
+					writeTestFile(t, path, "---\ntitle: Cache\n---\n"+body)
+				}
+			}
+			if got := f.state(t, false); got != c.want {
+				t.Errorf("state = %s, want %s", got, c.want)
+			}
+			if diverged := loadConceptSync(f.outDir).diverged(conceptRel, f.output(false)); diverged != (c.output != "" && c.output != "-") {
+				t.Errorf("diverged = %v", diverged)
+			}
+		})
+	}
+}
+
+func TestConceptSync(t *testing.T) {
+	cases := []struct {
+		name           string
+		published      bool // Sync against the published directory
+		source, output string
+		sync           func(string, ConceptSyncOptions) ([]ConceptFileStatus, error)
+		force          bool
+		wantSource     string
+		wantOutput     string
+		wantState      string // After the sync; clean when empty
+		wantErr        bool
+	}{
+		{
+			name:       "pull an edited copy",
+			output:     conceptOutputFM + "Edited\n",
+			sync:       PullConcepts,
+			wantSource: conceptSourceFM + "Edited\n",
+			wantOutput: conceptOutputFM + "Edited\n",
+		},
+		{
+			name:       "pull leaves a changed source to generate",
+			source:     conceptSourceFM + "Body v2\n",
+			sync:       PullConcepts,
+			wantSource: conceptSourceFM + "Body v2\n",
+			wantOutput: conceptOutputFM + "Body v1\n",
+			wantState:  ConceptSourceChanged,
+		},
+		{
+			name:       "push over an edited copy",
+			output:     conceptOutputFM + "Edited\n",
+			sync:       PushConcepts,
+			wantSource: conceptSourceFM + "Body v1\n",
+			wantOutput: conceptOutputFM + "Body v1\n",
+		},
+		{
+			name:       "conflict needs force",
+			source:     conceptSourceFM + "Body v2\n",
+			output:     conceptOutputFM + "Edited\n",
+			sync:       PullConcepts,
+			wantSource: conceptSourceFM + "Body v2\n",
+			wantOutput: conceptOutputFM + "Edited\n",
+			wantErr:    true,
+		},
+		{
+			name:       "forced pull of a conflict",
+			source:     conceptSourceFM + "Body v2\n",
+			output:     conceptOutputFM + "Edited\n",
+			sync:       PullConcepts,
+			force:      true,
+			wantSource: conceptSourceFM + "Edited\n",
+			wantOutput: conceptOutputFM + "Edited\n",
+		},
+		{
+			name:       "pull from the published directory",
+			published:  true,
+			output:     conceptOutputFM + "Edited on the website\n",
+			sync:       PullConcepts,
+			wantSource: conceptSourceFM + "Edited on the website\n",
+			wantOutput: conceptOutputFM + "Edited on the website\n",
+		},
+		{
+			name:       "push to the published directory",
+			published:  true,
+			source:     conceptSourceFM + "Body v2\n",
+			output:     conceptOutputFM + "Edited on the website\n",
+			sync:       PushConcepts,
+			force:      true,
+			wantSource: conceptSourceFM + "Body v2\n",
+			wantOutput: conceptOutputFM + "Body v2\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newConceptFixture(t)
+			if c.source != "" {
+				writeTestFile(t, f.source, c.source)
+			}
+			if c.output != "" {
+				writeTestFile(t, f.output(c.published), c.output)
+			}
+			opts := ConceptSyncOptions{Force: c.force}
+			if c.published {
+				opts.PublishedDir = f.pubDir
+			}
+
+			_, err := c.sync(f.outDir, opts)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("sync error = %v, want error %v", err, c.wantErr)
+			}
+			if got := readTestFile(t, f.source); got != c.wantSource {
+				t.Errorf("source = %q, want %q", got, c.wantSource)
+			}
+			if got := readTestFile(t, f.output(c.published)); got != c.wantOutput {
+				t.Errorf("copy = %q, want %q", got, c.wantOutput)
+			}
+			if c.wantErr {
+				return
+			}
+
+			// Once synced, the sides agree wherever status looks: a plain
+			// status after a published-dir sync sees the output directory's
+			// copy, and a plain pull must not undo the sync
+			want := c.wantState
+			if want == "" {
+				want = ConceptClean
+			}
+			if got := f.state(t, false); got != want {
+				t.Errorf("status after sync = %s, want %s", got, want)
+			}
+			if got := f.state(t, true); c.published && got != want {
+				t.Errorf("published-dir status after sync = %s, want %s", got, want)
+			}
+			if _, err := PullConcepts(f.outDir, ConceptSyncOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, f.source); got != c.wantSource {
+				t.Errorf("source after a plain pull = %q, want %q", got, c.wantSource)
+			}
+		})
+	}
+}
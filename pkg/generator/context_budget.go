@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/report"
+)
+
+// sectionBudget is the context budget of the section being generated.
+type sectionBudget struct {
+	*config.ContextBudget
+	topic []string // Terms of the section's name, title and prompt
+}
+
+// useContextBudget sets the context budget of the section about to make its
+// requests; a nil budget lifts it. The topic files are ranked against for
+// top_files and prioritize_topic is taken from the section's name, title
+// and prompt.
+func (g *Generator) useContextBudget(budget *config.ContextBudget, section config.SectionConfig, prompt string) {
+	if budget == nil {
+		g.budget = nil
+		return
+	}
+	g.budget = &sectionBudget{ContextBudget: budget, topic: topicTerms(section.Name + " " + section.Title + " " + prompt)}
+}
+
+// sectionContext returns the cx context of workDir for the open section,
+// reduced to the section's budget: to its most relevant files, and to its
+// cap when it is over it. trimmed reports whether it was reduced.
+func (g *Generator) sectionContext(workDir string) (cxContext string, trimmed bool, err error) {
+	cxContext, err = g.workDirContext(workDir)
+	if err != nil || g.budget == nil {
+		return cxContext, false, err
+	}
+	reduced, trim := g.budget.reduce(cxContext, func(strategy string) {
+		g.recordWarning("context budget of %s: unknown strategy %q", g.currentSection, strategy)
+	})
+	if trim == nil {
+		return cxContext, false, nil
+	}
+	g.logger.Infof("Context of %s trimmed from ~%dk to ~%dk tokens: %d of %d file(s) selected by relevance, %d dropped, %d summarized",
+		g.currentSection, trim.OriginalTokens/1000, trim.Tokens/1000, len(trim.Selected), trim.Files, len(trim.Dropped), len(trim.Summarized))
+	g.ulog.Info("Context trimmed to budget").
+		Field("section", g.currentSection).
+		Field("original_tokens", trim.OriginalTokens).
+		Field("tokens", trim.Tokens).
+		Field("max_tokens", trim.MaxTokens).
+		Field("files", trim.Files).
+		Field("selected", len(trim.Selected)).
+		Field("dropped", len(trim.Dropped)).
+		Field("summarized", len(trim.Summarized)).
+		Emit()
+	g.report.SetContext(trim)
+	return reduced, true, nil
+}
+
+// contextFileRe matches a file of a cx context: the file's contents wrapped
+// in a <file path="..."> element.
+var contextFileRe = regexp.MustCompile(`(?s)<file path="([^"]*)">\n?(.*?)</file>\n*`)
+
+// contextFile is one file of a cx context.
+type contextFile struct {
+	path       string
+	body       string
+	dropped    bool
+	summarized bool
+}
+
+func (f *contextFile) String() string {
+	return fmt.Sprintf("<file path=%q>\n%s</file>\n\n", f.path, f.body)
+}
+
+// parsedContext is a cx context split into its files. Text outside file
+// elements is kept as it is.
+type parsedContext struct {
+	head, tail string
+	files      []*contextFile
+}
+
+func parseContext(cxContext string) *parsedContext {
+	matches := contextFileRe.FindAllStringSubmatchIndex(cxContext, -1)
+	if len(matches) == 0 {
+		return &parsedContext{files: []*contextFile{{body: cxContext}}}
+	}
+	p := &parsedContext{head: cxContext[:matches[0][0]], tail: cxContext[matches[len(matches)-1][1]:]}
+	for _, m := range matches {
+		p.files = append(p.files, &contextFile{path: cxContext[m[2]:m[3]], body: cxContext[m[4]:m[5]]})
+	}
+	return p
+}
+
+func (p *parsedContext) String() string {
+	var sb strings.Builder
+	sb.WriteString(p.head)
+	for _, f := range p.files {
+		if f.dropped {
+			continue
+		}
+		if f.path == "" {
+			sb.WriteString(f.body)
+			continue
+		}
+		sb.WriteString(f.String())
+	}
+	sb.WriteString(p.tail)
+	return sb.String()
+}
+
+func (p *parsedContext) tokens() int {
+	return len(p.String()) / docsBytesPerToken
+}
+
+// reduce keeps the top_files files most relevant to the section, then
+// applies the budget's strategies in order until the context fits its cap.
+// Whatever they leave over the cap is cut from the end of the context. The
+// trim is nil when the context is within the budget as it is.
+func (b *sectionBudget) reduce(cxContext string, unknown func(strategy string)) (string, *report.ContextTrim) {
+	p := parseContext(cxContext)
+	trim := &report.ContextTrim{MaxTokens: b.MaxTokens, OriginalTokens: len(cxContext) / docsBytesPerToken}
+	var named []*contextFile
+	for _, f := range p.files {
+		if f.path != "" {
+			named = append(named, f)
+		}
+	}
+	trim.Files = len(named)
+	overCap := func() bool { return b.MaxTokens > 0 && p.tokens() > b.MaxTokens }
+
+	unselected := make(map[*contextFile]bool)
+	selected := b.TopFiles > 0 && len(named) > b.TopFiles
+	if selected {
+		ranked := rankFiles(b.topic, named)
+		for i, f := range ranked {
+			if i < b.TopFiles {
+				trim.Selected = append(trim.Selected, f.path)
+			} else {
+				f.dropped, unselected[f] = true, true
+			}
+		}
+	}
+	if !selected && !overCap() {
+		return cxContext, nil
+	}
+
+	for _, strategy := range b.Strategies {
+		if !overCap() {
+			break
+		}
+		switch strategy {
+		case config.ContextDropTests:
+			for _, f := range p.files {
+				if isTestFile(f.path) {
+					f.dropped = true
+				}
+			}
+		case config.ContextSummarizeLargeFiles:
+			b.summarizeLargeFiles(p)
+		case config.ContextPrioritizeTopic:
+			b.prioritizeTopic(p)
+		default:
+			unknown(strategy)
+		}
+	}
+	for i := len(p.files) - 1; i > 0 && overCap(); i-- {
+		p.files[i].dropped = true
+	}
+
+	for _, f := range p.files {
+		switch {
+		case f.dropped && f.path != "" && !unselected[f]:
+			trim.Dropped = append(trim.Dropped, f.path)
+		case f.summarized && !f.dropped:
+			trim.Summarized = append(trim.Summarized, f.path)
+		}
+	}
+	reduced := p.String()
+	if limit := b.MaxTokens * docsBytesPerToken; limit > 0 && len(reduced) > limit {
+		reduced = reduced[:strings.LastIndexByte(reduced[:limit], '\n')+1]
+	}
+	trim.Tokens = len(reduced) / docsBytesPerToken
+	return reduced, trim
+}
+
+// summarizeLargeFiles replaces files over a tenth of the budget, largest
+// first, with their outline until the context fits.
+func (b *sectionBudget) summarizeLargeFiles(p *parsedContext) {
+	large := make([]*contextFile, 0, len(p.files))
+	for _, f := range p.files {
+		if !f.dropped && f.path != "" && len(f.body)/docsBytesPerToken > b.MaxTokens/10 {
+			large = append(large, f)
+		}
+	}
+	sort.SliceStable(large, func(i, j int) bool { return len(large[i].body) > len(large[j].body) })
+	for _, f := range large {
+		if p.tokens() <= b.MaxTokens {
+			return
+		}
+		if outline := outlineSource(f.body); len(outline) < len(f.body) {
+			f.body, f.summarized = outline, true
+		}
+	}
+}
+
+// prioritizeTopic drops the files least relevant to the section's topic
+// (see rankFiles) until the context fits.
+func (b *sectionBudget) prioritizeTopic(p *parsedContext) {
+	var kept []*contextFile
+	for _, f := range p.files {
+		if !f.dropped && f.path != "" {
+			kept = append(kept, f)
+		}
+	}
+	ranked := rankFiles(b.topic, kept)
+	for i := len(ranked) - 1; i >= 0 && p.tokens() > b.MaxTokens; i-- {
+		ranked[i].dropped = true
+	}
+}
+
+// isTestFile reports whether path is a test file or test fixture by the
+// naming conventions of common languages.
+func isTestFile(p string) bool {
+	base := path.Base(p)
+	for _, suffix := range []string{"_test.go", "_test.py", "_spec.rb", "_test.rs"} {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	if strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py") {
+		return true
+	}
+	if ext := path.Ext(base); strings.HasSuffix(strings.TrimSuffix(base, ext), ".test") || strings.HasSuffix(strings.TrimSuffix(base, ext), ".spec") {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		switch dir {
+		case "test", "tests", "testdata", "__tests__", "spec", "fixtures":
+			return true
+		}
+	}
+	return false
+}
+
+// outlineSource reduces a source file to its outline: the lines that start
+// at the first column, such as package clauses and the signatures of
+// top-level declarations, with the comments just above them. Indented
+// bodies are replaced with a count of the lines left out.
+func outlineSource(body string) string {
+	lines := strings.Split(body, "\n")
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '}' || line[0] == ')' {
+			continue
+		}
+		keep[i] = true
+	}
+	var sb strings.Builder
+	omitted := 0
+	for i, line := range lines {
+		if !keep[i] {
+			if strings.TrimSpace(line) != "" {
+				omitted++
+			}
+			continue
+		}
+		if omitted > 0 {
+			fmt.Fprintf(&sb, "\t// ... %d line(s) omitted\n", omitted)
+			omitted = 0
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&sb, "\t// ... %d line(s) omitted\n", omitted)
+	}
+	return sb.String()
+}
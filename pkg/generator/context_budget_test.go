@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// testContext builds a cx context of files with the given bodies.
+func testContext(files ...string) string {
+	var sb strings.Builder
+	sb.WriteString("Repository context\n\n")
+	for i := 0; i < len(files); i += 2 {
+		fmt.Fprintf(&sb, "<file path=%q>\n%s</file>\n\n", files[i], files[i+1])
+	}
+	return sb.String()
+}
+
+func TestReduce(t *testing.T) {
+	filler := "func helper() {\n" + strings.Repeat("\tn++\n", 150) + "}\n" // ~260 tokens
+	cxContext := testContext(
+		"db/store.go", "func Open() {}\n"+filler,
+		"auth/login.go", "func Login() {}\n",
+		"auth/login_test.go", "func TestLogin() {}\n"+filler,
+		"auth/session.go", "// login sessions\nfunc NewSession() {}\n",
+		"cmd/root.go", "func Execute() {}\n"+filler,
+	)
+	unknown := func(strategy string) { t.Errorf("unknown strategy %q", strategy) }
+
+	cases := []struct {
+		name              string
+		budget            config.ContextBudget
+		selected, dropped []string
+		summarized        []string
+		kept, left        []string
+		untrimmed         bool
+	}{
+		{
+			name:      "within budget",
+			budget:    config.ContextBudget{MaxTokens: 100000, Strategies: config.DefaultContextStrategies},
+			untrimmed: true,
+		},
+		{
+			name:      "top_files over the file count",
+			budget:    config.ContextBudget{TopFiles: 10},
+			untrimmed: true,
+		},
+		{
+			name:     "top_files without a cap",
+			budget:   config.ContextBudget{TopFiles: 2},
+			selected: []string{"auth/login.go", "auth/login_test.go"},
+			kept:     []string{"auth/login.go", "auth/login_test.go"},
+			left:     []string{"db/store.go", "auth/session.go", "cmd/root.go"},
+		},
+		{
+			name:     "top_files then the cap",
+			budget:   config.ContextBudget{TopFiles: 3, MaxTokens: 200, Strategies: []string{config.ContextDropTests}},
+			selected: []string{"auth/login.go", "auth/login_test.go", "auth/session.go"},
+			dropped:  []string{"auth/login_test.go"},
+			kept:     []string{"auth/login.go", "auth/session.go"},
+			left:     []string{"db/store.go", "auth/login_test.go", "cmd/root.go"},
+		},
+		{
+			name:    "cap without top_files",
+			budget:  config.ContextBudget{MaxTokens: 500, Strategies: []string{config.ContextDropTests, config.ContextPrioritizeTopic}},
+			dropped: []string{"auth/login_test.go", "cmd/root.go"},
+			kept:    []string{"db/store.go", "auth/login.go", "auth/session.go"},
+			left:    []string{"auth/login_test.go", "cmd/root.go"},
+		},
+		{
+			name:       "large files summarized",
+			budget:     config.ContextBudget{MaxTokens: 500, Strategies: []string{config.ContextSummarizeLargeFiles}},
+			summarized: []string{"auth/login_test.go", "cmd/root.go"},
+			kept:       []string{"db/store.go", "auth/login.go", "auth/login_test.go", "auth/session.go", "cmd/root.go"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := &sectionBudget{ContextBudget: &c.budget, topic: []string{"login"}}
+			reduced, trim := b.reduce(cxContext, unknown)
+			if c.untrimmed {
+				if trim != nil || reduced != cxContext {
+					t.Fatalf("context trimmed: %+v", trim)
+				}
+				return
+			}
+			if trim == nil {
+				t.Fatal("context not trimmed")
+			}
+			if trim.Files != 5 {
+				t.Errorf("Files = %d, want 5", trim.Files)
+			}
+			for _, check := range []struct {
+				field     string
+				got, want []string
+			}{{"Selected", trim.Selected, c.selected}, {"Dropped", trim.Dropped, c.dropped}, {"Summarized", trim.Summarized, c.summarized}} {
+				if !reflect.DeepEqual(check.got, check.want) {
+					t.Errorf("%s = %q, want %q", check.field, check.got, check.want)
+				}
+			}
+			for _, path := range c.kept {
+				if !strings.Contains(reduced, fmt.Sprintf("<file path=%q>", path)) {
+					t.Errorf("%s left out of the context", path)
+				}
+			}
+			for _, path := range c.left {
+				if strings.Contains(reduced, fmt.Sprintf("<file path=%q>", path)) {
+					t.Errorf("%s kept in the context", path)
+				}
+			}
+			if !strings.HasPrefix(reduced, "Repository context\n\n") {
+				t.Error("text outside the files was not kept")
+			}
+			if c.budget.MaxTokens > 0 && trim.Tokens > c.budget.MaxTokens {
+				t.Errorf("Tokens = %d, over the cap of %d", trim.Tokens, c.budget.MaxTokens)
+			}
+		})
+	}
+}
+
+func TestReduceUnknownStrategy(t *testing.T) {
+	b := &sectionBudget{ContextBudget: &config.ContextBudget{MaxTokens: 10, Strategies: []string{"shrink"}}}
+	var unknown []string
+	reduced, trim := b.reduce(testContext("a.go", strings.Repeat("x\n", 100)), func(s string) { unknown = append(unknown, s) })
+	if !reflect.DeepEqual(unknown, []string{"shrink"}) {
+		t.Errorf("unknown strategies = %q", unknown)
+	}
+	if trim == nil || len(reduced)/docsBytesPerToken > 10 {
+		t.Errorf("context over the cap: %d bytes", len(reduced))
+	}
+}
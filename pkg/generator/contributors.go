@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"path/filepath"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/contributors"
+)
+
+// generateContributors writes the package's maintainers (from CODEOWNERS) and
+// contributors (from git shortlog) page, with their GitHub avatars under
+// images/avatars next to it when the section sets avatars.
+func (g *Generator) generateContributors(packageDir string, section config.SectionConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating contributors page: %s", section.Name)
+
+	page, err := contributors.Build(g.runContext(), packageDir, contributors.Options{
+		Title:   section.Title,
+		Limit:   section.Limit,
+		Avatars: section.Avatars,
+	})
+	if err != nil {
+		return err
+	}
+	for _, login := range page.Failed {
+		g.recordWarning("Could not fetch the avatar of %s for section %q", login, section.Name)
+	}
+
+	outputPath, err := writeReference(section, outputBaseDir, page.Markdown)
+	if err != nil {
+		return err
+	}
+	if err := page.WriteAvatars(filepath.Dir(outputPath)); err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote contributors page to %s", outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("avatars", len(page.Avatars)).
+		Emit()
+	return nil
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/descriptions"
+)
+
+// RefreshResult reports one descriptions store touched by RefreshDescriptions.
+type RefreshResult struct {
+	Section     string   `json:"section"`
+	Store       string   `json:"store"`
+	Invalidated []string `json:"invalidated"`
+}
+
+// descriptionsStorePath returns the descriptions store a section generates,
+// or "" for sections that don't own one. tui_describe and schema_describe
+// write their store to output; capture sections keep command descriptions in
+// the store named by descriptions.
+func descriptionsStorePath(t SectionTarget) string {
+	switch t.Section.Type {
+	case "tui_describe", "schema_describe":
+		return filepath.Join(t.OutputDir, t.Section.Output)
+	case "capture":
+		if t.Section.Descriptions != "" {
+			return filepath.Join(t.OutputDir, t.Section.Descriptions)
+		}
+	}
+	return ""
+}
+
+// RefreshDescriptions invalidates stored LLM descriptions and regenerates
+// them. only selects entries by ID or glob (e.g. "tui:flow", "schema:settings.*");
+// with none, every entry is regenerated. sections limits the refresh to the
+// named sections. Sections with no matching entry are left untouched unless
+// only is empty.
+func (g *Generator) RefreshDescriptions(packageDir string, sections, only []string) ([]RefreshResult, error) {
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range sections {
+		wanted[name] = true
+	}
+
+	type pending struct {
+		target SectionTarget
+		store  *descriptions.Store
+		result RefreshResult
+	}
+	var work []pending
+	for _, t := range targets {
+		storePath := descriptionsStorePath(t)
+		if storePath == "" {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[t.Name] && !wanted[t.Section.Name] {
+			continue
+		}
+		store, err := descriptions.Load(storePath)
+		if err != nil {
+			return nil, fmt.Errorf("section '%s': %w", t.Name, err)
+		}
+		invalidated := store.Invalidate(only...)
+		if len(only) > 0 && len(invalidated) == 0 {
+			continue
+		}
+		work = append(work, pending{
+			target: t,
+			store:  store,
+			result: RefreshResult{Section: t.Name, Store: storePath, Invalidated: invalidated},
+		})
+	}
+	if len(work) == 0 {
+		if len(only) > 0 {
+			return nil, fmt.Errorf("no stored descriptions match %v", only)
+		}
+		return nil, fmt.Errorf("no descriptions sections found (tui_describe, schema_describe, or capture with descriptions)")
+	}
+
+	rulesPath, err := config.ResolveDocsRulesFile(packageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve docs rules: %w", err)
+	}
+	g.logger.Info("Building context with 'cx generate'...")
+	if err := g.BuildContext(packageDir, rulesPath); err != nil {
+		return nil, fmt.Errorf("failed to build context: %w", err)
+	}
+
+	results := make([]RefreshResult, 0, len(work))
+	for _, w := range work {
+		if err := w.store.Save(); err != nil {
+			return results, err
+		}
+
+		t := w.target
+		switch t.Section.Type {
+		case "tui_describe":
+			err = g.generateTUIDescriptions(packageDir, t.Section, t.Config, t.OutputDir)
+		case "schema_describe":
+			err = g.generateSchemaDescriptions(packageDir, t.Section, t.Config, t.OutputDir)
+		case "capture":
+			err = g.generateFromCapture(packageDir, t.Section, t.Config, t.OutputDir)
+		}
+		if err != nil {
+			return results, fmt.Errorf("section '%s': %w", t.Name, err)
+		}
+		results = append(results, w.result)
+	}
+	return results, nil
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/changelog"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// DigestFile is the page docgen digest writes into the website's overview
+// section.
+const DigestFile = "whats-new.md"
+
+// DigestSection is the website section the digest page belongs to.
+const DigestSection = "overview"
+
+// digestExcerptLimit bounds the excerpt of each changed section in the
+// digest prompt.
+const digestExcerptLimit = 1500
+
+const digestPrompt = `You are writing the "What's new" page of a software ecosystem's documentation website.
+
+Below are the documentation sections that changed recently and the releases published in the same period, grouped by package. Summarize what is new for a user of the ecosystem: lead with the most important changes, group related changes, and say which package each belongs to. Link to a changed section or release with the path given for it. Do not invent changes that are not listed, and leave out changes that only reword existing documentation.
+
+Write markdown without frontmatter. Start with a one-paragraph summary, then one "##" heading per theme or package.
+`
+
+// DigestOptions configures Digest.
+type DigestOptions struct {
+	Since time.Time // Changes at or after this time are included
+	Mode  string    // Only sections published in this mode are included (default: dev)
+	Model string    // Default: the model of the local docgen config
+}
+
+// DigestInput is what changed in a package since the digest's start.
+type DigestInput struct {
+	Package  string
+	Title    string
+	Sections []DigestChange
+	Releases []changelog.Release
+}
+
+// DigestChange is a section whose doc changed.
+type DigestChange struct {
+	Name     string
+	Title    string
+	Path     string // Website path of the section's page
+	Modified time.Time
+	Excerpt  string
+}
+
+// CollectDigest returns, per package of the ecosystems configured in dir's
+// docgen config, the sections whose docs were modified and the changelog
+// releases dated since opts.Since. Packages without changes are left out.
+func (g *Generator) CollectDigest(dir string, opts DigestOptions) ([]DigestInput, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = config.ModeDev
+	}
+	packages, err := g.Packages(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs []DigestInput
+	for _, pkg := range packages {
+		if pkg.Config.Settings.OutputMode == "sections" {
+			continue
+		}
+		in := DigestInput{Package: pkg.Name, Title: pkg.Config.Title}
+		targets, err := ResolveSectionTargets(pkg.Path)
+		if err != nil {
+			g.logger.Debugf("Skipping the docs of %s: %v", pkg.Name, err)
+		}
+		for _, t := range targets {
+			if !config.Published(t.Section.GetStatus(), mode) || t.Section.Output == "" || !strings.HasSuffix(t.Section.Output, ".md") && !transformer.IsMDX(t.Section.Output) {
+				continue
+			}
+			path := filepath.Join(t.OutputDir, t.Section.Output)
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Before(opts.Since) {
+				continue
+			}
+			data, err := os.ReadFile(path) //nolint:gosec // path from config
+			if err != nil {
+				continue
+			}
+			excerpt := strings.TrimSpace(stripFrontmatter(string(data)))
+			if len(excerpt) > digestExcerptLimit {
+				excerpt = excerpt[:digestExcerptLimit] + "\n[...]"
+			}
+			in.Sections = append(in.Sections, DigestChange{
+				Name:     t.Name,
+				Title:    t.Section.Title,
+				Path:     fmt.Sprintf("/docs/%s/%s", pkg.Name, strings.TrimSuffix(strings.TrimSuffix(t.Section.Output, ".mdx"), ".md")),
+				Modified: info.ModTime(),
+				Excerpt:  excerpt,
+			})
+		}
+		if data, err := os.ReadFile(filepath.Join(pkg.Path, "CHANGELOG.md")); err == nil { //nolint:gosec // path from workspace
+			for _, r := range changelog.Released(changelog.Parse(data)) {
+				if date, err := time.Parse(time.DateOnly, r.Date); err == nil && !date.Before(opts.Since.Truncate(24*time.Hour)) {
+					in.Releases = append(in.Releases, r)
+				}
+			}
+		}
+		if len(in.Sections) > 0 || len(in.Releases) > 0 {
+			sort.Slice(in.Sections, func(i, j int) bool { return in.Sections[i].Modified.After(in.Sections[j].Modified) })
+			inputs = append(inputs, in)
+		}
+	}
+	return inputs, nil
+}
+
+// Digest summarizes with the LLM what changed across the ecosystem since
+// opts.Since (see CollectDigest) into a "What's new" page, returned with
+// its frontmatter.
+func (g *Generator) Digest(dir string, opts DigestOptions) (string, error) {
+	inputs, err := g.CollectDigest(dir, opts)
+	if err != nil {
+		return "", err
+	}
+	if len(inputs) == 0 {
+		return "", fmt.Errorf("no docs or releases changed since %s", opts.Since.Format(time.DateOnly))
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(digestPrompt)
+	fmt.Fprintf(&prompt, "\nPeriod: %s to %s\n", opts.Since.Format(time.DateOnly), time.Now().Format(time.DateOnly))
+	for _, in := range inputs {
+		fmt.Fprintf(&prompt, "\n<package name=%q title=%q>\n", in.Package, in.Title)
+		for _, r := range in.Releases {
+			fmt.Fprintf(&prompt, "<release version=%q date=%q path=%q>\n", r.Version, r.Date, fmt.Sprintf("/docs/%s/changelog#%s", in.Package, r.Anchor))
+			for _, c := range r.Changes {
+				fmt.Fprintf(&prompt, "%s:\n", c.Type)
+				for _, e := range c.Entries {
+					fmt.Fprintf(&prompt, "- %s\n", e)
+				}
+			}
+			prompt.WriteString("</release>\n")
+		}
+		for _, s := range in.Sections {
+			fmt.Fprintf(&prompt, "<section title=%q path=%q modified=%q>\n%s\n</section>\n", s.Title, s.Path, s.Modified.Format(time.DateOnly), s.Excerpt)
+		}
+		prompt.WriteString("</package>\n")
+	}
+
+	model := opts.Model
+	localCfg, _, _ := config.LoadWithNotebook(dir)
+	if model == "" && localCfg != nil {
+		model = localCfg.Settings.Model
+	}
+	if model == "" {
+		model = "gemini-3-pro-preview"
+	}
+	var genConfig config.GenerationConfig
+	if localCfg != nil {
+		genConfig = localCfg.Settings.GenerationConfig
+	}
+
+	g.logger.Infof("Summarizing changes in %d package(s) since %s", len(inputs), opts.Since.Format(time.DateOnly))
+	response, err := g.CallLLM(prompt.String(), model, genConfig, dir)
+	if err != nil {
+		return "", fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	frontmatter := fmt.Sprintf("---\ntitle: \"What's New\"\ndescription: \"Changes across the ecosystem since %s\"\n---\n\n", opts.Since.Format(time.DateOnly))
+	return frontmatter + strings.TrimSpace(cleanLLMResponse(response)) + "\n", nil
+}
+
+// DigestPath returns where the digest page of the ecosystems configured in
+// dir's docgen config goes: DigestFile in the docs of the website's
+// overview section, the overview subdirectory of the package with
+// output_mode: sections. It also reports whether the overview config has a
+// section with that output, without which aggregate does not publish it.
+func (g *Generator) DigestPath(dir string) (string, bool, error) {
+	packages, err := g.Packages(dir)
+	if err != nil {
+		return "", false, err
+	}
+	for _, pkg := range packages {
+		if pkg.Config.Settings.OutputMode != "sections" {
+			continue
+		}
+		targets, err := ResolveSectionTargets(pkg.Path)
+		if err != nil {
+			return "", false, err
+		}
+		outputDir := ""
+		for _, t := range targets {
+			if !strings.HasPrefix(t.Name, DigestSection+"/") {
+				continue
+			}
+			outputDir = t.OutputDir
+			if t.Section.Output == DigestFile {
+				return filepath.Join(t.OutputDir, DigestFile), true, nil
+			}
+		}
+		if outputDir != "" {
+			return filepath.Join(outputDir, DigestFile), false, nil
+		}
+	}
+	return "", false, fmt.Errorf("no website %s section found; pass --output", DigestSection)
+}
@@ -0,0 +1,322 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/diff"
+)
+
+// DiffSectionTypes are the section types DiffDocs regenerates: those
+// rendered from code without an LLM. schema_to_md is rendered as
+// deterministic tables whatever its llm setting.
+var DiffSectionTypes = []string{"capture", "schema_to_md", "tui_keymaps"}
+
+// File statuses of a DocFileDiff.
+const (
+	DocFileAdded    = "added"
+	DocFileRemoved  = "removed"
+	DocFileModified = "modified"
+)
+
+// DocDiffOptions configures DiffDocs.
+type DocDiffOptions struct {
+	Base string // Git ref to compare against, e.g. origin/main
+	// BuildCmd is a shell command that builds the package's binaries into
+	// bin/, run in the package directory of both the base and the working
+	// tree. capture sections run the binary built from each tree, and are
+	// skipped without it.
+	BuildCmd string
+	Sections []string // Section names to diff (empty means all)
+}
+
+// DocFileDiff is one file of a section that differs between base and head.
+type DocFileDiff struct {
+	Path    string `json:"path"` // Relative to the section's output directory
+	Status  string `json:"status"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// DocSectionDiff is the diff of one section.
+type DocSectionDiff struct {
+	Section string        `json:"section"`
+	Type    string        `json:"type"`
+	Files   []DocFileDiff `json:"files"`
+	Skipped string        `json:"skipped,omitempty"` // Why the section was not diffed
+	Error   string        `json:"error,omitempty"`   // The section could not be rendered for head
+	// BaseError is why the section could not be rendered for base, for
+	// example a schema the change adds; its files count as added.
+	BaseError string `json:"base_error,omitempty"`
+}
+
+// Changed reports whether the section has a changed file.
+func (s DocSectionDiff) Changed() bool {
+	return len(s.Files) > 0
+}
+
+// DocDiff is the documentation impact of the working tree's changes against
+// a base ref.
+type DocDiff struct {
+	Base     string           `json:"base"`
+	BaseRev  string           `json:"base_rev"`
+	Sections []DocSectionDiff `json:"sections"`
+}
+
+// Changed returns the number of sections with changed files.
+func (d *DocDiff) Changed() int {
+	n := 0
+	for _, s := range d.Sections {
+		if s.Changed() {
+			n++
+		}
+	}
+	return n
+}
+
+// DiffDocs renders the deterministic sections (DiffSectionTypes) of the
+// package at packageDir twice, from a temporary worktree of opts.Base and
+// from the working tree, and diffs the results. Nothing is written to the
+// package's docs. Sections come from the working tree's config and render
+// into temporary directories; descriptions that would need an LLM are left
+// out of captures.
+func (g *Generator) DiffDocs(ctx context.Context, packageDir string, opts DocDiffOptions) (*DocDiff, error) {
+	if opts.Base == "" {
+		return nil, fmt.Errorf("a base ref is required")
+	}
+	g.SetContext(ctx)
+	defer g.SetContext(nil)
+
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool)
+	for _, name := range opts.Sections {
+		wanted[name] = true
+	}
+	var selected []SectionTarget
+	for _, t := range targets {
+		if len(wanted) > 0 && !wanted[t.Name] && !wanted[t.Section.Name] {
+			continue
+		}
+		if slices.Contains(DiffSectionTypes, t.Section.Type) {
+			selected = append(selected, t)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no %s sections to diff", strings.Join(DiffSectionTypes, ", "))
+	}
+
+	out, err := gitOutput(ctx, packageDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("docgen diff needs a git repository: %w", err)
+	}
+	repoRoot := strings.TrimSpace(string(out))
+	out, err = gitOutput(ctx, repoRoot, "rev-parse", "--short", opts.Base+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown base ref %s: %w", opts.Base, err)
+	}
+	baseRev := strings.TrimSpace(string(out))
+	rel, err := filepath.Rel(repoRoot, packageDirInRepo(packageDir))
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "docgen-diff-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck // best-effort cleanup
+	baseRoot := filepath.Join(tmpDir, "base")
+	if _, err := gitOutput(ctx, repoRoot, "worktree", "add", "--detach", baseRoot, baseRev); err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w", opts.Base, err)
+	}
+	defer func() {
+		_, _ = gitOutput(context.Background(), repoRoot, "worktree", "remove", "--force", baseRoot)
+		_, _ = gitOutput(context.Background(), repoRoot, "worktree", "prune")
+	}()
+	baseDir := filepath.Join(baseRoot, rel)
+
+	var baseBuildErr, headBuildErr error
+	if opts.BuildCmd != "" {
+		g.logger.Infof("Building %s: %s", opts.Base, opts.BuildCmd)
+		baseBuildErr = g.runBuild(ctx, baseDir, opts.BuildCmd)
+		g.logger.Infof("Building the working tree: %s", opts.BuildCmd)
+		headBuildErr = g.runBuild(ctx, packageDir, opts.BuildCmd)
+	}
+
+	result := &DocDiff{Base: opts.Base, BaseRev: baseRev}
+	for i, t := range selected {
+		sd := DocSectionDiff{Section: t.Name, Type: t.Section.Type, Files: []DocFileDiff{}}
+		if t.Section.Type == "capture" {
+			switch {
+			case opts.BuildCmd == "":
+				sd.Skipped = "capture needs --build-cmd to build each tree's binary"
+			case headBuildErr != nil:
+				sd.Error = headBuildErr.Error()
+			}
+			if sd.Skipped != "" || sd.Error != "" {
+				result.Sections = append(result.Sections, sd)
+				continue
+			}
+		}
+
+		head, err := g.renderForDiff(packageDir, t, filepath.Join(tmpDir, "out", fmt.Sprint(i), "head"))
+		if err != nil {
+			sd.Error = err.Error()
+			result.Sections = append(result.Sections, sd)
+			continue
+		}
+		base := map[string]string{}
+		if t.Section.Type == "capture" && baseBuildErr != nil {
+			sd.BaseError = baseBuildErr.Error()
+		} else if base, err = g.renderForDiff(baseDir, t, filepath.Join(tmpDir, "out", fmt.Sprint(i), "base")); err != nil {
+			sd.BaseError = err.Error()
+			base = map[string]string{}
+		}
+		sd.Files = diffFiles(base, head)
+		result.Sections = append(result.Sections, sd)
+	}
+	return result, nil
+}
+
+// packageDirInRepo resolves symlinks in dir, so it can be made relative to
+// git's (resolved) top-level directory.
+func packageDirInRepo(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
+
+// runBuild runs a build command through the shell in dir.
+func (g *Generator) runBuild(ctx context.Context, dir, buildCmd string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", buildCmd) //nolint:gosec // user-supplied build command
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", buildCmd) //nolint:gosec // user-supplied build command
+	}
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("build failed in %s: %w\n%s", dir, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// renderForDiff renders a section of the package at packageDir into
+// outputDir and returns its files keyed by path relative to outputDir.
+func (g *Generator) renderForDiff(packageDir string, t SectionTarget, outputDir string) (map[string]string, error) {
+	section := t.Section
+	switch section.Type {
+	case "tui_keymaps":
+		// Descriptions are read, never written: keep reading them from the
+		// real output directory
+		return g.renderTUIKeymaps(packageDir, section, t.Config, t.OutputDir)
+	case "schema_to_md":
+		llm := false
+		section.LLM = &llm
+		if err := g.generateFromSchema(packageDir, section, t.Config, outputDir); err != nil {
+			return nil, err
+		}
+	case "capture":
+		// Describing commands needs an LLM
+		section.Descriptions = ""
+		binary := filepath.Join(packageDir, "bin", filepath.Base(section.Binary))
+		if runtime.GOOS == "windows" && filepath.Ext(binary) == "" {
+			binary += ".exe"
+		}
+		if _, err := os.Stat(binary); err != nil {
+			return nil, fmt.Errorf("the build did not produce %s", binary)
+		}
+		section.Binary = binary
+		if err := g.generateFromCapture(packageDir, section, t.Config, outputDir); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make(map[string]string)
+	err := filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // file rendered into our temp dir
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	return files, err
+}
+
+// diffFiles compares a section's files rendered from base and head.
+func diffFiles(base, head map[string]string) []DocFileDiff {
+	paths := make(map[string]bool)
+	for p := range base {
+		paths[p] = true
+	}
+	for p := range head {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	files := []DocFileDiff{}
+	for _, p := range sorted {
+		oldText, inBase := base[p]
+		newText, inHead := head[p]
+		if inBase && inHead && oldText == newText {
+			continue
+		}
+		f := DocFileDiff{Path: p, Status: DocFileModified}
+		switch {
+		case !inBase:
+			f.Status = DocFileAdded
+		case !inHead:
+			f.Status = DocFileRemoved
+		}
+		if isBinaryDoc(p) {
+			f.Diff = fmt.Sprintf("Binary files a/%s and b/%s differ\n", p, p)
+		} else {
+			f.Added, f.Removed = diff.Stats(oldText, newText)
+			f.Diff = diff.Unified("a/"+p, "b/"+p, oldText, newText)
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// isBinaryDoc reports whether a rendered doc file is binary, such as a PDF
+// cheat sheet, and is compared whole rather than by lines.
+func isBinaryDoc(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".pdf")
+}
@@ -0,0 +1,389 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	coreConfig "github.com/grovetools/core/config"
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/credentials"
+	"github.com/grovetools/docgen/pkg/ollama"
+	anthropic "github.com/grovetools/grove-anthropic/pkg/anthropic"
+)
+
+// Doctor check statuses.
+const (
+	DoctorOK   = "ok"
+	DoctorWarn = "warn"
+	DoctorFail = "fail"
+)
+
+// DoctorCheck is one diagnostic of the generation environment.
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"` // What to do when the check did not pass
+}
+
+// DoctorReport is the outcome of Doctor.
+type DoctorReport struct {
+	PackageDir string        `json:"package_dir"`
+	Checks     []DoctorCheck `json:"checks"`
+	Failed     int           `json:"failed"`
+	Warnings   int           `json:"warnings"`
+}
+
+func (r *DoctorReport) add(c DoctorCheck) {
+	switch c.Status {
+	case DoctorFail:
+		r.Failed++
+	case DoctorWarn:
+		r.Warnings++
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// doctorBinaries are the external commands generation shells out to.
+var doctorBinaries = []struct {
+	name, purpose, install string
+	required               bool
+}{
+	{"grove", "LLM requests (grove llm request) and TUI keybinding registries (grove keys dump)", "install the grove CLI", true},
+	{"cx", "building the code context sent with every prompt (cx generate)", "run 'grove install cx'", true},
+	{"flow", "docgen customize", "run 'grove install flow'", false},
+	{"ffmpeg", "video posters and transcodes (settings.assets.video)", "install ffmpeg (e.g. brew install ffmpeg)", false},
+}
+
+// Doctor checks everything generation for the package at packageDir depends
+// on: external binaries, LLM providers and their API keys, workspace and
+// notebook resolution, the docgen config, output directories and fonts for
+// logo generation. Failed checks carry a suggested fix. It never modifies the
+// package.
+func (g *Generator) Doctor(packageDir string) *DoctorReport {
+	report := &DoctorReport{PackageDir: packageDir}
+
+	for _, bin := range doctorBinaries {
+		path, err := exec.LookPath(bin.name)
+		switch {
+		case err == nil:
+			report.add(DoctorCheck{Name: "binary " + bin.name, Status: DoctorOK, Detail: path})
+		case bin.required:
+			report.add(DoctorCheck{Name: "binary " + bin.name, Status: DoctorFail,
+				Detail: "not found on PATH; needed for " + bin.purpose,
+				Fix:    bin.install + " and make sure " + bin.name + " is on PATH"})
+		default:
+			report.add(DoctorCheck{Name: "binary " + bin.name, Status: DoctorWarn,
+				Detail: "not found on PATH; only needed for " + bin.purpose,
+				Fix:    bin.install + " if you use " + bin.purpose})
+		}
+	}
+
+	node, err := workspace.GetProjectByPath(packageDir)
+	if err != nil {
+		report.add(DoctorCheck{Name: "workspace", Status: DoctorWarn,
+			Detail: fmt.Sprintf("%s is not a registered workspace: %v", packageDir, err),
+			Fix:    "add the project to a grove in ~/.config/grove/grove.yml; without it prompts and config are read from docs/ only"})
+	} else {
+		report.add(DoctorCheck{Name: "workspace", Status: DoctorOK, Detail: node.Name})
+		report.add(doctorNotebook(node))
+	}
+
+	g.doctorConfigFile(report, packageDir, true)
+	report.add(doctorFonts())
+	return report
+}
+
+// ValidateConfig runs the config checks of Doctor alone: the docgen config
+// loads, its sections have outputs and resolvable prompts, its context rules
+// resolve and its output directories are writable. It checks neither the
+// environment nor the models' providers, so it suits CI runs that do not
+// call an LLM.
+func (g *Generator) ValidateConfig(packageDir string) *DoctorReport {
+	report := &DoctorReport{PackageDir: packageDir}
+	g.doctorConfigFile(report, packageDir, false)
+	return report
+}
+
+// doctorConfigFile loads the package's docgen config and checks it, and with
+// models the providers of the models it uses.
+func (g *Generator) doctorConfigFile(report *DoctorReport, packageDir string, models bool) {
+	cfg, configPath, err := config.LoadWithNotebook(packageDir)
+	switch {
+	case os.IsNotExist(err):
+		report.add(DoctorCheck{Name: "config", Status: DoctorFail,
+			Detail: "no " + config.ConfigFileName + " in the notebook or docs/",
+			Fix:    "run 'docgen init' (or 'docgen init --interactive')"})
+	case err != nil:
+		report.add(DoctorCheck{Name: "config", Status: DoctorFail, Detail: err.Error(),
+			Fix: "fix the YAML error; the schema at schema/docgen.config.schema.json enables editor validation"})
+	default:
+		report.add(DoctorCheck{Name: "config", Status: DoctorOK, Detail: configPath})
+		g.doctorConfig(report, packageDir, cfg, models)
+	}
+}
+
+// doctorNotebook checks that the notebook locator resolves for the workspace.
+func doctorNotebook(node *workspace.WorkspaceNode) DoctorCheck {
+	coreCfg, err := coreConfig.LoadDefault()
+	if err != nil {
+		return DoctorCheck{Name: "notebook", Status: DoctorWarn, Detail: fmt.Sprintf("could not load grove config: %v", err),
+			Fix: "check ~/.config/grove/grove.yml"}
+	}
+	dir, err := workspace.NewNotebookLocator(coreCfg).GetDocgenDir(node)
+	if err != nil {
+		return DoctorCheck{Name: "notebook", Status: DoctorWarn, Detail: fmt.Sprintf("notebook locator did not resolve: %v", err),
+			Fix: "configure a notebook in grove.yml to keep prompts out of the repo, or keep using docs/"}
+	}
+	return DoctorCheck{Name: "notebook", Status: DoctorOK, Detail: dir}
+}
+
+// doctorConfig checks the loaded config: section outputs and prompts, the
+// docs context rules, with models the models' providers, and the output
+// directories.
+func (g *Generator) doctorConfig(report *DoctorReport, packageDir string, cfg *config.DocgenConfig, models bool) {
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		report.add(DoctorCheck{Name: "sections", Status: DoctorFail, Detail: err.Error(),
+			Fix: "fix the section configs under the docgen directory"})
+		return
+	}
+	sections := make([]config.SectionConfig, len(targets))
+	for i, t := range targets {
+		sections[i] = t.Section
+	}
+
+	problems := 0
+	if err := validateSectionOutputs(sections); err != nil {
+		report.add(DoctorCheck{Name: "section outputs", Status: DoctorFail, Detail: err.Error(),
+			Fix: "give every section an output: filename, and capture sections a binary:"})
+		problems++
+	}
+	if err := validateSectionPrompts(sections, func(i int, s config.SectionConfig) (string, error) {
+		if cfg.Settings.OutputMode == "sections" {
+			path := filepath.Join(targets[i].ConfigDir, "prompts", s.Prompt)
+			_, err := os.Stat(path)
+			return path, err
+		}
+		return g.resolvePromptPath(packageDir, s.Prompt)
+	}); err != nil {
+		report.add(DoctorCheck{Name: "section prompts", Status: DoctorFail, Detail: err.Error(),
+			Fix: "create the missing prompt files or fix the prompt: paths"})
+		problems++
+	}
+	if problems == 0 {
+		report.add(DoctorCheck{Name: "sections", Status: DoctorOK, Detail: fmt.Sprintf("%d section(s)", len(sections))})
+	}
+
+	if rules, err := config.ResolveDocsRulesFile(packageDir); err != nil {
+		report.add(DoctorCheck{Name: "context rules", Status: DoctorFail, Detail: err.Error(),
+			Fix: "set settings.rules_file to a context preset (for example doc) that cx can resolve"})
+	} else {
+		report.add(DoctorCheck{Name: "context rules", Status: DoctorOK, Detail: rules})
+	}
+
+	if models {
+		for _, c := range doctorModels(cfg, targets) {
+			report.add(c)
+		}
+	}
+
+	dirs := make(map[string]bool)
+	for _, t := range targets {
+		dirs[t.OutputDir] = true
+	}
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+	for _, dir := range sorted {
+		report.add(doctorWritable(dir))
+	}
+}
+
+// doctorModels checks that every model the config uses has a provider: the
+// native Anthropic client for Claude models when its key is set, otherwise
+// grove llm, which needs the provider's API key. Keys count whether they are
+// in the environment or in the credential store (docgen auth login). Local
+// models need their Ollama server running with the model pulled.
+func doctorModels(cfg *config.DocgenConfig, targets []SectionTarget) []DoctorCheck {
+	models := map[string]bool{}
+	if cfg.Settings.Model != "" {
+		models[cfg.Settings.Model] = true
+	}
+	for _, t := range targets {
+		if t.Section.Model != "" {
+			models[t.Section.Model] = true
+		} else if t.Config.Settings.Model != "" {
+			models[t.Config.Settings.Model] = true
+		}
+	}
+	if len(models) == 0 {
+		models["gemini-3-pro-preview"] = true // CallLLM's default
+	}
+	names := make([]string, 0, len(models))
+	for m := range models {
+		names = append(names, m)
+	}
+	sort.Strings(names)
+
+	_, groveErr := exec.LookPath("grove")
+	stored, storeErr := credentials.Env(cfg.Settings.Credentials)
+	var checks []DoctorCheck
+	if storeErr != nil {
+		checks = append(checks, DoctorCheck{Name: "credentials", Status: DoctorWarn, Detail: storeErr.Error(),
+			Fix: "check the credential store with 'docgen auth status'"})
+	}
+	for _, model := range names {
+		var vars []string
+		var provider, set string
+		if p, ok := credentials.ProviderForModel(model); ok {
+			vars, provider = p.EnvVars, p.Name
+			set = p.EnvKey()
+			for _, e := range stored {
+				if strings.HasPrefix(e, p.EnvVars[0]+"=") {
+					set = "stored key"
+				}
+			}
+		}
+		name := "model " + model
+		switch {
+		case ollama.IsModel(model):
+			checks = append(checks, doctorOllama(cfg, model))
+		case anthropic.IsAnthropicModel(model) && set != "" && set != "stored key":
+			// The native client resolves its own key, so a stored key
+			// leaves Claude models to grove llm
+			detail := "native Anthropic client (" + set + ") with cache fan-out"
+			if groveErr != nil {
+				checks = append(checks, DoctorCheck{Name: name, Status: DoctorWarn, Detail: detail + "; sections outside the fan-out need grove",
+					Fix: "install grove for requests the fan-out does not cover"})
+				continue
+			}
+			checks = append(checks, DoctorCheck{Name: name, Status: DoctorOK, Detail: detail})
+		case groveErr != nil:
+			checks = append(checks, DoctorCheck{Name: name, Status: DoctorFail, Detail: "no provider: grove is not on PATH",
+				Fix: "install grove, or use a Claude model with its API key set"})
+		case len(vars) > 0 && set == "":
+			checks = append(checks, DoctorCheck{Name: name, Status: DoctorWarn,
+				Detail: fmt.Sprintf("%s is not set; grove llm fails unless its own config provides the key", strings.Join(vars, " or ")),
+				Fix:    fmt.Sprintf("run 'docgen auth login --provider %s' or export %s=...", provider, vars[0])})
+		default:
+			detail := "grove llm"
+			if set != "" {
+				detail += " (" + set + ")"
+			}
+			checks = append(checks, DoctorCheck{Name: name, Status: DoctorOK, Detail: detail})
+		}
+	}
+	return checks
+}
+
+// doctorOllama checks that the Ollama server of a local model is reachable
+// and has the model.
+func doctorOllama(cfg *config.DocgenConfig, model string) DoctorCheck {
+	name := "model " + model
+	var host string
+	if cfg.Settings.Ollama != nil {
+		host = cfg.Settings.Ollama.Host
+	}
+	client := ollama.New(host)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	version, err := client.Version(ctx)
+	if err != nil {
+		return DoctorCheck{Name: name, Status: DoctorFail, Detail: err.Error(),
+			Fix: "start Ollama ('ollama serve') or set settings.ollama.host"}
+	}
+	window, err := client.ContextWindow(ctx, ollama.ModelName(model))
+	if err != nil {
+		return DoctorCheck{Name: name, Status: DoctorFail, Detail: err.Error(),
+			Fix: fmt.Sprintf("run 'ollama pull %s'", ollama.ModelName(model))}
+	}
+	if cfg.Settings.Ollama != nil && cfg.Settings.Ollama.ContextWindow > 0 {
+		window = cfg.Settings.Ollama.ContextWindow
+	}
+	return DoctorCheck{Name: name, Status: DoctorOK,
+		Detail: fmt.Sprintf("Ollama %s at %s (%d-token window)", version, client.Host(), window)}
+}
+
+// doctorWritable checks that files can be created in dir, or in its nearest
+// existing parent when generation would create dir.
+func doctorWritable(dir string) DoctorCheck {
+	name := "output " + dir
+	existing := dir
+	for {
+		if info, err := os.Stat(existing); err == nil {
+			if !info.IsDir() {
+				return DoctorCheck{Name: name, Status: DoctorFail, Detail: existing + " is not a directory",
+					Fix: "move the file or change settings.output_dir"}
+			}
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+	f, err := os.CreateTemp(existing, ".docgen-doctor-*")
+	if err != nil {
+		return DoctorCheck{Name: name, Status: DoctorFail, Detail: fmt.Sprintf("not writable: %v", err),
+			Fix: "fix the directory's permissions or change settings.output_dir"}
+	}
+	f.Close()           //nolint:errcheck // probe file
+	os.Remove(f.Name()) //nolint:errcheck // probe file
+	if existing != dir {
+		return DoctorCheck{Name: name, Status: DoctorOK, Detail: "will be created in " + existing}
+	}
+	return DoctorCheck{Name: name, Status: DoctorOK, Detail: "writable"}
+}
+
+// fontDirs are the system and user font directories per platform.
+func fontDirs() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"/System/Library/Fonts", "/Library/Fonts", filepath.Join(home, "Library", "Fonts")}
+	case "windows":
+		return []string{filepath.Join(os.Getenv("WINDIR"), "Fonts"), filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft", "Windows", "Fonts")}
+	default:
+		return []string{"/usr/share/fonts", "/usr/local/share/fonts", filepath.Join(home, ".fonts"), filepath.Join(home, ".local", "share", "fonts")}
+	}
+}
+
+// doctorFonts checks that a TTF or OTF font is available for docgen logo,
+// which converts text to paths.
+func doctorFonts() DoctorCheck {
+	count := 0
+	var example string
+	for _, dir := range fontDirs() {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr // unreadable font dirs are skipped
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			if !d.IsDir() && (ext == ".ttf" || ext == ".otf") {
+				if count == 0 {
+					example = path
+				}
+				count++
+			}
+			return nil
+		})
+	}
+	if count == 0 {
+		return DoctorCheck{Name: "fonts", Status: DoctorWarn, Detail: "no TTF/OTF fonts found in the system font directories",
+			Fix: "install a font (for example Fira Code) or pass --font with a font file to docgen logo"}
+	}
+	return DoctorCheck{Name: "fonts", Status: DoctorOK, Detail: fmt.Sprintf("%d font(s), e.g. %s", count, example)}
+}
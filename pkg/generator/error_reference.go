@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/reference"
+)
+
+const errorRemediationPrompt = `You are writing the error reference of a software project's documentation.
+
+Below is every exported error of the project as JSON: its code, kind, message, doc comment and the functions that return it. Using the project context you have been given, write remediation guidance for each error: one to three sentences on what usually causes it and what the user should do about it. Write for users of the project, not its maintainers, and do not invent causes the code does not support.
+
+Respond with only a JSON object mapping each error code to its guidance as a markdown string.`
+
+// generateErrorReference builds a reference of the module's exported sentinel
+// errors, error types and error constructors — with their messages and the
+// functions that return them — and, unless llm is false, asks the LLM for
+// remediation guidance per error.
+func (g *Generator) generateErrorReference(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating error reference: %s", section.Name)
+
+	errs, err := reference.Errors(packageDir, section.Source)
+	if err != nil {
+		return fmt.Errorf("failed to scan errors: %w", err)
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no exported errors found")
+	}
+
+	var remediation map[string]string
+	if section.UsesLLM() {
+		if err := g.callReferenceLLM(packageDir, section, cfg, errorRemediationPrompt, errs, &remediation); err != nil {
+			return err
+		}
+	}
+
+	var sb strings.Builder
+	title := section.Title
+	if title == "" {
+		title = "Error Reference"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+
+	for i := 0; i < len(errs); {
+		pkg := errs[i].Package
+		fmt.Fprintf(&sb, "## `%s`\n\n", pkg)
+		sb.WriteString("| Error | Kind | Message | Returned by |\n")
+		sb.WriteString("| :--- | :--- | :--- | :--- |\n")
+		start := i
+		for ; i < len(errs) && errs[i].Package == pkg; i++ {
+			e := errs[i]
+			message := ""
+			if e.Message != "" {
+				message = "`" + tableCell(e.Message) + "`"
+			}
+			fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n", e.Code, e.Kind, message, tableCell(strings.Join(e.ReturnedBy, "\n")))
+		}
+		sb.WriteString("\n")
+
+		for _, e := range errs[start:i] {
+			guidance := strings.TrimSpace(remediation[e.Code])
+			if guidance == "" && e.Doc == "" {
+				continue
+			}
+			fmt.Fprintf(&sb, "### `%s`\n\n", e.Code)
+			if e.Doc != "" {
+				sb.WriteString(e.Doc + "\n\n")
+			}
+			if guidance != "" {
+				sb.WriteString(guidance + "\n\n")
+			}
+		}
+	}
+
+	outputPath, err := writeReference(section, outputBaseDir, strings.TrimRight(sb.String(), "\n")+"\n")
+	if err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote %d errors to %s", len(errs), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("errors", len(errs)).
+		Emit()
+	return nil
+}
@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grovetools/docgen/internal/clilog"
+)
+
+// TestEventLogger checks milestones reach grove's unified log only from a
+// generator given an event logger, as the commands give theirs.
+func TestEventLogger(t *testing.T) {
+	var out bytes.Buffer
+	restore := clilog.Capture(&out)
+	defer restore()
+
+	g := newTestGenerator()
+	g.recordSectionFailure("overview", errors.New("boom"))
+	if out.Len() != 0 {
+		t.Errorf("generator without an event logger logged:\n%s", out.String())
+	}
+
+	g.SetEventLogger(clilog.New(clilog.Component))
+	g.recordSectionFailure("config", errors.New("boom"))
+	if got := out.String(); !strings.Contains(got, "config") || !strings.Contains(got, "failed") {
+		t.Errorf("event logger output = %q, want the section failure", got)
+	}
+}
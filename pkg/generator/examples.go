@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/examples"
+)
+
+// ExampleReport is the result of CheckExamples.
+type ExampleReport struct {
+	Module  string            `json:"module"`  // Module the examples were compiled against
+	Results []examples.Result `json:"results"` // One per checked ```go block
+	Failing []string          `json:"failing"` // Sections with at least one broken example
+}
+
+// CheckExamples compiles the ```go blocks of the sections' generated docs
+// against the Go module rooted at packageDir and reports the sections whose
+// examples no longer build or vet cleanly. sections limits the check to the
+// named sections.
+func (g *Generator) CheckExamples(packageDir string, sections []string) (*ExampleReport, error) {
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := examples.NewChecker(packageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range sections {
+		wanted[name] = true
+	}
+
+	var blocks []examples.Block
+	for _, t := range targets {
+		if len(wanted) > 0 && !wanted[t.Name] && !wanted[t.Section.Name] {
+			continue
+		}
+		if t.Section.Output == "" || !strings.HasSuffix(t.Section.Output, ".md") {
+			continue
+		}
+		path := filepath.Join(t.OutputDir, t.Section.Output)
+		data, err := os.ReadFile(path) //nolint:gosec // output path from config
+		if err != nil {
+			continue
+		}
+		blocks = append(blocks, examples.Extract(t.Name, path, string(data))...)
+	}
+	g.logger.Infof("Checking %d Go examples", len(blocks))
+
+	results, err := checker.Check(blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExampleReport{Module: packageDir, Results: results, Failing: []string{}}
+	if report.Results == nil {
+		report.Results = []examples.Result{}
+	}
+	failed := make(map[string]bool)
+	for _, r := range results {
+		if !r.OK && !failed[r.Section] {
+			failed[r.Section] = true
+			report.Failing = append(report.Failing, r.Section)
+		}
+	}
+	return report, nil
+}
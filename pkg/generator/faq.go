@@ -0,0 +1,194 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/github"
+)
+
+const faqSystemPrompt = `You are writing the FAQ page of a software project's documentation.
+
+Below are questions users asked in closed GitHub issues and answered discussions, grouped into clusters of similar questions. For each cluster write one FAQ entry:
+- A "### " heading phrasing the question the way a user would ask it.
+- An answer grounded in the project context you have been given (the code and docs) and in the answers in the threads. Prefer what the current code does over what a thread says if they disagree, and leave out clusters whose answer is no longer true.
+- A final line "Related: " linking the source issues and discussions as markdown links.
+
+Group related entries under "## " headings by topic. Start the page with "# Frequently Asked Questions" and output only the markdown page, with no preamble.
+
+`
+
+var (
+	faqWordRe = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+	faqStopwords = map[string]bool{
+		"the": true, "and": true, "for": true, "with": true, "how": true, "can": true,
+		"does": true, "what": true, "when": true, "why": true, "not": true, "use": true,
+		"using": true, "there": true, "this": true, "that": true, "from": true, "into": true,
+		"are": true, "you": true, "way": true, "get": true, "any": true, "should": true,
+	}
+)
+
+// faqCluster is a group of issues asking the same question.
+type faqCluster struct {
+	words  map[string]bool // Title words of the first issue
+	issues []github.Issue
+}
+
+// clusterIssues groups issues whose titles share most of their significant
+// words, in the order they were first seen.
+func clusterIssues(issues []github.Issue) []*faqCluster {
+	var clusters []*faqCluster
+	for _, issue := range issues {
+		words := titleWords(issue.Title)
+		var best *faqCluster
+		bestScore := 0.0
+		for _, c := range clusters {
+			if score := jaccard(words, c.words); score > bestScore {
+				best, bestScore = c, score
+			}
+		}
+		if best != nil && bestScore >= 0.5 {
+			best.issues = append(best.issues, issue)
+			continue
+		}
+		clusters = append(clusters, &faqCluster{words: words, issues: []github.Issue{issue}})
+	}
+	return clusters
+}
+
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range faqWordRe.FindAllString(strings.ToLower(title), -1) {
+		if len(w) > 2 && !faqStopwords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n[...]"
+}
+
+// faqRepo returns the section's repository, or the one origin points to.
+func faqRepo(packageDir string, section config.SectionConfig) (string, error) {
+	if section.Repo != "" {
+		return section.Repo, nil
+	}
+	out, err := exec.Command("git", "-C", packageDir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not determine origin remote (set 'repo'): %w", err)
+	}
+	return github.RepoFromRemote(string(out))
+}
+
+// generateFromIssues builds an FAQ from closed GitHub issues and answered
+// discussions carrying the section's labels. Similar questions are clustered
+// and the LLM writes one answer per cluster, grounded in the package context.
+func (g *Generator) generateFromIssues(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating FAQ from issues: %s", section.Name)
+
+	repo, err := faqRepo(packageDir, section)
+	if err != nil {
+		return err
+	}
+	labels := section.Labels
+	if len(labels) == 0 {
+		labels = []string{"question"}
+	}
+	limit := section.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	client := github.NewClient("")
+	issues, err := client.ClosedIssues(repo, labels, limit)
+	if err != nil {
+		return fmt.Errorf("failed to list issues of %s: %w", repo, err)
+	}
+	if client.Token != "" {
+		discussions, err := client.AnsweredDiscussions(repo, labels, limit)
+		if err != nil {
+			g.logger.Warnf("Skipping discussions of %s: %v", repo, err)
+		}
+		issues = append(issues, discussions...)
+	} else {
+		g.logger.Warn("GITHUB_TOKEN is not set; reading issues only (discussions need a token)")
+	}
+	if len(issues) == 0 {
+		return fmt.Errorf("no closed issues or answered discussions of %s are labeled %s", repo, strings.Join(labels, ", "))
+	}
+
+	clusters := clusterIssues(issues)
+	g.logger.Infof("Clustered %d questions from %s into %d topics", len(issues), repo, len(clusters))
+
+	var sb strings.Builder
+	sb.WriteString(faqSystemPrompt)
+	for i, c := range clusters {
+		fmt.Fprintf(&sb, "<cluster id=\"%d\">\n", i+1)
+		for _, issue := range c.issues {
+			fmt.Fprintf(&sb, "<thread kind=%q url=%q>\nTitle: %s\n\n%s\n", issue.Kind, issue.URL, issue.Title, truncate(issue.Body, 2000))
+			if issue.Answer != "" {
+				fmt.Fprintf(&sb, "\nAccepted answer:\n%s\n", truncate(issue.Answer, 2000))
+			}
+			for _, comment := range issue.Comments {
+				fmt.Fprintf(&sb, "\nComment:\n%s\n", truncate(comment, 1000))
+			}
+			sb.WriteString("</thread>\n")
+		}
+		sb.WriteString("</cluster>\n\n")
+	}
+
+	model := section.Model
+	if model == "" {
+		model = cfg.Settings.Model
+	}
+	if model == "" {
+		model = "gemini-3-pro-preview"
+	}
+
+	genConfig := config.MergeGenerationConfig(cfg.Settings.GenerationConfig, section.GenerationConfig)
+	output, err := g.CallLLM(sb.String(), model, genConfig, packageDir)
+	if err != nil {
+		return fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(strings.TrimSpace(output)+"\n"), 0o644); err != nil { //nolint:gosec // generated doc
+		return fmt.Errorf("failed to write FAQ: %w", err)
+	}
+
+	g.logger.Infof("Successfully generated FAQ with %d topics at %s", len(clusters), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Emit()
+	return nil
+}
@@ -7,26 +7,36 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
 	coreConfig "github.com/grovetools/core/config"
-	"github.com/grovetools/core/logging"
 	"github.com/grovetools/core/pkg/workspace"
 	"github.com/grovetools/core/util/delegation"
+	"github.com/grovetools/docgen/internal/clilog"
 	"github.com/grovetools/docgen/pkg/capture"
+	"github.com/grovetools/docgen/pkg/citations"
 	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/credentials"
+	"github.com/grovetools/docgen/pkg/descriptions"
+	"github.com/grovetools/docgen/pkg/discovery"
+	"github.com/grovetools/docgen/pkg/ollama"
 	"github.com/grovetools/docgen/pkg/parser"
+	"github.com/grovetools/docgen/pkg/progress"
+	"github.com/grovetools/docgen/pkg/report"
 	"github.com/grovetools/docgen/pkg/schema"
+	"github.com/grovetools/docgen/pkg/telemetry"
+	"github.com/grovetools/docgen/pkg/transformer"
 	"github.com/grovetools/grove-anthropic/pkg/anthropic"
 	"github.com/sirupsen/logrus"
 )
 
-var ulog = logging.NewUnifiedLogger("grove-docgen")
-
 // Generator handles the documentation generation for a single package.
 type Generator struct {
 	logger *logrus.Logger
@@ -53,6 +63,69 @@ type Generator struct {
 	// boundary instead of seeing only "exit status 1".
 	failedSections      []string
 	failedSectionErrors map[string]string
+
+	// warnings accumulates problems that leave the docs incomplete without
+	// failing a section (an unreadable system prompt, a sub-config that does
+	// not load). GenerateOptions.Strict turns them into a run failure.
+	warnings []string
+
+	// report, when set, records each section's outcome, duration and usage
+	// for the run report (see SetReport).
+	report *report.Recorder
+
+	// ctx bounds every LLM request and subprocess of the run (see
+	// SetContext); nil means context.Background().
+	ctx context.Context
+
+	// isolation, when set (GenerateOptions.Isolate), is the temporary
+	// worktree context tooling runs in instead of the user's checkout.
+	isolation *isolation
+
+	// sectionSpan traces the open section (see startSection), and
+	// sectionCtx carries it to the spans of its LLM calls and writes.
+	sectionSpan *telemetry.Span
+	sectionCtx  context.Context
+
+	// progress, when set, shows the run's progress on the terminal (see
+	// SetProgress); sectionTask is the open section's entry in it.
+	progress    *progress.Tracker
+	sectionTask *progress.Task
+
+	// answer asks the user the questions sections ask (settings.questions)
+	// that answers.yml does not answer; see SetAnswerFunc.
+	answer AnswerFunc
+
+	// ulog, when set, receives the run's milestones, such as each section
+	// written and the summary, for grove's unified log (see SetEventLogger).
+	ulog *clilog.EventLogger
+
+	// surfaces caches the CLI and config surface of each package the
+	// reference check lints against, and captured the command trees the
+	// run's capture sections crawled (see referenceSurface).
+	surfaces map[string]*surface
+	captured map[string]*capture.CommandNode
+
+	// credentials is the credentials profile (settings.credentials) the
+	// provider keys of the run's LLM requests are read from;
+	// credentialsWarned is set once a store failure has been reported.
+	credentials       string
+	credentialsWarned bool
+
+	// rateLimits are the provider quotas (settings.rate_limits) the run's
+	// LLM requests wait on; see UseConfig.
+	rateLimits map[string]config.RateLimit
+
+	// ollamaConfig is the run's settings.ollama; ollama holds the context
+	// windows and context summaries of its local model requests.
+	ollamaConfig *config.OllamaConfig
+	ollama       *ollamaRun
+
+	// budget is the context budget (settings.context) of the section
+	// making requests; see useContextBudget.
+	budget *sectionBudget
+
+	// batch, when set, is the state of a --batch run (see generateBatch).
+	batch *batchRun
 }
 
 // GenerateOptions configures what sections to generate
@@ -68,6 +141,28 @@ type GenerateOptions struct {
 	// report so the caller can still distinguish "ran, no cache usage" from
 	// "did not run".
 	UsageJSONPath string
+	// FailFast stops the run at the first failed section instead of giving
+	// the remaining sections their chance; the skipped sections are listed in
+	// the run error so they can be retried.
+	FailFast bool
+	// Strict fails the run on warnings that would otherwise leave partial
+	// docs behind a zero exit, such as a system prompt that cannot be read or
+	// a structured output file that cannot be built.
+	Strict bool
+	// Isolate runs context building and LLM requests in a temporary git
+	// worktree of the package's repository (with its uncommitted changes),
+	// so the context files they write never touch the user's checkout and
+	// packages of one repository can generate concurrently. Docs are still
+	// written to the package's output directory.
+	Isolate bool
+	// OnlyStale generates only the stale sections (see StaleSections), or
+	// nothing when every doc is up to date. It cannot be combined with
+	// Sections.
+	OnlyStale bool
+	// Batch submits the prose sections' requests through the providers'
+	// batch APIs and waits for them before writing the docs (see
+	// generateBatch). It does not use the cache fan-out.
+	Batch bool
 }
 
 // SectionUsage is one section's cache/usage accounting in the machine-readable
@@ -108,22 +203,141 @@ func New(logger *logrus.Logger) *Generator {
 	return &Generator{logger: logger}
 }
 
+// SetContext bounds the generator's LLM requests and subprocesses by ctx:
+// once it is cancelled or times out, in-flight calls are killed and section
+// loops stop. Callers that use the generator's helpers directly (CallLLM,
+// BuildContext) set it; GenerateContext sets it for a run.
+func (g *Generator) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// runContext returns the context set by SetContext, or context.Background().
+func (g *Generator) runContext() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
+// cancelled reports a cancelled or timed-out run as an error wrapping
+// context.Canceled or context.DeadlineExceeded, or nil while it may go on.
+func (g *Generator) cancelled() error {
+	if err := g.runContext().Err(); err != nil {
+		return fmt.Errorf("generation cancelled: %w", err)
+	}
+	return nil
+}
+
+// runCommand runs cmd, killing it when the run's context is cancelled.
+// delegation.Command builds plain commands, so the context is applied here
+// rather than through exec.CommandContext. The subprocess joins the trace of
+// the span ctx carries, through TRACEPARENT.
+func (g *Generator) runCommand(ctx context.Context, cmd *exec.Cmd) error {
+	// With --isolate, run in the worktree's copy of the directory.
+	cmd.Dir = g.isolation.path(cmd.Dir)
+	cmd.Env = telemetry.Environ(ctx, cmd.Env)
+	// Don't wait on children of a killed process still holding the pipes.
+	if cmd.WaitDelay == 0 {
+		cmd.WaitDelay = time.Second
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-g.runContext().Done():
+		_ = cmd.Process.Kill() //nolint:errcheck // the process may already have exited
+		<-done
+		return g.cancelled()
+	}
+}
+
+// SetReport makes the generator record each section it processes in r; the
+// caller finishes and writes the report.
+func (g *Generator) SetReport(r *report.Recorder) {
+	g.report = r
+}
+
+// SetProgress makes the generator report its sections to p, shared by the
+// packages of GenerateAllContext; the caller stops it.
+func (g *Generator) SetProgress(p *progress.Tracker) {
+	g.progress = p
+}
+
+// SetEventLogger makes the generator log its milestones to l as well as to
+// its logger. Without one, as for library callers, they are not logged
+// there.
+func (g *Generator) SetEventLogger(l *clilog.EventLogger) {
+	g.ulog = l
+}
+
 // recordSectionFailure books one failed section for the usage report and
 // emits it with the section name in the log message itself — log panes list
 // only the message line, and fifteen bare "Section failed" rows are useless
 // without a click-through — plus the error text as a field.
 func (g *Generator) recordSectionFailure(name string, err error) {
+	if g.batch.collecting() {
+		return
+	}
+	g.report.FailSection(err)
+	g.failSectionSpan(err)
 	g.failedSections = append(g.failedSections, name)
 	if g.failedSectionErrors == nil {
 		g.failedSectionErrors = make(map[string]string)
 	}
 	g.failedSectionErrors[name] = err.Error()
-	ulog.Error(fmt.Sprintf("Section %q failed", name)).
+	g.ulog.Error(fmt.Sprintf("Section %q failed", name)).
 		Field("section", name).
 		Field("error", err.Error()).
 		Emit()
 }
 
+// recordWarning books a problem that leaves the docs incomplete without
+// failing a section, so --strict can fail the run on it.
+func (g *Generator) recordWarning(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	g.warnings = append(g.warnings, msg)
+	g.logger.Warn(msg)
+}
+
+// finishSections emits the end-of-run tally and builds the run error for a
+// section loop. skipped are the sections --fail-fast never started.
+func (g *Generator) finishSections(total int, failed, skipped []string) error {
+	if g.batch.collecting() {
+		return g.cancelled()
+	}
+	entry := g.ulog.Info("Generation summary")
+	if len(failed) > 0 {
+		entry = g.ulog.Error("Generation summary")
+	}
+	entry = entry.
+		Field("sections", total).
+		Field("succeeded", total-len(failed)-len(skipped)).
+		Field("failed", len(failed))
+	if len(failed) > 0 {
+		entry = entry.Field("failed_sections", strings.Join(failed, ", "))
+	}
+	if len(skipped) > 0 {
+		entry = entry.Field("skipped", strings.Join(skipped, ", "))
+	}
+	entry.Emit()
+
+	if err := g.cancelled(); err != nil {
+		return err
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	err := g.failedSectionsError(failed)
+	if len(skipped) > 0 {
+		return fmt.Errorf("%w; skipped by --fail-fast: %s", err, strings.Join(skipped, ", "))
+	}
+	return err
+}
+
 // failedSectionsError builds the run-level error for a set of failed
 // sections. Section failures share one root cause almost always (an
 // over-window prefix 400s every request), so the first failure's error text
@@ -144,11 +358,60 @@ func (g *Generator) Generate(packageDir string) error {
 
 // GenerateWithOptions orchestrates documentation generation with specific options.
 func (g *Generator) GenerateWithOptions(packageDir string, opts GenerateOptions) error {
+	return g.GenerateContext(context.Background(), packageDir, opts)
+}
+
+// GenerateContext is GenerateWithOptions bounded by ctx. Cancelling ctx (for
+// example on SIGINT or a --timeout) kills the in-flight LLM request and stops
+// before the next section; section outputs are written atomically, so an
+// interrupted run leaves each doc either regenerated or as it was.
+func (g *Generator) GenerateContext(ctx context.Context, packageDir string, opts GenerateOptions) (err error) {
+	if opts.Batch && g.batch == nil {
+		return g.generateBatch(ctx, packageDir, opts)
+	}
+	ctx, span := telemetry.Start(ctx, "docgen.generate",
+		telemetry.String("docgen.package", packageDir),
+		telemetry.String("docgen.model", opts.Model))
+	defer func() {
+		g.endSectionSpan()
+		span.RecordError(err)
+		span.End()
+	}()
+	g.SetContext(ctx)
 	// Emit the machine-readable usage report at the end of the run (even on
 	// partial failure) so a shelling caller always gets whatever was billed.
 	if opts.UsageJSONPath != "" {
 		defer g.writeUsageReport(opts.UsageJSONPath, opts.Model)
 	}
+	if opts.OnlyStale {
+		if len(opts.Sections) > 0 {
+			return fmt.Errorf("only-stale selects the sections itself and cannot be combined with sections")
+		}
+		stale, err := g.StaleSections(packageDir)
+		if err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			g.logger.Infof("Every doc of %s is up to date; nothing to generate", packageDir)
+			g.report.StartSection("", "", "package", "")
+			g.report.SkipSection("no stale sections")
+			return nil
+		}
+		g.logger.Infof("%d stale section(s): %v", len(stale), stale)
+		opts.Sections = stale
+	}
+	if opts.Isolate {
+		iso, err := newIsolation(g.runContext(), packageDir)
+		if err != nil {
+			return fmt.Errorf("failed to isolate generation: %w", err)
+		}
+		g.logger.Infof("Isolated context building in worktree %s", iso.dir)
+		g.isolation = iso
+		defer func() {
+			iso.remove()
+			g.isolation = nil
+		}()
+	}
 	if len(opts.Sections) > 0 {
 		g.logger.Infof("Starting generation for package at: %s (sections: %v)", packageDir, opts.Sections)
 	} else {
@@ -159,6 +422,11 @@ func (g *Generator) GenerateWithOptions(packageDir string, opts GenerateOptions)
 	if err := g.generateInPlace(packageDir, opts); err != nil {
 		return fmt.Errorf("generation process failed: %w", err)
 	}
+	defer func() {
+		if opts.Strict && len(g.warnings) > 0 && err == nil {
+			err = fmt.Errorf("strict mode: %d warning(s): %s", len(g.warnings), strings.Join(g.warnings, "; "))
+		}
+	}()
 
 	// Generate JSON from markdown if configured
 	cfg, err := config.Load(packageDir)
@@ -170,8 +438,11 @@ func (g *Generator) GenerateWithOptions(packageDir string, opts GenerateOptions)
 		g.logger.Info("Generating structured JSON from markdown...")
 		p := parser.New(g.logger)
 		if err := p.GenerateJSON(packageDir, cfg); err != nil {
-			g.logger.WithError(err).Error("Failed to generate JSON from markdown")
-			// Don't fail the whole process if JSON generation fails
+			g.report.AddError(fmt.Errorf("structured output: %w", err))
+			// Don't fail the whole process if JSON generation fails, unless strict
+			g.recordWarning("Failed to generate JSON from markdown: %v", err)
+		} else {
+			g.report.AddOutput(filepath.Join(packageDir, cfg.Settings.StructuredOutputFile))
 		}
 	}
 
@@ -258,6 +529,8 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 		return fmt.Errorf("failed to load docgen config: %w", err)
 	}
 
+	g.UseConfig(cfg)
+
 	// Resolve once, before building context or making any LLM request. A
 	// configured docgen run must never silently fall back to default rules.
 	rulesPath, err := config.ResolveDocsRulesFile(packageDir)
@@ -271,29 +544,16 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 	}
 
 	// 2. Determine output base directory based on config location
-	var outputBaseDir string
-
-	// Check if config was loaded from notebook by checking if config path is outside the repo
-	// (notebook configs won't be under packageDir)
-	isNotebookMode := !strings.HasPrefix(configPath, packageDir)
+	outputBaseDir, isNotebookMode := outputBaseDirFor(packageDir, configPath, cfg)
 	if isNotebookMode {
-		// Output to notebook's docgen/docs/ directory
-		docgenDir := filepath.Dir(configPath) // configPath is docgenDir/docgen.config.yml
-		outputBaseDir = filepath.Join(docgenDir, "docs")
 		g.logger.Infof("Using notebook mode: config from %s, outputting to %s", configPath, outputBaseDir)
-		ulog.Info("Notebook mode").
+		g.ulog.Info("Notebook mode").
 			Field("config", configPath).
 			Field("output", outputBaseDir).
 			Emit()
 	} else {
-		// Output to repo's configured output_dir (default: docs/)
-		if cfg.Settings.OutputDir != "" {
-			outputBaseDir = filepath.Join(packageDir, cfg.Settings.OutputDir)
-		} else {
-			outputBaseDir = filepath.Join(packageDir, "docs")
-		}
 		g.logger.Infof("Using repo mode: config from %s, outputting to %s", configPath, outputBaseDir)
-		ulog.Info("Repo mode").
+		g.ulog.Info("Repo mode").
 			Field("config", configPath).
 			Field("output", outputBaseDir).
 			Emit()
@@ -327,11 +587,19 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 				systemPrompt = string(content)
 				g.logger.Debugf("Loaded system prompt from %s", cfg.Settings.SystemPrompt)
 			} else {
-				g.logger.Warnf("Failed to load system prompt from %s, proceeding without it", cfg.Settings.SystemPrompt)
+				g.recordWarning("Failed to load system prompt from %s, proceeding without it", cfg.Settings.SystemPrompt)
 			}
 		}
 	}
 
+	// 3b. Inject the glossary so terminology stays consistent across sections
+	if glossaryPrompt := g.glossaryPrompt(packageDir, cfg); glossaryPrompt != "" {
+		if systemPrompt != "" {
+			systemPrompt += "\n"
+		}
+		systemPrompt += glossaryPrompt
+	}
+
 	// 4. Filter sections if specified
 	sectionsToGenerate := cfg.Sections
 	if len(opts.Sections) > 0 {
@@ -372,6 +640,8 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 		sectionsToGenerate = filteredSections
 		g.logger.Infof("Generating %d of %d sections: %v", len(sectionsToGenerate), len(cfg.Sections), opts.Sections)
 	}
+	// concat sections combine other sections' output, so they run last.
+	sectionsToGenerate = concatLast(sectionsToGenerate)
 
 	// Pre-spend guard: fail before any LLM call if an in-scope section lacks an
 	// output: filename (an empty output writes onto the output dir itself). Only
@@ -380,9 +650,10 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 		return err
 	}
 
-	// Pre-spend guard: every in-scope prose section's prompt file must resolve
-	// (notebook first, legacy fallback — the same resolution the loop below
-	// uses) before any LLM call, listing ALL missing prompts in one error.
+	// Pre-spend guard: the prompt file of every in-scope section that reads
+	// one must resolve (notebook first, legacy fallback — the same resolution
+	// the loop below uses) before any LLM call, listing ALL missing prompts in
+	// one error.
 	if err := validateSectionPrompts(sectionsToGenerate, func(_ int, s config.SectionConfig) (string, error) {
 		return g.resolvePromptPath(packageDir, s.Prompt)
 	}); err != nil {
@@ -399,8 +670,24 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 		failedSections = append(failedSections, name)
 		g.recordSectionFailure(name, err)
 	}
-	for _, section := range sectionsToGenerate {
+	var skippedSections []string
+	g.progress.AddTotal(len(sectionsToGenerate))
+	for i, section := range sectionsToGenerate {
+		if opts.FailFast && len(failedSections) > 0 {
+			for _, rest := range sectionsToGenerate[i:] {
+				skippedSections = append(skippedSections, rest.Name)
+			}
+			break
+		}
+		if err := g.cancelled(); err != nil {
+			g.endSection()
+			return err
+		}
+		if g.batch.collecting() && !isProseSection(section.Type) {
+			continue
+		}
 		g.currentSection = section.Name
+		g.startSection(section.Name, section.Type, filepath.Join(outputBaseDir, section.Output))
 		// Handle different generation types
 		if section.Type == "schema_to_md" {
 			if err := g.generateFromSchema(packageDir, section, cfg, outputBaseDir); err != nil {
@@ -465,6 +752,102 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 			}
 			continue
 		}
+		if section.Type == "concat" {
+			if err := g.generateFromConcat(section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("Concat generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "faq_from_issues" {
+			if err := g.generateFromIssues(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("FAQ generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "error_reference" {
+			if err := g.generateErrorReference(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("Error reference generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "metrics_reference" {
+			if err := g.generateMetricsReference(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("Metrics reference generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "http_routes" {
+			if err := g.generateHTTPRoutes(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("HTTP route reference generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "make_targets" {
+			if err := g.generateMakeTargets(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("Task runner target generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "ci_workflows" {
+			if err := g.generateCIWorkflows(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("CI workflow generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "contributors" {
+			if err := g.generateContributors(packageDir, section, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("Contributors generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "licenses" {
+			if err := g.generateLicenses(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("License generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "sql_schema" {
+			if err := g.generateSQLSchema(packageDir, section, cfg, outputBaseDir); err != nil {
+				g.logger.WithError(err).Errorf("SQL schema generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "tutorial" {
+			scenario, err := g.resolvePromptContent(packageDir, section.Prompt)
+			if err != nil {
+				g.logger.WithError(err).Errorf("Could not resolve scenario for section '%s'", section.Name)
+				sectionFailed(section.Name, fmt.Errorf("could not resolve prompt for section '%s': %w", section.Name, err))
+				continue
+			}
+			if err := g.generateFromTutorial(packageDir, section, cfg, outputBaseDir, string(scenario)); err != nil {
+				g.logger.WithError(err).Errorf("Tutorial generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
+		if section.Type == "template" {
+			skeleton, err := g.resolvePromptContent(packageDir, section.Prompt)
+			if err != nil {
+				g.logger.WithError(err).Errorf("Could not resolve template for section '%s'", section.Name)
+				sectionFailed(section.Name, fmt.Errorf("could not resolve template for section '%s': %w", section.Name, err))
+				continue
+			}
+			if err := g.generateFromTemplate(packageDir, section, cfg, outputBaseDir, string(skeleton)); err != nil {
+				g.logger.WithError(err).Errorf("Template generation failed for section '%s'", section.Name)
+				sectionFailed(section.Name, err)
+			}
+			continue
+		}
 		g.logger.Infof("Generating section: %s", section.Name)
 
 		// Use the new prompt resolution method that checks notebook first
@@ -474,7 +857,7 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 		}
 
 		// Build the final prompt with system prompt prepended if available
-		finalPrompt := string(promptContent)
+		finalPrompt := g.expandSnippets(packageDir, string(promptContent))
 		if systemPrompt != "" {
 			finalPrompt = systemPrompt + "\n" + finalPrompt
 		}
@@ -489,6 +872,13 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 			}
 		}
 
+		if cfg.Settings.Citations {
+			finalPrompt += citations.Instructions
+		}
+		if transformer.IsMDX(section.Output) {
+			finalPrompt += mdxPrompt(cfg.ComponentMappings(nil))
+		}
+
 		// Determine model to use (section override or global)
 		model := cfg.Settings.Model
 		if section.Model != "" {
@@ -499,32 +889,49 @@ func (g *Generator) generateInPlace(packageDir string, opts GenerateOptions) err
 		// Merge generation configs (global + section overrides)
 		genConfig := config.MergeGenerationConfig(cfg.Settings.GenerationConfig, section.GenerationConfig)
 
-		output, err := g.CallLLM(finalPrompt, model, genConfig, packageDir)
+		var output string
+		g.useContextBudget(config.MergeContextBudget(cfg.Settings.Context, section.Context), section, string(promptContent))
+		if cfg.Settings.Questions {
+			output, err = g.callWithQuestions(section.Name, finalPrompt, model, genConfig, packageDir, filepath.Join(filepath.Dir(configPath), AnswersFileName))
+		} else {
+			output, err = g.CallLLM(finalPrompt, model, genConfig, packageDir)
+		}
+		g.useContextBudget(nil, section, "")
 		if err != nil {
 			g.logger.WithError(err).Errorf("LLM call failed for section '%s'", section.Name)
 			sectionFailed(section.Name, err)
 			continue // Continue to the next section even if one fails
 		}
+		output = g.extractDocument(output, model, cfg, genConfig, packageDir)
+
+		// Inline any snippet references the model kept from the prompt
+		output = g.expandSnippets(packageDir, output)
+		output = transformer.InferCodeLanguages(output)
+		output = g.postprocess(section.Name, output, cfg.PostprocessSteps(section), packageDir)
+		if transformer.IsMDX(section.Output) {
+			output = g.prepareMDX(section.Name, output, cfg.ComponentMappings(nil))
+		}
+		g.checkAnchors(section.Name, output)
 
 		// 6. Write output to the determined output directory
 		outputPath := filepath.Join(outputBaseDir, section.Output)
-		if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
-			return fmt.Errorf("failed to create output directory: %w", err)
-		}
-		if err := os.WriteFile(outputPath, []byte(output), 0o644); err != nil {
+		if err := g.writeOutput(outputPath, output); err != nil {
 			return fmt.Errorf("failed to write section output: %w", err)
 		}
+		if cfg.Settings.Citations {
+			g.checkCitations(section.Name, output, outputPath, packageDir)
+		}
+		g.checkReferences(packageDir, section.Name, outputPath, output)
 		g.logger.Infof("Successfully wrote section '%s' to %s", section.Name, outputPath)
-		ulog.Success("Wrote section").
+		g.ulog.Success("Wrote section").
 			Field("section", section.Name).
 			Field("path", outputPath).
 			Emit()
 	}
 
-	if len(failedSections) > 0 {
-		return g.failedSectionsError(failedSections)
-	}
-	return nil
+	g.endSection()
+
+	return g.finishSections(len(sectionsToGenerate), failedSections, skippedSections)
 }
 
 // validateSectionOutputs is the pre-spend guard for a generation run: every
@@ -567,8 +974,8 @@ func validateSectionOutputs(sections []config.SectionConfig) error {
 }
 
 // validateSectionPrompts is the pre-spend prompt-existence guard, the prompt
-// counterpart to validateSectionOutputs: every in-scope PROSE section's prompt
-// file must resolve BEFORE any LLM call, or a section late in the run would
+// counterpart to validateSectionOutputs: the prompt file of every in-scope
+// section that reads one (isPromptSection) must resolve BEFORE any LLM call, or a section late in the run would
 // hard-fail on a missing prompt after earlier sections already paid for their
 // calls. resolvePath is the caller's own prompt resolution (index-aligned with
 // sections) so the guard checks the exact file generation would read — notebook
@@ -579,7 +986,7 @@ func validateSectionOutputs(sections []config.SectionConfig) error {
 func validateSectionPrompts(sections []config.SectionConfig, resolvePath func(i int, s config.SectionConfig) (string, error)) error {
 	var problems []string
 	for i, s := range sections {
-		if !isProseSection(s.Type) {
+		if !isPromptSection(s.Type) {
 			continue
 		}
 		if strings.TrimSpace(s.Prompt) == "" {
@@ -593,7 +1000,7 @@ func validateSectionPrompts(sections []config.SectionConfig, resolvePath func(i
 	if len(problems) == 0 {
 		return nil
 	}
-	return fmt.Errorf("could not resolve prompt for %d prose section(s) — failing before any LLM call: %s",
+	return fmt.Errorf("could not resolve prompt for %d section(s) — failing before any LLM call: %s",
 		len(problems), strings.Join(problems, "; "))
 }
 
@@ -721,12 +1128,16 @@ func (g *Generator) generateFromSchema(packageDir string, section config.Section
 	var inputs []config.SchemaInput
 	if len(section.Schemas) > 0 {
 		inputs = section.Schemas
-	} else if section.Source != "" {
-		inputs = []config.SchemaInput{{Path: section.Source}}
+	} else if len(section.Source) > 0 {
+		inputs = section.Source.SchemaInputs()
 	} else {
 		return fmt.Errorf("section type 'schema_to_md' requires 'schemas' list or 'source' file")
 	}
 
+	if !section.UsesLLM() {
+		return g.renderSchemaMarkdown(packageDir, section, inputs, outputBaseDir)
+	}
+
 	var sb strings.Builder
 
 	for _, input := range inputs {
@@ -786,10 +1197,58 @@ func (g *Generator) generateFromSchema(packageDir string, section config.Section
 	if err := os.WriteFile(outputPath, []byte(output), 0o644); err != nil {
 		return fmt.Errorf("failed to write schema doc output: %w", err)
 	}
+	g.checkReferences(packageDir, section.Name, outputPath, output)
 	g.logger.Infof("Successfully wrote schema doc section '%s' to %s", section.Name, outputPath)
 	return nil
 }
 
+// renderSchemaMarkdown writes schema_to_md output directly from the schemas
+// (`llm: false`), producing reproducible reference tables.
+func (g *Generator) renderSchemaMarkdown(packageDir string, section config.SectionConfig, inputs []config.SchemaInput, outputBaseDir string) error {
+	var sb strings.Builder
+	if section.Title != "" {
+		sb.WriteString(fmt.Sprintf("# %s\n\n", section.Title))
+	}
+
+	for _, input := range inputs {
+		if input.Path == "" {
+			continue
+		}
+
+		schemaPath := filepath.Join(packageDir, input.Path)
+		parser, err := schema.NewParser(schemaPath)
+		if err != nil {
+			return fmt.Errorf("failed to initialize schema parser for %s: %w", input.Path, err)
+		}
+
+		opts := schema.MarkdownOptions{HeadingLevel: 2}
+		if input.Title != "" {
+			sb.WriteString(fmt.Sprintf("## %s\n\n", input.Title))
+			opts.HeadingLevel = 3
+		}
+		if len(inputs) > 1 {
+			// Keep anchors unique when several schemas share one page
+			opts.AnchorPrefix = strings.TrimSuffix(filepath.Base(input.Path), filepath.Ext(input.Path))
+		}
+
+		md, err := parser.RenderMarkdown(opts)
+		if err != nil {
+			return fmt.Errorf("failed to render schema %s as markdown: %w", input.Path, err)
+		}
+		sb.WriteString(md)
+	}
+
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create output directory for schema doc: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(strings.TrimRight(sb.String(), "\n")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write schema doc output: %w", err)
+	}
+	g.logger.Infof("Successfully rendered schema doc section '%s' to %s (llm: false)", section.Name, outputPath)
+	return nil
+}
+
 func (g *Generator) generateFromDocSections(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
 	g.logger.Infof("Generating doc sections: %s", section.Name)
 
@@ -798,37 +1257,21 @@ func (g *Generator) generateFromDocSections(packageDir string, section config.Se
 	}
 
 	// Discover all ecosystems to build package path map
-	discoveryService := workspace.NewDiscoveryService(g.logger)
-	result, err := discoveryService.DiscoverAll()
+	disc := discovery.New(g.logger)
+	ecosystems, err := disc.AllEcosystems()
 	if err != nil {
 		return fmt.Errorf("failed to discover ecosystems: %w", err)
 	}
 
 	// Build map of package name -> path
 	packagePaths := make(map[string]string)
-	for _, eco := range result.Ecosystems {
-		configPath, err := coreConfig.FindConfigFile(eco.Path)
-		if err != nil {
-			continue
-		}
-		ecoCfg, err := coreConfig.Load(configPath)
+	for _, eco := range ecosystems {
+		workspaces, err := disc.Workspaces(eco)
 		if err != nil {
 			continue
 		}
-
-		for _, wsPattern := range ecoCfg.Workspaces {
-			pattern := filepath.Join(eco.Path, wsPattern)
-			matches, err := filepath.Glob(pattern)
-			if err != nil {
-				continue
-			}
-			for _, match := range matches {
-				info, err := os.Stat(match)
-				if err == nil && info.IsDir() {
-					pkgName := filepath.Base(match)
-					packagePaths[pkgName] = match
-				}
-			}
+		for _, wsPath := range workspaces {
+			packagePaths[filepath.Base(wsPath)] = wsPath
 		}
 	}
 
@@ -1051,8 +1494,8 @@ func (g *Generator) generateFromSchemaTable(packageDir string, section config.Se
 	var inputs []config.SchemaInput
 	if len(section.Schemas) > 0 {
 		inputs = section.Schemas
-	} else if section.Source != "" {
-		inputs = []config.SchemaInput{{Path: section.Source}}
+	} else if len(section.Source) > 0 {
+		inputs = section.Source.SchemaInputs()
 	} else {
 		return fmt.Errorf("section type 'schema_table' requires 'schemas' list or 'source' file")
 	}
@@ -1152,8 +1595,8 @@ func (g *Generator) generateFromSchemaTableJSON(packageDir string, section confi
 	var inputs []config.SchemaInput
 	if len(section.Schemas) > 0 {
 		inputs = section.Schemas
-	} else if section.Source != "" {
-		inputs = []config.SchemaInput{{Path: section.Source}}
+	} else if len(section.Source) > 0 {
+		inputs = section.Source.SchemaInputs()
 	} else {
 		return fmt.Errorf("section type 'schema_table' requires 'schemas' list or 'source' file")
 	}
@@ -1405,8 +1848,8 @@ func (g *Generator) generateSchemaDescriptions(packageDir string, section config
 	var inputs []config.SchemaInput
 	if len(section.Schemas) > 0 {
 		inputs = section.Schemas
-	} else if section.Source != "" {
-		inputs = []config.SchemaInput{{Path: section.Source}}
+	} else if len(section.Source) > 0 {
+		inputs = section.Source.SchemaInputs()
 	} else {
 		return fmt.Errorf("section type 'schema_describe' requires 'schemas' list or 'source' file")
 	}
@@ -1429,6 +1872,27 @@ func (g *Generator) generateSchemaDescriptions(packageDir string, section config
 		allProps = append(allProps, props...)
 	}
 
+	// Only properties that are new or whose type changed since their
+	// description was generated are sent to the LLM.
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	store, err := descriptions.Load(outputPath)
+	if err != nil {
+		return err
+	}
+	hashes := make(map[string]string)
+	var staleProps []string
+	for _, fp := range flattenSchemaProperties(allProps, "") {
+		hashes[fp.path] = descriptions.SchemaHash(fp.prop.Type)
+		if store.Stale(descriptions.ID(descriptions.KindSchema, fp.path), hashes[fp.path]) {
+			staleProps = append(staleProps, fmt.Sprintf("- %s (%s): %s\n", fp.path, fp.prop.Type, fp.prop.Description))
+		}
+	}
+	if len(staleProps) == 0 {
+		g.logger.Infof("Schema descriptions are up to date (%d properties)", len(hashes))
+		return nil
+	}
+	g.logger.Infof("Describing %d of %d properties (new or changed)", len(staleProps), len(hashes))
+
 	// Build prompt for LLM
 	var promptBuilder strings.Builder
 
@@ -1448,7 +1912,7 @@ Keep descriptions concise but informative (1-3 sentences).
 
 Properties to describe:
 `)
-	g.collectPropertyPaths(&promptBuilder, allProps, "")
+	promptBuilder.WriteString(strings.Join(staleProps, ""))
 
 	promptBuilder.WriteString(`
 Output format (JSON only, no markdown fences):
@@ -1473,7 +1937,7 @@ Output format (JSON only, no markdown fences):
 	}
 
 	// Parse and validate JSON response
-	var descriptions map[string]string
+	var generated map[string]string
 	// Strip markdown code fences if present
 	cleanResponse := strings.TrimSpace(response)
 	cleanResponse = strings.TrimPrefix(cleanResponse, "```json")
@@ -1481,26 +1945,24 @@ Output format (JSON only, no markdown fences):
 	cleanResponse = strings.TrimSuffix(cleanResponse, "```")
 	cleanResponse = strings.TrimSpace(cleanResponse)
 
-	if err := json.Unmarshal([]byte(cleanResponse), &descriptions); err != nil {
+	if err := json.Unmarshal([]byte(cleanResponse), &generated); err != nil {
 		return fmt.Errorf("failed to parse LLM response as JSON: %w\nResponse: %s", err, response)
 	}
 
-	// Write output
-	outputPath := filepath.Join(outputBaseDir, section.Output)
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	jsonBytes, err := json.MarshalIndent(descriptions, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal descriptions: %w", err)
+	for path, desc := range generated {
+		hash, ok := hashes[path]
+		if !ok {
+			g.logger.Warnf("Ignoring description for unknown property %q", path)
+			continue
+		}
+		store.Put(descriptions.ID(descriptions.KindSchema, path), descriptions.Entry{Description: desc}, hash)
 	}
 
-	if err := os.WriteFile(outputPath, jsonBytes, 0o644); err != nil {
-		return fmt.Errorf("failed to write descriptions file: %w", err)
+	if err := store.Save(); err != nil {
+		return err
 	}
 
-	g.logger.Infof("Successfully wrote %d descriptions to %s", len(descriptions), outputPath)
+	g.logger.Infof("Successfully wrote %d descriptions to %s", len(generated), outputPath)
 	return nil
 }
 
@@ -1512,8 +1974,8 @@ func (g *Generator) generateSchemaExamples(packageDir string, section config.Sec
 	var inputs []config.SchemaInput
 	if len(section.Schemas) > 0 {
 		inputs = section.Schemas
-	} else if section.Source != "" {
-		inputs = []config.SchemaInput{{Path: section.Source}}
+	} else if len(section.Source) > 0 {
+		inputs = section.Source.SchemaInputs()
 	} else {
 		return fmt.Errorf("section type 'schema_examples' requires 'schemas' list or 'source' file")
 	}
@@ -1625,6 +2087,26 @@ Output format (JSON only, no markdown fences):
 	return nil
 }
 
+// flatProperty is a schema property with its dotted path.
+type flatProperty struct {
+	path string
+	prop schema.Property
+}
+
+// flattenSchemaProperties lists every property depth-first with its path.
+func flattenSchemaProperties(props []schema.Property, prefix string) []flatProperty {
+	var flat []flatProperty
+	for _, prop := range props {
+		path := prop.Name
+		if prefix != "" {
+			path = prefix + "." + prop.Name
+		}
+		flat = append(flat, flatProperty{path: path, prop: prop})
+		flat = append(flat, flattenSchemaProperties(prop.Properties, path)...)
+	}
+	return flat
+}
+
 // collectPropertyPaths recursively collects property paths for the LLM prompt
 func (g *Generator) collectPropertyPaths(sb *strings.Builder, props []schema.Property, prefix string) {
 	for _, prop := range props {
@@ -1650,11 +2132,11 @@ func (g *Generator) loadDescriptions(packageDir, outputBaseDir, descriptionsPath
 
 	// Try outputBaseDir first (where schema_describe writes to)
 	fullPath := filepath.Join(outputBaseDir, filepath.Base(descriptionsPath))
-	data, err := os.ReadFile(fullPath)
+	_, err := os.Stat(fullPath)
 	if err != nil {
 		// Fall back to packageDir-relative path
 		fullPath = filepath.Join(packageDir, descriptionsPath)
-		data, err = os.ReadFile(fullPath)
+		_, err = os.Stat(fullPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read descriptions file (tried %s and %s): %w",
 				filepath.Join(outputBaseDir, filepath.Base(descriptionsPath)),
@@ -1662,13 +2144,13 @@ func (g *Generator) loadDescriptions(packageDir, outputBaseDir, descriptionsPath
 		}
 	}
 
-	var descriptions map[string]string
-	if err := json.Unmarshal(data, &descriptions); err != nil {
-		return nil, fmt.Errorf("failed to parse descriptions file: %w", err)
+	store, err := descriptions.Load(fullPath)
+	if err != nil {
+		return nil, err
 	}
 
 	g.logger.Debugf("Loaded descriptions from %s", fullPath)
-	return descriptions, nil
+	return store.Descriptions(descriptions.KindSchema), nil
 }
 
 func (g *Generator) generateFromCapture(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
@@ -1696,6 +2178,7 @@ func (g *Generator) generateFromCapture(packageDir string, section config.Sectio
 		MaxDepth:        depth,
 		Format:          format,
 		SubcommandOrder: section.SubcommandOrder,
+		CobraJSON:       section.CobraJSON,
 	}
 
 	outputPath := filepath.Join(outputBaseDir, section.Output)
@@ -1703,20 +2186,126 @@ func (g *Generator) generateFromCapture(packageDir string, section config.Sectio
 		return fmt.Errorf("failed to create output directory for capture: %w", err)
 	}
 
-	if err := capturer.Capture(section.Binary, outputPath, opts); err != nil {
+	root, err := capturer.CrawlContext(g.runContext(), section.Binary, opts)
+	if err != nil {
 		return fmt.Errorf("CLI capture failed for section '%s': %w", section.Name, err)
 	}
+	g.rememberCapture(section.Binary, root)
+
+	if section.Descriptions != "" {
+		if err := g.describeCommands(packageDir, section, cfg, filepath.Join(outputBaseDir, section.Descriptions), root); err != nil {
+			return fmt.Errorf("failed to describe commands for section '%s': %w", section.Name, err)
+		}
+	}
+
+	if err := os.WriteFile(outputPath, []byte(capturer.Render(root, opts)), 0o644); err != nil { //nolint:gosec // internal doc tool output
+		return fmt.Errorf("failed to write CLI capture for section '%s': %w", section.Name, err)
+	}
 
 	g.logger.Infof("Successfully captured CLI reference for '%s' to %s", section.Binary, outputPath)
 	return nil
 }
 
-// BuildContext runs cx generate to prepare context for LLM calls
+// describeCommands fills in each captured command's description from the
+// descriptions store at storePath. Commands whose help output changed since
+// their description was generated, or that have none yet, are described by
+// the LLM in one request and saved back to the store.
+func (g *Generator) describeCommands(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, storePath string, root *capture.CommandNode) error {
+	store, err := descriptions.Load(storePath)
+	if err != nil {
+		return err
+	}
+
+	hashes := make(map[string]string)
+	var stale []*capture.CommandNode
+	root.Walk(func(node *capture.CommandNode) {
+		hashes[node.FullName] = descriptions.Hash(node.HelpOutput)
+		if store.Stale(descriptions.ID(descriptions.KindCLI, node.FullName), hashes[node.FullName]) {
+			stale = append(stale, node)
+		}
+	})
+
+	if len(stale) > 0 {
+		g.logger.Infof("Describing %d of %d commands (new or changed)", len(stale), len(hashes))
+
+		if section.RulesFile != "" {
+			if err := g.BuildContextForRulesSpec(packageDir, section.RulesFile); err != nil {
+				return fmt.Errorf("failed to build section context: %w", err)
+			}
+		}
+
+		var promptBuilder strings.Builder
+		promptBuilder.WriteString(DefaultSystemPrompt)
+		promptBuilder.WriteString(`
+Write a short introduction for each CLI command below, based on its help output.
+Each introduction should explain what the command is for and when to use it (1-3 sentences).
+Do not repeat the flag list.
+
+Commands to describe:
+`)
+		for _, node := range stale {
+			fmt.Fprintf(&promptBuilder, "\n## %s\n```text\n%s\n```\n", node.FullName, strings.TrimSpace(node.HelpOutput))
+		}
+		promptBuilder.WriteString(`
+Output format (JSON only, no markdown fences), keyed by the full command name:
+{
+  "tool command": "Introduction text here...",
+  ...
+}`)
+
+		model := section.Model
+		if model == "" {
+			model = cfg.Settings.Model
+		}
+		if model == "" {
+			model = "gemini-3-pro-preview"
+		}
+
+		genConfig := config.MergeGenerationConfig(cfg.Settings.GenerationConfig, section.GenerationConfig)
+		response, err := g.CallLLM(promptBuilder.String(), model, genConfig, packageDir)
+		if err != nil {
+			return fmt.Errorf("LLM generation failed: %w", err)
+		}
+
+		cleanResponse := strings.TrimSpace(response)
+		cleanResponse = strings.TrimPrefix(cleanResponse, "```json")
+		cleanResponse = strings.TrimPrefix(cleanResponse, "```")
+		cleanResponse = strings.TrimSuffix(cleanResponse, "```")
+		cleanResponse = strings.TrimSpace(cleanResponse)
+
+		var generated map[string]string
+		if err := json.Unmarshal([]byte(cleanResponse), &generated); err != nil {
+			return fmt.Errorf("failed to parse LLM response as JSON: %w\nResponse: %s", err, response)
+		}
+		for name, desc := range generated {
+			hash, ok := hashes[name]
+			if !ok {
+				g.logger.Warnf("Ignoring description for unknown command %q", name)
+				continue
+			}
+			store.Put(descriptions.ID(descriptions.KindCLI, name), descriptions.Entry{Description: desc}, hash)
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+	}
+
+	root.Walk(func(node *capture.CommandNode) {
+		if entry, ok := store.Get(descriptions.ID(descriptions.KindCLI, node.FullName)); ok {
+			node.Description = entry.Description
+		}
+	})
+	return nil
+}
+
+// BuildContext runs cx generate to prepare context for LLM calls. The docs
+// rules are passed with --rules-file; the workspace's .grove/rules is left as
+// it was (see guardRules).
 func (g *Generator) BuildContext(packageDir, rulesPath string) error {
 	args := []string{"generate"}
 	if rulesPath != "" {
 		g.logger.Infof("Docs context rules: %s", rulesPath)
-		ulog.Info("Docs context rules active").Field("rules", rulesPath).Emit()
+		g.ulog.Info("Docs context rules active").Field("rules", rulesPath).Emit()
 		args = append(args, "--rules-file", rulesPath)
 	}
 	cmd := delegation.Command("cx", args...)
@@ -1724,7 +2313,8 @@ func (g *Generator) BuildContext(packageDir, rulesPath string) error {
 	// Discard output to avoid contaminating the LLM response
 	cmd.Stdout = io.Discard
 	cmd.Stderr = io.Discard
-	return cmd.Run()
+	defer g.guardRules(g.isolation.path(packageDir))()
+	return g.runCommand(g.spanContext(), cmd)
 }
 
 // BuildContextForRulesSpec regenerates context for a section-specific rules
@@ -1745,8 +2335,13 @@ func (g *Generator) BuildContextForRulesSpec(packageDir, spec string) error {
 // task rider on the shared, byte-identical repo-context prefix — the big cx
 // context is written to the cache once and cache-read by every subsequent
 // section — instead of shelling `grove llm request`. Non-Claude models (and
-// runs without an active prefix) keep the original facade path untouched.
-func (g *Generator) CallLLM(promptContent, model string, genConfig config.GenerationConfig, workDir string) (string, error) {
+// runs without an active prefix) keep the original facade path untouched,
+// except local models named ollama/<model>, which are requested from the
+// Ollama server directly (callOllama).
+//
+// Each call is traced as a docgen.llm.request span under the open section
+// and counted in docgen.llm.requests.
+func (g *Generator) CallLLM(promptContent, model string, genConfig config.GenerationConfig, workDir string) (_ string, err error) {
 	// A run-wide --model override forces every section onto one model so the
 	// whole wave shares a single cached prefix.
 	if g.forceModel != "" {
@@ -1757,11 +2352,79 @@ func (g *Generator) CallLLM(promptContent, model string, genConfig config.Genera
 	if model == "" {
 		model = "gemini-3-pro-preview"
 	}
+	g.report.SetModel(model)
+
+	// A batch run answers requests from its batches
+	if g.batch != nil {
+		if response, handled, err := g.batchCall(promptContent, model, genConfig, workDir); handled {
+			return response, err
+		}
+	}
+
+	fanout := g.prefix != nil && anthropic.ResolveModelAlias(model) == g.prefix.Model()
+	route := "grove-llm"
+	if fanout {
+		route = "fanout"
+	} else if ollama.IsModel(model) {
+		route = "ollama"
+	}
+
+	// A context over the section's budget is sent trimmed with the prompt.
+	// The fan-out prefix holds the whole context, so a section whose budget
+	// trims it is requested through grove llm instead.
+	var cxContext string
+	trimmed := false
+	if g.budget != nil && route != "ollama" {
+		if cxContext, trimmed, err = g.sectionContext(workDir); err != nil {
+			return "", err
+		}
+		if trimmed && fanout {
+			g.logger.Infof("Context budget of %s trims its context; requesting it without the shared cache prefix", g.currentSection)
+			fanout, route = false, "grove-llm"
+		}
+	}
+	spanCtx, span := telemetry.StartKind(g.spanContext(), "docgen.llm.request", telemetry.KindClient,
+		telemetry.String("gen_ai.request.model", model),
+		telemetry.String("docgen.section", g.currentSection),
+		telemetry.String("docgen.llm.route", route),
+		telemetry.Int("docgen.prompt.bytes", int64(len(promptContent))))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+		telemetry.Add(telemetry.MetricLLMRequests, "{request}", 1,
+			telemetry.String("model", model),
+			telemetry.String("route", route),
+			telemetry.Bool("error", err != nil))
+	}()
+
+	// Queue behind the provider's quota, shared with concurrent generations
+	if err := g.waitRateLimit(model, g.requestTokens(promptContent, workDir, fanout)); err != nil {
+		if cerr := g.cancelled(); cerr != nil {
+			return "", cerr
+		}
+		return "", err
+	}
 
 	// Route Claude generation through the shared-prefix fan-out when one is
 	// active for this exact model.
-	if g.prefix != nil && anthropic.ResolveModelAlias(model) == g.prefix.Model() {
-		return g.callViaFanout(promptContent)
+	if fanout {
+		return g.callViaFanout(promptContent, span)
+	}
+
+	// Local models are served by Ollama directly
+	if route == "ollama" {
+		return g.callOllama(promptContent, model, genConfig, workDir, span)
+	}
+
+	// A trimmed context is sent with the prompt, from a directory where
+	// grove llm finds no context to attach
+	contextDir := workDir
+	if trimmed {
+		promptContent = withContext(cxContext, promptContent)
+		if contextDir, err = os.MkdirTemp("", "docgen-context-*"); err != nil {
+			return "", fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		defer os.RemoveAll(contextDir) //nolint:errcheck // best-effort temp cleanup
 	}
 
 	// Create a temporary file for the prompt
@@ -1784,9 +2447,11 @@ func (g *Generator) CallLLM(promptContent, model string, genConfig config.Genera
 		"request",
 		"--file", promptFile.Name(),
 		"--model", model,
-		"--regenerate", // Ensure context is regenerated with current rules
 		"--yes",
 	}
+	if contextDir == workDir {
+		args = append(args, "--regenerate") // Ensure context is regenerated with current rules
+	}
 
 	// Add generation parameters if specified
 	if genConfig.Temperature != nil {
@@ -1803,15 +2468,22 @@ func (g *Generator) CallLLM(promptContent, model string, genConfig config.Genera
 	}
 
 	cmd := delegation.Command(args[0], args[1:]...)
-	cmd.Dir = workDir
+	cmd.Dir = contextDir
+	// Pass grove llm the stored keys of the run's credentials profile
+	if env := g.credentialEnv(); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	// Capture both stdout and stderr
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err = cmd.Run()
+	err = g.runCommand(spanCtx, cmd)
 	if err != nil {
+		if cerr := g.cancelled(); cerr != nil {
+			return "", cerr
+		}
 		// Log the full stderr for debugging
 		g.logger.Debugf("LLM stderr: %s", stderr.String())
 		// Surface the underlying cause (e.g. missing API key) instead of
@@ -1844,37 +2516,36 @@ func (g *Generator) CallLLM(promptContent, model string, genConfig config.Genera
 		}
 	}
 
+	// grove llm reports no usage here, so the throughput counts an estimate
+	// of the response's tokens
+	g.progress.AddTokens(estimateTokens(output))
+
 	// Clean up the output
 	return cleanLLMResponse(string(output)), nil
 }
 
-// cleanLLMResponse trims whitespace and strips a single wrapping markdown code
-// fence (```markdown / ```md / ```) from an LLM response, leaving clean markdown.
-// Shared by the shell facade path and the cache fan-out path so both produce
-// byte-comparable section output.
+// cleanLLMResponse sanitizes an LLM response into clean markdown (see
+// SanitizeResponse). Shared by the shell facade path and the cache fan-out
+// path so both produce byte-comparable section output.
 func cleanLLMResponse(response string) string {
-	response = strings.TrimSpace(response)
-
-	if strings.HasPrefix(response, "```markdown") || strings.HasPrefix(response, "```md") {
-		lines := strings.Split(response, "\n")
-		if len(lines) > 2 && strings.HasSuffix(response, "```") {
-			response = strings.Join(lines[1:len(lines)-1], "\n")
-		}
-	} else if strings.HasPrefix(response, "```") && strings.HasSuffix(response, "```") {
-		lines := strings.Split(response, "\n")
-		if len(lines) > 2 {
-			response = strings.Join(lines[1:len(lines)-1], "\n")
-		}
-	}
-
-	return response
+	cleaned, _ := SanitizeResponse(response)
+	return cleaned
 }
 
 // callViaFanout issues one section request against the active shared-prefix
-// cache fan-out and logs its per-section cache write/read usage.
-func (g *Generator) callViaFanout(promptContent string) (string, error) {
-	text, usage, err := g.prefix.Request(context.Background(), promptContent)
+// cache fan-out and logs its per-section cache write/read usage, recording
+// it on span.
+func (g *Generator) callViaFanout(promptContent string, span *telemetry.Span) (string, error) {
+	text, usage, err := g.prefix.Request(g.runContext(), promptContent)
 	g.logFanoutUsage(usage)
+	if usage != nil {
+		span.SetAttributes(
+			telemetry.Int("gen_ai.usage.input_tokens", usage.InputTokens),
+			telemetry.Int("gen_ai.usage.output_tokens", usage.OutputTokens),
+			telemetry.Int("docgen.usage.cache_write_tokens", usage.CacheCreationTokens),
+			telemetry.Int("docgen.usage.cache_read_tokens", usage.CacheReadTokens),
+			telemetry.Float("docgen.usage.est_cost_usd", usage.EstimatedCostUSD))
+	}
 	if err != nil {
 		return "", fmt.Errorf("cache fan-out request failed: %w", err)
 	}
@@ -1903,7 +2574,18 @@ func (g *Generator) logFanoutUsage(u *anthropic.UsageResult) {
 		CacheReadTokens:  u.CacheReadTokens,
 		EstCostUSD:       u.EstimatedCostUSD,
 	})
-	ulog.Info("Cache fan-out usage").
+	g.report.AddUsage(u.Model, u.InputTokens, u.OutputTokens, u.CacheCreationTokens, u.CacheReadTokens, u.EstimatedCostUSD)
+	g.progress.AddTokens(u.OutputTokens)
+	for _, t := range []struct {
+		typ string
+		n   int64
+	}{{"input", u.InputTokens}, {"output", u.OutputTokens}, {"cache_write", u.CacheCreationTokens}, {"cache_read", u.CacheReadTokens}} {
+		telemetry.Add(telemetry.MetricTokens, "{token}", t.n, telemetry.String("model", u.Model), telemetry.String("type", t.typ))
+	}
+	if u.CacheReadTokens > 0 {
+		telemetry.Add(telemetry.MetricCacheHits, "{request}", 1, telemetry.String("model", u.Model))
+	}
+	g.ulog.Info("Cache fan-out usage").
 		Field("section", section).
 		Field("model", u.Model).
 		Field("input", u.InputTokens).
@@ -1977,6 +2659,9 @@ const docsBytesPerToken = 3
 // the configured rules_file preset.
 func (g *Generator) setupFanout(packageDir string, cfg *config.DocgenConfig, opts GenerateOptions) (func(), error) {
 	noop := func() {}
+	if g.batch != nil {
+		return noop, nil
+	}
 
 	prefixModel := opts.Model
 	if prefixModel == "" {
@@ -1991,14 +2676,14 @@ func (g *Generator) setupFanout(packageDir string, cfg *config.DocgenConfig, opt
 
 	// Verify the context fileset before spending: an empty set means cx produced
 	// nothing to cache, so fall back rather than fan out over an empty prefix.
-	ctxFiles := anthropic.WorkDirContextFiles(packageDir)
+	ctxFiles := anthropic.WorkDirContextFiles(g.isolation.path(packageDir))
 	if len(ctxFiles) == 0 {
 		g.logger.Warnf("cache fan-out requested for model %q but cx produced no context in %s; using the standard grove llm path", prefixModel, packageDir)
 		return noop, nil
 	}
 
 	// Window precheck — fail fast and loud before any upload/spend.
-	if err := checkDocsWindow(prefixModel, ctxFiles); err != nil {
+	if err := g.checkDocsWindow(prefixModel, ctxFiles); err != nil {
 		return noop, err
 	}
 
@@ -2010,6 +2695,11 @@ func (g *Generator) setupFanout(packageDir string, cfg *config.DocgenConfig, opt
 		ttl = "5m"
 	}
 
+	if g.storedAnthropicKey() {
+		g.logger.Infof("cache fan-out skipped: the Anthropic key of credentials profile %q is only passed to grove llm; using the standard grove llm path", g.credentials)
+		return noop, nil
+	}
+
 	prefix, err := newDocsSharedPrefix(ctxFiles, prefixModel, ttl)
 	if err != nil {
 		g.logger.WithError(err).Warnf("failed to set up cache fan-out for model %q; using the standard grove llm path", prefixModel)
@@ -2019,7 +2709,7 @@ func (g *Generator) setupFanout(packageDir string, cfg *config.DocgenConfig, opt
 	g.prefix = prefix
 	g.forceModel = prefixModel
 	g.logger.Infof("Cache fan-out enabled: model=%s ttl=%s prefix_docs=%d", prefix.Model(), ttl, len(ctxFiles))
-	ulog.Info("Cache fan-out enabled").
+	g.ulog.Info("Cache fan-out enabled").
 		Field("model", prefix.Model()).
 		Field("ttl", ttl).
 		Field("prefix_docs", len(ctxFiles)).
@@ -2039,7 +2729,7 @@ func (g *Generator) setupFanout(packageDir string, cfg *config.DocgenConfig, opt
 // prefix identically, before any Files-API upload or API spend. An over-window
 // context is a hard, permanent error (the same over-window bytes would 400 every
 // request), so the caller stops rather than falling back.
-func checkDocsWindow(prefixModel string, ctxFiles []string) error {
+func (g *Generator) checkDocsWindow(prefixModel string, ctxFiles []string) error {
 	var ctxBytes int64
 	for _, f := range ctxFiles {
 		if fi, statErr := os.Stat(f); statErr == nil {
@@ -2050,7 +2740,7 @@ func checkDocsWindow(prefixModel string, ctxFiles []string) error {
 		err := fmt.Errorf(
 			"docs context too large for %s: %d file(s), %.2f MB (~%dk tokens at ~%d bytes/token) exceeds the ~%dk-token window — narrow the configured settings.rules_file preset",
 			prefixModel, len(ctxFiles), float64(ctxBytes)/1e6, estTokens/1000, docsBytesPerToken, docsWindowTokens/1000)
-		ulog.Error("Docs context exceeds model window").
+		g.ulog.Error("Docs context exceeds model window").
 			Field("model", prefixModel).
 			Field("context_bytes", ctxBytes).
 			Field("est_tokens", estTokens).
@@ -2080,6 +2770,32 @@ func newDocsSharedPrefix(ctxFiles []string, model, ttl string) (*anthropic.Share
 	})
 }
 
+// storedAnthropicKey reports whether the run's Anthropic key comes from the
+// credential store rather than the environment. The native client behind
+// the shared prefix resolves its own key (ANTHROPIC_API_KEY, then grove.yml)
+// and takes none from the caller, so it would not use that key; grove llm,
+// which gets it in its environment, must make the requests instead. Setting
+// the process environment is no option: packages with other profiles may
+// run in the same process.
+func (g *Generator) storedAnthropicKey() bool {
+	p, err := credentials.LookupProvider("anthropic")
+	if err != nil || p.EnvKey() != "" {
+		return false
+	}
+	return g.providerKey("anthropic") != ""
+}
+
+// credentialEnv returns the environment entries of the provider keys stored
+// for the run's credentials profile that the environment does not set.
+func (g *Generator) credentialEnv() []string {
+	env, err := credentials.Env(g.credentials)
+	if err != nil && !g.credentialsWarned {
+		g.credentialsWarned = true
+		g.recordWarning("Could not read provider keys from the credential store: %v", err)
+	}
+	return env
+}
+
 // lastLines returns the last n lines of s, with surrounding whitespace trimmed.
 // It returns "" if s contains no non-whitespace content.
 func lastLines(s string, n int) string {
@@ -2101,7 +2817,7 @@ func lastLines(s string, n int) string {
 func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *config.DocgenConfig, rulesPath string, opts GenerateOptions) error {
 	docgenDir := filepath.Dir(configPath)
 	g.logger.Infof("Sections mode: scanning subdirectories in %s", docgenDir)
-	ulog.Info("Sections mode").
+	g.ulog.Info("Sections mode").
 		Field("docgenDir", docgenDir).
 		Emit()
 
@@ -2120,6 +2836,9 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 	}
 	defer teardownFanout()
 
+	// Inject the glossary so terminology stays consistent across sections
+	glossaryPrompt := g.glossaryPrompt(packageDir, topCfg)
+
 	// Discover subdirectories with their own docgen.config.yml
 	type subSection struct {
 		subDir  string // subdirectory path (e.g., .../docgen/overview)
@@ -2148,7 +2867,7 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 
 		subCfg, loadErr := config.LoadFromPath(subConfigPath)
 		if loadErr != nil {
-			g.logger.Warnf("Failed to load config from %s: %v", subConfigPath, loadErr)
+			g.recordWarning("Failed to load config from %s: %v", subConfigPath, loadErr)
 			continue
 		}
 
@@ -2232,6 +2951,10 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 		sectionsToGenerate = filtered
 		g.logger.Infof("Generating %d of %d sections: %v", len(sectionsToGenerate), len(allSections), opts.Sections)
 	}
+	// concat sections combine other sections' output, so they run last.
+	sort.SliceStable(sectionsToGenerate, func(i, j int) bool {
+		return sectionsToGenerate[i].section.Type != "concat" && sectionsToGenerate[j].section.Type == "concat"
+	})
 
 	// Pre-spend guard: fail before any LLM call if an in-scope section lacks an
 	// output: filename (an empty output writes onto the output dir itself). Names
@@ -2246,9 +2969,10 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 		return err
 	}
 
-	// Pre-spend guard: every in-scope prose section's prompt file must exist in
-	// its subdirectory's prompts/ dir (the exact path the loop below reads)
-	// before any LLM call, listing ALL missing prompts in one error.
+	// Pre-spend guard: the prompt file of every in-scope section that reads
+	// one must exist in its subdirectory's prompts/ dir (the exact path the
+	// loop below reads) before any LLM call, listing ALL missing prompts in
+	// one error.
 	if err := validateSectionPrompts(scoped, func(i int, _ config.SectionConfig) (string, error) {
 		p := filepath.Join(sectionsToGenerate[i].subDir, "prompts", sectionsToGenerate[i].section.Prompt)
 		if _, serr := os.Stat(p); serr != nil {
@@ -2268,7 +2992,22 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 		failedSections = append(failedSections, name)
 		g.recordSectionFailure(name, err)
 	}
-	for _, ss := range sectionsToGenerate {
+	var skippedSections []string
+	g.progress.AddTotal(len(sectionsToGenerate))
+	for i, ss := range sectionsToGenerate {
+		if opts.FailFast && len(failedSections) > 0 {
+			for _, rest := range sectionsToGenerate[i:] {
+				skippedSections = append(skippedSections, qualifiedName(rest))
+			}
+			break
+		}
+		if err := g.cancelled(); err != nil {
+			g.endSection()
+			return err
+		}
+		if g.batch.collecting() && !isProseSection(ss.section.Type) {
+			continue
+		}
 		g.currentSection = qualifiedName(ss)
 		g.logger.Infof("Generating section: %s", qualifiedName(ss))
 
@@ -2277,6 +3016,7 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 		if ss.subCfg.Settings.OutputDir != "" {
 			outputDir = filepath.Join(ss.subDir, ss.subCfg.Settings.OutputDir)
 		}
+		g.startSection(qualifiedName(ss), ss.section.Type, filepath.Join(outputDir, ss.section.Output))
 
 		// Handle special section types that don't use prompt files
 		if ss.section.Type == "schema_to_md" {
@@ -2335,6 +3075,104 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 			}
 			continue
 		}
+		if ss.section.Type == "concat" {
+			if err := g.generateFromConcat(ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("Concat generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "faq_from_issues" {
+			if err := g.generateFromIssues(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("FAQ generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "error_reference" {
+			if err := g.generateErrorReference(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("Error reference generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "metrics_reference" {
+			if err := g.generateMetricsReference(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("Metrics reference generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "http_routes" {
+			if err := g.generateHTTPRoutes(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("HTTP route reference generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "make_targets" {
+			if err := g.generateMakeTargets(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("Task runner target generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "ci_workflows" {
+			if err := g.generateCIWorkflows(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("CI workflow generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "contributors" {
+			if err := g.generateContributors(packageDir, ss.section, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("Contributors generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "licenses" {
+			if err := g.generateLicenses(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("License generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "sql_schema" {
+			if err := g.generateSQLSchema(packageDir, ss.section, ss.subCfg, outputDir); err != nil {
+				g.logger.WithError(err).Errorf("SQL schema generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "tutorial" {
+			promptPath := filepath.Join(ss.subDir, "prompts", ss.section.Prompt)
+			scenario, err := os.ReadFile(promptPath)
+			if err != nil {
+				g.logger.WithError(err).Errorf("Could not read scenario for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), fmt.Errorf("could not read prompt for section '%s' at %s: %w", ss.section.Name, promptPath, err))
+				continue
+			}
+			if err := g.generateFromTutorial(packageDir, ss.section, ss.subCfg, outputDir, string(scenario)); err != nil {
+				g.logger.WithError(err).Errorf("Tutorial generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
+		if ss.section.Type == "template" {
+			templatePath := filepath.Join(ss.subDir, "prompts", ss.section.Prompt)
+			skeleton, err := os.ReadFile(templatePath)
+			if err != nil {
+				g.logger.WithError(err).Errorf("Could not read template for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), fmt.Errorf("could not read template for section '%s' at %s: %w", ss.section.Name, templatePath, err))
+				continue
+			}
+			if err := g.generateFromTemplate(packageDir, ss.section, ss.subCfg, outputDir, string(skeleton)); err != nil {
+				g.logger.WithError(err).Errorf("Template generation failed for section '%s'", ss.section.Name)
+				sectionFailed(qualifiedName(ss), err)
+			}
+			continue
+		}
 
 		// Standard prompt-based generation
 		// Resolve prompt from the subdirectory's prompts/ folder
@@ -2345,7 +3183,7 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 		}
 
 		// Build the final prompt with system prompt if configured
-		finalPrompt := string(promptContent)
+		finalPrompt := g.expandSnippets(packageDir, string(promptContent))
 		if ss.subCfg.Settings.SystemPrompt != "" {
 			if ss.subCfg.Settings.SystemPrompt == "default" {
 				finalPrompt = DefaultSystemPrompt + "\n" + finalPrompt
@@ -2353,9 +3191,14 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 				systemPromptPath := filepath.Join(ss.subDir, ss.subCfg.Settings.SystemPrompt)
 				if content, readErr := os.ReadFile(systemPromptPath); readErr == nil {
 					finalPrompt = string(content) + "\n" + finalPrompt
+				} else {
+					g.recordWarning("Failed to load system prompt from %s, proceeding without it", systemPromptPath)
 				}
 			}
 		}
+		if glossaryPrompt != "" {
+			finalPrompt = glossaryPrompt + "\n" + finalPrompt
+		}
 
 		// Handle reference mode
 		if ss.subCfg.Settings.RegenerationMode == "reference" {
@@ -2367,6 +3210,13 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 			}
 		}
 
+		if ss.subCfg.Settings.Citations {
+			finalPrompt += citations.Instructions
+		}
+		if transformer.IsMDX(ss.section.Output) {
+			finalPrompt += mdxPrompt(ss.subCfg.ComponentMappings(nil))
+		}
+
 		// Determine model (section override > sub-config > top-level)
 		model := topCfg.Settings.Model
 		if ss.subCfg.Settings.Model != "" {
@@ -2378,30 +3228,47 @@ func (g *Generator) generateSectionsMode(packageDir, configPath string, topCfg *
 
 		genConfig := config.MergeGenerationConfig(ss.subCfg.Settings.GenerationConfig, ss.section.GenerationConfig)
 
-		output, err := g.CallLLM(finalPrompt, model, genConfig, packageDir)
+		var output string
+		g.useContextBudget(config.MergeContextBudget(ss.subCfg.Settings.Context, ss.section.Context), ss.section, string(promptContent))
+		if ss.subCfg.Settings.Questions {
+			output, err = g.callWithQuestions(ss.section.Name, finalPrompt, model, genConfig, packageDir, filepath.Join(ss.subDir, AnswersFileName))
+		} else {
+			output, err = g.CallLLM(finalPrompt, model, genConfig, packageDir)
+		}
+		g.useContextBudget(nil, ss.section, "")
 		if err != nil {
 			g.logger.WithError(err).Errorf("LLM call failed for section '%s'", ss.section.Name)
 			sectionFailed(qualifiedName(ss), err)
 			continue
 		}
+		output = g.extractDocument(output, model, ss.subCfg, genConfig, packageDir)
+
+		// Inline any snippet references the model kept from the prompt
+		output = g.expandSnippets(packageDir, output)
+		output = transformer.InferCodeLanguages(output)
+		output = g.postprocess(qualifiedName(ss), output, ss.subCfg.PostprocessSteps(ss.section), packageDir)
+		if transformer.IsMDX(ss.section.Output) {
+			output = g.prepareMDX(qualifiedName(ss), output, ss.subCfg.ComponentMappings(nil))
+		}
+		g.checkAnchors(qualifiedName(ss), output)
 
 		// Write output to the subdirectory's docs/ folder
 		outputPath := filepath.Join(outputDir, ss.section.Output)
-		if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
-		}
-		if err := os.WriteFile(outputPath, []byte(output), 0o644); err != nil {
+		if err := g.writeOutput(outputPath, output); err != nil {
 			return fmt.Errorf("failed to write section output: %w", err)
 		}
+		if ss.subCfg.Settings.Citations {
+			g.checkCitations(qualifiedName(ss), output, outputPath, packageDir)
+		}
+		g.checkReferences(packageDir, qualifiedName(ss), outputPath, output)
 		g.logger.Infof("Successfully wrote section '%s' to %s", ss.section.Name, outputPath)
-		ulog.Success("Wrote section").
+		g.ulog.Success("Wrote section").
 			Field("section", ss.section.Name).
 			Field("path", outputPath).
 			Emit()
 	}
 
-	if len(failedSections) > 0 {
-		return g.failedSectionsError(failedSections)
-	}
-	return nil
+	g.endSection()
+
+	return g.finishSections(len(sectionsToGenerate), failedSections, skippedSections)
 }
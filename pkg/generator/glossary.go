@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/glossary"
+)
+
+const glossaryExtractPrompt = `You are building the terminology glossary of a software project's documentation.
+
+Read the documentation below and list the domain terms a reader must understand: project-specific concepts, components and named features. Skip generic programming vocabulary and plain English words.
+
+For each term give:
+- "term": the canonical spelling and capitalization used by the docs.
+- "definition": one sentence defining it for a new user.
+- "aliases": other spellings the docs use for the same thing that are acceptable.
+- "avoid": names used for the same thing that conflict with the canonical term and should be replaced.
+
+When a term is already in the existing glossary, use its spelling exactly.
+
+Output format (JSON only, no markdown fences):
+[
+  {"term": "...", "definition": "...", "aliases": [], "avoid": []}
+]
+`
+
+// glossaryPrompt returns the glossary prompt section for a package, or "" when
+// it has no glossary.
+func (g *Generator) glossaryPrompt(packageDir string, cfg *config.DocgenConfig) string {
+	path := glossary.Find(packageDir, cfg.Settings.Glossary)
+	if path == "" {
+		return ""
+	}
+	gl, err := glossary.Load(path)
+	if err != nil {
+		g.logger.Warnf("Failed to load glossary, proceeding without it: %v", err)
+		return ""
+	}
+	g.logger.Debugf("Injecting glossary from %s (%d terms)", path, len(gl.Terms))
+	return gl.Prompt()
+}
+
+// BuildGlossary extracts the domain terms from each package's generated
+// markdown sections with the LLM and merges them into gl. Packages without a
+// docgen config or generated docs are skipped.
+func (g *Generator) BuildGlossary(packageDirs []string, gl *glossary.Glossary) error {
+	for _, packageDir := range packageDirs {
+		pkgName := filepath.Base(packageDir)
+		targets, err := ResolveSectionTargets(packageDir)
+		if err != nil || len(targets) == 0 {
+			g.logger.Debugf("Skipping %s: no docgen config", pkgName)
+			continue
+		}
+
+		var docs strings.Builder
+		for _, t := range targets {
+			if t.Section.Type == "concat" || !strings.HasSuffix(t.Section.Output, ".md") {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(t.OutputDir, t.Section.Output)) //nolint:gosec // path from config
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(&docs, "<doc section=%q>\n%s\n</doc>\n\n", t.Name, stripFrontmatter(string(data)))
+		}
+		if docs.Len() == 0 {
+			g.logger.Debugf("Skipping %s: no generated docs", pkgName)
+			continue
+		}
+
+		var prompt strings.Builder
+		prompt.WriteString(glossaryExtractPrompt)
+		if len(gl.Terms) > 0 {
+			prompt.WriteString("\nExisting glossary terms:\n")
+			for _, t := range gl.Terms {
+				fmt.Fprintf(&prompt, "- %s\n", t.Term)
+			}
+		}
+		fmt.Fprintf(&prompt, "\n--- DOCUMENTATION (%s) ---\n\n%s", pkgName, docs.String())
+
+		cfg := targets[0].Config
+		model := cfg.Settings.Model
+		if model == "" {
+			model = "gemini-3-pro-preview"
+		}
+
+		g.logger.Infof("Extracting glossary terms from %s", pkgName)
+		response, err := g.CallLLM(prompt.String(), model, cfg.Settings.GenerationConfig, packageDir)
+		if err != nil {
+			return fmt.Errorf("LLM generation failed for %s: %w", pkgName, err)
+		}
+
+		cleanResponse := strings.TrimSpace(response)
+		cleanResponse = strings.TrimPrefix(cleanResponse, "```json")
+		cleanResponse = strings.TrimPrefix(cleanResponse, "```")
+		cleanResponse = strings.TrimSuffix(cleanResponse, "```")
+		cleanResponse = strings.TrimSpace(cleanResponse)
+
+		var extracted []struct {
+			Term       string   `json:"term"`
+			Definition string   `json:"definition"`
+			Aliases    []string `json:"aliases"`
+			Avoid      []string `json:"avoid"`
+		}
+		if err := json.Unmarshal([]byte(cleanResponse), &extracted); err != nil {
+			return fmt.Errorf("failed to parse LLM response for %s as JSON: %w\nResponse: %s", pkgName, err, response)
+		}
+
+		terms := make([]glossary.Term, 0, len(extracted))
+		for _, e := range extracted {
+			terms = append(terms, glossary.Term{
+				Term:       e.Term,
+				Definition: e.Definition,
+				Aliases:    e.Aliases,
+				Avoid:      e.Avoid,
+				Packages:   []string{pkgName},
+			})
+		}
+		gl.Merge(terms...)
+		g.logger.Infof("Extracted %d terms from %s", len(terms), pkgName)
+	}
+	return nil
+}
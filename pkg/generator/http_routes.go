@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/reference"
+)
+
+// generateHTTPRoutes builds an endpoint reference from the chi, gin and
+// net/http route registrations in the package's code, merged with any
+// OpenAPI specs listed in source. Directories in source limit the code scan;
+// with only specs listed, the whole module is scanned.
+func (g *Generator) generateHTTPRoutes(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating HTTP route reference: %s", section.Name)
+
+	var dirs, specs []string
+	for _, src := range section.Source {
+		info, err := os.Stat(filepath.Join(packageDir, src))
+		if err != nil {
+			return fmt.Errorf("routes source %s: %w", src, err)
+		}
+		if info.IsDir() {
+			dirs = append(dirs, src)
+		} else {
+			specs = append(specs, src)
+		}
+	}
+
+	routes, err := reference.Routes(packageDir, dirs)
+	if err != nil {
+		return fmt.Errorf("failed to scan routes: %w", err)
+	}
+	for _, spec := range specs {
+		data, err := os.ReadFile(filepath.Join(packageDir, spec)) //nolint:gosec // path from config
+		if err != nil {
+			return fmt.Errorf("failed to read OpenAPI spec %s: %w", spec, err)
+		}
+		if routes, err = reference.MergeOpenAPI(routes, data); err != nil {
+			return fmt.Errorf("%s: %w", spec, err)
+		}
+	}
+	if len(routes) == 0 {
+		return fmt.Errorf("no HTTP routes found")
+	}
+
+	var sb strings.Builder
+	title := section.Title
+	if title == "" {
+		title = "HTTP API Reference"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	sb.WriteString("| Method | Path | Handler | Middleware | Description |\n")
+	sb.WriteString("| :--- | :--- | :--- | :--- | :--- |\n")
+	for _, r := range routes {
+		handler := ""
+		if r.Handler != "" {
+			handler = "`" + tableCell(r.Handler) + "`"
+		}
+		middleware := make([]string, len(r.Middleware))
+		for i, m := range r.Middleware {
+			middleware[i] = "`" + tableCell(m) + "`"
+		}
+		fmt.Fprintf(&sb, "| %s | `%s` | %s | %s | %s |\n", r.Method, tableCell(r.Path), handler, strings.Join(middleware, ", "), tableCell(r.Summary))
+	}
+
+	outputPath, err := writeReference(section, outputBaseDir, sb.String())
+	if err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote %d routes to %s", len(routes), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("routes", len(routes)).
+		Emit()
+	return nil
+}
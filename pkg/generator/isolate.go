@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// isolation is a temporary git worktree that a run's context tooling (cx
+// generate, grove llm) works in instead of the user's checkout, so the
+// context files it writes never dirty the working tree and several packages
+// of one repository can generate at the same time. The worktree holds the
+// working tree as it is, uncommitted and untracked files included; generated
+// docs are still written to the package's real output directory.
+type isolation struct {
+	repoRoot string // Root of the user's repository
+	tmpDir   string // Temporary directory holding the worktree
+	dir      string // Root of the worktree
+}
+
+// newIsolation checks out HEAD of the repository containing packageDir into
+// a temporary worktree and overlays the uncommitted changes.
+func newIsolation(ctx context.Context, packageDir string) (*isolation, error) {
+	out, err := gitOutput(ctx, packageDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("--isolate needs a git repository: %w", err)
+	}
+	repoRoot := strings.TrimSpace(string(out))
+
+	tmpDir, err := os.MkdirTemp("", "docgen-isolate-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	iso := &isolation{repoRoot: repoRoot, tmpDir: tmpDir, dir: filepath.Join(tmpDir, filepath.Base(repoRoot))}
+	if _, err := gitOutput(ctx, repoRoot, "worktree", "add", "--detach", iso.dir, "HEAD"); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("failed to add worktree: %w", err)
+	}
+	if err := iso.overlay(ctx); err != nil {
+		iso.remove()
+		return nil, err
+	}
+	return iso, nil
+}
+
+// overlay copies modified and untracked (non-ignored) files into the
+// worktree and deletes the files removed from the working tree.
+func (iso *isolation) overlay(ctx context.Context) error {
+	changed, err := gitOutput(ctx, iso.repoRoot, "ls-files", "-z", "--modified", "--others", "--exclude-standard")
+	if err != nil {
+		return fmt.Errorf("failed to list working tree changes: %w", err)
+	}
+	for _, rel := range strings.Split(string(changed), "\x00") {
+		if rel == "" {
+			continue
+		}
+		src := filepath.Join(iso.repoRoot, rel)
+		info, err := os.Lstat(src)
+		if err != nil || !info.Mode().IsRegular() {
+			continue // Deleted (handled below), or not a plain file
+		}
+		if err := copyFile(src, filepath.Join(iso.dir, rel), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to copy %s into worktree: %w", rel, err)
+		}
+	}
+
+	deleted, err := gitOutput(ctx, iso.repoRoot, "ls-files", "-z", "--deleted")
+	if err != nil {
+		return fmt.Errorf("failed to list deleted files: %w", err)
+	}
+	for _, rel := range strings.Split(string(deleted), "\x00") {
+		if rel != "" {
+			_ = os.Remove(filepath.Join(iso.dir, rel))
+		}
+	}
+	return nil
+}
+
+// path maps a directory inside the user's repository to the same directory
+// in the worktree; other directories are returned unchanged.
+func (iso *isolation) path(dir string) string {
+	if iso == nil || dir == "" {
+		return dir
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(iso.repoRoot, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return dir
+	}
+	return filepath.Join(iso.dir, rel)
+}
+
+// remove deletes the worktree and its registration in the repository.
+func (iso *isolation) remove() {
+	_, _ = gitOutput(context.Background(), iso.repoRoot, "worktree", "remove", "--force", iso.dir)
+	_ = os.RemoveAll(iso.tmpDir)
+	_, _ = gitOutput(context.Background(), iso.repoRoot, "worktree", "prune")
+}
+
+// gitOutput runs git in dir and returns its stdout, with stderr in the error.
+func gitOutput(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// copyFile copies src to dst, creating dst's directory.
+func copyFile(src, dst string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck // read-only
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
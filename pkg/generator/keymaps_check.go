@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/docgen/pkg/diff"
+)
+
+// KeymapsDrift reports a file of a tui_keymaps section that no longer matches
+// the current keybinding registry.
+type KeymapsDrift struct {
+	Section string `json:"section"`
+	Output  string `json:"output"`
+	Missing bool   `json:"missing,omitempty"` // The doc has never been generated
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// CheckTUIKeymaps re-renders every tui_keymaps section (or only the named
+// ones) from the current registry and compares the result with the doc on
+// disk, so keybinding docs can't silently drift from the code. It returns one
+// entry per stale file.
+func (g *Generator) CheckTUIKeymaps(packageDir string, sections []string) ([]KeymapsDrift, error) {
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range sections {
+		wanted[name] = true
+	}
+
+	var drifts []KeymapsDrift
+	checked := 0
+	for _, t := range targets {
+		if t.Section.Type != "tui_keymaps" {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[t.Name] && !wanted[t.Section.Name] {
+			continue
+		}
+		checked++
+
+		files, err := g.renderTUIKeymaps(packageDir, t.Section, t.Config, t.OutputDir)
+		if err != nil {
+			return nil, fmt.Errorf("section '%s': %w", t.Name, err)
+		}
+
+		for _, rel := range sortedKeys(files) {
+			fresh := files[rel]
+			outputPath := filepath.Join(t.OutputDir, rel)
+			existing, err := os.ReadFile(outputPath) //nolint:gosec // path from config
+			if os.IsNotExist(err) {
+				drifts = append(drifts, KeymapsDrift{Section: t.Name, Output: outputPath, Missing: true})
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", outputPath, err)
+			}
+			if string(existing) == fresh {
+				continue
+			}
+
+			if isBinaryDoc(rel) {
+				drifts = append(drifts, KeymapsDrift{Section: t.Name, Output: outputPath, Diff: fmt.Sprintf("Binary files %s and registry differ\n", outputPath)})
+				continue
+			}
+			added, removed := diff.Stats(string(existing), fresh)
+			drifts = append(drifts, KeymapsDrift{
+				Section: t.Name,
+				Output:  outputPath,
+				Added:   added,
+				Removed: removed,
+				Diff:    diff.Unified(outputPath, "registry", string(existing), fresh),
+			})
+		}
+	}
+
+	if checked == 0 {
+		return nil, fmt.Errorf("no tui_keymaps sections found to check")
+	}
+	return drifts, nil
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/reference"
+)
+
+// generateLicenses writes the third-party notices of the package: the Go
+// modules its packages (source, default ./...) link in, with their licenses
+// and the text of their license and notice files.
+func (g *Generator) generateLicenses(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating third-party notices: %s", section.Name)
+
+	modules, err := reference.Licenses(packageDir, section.Source)
+	if err != nil {
+		return fmt.Errorf("failed to analyze dependencies: %w", err)
+	}
+
+	var sb strings.Builder
+	title := section.Title
+	if title == "" {
+		title = "Third-Party Licenses"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	if len(modules) == 0 {
+		fmt.Fprintf(&sb, "%s does not include third-party Go modules.\n", cfg.Title)
+	} else {
+		fmt.Fprintf(&sb, "%s includes the following third-party Go modules, distributed under the licenses reproduced below.\n\n", cfg.Title)
+		sb.WriteString("| Module | Version | License |\n")
+		sb.WriteString("| :--- | :--- | :--- |\n")
+		for _, m := range modules {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s |\n", m.Path, tableCell(m.Version), m.License)
+		}
+
+		sb.WriteString("\n## License Texts\n")
+		for _, m := range modules {
+			if m.License == reference.UnknownLicense {
+				g.recordWarning("Section %q: could not determine the license of %s", section.Name, m.Path)
+			}
+			heading := m.Path
+			if m.Version != "" {
+				heading += " " + m.Version
+			}
+			fmt.Fprintf(&sb, "\n### %s\n\n", heading)
+			names := m.FileNames()
+			if len(names) == 0 {
+				sb.WriteString("No license file found.\n")
+				continue
+			}
+			for _, name := range names {
+				fmt.Fprintf(&sb, "`%s`:\n\n", name)
+				text := strings.TrimSpace(m.Files[name])
+				fence := "```"
+				for strings.Contains(text, fence) {
+					fence += "`"
+				}
+				fmt.Fprintf(&sb, "%stext\n%s\n%s\n\n", fence, text, fence)
+			}
+		}
+	}
+
+	outputPath, err := writeReference(section, outputBaseDir, strings.TrimRight(sb.String(), "\n")+"\n")
+	if err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote %d modules to %s", len(modules), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("modules", len(modules)).
+		Emit()
+	return nil
+}
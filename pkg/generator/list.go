@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// SectionInfo summarizes one configured section for `docgen list sections`.
+type SectionInfo struct {
+	Name          string     `json:"name"` // Qualified name (subdir/section) in sections mode
+	Title         string     `json:"title,omitempty"`
+	Type          string     `json:"type"` // "prose" for prompt-driven sections
+	Status        string     `json:"status"`
+	Output        string     `json:"output"` // Absolute path of the generated file
+	Model         string     `json:"model,omitempty"`
+	LastGenerated *time.Time `json:"last_generated,omitempty"` // Modification time of the output; nil if never generated
+}
+
+// ListSections returns every section configured for the package at
+// packageDir, in config order, with its effective model and when its output
+// was last written.
+func ListSections(packageDir string) ([]SectionInfo, error) {
+	cfg, _, err := config.LoadWithNotebook(packageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load docgen config: %w", err)
+	}
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]SectionInfo, 0, len(targets))
+	for _, t := range targets {
+		info := SectionInfo{
+			Name:   t.Name,
+			Title:  t.Section.Title,
+			Type:   t.Section.Type,
+			Status: t.Section.GetStatus(),
+			Output: filepath.Join(t.OutputDir, t.Section.Output),
+			Model:  t.Section.Model,
+		}
+		if info.Type == "" {
+			info.Type = "prose"
+		}
+		if info.Model == "" {
+			info.Model = t.Config.Settings.Model
+		}
+		if info.Model == "" {
+			// Sections mode falls back to the top-level config's model
+			info.Model = cfg.Settings.Model
+		}
+		if t.Section.Output != "" {
+			if stat, err := os.Stat(info.Output); err == nil {
+				mod := stat.ModTime()
+				info.LastGenerated = &mod
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// PackageInfo summarizes one package's docgen config for `docgen list packages`.
+type PackageInfo struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Title      string `json:"title,omitempty"`
+	Enabled    bool   `json:"enabled"`
+	Category   string `json:"category,omitempty"`
+	OutputMode string `json:"output_mode,omitempty"`
+	Sections   int    `json:"sections"`
+	Production int    `json:"production"`
+	Dev        int    `json:"dev"`
+	Draft      int    `json:"draft"`
+}
+
+// DescribePackage loads the docgen config of the package at dir and counts
+// its sections by status. It returns os.ErrNotExist when the package has no
+// docgen config.
+func DescribePackage(name, dir string) (*PackageInfo, error) {
+	cfg, _, err := config.LoadWithNotebook(dir)
+	if err != nil {
+		return nil, err
+	}
+	info := &PackageInfo{
+		Name:       name,
+		Path:       dir,
+		Title:      cfg.Title,
+		Enabled:    cfg.Enabled,
+		Category:   cfg.Category,
+		OutputMode: cfg.Settings.OutputMode,
+	}
+	targets, err := ResolveSectionTargets(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve sections of %s: %w", name, err)
+	}
+	for _, t := range targets {
+		info.Sections++
+		switch t.Section.GetStatus() {
+		case config.StatusProduction:
+			info.Production++
+		case config.StatusDev:
+			info.Dev++
+		default:
+			info.Draft++
+		}
+	}
+	return info, nil
+}
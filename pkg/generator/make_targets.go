@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/reference"
+)
+
+const targetDescriptionPrompt = `You are documenting the developer workflow of a software project.
+
+Below, as JSON, are task runner targets (make, task or just) that have no description, with their dependencies and recipe commands. Using the project context you have been given, describe what each target does in one short sentence in the imperative mood, as a Makefile help comment would (e.g. "Build the CLI into bin/").
+
+Respond with only a JSON object mapping each target's "runner name" (e.g. "make build") to its description.`
+
+// generateMakeTargets documents the developer workflow from the package's
+// Makefile, Taskfile and justfile: one table of targets per file with their
+// descriptions and dependencies. Targets without a comment are described by
+// the LLM unless llm is false.
+func (g *Generator) generateMakeTargets(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating task runner targets: %s", section.Name)
+
+	files := []string(section.Source)
+	if len(files) == 0 {
+		for _, name := range reference.TaskFiles {
+			if _, err := os.Stat(filepath.Join(packageDir, name)); err == nil {
+				files = append(files, name)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no Makefile, Taskfile or justfile found (set 'source')")
+	}
+
+	type fileTargets struct {
+		file    string
+		targets []reference.Target
+	}
+	var all []fileTargets
+	var undescribed []reference.Target
+	total := 0
+	for _, file := range files {
+		targets, err := reference.Targets(filepath.Join(packageDir, file))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		for _, t := range targets {
+			if t.Description == "" {
+				undescribed = append(undescribed, t)
+			}
+		}
+		total += len(targets)
+		all = append(all, fileTargets{file: file, targets: targets})
+	}
+	if total == 0 {
+		return fmt.Errorf("no targets found in %s", strings.Join(files, ", "))
+	}
+
+	var generated map[string]string
+	if len(undescribed) > 0 && section.UsesLLM() {
+		g.logger.Infof("Describing %d targets without comments", len(undescribed))
+		if err := g.callReferenceLLM(packageDir, section, cfg, targetDescriptionPrompt, undescribed, &generated); err != nil {
+			return err
+		}
+	}
+
+	var sb strings.Builder
+	title := section.Title
+	if title == "" {
+		title = "Development Tasks"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	for _, ft := range all {
+		if len(ft.targets) == 0 {
+			continue
+		}
+		if len(all) > 1 {
+			fmt.Fprintf(&sb, "## `%s`\n\n", ft.file)
+		}
+		sb.WriteString("| Command | Description | Depends on |\n")
+		sb.WriteString("| :--- | :--- | :--- |\n")
+		for _, t := range ft.targets {
+			command := t.Runner + " " + t.Name
+			desc := t.Description
+			if desc == "" {
+				desc = generated[command]
+			}
+			deps := make([]string, len(t.Deps))
+			for i, d := range t.Deps {
+				deps[i] = "`" + tableCell(d) + "`"
+			}
+			fmt.Fprintf(&sb, "| `%s` | %s | %s |\n", tableCell(command), tableCell(desc), strings.Join(deps, ", "))
+		}
+		sb.WriteString("\n")
+	}
+
+	outputPath, err := writeReference(section, outputBaseDir, strings.TrimRight(sb.String(), "\n")+"\n")
+	if err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote %d targets to %s", total, outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("targets", total).
+		Emit()
+	return nil
+}
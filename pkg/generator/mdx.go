@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// mdxPrompt tells the model a section is MDX and which components it may
+// use. docgen adds the imports, so the model must not write them.
+func mdxPrompt(mappings []config.ComponentMapping) string {
+	var sb strings.Builder
+	sb.WriteString("\n\n---\n\nThis page is MDX. Write it as markdown; do not write import or export statements.")
+	if names := componentNames(mappings); len(names) > 0 {
+		fmt.Fprintf(&sb, " Where they help the reader, you may use these components as JSX elements: %s.", strings.Join(names, ", "))
+	}
+	return sb.String()
+}
+
+func componentNames(mappings []config.ComponentMapping) []string {
+	names := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		names = append(names, m.Component)
+	}
+	return names
+}
+
+// prepareMDX escapes what MDX would read as syntax in the model's output for
+// an MDX section, and warns when the result still does not parse as MDX.
+func (g *Generator) prepareMDX(section, output string, mappings []config.ComponentMapping) string {
+	output = transformer.EscapeMDX(output, componentNames(mappings))
+	g.checkMDX(section, output)
+	return output
+}
+
+// checkMDX warns when an MDX section does not parse as MDX. HTML comments,
+// such as citations, are fine; aggregate rewrites them.
+func (g *Generator) checkMDX(section, output string) {
+	for _, err := range transformer.ValidateMDX(transformer.PrepareMDX(output, nil, nil)) {
+		g.recordWarning("Section %q is not valid MDX: %v", section, err)
+	}
+}
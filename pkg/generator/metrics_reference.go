@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/reference"
+)
+
+// generateMetricsReference builds a table of the package's metrics from its
+// Prometheus registrations and from any OpenMetrics descriptor files listed
+// in source. Directories in source limit the code scan; with only descriptor
+// files listed, the code is not scanned.
+func (g *Generator) generateMetricsReference(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating metrics reference: %s", section.Name)
+
+	var dirs []string
+	var described []reference.Metric
+	for _, src := range section.Source {
+		path := filepath.Join(packageDir, src)
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("metrics source %s: %w", src, err)
+		}
+		if info.IsDir() {
+			dirs = append(dirs, src)
+			continue
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // path from config
+		if err != nil {
+			return fmt.Errorf("failed to read metrics descriptor %s: %w", src, err)
+		}
+		described = append(described, reference.ParseOpenMetrics(src, string(data))...)
+	}
+
+	var registered []reference.Metric
+	if len(dirs) > 0 || len(section.Source) == 0 {
+		var err error
+		registered, err = reference.Metrics(packageDir, dirs)
+		if err != nil {
+			return fmt.Errorf("failed to scan metrics: %w", err)
+		}
+	}
+	metrics := reference.MergeMetrics(registered, described)
+	if len(metrics) == 0 {
+		return fmt.Errorf("no metrics found")
+	}
+
+	var sb strings.Builder
+	title := section.Title
+	if title == "" {
+		title = "Metrics Reference"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	sb.WriteString("| Metric | Type | Labels | Description |\n")
+	sb.WriteString("| :--- | :--- | :--- | :--- |\n")
+	for _, m := range metrics {
+		labels := make([]string, len(m.Labels))
+		for i, l := range m.Labels {
+			labels[i] = "`" + l + "`"
+		}
+		fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n", m.Name, m.Type, strings.Join(labels, ", "), tableCell(m.Help))
+	}
+
+	outputPath, err := writeReference(section, outputBaseDir, sb.String())
+	if err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote %d metrics to %s", len(metrics), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("metrics", len(metrics)).
+		Emit()
+	return nil
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/ollama"
+	"github.com/grovetools/docgen/pkg/telemetry"
+)
+
+// ollamaSummaryPrompt asks a local model to condense one chunk of a cx
+// context that does not fit its context window.
+const ollamaSummaryPrompt = `The text below is part of the source code of a software project, or a summary of part of it. Summarize it for a technical writer who will document the project without seeing the code.
+
+Keep the names of packages, public functions, types, commands, flags, configuration keys, environment variables and file paths exactly as written, with the signatures of public APIs. Say what each one does, its defaults and its errors, and keep short usage examples. Leave out private helpers and implementation details. Reply with the summary only.
+
+---
+
+`
+
+// ollamaMaxRounds bounds the rounds of summarization fitting a context into
+// a model's window; each round summarizes the summaries of the last.
+const ollamaMaxRounds = 4
+
+// ollamaRun is the state of the Ollama requests of a generator: the
+// context windows of its models and the summaries of the context chunks
+// they have made, which every section of the run reuses.
+type ollamaRun struct {
+	client    *ollama.Client
+	windows   map[string]int
+	summaries map[string]string // sha256 of model, window and chunk -> summary
+}
+
+// ollamaState returns the Ollama state for the server of settings.ollama,
+// starting over when the server changed.
+func (g *Generator) ollamaState() *ollamaRun {
+	var host string
+	if g.ollamaConfig != nil {
+		host = g.ollamaConfig.Host
+	}
+	client := ollama.New(host)
+	if g.ollama == nil || g.ollama.client.Host() != client.Host() {
+		g.ollama = &ollamaRun{client: client, windows: make(map[string]int), summaries: make(map[string]string)}
+	}
+	return g.ollama
+}
+
+// ollamaWindow returns the context window to run model with:
+// settings.ollama.context_window, or what the server reports for the model.
+func (g *Generator) ollamaWindow(o *ollamaRun, model string) (int, error) {
+	if g.ollamaConfig != nil && g.ollamaConfig.ContextWindow > 0 {
+		return g.ollamaConfig.ContextWindow, nil
+	}
+	if n, ok := o.windows[model]; ok {
+		return n, nil
+	}
+	n, err := o.client.ContextWindow(g.runContext(), model)
+	if err != nil {
+		return 0, err
+	}
+	o.windows[model] = n
+	return n, nil
+}
+
+// callOllama makes an LLM request to a local model served by Ollama. The
+// cx context, reduced to the section's budget, is sent with the prompt, as
+// grove llm would send it; when the two do not fit the model's context
+// window with room for the response, the context is summarized to fit first
+// (see fitContext).
+func (g *Generator) callOllama(promptContent, model string, genConfig config.GenerationConfig, workDir string, span *telemetry.Span) (string, error) {
+	o := g.ollamaState()
+	name := ollama.ModelName(model)
+	window, err := g.ollamaWindow(o, name)
+	if err != nil {
+		return "", err
+	}
+	opts := ollama.Options{
+		NumCtx:      window,
+		Temperature: genConfig.Temperature,
+		TopP:        genConfig.TopP,
+		TopK:        genConfig.TopK,
+		NumPredict:  genConfig.MaxOutputTokens,
+	}
+
+	// A quarter of the window is left for the response unless the section
+	// sets its own limit
+	reserve := window / 4
+	if genConfig.MaxOutputTokens != nil {
+		reserve = int(*genConfig.MaxOutputTokens)
+	}
+	budget := window - reserve - len(promptContent)/docsBytesPerToken
+	if budget <= 0 {
+		return "", fmt.Errorf("the prompt (~%d tokens) leaves no room for context in the %d-token window of %s; raise settings.ollama.context_window",
+			len(promptContent)/docsBytesPerToken, window, model)
+	}
+
+	cxContext, _, err := g.sectionContext(workDir)
+	if err != nil {
+		return "", err
+	}
+	if len(cxContext)/docsBytesPerToken > budget {
+		if cxContext, err = g.fitContext(o, name, cxContext, budget, opts); err != nil {
+			if cerr := g.cancelled(); cerr != nil {
+				return "", cerr
+			}
+			return "", err
+		}
+	}
+
+	resp, err := o.client.Generate(g.runContext(), name, withContext(cxContext, promptContent), opts)
+	if err != nil {
+		if cerr := g.cancelled(); cerr != nil {
+			return "", cerr
+		}
+		return "", fmt.Errorf("ollama request failed: %w", err)
+	}
+	g.recordOllamaUsage(model, resp)
+	span.SetAttributes(
+		telemetry.Int("gen_ai.usage.input_tokens", resp.InputTokens),
+		telemetry.Int("gen_ai.usage.output_tokens", resp.OutputTokens))
+	return cleanLLMResponse(resp.Text), nil
+}
+
+// fitContext shrinks a cx context to budget tokens by map-reduce
+// summarization: the context is split into chunks that fit the model's
+// window, each chunk is summarized, and the summaries are summarized in
+// turn until they fit. Summaries are kept for the run, so the sections
+// sharing a context summarize it once.
+func (g *Generator) fitContext(o *ollamaRun, model, cxContext string, budget int, opts ollama.Options) (string, error) {
+	window := opts.NumCtx
+	summaryLimit := int32(window / 4)
+	summaryOpts := ollama.Options{NumCtx: window, Temperature: opts.Temperature, NumPredict: &summaryLimit}
+	// A chunk leaves room in the window for the summary prompt and the summary
+	chunkBytes := (window - int(summaryLimit) - len(ollamaSummaryPrompt)/docsBytesPerToken) * docsBytesPerToken * 9 / 10
+	if chunkBytes <= 0 {
+		return "", fmt.Errorf("the %d-token window of %s is too small to summarize the context in", window, model)
+	}
+
+	text := cxContext
+	for round := 1; round <= ollamaMaxRounds; round++ {
+		chunks := splitChunks(text, chunkBytes)
+		g.logger.Infof("Context of ~%dk tokens exceeds the ~%dk tokens left in the window of %s; summarizing %d chunk(s) (round %d)",
+			len(text)/docsBytesPerToken/1000, budget/1000, model, len(chunks), round)
+		g.ulog.Info("Summarizing context for local model").
+			Field("model", model).
+			Field("section", g.currentSection).
+			Field("context_tokens", len(text)/docsBytesPerToken).
+			Field("budget_tokens", budget).
+			Field("chunks", len(chunks)).
+			Field("round", round).
+			Emit()
+
+		summaries := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			sum := sha256.Sum256(fmt.Appendf(nil, "%s\n%d\n%s", model, window, chunk))
+			key := hex.EncodeToString(sum[:])
+			if s, ok := o.summaries[key]; ok {
+				summaries[i] = s
+				continue
+			}
+			resp, err := o.client.Generate(g.runContext(), model, ollamaSummaryPrompt+chunk, summaryOpts)
+			if err != nil {
+				return "", fmt.Errorf("failed to summarize context chunk %d/%d: %w", i+1, len(chunks), err)
+			}
+			g.recordOllamaUsage(ollama.Prefix+model, resp)
+			summaries[i] = strings.TrimSpace(resp.Text)
+			o.summaries[key] = summaries[i]
+		}
+
+		reduced := "The project's source code did not fit the context window, so it is summarized below.\n\n" +
+			strings.Join(summaries, "\n\n") + "\n\n"
+		if len(reduced)/docsBytesPerToken <= budget {
+			return reduced, nil
+		}
+		if len(reduced) >= len(text) {
+			break
+		}
+		text = strings.Join(summaries, "\n\n")
+	}
+	return "", fmt.Errorf("the context of %s does not fit its %d-token window even summarized; narrow settings.rules_file or raise settings.ollama.context_window",
+		model, window)
+}
+
+// splitChunks splits text into chunks of at most maxBytes, at line breaks
+// where it can.
+func splitChunks(text string, maxBytes int) []string {
+	var chunks []string
+	for len(text) > maxBytes {
+		cut := strings.LastIndexByte(text[:maxBytes], '\n') + 1
+		if cut <= 0 {
+			cut = maxBytes
+		}
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	if strings.TrimSpace(text) != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// recordOllamaUsage adds the tokens of an Ollama request to the run's
+// report and throughput. Local requests cost nothing.
+func (g *Generator) recordOllamaUsage(model string, resp *ollama.Response) {
+	g.report.AddUsage(model, resp.InputTokens, resp.OutputTokens, 0, 0, 0)
+	g.progress.AddTokens(resp.OutputTokens)
+}
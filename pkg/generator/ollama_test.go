@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grovetools/docgen/pkg/ollama"
+)
+
+func TestSplitChunks(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		maxBytes int
+		want     []string
+	}{
+		{"fits", "a\nb\n", 10, []string{"a\nb\n"}},
+		{"empty", "", 10, nil},
+		{"whitespace tail dropped", "aaaa\n  \n", 5, []string{"aaaa\n"}},
+		{"at line breaks", "aaa\nbbb\nccc\n", 8, []string{"aaa\nbbb\n", "ccc\n"}},
+		{"last line break in reach", "aa\nbb\ncccc\n", 7, []string{"aa\nbb\n", "cccc\n"}},
+		{"long line cut", "aaaaaaaaaa\nbbb", 4, []string{"aaaa", "aaaa", "aa\n", "bbb"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := splitChunks(c.text, c.maxBytes)
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", c.want) {
+				t.Errorf("splitChunks(%q, %d) = %q, want %q", c.text, c.maxBytes, got, c.want)
+			}
+			for _, chunk := range got {
+				if len(chunk) > c.maxBytes {
+					t.Errorf("chunk %q is over %d bytes", chunk, c.maxBytes)
+				}
+			}
+			if kept := strings.Join(got, ""); strings.TrimSpace(kept) != strings.TrimSpace(c.text) {
+				t.Errorf("chunks lose text: %q from %q", kept, c.text)
+			}
+		})
+	}
+}
+
+// fakeOllama is an Ollama server whose /api/generate answers with
+// summarize(prompt), recording the requests it was sent.
+type fakeOllama struct {
+	mu        sync.Mutex
+	prompts   []string
+	options   []ollama.Options
+	summarize func(prompt string) string
+}
+
+func newFakeOllama(t *testing.T, summarize func(prompt string) string) (*fakeOllama, *ollama.Client) {
+	f := &fakeOllama{summarize: summarize}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			http.Error(w, `{"error": "unexpected request"}`, http.StatusNotFound)
+			return
+		}
+		var req struct {
+			Prompt  string         `json:"prompt"`
+			Options ollama.Options `json:"options"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.prompts = append(f.prompts, req.Prompt)
+		f.options = append(f.options, req.Options)
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"response":          f.summarize(req.Prompt),
+			"prompt_eval_count": len(req.Prompt) / docsBytesPerToken,
+			"eval_count":        10,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return f, ollama.New(srv.URL)
+}
+
+func (f *fakeOllama) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.prompts)
+}
+
+// sourceContext returns a cx context of n lines of about 60 bytes.
+func sourceContext(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "func Exported%04d(arg string) error // does thing number %04d\n", i, i)
+	}
+	return sb.String()
+}
+
+func TestFitContext(t *testing.T) {
+	const window = 2000
+	fake, client := newFakeOllama(t, func(prompt string) string {
+		return fmt.Sprintf("Summary of %d bytes.", len(prompt))
+	})
+	g := newTestGenerator()
+	o := &ollamaRun{client: client, windows: map[string]int{}, summaries: map[string]string{}}
+	temp := float32(0.2)
+	opts := ollama.Options{NumCtx: window, Temperature: &temp}
+
+	cxContext := sourceContext(200) // ~12 KB, ~4k tokens
+	got, err := g.fitContext(o, "llama-test", cxContext, 500, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "The project's source code did not fit the context window") {
+		t.Errorf("fitted context = %q", got)
+	}
+	if len(got)/docsBytesPerToken > 500 {
+		t.Errorf("fitted context is ~%d tokens, over the 500-token budget", len(got)/docsBytesPerToken)
+	}
+
+	// Every chunk is summarized once, in a prompt that fits the window with
+	// room for the summary
+	chunks := fake.calls()
+	if chunks < 2 {
+		t.Fatalf("summarized %d chunk(s), want the context split", chunks)
+	}
+	var joined strings.Builder
+	for i, prompt := range fake.prompts {
+		if !strings.HasPrefix(prompt, ollamaSummaryPrompt) {
+			t.Errorf("request %d is not a summary prompt", i)
+		}
+		joined.WriteString(strings.TrimPrefix(prompt, ollamaSummaryPrompt))
+		if tokens := len(prompt) / docsBytesPerToken; tokens+window/4 > window {
+			t.Errorf("request %d is ~%d tokens, leaving no room for a %d-token summary", i, tokens, window/4)
+		}
+		o := fake.options[i]
+		if o.NumCtx != window || o.NumPredict == nil || *o.NumPredict != window/4 || o.Temperature == nil || *o.Temperature != temp {
+			t.Errorf("request %d options = %+v", i, o)
+		}
+		if !strings.Contains(got, fmt.Sprintf("Summary of %d bytes.", len(prompt))) {
+			t.Errorf("fitted context lacks the summary of chunk %d", i)
+		}
+	}
+	if joined.String() != cxContext {
+		t.Error("the chunks summarized are not the whole context in order")
+	}
+
+	// Summaries are reused by the sections sharing a context
+	again, err := g.fitContext(o, "llama-test", cxContext, 500, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != got || fake.calls() != chunks {
+		t.Errorf("refitting the same context made %d more request(s)", fake.calls()-chunks)
+	}
+}
+
+// TestFitContextRounds checks summaries that do not fit the budget are
+// summarized again in a further round.
+func TestFitContextRounds(t *testing.T) {
+	fake, client := newFakeOllama(t, func(prompt string) string {
+		// A tenth of the chunk, so the first round's summaries are still
+		// too long for the budget
+		return strings.Repeat("s", len(prompt)/10)
+	})
+	g := newTestGenerator()
+	o := &ollamaRun{client: client, windows: map[string]int{}, summaries: map[string]string{}}
+
+	got, err := g.fitContext(o, "llama-test", sourceContext(1000), 300, ollama.Options{NumCtx: 2000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got)/docsBytesPerToken > 300 {
+		t.Errorf("fitted context is ~%d tokens, over the 300-token budget", len(got)/docsBytesPerToken)
+	}
+	var resummarized int
+	for _, prompt := range fake.prompts {
+		if strings.HasPrefix(strings.TrimPrefix(prompt, ollamaSummaryPrompt), "s") {
+			resummarized++
+		}
+	}
+	if resummarized == 0 {
+		t.Error("no summaries were summarized in a second round")
+	}
+}
+
+func TestFitContextErrors(t *testing.T) {
+	_, client := newFakeOllama(t, func(prompt string) string {
+		// Summaries as long as their chunks never converge
+		return strings.TrimPrefix(prompt, ollamaSummaryPrompt)
+	})
+	g := newTestGenerator()
+	o := &ollamaRun{client: client, windows: map[string]int{}, summaries: map[string]string{}}
+
+	_, err := g.fitContext(o, "llama-test", sourceContext(200), 100, ollama.Options{NumCtx: 2000})
+	if err == nil || !strings.Contains(err.Error(), "does not fit its 2000-token window even summarized") {
+		t.Errorf("fitContext() with summaries that do not shrink = %v", err)
+	}
+
+	_, err = g.fitContext(o, "llama-test", sourceContext(200), 100, ollama.Options{NumCtx: 200})
+	if err == nil || !strings.Contains(err.Error(), "too small to summarize") {
+		t.Errorf("fitContext() in a tiny window = %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// defaultWrapWidth is the line width of the wrap post-processor.
+const defaultWrapWidth = 80
+
+var (
+	ppFenceRegex      = regexp.MustCompile("^[ \t]*(```+|~~~+)")
+	ppHeadingRegex    = regexp.MustCompile(`^(#{1,6})([ \t]+.*)$`)
+	ppListMarkerRegex = regexp.MustCompile(`^([ \t]*)[*+]([ \t]+)`)
+	ppListItemRegex   = regexp.MustCompile(`^([ \t]*)([-*+]|\d+[.)])[ \t]+`)
+	ppBlockLineRegex  = regexp.MustCompile(`^[ \t]*([|>#<]|$|(-{3,}|\*{3,}|_{3,}|={3,})[ \t]*$)`)
+)
+
+// postprocess runs a section's post-processors over its output, in order.
+// A post-processor that fails is skipped with a warning, leaving the output
+// as the previous one left it. Frontmatter is only seen by prettier.
+func (g *Generator) postprocess(section, output string, steps []config.PostprocessStep, workDir string) string {
+	for _, step := range steps {
+		var err error
+		switch step.Name {
+		case "trim_whitespace":
+			output = trimWhitespace(output)
+		case "normalize":
+			output = outsideFrontmatter(output, normalizeMarkdown)
+		case "wrap":
+			width := step.Width
+			if width <= 0 {
+				width = defaultWrapWidth
+			}
+			output = outsideFrontmatter(output, func(s string) string { return wrapMarkdown(s, width) })
+		case "shift_headings":
+			levels := step.Levels
+			if levels == 0 {
+				levels = 1
+			}
+			output = outsideFrontmatter(output, func(s string) string { return shiftHeadingLevels(s, levels) })
+		case "prettier":
+			var formatted string
+			if formatted, err = g.runFormatter(step.Command, output, workDir); err == nil {
+				output = formatted
+			}
+		default:
+			err = fmt.Errorf("unknown post-processor")
+		}
+		if err != nil {
+			g.recordWarning("Section %q: post-processor %q skipped: %v", section, step.Name, err)
+		}
+	}
+	return output
+}
+
+// runFormatter pipes output through an external formatter, prettier by
+// default.
+func (g *Generator) runFormatter(command []string, output, workDir string) (string, error) {
+	if len(command) == 0 {
+		command = []string{"prettier", "--parser", "markdown"}
+	}
+	cmd := exec.CommandContext(g.runContext(), command[0], command[1:]...) //nolint:gosec // formatter from the package's own config
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(output)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if tail := lastLines(stderr.String(), 5); tail != "" {
+			return "", fmt.Errorf("%s: %w: %s", command[0], err, tail)
+		}
+		return "", fmt.Errorf("%s: %w", command[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// outsideFrontmatter applies fn to a document without its YAML frontmatter,
+// which is kept as it is.
+func outsideFrontmatter(content string, fn func(string) string) string {
+	if !strings.HasPrefix(content, "---\n") {
+		return fn(content)
+	}
+	end := strings.Index(content[4:], "\n---\n")
+	if end == -1 {
+		return fn(content)
+	}
+	split := 4 + end + len("\n---\n")
+	return content[:split] + fn(content[split:])
+}
+
+// forEachProseLine rewrites the lines of a markdown document outside fenced
+// code blocks with fn; fence lines and code are kept as they are.
+func forEachProseLine(content string, fn func(line string) string) string {
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		if m := ppFenceRegex.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+			case m[1][0] == fence[0] && len(m[1]) >= len(fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence == "" {
+			lines[i] = fn(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimWhitespace removes trailing whitespace from every line and ends the
+// document with exactly one newline.
+func trimWhitespace(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// normalizeMarkdown applies consistent formatting: "-" list markers, a blank
+// line around headings and code blocks, no runs of blank lines, and a final
+// newline.
+func normalizeMarkdown(content string) string {
+	content = forEachProseLine(content, func(line string) string {
+		return ppListMarkerRegex.ReplaceAllString(line, "$1-$2")
+	})
+
+	var out []string
+	fence := ""
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	for i, line := range lines {
+		if m := ppFenceRegex.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+				blank()
+				out = append(out, line)
+			case m[1][0] == fence[0] && len(m[1]) >= len(fence):
+				fence = ""
+				out = append(out, line)
+				if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					out = append(out, "")
+				}
+			default:
+				out = append(out, line)
+			}
+			continue
+		}
+		switch {
+		case fence != "":
+			out = append(out, line)
+		case strings.TrimSpace(line) == "":
+			blank()
+		case ppHeadingRegex.MatchString(line):
+			blank()
+			out = append(out, line)
+			if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// wrapMarkdown rewraps paragraphs and list items to width. Headings, tables,
+// blockquotes, HTML, code and lines with hard breaks are left alone; a
+// paragraph's lines are joined before rewrapping.
+func wrapMarkdown(content string, width int) string {
+	var out []string
+	var para []string
+	prefix, indent := "", ""
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		out = append(out, wrapWords(strings.Fields(strings.Join(para, " ")), prefix, indent, width)...)
+		para, prefix, indent = nil, "", ""
+	}
+
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		if m := ppFenceRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			switch {
+			case fence == "":
+				fence = m[1]
+			case m[1][0] == fence[0] && len(m[1]) >= len(fence):
+				fence = ""
+			}
+			out = append(out, line)
+			continue
+		}
+		if fence != "" {
+			out = append(out, line)
+			continue
+		}
+		switch {
+		case ppBlockLineRegex.MatchString(line) || strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\"):
+			flush()
+			out = append(out, line)
+		case ppListItemRegex.MatchString(line):
+			flush()
+			marker := ppListItemRegex.FindString(line)
+			prefix, indent = marker, strings.Repeat(" ", len(marker))
+			para = append(para, line[len(marker):])
+		default:
+			para = append(para, strings.TrimSpace(line))
+		}
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// wrapWords lays words out in lines of at most width, the first line
+// starting with prefix and the rest with indent. A word longer than the width
+// gets a line of its own.
+func wrapWords(words []string, prefix, indent string, width int) []string {
+	var lines []string
+	line := prefix
+	empty := true
+	for _, w := range words {
+		if !empty && len(line)+1+len(w) > width {
+			lines = append(lines, line)
+			line, empty = indent, true
+		}
+		if !empty {
+			line += " "
+		}
+		line += w
+		empty = false
+	}
+	return append(lines, line)
+}
+
+// shiftHeadingLevels moves every heading down (levels > 0) or up (levels < 0),
+// keeping it between levels 1 and 6.
+func shiftHeadingLevels(content string, levels int) string {
+	return forEachProseLine(content, func(line string) string {
+		m := ppHeadingRegex.FindStringSubmatch(line)
+		if m == nil {
+			return line
+		}
+		level := len(m[1]) + levels
+		if level < 1 {
+			level = 1
+		}
+		if level > 6 {
+			level = 6
+		}
+		return strings.Repeat("#", level) + m[2]
+	})
+}
@@ -231,10 +231,13 @@ func (g *Generator) Propose(packageDir string, opts ProposeOptions) error {
 		return fmt.Errorf("cx produced no context in %s; cannot warm a shared prefix for the proposal", packageDir)
 	}
 	// Same pre-spend window guard the fan-out uses — an over-window prefix 400s.
-	if err := checkDocsWindow(model, ctxFiles); err != nil {
+	if err := g.checkDocsWindow(model, ctxFiles); err != nil {
 		return err
 	}
 
+	if g.storedAnthropicKey() {
+		return fmt.Errorf("propose uses the native Anthropic client, which reads ANTHROPIC_API_KEY or grove.yml and cannot be given the key of credentials profile %q; export ANTHROPIC_API_KEY for this run", g.credentials)
+	}
 	prefix, err := newDocsSharedPrefix(ctxFiles, model, ttl)
 	if err != nil {
 		return fmt.Errorf("failed to set up shared prefix for propose: %w", err)
@@ -242,7 +245,7 @@ func (g *Generator) Propose(packageDir string, opts ProposeOptions) error {
 	defer func() { _ = prefix.Close() }()
 
 	g.logger.Infof("Propose via cache fan-out: model=%s ttl=%s prefix_docs=%d", prefix.Model(), ttl, len(ctxFiles))
-	ulog.Info("Propose cache fan-out").
+	g.ulog.Info("Propose cache fan-out").
 		Field("model", prefix.Model()).
 		Field("ttl", ttl).
 		Field("prefix_docs", len(ctxFiles)).
@@ -275,7 +278,7 @@ func (g *Generator) Propose(packageDir string, opts ProposeOptions) error {
 	}
 	if written.ConfigWarning != "" {
 		g.logger.Warnf("proposed config did not validate (written anyway for review): %s", written.ConfigWarning)
-		ulog.Warn("Proposed config invalid").Field("error", written.ConfigWarning).Emit()
+		g.ulog.Warn("Proposed config invalid").Field("error", written.ConfigWarning).Emit()
 	}
 
 	// Record the extended transcript so a later --followup can replay this exact
@@ -293,7 +296,7 @@ func (g *Generator) Propose(packageDir string, opts ProposeOptions) error {
 	}
 	g.logger.Infof("Wrote proposal bundle to %s (%s, %s, %d prompt(s))",
 		opts.OutputDir, filepath.Base(written.ProposalPath), filepath.Base(written.ConfigPath), len(written.PromptPaths))
-	ulog.Success("Proposal bundle written").
+	g.ulog.Success("Proposal bundle written").
 		Field("dir", opts.OutputDir).
 		Field("prompts", len(written.PromptPaths)).
 		Emit()
@@ -647,6 +650,19 @@ func isProseSection(sectionType string) bool {
 	}
 }
 
+// isPromptSection reports whether a section type reads its prompt: file,
+// as prose sections, tutorial scenarios and template skeletons do. The
+// pre-spend guard (validateSectionPrompts) checks the prompts of these
+// sections.
+func isPromptSection(sectionType string) bool {
+	switch sectionType {
+	case "tutorial", "template":
+		return true
+	default:
+		return isProseSection(sectionType)
+	}
+}
+
 // stripFence removes surrounding blank lines and a single wrapping code fence
 // (```lang ... ```) from a block body, leaving the inner content.
 func stripFence(s string) string {
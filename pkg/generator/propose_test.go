@@ -587,6 +587,22 @@ func TestValidateSectionPrompts(t *testing.T) {
 		}
 	})
 
+	t.Run("tutorial scenarios and template skeletons are checked", func(t *testing.T) {
+		sections := []config.SectionConfig{
+			{Name: "07-tour", Type: "tutorial", Prompt: "07-tour.md", Output: "07-tour.md"},
+			{Name: "08-api", Type: "template", Prompt: "08-api.md", Output: "08-api.md"},
+		}
+		err := validateSectionPrompts(sections, resolve)
+		if err == nil {
+			t.Fatal("expected the missing scenario and skeleton to be reported")
+		}
+		for _, name := range []string{"07-tour", "08-api"} {
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("error does not name offender %q: %v", name, err)
+			}
+		}
+	})
+
 	t.Run("prose section with empty prompt is an offender", func(t *testing.T) {
 		sections := []config.SectionConfig{
 			{Name: "06-blank", Type: "prose", Prompt: "  ", Output: "06-blank.md"},
@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// AnswersFileName is the file next to a docgen config that holds the answers
+// to the questions sections asked during generation (settings.questions).
+const AnswersFileName = "answers.yml"
+
+// maxQuestionRounds bounds how often one section may ask questions before
+// it fails, so a model that keeps asking cannot loop forever.
+const maxQuestionRounds = 3
+
+// questionsInstructions is appended to a section's prompt when
+// settings.questions is on.
+const questionsInstructions = `
+
+---
+
+If the context above is not enough to document something accurately, do not guess. Instead of the document, respond with only the questions whose answers you need, one per line, in this block:
+
+<questions>
+- First question?
+- Second question?
+</questions>`
+
+var (
+	// questionsRegex matches the questions block of a response.
+	questionsRegex = regexp.MustCompile(`(?s)<questions>(.*?)</questions>`)
+	// listMarkerRegex matches the list marker of a question.
+	listMarkerRegex = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+)
+
+// AnswerFunc asks the user the question a section asked during generation
+// and returns the answer, or "" to leave it unanswered.
+type AnswerFunc func(section, question string) (string, error)
+
+// SetAnswerFunc sets how the questions sections ask are answered when
+// answers.yml has no answer (settings.questions). Without one, unanswered
+// questions are added to answers.yml and the section fails.
+func (g *Generator) SetAnswerFunc(f AnswerFunc) {
+	g.answer = f
+}
+
+// QA is a question a section asked and its answer.
+type QA struct {
+	Question string `yaml:"question"`
+	Answer   string `yaml:"answer"`
+}
+
+// Answers are the questions sections asked, by section name, as stored in
+// answers.yml.
+type Answers map[string][]QA
+
+// LoadAnswers reads the answers file at path; a missing file has no answers.
+func LoadAnswers(path string) (Answers, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path next to the docgen config
+	if errors.Is(err, os.ErrNotExist) {
+		return Answers{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	answers := Answers{}
+	if err := yaml.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+// Save writes the answers to path.
+func (a Answers) Save(path string) error {
+	data, err := yaml.Marshal(map[string][]QA(a))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644) //nolint:gosec // answers are not secret
+}
+
+// lookup returns the index of question in the section's answers, or -1.
+// Questions match regardless of case and spacing.
+func (a Answers) lookup(section, question string) int {
+	key := normalizeQuestion(question)
+	for i, qa := range a[section] {
+		if normalizeQuestion(qa.Question) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func normalizeQuestion(q string) string {
+	return strings.ToLower(strings.Join(strings.Fields(q), " "))
+}
+
+// parseQuestions returns the questions of a response's questions block, or
+// false when the response has none and is the document.
+func parseQuestions(response string) ([]string, bool) {
+	m := questionsRegex.FindStringSubmatch(response)
+	if m == nil {
+		return nil, false
+	}
+	var questions []string
+	for _, line := range strings.Split(m[1], "\n") {
+		line = listMarkerRegex.ReplaceAllString(strings.TrimSpace(line), "")
+		if line != "" {
+			questions = append(questions, line)
+		}
+	}
+	return questions, len(questions) > 0
+}
+
+// answersPrompt renders a section's answered questions for its prompt, or ""
+// when none is answered.
+func answersPrompt(qas []QA) string {
+	var b strings.Builder
+	for _, qa := range qas {
+		if strings.TrimSpace(qa.Answer) == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "Q: %s\nA: %s\n\n", qa.Question, strings.TrimSpace(qa.Answer))
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\n\n---\n\nAnswers from the maintainers to questions about this documentation:\n\n<answers>\n" +
+		strings.TrimRight(b.String(), "\n") + "\n</answers>"
+}
+
+// callWithQuestions generates a section with settings.questions on: the
+// model may answer with questions instead of the document. Their answers
+// come from the answers file at answersPath or the answer function; the
+// section is then requested again with every answer in its prompt. Questions
+// left unanswered are added to the answers file with an empty answer for the
+// maintainers to fill in, and the section fails.
+func (g *Generator) callWithQuestions(section, prompt, model string, genConfig config.GenerationConfig, workDir, answersPath string) (string, error) {
+	answers, err := LoadAnswers(answersPath)
+	if err != nil {
+		return "", err
+	}
+	for round := 1; ; round++ {
+		output, err := g.CallLLM(prompt+answersPrompt(answers[section])+questionsInstructions, model, genConfig, workDir)
+		if err != nil {
+			return "", err
+		}
+		questions, ok := parseQuestions(output)
+		if !ok {
+			return output, nil
+		}
+		g.logger.Infof("Section '%s' asked %d question(s)", section, len(questions))
+		g.ulog.Info("Section asked questions").
+			Field("section", section).
+			Field("questions", len(questions)).
+			Emit()
+		if round == maxQuestionRounds {
+			return "", fmt.Errorf("section '%s' still asks questions after %d rounds of answers: %s", section, maxQuestionRounds, strings.Join(questions, " "))
+		}
+
+		var unanswered []string
+		changed := false
+		for _, q := range questions {
+			i := answers.lookup(section, q)
+			if i >= 0 && strings.TrimSpace(answers[section][i].Answer) != "" {
+				continue
+			}
+			answer := ""
+			if g.answer != nil {
+				if answer, err = g.answer(section, q); err != nil {
+					return "", err
+				}
+			}
+			if i < 0 {
+				answers[section] = append(answers[section], QA{Question: q, Answer: answer})
+				changed = true
+			} else if answer != "" {
+				answers[section][i].Answer = answer
+				changed = true
+			}
+			if strings.TrimSpace(answer) == "" {
+				unanswered = append(unanswered, q)
+			}
+		}
+		if changed {
+			if err := answers.Save(answersPath); err != nil {
+				return "", fmt.Errorf("failed to save answers: %w", err)
+			}
+		}
+		if len(unanswered) > 0 {
+			return "", fmt.Errorf("section '%s' needs answers to %d question(s); answer them in %s and generate it again: %s",
+				section, len(unanswered), answersPath, strings.Join(unanswered, " "))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuestions(t *testing.T) {
+	response := "<questions>\n- Which databases are supported?\n2. Is 3D output planned?\n\n</questions>"
+	got, ok := parseQuestions(response)
+	want := []string{"Which databases are supported?", "Is 3D output planned?"}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("parseQuestions = %q, %v; want %q", got, ok, want)
+	}
+
+	if _, ok := parseQuestions("# Overview\n\nBody"); ok {
+		t.Error("document: want no questions")
+	}
+	if _, ok := parseQuestions("<questions>\n</questions>"); ok {
+		t.Error("empty block: want no questions")
+	}
+}
+
+func TestAnswers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), AnswersFileName)
+	answers, err := LoadAnswers(path)
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	answers["overview"] = []QA{
+		{Question: "Which databases are supported?", Answer: "PostgreSQL and SQLite."},
+		{Question: "Is there a GUI?"},
+	}
+	if err := answers.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadAnswers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := loaded.lookup("overview", "which  databases are SUPPORTED?"); i != 0 {
+		t.Errorf("lookup = %d; want 0", i)
+	}
+	if i := loaded.lookup("usage", "Is there a GUI?"); i != -1 {
+		t.Errorf("lookup in another section = %d; want -1", i)
+	}
+
+	want := "\n\n---\n\nAnswers from the maintainers to questions about this documentation:\n\n<answers>\n" +
+		"Q: Which databases are supported?\nA: PostgreSQL and SQLite.\n</answers>"
+	if got := answersPrompt(loaded["overview"]); got != want {
+		t.Errorf("answersPrompt =\n%q\nwant\n%q", got, want)
+	}
+	if got := answersPrompt(loaded["usage"]); got != "" {
+		t.Errorf("answersPrompt without answers = %q; want empty", got)
+	}
+}
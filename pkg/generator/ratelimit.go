@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"os"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/credentials"
+	"github.com/grovetools/docgen/pkg/ratelimit"
+	"github.com/grovetools/grove-anthropic/pkg/anthropic"
+)
+
+// UseConfig applies the run-wide LLM settings of cfg to the generator's
+// requests: the credentials profile, the provider rate limits and the Ollama
+// server. Generate applies the package's config itself; callers making
+// requests through the generator directly, such as the schema enricher, call
+// it first.
+func (g *Generator) UseConfig(cfg *config.DocgenConfig) {
+	g.credentials = cfg.Settings.Credentials
+	g.rateLimits = cfg.Settings.RateLimits
+	g.ollamaConfig = cfg.Settings.Ollama
+	for provider := range g.rateLimits {
+		if _, err := credentials.LookupProvider(provider); err != nil {
+			g.recordWarning("settings.rate_limits: %v", err)
+		}
+	}
+}
+
+// waitRateLimit waits until the rate limit of model's provider admits a
+// request of tokens estimated input tokens. The limiter is shared by every
+// generation in the process, so concurrent packages queue on one quota.
+// While it waits, the section shows as rate-limited on the progress line.
+func (g *Generator) waitRateLimit(model string, tokens int) error {
+	p, ok := credentials.ProviderForModel(model)
+	if !ok {
+		return nil
+	}
+	limit, ok := g.rateLimits[p.Name]
+	if !ok || (limit.RequestsPerMinute == 0 && limit.TokensPerMinute == 0) {
+		return nil
+	}
+	limiter := ratelimit.For(p.Name, ratelimit.Limits{
+		RequestsPerMinute: limit.RequestsPerMinute,
+		TokensPerMinute:   limit.TokensPerMinute,
+	})
+	defer g.sectionTask.SetWaiting(false)
+	return limiter.Wait(g.runContext(), tokens, func(delay time.Duration) {
+		g.sectionTask.SetWaiting(true)
+		g.logger.Debugf("Rate limit of %s reached; waiting %s", p.Name, delay.Round(time.Second))
+		g.ulog.Info("Waiting for rate limit").
+			Field("provider", p.Name).
+			Field("section", g.currentSection).
+			Field("wait", delay.Round(time.Second).String()).
+			Emit()
+	})
+}
+
+// requestTokens estimates the input tokens of a request: the prompt plus,
+// for grove llm, the cx context it attaches. Fan-out requests read the
+// context from the cache, which does not count against input token quotas.
+func (g *Generator) requestTokens(prompt, workDir string, fanout bool) int {
+	size := int64(len(prompt))
+	if !fanout {
+		for _, f := range anthropic.WorkDirContextFiles(g.isolation.path(workDir)) {
+			if fi, err := os.Stat(f); err == nil {
+				size += fi.Size()
+			}
+		}
+	}
+	return int(size / docsBytesPerToken)
+}
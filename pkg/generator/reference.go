@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// Reference section types (error_reference and friends) are built from facts
+// parsed out of the package's sources; the LLM only adds prose around them.
+
+// tableCell makes text safe to place inside a Markdown table cell.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// callReferenceLLM sends facts, marshaled as JSON, to the section's model with
+// instructions asking for a JSON object, and decodes the answer into out.
+func (g *Generator) callReferenceLLM(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, instructions string, facts, out interface{}) error {
+	data, err := json.MarshalIndent(facts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode reference for the LLM: %w", err)
+	}
+
+	model := section.Model
+	if model == "" {
+		model = cfg.Settings.Model
+	}
+	if model == "" {
+		model = "gemini-3-pro-preview"
+	}
+
+	genConfig := config.MergeGenerationConfig(cfg.Settings.GenerationConfig, section.GenerationConfig)
+	response, err := g.CallLLM(instructions+"\n\n"+string(data), model, genConfig, packageDir)
+	if err != nil {
+		return fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	// Strip markdown code fences if present
+	cleanResponse := strings.TrimSpace(response)
+	cleanResponse = strings.TrimPrefix(cleanResponse, "```json")
+	cleanResponse = strings.TrimPrefix(cleanResponse, "```")
+	cleanResponse = strings.TrimSuffix(cleanResponse, "```")
+	cleanResponse = strings.TrimSpace(cleanResponse)
+
+	if err := json.Unmarshal([]byte(cleanResponse), out); err != nil {
+		return fmt.Errorf("failed to parse LLM response as JSON: %w\nResponse: %s", err, response)
+	}
+	return nil
+}
+
+// writeReference writes a rendered reference page to the section's output.
+func writeReference(section config.SectionConfig, outputBaseDir, content string) (string, error) {
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(content), 0o644); err != nil { //nolint:gosec // generated doc
+		return "", fmt.Errorf("failed to write %s output: %w", section.Type, err)
+	}
+	return outputPath, nil
+}
@@ -0,0 +1,543 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/capture"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Reference lint rules reported in ReferenceIssue.Rule.
+const (
+	RuleUnknownCommand   = "unknown-command"    // A subcommand the captured CLI does not have
+	RuleUnknownFlag      = "unknown-flag"       // A flag the command's help does not list
+	RuleUnknownConfigKey = "unknown-config-key" // A config key the JSON schema does not define
+)
+
+// ReferenceIssue is a command, flag or config key a doc mentions that the
+// package's CLI or config schema does not have.
+type ReferenceIssue struct {
+	Section   string `json:"section"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Rule      string `json:"rule"`
+	Reference string `json:"reference"`
+	Message   string `json:"message"`
+}
+
+var (
+	refCodeSpanRe = regexp.MustCompile("`([^`\n]+)`")
+	refFenceRe    = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([\\w+-]*)")
+	refKeyPathRe  = regexp.MustCompile(`^[A-Za-z_][\w-]*(?:\[\d*\])?(?:\.[A-Za-z_][\w-]*(?:\[\d*\])?)+$`)
+	refIndexRe    = regexp.MustCompile(`\[\d*\]`)
+	refTOMLKeyRe  = regexp.MustCompile(`^\s*([A-Za-z0-9_."'-]+)\s*=`)
+	refTOMLHeadRe = regexp.MustCompile(`^\s*\[\[?\s*([A-Za-z0-9_."' -]+?)\s*\]\]?\s*(#.*)?$`)
+)
+
+// shellLangs are the fence languages whose lines are command lines.
+var shellLangs = map[string]bool{"": true, "bash": true, "sh": true, "shell": true, "console": true, "zsh": true, "text": true}
+
+// fileExtensions are last segments that make a dotted name a file, not a
+// config key (e.g. settings.yml).
+var fileExtensions = map[string]bool{
+	"yml": true, "yaml": true, "json": true, "toml": true, "md": true, "mdx": true, "go": true,
+	"txt": true, "js": true, "ts": true, "py": true, "sh": true, "html": true, "css": true,
+}
+
+// surface is what a package's docs can refer to: the command trees its
+// capture sections crawl and the keys its JSON schemas define.
+type surface struct {
+	commands map[string]*surfaceCommand // Root commands by binary name
+	keys     map[string]bool            // Dotted config key paths
+	open     map[string]bool            // Keys whose children are free-form
+	roots    map[string]bool            // Top-level config keys
+}
+
+type surfaceCommand struct {
+	subs  map[string]*surfaceCommand
+	flags map[string]capture.Flag // By "--name" and "-x"
+	args  bool                    // Takes positional arguments
+	known bool                    // Its help was captured
+}
+
+func (s *surface) empty() bool {
+	return len(s.commands) == 0 && len(s.keys) == 0
+}
+
+func newSurfaceCommand(n *capture.CommandNode) *surfaceCommand {
+	c := &surfaceCommand{
+		subs:  make(map[string]*surfaceCommand),
+		flags: make(map[string]capture.Flag),
+		known: n.HelpOutput != "",
+	}
+	if c.known {
+		c.args = n.TakesArgs()
+		for _, f := range n.Flags() {
+			if f.Name != "" {
+				c.flags["--"+f.Name] = f
+			}
+			if f.Short != "" {
+				c.flags["-"+f.Short] = f
+			}
+		}
+	}
+	for _, sub := range n.SubCommands {
+		c.subs[sub.Name] = newSurfaceCommand(sub)
+	}
+	return c
+}
+
+func (s *surface) addSchema(props []schema.Property, prefix string) {
+	for _, p := range props {
+		path := p.Name
+		if prefix == "" {
+			s.roots[path] = true
+		} else {
+			path = prefix + "." + p.Name
+		}
+		s.keys[path] = true
+		switch {
+		case len(p.Properties) > 0:
+			s.addSchema(p.Properties, path)
+		case p.Items != nil && len(p.Items.Properties) > 0:
+			s.addSchema(p.Items.Properties, path)
+		case p.Type == "object" || p.Type == "" || (p.Items != nil && p.Items.Type == "object"):
+			s.open[path] = true
+		}
+	}
+}
+
+// hasKey reports whether path, with array indexes dropped, is a key of the
+// schemas or lies below a free-form one.
+func (s *surface) hasKey(path string) bool {
+	path = refIndexRe.ReplaceAllString(path, "")
+	if s.keys[path] {
+		return true
+	}
+	for i := strings.LastIndexByte(path, '.'); i > 0; i = strings.LastIndexByte(path[:i], '.') {
+		if s.open[path[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// rememberCapture keeps a command tree a capture section crawled, so the
+// reference check of the run does not crawl it again.
+func (g *Generator) rememberCapture(binary string, root *capture.CommandNode) {
+	if g.captured == nil {
+		g.captured = make(map[string]*capture.CommandNode)
+	}
+	g.captured[binary] = root
+}
+
+// referenceSurface returns the surface of the package at packageDir, built
+// once per run from its capture and schema sections.
+func (g *Generator) referenceSurface(packageDir string) (*surface, error) {
+	if s, ok := g.surfaces[packageDir]; ok {
+		return s, nil
+	}
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	s := &surface{
+		commands: make(map[string]*surfaceCommand),
+		keys:     make(map[string]bool),
+		open:     make(map[string]bool),
+		roots:    make(map[string]bool),
+	}
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		section := t.Section
+		switch {
+		case section.Type == "capture" && section.Binary != "":
+			name := strings.TrimSuffix(filepath.Base(section.Binary), filepath.Ext(section.Binary))
+			if seen["bin:"+name] {
+				continue
+			}
+			seen["bin:"+name] = true
+			root := g.captured[section.Binary]
+			if root == nil {
+				depth := 5
+				if section.Depth > 0 {
+					depth = section.Depth
+				}
+				root, err = capture.New(g.logger).CrawlContext(g.runContext(), section.Binary, capture.Options{
+					MaxDepth:  depth,
+					Format:    capture.FormatMarkdown,
+					CobraJSON: section.CobraJSON,
+				})
+				if err != nil {
+					g.logger.Debugf("Skipping the commands of %s in the reference check: %v", section.Binary, err)
+					continue
+				}
+				g.rememberCapture(section.Binary, root)
+			}
+			s.commands[name] = newSurfaceCommand(root)
+		case strings.HasPrefix(section.Type, "schema_"):
+			for _, input := range schemaInputs(section) {
+				if input.Path == "" || seen["schema:"+input.Path] {
+					continue
+				}
+				seen["schema:"+input.Path] = true
+				p, err := schema.NewParser(filepath.Join(packageDir, input.Path))
+				if err != nil {
+					g.logger.Debugf("Skipping schema %s in the reference check: %v", input.Path, err)
+					continue
+				}
+				props, err := p.Parse()
+				if err != nil {
+					g.logger.Debugf("Skipping schema %s in the reference check: %v", input.Path, err)
+					continue
+				}
+				s.addSchema(props, "")
+			}
+		}
+	}
+	if g.surfaces == nil {
+		g.surfaces = make(map[string]*surface)
+	}
+	g.surfaces[packageDir] = s
+	return s, nil
+}
+
+// schemaInputs returns the schemas of a schema_* section: its schemas list,
+// or its JSON source files.
+func schemaInputs(section config.SectionConfig) []config.SchemaInput {
+	if len(section.Schemas) > 0 {
+		return section.Schemas
+	}
+	var inputs []config.SchemaInput
+	for _, input := range section.Source.SchemaInputs() {
+		if strings.HasSuffix(input.Path, ".json") {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+// checkReferences warns about the commands, flags and config keys a
+// generated section mentions that the package's captured CLI or JSON schemas
+// do not have, which models invent when the context does not show them.
+func (g *Generator) checkReferences(packageDir, section, outputPath, output string) {
+	s, err := g.referenceSurface(packageDir)
+	if err != nil || s.empty() {
+		return
+	}
+	for _, issue := range lintReferences(s, section, outputPath, output) {
+		g.recordWarning("Section %q line %d: %s", section, issue.Line, issue.Message)
+	}
+}
+
+// CheckReferences lints the generated markdown docs of the package at
+// packageDir (or only the named sections) against its CLI and config
+// surface: the command trees of its capture sections and the JSON schemas
+// of its schema sections. It returns one issue per command, flag or config
+// key a doc mentions that does not exist.
+func (g *Generator) CheckReferences(packageDir string, sections []string) ([]ReferenceIssue, error) {
+	s, err := g.referenceSurface(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	if s.empty() {
+		return nil, fmt.Errorf("no capture or schema sections to check references against")
+	}
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool)
+	for _, name := range sections {
+		wanted[name] = true
+	}
+
+	issues := []ReferenceIssue{}
+	for _, t := range targets {
+		if len(wanted) > 0 && !wanted[t.Name] && !wanted[t.Section.Name] {
+			continue
+		}
+		// Captured and rendered sections are the surface itself
+		if t.Section.Type == "capture" || t.Section.Type == "concat" || !strings.HasSuffix(t.Section.Output, ".md") {
+			continue
+		}
+		path := filepath.Join(t.OutputDir, t.Section.Output)
+		data, err := os.ReadFile(path) //nolint:gosec // path from config
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		issues = append(issues, lintReferences(s, t.Name, path, string(data))...)
+	}
+	return issues, nil
+}
+
+// keyRef is a config key a doc mentions.
+type keyRef struct {
+	path string
+	line int
+}
+
+// lintReferences checks the command lines and config keys of a markdown doc
+// against s: command lines in shell code blocks and code spans, keys in YAML
+// and TOML code blocks and dotted keys in code spans.
+func lintReferences(s *surface, section, file, doc string) []ReferenceIssue {
+	var issues []ReferenceIssue
+	add := func(line int, rule, ref, format string, args ...any) {
+		issues = append(issues, ReferenceIssue{
+			Section:   section,
+			File:      file,
+			Line:      line,
+			Rule:      rule,
+			Reference: ref,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	lines := strings.Split(doc, "\n")
+	fence, lang := "", ""
+	var block []string
+	blockStart := 0
+	for i, line := range lines {
+		if m := refFenceRe.FindStringSubmatch(line); m != nil && (fence == "" || strings.HasPrefix(strings.TrimSpace(line), fence)) {
+			if fence == "" {
+				fence, lang, block, blockStart = m[1], strings.ToLower(m[2]), nil, i+2
+				continue
+			}
+			switch lang {
+			case "yaml", "yml":
+				s.checkKeys(yamlKeys(strings.Join(block, "\n"), blockStart), add)
+			case "toml":
+				s.checkKeys(tomlKeys(block, blockStart), add)
+			}
+			fence = ""
+			continue
+		}
+		if fence != "" {
+			block = append(block, line)
+			if shellLangs[lang] {
+				s.checkCommandLine(line, i+1, add)
+			}
+			continue
+		}
+		for _, m := range refCodeSpanRe.FindAllStringSubmatch(line, -1) {
+			span := strings.TrimSpace(m[1])
+			if refKeyPathRe.MatchString(span) {
+				s.checkKeys([]keyRef{{path: span, line: i + 1}}, add)
+				continue
+			}
+			s.checkCommandLine(span, i+1, add)
+		}
+	}
+	return issues
+}
+
+// checkKeys reports the keys that are not in the schemas. Keys are only
+// checked when one of them starts at a top-level key of the schemas, so
+// other config files in a doc are left alone; keys below an unknown key are
+// not reported again.
+func (s *surface) checkKeys(refs []keyRef, add func(int, string, string, string, ...any)) {
+	relevant := false
+	for _, r := range refs {
+		first, _, _ := strings.Cut(refIndexRe.ReplaceAllString(r.path, ""), ".")
+		if s.roots[first] {
+			relevant = true
+			break
+		}
+	}
+	if !relevant {
+		return
+	}
+	var unknown []string
+	for _, r := range refs {
+		path := r.path
+		if i := strings.LastIndexByte(path, '.'); i >= 0 && fileExtensions[strings.ToLower(path[i+1:])] {
+			continue
+		}
+		if s.hasKey(path) {
+			continue
+		}
+		below := false
+		for _, u := range unknown {
+			if strings.HasPrefix(path, u+".") {
+				below = true
+				break
+			}
+		}
+		if below {
+			continue
+		}
+		unknown = append(unknown, path)
+		add(r.line, RuleUnknownConfigKey, path, "config key %s is not defined by the config schema", path)
+	}
+}
+
+// checkCommandLine walks the command lines of line that start with a
+// captured binary down its command tree, reporting subcommands and flags it
+// does not have.
+func (s *surface) checkCommandLine(line string, lineNo int, add func(int, string, string, string, ...any)) {
+	if len(s.commands) == 0 {
+		return
+	}
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "$ ")
+	for _, segment := range regexp.MustCompile(`\|\||&&|[|;]`).Split(line, -1) {
+		words := strings.Fields(segment)
+		for len(words) > 0 && strings.Contains(words[0], "=") && !strings.HasPrefix(words[0], "-") {
+			words = words[1:] // Environment assignments
+		}
+		if len(words) == 0 {
+			continue
+		}
+		root := s.commands[words[0]]
+		if root == nil {
+			continue
+		}
+		s.walkCommand(root, words, lineNo, add)
+	}
+}
+
+func (s *surface) walkCommand(cmd *surfaceCommand, words []string, lineNo int, add func(int, string, string, string, ...any)) {
+	path := []string{words[0]}
+	argsSeen, flagsDone := false, false
+	for i := 1; i < len(words); i++ {
+		w := words[i]
+		if strings.HasPrefix(w, "#") || w == ">" || w == "2>&1" || strings.HasPrefix(w, ">") {
+			return
+		}
+		if w == "\\" || strings.ContainsAny(w, "<>[]{}$'\"`*") || strings.Contains(w, "...") {
+			argsSeen = true
+			continue
+		}
+		if w == "--" {
+			flagsDone = true
+			continue
+		}
+		if strings.HasPrefix(w, "-") && !flagsDone && len(w) > 1 {
+			name, _, hasValue := strings.Cut(w, "=")
+			if !strings.HasPrefix(name, "--") && len(name) != 2 {
+				continue // Combined short flags or a negative number
+			}
+			if !cmd.known || len(cmd.flags) == 0 || name == "--help" || name == "-h" {
+				continue
+			}
+			f, ok := cmd.flags[name]
+			if !ok {
+				ref := strings.Join(append(path, name), " ")
+				add(lineNo, RuleUnknownFlag, ref, "%s has no %s flag", strings.Join(path, " "), name)
+				continue
+			}
+			if f.TakesValue && !hasValue {
+				i++ // Its value
+			}
+			continue
+		}
+		if argsSeen || len(cmd.subs) == 0 {
+			argsSeen = true
+			continue
+		}
+		if sub, ok := cmd.subs[w]; ok {
+			cmd = sub
+			path = append(path, w)
+			continue
+		}
+		if w == "help" || w == "completion" || !cmd.known || cmd.args {
+			argsSeen = true
+			continue
+		}
+		add(lineNo, RuleUnknownCommand, strings.Join(append(path, w), " "), "%s has no %s subcommand (it has %s)",
+			strings.Join(path, " "), w, strings.Join(sortedNames(cmd.subs), ", "))
+		return
+	}
+}
+
+func sortedNames(m map[string]*surfaceCommand) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// yamlKeys returns the keys of a YAML code block starting at line start of
+// the doc, with their dotted paths. Sequences of mappings add their keys to
+// the sequence's path. A block that does not parse has none.
+func yamlKeys(src string, start int) []keyRef {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	var refs []keyRef
+	var walk func(n *yaml.Node, prefix string)
+	walk = func(n *yaml.Node, prefix string) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i]
+				path := key.Value
+				if prefix != "" {
+					path = prefix + "." + key.Value
+				}
+				refs = append(refs, keyRef{path: path, line: start + key.Line - 1})
+				walk(n.Content[i+1], path)
+			}
+		case yaml.SequenceNode:
+			for _, item := range n.Content {
+				walk(item, prefix)
+			}
+		}
+	}
+	walk(doc.Content[0], "")
+	return refs
+}
+
+// tomlKeys returns the keys of a TOML code block starting at line start of
+// the doc: table headers and the keys below them, with their dotted paths.
+func tomlKeys(lines []string, start int) []keyRef {
+	var refs []keyRef
+	table := ""
+	inString := false
+	for i, line := range lines {
+		if strings.Count(line, `"""`)%2 == 1 || strings.Count(line, `'''`)%2 == 1 {
+			inString = !inString
+			if !inString {
+				continue
+			}
+		}
+		if inString {
+			continue
+		}
+		if m := refTOMLHeadRe.FindStringSubmatch(line); m != nil {
+			table = tomlPath(m[1])
+			refs = append(refs, keyRef{path: table, line: start + i})
+			continue
+		}
+		if m := refTOMLKeyRe.FindStringSubmatch(line); m != nil {
+			path := tomlPath(m[1])
+			if table != "" {
+				path = table + "." + path
+			}
+			refs = append(refs, keyRef{path: path, line: start + i})
+		}
+	}
+	return refs
+}
+
+// tomlPath normalizes a TOML key: quotes and spaces around dots removed.
+func tomlPath(key string) string {
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
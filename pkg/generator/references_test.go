@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/grovetools/docgen/pkg/capture"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/schema"
+)
+
+func TestLintReferences(t *testing.T) {
+	root := &capture.CommandNode{
+		Name:       "docgen",
+		FullName:   "docgen",
+		HelpOutput: "Usage:\n  docgen [command]\n\nFlags:\n  -c, --config string   Config file\n  -h, --help            help for docgen\n",
+		SubCommands: []*capture.CommandNode{{
+			Name:       "generate",
+			FullName:   "docgen generate",
+			HelpOutput: "Usage:\n  docgen generate [flags]\n\nFlags:\n  -s, --section strings   Sections\n      --strict            Fail on warnings\n",
+		}},
+	}
+	s := &surface{
+		commands: map[string]*surfaceCommand{"docgen": newSurfaceCommand(root)},
+		keys:     map[string]bool{},
+		open:     map[string]bool{},
+		roots:    map[string]bool{},
+	}
+	s.addSchema([]schema.Property{
+		{Name: "settings", Type: "object", Properties: []schema.Property{
+			{Name: "model", Type: "string"},
+			{Name: "generation_config", Type: "object"},
+		}},
+		{Name: "sections", Type: "array", Items: &schema.Property{Type: "object", Properties: []schema.Property{
+			{Name: "name", Type: "string"},
+		}}},
+	}, "")
+
+	doc := "# Usage\n\n" +
+		"Run `docgen generate -s overview --strict` or `docgen build`.\n\n" +
+		"```bash\n$ docgen generate --fast | tee log.txt\n```\n\n" +
+		"Set `settings.model`, `settings.generation_config.temperature` and `settings.modle` in `docgen.config.yml`.\n\n" +
+		"```yaml\nsettings:\n  model: gemini\n  budget: 3\nsections:\n  - name: overview\n    kind: prose\n```\n\n" +
+		"```toml\n[settings]\nmodel = \"x\"\n```\n"
+
+	var got []string
+	for _, issue := range lintReferences(s, "usage", "usage.md", doc) {
+		got = append(got, fmt.Sprintf("%d %s %s", issue.Line, issue.Rule, issue.Reference))
+	}
+	want := []string{
+		"3 unknown-command docgen build",
+		"6 unknown-flag docgen generate --fast",
+		"9 unknown-config-key settings.modle",
+		"14 unknown-config-key settings.budget",
+		"17 unknown-config-key sections.kind",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lintReferences =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestSchemaInputs(t *testing.T) {
+	cases := []struct {
+		name    string
+		section config.SectionConfig
+		want    []config.SchemaInput
+	}{
+		{"no source", config.SectionConfig{}, nil},
+		{"single source", config.SectionConfig{Source: config.SourceList{"schema.json"}},
+			[]config.SchemaInput{{Path: "schema.json"}}},
+		{"each source is its own schema", config.SectionConfig{Source: config.SourceList{"a.json", "b.json"}},
+			[]config.SchemaInput{{Path: "a.json"}, {Path: "b.json"}}},
+		{"non-JSON sources are left out", config.SectionConfig{Source: config.SourceList{"a.json", "b.toml"}},
+			[]config.SchemaInput{{Path: "a.json"}}},
+		{"schemas win over source", config.SectionConfig{Source: config.SourceList{"a.json"}, Schemas: []config.SchemaInput{{Path: "c.json", Title: "C"}}},
+			[]config.SchemaInput{{Path: "c.json", Title: "C"}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := schemaInputs(c.section); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("schemaInputs() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters of rankFiles, at their usual values.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// relevancePathBoost is how many occurrences in a file's contents a word of
+// its path counts for: a file named after the topic is about it.
+const relevancePathBoost = 5
+
+// topicStopWords are words of prompts that say what to write rather than
+// what it is about.
+var topicStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "not": true, "but": true, "you": true, "your": true,
+	"all": true, "any": true, "can": true, "how": true, "its": true, "use": true, "using": true, "with": true,
+	"this": true, "that": true, "these": true, "those": true, "from": true, "into": true, "each": true, "what": true,
+	"when": true, "which": true, "will": true, "should": true, "must": true, "also": true, "about": true, "only": true,
+	"more": true, "most": true, "such": true, "them": true, "they": true, "their": true, "then": true, "than": true,
+	"have": true, "has": true, "does": true, "like": true, "make": true, "used": true, "well": true, "other": true,
+	"write": true, "writing": true, "section": true, "sections": true, "document": true, "documentation": true,
+	"docs": true, "explain": true, "describe": true, "include": true, "including": true, "example": true,
+	"examples": true, "overview": true, "guide": true, "page": true, "markdown": true, "heading": true,
+	"headings": true, "reader": true, "readers": true, "user": true, "users": true, "clear": true, "concise": true,
+}
+
+// topicTerms returns the distinct words of text that name a section's topic,
+// leaving out the words of instructions.
+func topicTerms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range relevanceWords(text) {
+		if !seen[w] && !topicStopWords[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// relevanceWords splits text into lowercase words of at least three
+// letters or digits, splitting identifiers at case changes, so LoginHandler
+// and login_handler both hold login and handler.
+func relevanceWords(text string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) >= 3 {
+			words = append(words, strings.ToLower(string(word)))
+		}
+		word = word[:0]
+	}
+	var prev rune
+	for _, r := range text {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+	return words
+}
+
+// rankFiles orders files by relevance to topic, most relevant first, by
+// their BM25 score over the words of their paths and contents. Files of
+// equal score keep their order.
+func rankFiles(topic []string, files []*contextFile) []*contextFile {
+	terms := make(map[string]bool, len(topic))
+	for _, t := range topic {
+		terms[t] = true
+	}
+	counts := make([]map[string]int, len(files))
+	lengths := make([]int, len(files))
+	docFreq := make(map[string]int)
+	total := 0
+	for i, f := range files {
+		counts[i] = make(map[string]int)
+		for _, w := range relevanceWords(f.path) {
+			if terms[w] {
+				counts[i][w] += relevancePathBoost
+			}
+		}
+		body := relevanceWords(f.body)
+		for _, w := range body {
+			if terms[w] {
+				counts[i][w]++
+			}
+		}
+		lengths[i] = len(body)
+		total += len(body)
+		for w := range counts[i] {
+			docFreq[w]++
+		}
+	}
+	avgLength := math.Max(float64(total)/math.Max(float64(len(files)), 1), 1)
+
+	scores := make(map[*contextFile]float64, len(files))
+	for i, f := range files {
+		score := 0.0
+		for w, tf := range counts[i] {
+			idf := math.Log(1 + (float64(len(files))-float64(docFreq[w])+0.5)/(float64(docFreq[w])+0.5))
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(lengths[i])/avgLength)
+			score += idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + norm)
+		}
+		scores[f] = score
+	}
+	ranked := append([]*contextFile(nil), files...)
+	sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+	return ranked
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelevanceWords(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"LoginHandler", []string{"login", "handler"}},
+		{"login_handler", []string{"login", "handler"}},
+		{"pkg/auth/loginHandler.go", []string{"pkg", "auth", "login", "handler"}},
+		{"HTTPServer", []string{"httpserver"}},
+		{"parseV2Config", []string{"parse", "v2config"}},
+		{"an id of a db", nil},
+		{"Écrire données", []string{"écrire", "données"}},
+	}
+	for _, c := range cases {
+		if got := relevanceWords(c.in); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("relevanceWords(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestTopicTerms(t *testing.T) {
+	got := topicTerms("Write the Login section: explain how LoginHandler checks the session")
+	want := []string{"login", "handler", "checks", "session"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("topicTerms() = %q, want %q", got, want)
+	}
+}
+
+func TestRankFiles(t *testing.T) {
+	files := func() []*contextFile {
+		return []*contextFile{
+			{path: "db/store.go", body: "func Open() {}\nfunc Close() {}\n"},
+			{path: "auth/session.go", body: "func NewSession() {}\n// the login flow creates a session\n"},
+			{path: "auth/login.go", body: "func Login() {}\n"},
+			{path: "cmd/root.go", body: "func Execute() {}\n"},
+		}
+	}
+	cases := []struct {
+		name  string
+		topic []string
+		want  []string
+	}{
+		{"path matches rank first", []string{"login"}, []string{"auth/login.go", "auth/session.go", "db/store.go", "cmd/root.go"}},
+		{"path words outweigh contents", []string{"open", "session"}, []string{"auth/session.go", "db/store.go", "auth/login.go", "cmd/root.go"}},
+		{"no matches keep their order", []string{"billing"}, []string{"db/store.go", "auth/session.go", "auth/login.go", "cmd/root.go"}},
+		{"no topic keeps the order", nil, []string{"db/store.go", "auth/session.go", "auth/login.go", "cmd/root.go"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			in := files()
+			var got []string
+			for _, f := range rankFiles(c.topic, in) {
+				got = append(got, f.path)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("rankFiles(%q) = %q, want %q", c.topic, got, c.want)
+			}
+			if in[0].path != "db/store.go" {
+				t.Error("rankFiles reordered its input")
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rulesLocks serializes the guarded context builds of a workspace, so
+// packages generated concurrently from one repository never snapshot
+// .grove/rules while another build has it rewritten.
+var rulesLocks sync.Map // rules path -> *sync.Mutex
+
+// guardRules snapshots the .grove/rules of the repository holding dir and
+// returns a function restoring it. Docgen passes its docs rules to cx with
+// --rules-file and never writes .grove/rules itself, but cx may rewrite the
+// active rules while regenerating; restoring keeps a generation run free of
+// side effects on the user's rules. Only cx context builds are guarded, and
+// the file is restored as soon as the build exits, while it holds what cx
+// wrote: edits the user makes during the long LLM requests are kept.
+func (g *Generator) guardRules(dir string) func() {
+	if dir == "" {
+		return func() {}
+	}
+	path := filepath.Join(repoRoot(dir), ".grove", "rules")
+	mu, _ := rulesLocks.LoadOrStore(path, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	before, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		mu.(*sync.Mutex).Unlock()
+		return func() {} // Unreadable: leave it alone
+	}
+	return func() {
+		defer mu.(*sync.Mutex).Unlock()
+		after, err := os.ReadFile(path)
+		switch {
+		case !existed && os.IsNotExist(err):
+		case err != nil:
+			// Removed or unreadable: not something cx leaves behind
+		case !existed:
+			g.logger.Debugf("Removing %s created during context building", path)
+			_ = os.Remove(path)
+		case !bytes.Equal(before, after):
+			g.logger.Debugf("Restoring %s rewritten during context building", path)
+			_ = os.WriteFile(path, before, 0o644)
+		}
+	}
+}
+
+// repoRoot returns the root of the git repository holding dir, or dir when
+// it is not in one.
+func repoRoot(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for d := abs; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGuardRules(t *testing.T) {
+	setup := func(t *testing.T, rules string) (pkgDir, rulesPath string) {
+		root := t.TempDir()
+		pkgDir = filepath.Join(root, "pkg", "api")
+		for _, d := range []string{filepath.Join(root, ".git"), filepath.Join(root, ".grove"), pkgDir} {
+			if err := os.MkdirAll(d, 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rulesPath = filepath.Join(root, ".grove", "rules")
+		if rules != "" {
+			if err := os.WriteFile(rulesPath, []byte(rules), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return pkgDir, rulesPath
+	}
+
+	t.Run("rewrite at the repo root is restored", func(t *testing.T) {
+		pkgDir, rulesPath := setup(t, "*.go\n")
+		restore := newTestGenerator().guardRules(pkgDir)
+		if err := os.WriteFile(rulesPath, []byte("docs/**\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		restore()
+		if got, _ := os.ReadFile(rulesPath); string(got) != "*.go\n" {
+			t.Errorf("rules = %q, want the original", got)
+		}
+	})
+
+	t.Run("created rules are removed", func(t *testing.T) {
+		pkgDir, rulesPath := setup(t, "")
+		restore := newTestGenerator().guardRules(pkgDir)
+		if err := os.WriteFile(rulesPath, []byte("docs/**\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		restore()
+		if _, err := os.Stat(rulesPath); !os.IsNotExist(err) {
+			t.Errorf("rules created during the build were kept (err %v)", err)
+		}
+	})
+
+	t.Run("edits after the build are kept", func(t *testing.T) {
+		pkgDir, rulesPath := setup(t, "*.go\n")
+		newTestGenerator().guardRules(pkgDir)()
+		if err := os.WriteFile(rulesPath, []byte("edited\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		newTestGenerator().guardRules(pkgDir)()
+		if got, _ := os.ReadFile(rulesPath); string(got) != "edited\n" {
+			t.Errorf("rules = %q, want the user's edit", got)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+var (
+	// preambleRegex matches the chatty lead-in models put before a document:
+	// an interjection, an introduction ending in a colon, or a report of
+	// what was written.
+	preambleRegex = regexp.MustCompile(`(?i)^(sure|certainly|of course|okay|absolutely)\b[ ,.!]|^(here(’s|'s| is| are)|below is|below you will find|the following is)\b.*:$|^i('ve| have)( now)? (written|generated|created|updated|revised|prepared)\b`)
+	// postambleRegex matches the sign-off models put after a document.
+	postambleRegex = regexp.MustCompile(`(?i)^(let me know|i hope (this|that)|feel free|if you('d| would)? like|would you like|this (document|documentation|guide) (covers|provides|should))\b`)
+	// wrapperFenceRegex matches the opening of a fence wrapping a whole
+	// document: markdown, md, mdx or no language.
+	wrapperFenceRegex = regexp.MustCompile("^(```+|~~~+)[ \t]*(markdown|md|mdx)?[ \t]*$")
+	fenceMarkerRegex  = regexp.MustCompile("^(```+|~~~+)(.*)$")
+)
+
+// SanitizeResponse cleans an LLM response meant to be a markdown document,
+// returning the document and the names of the fixes applied. It strips, in
+// order: a byte order mark and zero-width characters, a chatty preamble
+// ("Here is the documentation:"), a fence wrapping the whole document
+// (including one the model never closed, and nested fences inside it), and a
+// sign-off after the document ("Let me know if ...").
+func SanitizeResponse(response string) (string, []string) {
+	var fixes []string
+	s := strings.ReplaceAll(response, "\r\n", "\n")
+	if trimmed := strings.TrimLeft(s, "\ufeff\u200b\u200c\u200d\u2060"); trimmed != s {
+		s = trimmed
+		fixes = append(fixes, "bom")
+	}
+	s = strings.TrimSpace(s)
+
+	if rest, ok := stripPreamble(s); ok {
+		s = rest
+		fixes = append(fixes, "preamble")
+	}
+	if rest, ok := stripWrapperFence(s); ok {
+		s = rest
+		fixes = append(fixes, "fence")
+	}
+	if rest, ok := stripPostamble(s); ok {
+		s = rest
+		fixes = append(fixes, "postamble")
+	}
+	return s, fixes
+}
+
+// stripPreamble drops leading lines of conversational prose, up to the first
+// blank line after them, when a document follows.
+func stripPreamble(s string) (string, bool) {
+	lines := strings.Split(s, "\n")
+	i := 0
+	for i < len(lines) && isPreambleLine(lines[i]) {
+		i++
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			i++
+		}
+	}
+	if i == 0 || i == len(lines) {
+		return s, false
+	}
+	return strings.Join(lines[i:], "\n"), true
+}
+
+// isPreambleLine reports whether line is chatty lead-in prose rather than
+// the start of a document.
+func isPreambleLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || len(line) > 300 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "```") {
+		return false
+	}
+	return preambleRegex.MatchString(line)
+}
+
+// stripWrapperFence removes a fence wrapping the whole document. The closing
+// fence is the last line that is a bare fence of the opening's character and
+// at least its length, with only a sign-off allowed after it; when there is
+// none, the model stopped before closing and only the opening is removed.
+func stripWrapperFence(s string) (string, bool) {
+	lines := strings.Split(s, "\n")
+	m := wrapperFenceRegex.FindStringSubmatch(lines[0])
+	if m == nil || len(lines) < 2 {
+		return s, false
+	}
+	fence := m[1]
+	body := lines[1:]
+
+	closing := -1
+	for i := len(body) - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(body[i])
+		if trimmed == "" || isPostambleLine(trimmed) {
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			closing = i
+		}
+		break
+	}
+	if m[2] == "" {
+		// A bare fence wraps the document only when a document follows it
+		// and its closing fence is not one of the document's code blocks;
+		// otherwise the response starts with a code block
+		first := strings.TrimSpace(body[0])
+		if closing == -1 || !(strings.HasPrefix(first, "#") || first == "---") || !balancedFences(body[:closing]) {
+			return s, false
+		}
+	}
+	if closing == -1 {
+		return strings.TrimSpace(strings.Join(body, "\n")), true
+	}
+	return strings.TrimSpace(strings.Join(body[:closing], "\n")), true
+}
+
+// balancedFences reports whether every fenced block in lines is closed.
+func balancedFences(lines []string) bool {
+	open := ""
+	for _, line := range lines {
+		m := fenceMarkerRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		switch {
+		case open == "":
+			open = m[1]
+		case m[1][0] == open[0] && len(m[1]) >= len(open) && strings.TrimSpace(m[2]) == "":
+			open = ""
+		}
+	}
+	return open == ""
+}
+
+// stripPostamble drops a trailing paragraph of conversational sign-off.
+func stripPostamble(s string) (string, bool) {
+	lines := strings.Split(s, "\n")
+	end := len(lines)
+	for end > 0 {
+		i := end - 1
+		for i >= 0 && strings.TrimSpace(lines[i]) == "" {
+			i--
+		}
+		if i < 0 || !isPostambleLine(strings.TrimSpace(lines[i])) {
+			break
+		}
+		end = i
+	}
+	if end == len(lines) || end == 0 {
+		return s, false
+	}
+	return strings.TrimSpace(strings.Join(lines[:end], "\n")), true
+}
+
+// isPostambleLine reports whether line is a conversational sign-off.
+func isPostambleLine(line string) bool {
+	return len(line) <= 300 && postambleRegex.MatchString(line)
+}
+
+// NeedsExtraction reports whether a sanitized response still reads like a
+// conversation rather than a document, so it is worth asking the model to
+// extract the document from it.
+func NeedsExtraction(response string) bool {
+	first := strings.TrimSpace(strings.SplitN(strings.TrimSpace(response), "\n", 2)[0])
+	return isPreambleLine(first) || strings.Contains(strings.ToLower(response), "as an ai")
+}
+
+// extractDocumentPrompt asks a model to return just the document from a
+// response that mixes it with conversation.
+const extractDocumentPrompt = `The text between <response> tags is a model's answer that should have been
+a markdown document but also contains conversational text addressed to the
+user (introductions, explanations of what was done, offers of further help).
+
+Return only the markdown document, exactly as written, without the
+conversational text, without wrapping it in a code fence, and without any
+comment of your own.
+
+<response>
+%s
+</response>`
+
+// extractDocument is the optional fallback pass for sections whose response
+// still reads like a conversation after SanitizeResponse: it asks the model
+// to extract the document. The response is kept when the pass is off, not
+// needed, or fails.
+func (g *Generator) extractDocument(response, model string, cfg *config.DocgenConfig, genConfig config.GenerationConfig, workDir string) string {
+	if !cfg.Settings.SanitizeWithLLM || !NeedsExtraction(response) {
+		return response
+	}
+	g.logger.Infof("Response still contains conversational text; asking the model to extract the document")
+	extracted, err := g.CallLLM(strings.Replace(extractDocumentPrompt, "%s", response, 1), model, genConfig, workDir)
+	if err != nil || strings.TrimSpace(extracted) == "" {
+		g.logger.Warnf("Document extraction failed, keeping the response as is: %v", err)
+		return response
+	}
+	return extracted
+}
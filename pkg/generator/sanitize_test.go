@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeResponse(t *testing.T) {
+	cases := []struct {
+		name  string
+		in    string
+		want  string
+		fixes []string
+	}{
+		{
+			name: "clean document is unchanged",
+			in:   "# Title\n\nBody text.",
+			want: "# Title\n\nBody text.",
+		},
+		{
+			name:  "byte order mark",
+			in:    "\ufeff# Title\n\nBody",
+			want:  "# Title\n\nBody",
+			fixes: []string{"bom"},
+		},
+		{
+			name:  "preamble before the document",
+			in:    "Here is the documentation:\n\n# Title\n\nBody",
+			want:  "# Title\n\nBody",
+			fixes: []string{"preamble"},
+		},
+		{
+			name:  "interjection and introduction",
+			in:    "Sure! I've written the overview you asked for.\n\n# Overview\n\nBody",
+			want:  "# Overview\n\nBody",
+			fixes: []string{"preamble"},
+		},
+		{
+			name: "prose opening that is part of the document",
+			in:   "Here is how the cache works in practice.\n\n## Details",
+			want: "Here is how the cache works in practice.\n\n## Details",
+		},
+		{
+			name:  "preamble then wrapping fence",
+			in:    "Here's the updated documentation:\n\n```markdown\n# Title\n\nBody\n```",
+			want:  "# Title\n\nBody",
+			fixes: []string{"preamble", "fence"},
+		},
+		{
+			name:  "wrapping fence with nested code blocks",
+			in:    "```markdown\n# Install\n\n```bash\ngo install ./...\n```\n\nDone.\n```",
+			want:  "# Install\n\n```bash\ngo install ./...\n```\n\nDone.",
+			fixes: []string{"fence"},
+		},
+		{
+			name:  "longer wrapping fence around triple backticks",
+			in:    "````md\n# Usage\n\n```\ndocgen generate\n```\n````",
+			want:  "# Usage\n\n```\ndocgen generate\n```",
+			fixes: []string{"fence"},
+		},
+		{
+			name:  "unclosed wrapping fence",
+			in:    "```markdown\n# Title\n\nBody that was cut off",
+			want:  "# Title\n\nBody that was cut off",
+			fixes: []string{"fence"},
+		},
+		{
+			name:  "bare wrapping fence",
+			in:    "```\n# Title\nbody\n```",
+			want:  "# Title\nbody",
+			fixes: []string{"fence"},
+		},
+		{
+			name: "document starting with a code block",
+			in:   "```\ndocgen init\n```\n\nThen edit the config.\n\n```\ndocgen generate\n```",
+			want: "```\ndocgen init\n```\n\nThen edit the config.\n\n```\ndocgen generate\n```",
+		},
+		{
+			name:  "sign-off after the fence",
+			in:    "```markdown\n# Title\n\nBody\n```\n\nLet me know if you'd like any changes.",
+			want:  "# Title\n\nBody",
+			fixes: []string{"fence"},
+		},
+		{
+			name:  "sign-off without a fence",
+			in:    "# Title\n\nBody\n\nI hope this helps!",
+			want:  "# Title\n\nBody",
+			fixes: []string{"postamble"},
+		},
+		{
+			name:  "windows line endings",
+			in:    "```markdown\r\n# Title\r\n```\r\n",
+			want:  "# Title",
+			fixes: []string{"fence"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, fixes := SanitizeResponse(c.in)
+			if got != c.want {
+				t.Errorf("SanitizeResponse(%q) = %q, want %q", c.in, got, c.want)
+			}
+			if !reflect.DeepEqual(fixes, c.fixes) {
+				t.Errorf("SanitizeResponse(%q) fixes = %v, want %v", c.in, fixes, c.fixes)
+			}
+		})
+	}
+}
+
+func TestNeedsExtraction(t *testing.T) {
+	cases := []struct {
+		in   string
+		want bool
+	}{
+		{"# Title\n\nBody", false},
+		{"Certainly, here you go.\nThe tool does things.", true},
+		{"As an AI, I cannot run the tool, but here is what it does.", true},
+		{"Here is how the cache works in practice.", false},
+	}
+	for _, c := range cases {
+		if got := NeedsExtraction(c.in); got != c.want {
+			t.Errorf("NeedsExtraction(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
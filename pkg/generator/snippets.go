@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/snippets"
+)
+
+// expandSnippets inlines {{snippet name}} references in content with the
+// current code from the package's sources. References to unknown snippets are
+// left in place with a warning; 'docgen check snippets' fails on them.
+func (g *Generator) expandSnippets(packageDir, content string) string {
+	if !snippets.HasRefs(content) {
+		return content
+	}
+	idx, err := snippets.Scan(packageDir)
+	if err != nil {
+		g.logger.Warnf("Failed to scan snippets, leaving references in place: %v", err)
+		return content
+	}
+	expanded, missing := snippets.Expand(content, idx)
+	if len(missing) > 0 {
+		g.logger.Warnf("Unknown snippets left in place: %s", strings.Join(missing, ", "))
+	}
+	return expanded
+}
+
+// SnippetReport is the result of CheckSnippets.
+type SnippetReport struct {
+	Snippets int            `json:"snippets"` // Snippets defined in the package's sources
+	Files    []string       `json:"files"`    // Prompts and docs that were checked
+	Refs     []snippets.Ref `json:"refs"`
+	Missing  []snippets.Ref `json:"missing"` // References to snippets that no longer exist
+}
+
+// CheckSnippets scans the package's sources for snippets and reports every
+// {{snippet name}} reference in the sections' prompts and generated docs whose
+// snippet no longer exists. sections limits the check to the named sections.
+func (g *Generator) CheckSnippets(packageDir string, sections []string) (*SnippetReport, error) {
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := snippets.Scan(packageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range sections {
+		wanted[name] = true
+	}
+
+	report := &SnippetReport{Snippets: len(idx), Refs: []snippets.Ref{}, Missing: []snippets.Ref{}}
+	seen := make(map[string]bool)
+	check := func(path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		data, err := os.ReadFile(path) //nolint:gosec // prompt or output path from config
+		if err != nil {
+			return
+		}
+		report.Files = append(report.Files, path)
+		for _, ref := range snippets.Refs(path, string(data)) {
+			report.Refs = append(report.Refs, ref)
+			if _, ok := idx[ref.Name]; !ok {
+				report.Missing = append(report.Missing, ref)
+			}
+		}
+	}
+
+	for _, t := range targets {
+		if len(wanted) > 0 && !wanted[t.Name] && !wanted[t.Section.Name] {
+			continue
+		}
+		if t.Section.Prompt != "" {
+			promptPath := filepath.Join(t.ConfigDir, "prompts", t.Section.Prompt)
+			if _, err := os.Stat(promptPath); err != nil {
+				promptPath, _ = g.resolvePromptPath(packageDir, t.Section.Prompt)
+			}
+			check(promptPath)
+		}
+		if t.Section.Output != "" {
+			check(filepath.Join(t.OutputDir, t.Section.Output))
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/reference"
+)
+
+// migrationDirs are looked for when a sql_schema section sets neither source
+// nor dsn.
+var migrationDirs = []string{"migrations", "db/migrations", "sql/migrations", "internal/db/migrations"}
+
+// generateSQLSchema documents the package's database schema — tables,
+// columns, indexes and foreign keys — by replaying its migration files, or
+// by dumping the schema of the development database at dsn. An ER diagram is
+// written as a Mermaid asset next to the doc and, when mmdc is installed,
+// rendered to SVG.
+func (g *Generator) generateSQLSchema(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
+	g.logger.Infof("Generating SQL schema reference: %s", section.Name)
+
+	schema := &reference.SQLSchema{}
+	if section.DSN != "" {
+		ddl, err := reference.DumpSchema(os.ExpandEnv(section.DSN))
+		if err != nil {
+			return fmt.Errorf("failed to introspect database: %w", err)
+		}
+		schema.Apply(ddl)
+	} else {
+		paths := []string(section.Source)
+		if len(paths) == 0 {
+			for _, dir := range migrationDirs {
+				if info, err := os.Stat(filepath.Join(packageDir, dir)); err == nil && info.IsDir() {
+					paths = []string{dir}
+					break
+				}
+			}
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("no migrations directory found (set 'source' or 'dsn')")
+		}
+		files, err := reference.MigrationFiles(packageDir, paths)
+		if err != nil {
+			return fmt.Errorf("failed to list migrations: %w", err)
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file) //nolint:gosec // migration from config
+			if err != nil {
+				return fmt.Errorf("failed to read migration: %w", err)
+			}
+			schema.Apply(reference.UpMigration(string(data)))
+		}
+		g.logger.Infof("Replayed %d migrations", len(files))
+	}
+	if len(schema.Tables) == 0 {
+		return fmt.Errorf("no tables found")
+	}
+
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	diagram, err := g.writeERDiagram(section, filepath.Dir(outputPath), schema.Mermaid())
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	title := section.Title
+	if title == "" {
+		title = "Database Schema"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	sb.WriteString(diagram + "\n")
+
+	for _, t := range schema.Tables {
+		fmt.Fprintf(&sb, "## `%s`\n\n", t.Name)
+		if t.Comment != "" {
+			sb.WriteString(t.Comment + "\n\n")
+		}
+		refs := make(map[string]string)
+		for _, fk := range t.ForeignKeys {
+			target := fk.RefTable
+			if len(fk.RefColumns) > 0 {
+				target += "." + strings.Join(fk.RefColumns, ", ")
+			}
+			for _, c := range fk.Columns {
+				refs[strings.ToLower(c)] = target
+			}
+		}
+		sb.WriteString("| Column | Type | Nullable | Default | Description |\n")
+		sb.WriteString("| :--- | :--- | :--- | :--- | :--- |\n")
+		for _, c := range t.Columns {
+			name := "`" + c.Name + "`"
+			if c.PrimaryKey {
+				name += " (PK)"
+			}
+			desc := tableCell(c.Comment)
+			if ref, ok := refs[strings.ToLower(c.Name)]; ok {
+				if desc != "" {
+					desc += " "
+				}
+				desc += "References `" + ref + "`."
+			}
+			nullable := "no"
+			if c.Nullable {
+				nullable = "yes"
+			}
+			def := ""
+			if c.Default != "" {
+				def = "`" + tableCell(c.Default) + "`"
+			}
+			fmt.Fprintf(&sb, "| %s | `%s` | %s | %s | %s |\n", name, tableCell(c.Type), nullable, def, desc)
+		}
+		sb.WriteString("\n")
+
+		if len(t.Indexes) > 0 {
+			sb.WriteString("**Indexes:**\n\n")
+			for _, idx := range t.Indexes {
+				kind := "index"
+				if idx.Unique {
+					kind = "unique"
+				}
+				name := ""
+				if idx.Name != "" {
+					name = "`" + idx.Name + "` "
+				}
+				fmt.Fprintf(&sb, "- %s%s on (%s)\n", name, kind, strings.Join(idx.Columns, ", "))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if _, err := writeReference(section, outputBaseDir, strings.TrimRight(sb.String(), "\n")+"\n"); err != nil {
+		return err
+	}
+
+	g.logger.Infof("Successfully wrote %d tables to %s", len(schema.Tables), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("tables", len(schema.Tables)).
+		Emit()
+	return nil
+}
+
+// writeERDiagram writes the Mermaid source of the ER diagram to
+// images/<section>-er.mmd under docDir and returns the markdown that shows
+// it: an image of the SVG rendered by mmdc when it is installed, or else the
+// Mermaid block itself.
+func (g *Generator) writeERDiagram(section config.SectionConfig, docDir, mermaid string) (string, error) {
+	imagesDir := filepath.Join(docDir, "images")
+	if err := os.MkdirAll(imagesDir, 0o755); err != nil { //nolint:gosec // internal doc tool
+		return "", fmt.Errorf("failed to create images directory: %w", err)
+	}
+	base := section.Name + "-er"
+	src := filepath.Join(imagesDir, base+".mmd")
+	if err := os.WriteFile(src, []byte(mermaid), 0o644); err != nil { //nolint:gosec // generated asset
+		return "", fmt.Errorf("failed to write ER diagram: %w", err)
+	}
+
+	block := "```mermaid\n" + mermaid + "```\n"
+	if _, err := exec.LookPath("mmdc"); err != nil {
+		return block, nil
+	}
+	svg := filepath.Join(imagesDir, base+".svg")
+	if out, err := exec.Command("mmdc", "-i", src, "-o", svg).CombinedOutput(); err != nil { //nolint:gosec // paths we wrote
+		g.logger.Warnf("Failed to render ER diagram with mmdc, embedding Mermaid source: %v: %s", err, strings.TrimSpace(string(out)))
+		return block, nil
+	}
+	return fmt.Sprintf("![Entity-relationship diagram](./images/%s.svg)\n", base), nil
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// StaleSections returns the sections of the package at packageDir whose doc
+// is out of date: never generated, or older than its prompt or the docgen
+// config it comes from. Names are in the form GenerateOptions.Sections
+// accepts (subdir/section in sections mode). Sections without a prompt,
+// such as captures and references built from code, are stale only when
+// their doc is missing or older than the config.
+func (g *Generator) StaleSections(packageDir string) ([]string, error) {
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, t := range targets {
+		if t.Section.Output == "" {
+			continue
+		}
+		doc, err := os.Stat(filepath.Join(t.OutputDir, t.Section.Output))
+		if err != nil {
+			stale = append(stale, t.Name)
+			continue
+		}
+		inputs := []string{filepath.Join(t.ConfigDir, config.ConfigFileName)}
+		if t.Section.Prompt != "" {
+			inputs = append(inputs, g.stalePromptPath(packageDir, t))
+		}
+		if newerThan(inputs, doc.ModTime()) {
+			stale = append(stale, t.Name)
+		}
+	}
+	return stale, nil
+}
+
+// stalePromptPath returns the prompt file of t: in its config directory's
+// prompts/ (sections mode and notebook configs), else as generate resolves
+// it. An unresolvable prompt returns "", which is never newer.
+func (g *Generator) stalePromptPath(packageDir string, t SectionTarget) string {
+	local := filepath.Join(t.ConfigDir, "prompts", t.Section.Prompt)
+	if _, err := os.Stat(local); err == nil {
+		return local
+	}
+	path, err := g.resolvePromptPath(packageDir, t.Section.Prompt)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// newerThan reports whether any of paths was modified after t.
+func newerThan(paths []string, t time.Time) bool {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(t) {
+			return true
+		}
+	}
+	return false
+}
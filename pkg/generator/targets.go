@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// SectionTarget is a configured section together with the config it came
+// from and the directory its output is written to.
+type SectionTarget struct {
+	Name      string // Qualified name (subdir/section) in sections mode
+	Section   config.SectionConfig
+	Config    *config.DocgenConfig
+	OutputDir string
+	ConfigDir string // Directory of the docgen config the section comes from
+}
+
+// outputBaseDirFor returns the output base directory for a config loaded from
+// configPath. Notebook configs (outside packageDir) write to the notebook's
+// docgen/docs/; repo configs write to settings.output_dir (default: docs/).
+func outputBaseDirFor(packageDir, configPath string, cfg *config.DocgenConfig) (string, bool) {
+	// Check if config was loaded from notebook by checking if config path is outside the repo
+	// (notebook configs won't be under packageDir)
+	if !strings.HasPrefix(configPath, packageDir) {
+		return filepath.Join(filepath.Dir(configPath), "docs"), true
+	}
+	if cfg.Settings.OutputDir != "" {
+		return filepath.Join(packageDir, cfg.Settings.OutputDir), false
+	}
+	return filepath.Join(packageDir, "docs"), false
+}
+
+// ResolveSectionTargets loads the package's docgen config and returns every
+// section with its output directory, following the same resolution as
+// generate: notebook or repo mode, and subdirectory configs in sections mode.
+func ResolveSectionTargets(packageDir string) ([]SectionTarget, error) {
+	cfg, configPath, err := config.LoadWithNotebook(packageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load docgen config: %w", err)
+	}
+
+	if cfg.Settings.OutputMode != "sections" {
+		outputDir, _ := outputBaseDirFor(packageDir, configPath, cfg)
+		targets := make([]SectionTarget, 0, len(cfg.Sections))
+		for _, section := range cfg.Sections {
+			targets = append(targets, SectionTarget{Name: section.Name, Section: section, Config: cfg, OutputDir: outputDir, ConfigDir: filepath.Dir(configPath)})
+		}
+		return targets, nil
+	}
+
+	docgenDir := filepath.Dir(configPath)
+	entries, err := os.ReadDir(docgenDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docgen directory %s: %w", docgenDir, err)
+	}
+
+	var targets []SectionTarget
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subDir := filepath.Join(docgenDir, entry.Name())
+		subConfigPath := filepath.Join(subDir, config.ConfigFileName)
+		if _, err := os.Stat(subConfigPath); err != nil {
+			continue
+		}
+		subCfg, err := config.LoadFromPath(subConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config from %s: %w", subConfigPath, err)
+		}
+		outputDir := filepath.Join(subDir, "docs")
+		if subCfg.Settings.OutputDir != "" {
+			outputDir = filepath.Join(subDir, subCfg.Settings.OutputDir)
+		}
+		for _, section := range subCfg.Sections {
+			targets = append(targets, SectionTarget{
+				Name:      entry.Name() + "/" + section.Name,
+				Section:   section,
+				Config:    subCfg,
+				OutputDir: outputDir,
+				ConfigDir: subDir,
+			})
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/grovetools/docgen/internal/fsutil"
+	"github.com/grovetools/docgen/pkg/telemetry"
+)
+
+// startSection opens a section in the run report and the progress line and
+// starts its span, ending the previous section's. Like the report, a section
+// stays open until it fails or the next one starts.
+func (g *Generator) startSection(name, typ, output string) {
+	g.report.StartSection("", name, typ, output)
+	g.endSectionSpan()
+	g.sectionTask = g.progress.Start(name)
+	if typ == "" {
+		typ = "prose"
+	}
+	g.sectionCtx, g.sectionSpan = telemetry.Start(g.runContext(), "docgen.section",
+		telemetry.String("docgen.section", name),
+		telemetry.String("docgen.section.type", typ),
+		telemetry.String("docgen.output", output))
+}
+
+// endSection closes the open section as succeeded.
+func (g *Generator) endSection() {
+	g.report.EndSection()
+	g.endSectionSpan()
+}
+
+// endSectionSpan ends the open section's span and progress entry; one that
+// recorded no failure succeeded.
+func (g *Generator) endSectionSpan() {
+	g.sectionSpan.SetOK()
+	g.sectionSpan.End()
+	g.sectionCtx, g.sectionSpan = nil, nil
+	g.sectionTask.End()
+	g.sectionTask = nil
+}
+
+// failSectionSpan marks the open section's span and progress entry as
+// failed and counts the failure.
+func (g *Generator) failSectionSpan(err error) {
+	g.sectionSpan.RecordError(err)
+	g.sectionTask.Fail()
+	telemetry.Add(telemetry.MetricSectionFailure, "{section}", 1, telemetry.String("command", "generate"))
+}
+
+// spanContext returns the context new spans are children of: the open
+// section's, else the run's.
+func (g *Generator) spanContext() context.Context {
+	if g.sectionCtx != nil {
+		return g.sectionCtx
+	}
+	return g.runContext()
+}
+
+// writeOutput writes a section's output atomically, creating its directory,
+// under a span.
+func (g *Generator) writeOutput(path, output string) (err error) {
+	_, span := telemetry.Start(g.spanContext(), "docgen.write",
+		telemetry.String("docgen.output", path),
+		telemetry.Int("docgen.bytes", int64(len(output))))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, []byte(output), 0o644)
+}
+
+// estimateTokens estimates the tokens of an LLM response at four bytes a
+// token.
+func estimateTokens(response []byte) int64 {
+	return int64(len(response)+3) / 4
+}
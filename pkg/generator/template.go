@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+const templateSystemPrompt = `You are filling in the placeholders of a documentation page whose other text is fixed.
+
+The page is below, with each placeholder replaced by a marker such as [[FILL 1]]. For each placeholder, write only the text that replaces its marker, following its instruction and fitting the text around it: a phrase or sentences for a marker inside a line, paragraphs for a marker on a line of its own. Base it on the project context you have been given, and do not repeat the fixed text.
+
+Answer with one block per placeholder and nothing else:
+
+<fill id="1">
+The text for placeholder 1.
+</fill>
+
+`
+
+var (
+	// llmPlaceholderRe matches {{llm "instruction"}}; the instruction is a
+	// Go string literal.
+	llmPlaceholderRe = regexp.MustCompile(`\{\{\s*llm\s+("(?:[^"\\\n]|\\.)*")\s*\}\}`)
+	fillRe           = regexp.MustCompile(`(?s)<fill id="(\d+)">(.*?)</fill>`)
+)
+
+// docTemplate is a parsed template: the fixed text around the placeholders
+// and each placeholder's instruction. statics has one more entry than
+// instructions.
+type docTemplate struct {
+	statics      []string
+	instructions []string
+}
+
+func parseTemplate(src string) (*docTemplate, error) {
+	t := &docTemplate{}
+	last := 0
+	for _, m := range llmPlaceholderRe.FindAllStringSubmatchIndex(src, -1) {
+		instruction, err := strconv.Unquote(src[m[2]:m[3]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder %s: %w", src[m[0]:m[1]], err)
+		}
+		t.statics = append(t.statics, src[last:m[0]])
+		t.instructions = append(t.instructions, instruction)
+		last = m[1]
+	}
+	t.statics = append(t.statics, src[last:])
+	return t, nil
+}
+
+// render joins the fixed text with the fills, one per placeholder.
+func (t *docTemplate) render(fills []string) string {
+	var sb strings.Builder
+	for i, s := range t.statics {
+		sb.WriteString(s)
+		if i < len(fills) {
+			sb.WriteString(fills[i])
+		}
+	}
+	return sb.String()
+}
+
+// previousFills recovers the fills of a doc rendered from t by matching its
+// fixed text. It returns nil when the doc does not match, e.g. after the
+// template changed around a placeholder or two placeholders are adjacent.
+func (t *docTemplate) previousFills(doc string) []string {
+	if len(t.instructions) == 0 || !strings.HasPrefix(doc, t.statics[0]) {
+		return nil
+	}
+	fills := make([]string, 0, len(t.instructions))
+	pos := len(t.statics[0])
+	for i, s := range t.statics[1:] {
+		if i == len(t.statics)-2 {
+			if !strings.HasSuffix(doc[pos:], s) {
+				return nil
+			}
+			fills = append(fills, doc[pos:len(doc)-len(s)])
+			break
+		}
+		at := strings.Index(doc[pos:], s)
+		if s == "" || at < 0 {
+			return nil
+		}
+		fills = append(fills, doc[pos:pos+at])
+		pos += at + len(s)
+	}
+	return fills
+}
+
+// prompt asks for the fills of every placeholder, showing the page with
+// markers and, to keep regeneration low-churn, the current text of each
+// placeholder.
+func (t *docTemplate) prompt(previous []string) string {
+	markers := make([]string, len(t.instructions))
+	for i := range markers {
+		markers[i] = fmt.Sprintf("[[FILL %d]]", i+1)
+	}
+	var sb strings.Builder
+	sb.WriteString(templateSystemPrompt)
+	sb.WriteString("Page:\n\n<page>\n")
+	sb.WriteString(t.render(markers))
+	sb.WriteString("\n</page>\n\nPlaceholders:\n")
+	for i, instruction := range t.instructions {
+		fmt.Fprintf(&sb, "\n%d. %s\n", i+1, instruction)
+		if i < len(previous) && strings.TrimSpace(previous[i]) != "" {
+			fmt.Fprintf(&sb, "Current text, to keep word for word unless it is no longer accurate:\n<current>\n%s\n</current>\n", strings.TrimSpace(previous[i]))
+		}
+	}
+	return sb.String()
+}
+
+// parseFills returns the fill of each of n placeholders from an LLM
+// response, trimmed of surrounding blank lines.
+func parseFills(response string, n int) ([]string, error) {
+	fills := make([]string, n)
+	found := make([]bool, n)
+	for _, m := range fillRe.FindAllStringSubmatch(response, -1) {
+		id, _ := strconv.Atoi(m[1])
+		if id < 1 || id > n {
+			continue
+		}
+		fills[id-1] = strings.TrimSpace(m[2])
+		found[id-1] = true
+	}
+	var missing []string
+	for i, ok := range found {
+		if !ok {
+			missing = append(missing, strconv.Itoa(i+1))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("response has no text for placeholder(s) %s", strings.Join(missing, ", "))
+	}
+	return fills, nil
+}
+
+// generateFromTemplate renders the markdown skeleton src: its fixed text is
+// written as is, so it stays byte-stable across runs, and only its
+// {{llm "..."}} placeholders are written by the LLM, in one request. The
+// text the placeholders have in the current doc is offered to the LLM to
+// keep, so a regeneration only changes what needs to change.
+func (g *Generator) generateFromTemplate(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir, src string) error {
+	g.logger.Infof("Generating template: %s", section.Name)
+
+	t, err := parseTemplate(g.expandSnippets(packageDir, src))
+	if err != nil {
+		return err
+	}
+
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	output := t.render(nil)
+	if len(t.instructions) > 0 {
+		var previous []string
+		if existing, err := os.ReadFile(outputPath); err == nil { //nolint:gosec // path from config
+			previous = t.previousFills(string(existing))
+		}
+
+		model := section.Model
+		if model == "" {
+			model = cfg.Settings.Model
+		}
+		genConfig := config.MergeGenerationConfig(cfg.Settings.GenerationConfig, section.GenerationConfig)
+		response, err := g.CallLLM(t.prompt(previous), model, genConfig, packageDir)
+		if err != nil {
+			return fmt.Errorf("LLM generation failed: %w", err)
+		}
+		fills, err := parseFills(response, len(t.instructions))
+		if err != nil {
+			return err
+		}
+		if transformer.IsMDX(section.Output) {
+			// Only the fills are the model's; the template may use JSX
+			for i := range fills {
+				fills[i] = transformer.EscapeMDX(fills[i], componentNames(cfg.ComponentMappings(nil)))
+			}
+		}
+		output = t.render(fills)
+	}
+
+	if transformer.IsMDX(section.Output) {
+		g.checkMDX(section.Name, output)
+	}
+	if err := g.writeOutput(outputPath, output); err != nil {
+		return fmt.Errorf("failed to write section output: %w", err)
+	}
+	g.checkReferences(packageDir, section.Name, outputPath, output)
+	g.logger.Infof("Successfully wrote template section '%s' to %s", section.Name, outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("placeholders", len(t.instructions)).
+		Emit()
+	return nil
+}
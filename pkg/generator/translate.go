@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/manifest"
+)
+
+// Translation outcomes reported in TranslateResult.Status.
+const (
+	TranslationUpdated   = "translated" // Source changed (or --force); translated again
+	TranslationUnchanged = "unchanged"  // Source matches the last translation; skipped
+)
+
+// TranslateOptions configures Translate.
+type TranslateOptions struct {
+	Languages []string // Target languages (BCP 47 tags, e.g. es, ja, pt-BR)
+	Sections  []string // Limit to these sections (by name); empty means all
+	Force     bool     // Retranslate even when the source is unchanged
+}
+
+// TranslateResult reports one translated section.
+type TranslateResult struct {
+	Language string `json:"language"`
+	Section  string `json:"section"`
+	Source   string `json:"source"`
+	Output   string `json:"output"`
+	Status   string `json:"status"`
+}
+
+// translationState is the TranslationsFile of one language directory.
+type translationState struct {
+	Language string            `json:"language"`
+	Sources  map[string]string `json:"sources"` // Section output -> hash of the source it was translated from
+}
+
+const translateSystemPrompt = `You are translating technical documentation for a software project.
+
+Translate the markdown document below into the language with the BCP 47 tag %q.
+
+Rules:
+- Output only the translated markdown document, with no preamble and no surrounding code fence.
+- Keep the markdown structure exactly: the same headings, lists, tables, block quotes and blank lines.
+- Do not translate code blocks, inline code, command names, flags, file paths, URLs, link targets, image paths or HTML tags and attributes.
+- Translate link text and image alt text.
+- Keep YAML frontmatter keys unchanged; translate only the title and description values.
+- Keep product and project names untranslated.
+- Use the terminology a native technical writer would use in software documentation.
+
+Document:
+
+`
+
+// Translate runs the package's generated markdown sections through the LLM
+// for each language and writes the results under {output_dir}/{lang}/. The
+// source hash of every translated section is kept in the language's
+// TranslationsFile, so only sections changed since their last translation are
+// sent again.
+func (g *Generator) Translate(packageDir string, opts TranslateOptions) ([]TranslateResult, error) {
+	if len(opts.Languages) == 0 {
+		return nil, fmt.Errorf("no languages given")
+	}
+	targets, err := ResolveSectionTargets(packageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range opts.Sections {
+		wanted[name] = true
+	}
+	var selected []SectionTarget
+	for _, t := range targets {
+		// Concat sections repeat the others; translate their parts instead.
+		if t.Section.Type == "concat" || !strings.HasSuffix(t.Section.Output, ".md") {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[t.Name] && !wanted[t.Section.Name] {
+			continue
+		}
+		selected = append(selected, t)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no markdown sections to translate")
+	}
+
+	var results []TranslateResult
+	for _, lang := range opts.Languages {
+		states := make(map[string]*translationState)
+		for _, t := range selected {
+			sourcePath := filepath.Join(t.OutputDir, t.Section.Output)
+			source, err := os.ReadFile(sourcePath) //nolint:gosec // path from config
+			if err != nil {
+				if os.IsNotExist(err) {
+					g.logger.Warnf("Skipping section '%s': %s has not been generated", t.Name, sourcePath)
+					continue
+				}
+				return results, fmt.Errorf("failed to read section '%s': %w", t.Name, err)
+			}
+
+			langDir := filepath.Join(t.OutputDir, lang)
+			state, ok := states[langDir]
+			if !ok {
+				state = loadTranslationState(langDir, lang)
+				states[langDir] = state
+			}
+
+			outputPath := filepath.Join(langDir, t.Section.Output)
+			result := TranslateResult{Language: lang, Section: t.Name, Source: sourcePath, Output: outputPath}
+			hash := hashContent(source)
+			if _, err := os.Stat(outputPath); err == nil && !opts.Force && state.Sources[t.Section.Output] == hash {
+				result.Status = TranslationUnchanged
+				results = append(results, result)
+				continue
+			}
+
+			g.logger.Infof("Translating %s into %s", t.Name, lang)
+			translated, err := g.translateSection(packageDir, t, lang, string(source))
+			if err != nil {
+				return results, fmt.Errorf("failed to translate section '%s' into %s: %w", t.Name, lang, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+				return results, fmt.Errorf("failed to create output directory: %w", err)
+			}
+			if err := os.WriteFile(outputPath, []byte(translated), 0o644); err != nil { //nolint:gosec // generated doc
+				return results, fmt.Errorf("failed to write translation: %w", err)
+			}
+
+			// Save after every section so an interrupted run keeps its progress.
+			state.Sources[t.Section.Output] = hash
+			if err := state.save(langDir); err != nil {
+				return results, err
+			}
+			result.Status = TranslationUpdated
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// translateSection asks the LLM for one section's translation, using the
+// section's model and generation settings.
+func (g *Generator) translateSection(packageDir string, t SectionTarget, lang, source string) (string, error) {
+	model := t.Section.Model
+	if model == "" {
+		model = t.Config.Settings.Model
+	}
+	if model == "" {
+		model = "gemini-3-pro-preview"
+	}
+
+	genConfig := config.MergeGenerationConfig(t.Config.Settings.GenerationConfig, t.Section.GenerationConfig)
+	response, err := g.CallLLM(fmt.Sprintf(translateSystemPrompt, lang)+source, model, genConfig, packageDir)
+	if err != nil {
+		return "", fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	// Strip a code fence wrapping the whole document, if the model added one.
+	translated := strings.TrimSpace(response)
+	if strings.HasPrefix(translated, "```") && strings.HasSuffix(translated, "```") {
+		if _, body, ok := strings.Cut(translated, "\n"); ok {
+			translated = strings.TrimSpace(strings.TrimSuffix(body, "```"))
+		}
+	}
+	if translated == "" {
+		return "", fmt.Errorf("LLM returned an empty translation")
+	}
+	return translated + "\n", nil
+}
+
+func loadTranslationState(langDir, lang string) *translationState {
+	state := &translationState{Language: lang, Sources: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(langDir, manifest.TranslationsFile)) //nolint:gosec // output dir from config
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, state); err != nil || state.Sources == nil {
+		return &translationState{Language: lang, Sources: make(map[string]string)}
+	}
+	return state
+}
+
+func (s *translationState) save(langDir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal translation state: %w", err)
+	}
+	if err := os.MkdirAll(langDir, 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(langDir, manifest.TranslationsFile), data, 0o644); err != nil { //nolint:gosec // non-sensitive state file
+		return fmt.Errorf("failed to write translation state: %w", err)
+	}
+	return nil
+}
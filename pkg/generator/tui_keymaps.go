@@ -6,9 +6,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/descriptions"
+	"github.com/grovetools/docgen/pkg/export/pdf"
+	"github.com/grovetools/docgen/pkg/manifest"
 )
 
 // TUIDescriptions holds LLM-generated descriptions for TUIs.
@@ -65,25 +69,85 @@ func parseTUIRegistry(output []byte) ([]TUIRegistryEntry, error) {
 	return registry, nil
 }
 
+// loadTUIRegistry reads the keybinding registry for a section from its
+// registry_file, the output of its registry_cmd, or `grove keys dump` when
+// neither is set. A committed registry file lets CI generate keymap docs
+// without the grove CLI installed.
+func (g *Generator) loadTUIRegistry(packageDir string, section config.SectionConfig) ([]TUIRegistryEntry, error) {
+	var output []byte
+	switch {
+	case section.RegistryFile != "":
+		path := section.RegistryFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(packageDir, path)
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // path from config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TUI registry file: %w", err)
+		}
+		g.logger.Debugf("Loaded TUI registry from %s", path)
+		output = data
+	case section.RegistryCmd != "":
+		cmd := exec.CommandContext(g.runContext(), "sh", "-c", section.RegistryCmd) //nolint:gosec // command from config
+		cmd.Dir = packageDir
+		cmd.Stderr = os.Stderr
+		data, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch TUI registry with %q: %w", section.RegistryCmd, err)
+		}
+		output = data
+	default:
+		// Fetch registry from grove CLI
+		cmd := exec.CommandContext(g.runContext(), "grove", "keys", "dump")
+		data, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch TUI registry (ensure 'grove' is installed and built, or set registry_file): %w\nOutput: %s", err, string(data))
+		}
+		output = data
+	}
+
+	return parseTUIRegistry(output)
+}
+
 // generateFromTUIKeymaps generates markdown documentation from TUI keybinding registry.
 // This is a deterministic generator that doesn't require LLM calls.
 func (g *Generator) generateFromTUIKeymaps(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
 	g.logger.Infof("Generating TUI keymaps: %s", section.Name)
 
-	// Fetch registry from grove CLI
-	cmd := exec.Command("grove", "keys", "dump")
-	output, err := cmd.Output()
+	files, err := g.renderTUIKeymaps(packageDir, section, cfg, outputBaseDir)
 	if err != nil {
-		return fmt.Errorf("failed to fetch TUI registry (ensure 'grove' is installed and built): %w\nOutput: %s", err, string(output))
+		return err
 	}
 
-	registry, err := parseTUIRegistry(output)
-	if err != nil {
-		return err
+	for _, rel := range sortedKeys(files) {
+		outputPath := filepath.Join(outputBaseDir, rel)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		if err := os.WriteFile(outputPath, []byte(files[rel]), 0o644); err != nil {
+			return fmt.Errorf("failed to write TUI keymaps output: %w", err)
+		}
 	}
 
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# %s\n\n", section.Title))
+	g.logger.Infof("Successfully wrote TUI keymaps '%s' to %s (%d files)", section.Name, filepath.Join(outputBaseDir, section.Output), len(files))
+	return nil
+}
+
+// tuiSplitDir returns the directory holding per-TUI pages for split: per_tui,
+// derived from the section output (e.g. "05-keybindings.md" -> "05-keybindings").
+func tuiSplitDir(section config.SectionConfig) string {
+	return strings.TrimSuffix(section.Output, filepath.Ext(section.Output))
+}
+
+// renderTUIKeymaps builds every file for a tui_keymaps section from the current
+// registry without writing them, keyed by path relative to the output dir:
+// the section output, plus per-TUI pages and their manifest for
+// split: per_tui, plus the cheat sheet when cheat_sheet is set.
+func (g *Generator) renderTUIKeymaps(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) (map[string]string, error) {
+	targetTUIs, err := g.selectTUIs(packageDir, section, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build a map of TUI name -> TUIEntry for looking up all config
 	tuiConfigs := make(map[string]config.TUIEntry)
@@ -104,7 +168,64 @@ func (g *Generator) generateFromTUIKeymaps(packageDir string, section config.Sec
 		}
 	}
 
-	// Determine which TUIs to document (preserving order from config)
+	files := make(map[string]string)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", section.Title))
+
+	if len(targetTUIs) == 0 {
+		g.logger.Warnf("No TUIs found matching package %s or specified TUIs list", cfg.Title)
+		sb.WriteString("*No terminal UIs documented for this package yet.*\n")
+	} else if section.Split == "per_tui" {
+		// One page per TUI, with the section output as an index linking them
+		splitDir := tuiSplitDir(section)
+		var pages []manifest.SectionManifest
+		for i, tui := range targetTUIs {
+			var page strings.Builder
+			tuiCfg, hasCfg := tuiConfigs[tui.Name]
+			g.renderTUIEntry(&page, tui, 1, tuiCfg, hasCfg, descriptions)
+			rel := filepath.ToSlash(filepath.Join(splitDir, tui.Name+".md"))
+			files[rel] = page.String()
+			pages = append(pages, manifest.SectionManifest{
+				Name:  tui.Name,
+				Title: tui.Name,
+				Order: section.Order*100 + i + 1,
+				Path:  rel,
+			})
+
+			link := fmt.Sprintf("- [%s](./%s)", tui.Name, rel)
+			if desc := tuiDescription(tui, descriptions); desc != "" {
+				link += " — " + strings.SplitN(desc, "\n", 2)[0]
+			}
+			sb.WriteString(link + "\n")
+		}
+		data, err := json.MarshalIndent(pages, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal TUI page manifest: %w", err)
+		}
+		files[filepath.ToSlash(filepath.Join(splitDir, manifest.SplitPagesFile))] = string(data) + "\n"
+	} else {
+		for _, tui := range targetTUIs {
+			tuiCfg, hasCfg := tuiConfigs[tui.Name]
+			g.renderTUIEntry(&sb, tui, 2, tuiCfg, hasCfg, descriptions)
+		}
+	}
+	files[section.Output] = sb.String()
+
+	if section.CheatSheet != "" {
+		files[section.CheatSheet] = renderTUICheatSheet(section.Title, targetTUIs, section.CheatSheet)
+	}
+
+	return files, nil
+}
+
+// selectTUIs loads the registry and picks the TUIs a section documents: the
+// configured tuis list in order, or every TUI belonging to the package.
+func (g *Generator) selectTUIs(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig) ([]TUIRegistryEntry, error) {
+	registry, err := g.loadTUIRegistry(packageDir, section)
+	if err != nil {
+		return nil, err
+	}
+
 	var targetTUIs []TUIRegistryEntry
 	if len(section.TUIs) > 0 {
 		// Explicit list provided - preserve order from config
@@ -125,140 +246,183 @@ func (g *Generator) generateFromTUIKeymaps(packageDir string, section config.Sec
 			}
 		}
 	}
+	return targetTUIs, nil
+}
 
-	if len(targetTUIs) == 0 {
-		g.logger.Warnf("No TUIs found matching package %s or specified TUIs list", cfg.Title)
-		sb.WriteString("*No terminal UIs documented for this package yet.*\n")
-	} else {
-		for _, tui := range targetTUIs {
-			sb.WriteString(fmt.Sprintf("## %s\n\n", tui.Name))
-
-			// Use rich description if available, otherwise fall back to registry description
-			tuiDesc := tui.Description
-			var sectionDescs map[string]string
-			var capabilities []string
-			if descriptions != nil {
-				if desc, ok := descriptions.TUIs[tui.Name]; ok {
-					if desc.Description != "" {
-						tuiDesc = desc.Description
-					}
-					sectionDescs = desc.Sections
-					capabilities = desc.Capabilities
-				}
+// tuiDescription returns the rich description for a TUI if available,
+// otherwise the registry description.
+func tuiDescription(tui TUIRegistryEntry, descriptions *TUIDescriptions) string {
+	if descriptions != nil {
+		if desc, ok := descriptions.TUIs[tui.Name]; ok && desc.Description != "" {
+			return desc.Description
+		}
+	}
+	return tui.Description
+}
+
+// renderTUIEntry writes one TUI's documentation with its title at the given
+// heading level (2 in the combined page, 1 on its own page).
+func (g *Generator) renderTUIEntry(sb *strings.Builder, tui TUIRegistryEntry, level int, tuiCfg config.TUIEntry, hasCfg bool, descriptions *TUIDescriptions) {
+	h := strings.Repeat("#", level)
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", h, tui.Name))
+
+	// Use rich description if available, otherwise fall back to registry description
+	var sectionDescs map[string]string
+	var capabilities []string
+	if descriptions != nil {
+		if desc, ok := descriptions.TUIs[tui.Name]; ok {
+			sectionDescs = desc.Sections
+			capabilities = desc.Capabilities
+		}
+	}
+	if tuiDesc := tuiDescription(tui, descriptions); tuiDesc != "" {
+		sb.WriteString(fmt.Sprintf("%s\n\n", tuiDesc))
+	}
+
+	// Show command and CLI docs link if specified
+	if hasCfg {
+		if tuiCfg.Command != "" {
+			sb.WriteString(fmt.Sprintf("**Command:** `%s`", tuiCfg.Command))
+			if tuiCfg.CLIDocsURL != "" {
+				sb.WriteString(fmt.Sprintf(" ([CLI Reference](%s))", tuiCfg.CLIDocsURL))
+			}
+			sb.WriteString("\n\n")
+		}
+
+		// Output media (asciinema, video, or screenshot)
+		if tuiCfg.Asciinema != nil {
+			sb.WriteString("```asciinema\n")
+			sb.WriteString("{\n")
+			sb.WriteString(fmt.Sprintf("  \"src\": \"%s\"", tuiCfg.Asciinema.Src))
+			if tuiCfg.Asciinema.Poster != "" {
+				sb.WriteString(fmt.Sprintf(",\n  \"poster\": \"%s\"", tuiCfg.Asciinema.Poster))
 			}
-			if tuiDesc != "" {
-				sb.WriteString(fmt.Sprintf("%s\n\n", tuiDesc))
+			if tuiCfg.Asciinema.AutoPlay {
+				sb.WriteString(",\n  \"autoPlay\": true")
 			}
+			if tuiCfg.Asciinema.Loop {
+				sb.WriteString(",\n  \"loop\": true")
+			}
+			sb.WriteString("\n}\n```\n\n")
+		} else if tuiCfg.Video != "" {
+			sb.WriteString(fmt.Sprintf("![%s](%s#themed)\n\n", tui.Name, tuiCfg.Video))
+		} else if tuiCfg.Screenshot != "" {
+			if tuiCfg.ScreenshotDark != "" {
+				sb.WriteString(fmt.Sprintf("![%s](%s#themed)\n\n", tui.Name, tuiCfg.Screenshot))
+			} else {
+				sb.WriteString(fmt.Sprintf("![%s](%s)\n\n", tui.Name, tuiCfg.Screenshot))
+			}
+		}
+	}
 
-			// Show command and CLI docs link if specified
-			tuiCfg, hasCfg := tuiConfigs[tui.Name]
-			if hasCfg {
-				if tuiCfg.Command != "" {
-					sb.WriteString(fmt.Sprintf("**Command:** `%s`", tuiCfg.Command))
-					if tuiCfg.CLIDocsURL != "" {
-						sb.WriteString(fmt.Sprintf(" ([CLI Reference](%s))", tuiCfg.CLIDocsURL))
-					}
-					sb.WriteString("\n\n")
-				}
+	// Output capabilities list below media
+	if len(capabilities) > 0 {
+		sb.WriteString("**Capabilities:**\n\n")
+		for _, cap := range capabilities {
+			sb.WriteString(fmt.Sprintf("- %s\n", cap))
+		}
+		sb.WriteString("\n")
+	}
 
-				// Output media (asciinema, video, or screenshot)
-				if tuiCfg.Asciinema != nil {
-					sb.WriteString("```asciinema\n")
-					sb.WriteString("{\n")
-					sb.WriteString(fmt.Sprintf("  \"src\": \"%s\"", tuiCfg.Asciinema.Src))
-					if tuiCfg.Asciinema.Poster != "" {
-						sb.WriteString(fmt.Sprintf(",\n  \"poster\": \"%s\"", tuiCfg.Asciinema.Poster))
-					}
-					if tuiCfg.Asciinema.AutoPlay {
-						sb.WriteString(",\n  \"autoPlay\": true")
-					}
-					if tuiCfg.Asciinema.Loop {
-						sb.WriteString(",\n  \"loop\": true")
-					}
-					sb.WriteString("\n}\n```\n\n")
-				} else if tuiCfg.Video != "" {
-					sb.WriteString(fmt.Sprintf("![%s](%s#themed)\n\n", tui.Name, tuiCfg.Video))
-				} else if tuiCfg.Screenshot != "" {
-					if tuiCfg.ScreenshotDark != "" {
-						sb.WriteString(fmt.Sprintf("![%s](%s#themed)\n\n", tui.Name, tuiCfg.Screenshot))
-					} else {
-						sb.WriteString(fmt.Sprintf("![%s](%s)\n\n", tui.Name, tuiCfg.Screenshot))
-					}
-				}
-			}
+	for _, sec := range tui.Sections {
+		if !hasEnabledBindings(sec) {
+			continue
+		}
 
-			// Output capabilities list below media
-			if len(capabilities) > 0 {
-				sb.WriteString("**Capabilities:**\n\n")
-				for _, cap := range capabilities {
-					sb.WriteString(fmt.Sprintf("- %s\n", cap))
-				}
-				sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("%s# %s\n\n", h, sec.Name))
+
+		// Add section description if available
+		if sectionDescs != nil {
+			if secDesc, ok := sectionDescs[sec.Name]; ok && secDesc != "" {
+				sb.WriteString(fmt.Sprintf("%s\n\n", secDesc))
 			}
+		}
 
-			for _, sec := range tui.Sections {
-				// Verify there's at least one enabled binding in this section
-				hasEnabled := false
-				for _, b := range sec.Bindings {
-					if b.Enabled {
-						hasEnabled = true
-						break
-					}
-				}
-				if !hasEnabled {
-					continue
-				}
+		sb.WriteString("| Action | Keybinding |\n")
+		sb.WriteString("| :--- | :--- |\n")
 
-				sb.WriteString(fmt.Sprintf("### %s\n\n", sec.Name))
+		for _, b := range sec.Bindings {
+			if !b.Enabled {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", bindingAction(b), formatBindingKeys(b)))
+		}
+		sb.WriteString("\n")
+	}
 
-				// Add section description if available
-				if sectionDescs != nil {
-					if secDesc, ok := sectionDescs[sec.Name]; ok && secDesc != "" {
-						sb.WriteString(fmt.Sprintf("%s\n\n", secDesc))
-					}
-				}
+	// Append TOML configuration example block
+	sb.WriteString(g.generateTUIConfigExample(tui, level+1))
+}
 
-				sb.WriteString("| Action | Keybinding |\n")
-				sb.WriteString("| :--- | :--- |\n")
-
-				for _, b := range sec.Bindings {
-					if !b.Enabled {
-						continue
-					}
-					var keysFormatted []string
-					for _, k := range b.Keys {
-						keysFormatted = append(keysFormatted, fmt.Sprintf("`%s`", k))
-					}
-					desc := b.Description
-					if desc == "" {
-						desc = b.Name
-					}
-					sb.WriteString(fmt.Sprintf("| %s | %s |\n", desc, strings.Join(keysFormatted, ", ")))
+// renderTUICheatSheet renders a printable, condensed page with every enabled
+// binding of the given TUIs in a single table: a PDF when path ends in .pdf,
+// otherwise markdown.
+func renderTUICheatSheet(title string, tuis []TUIRegistryEntry, path string) string {
+	header := []string{"TUI", "Section", "Action", "Keys"}
+	var rows [][]string
+	for _, tui := range tuis {
+		for _, sec := range tui.Sections {
+			for _, b := range sec.Bindings {
+				if !b.Enabled {
+					continue
 				}
-				sb.WriteString("\n")
+				rows = append(rows, []string{tui.Name, sec.Name, bindingAction(b), formatBindingKeys(b)})
 			}
+		}
+	}
+	title += " Cheat Sheet"
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		return string(pdf.Table{Title: title, Header: header, Rows: rows}.Render())
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", title))
+	sb.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	sb.WriteString("| :--- | :--- | :--- | :--- |\n")
+	for _, row := range rows {
+		sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	return sb.String()
+}
 
-			// Append TOML configuration example block
-			sb.WriteString(g.generateTUIConfigExample(tui))
+// hasEnabledBindings verifies there's at least one enabled binding in a section.
+func hasEnabledBindings(sec SectionEntry) bool {
+	for _, b := range sec.Bindings {
+		if b.Enabled {
+			return true
 		}
 	}
+	return false
+}
 
-	outputPath := filepath.Join(outputBaseDir, section.Output)
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+func bindingAction(b BindingEntry) string {
+	if b.Description != "" {
+		return b.Description
 	}
-	if err := os.WriteFile(outputPath, []byte(sb.String()), 0o644); err != nil {
-		return fmt.Errorf("failed to write TUI keymaps output: %w", err)
+	return b.Name
+}
+
+func formatBindingKeys(b BindingEntry) string {
+	keysFormatted := make([]string, 0, len(b.Keys))
+	for _, k := range b.Keys {
+		keysFormatted = append(keysFormatted, fmt.Sprintf("`%s`", k))
 	}
+	return strings.Join(keysFormatted, ", ")
+}
 
-	g.logger.Infof("Successfully wrote TUI keymaps '%s' to %s", section.Name, outputPath)
-	return nil
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // generateTUIConfigExample generates a copy-pasteable TOML block for a TUI's keybindings.
-func (g *Generator) generateTUIConfigExample(tui TUIRegistryEntry) string {
+func (g *Generator) generateTUIConfigExample(tui TUIRegistryEntry, level int) string {
 	var sb strings.Builder
-	sb.WriteString("### Configuration\n\n")
+	sb.WriteString(strings.Repeat("#", level) + " Configuration\n\n")
 	sb.WriteString("Override these keybindings in `grove.toml`:\n\n")
 	sb.WriteString("```toml\n")
 
@@ -267,14 +431,7 @@ func (g *Generator) generateTUIConfigExample(tui TUIRegistryEntry) string {
 	sb.WriteString(fmt.Sprintf("[tui.keybindings.%s.%s]\n", tui.Package, shortName))
 
 	for _, section := range tui.Sections {
-		hasBindings := false
-		for _, b := range section.Bindings {
-			if b.Enabled {
-				hasBindings = true
-				break
-			}
-		}
-		if !hasBindings {
+		if !hasEnabledBindings(section) {
 			continue
 		}
 
@@ -306,43 +463,38 @@ func (g *Generator) generateTUIConfigExample(tui TUIRegistryEntry) string {
 func (g *Generator) generateTUIDescriptions(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir string) error {
 	g.logger.Infof("Generating TUI descriptions: %s", section.Name)
 
-	// Fetch registry from grove CLI
-	cmd := exec.Command("grove", "keys", "dump")
-	output, err := cmd.Output()
+	// Determine which TUIs to describe
+	targetTUIs, err := g.selectTUIs(packageDir, section, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to fetch TUI registry (ensure 'grove' is installed and built): %w", err)
+		return err
 	}
 
-	registry, err := parseTUIRegistry(output)
+	if len(targetTUIs) == 0 {
+		g.logger.Warnf("No TUIs found for descriptions")
+		return nil
+	}
+
+	// Only TUIs whose registry entry changed since their description was
+	// generated (or that have none yet) are sent to the LLM.
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	store, err := descriptions.Load(outputPath)
 	if err != nil {
 		return err
 	}
-
-	// Determine which TUIs to describe
-	var targetTUIs []TUIRegistryEntry
-	if len(section.TUIs) > 0 {
-		for _, tuiEntry := range section.TUIs {
-			for _, entry := range registry {
-				if entry.Name == tuiEntry.Name {
-					targetTUIs = append(targetTUIs, entry)
-					break
-				}
-			}
-		}
-	} else {
-		// Auto-discover by package name
-		pkgName := strings.ToLower(cfg.Title)
-		for _, entry := range registry {
-			if entry.Package == pkgName {
-				targetTUIs = append(targetTUIs, entry)
-			}
+	hashes := make(map[string]string, len(targetTUIs))
+	var staleTUIs []TUIRegistryEntry
+	for _, tui := range targetTUIs {
+		hashes[tui.Name] = descriptions.Hash(tui)
+		if store.Stale(descriptions.ID(descriptions.KindTUI, tui.Name), hashes[tui.Name]) {
+			staleTUIs = append(staleTUIs, tui)
 		}
 	}
-
-	if len(targetTUIs) == 0 {
-		g.logger.Warnf("No TUIs found for descriptions")
+	if len(staleTUIs) == 0 {
+		g.logger.Infof("TUI descriptions are up to date (%d TUIs)", len(targetTUIs))
 		return nil
 	}
+	g.logger.Infof("Describing %d of %d TUIs (new or changed)", len(staleTUIs), len(targetTUIs))
+	targetTUIs = staleTUIs
 
 	if section.RulesFile != "" {
 		if err := g.BuildContextForRulesSpec(packageDir, section.RulesFile); err != nil {
@@ -416,45 +568,54 @@ Output format (JSON only, no markdown fences):
 	}
 
 	// Parse and validate JSON response
-	var descriptions TUIDescriptions
+	var generated TUIDescriptions
 	cleanResponse := strings.TrimSpace(response)
 	cleanResponse = strings.TrimPrefix(cleanResponse, "```json")
 	cleanResponse = strings.TrimPrefix(cleanResponse, "```")
 	cleanResponse = strings.TrimSuffix(cleanResponse, "```")
 	cleanResponse = strings.TrimSpace(cleanResponse)
 
-	if err := json.Unmarshal([]byte(cleanResponse), &descriptions); err != nil {
+	if err := json.Unmarshal([]byte(cleanResponse), &generated); err != nil {
 		return fmt.Errorf("failed to parse LLM response as JSON: %w\nResponse: %s", err, response)
 	}
 
-	// Write output
-	outputPath := filepath.Join(outputBaseDir, section.Output)
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	jsonBytes, err := json.MarshalIndent(descriptions, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal descriptions: %w", err)
+	for name, desc := range generated.TUIs {
+		hash, ok := hashes[name]
+		if !ok {
+			g.logger.Warnf("Ignoring description for unknown TUI %q", name)
+			continue
+		}
+		store.Put(descriptions.ID(descriptions.KindTUI, name), descriptions.Entry{
+			Description:  desc.Description,
+			Capabilities: desc.Capabilities,
+			Sections:     desc.Sections,
+		}, hash)
 	}
 
-	if err := os.WriteFile(outputPath, jsonBytes, 0o644); err != nil {
-		return fmt.Errorf("failed to write descriptions file: %w", err)
+	if err := store.Save(); err != nil {
+		return err
 	}
 
-	g.logger.Infof("Successfully wrote TUI descriptions for %d TUIs to %s", len(descriptions.TUIs), outputPath)
+	g.logger.Infof("Successfully wrote TUI descriptions for %d TUIs to %s", len(generated.TUIs), outputPath)
 	return nil
 }
 
-// loadTUIDescriptions loads TUI descriptions from a JSON file.
+// loadTUIDescriptions loads TUI descriptions from a descriptions store.
 func loadTUIDescriptions(path string) (*TUIDescriptions, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
+	if _, err := os.Stat(path); err != nil {
 		return nil, err
 	}
-	var descriptions TUIDescriptions
-	if err := json.Unmarshal(data, &descriptions); err != nil {
+	store, err := descriptions.Load(path)
+	if err != nil {
 		return nil, err
 	}
-	return &descriptions, nil
+	tuis := make(map[string]TUIDescription)
+	for name, entry := range store.Kind(descriptions.KindTUI) {
+		tuis[name] = TUIDescription{
+			Description:  entry.Description,
+			Capabilities: entry.Capabilities,
+			Sections:     entry.Sections,
+		}
+	}
+	return &TUIDescriptions{TUIs: tuis}, nil
 }
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/internal/fsutil"
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+const tutorialSystemPrompt = `You are writing a step-by-step tutorial for a software project's documentation.
+
+The scenario to walk through is described below. Write the tutorial as markdown:
+- Start with a "# " title and a short introduction saying what the reader will build or learn.
+- Put every command the reader runs in a fenced code block with the language "bash". The blocks run in order, each in a fresh shell started in the same empty working directory, so each block must be self-contained (repeat any cd) and must work non-interactively.
+- Do not write the output of commands; the real output is captured when the tutorial is verified and inserted after each block.
+- Only use commands that exist in the project context you have been given. Files the reader creates must be created by commands in the tutorial (e.g. with cat <<'EOF').
+- Use fenced blocks with other languages (yaml, toml, text) for content that is shown but not run.
+
+Scenario:
+
+`
+
+var tutorialFenceRe = regexp.MustCompile("^(```+|~~~+)\\s*([\\w+-]*)")
+
+// tutorialShells are the fence languages whose blocks are run.
+var tutorialShells = map[string]bool{"bash": true, "sh": true, "shell": true}
+
+// tutorialStep is one runnable block of a tutorial.
+type tutorialStep struct {
+	end    int // Index of the closing fence line
+	script string
+}
+
+// tutorialSteps returns the runnable shell blocks of a markdown document.
+func tutorialSteps(lines []string) []tutorialStep {
+	var steps []tutorialStep
+	for i := 0; i < len(lines); i++ {
+		m := tutorialFenceRe.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), m[1]) {
+			end++
+		}
+		if tutorialShells[strings.ToLower(m[2])] && end < len(lines) {
+			steps = append(steps, tutorialStep{end: end, script: strings.Join(lines[i+1:end], "\n")})
+		}
+		i = end
+	}
+	return steps
+}
+
+// runTutorialStep runs one block with sh -e in dir, through runner when it
+// is set, bounded by parent and timeout.
+func runTutorialStep(parent context.Context, script, dir string, env, runner []string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	args := append(append([]string{}, runner...), "sh", "-e", "-c", script)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec // tutorial commands are the point
+	cmd.Dir = dir
+	cmd.Env = env
+	// Don't wait on background processes still holding the output pipe.
+	cmd.WaitDelay = time.Second
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if perr := parent.Err(); perr != nil {
+		return out.String(), fmt.Errorf("generation cancelled: %w", perr)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return out.String(), fmt.Errorf("timed out after %s", timeout)
+	}
+	return out.String(), err
+}
+
+// generateFromTutorial asks the LLM for a tutorial of the scenario, then
+// verifies it: every bash block runs in order in a temporary sandbox directory
+// with the section's env, and its real output is inserted below it. Any
+// failing command fails the section and the previous doc is kept, so
+// published tutorials are known to work. The blocks are LLM-written commands
+// run as the user, so nothing is requested or run unless
+// settings.tutorial.allow_exec is set.
+func (g *Generator) generateFromTutorial(packageDir string, section config.SectionConfig, cfg *config.DocgenConfig, outputBaseDir, scenario string) error {
+	g.logger.Infof("Generating tutorial: %s", section.Name)
+
+	tutorial := cfg.Settings.Tutorial
+	if tutorial == nil || !tutorial.AllowExec {
+		return fmt.Errorf("tutorial sections run the commands the LLM writes as you, with your files and network; set settings.tutorial.allow_exec to allow it, with settings.tutorial.runner to run them in a container")
+	}
+
+	timeout := 2 * time.Minute
+	if section.Timeout != "" {
+		d, err := time.ParseDuration(section.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", section.Timeout, err)
+		}
+		timeout = d
+	}
+
+	model := section.Model
+	if model == "" {
+		model = cfg.Settings.Model
+	}
+	if model == "" {
+		model = "gemini-3-pro-preview"
+	}
+
+	genConfig := config.MergeGenerationConfig(cfg.Settings.GenerationConfig, section.GenerationConfig)
+	output, err := g.CallLLM(tutorialSystemPrompt+scenario, model, genConfig, packageDir)
+	if err != nil {
+		return fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	steps := tutorialSteps(lines)
+	if len(steps) == 0 {
+		return fmt.Errorf("tutorial has no bash blocks to verify")
+	}
+
+	sandbox, err := os.MkdirTemp("", "docgen-tutorial-")
+	if err != nil {
+		return fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	defer os.RemoveAll(sandbox) //nolint:errcheck // best-effort temp cleanup
+
+	env := tutorialEnv(sandbox, section.Env)
+	runner := make([]string, len(tutorial.Runner))
+	for i, arg := range tutorial.Runner {
+		runner[i] = os.Expand(arg, tutorialExpand(sandbox))
+	}
+
+	// Run every step, then splice the outputs in from the end so earlier
+	// line indexes stay valid.
+	outputs := make([]string, len(steps))
+	for i, step := range steps {
+		g.logger.Infof("Running tutorial step %d/%d", i+1, len(steps))
+		out, err := runTutorialStep(g.runContext(), step.script, sandbox, env, runner, timeout)
+		// Tutorials must not leak the sandbox path into the docs.
+		out = strings.ReplaceAll(strings.TrimRight(out, "\n"), sandbox, ".")
+		if err != nil {
+			return fmt.Errorf("tutorial step %d failed: %w\n--- command ---\n%s\n--- output ---\n%s", i+1, err, step.script, out)
+		}
+		outputs[i] = out
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		if outputs[i] == "" {
+			continue
+		}
+		block := []string{"", "```text", outputs[i], "```"}
+		at := steps[i].end + 1
+		lines = append(lines[:at], append(block, lines[at:]...)...)
+	}
+
+	outputPath := filepath.Join(outputBaseDir, section.Output)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(outputPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write tutorial: %w", err)
+	}
+
+	g.logger.Infof("Successfully verified %d tutorial steps in %s", len(steps), outputPath)
+	g.ulog.Success("Wrote section").
+		Field("section", section.Name).
+		Field("path", outputPath).
+		Field("steps", len(steps)).
+		Emit()
+	return nil
+}
+
+// tutorialEnv returns the environment of the commands of a tutorial run in
+// sandbox: PATH, with HOME and TMPDIR in the sandbox, and the section's env.
+// Generated commands get nothing else, so they cannot read the provider keys
+// or other secrets of the process from its environment. This is no
+// isolation: they can still read any file the user can.
+func tutorialEnv(sandbox string, sectionEnv map[string]string) []string {
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + sandbox,
+		"TMPDIR=" + sandbox,
+		"DOCGEN_TUTORIAL_DIR=" + sandbox,
+	}
+	keys := make([]string, 0, len(sectionEnv))
+	for k := range sectionEnv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+os.Expand(sectionEnv[k], tutorialExpand(sandbox)))
+	}
+	return env
+}
+
+// tutorialExpand expands $DOCGEN_TUTORIAL_DIR to sandbox and other
+// variables to the values docgen runs with.
+func tutorialExpand(sandbox string) func(string) string {
+	return func(name string) string {
+		if name == "DOCGEN_TUTORIAL_DIR" {
+			return sandbox
+		}
+		return os.Getenv(name)
+	}
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+func TestTutorialEnv(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-secret")
+	t.Setenv("GOPATH", "/go")
+
+	got := tutorialEnv("/tmp/sandbox", map[string]string{
+		"GROVE_HOME": "$DOCGEN_TUTORIAL_DIR/.grove",
+		"GOPATH":     "$GOPATH",
+		"LANG":       "C",
+	})
+	want := []string{
+		"PATH=/usr/bin",
+		"HOME=/tmp/sandbox",
+		"TMPDIR=/tmp/sandbox",
+		"DOCGEN_TUTORIAL_DIR=/tmp/sandbox",
+		"GOPATH=/go",
+		"GROVE_HOME=/tmp/sandbox/.grove",
+		"LANG=C",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tutorialEnv() = %q, want %q", got, want)
+	}
+}
+
+func TestRunTutorialStep(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		name   string
+		script string
+		runner []string
+		want   string
+		fails  bool
+	}{
+		{"direct", "echo one\necho two", nil, "one\ntwo\n", false},
+		{"stops at the first failure", "false\necho unreachable", nil, "", true},
+		{"through a runner", "echo $RUNNER_DIR", []string{"env", "RUNNER_DIR=" + dir}, dir + "\n", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := runTutorialStep(context.Background(), c.script, dir, tutorialEnv(dir, nil), c.runner, time.Minute)
+			if (err != nil) != c.fails || got != c.want {
+				t.Errorf("runTutorialStep() = %q, %v; want %q, failure %v", got, err, c.want, c.fails)
+			}
+		})
+	}
+}
+
+func TestGenerateFromTutorialNeedsAllowExec(t *testing.T) {
+	for _, tutorial := range []*config.TutorialConfig{nil, {Runner: []string{"docker", "run"}}} {
+		cfg := &config.DocgenConfig{Settings: config.SettingsConfig{Model: "gpt-5", Tutorial: tutorial}}
+		section := config.SectionConfig{Name: "quickstart", Type: "tutorial", Output: "quickstart.md"}
+		err := newTestGenerator().generateFromTutorial(t.TempDir(), section, cfg, t.TempDir(), "Install and run")
+		if err == nil || !strings.Contains(err.Error(), "settings.tutorial.allow_exec") {
+			t.Errorf("tutorial %+v: err = %v, want the opt-in asked for", tutorial, err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Package github is a minimal GitHub API client for the docs sources docgen
+// reads from a repository (issues, discussions and avatars).
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the GitHub REST API root; GraphQL lives at {base}/graphql.
+const DefaultBaseURL = "https://api.github.com"
+
+// AvatarBaseURL serves user avatars at {base}/{login}.png.
+const AvatarBaseURL = "https://github.com"
+
+// Client calls the GitHub API. Requests are authenticated when a token is set.
+type Client struct {
+	BaseURL string
+	Token   string
+	http    *http.Client
+}
+
+// NewClient creates a client using token, or GITHUB_TOKEN / GH_TOKEN from the
+// environment when token is empty.
+func NewClient(token string) *Client {
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	return &Client{
+		BaseURL: DefaultBaseURL,
+		Token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Issue is a closed issue or answered discussion and its thread.
+type Issue struct {
+	Number   int      `json:"number"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	URL      string   `json:"url"`
+	Labels   []string `json:"labels,omitempty"`
+	Comments []string `json:"comments,omitempty"`
+	Answer   string   `json:"answer,omitempty"` // Accepted answer (discussions)
+	Kind     string   `json:"kind"`             // "issue" or "discussion"
+}
+
+var remoteRe = regexp.MustCompile(`github\.com[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// RepoFromRemote extracts "owner/name" from a GitHub remote URL (SSH or HTTPS).
+func RepoFromRemote(remote string) (string, error) {
+	m := remoteRe.FindStringSubmatch(strings.TrimSpace(remote))
+	if m == nil {
+		return "", fmt.Errorf("%q is not a GitHub remote", remote)
+	}
+	return m[1] + "/" + m[2], nil
+}
+
+// ClosedIssues returns up to limit closed issues of repo ("owner/name")
+// carrying all of labels, newest first, with their comments. Pull requests
+// are skipped.
+func (c *Client) ClosedIssues(repo string, labels []string, limit int) ([]Issue, error) {
+	var issues []Issue
+	for page := 1; len(issues) < limit; page++ {
+		q := url.Values{}
+		q.Set("state", "closed")
+		q.Set("per_page", "100")
+		q.Set("page", fmt.Sprint(page))
+		if len(labels) > 0 {
+			q.Set("labels", strings.Join(labels, ","))
+		}
+		var batch []struct {
+			Number      int    `json:"number"`
+			Title       string `json:"title"`
+			Body        string `json:"body"`
+			HTMLURL     string `json:"html_url"`
+			Comments    int    `json:"comments"`
+			PullRequest *struct {
+				URL string `json:"url"`
+			} `json:"pull_request"`
+			Labels []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+		}
+		if err := c.get(fmt.Sprintf("/repos/%s/issues?%s", repo, q.Encode()), &batch); err != nil {
+			return nil, err
+		}
+		for _, b := range batch {
+			if b.PullRequest != nil || len(issues) >= limit {
+				continue
+			}
+			issue := Issue{Number: b.Number, Title: b.Title, Body: b.Body, URL: b.HTMLURL, Kind: "issue"}
+			for _, l := range b.Labels {
+				issue.Labels = append(issue.Labels, l.Name)
+			}
+			if b.Comments > 0 {
+				var comments []struct {
+					Body string `json:"body"`
+				}
+				if err := c.get(fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100", repo, b.Number), &comments); err != nil {
+					return nil, err
+				}
+				for _, cm := range comments {
+					issue.Comments = append(issue.Comments, cm.Body)
+				}
+			}
+			issues = append(issues, issue)
+		}
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return issues, nil
+}
+
+const discussionsQuery = `query($owner: String!, $name: String!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    discussions(first: 50, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number title body url isAnswered
+        answer { body }
+        labels(first: 20) { nodes { name } }
+        comments(first: 20) { nodes { body } }
+      }
+    }
+  }
+}`
+
+// AnsweredDiscussions returns up to limit answered discussions of repo
+// carrying all of labels. The GraphQL API requires a token.
+func (c *Client) AnsweredDiscussions(repo string, labels []string, limit int) ([]Issue, error) {
+	if c.Token == "" {
+		return nil, fmt.Errorf("reading discussions requires a GitHub token (set GITHUB_TOKEN)")
+	}
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository %q (expected owner/name)", repo)
+	}
+
+	var issues []Issue
+	var cursor *string
+	for len(issues) < limit {
+		var resp struct {
+			Data struct {
+				Repository struct {
+					Discussions struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							Number     int    `json:"number"`
+							Title      string `json:"title"`
+							Body       string `json:"body"`
+							URL        string `json:"url"`
+							IsAnswered bool   `json:"isAnswered"`
+							Answer     *struct {
+								Body string `json:"body"`
+							} `json:"answer"`
+							Labels struct {
+								Nodes []struct {
+									Name string `json:"name"`
+								} `json:"nodes"`
+							} `json:"labels"`
+							Comments struct {
+								Nodes []struct {
+									Body string `json:"body"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"discussions"`
+				} `json:"repository"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		vars := map[string]interface{}{"owner": owner, "name": name, "cursor": cursor}
+		if err := c.post("/graphql", map[string]interface{}{"query": discussionsQuery, "variables": vars}, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("GitHub GraphQL error: %s", resp.Errors[0].Message)
+		}
+
+		d := resp.Data.Repository.Discussions
+		for _, n := range d.Nodes {
+			if !n.IsAnswered || len(issues) >= limit {
+				continue
+			}
+			issue := Issue{Number: n.Number, Title: n.Title, Body: n.Body, URL: n.URL, Kind: "discussion"}
+			for _, l := range n.Labels.Nodes {
+				issue.Labels = append(issue.Labels, l.Name)
+			}
+			if !hasLabels(issue.Labels, labels) {
+				continue
+			}
+			if n.Answer != nil {
+				issue.Answer = n.Answer.Body
+			}
+			for _, cm := range n.Comments.Nodes {
+				issue.Comments = append(issue.Comments, cm.Body)
+			}
+			issues = append(issues, issue)
+		}
+		if !d.PageInfo.HasNextPage {
+			break
+		}
+		next := d.PageInfo.EndCursor
+		cursor = &next
+	}
+	return issues, nil
+}
+
+// Avatar returns the PNG avatar of a GitHub user, size pixels square.
+func (c *Client) Avatar(login string, size int) ([]byte, error) {
+	u := fmt.Sprintf("%s/%s.png?size=%d", AvatarBaseURL, url.PathEscape(login), size)
+	resp, err := c.http.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch avatar of %s: %w", login, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only response body
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar of %s: %w", login, err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("avatar of %s: %s", login, resp.Status)
+	}
+	return data, nil
+}
+
+// hasLabels reports whether have contains every label in want, ignoring case.
+func hasLabels(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) get(path string, out interface{}) error {
+	return c.do(http.MethodGet, path, nil, out)
+}
+
+func (c *Client) post(path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode GitHub request: %w", err)
+	}
+	return c.do(http.MethodPost, path, bytes.NewReader(data), out)
+}
+
+func (c *Client) do(method, path string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.BaseURL, "/")+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only response body
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read GitHub response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("GitHub API %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	return nil
+}
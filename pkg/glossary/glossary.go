@@ -0,0 +1,174 @@
+// Package glossary implements the shared terminology glossary: a YAML file of
+// domain terms that generation injects into prompts and lint checks docs
+// against.
+package glossary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the glossary file name looked up from a package upwards.
+const DefaultFile = "glossary.yml"
+
+// Term is one glossary entry.
+type Term struct {
+	Term       string   `yaml:"term"`
+	Definition string   `yaml:"definition"`
+	Aliases    []string `yaml:"aliases,omitempty"`  // Accepted alternative spellings
+	Avoid      []string `yaml:"avoid,omitempty"`    // Conflicting names that should be replaced by Term
+	Packages   []string `yaml:"packages,omitempty"` // Packages whose docs use the term
+}
+
+// Glossary is the set of terms persisted in glossary.yml.
+type Glossary struct {
+	Terms []Term `yaml:"terms"`
+}
+
+// Load reads a glossary file.
+func Load(path string) (*Glossary, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // glossary path from config or flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glossary: %w", err)
+	}
+	var g Glossary
+	if err := yaml.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse glossary %s: %w", path, err)
+	}
+	return &g, nil
+}
+
+// Save writes the glossary to path with terms sorted alphabetically.
+func (g *Glossary) Save(path string) error {
+	g.sort()
+	data, err := yaml.Marshal(g)
+	if err != nil {
+		return fmt.Errorf("failed to marshal glossary: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // internal doc tool
+		return fmt.Errorf("failed to create glossary directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil { //nolint:gosec // non-sensitive glossary
+		return fmt.Errorf("failed to write glossary: %w", err)
+	}
+	return nil
+}
+
+// Find returns the glossary for a package: path when set (relative to
+// packageDir), otherwise the nearest glossary.yml in packageDir or one of its
+// parents. It returns "" when there is none.
+func Find(packageDir, path string) string {
+	if path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(packageDir, path)
+		}
+		return path
+	}
+	for dir := packageDir; ; dir = filepath.Dir(dir) {
+		candidate := filepath.Join(dir, DefaultFile)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// Lookup returns the term whose name or alias matches name, ignoring case.
+func (g *Glossary) Lookup(name string) (*Term, bool) {
+	for i := range g.Terms {
+		t := &g.Terms[i]
+		if strings.EqualFold(t.Term, name) {
+			return t, true
+		}
+		for _, alias := range t.Aliases {
+			if strings.EqualFold(alias, name) {
+				return t, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Merge adds terms to the glossary. A term already present keeps its
+// definition, since glossary.yml may have been edited by hand; its aliases,
+// avoided names and packages are extended.
+func (g *Glossary) Merge(terms ...Term) {
+	for _, t := range terms {
+		t.Term = strings.TrimSpace(t.Term)
+		if t.Term == "" {
+			continue
+		}
+		existing, ok := g.Lookup(t.Term)
+		if !ok {
+			g.Terms = append(g.Terms, t)
+			continue
+		}
+		if existing.Definition == "" {
+			existing.Definition = t.Definition
+		}
+		if !strings.EqualFold(existing.Term, t.Term) {
+			t.Aliases = append(t.Aliases, t.Term)
+		}
+		existing.Aliases = union(existing.Aliases, t.Aliases, existing.Term)
+		existing.Avoid = union(existing.Avoid, t.Avoid, existing.Term)
+		existing.Packages = union(existing.Packages, t.Packages, "")
+	}
+	g.sort()
+}
+
+// Prompt renders the glossary as a prompt section instructing the model to
+// use the defined terms consistently.
+func (g *Glossary) Prompt() string {
+	if len(g.Terms) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("<glossary>\n")
+	sb.WriteString("Use these terms exactly as defined and spelled below. Do not use the names listed under \"avoid\"; use the term instead.\n\n")
+	for _, t := range g.Terms {
+		sb.WriteString("- " + t.Term)
+		if t.Definition != "" {
+			sb.WriteString(": " + t.Definition)
+		}
+		if len(t.Aliases) > 0 {
+			fmt.Fprintf(&sb, " (also: %s)", strings.Join(t.Aliases, ", "))
+		}
+		if len(t.Avoid) > 0 {
+			fmt.Fprintf(&sb, " (avoid: %s)", strings.Join(t.Avoid, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</glossary>\n")
+	return sb.String()
+}
+
+func (g *Glossary) sort() {
+	sort.SliceStable(g.Terms, func(i, j int) bool {
+		return strings.ToLower(g.Terms[i].Term) < strings.ToLower(g.Terms[j].Term)
+	})
+}
+
+// union appends the values of b missing from a (ignoring case), leaving out
+// skip, and returns the sorted result.
+func union(a, b []string, skip string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, v := range append(append([]string{}, a...), b...) {
+		v = strings.TrimSpace(v)
+		key := strings.ToLower(v)
+		if v == "" || seen[key] || (skip != "" && strings.EqualFold(v, skip)) {
+			continue
+		}
+		seen[key] = true
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,178 @@
+package glossary
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Lint rules reported in Issue.Rule.
+const (
+	RuleConflicting = "conflicting-term" // A name the glossary lists under avoid is used
+	RuleUndefined   = "undefined-term"   // A term is introduced in bold but is not in the glossary
+)
+
+// Issue is one lint finding.
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Term    string `json:"term"`
+	Message string `json:"message"`
+}
+
+var (
+	lintFenceRe    = regexp.MustCompile("^\\s*(```|~~~)")
+	lintCodeSpanRe = regexp.MustCompile("`[^`]*`")
+	lintLinkDestRe = regexp.MustCompile(`\]\([^)]*\)`)
+	lintBoldRe     = regexp.MustCompile(`\*\*([^*\n]+)\*\*|__([^_\n]+)__`)
+)
+
+// calloutWords are bold labels that introduce notes rather than terms.
+var calloutWords = map[string]bool{
+	"note": true, "notes": true, "warning": true, "tip": true, "important": true,
+	"example": true, "examples": true, "caution": true, "info": true, "danger": true,
+	"deprecated": true, "required": true, "optional": true, "default": true,
+}
+
+type avoidPattern struct {
+	term string
+	re   *regexp.Regexp
+}
+
+// Linter checks markdown against a glossary.
+type Linter struct {
+	avoid   []avoidPattern
+	allowed []*regexp.Regexp // Terms and aliases; avoided names inside them are not flagged
+	g       *Glossary
+}
+
+// NewLinter compiles the glossary's terms for linting.
+func NewLinter(g *Glossary) *Linter {
+	l := &Linter{g: g}
+	for _, t := range g.Terms {
+		for _, name := range append([]string{t.Term}, t.Aliases...) {
+			l.allowed = append(l.allowed, wordRegexp(name))
+		}
+		for _, name := range t.Avoid {
+			l.avoid = append(l.avoid, avoidPattern{term: t.Term, re: wordRegexp(name)})
+		}
+	}
+	return l
+}
+
+// wordRegexp matches name case-insensitively as whole words.
+func wordRegexp(name string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(name)
+	if r := []rune(name); len(r) > 0 && isWordRune(r[0]) {
+		pattern = `\b` + pattern
+	}
+	if r := []rune(name); len(r) > 0 && isWordRune(r[len(r)-1]) {
+		pattern += `\b`
+	}
+	return regexp.MustCompile(`(?i)` + pattern)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Lint reports the glossary issues in one markdown file. Frontmatter, code
+// blocks, inline code and link targets are ignored. Each undefined term is
+// reported once per file.
+func (l *Linter) Lint(file, content string) []Issue {
+	var issues []Issue
+	reported := make(map[string]bool)
+
+	lines := strings.Split(content, "\n")
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	inFence := false
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if lintFenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = blank(line, lintCodeSpanRe)
+		line = blank(line, lintLinkDestRe)
+
+		masked := line
+		for _, re := range l.allowed {
+			masked = blank(masked, re)
+		}
+		for _, a := range l.avoid {
+			for _, m := range a.re.FindAllString(masked, -1) {
+				issues = append(issues, Issue{
+					File:    file,
+					Line:    i + 1,
+					Rule:    RuleConflicting,
+					Term:    a.term,
+					Message: fmt.Sprintf("use %q instead of %q", a.term, m),
+				})
+			}
+		}
+
+		for _, m := range lintBoldRe.FindAllStringSubmatch(line, -1) {
+			text := strings.TrimSpace(m[1] + m[2])
+			if !looksLikeTerm(text) || reported[strings.ToLower(text)] {
+				continue
+			}
+			if _, ok := l.g.Lookup(text); ok || l.avoided(text) {
+				continue
+			}
+			reported[strings.ToLower(text)] = true
+			issues = append(issues, Issue{
+				File:    file,
+				Line:    i + 1,
+				Rule:    RuleUndefined,
+				Term:    text,
+				Message: fmt.Sprintf("%q is not defined in the glossary", text),
+			})
+		}
+	}
+	return issues
+}
+
+// avoided reports whether text is a name the glossary lists under avoid;
+// those are reported as conflicting instead.
+func (l *Linter) avoided(text string) bool {
+	for _, a := range l.avoid {
+		if loc := a.re.FindStringIndex(text); loc != nil && loc[0] == 0 && loc[1] == len(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeTerm reports whether bold text reads like an introduced term: one
+// to four words starting with a letter, not a sentence or a callout label.
+func looksLikeTerm(text string) bool {
+	words := strings.Fields(text)
+	if len(words) == 0 || len(words) > 4 {
+		return false
+	}
+	if r := []rune(text); !unicode.IsLetter(r[0]) || strings.ContainsAny(string(r[len(r)-1]), ":.!?,;") {
+		return false
+	}
+	return !calloutWords[strings.ToLower(text)]
+}
+
+// blank replaces every match of re in s with spaces, keeping offsets intact.
+func blank(s string, re *regexp.Regexp) string {
+	return re.ReplaceAllStringFunc(s, func(m string) string {
+		return strings.Repeat(" ", len(m))
+	})
+}
@@ -0,0 +1,302 @@
+// Package jobs is docgen's built-in local job queue. generate --enqueue
+// records the sections to generate as a job in a state file and hands it to
+// a detached worker process, so a long regeneration survives the terminal
+// that started it. The worker updates the state file as each section starts
+// and finishes; jobs status and jobs attach read it and the worker's log.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/internal/fsutil"
+)
+
+// Job statuses. A running job whose worker is gone is reported as
+// interrupted.
+const (
+	StatusQueued      = "queued"
+	StatusRunning     = "running"
+	StatusDone        = "done"
+	StatusFailed      = "failed"
+	StatusInterrupted = "interrupted"
+)
+
+// Task statuses.
+const (
+	TaskPending = "pending"
+	TaskRunning = "running"
+	TaskOK      = "ok"
+	TaskFailed  = "failed"
+	TaskSkipped = "skipped"
+)
+
+// ErrNotFound is returned by Load for an unknown job ID.
+var ErrNotFound = errors.New("job not found")
+
+// Task is one section of a job.
+type Task struct {
+	Package    string     `json:"package"` // Package name
+	Dir        string     `json:"dir"`     // Package directory generate runs in
+	Section    string     `json:"section"` // As generate --section accepts it
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Options are the generate flags a job's sections run with.
+type Options struct {
+	Model    string `json:"model,omitempty"`
+	CacheTTL string `json:"cache_ttl,omitempty"`
+	FailFast bool   `json:"fail_fast,omitempty"`
+	Strict   bool   `json:"strict,omitempty"`
+	Isolate  bool   `json:"isolate,omitempty"`
+}
+
+// Job is a queued generation run and its progress.
+type Job struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	PID        int        `json:"pid,omitempty"` // The worker's process
+	Log        string     `json:"log"`           // The worker's output
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Options    Options    `json:"options"`
+	Tasks      []Task     `json:"tasks"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Dir returns the directory job state files and logs are kept in:
+// $DOCGEN_JOBS_DIR, or docgen/jobs in the user's cache directory.
+func Dir() (string, error) {
+	if dir := os.Getenv("DOCGEN_JOBS_DIR"); dir != "" {
+		return dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no cache directory for the job queue: %w", err)
+	}
+	return filepath.Join(cacheDir, "docgen", "jobs"), nil
+}
+
+// New creates a queued job for tasks and saves it.
+func New(tasks []Task, opts Options) (*Job, error) {
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no sections to enqueue")
+	}
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // user cache directory
+		return nil, fmt.Errorf("failed to create job directory: %w", err)
+	}
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	id := now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+	job := &Job{
+		ID:        id,
+		Status:    StatusQueued,
+		Log:       filepath.Join(dir, id+".log"),
+		CreatedAt: now,
+		Options:   opts,
+		Tasks:     tasks,
+	}
+	for i := range job.Tasks {
+		job.Tasks[i].Status = TaskPending
+	}
+	return job, job.Save()
+}
+
+// Save writes the job's state file atomically, so readers never see half
+// of it.
+func (j *Job) Save() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %s: %w", j.ID, err)
+	}
+	return fsutil.WriteFileAtomic(filepath.Join(dir, j.ID+".json"), append(data, '\n'), 0o644)
+}
+
+// Load reads the job with the given ID. A unique prefix of an ID is
+// accepted.
+func Load(id string) (*Job, error) {
+	all, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var matches []*Job
+	for _, j := range all {
+		if j.ID == id {
+			return j, nil
+		}
+		if strings.HasPrefix(j.ID, id) {
+			matches = append(matches, j)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("job ID %s is ambiguous: %d jobs match", id, len(matches))
+}
+
+// List returns every job, newest first.
+func List() ([]*Job, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*Job
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // job state file
+		if err != nil {
+			continue
+		}
+		var j Job
+		if err := json.Unmarshal(data, &j); err != nil {
+			continue
+		}
+		jobs = append(jobs, &j)
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].CreatedAt.After(jobs[b].CreatedAt) })
+	return jobs, nil
+}
+
+// State returns the job's status, reporting a queued or running job whose
+// worker is no longer alive as interrupted.
+func (j *Job) State() string {
+	if (j.Status == StatusRunning || j.Status == StatusQueued) && j.PID != 0 && !processAlive(j.PID) {
+		return StatusInterrupted
+	}
+	return j.Status
+}
+
+// Finished reports whether the job will make no more progress.
+func (j *Job) Finished() bool {
+	switch j.State() {
+	case StatusDone, StatusFailed, StatusInterrupted:
+		return true
+	}
+	return false
+}
+
+// Counts returns how many of the job's tasks have each status.
+func (j *Job) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, t := range j.Tasks {
+		counts[t.Status]++
+	}
+	return counts
+}
+
+// Start launches a detached worker running "<executable> jobs run <id>",
+// with its output going to the job's log. The worker outlives the calling
+// process and its terminal.
+func (j *Job) Start() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot find the docgen executable: %w", err)
+	}
+	logFile, err := os.OpenFile(j.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gosec // job log
+	if err != nil {
+		return fmt.Errorf("failed to open job log: %w", err)
+	}
+	defer logFile.Close() //nolint:errcheck // the worker holds its own handle
+
+	cmd := exec.Command(exe, "jobs", "run", j.ID) //nolint:gosec // re-runs docgen itself
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = os.Environ()
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start job worker: %w", err)
+	}
+	// The worker records its own PID when it starts; saving here could
+	// overwrite its first progress update
+	j.PID = cmd.Process.Pid
+	return cmd.Process.Release()
+}
+
+// Run works through the job's tasks in order with generate, saving the
+// job's state as each starts and ends. Tasks that already succeeded, in a
+// job run before, are not run again. With FailFast, the tasks after a
+// failure are skipped. Run returns an error when any task failed.
+func (j *Job) Run(ctx context.Context, generate func(ctx context.Context, t Task) error) error {
+	now := time.Now()
+	j.Status, j.PID, j.StartedAt, j.FinishedAt, j.Error = StatusRunning, os.Getpid(), &now, nil, ""
+	if err := j.Save(); err != nil {
+		return err
+	}
+
+	var failed []string
+	for i := range j.Tasks {
+		t := &j.Tasks[i]
+		if t.Status == TaskOK {
+			continue
+		}
+		if ctx.Err() != nil || (j.Options.FailFast && len(failed) > 0) {
+			t.Status, t.Error = TaskSkipped, ""
+			continue
+		}
+		started := time.Now()
+		t.Status, t.Error, t.StartedAt, t.FinishedAt = TaskRunning, "", &started, nil
+		if err := j.Save(); err != nil {
+			return err
+		}
+		err := generate(ctx, *t)
+		finished := time.Now()
+		t.FinishedAt = &finished
+		if err != nil {
+			t.Status, t.Error = TaskFailed, err.Error()
+			failed = append(failed, t.Package+"/"+t.Section)
+		} else {
+			t.Status = TaskOK
+		}
+		if err := j.Save(); err != nil {
+			return err
+		}
+	}
+
+	finished := time.Now()
+	j.FinishedAt = &finished
+	j.Status = StatusDone
+	var runErr error
+	if err := ctx.Err(); err != nil {
+		runErr = fmt.Errorf("job cancelled: %w", err)
+	} else if len(failed) > 0 {
+		runErr = fmt.Errorf("%d section(s) failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	if runErr != nil {
+		j.Status, j.Error = StatusFailed, runErr.Error()
+	}
+	if err := j.Save(); err != nil {
+		return err
+	}
+	return runErr
+}
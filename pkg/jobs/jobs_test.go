@@ -0,0 +1,275 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTasks() []Task {
+	return []Task{
+		{Package: "core", Dir: "/repo/core", Section: "overview"},
+		{Package: "core", Dir: "/repo/core", Section: "config"},
+		{Package: "cli", Dir: "/repo/cli", Section: "overview"},
+	}
+}
+
+func taskStatuses(j *Job) []string {
+	var statuses []string
+	for _, t := range j.Tasks {
+		statuses = append(statuses, t.Status)
+	}
+	return statuses
+}
+
+func TestNewAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCGEN_JOBS_DIR", dir)
+
+	if _, err := New(nil, Options{}); err == nil {
+		t.Error("New accepted a job without tasks")
+	}
+
+	job, err := New(testTasks(), Options{Model: "m", FailFast: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusQueued {
+		t.Errorf("new job status = %q, want %q", job.Status, StatusQueued)
+	}
+	if got := taskStatuses(job); !reflect.DeepEqual(got, []string{TaskPending, TaskPending, TaskPending}) {
+		t.Errorf("new task statuses = %q", got)
+	}
+	if job.Log != filepath.Join(dir, job.ID+".log") {
+		t.Errorf("job log = %q, want it in %s", job.Log, dir)
+	}
+
+	loaded, err := Load(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ID != job.ID || loaded.Options != job.Options || len(loaded.Tasks) != 3 {
+		t.Errorf("Load(%s) = %+v, want %+v", job.ID, loaded, job)
+	}
+	if byPrefix, err := Load(job.ID[:len(job.ID)-2]); err != nil || byPrefix.ID != job.ID {
+		t.Errorf("Load by prefix = %v, %v; want %s", byPrefix, err, job.ID)
+	}
+	if _, err := Load("19990101"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load of an unknown ID = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListAndAmbiguousPrefix(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCGEN_JOBS_DIR", dir)
+
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, id := range []string{"20260102-030405-aaaaaa", "20260102-030405-aaaabb", "20260102-030406-cccccc"} {
+		job := &Job{ID: id, Status: StatusDone, CreatedAt: base.Add(time.Duration(i) * time.Second)}
+		if err := job.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, j := range all {
+		ids = append(ids, j.ID)
+	}
+	want := []string{"20260102-030406-cccccc", "20260102-030405-aaaabb", "20260102-030405-aaaaaa"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("List() = %q, want newest first %q", ids, want)
+	}
+
+	if _, err := Load("20260102-030405-aaaa"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Load of a shared prefix = %v, want an ambiguity error", err)
+	}
+	if j, err := Load("20260102-030405-aaaaaa"); err != nil || j.ID != "20260102-030405-aaaaaa" {
+		t.Errorf("Load of an exact ID that prefixes another = %v, %v", j, err)
+	}
+}
+
+// TestRun checks the job and task transitions Run records, both in memory
+// and in the state file other processes read while the job runs.
+func TestRun(t *testing.T) {
+	boom := errors.New("boom")
+	cases := []struct {
+		name       string
+		opts       Options
+		prior      []string // Task statuses from an earlier run, if any
+		fail       string   // Section of the "core" package that fails
+		wantRun    []string
+		wantTasks  []string
+		wantStatus string
+		wantErr    string
+	}{
+		{
+			name:       "all ok",
+			wantRun:    []string{"core/overview", "core/config", "cli/overview"},
+			wantTasks:  []string{TaskOK, TaskOK, TaskOK},
+			wantStatus: StatusDone,
+		},
+		{
+			name:       "failure continues",
+			fail:       "overview",
+			wantRun:    []string{"core/overview", "core/config", "cli/overview"},
+			wantTasks:  []string{TaskFailed, TaskOK, TaskOK},
+			wantStatus: StatusFailed,
+			wantErr:    "1 section(s) failed: core/overview",
+		},
+		{
+			name:       "fail fast skips the rest",
+			opts:       Options{FailFast: true},
+			fail:       "overview",
+			wantRun:    []string{"core/overview"},
+			wantTasks:  []string{TaskFailed, TaskSkipped, TaskSkipped},
+			wantStatus: StatusFailed,
+			wantErr:    "1 section(s) failed: core/overview",
+		},
+		{
+			name:       "rerun skips finished tasks",
+			prior:      []string{TaskOK, TaskFailed, TaskSkipped},
+			wantRun:    []string{"core/config", "cli/overview"},
+			wantTasks:  []string{TaskOK, TaskOK, TaskOK},
+			wantStatus: StatusDone,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("DOCGEN_JOBS_DIR", t.TempDir())
+			job, err := New(testTasks(), c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, status := range c.prior {
+				job.Tasks[i].Status, job.Tasks[i].Error = status, "earlier"
+			}
+
+			var ran []string
+			err = job.Run(context.Background(), func(ctx context.Context, task Task) error {
+				ran = append(ran, task.Package+"/"+task.Section)
+				saved, err := Load(job.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if saved.Status != StatusRunning || saved.PID != os.Getpid() || saved.StartedAt == nil {
+					t.Errorf("state file during run: status %q, pid %d", saved.Status, saved.PID)
+				}
+				for _, st := range saved.Tasks {
+					if st.Section == task.Section && st.Package == task.Package {
+						if st.Status != TaskRunning || st.Error != "" || st.StartedAt == nil {
+							t.Errorf("running task saved as %+v", st)
+						}
+					}
+				}
+				if task.Package == "core" && task.Section == c.fail {
+					return boom
+				}
+				return nil
+			})
+			if c.wantErr == "" && err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+			if c.wantErr != "" && (err == nil || err.Error() != c.wantErr) {
+				t.Fatalf("Run() = %v, want %q", err, c.wantErr)
+			}
+			if !reflect.DeepEqual(ran, c.wantRun) {
+				t.Errorf("ran %q, want %q", ran, c.wantRun)
+			}
+
+			saved, err := Load(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := taskStatuses(saved); !reflect.DeepEqual(got, c.wantTasks) {
+				t.Errorf("saved task statuses = %q, want %q", got, c.wantTasks)
+			}
+			if saved.Status != c.wantStatus || saved.Error != c.wantErr || saved.FinishedAt == nil {
+				t.Errorf("saved job: status %q error %q, want %q %q", saved.Status, saved.Error, c.wantStatus, c.wantErr)
+			}
+			for _, task := range saved.Tasks {
+				if task.Status == TaskFailed && task.Error != boom.Error() {
+					t.Errorf("failed task error = %q, want %q", task.Error, boom)
+				}
+			}
+			if !saved.Finished() {
+				t.Errorf("finished job reports state %q", saved.State())
+			}
+		})
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	t.Setenv("DOCGEN_JOBS_DIR", t.TempDir())
+	job, err := New(testTasks(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	err = job.Run(ctx, func(ctx context.Context, task Task) error {
+		cancel()
+		return ctx.Err()
+	})
+	if err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() = %v, want a cancellation", err)
+	}
+	if got := taskStatuses(job); !reflect.DeepEqual(got, []string{TaskFailed, TaskSkipped, TaskSkipped}) {
+		t.Errorf("task statuses = %q", got)
+	}
+	if job.Status != StatusFailed {
+		t.Errorf("job status = %q, want %q", job.Status, StatusFailed)
+	}
+}
+
+// TestState checks a queued or running job is reported as interrupted once
+// its worker process is gone.
+func TestState(t *testing.T) {
+	exited := exec.Command("go", "version")
+	if err := exited.Run(); err != nil {
+		t.Skipf("cannot run a short-lived process: %v", err)
+	}
+	gone := exited.Process.Pid
+
+	cases := []struct {
+		status string
+		pid    int
+		want   string
+	}{
+		{StatusQueued, 0, StatusQueued},
+		{StatusRunning, os.Getpid(), StatusRunning},
+		{StatusRunning, gone, StatusInterrupted},
+		{StatusQueued, gone, StatusInterrupted},
+		{StatusDone, gone, StatusDone},
+		{StatusFailed, gone, StatusFailed},
+	}
+	for _, c := range cases {
+		job := &Job{Status: c.status, PID: c.pid}
+		if got := job.State(); got != c.want {
+			t.Errorf("State() of %s job with pid %d = %q, want %q", c.status, c.pid, got, c.want)
+		}
+		wantFinished := c.want != StatusQueued && c.want != StatusRunning
+		if job.Finished() != wantFinished {
+			t.Errorf("Finished() of %s job with pid %d = %v", c.status, c.pid, !wantFinished)
+		}
+	}
+}
+
+func TestCounts(t *testing.T) {
+	job := &Job{Tasks: []Task{{Status: TaskOK}, {Status: TaskOK}, {Status: TaskFailed}, {Status: TaskPending}}}
+	want := map[string]int{TaskOK: 2, TaskFailed: 1, TaskPending: 1}
+	if got := job.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package jobs
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+// TestDetach starts a process the way Start starts a worker and checks it
+// leads its own session, apart from the test's.
+func TestDetach(t *testing.T) {
+	cmd := exec.Command("sleep", "5")
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	pid := cmd.Process.Pid
+	defer cmd.Wait()         //nolint:errcheck // killed below
+	defer cmd.Process.Kill() //nolint:errcheck // best-effort cleanup
+
+	sid, err := getsid(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid != pid {
+		t.Errorf("detached process session = %d, want its own (%d)", sid, pid)
+	}
+	if !processAlive(pid) {
+		t.Error("processAlive reports a running worker as gone")
+	}
+}
+
+func getsid(pid int) (int, error) {
+	sid, _, errno := syscall.RawSyscall(syscall.SYS_GETSID, uintptr(pid), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(sid), nil
+}
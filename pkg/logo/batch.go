@@ -0,0 +1,206 @@
+package logo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes the logos of an ecosystem for batch generation.
+//
+//	font: ~/.local/share/fonts/FiraCode-Regular.ttf
+//	logos:
+//	  - input: flow/docs/images/logo.svg
+//	    text: grove flow
+//	    themes:
+//	      - { name: dark, input: flow/docs/images/logo-dark.svg, color: "#589ac7" }
+//	      - { name: light, color: "#1f4e79" }
+type Manifest struct {
+	Font          string      `yaml:"font"`
+	FallbackFonts []string    `yaml:"fallback_fonts,omitempty"`
+	Size          float64     `yaml:"size,omitempty"`
+	Spacing       float64     `yaml:"spacing,omitempty"`
+	TextScale     float64     `yaml:"text_scale,omitempty"`
+	Width         float64     `yaml:"width,omitempty"`
+	Layout        string      `yaml:"layout,omitempty"`         // below or right
+	LetterSpacing float64     `yaml:"letter_spacing,omitempty"` // In em
+	FontWeight    string      `yaml:"font_weight,omitempty"`    // normal, bold, or 400-900
+	Raster        string      `yaml:"raster,omitempty"`         // "png" also exports PNGs and an icon set
+	Sizes         []int       `yaml:"sizes,omitempty"`
+	Logos         []LogoEntry `yaml:"logos"`
+}
+
+// LogoEntry is one package's logo in a Manifest. Fields left empty fall back
+// to the manifest's.
+type LogoEntry struct {
+	Input  string       `yaml:"input"`
+	Text   string       `yaml:"text"`
+	Output string       `yaml:"output,omitempty"` // Defaults to input-with-text.svg
+	Color  string       `yaml:"color,omitempty"`
+	Font   string       `yaml:"font,omitempty"`
+	Width  float64      `yaml:"width,omitempty"`
+	Themes []ThemeEntry `yaml:"themes,omitempty"`
+}
+
+// ThemeEntry is a theme variant of a logo, e.g. for dark mode.
+type ThemeEntry struct {
+	Name   string `yaml:"name"`
+	Input  string `yaml:"input,omitempty"`  // Defaults to the logo's input
+	Output string `yaml:"output,omitempty"` // Defaults to input-with-text-<name>.svg
+	Color  string `yaml:"color,omitempty"`
+}
+
+// BatchResult is the outcome of generating one logo of a manifest.
+type BatchResult struct {
+	Output string
+	Files  []string // Raster files written next to Output
+	Err    error
+}
+
+// LoadManifest reads a logo manifest. Relative paths in it are resolved
+// against the manifest's directory and ~ is expanded.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path from user input
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if m.Raster != "" && m.Raster != "png" {
+		return nil, fmt.Errorf("unsupported raster format %q (supported: png)", m.Raster)
+	}
+	if _, err := ParseFontWeight(m.FontWeight); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" {
+			return ""
+		}
+		if strings.HasPrefix(p, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				return filepath.Join(home, p[2:])
+			}
+		}
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	m.Font = resolve(m.Font)
+	for i, f := range m.FallbackFonts {
+		m.FallbackFonts[i] = resolve(f)
+	}
+	for i := range m.Logos {
+		l := &m.Logos[i]
+		if l.Input == "" || l.Text == "" {
+			return nil, fmt.Errorf("logo %d: input and text are required", i+1)
+		}
+		l.Input, l.Output, l.Font = resolve(l.Input), resolve(l.Output), resolve(l.Font)
+		for j := range l.Themes {
+			t := &l.Themes[j]
+			if t.Name == "" {
+				return nil, fmt.Errorf("logo %s: theme %d has no name", l.Text, j+1)
+			}
+			t.Input, t.Output = resolve(t.Input), resolve(t.Output)
+		}
+	}
+	return &m, nil
+}
+
+// GenerateBatch generates every logo of a manifest and its theme variants. A
+// logo that fails does not stop the others; its error is in its result.
+func (g *Generator) GenerateBatch(m *Manifest) []BatchResult {
+	var results []BatchResult
+	for _, l := range m.Logos {
+		base := Config{
+			InputPath:     l.Input,
+			Text:          l.Text,
+			TextColor:     l.Color,
+			FontPath:      l.Font,
+			FontSize:      m.Size,
+			Spacing:       m.Spacing,
+			TextScale:     m.TextScale,
+			Width:         l.Width,
+			Layout:        m.Layout,
+			LetterSpacing: m.LetterSpacing,
+			FallbackFonts: m.FallbackFonts,
+		}
+		base.FontWeight, _ = ParseFontWeight(m.FontWeight)
+		if base.FontPath == "" {
+			base.FontPath = m.Font
+		}
+		if base.Width == 0 {
+			base.Width = m.Width
+		}
+
+		cfgs := []Config{base}
+		cfgs[0].OutputPath = l.Output
+		if cfgs[0].OutputPath == "" {
+			cfgs[0].OutputPath = defaultOutputPath(l.Input, "")
+		}
+		for _, t := range l.Themes {
+			cfg := base
+			if t.Input != "" {
+				cfg.InputPath = t.Input
+			}
+			if t.Color != "" {
+				cfg.TextColor = t.Color
+			}
+			cfg.OutputPath = t.Output
+			if cfg.OutputPath == "" {
+				cfg.OutputPath = defaultOutputPath(l.Input, t.Name)
+			}
+			cfgs = append(cfgs, cfg)
+		}
+
+		for i, cfg := range cfgs {
+			result := BatchResult{Output: cfg.OutputPath}
+			result.Err = g.Generate(cfg)
+			if result.Err == nil && m.Raster != "" {
+				// Theme variants share the logo's favicon, so only it gets an icon set
+				result.Files, result.Err = g.rasterizeLogo(cfg, m.Sizes, i == 0)
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// rasterizeLogo writes the PNG of a generated logo and, with icons, the icon
+// set of its input next to it, as logo generate --raster png does.
+func (g *Generator) rasterizeLogo(cfg Config, sizes []int, icons bool) ([]string, error) {
+	pngPath, err := g.ExportPNG(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !icons {
+		return []string{pngPath}, nil
+	}
+	iconFiles, err := g.GenerateIconSet(IconSetConfig{
+		InputPath: cfg.InputPath,
+		OutputDir: filepath.Dir(cfg.OutputPath),
+		Sizes:     sizes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate icon set: %w", err)
+	}
+	return append([]string{pngPath}, iconFiles...), nil
+}
+
+// defaultOutputPath returns input-with-text.svg, or
+// input-with-text-<theme>.svg for a theme variant.
+func defaultOutputPath(input, theme string) string {
+	ext := filepath.Ext(input)
+	out := strings.TrimSuffix(input, ext) + "-with-text"
+	if theme != "" {
+		out += "-" + theme
+	}
+	return out + ext
+}
@@ -0,0 +1,168 @@
+package logo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tdewolff/canvas"
+	"github.com/tdewolff/canvas/renderers"
+)
+
+// Social card dimensions in pixels: the 1.91:1 size Open Graph and Twitter
+// previews use.
+const (
+	CardWidth  = 1200
+	CardHeight = 630
+)
+
+// ptPerPx converts the card's pixel sizes to font points. The card is drawn
+// at one pixel per millimetre, and canvas sizes fonts in points.
+const ptPerPx = 72 / 25.4
+
+// CardConfig holds the configuration for a social card.
+type CardConfig struct {
+	Title      string // Page title, wrapped over up to three lines
+	Package    string // Package name, shown next to the logo
+	Category   string // Shown at the bottom
+	LogoPath   string // SVG logo drawn in the top-left corner (optional)
+	FontPath   string // Path to font file (TTF/OTF)
+	Background string // Background color (defaults to #0d1117)
+	TextColor  string // Title color (defaults to #f0f6fc)
+	Accent     string // Package name and bar color (defaults to the logo's color)
+}
+
+// GenerateCard renders a 1200x630 PNG social card: the logo and package name
+// at the top, the title in large type, the category at the bottom, and an
+// accent bar along the bottom edge.
+func (g *Generator) GenerateCard(cfg CardConfig) ([]byte, error) {
+	if cfg.FontPath == "" {
+		return nil, fmt.Errorf("font path is required for social cards")
+	}
+	if cfg.Background == "" {
+		cfg.Background = "#0d1117"
+	}
+	if cfg.TextColor == "" {
+		cfg.TextColor = "#f0f6fc"
+	}
+
+	var logoCanvas *canvas.Canvas
+	if cfg.LogoPath != "" {
+		data, err := os.ReadFile(cfg.LogoPath) //nolint:gosec // path from user config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read logo: %w", err)
+		}
+		if cfg.Accent == "" {
+			cfg.Accent = extractDominantColor(string(data))
+		}
+		if logoCanvas, err = canvas.ParseSVG(bytes.NewReader(data)); err != nil {
+			g.logger.Warnf("Failed to parse logo %s, rendering the card without it: %v", cfg.LogoPath, err)
+			logoCanvas = nil
+		}
+	}
+	if cfg.Accent == "" {
+		cfg.Accent = "#589ac7" // Fallback to grove blue
+	}
+
+	fontFamily := canvas.NewFontFamily("card")
+	if err := fontFamily.LoadFontFile(cfg.FontPath, canvas.FontRegular); err != nil {
+		return nil, fmt.Errorf("failed to load font %s: %w", cfg.FontPath, err)
+	}
+
+	c := canvas.New(CardWidth, CardHeight)
+	ctx := canvas.NewContext(c)
+
+	// Background and accent bar (canvas' origin is the bottom-left corner)
+	ctx.SetFillColor(canvas.Hex(cfg.Background))
+	ctx.DrawPath(0, 0, canvas.Rectangle(CardWidth, CardHeight))
+	ctx.SetFillColor(canvas.Hex(cfg.Accent))
+	ctx.DrawPath(0, 0, canvas.Rectangle(CardWidth, 12))
+
+	const margin = 80.0
+	headerTop := float64(CardHeight) - margin
+	textX := margin
+	if logoCanvas != nil && logoCanvas.W > 0 && logoCanvas.H > 0 {
+		const logoHeight = 96.0
+		scale := logoHeight / logoCanvas.H
+		logoCanvas.RenderViewTo(c, canvas.Identity.Translate(margin, headerTop-logoHeight).Scale(scale, scale))
+		textX += logoCanvas.W*scale + 28
+	}
+	if cfg.Package != "" {
+		if err := g.drawLine(ctx, fontFamily, cfg.Package, 44, cfg.Accent, textX, headerTop-70); err != nil {
+			return nil, err
+		}
+	}
+
+	// Title: shrink until it fits in three lines, then truncate
+	width := float64(CardWidth) - 2*margin
+	size := 72.0
+	lines := g.wrapText(fontFamily, cfg.Title, size, width)
+	for len(lines) > 3 && size > 48 {
+		size -= 8
+		lines = g.wrapText(fontFamily, cfg.Title, size, width)
+	}
+	if len(lines) > 3 {
+		lines = append(lines[:2], strings.TrimRight(lines[2], " .,;:")+"…")
+	}
+	y := float64(CardHeight) - 260
+	for _, line := range lines {
+		if err := g.drawLine(ctx, fontFamily, line, size, cfg.TextColor, margin, y); err != nil {
+			return nil, err
+		}
+		y -= size * 1.2
+	}
+
+	if cfg.Category != "" {
+		if err := g.drawLine(ctx, fontFamily, cfg.Category, 32, cfg.TextColor, margin, 60); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := renderers.PNG(canvas.DPMM(1))(&buf, c); err != nil {
+		return nil, fmt.Errorf("failed to render social card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine draws one line of text with its baseline at y, sized in pixels.
+func (g *Generator) drawLine(ctx *canvas.Context, family *canvas.FontFamily, text string, sizePx float64, color string, x, y float64) error {
+	face := family.Face(sizePx*ptPerPx, canvas.Hex(color), canvas.FontRegular, canvas.FontNormal)
+	path, _, err := face.ToPath(text)
+	if err != nil {
+		return fmt.Errorf("failed to convert text to path: %w", err)
+	}
+	ctx.SetFillColor(canvas.Hex(color))
+	ctx.DrawPath(x, y, path)
+	return nil
+}
+
+// wrapText breaks text into lines no wider than width at sizePx.
+func (g *Generator) wrapText(family *canvas.FontFamily, text string, sizePx, width float64) []string {
+	face := family.Face(sizePx*ptPerPx, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+	measure := func(s string) float64 {
+		path, _, err := face.ToPath(s)
+		if err != nil {
+			return 0
+		}
+		return path.Bounds().W()
+	}
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && measure(candidate) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -3,6 +3,7 @@ package logo
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -26,17 +27,43 @@ func New(logger *logrus.Logger) *Generator {
 
 // Config holds the configuration for logo generation.
 type Config struct {
-	InputPath  string  // Path to the input logo SVG
-	OutputPath string  // Path for the output combined SVG
-	Text       string  // Text to display (e.g., "grove flow")
-	TextColor  string  // Color for the text (e.g., "#589ac7")
-	FontPath   string  // Path to font file (TTF/OTF) - required for path conversion
-	FontSize   float64 // Font size in pixels (defaults to 48)
-	Spacing    float64 // Spacing between logo and text (defaults to 20)
-	TextScale  float64 // Text width as proportion of logo width (defaults to 0.8)
-	Width      float64 // Output SVG width in pixels (defaults to 200)
+	InputPath  string // Path to the input logo SVG
+	OutputPath string // Path for the output combined SVG
+	Text       string // Text to display (e.g., "grove flow")
+	TextColor  string // Color for the text (e.g., "#589ac7")
+	FontPath   string // Path to font file (TTF/OTF) - required for path conversion
+	// FallbackFonts are tried in order for glyphs FontPath does not have
+	FallbackFonts []string
+	FontSize      float64 // Font size in pixels (defaults to 48)
+	Spacing       float64 // Spacing between logo and text (defaults to 20)
+	TextScale     float64 // Text width as proportion of logo width, or with LayoutRight text height as proportion of logo height (defaults to 0.8, 0.6 right)
+	Width         float64 // Output SVG width in pixels (defaults to 200)
+	// Layout places the text below the logo (LayoutBelow, the default) or to
+	// its right (LayoutRight)
+	Layout        string
+	LetterSpacing float64 // Extra space between letters, in em (e.g. 0.05)
+	FontWeight    int     // CSS weight from 400 to 900; above 400 the glyphs are emboldened (defaults to 400)
 }
 
+// Text layouts.
+const (
+	LayoutBelow = "below"
+	LayoutRight = "right"
+)
+
+const (
+	// defaultRightTextScale is the default text height, as a proportion of
+	// the logo height, for LayoutRight.
+	defaultRightTextScale = 0.6
+	// lineGap is the space between lines of text, as a proportion of the
+	// first line's height. Line boxes already include the font's ascent and
+	// descent, so it only needs to be small.
+	lineGap = 0.1
+	// emboldenPerWeight is the stroke width, in em, added per 100 of font
+	// weight above 400.
+	emboldenPerWeight = 0.012
+)
+
 // DefaultConfig returns a Config with sensible defaults.
 // Note: TextColor defaults to empty string to trigger auto-detection from source SVG.
 func DefaultConfig() Config {
@@ -55,6 +82,8 @@ type SVGDimensions struct {
 	Height  float64
 	ViewBox string
 	Content string // The inner content of the SVG (everything inside <svg>...</svg>)
+	// Namespaces holds the xmlns attributes for the prefixes Content uses
+	Namespaces string
 }
 
 // Generate creates a combined logo+text SVG with text converted to paths.
@@ -63,6 +92,18 @@ func (g *Generator) Generate(cfg Config) error {
 	if cfg.FontPath == "" {
 		return fmt.Errorf("font path is required for text-to-path conversion")
 	}
+	if cfg.Layout == "" {
+		cfg.Layout = LayoutBelow
+	}
+	if cfg.Layout != LayoutBelow && cfg.Layout != LayoutRight {
+		return fmt.Errorf("unknown layout %q (supported: below, right)", cfg.Layout)
+	}
+	if cfg.FontWeight == 0 {
+		cfg.FontWeight = 400
+	}
+	if cfg.FontWeight < 400 || cfg.FontWeight > 900 {
+		return fmt.Errorf("font weight %d is not supported (400-900; use a lighter font file for thinner text)", cfg.FontWeight)
+	}
 
 	// Read and parse the input SVG
 	dims, err := g.parseSVG(cfg.InputPath)
@@ -79,6 +120,9 @@ func (g *Generator) Generate(cfg Config) error {
 	}
 	if cfg.TextScale == 0 {
 		cfg.TextScale = DefaultConfig().TextScale
+		if cfg.Layout == LayoutRight {
+			cfg.TextScale = defaultRightTextScale
+		}
 	}
 	if cfg.Width == 0 {
 		cfg.Width = DefaultConfig().Width
@@ -93,28 +137,47 @@ func (g *Generator) Generate(cfg Config) error {
 		g.logger.Debugf("Auto-detected text color: %s", cfg.TextColor)
 	}
 
-	// Load the font
-	fontFamily := canvas.NewFontFamily("custom")
-	if err := fontFamily.LoadFontFile(cfg.FontPath, canvas.FontRegular); err != nil {
-		return fmt.Errorf("failed to load font %s: %w", cfg.FontPath, err)
+	// Load the font and its fallbacks, creating a face of each for measuring
+	// and rendering
+	var chain fontChain
+	for i, fontPath := range append([]string{cfg.FontPath}, cfg.FallbackFonts...) {
+		fontFamily := canvas.NewFontFamily(fmt.Sprintf("custom%d", i))
+		if err := fontFamily.LoadFontFile(fontPath, canvas.FontRegular); err != nil {
+			return fmt.Errorf("failed to load font %s: %w", fontPath, err)
+		}
+		chain = append(chain, fontFamily.Face(cfg.FontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal))
 	}
 
-	// Create a face for measuring and rendering
-	face := fontFamily.Face(cfg.FontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+	// Heavier weights are drawn by stroking the glyph outlines, which a
+	// single font file cannot provide otherwise
+	emSize := cfg.FontSize * 25.4 / 72 // Face sizes are in points, paths in millimetres
+	strokeWidth := float64(cfg.FontWeight-400) / 100 * emboldenPerWeight * emSize
 
-	// Measure the text
-	textPath, _, err := face.ToPath(cfg.Text)
-	if err != nil {
-		return fmt.Errorf("failed to convert text to path: %w", err)
+	// Convert each line of text to paths
+	var lines []textLine
+	for _, text := range strings.Split(cfg.Text, "\n") {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		path, advance, err := chain.textPath(text, cfg.LetterSpacing*emSize)
+		if err != nil {
+			return fmt.Errorf("failed to convert text to path: %w", err)
+		}
+		ascent, descent := chain.metrics()
+		svg, w, h, err := g.generateTextPathSVG(path, advance, ascent, descent, cfg.TextColor, strokeWidth)
+		if err != nil {
+			return fmt.Errorf("failed to generate text path: %w", err)
+		}
+		lines = append(lines, textLine{svg: svg, width: w, height: h})
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("text is empty")
 	}
-	textBounds := textPath.Bounds()
-	textWidth := textBounds.W()
-	textHeight := textBounds.H()
 
 	// Parse the original viewBox
 	var vbX, vbY, vbW, vbH float64
 	if dims.ViewBox != "" {
-		parts := strings.Fields(dims.ViewBox)
+		parts := strings.Fields(strings.ReplaceAll(dims.ViewBox, ",", " "))
 		if len(parts) == 4 {
 			vbX, _ = strconv.ParseFloat(parts[0], 64)
 			vbY, _ = strconv.ParseFloat(parts[1], 64)
@@ -128,67 +191,74 @@ func (g *Generator) Generate(cfg Config) error {
 
 	// Calculate scale factor from viewBox to actual dimensions
 	scaleY := dims.Height / vbH
+	scaledSpacing := cfg.Spacing / scaleY
 
-	// Scale text to fit nicely under the logo
-	targetTextWidth := vbW * cfg.TextScale
-	textScale := targetTextWidth / textWidth
-	scaledTextWidth := textWidth * textScale
-	scaledTextHeightFinal := textHeight * textScale
-
-	// Calculate new viewBox dimensions
-	// Width: use the larger of logo width or text width, plus small margin for text
-	newVBWidth := vbW
-	logoOffsetX := 0.0
-	textX := 0.0
-	textMargin := scaledTextHeightFinal * 0.2 // Horizontal margin to prevent clipping
-	if scaledTextWidth+textMargin > vbW {
-		// Text is wider than logo - expand viewBox and center logo
-		newVBWidth = scaledTextWidth + textMargin
-		logoOffsetX = (newVBWidth - vbW) / 2
-		textX = textMargin / 2
+	// Scale the first line to the target size, then shrink any longer line
+	// to the first line's width, so one long line does not shrink them all
+	if cfg.Layout == LayoutRight {
+		// Text block height as a proportion of the logo height
+		lineHeight := vbH * cfg.TextScale / (float64(len(lines)) + float64(len(lines)-1)*lineGap)
+		lines[0].scale = lineHeight / lines[0].height
 	} else {
-		// Logo is wider - center text under logo
-		textX = (vbW - scaledTextWidth) / 2
+		// Text width as a proportion of the logo width
+		lines[0].scale = vbW * cfg.TextScale / lines[0].width
+	}
+	blockWidth := lines[0].width * lines[0].scale
+	gap := lines[0].height * lines[0].scale * lineGap
+	blockHeight := -gap
+	for i := range lines {
+		if i > 0 {
+			lines[i].scale = math.Min(lines[0].scale, blockWidth/lines[i].width)
+		}
+		blockHeight += lines[i].height*lines[i].scale + gap
 	}
 
-	// Calculate new dimensions - add space for text below logo
-	scaledSpacing := cfg.Spacing / scaleY
-	topPadding := 20.0                                                                           // Padding at top to prevent clipping
-	bottomPadding := 50.0                                                                        // Extra padding between logo and text
-	newVBHeight := topPadding + vbH + scaledSpacing + bottomPadding + scaledTextHeightFinal + 10 // extra padding
+	// Lay out the logo and text block. Line boxes span the font's ascent and
+	// descent, so descenders fit without extra padding and centering does not
+	// depend on which letters the text has.
+	var logoX, logoY, textX, textY, newVBWidth, newVBHeight float64
+	textMargin := lines[0].height * lines[0].scale * 0.2 // Horizontal margin for glyphs overhanging their advance
+	if cfg.Layout == LayoutRight {
+		// Text to the right, vertically centered on the logo
+		textX = vbW + scaledSpacing
+		textY = (vbH - blockHeight) / 2
+		if textY < 0 {
+			logoY = -textY
+			textY = 0
+		}
+		newVBWidth = textX + blockWidth + textMargin
+		newVBHeight = math.Max(logoY+vbH, textY+blockHeight)
+	} else {
+		// Width: use the larger of logo width or text width, plus small margin for text
+		newVBWidth = vbW
+		if blockWidth+textMargin > vbW {
+			// Text is wider than logo - expand viewBox and center logo
+			newVBWidth = blockWidth + textMargin
+			logoX = (newVBWidth - vbW) / 2
+		}
+		textY = vbH + scaledSpacing
+		newVBHeight = textY + blockHeight
+	}
 
 	// Use specified width and calculate height to maintain aspect ratio
 	newWidth := cfg.Width
 	aspectRatio := newVBHeight / newVBWidth
 	newHeight := newWidth * aspectRatio
 
-	// Generate text path SVG using canvas
-	textPathSVG, err := g.generateTextPathSVG(cfg.Text, face, cfg.TextColor, textScale)
-	if err != nil {
-		return fmt.Errorf("failed to generate text path: %w", err)
-	}
-
-	// Calculate text Y position (below logo, accounting for padding)
-	textY := topPadding + vbH + scaledSpacing + bottomPadding
-
 	// Coordinate normalization: always use (0, 0) as the output viewBox origin.
 	// We translate the logo content to compensate for any non-zero origin in the source SVG.
 	// This handles SVGs with centered coordinates like viewBox="-50 -50 100 100".
-	// Also add topPadding to push content down from the top edge.
-	normX := -vbX // Translation to normalize source coordinates to (0,0)
-	normY := -vbY + topPadding
+	logoTranslateX := -vbX + logoX
+	logoTranslateY := -vbY + logoY
 
 	// Generate the combined SVG with normalized coordinates (origin at 0,0)
 	var buf bytes.Buffer
 	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
-	buf.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.2f %.2f">`,
-		newWidth, newHeight, newVBWidth, newVBHeight))
+	buf.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg"%s width="%.0f" height="%.0f" viewBox="0 0 %.2f %.2f">`,
+		dims.Namespaces, newWidth, newHeight, newVBWidth, newVBHeight))
 	buf.WriteString("\n")
 
 	// Add the original SVG content as a group, translated to normalize coordinates
-	// and optionally offset horizontally if text is wider than logo
-	logoTranslateX := normX + logoOffsetX
-	logoTranslateY := normY
 	if logoTranslateX != 0 || logoTranslateY != 0 {
 		buf.WriteString(fmt.Sprintf(`  <g transform="translate(%.2f, %.2f)">`, logoTranslateX, logoTranslateY))
 	} else {
@@ -198,11 +268,20 @@ func (g *Generator) Generate(cfg Config) error {
 	buf.WriteString(dims.Content)
 	buf.WriteString("\n  </g>\n")
 
-	// Add the text path, translated to position (relative to normalized coordinates)
-	buf.WriteString(fmt.Sprintf(`  <g transform="translate(%.2f, %.2f) scale(%.4f)">`, textX, textY, textScale))
-	buf.WriteString("\n")
-	buf.WriteString(textPathSVG)
-	buf.WriteString("\n  </g>\n")
+	// Add each line of text, centered in the text block below the logo or
+	// left-aligned next to it
+	y := textY
+	for _, line := range lines {
+		x := textX
+		if cfg.Layout == LayoutBelow {
+			x = (newVBWidth - line.width*line.scale) / 2
+		}
+		buf.WriteString(fmt.Sprintf(`  <g transform="translate(%.2f, %.2f) scale(%.4f)">`, x, y, line.scale))
+		buf.WriteString("\n")
+		buf.WriteString(line.svg)
+		buf.WriteString("\n  </g>\n")
+		y += line.height*line.scale + gap
+	}
 
 	buf.WriteString("</svg>\n")
 
@@ -220,19 +299,99 @@ func (g *Generator) Generate(cfg Config) error {
 	return nil
 }
 
-// generateTextPathSVG converts text to SVG path elements.
-func (g *Generator) generateTextPathSVG(text string, face *canvas.FontFace, hexColor string, scale float64) (string, error) {
-	// Convert text to path
-	textPath, _, err := face.ToPath(text)
-	if err != nil {
-		return "", err
+// textLine is one line of logo text converted to paths.
+type textLine struct {
+	svg    string
+	width  float64
+	height float64
+	scale  float64 // Scale applied in the combined SVG
+}
+
+// fontChain is a font face and its fallbacks, tried in order for each
+// glyph.
+type fontChain []*canvas.FontFace
+
+// faceFor returns the first face with a glyph for r, or the primary face
+// when none has one.
+func (c fontChain) faceFor(r rune) *canvas.FontFace {
+	for _, face := range c {
+		if face.Font.GlyphIndex(r) != 0 {
+			return face
+		}
 	}
+	return c[0]
+}
+
+// metrics returns the largest ascent and descent of the chain's faces.
+func (c fontChain) metrics() (ascent, descent float64) {
+	for _, face := range c {
+		m := face.Metrics()
+		ascent = math.Max(ascent, m.Ascent)
+		descent = math.Max(descent, m.Descent)
+	}
+	return ascent, descent
+}
+
+// textPath converts a line of text to a path with its baseline at y=0 and
+// returns it with its advance width. Runs of glyphs are taken from the first
+// font that has them. With letter spacing, glyphs are placed one by one,
+// which gives up the font's kerning.
+func (c fontChain) textPath(text string, letterSpacing float64) (*canvas.Path, float64, error) {
+	path := &canvas.Path{}
+	x := 0.0
+	flush := func(face *canvas.FontFace, run string) error {
+		if run == "" {
+			return nil
+		}
+		p, advance, err := face.ToPath(run)
+		if err != nil {
+			return err
+		}
+		path = path.Join(p.Translate(x, 0))
+		x += advance
+		return nil
+	}
+
+	var runFace *canvas.FontFace
+	var run strings.Builder
+	for _, r := range text {
+		face := c.faceFor(r)
+		if face != runFace || letterSpacing != 0 {
+			if err := flush(runFace, run.String()); err != nil {
+				return nil, 0, err
+			}
+			if run.Len() > 0 && letterSpacing != 0 {
+				x += letterSpacing
+			}
+			runFace = face
+			run.Reset()
+		}
+		run.WriteRune(r)
+	}
+	if err := flush(runFace, run.String()); err != nil {
+		return nil, 0, err
+	}
+	return path, x, nil
+}
 
-	// Create a canvas context to render to SVG
-	bounds := textPath.Bounds()
+// generateTextPathSVG renders a line's path to SVG path elements and returns
+// them with the size of the line's box. The box spans the advance width
+// horizontally and the font's ascent and descent vertically, grown to fit
+// any ink outside it, with its top-left corner at the origin. A non-zero
+// strokeWidth outlines the glyphs to make them heavier.
+func (g *Generator) generateTextPathSVG(textPath *canvas.Path, advance, ascent, descent float64, hexColor string, strokeWidth float64) (string, float64, float64, error) {
+	ink := textPath.Bounds()
+	if ink.W() <= 0 || ink.H() <= 0 {
+		return "", 0, 0, fmt.Errorf("text has no visible glyphs")
+	}
+	left := math.Min(0, ink.X0) - strokeWidth/2
+	right := math.Max(advance, ink.X1) + strokeWidth/2
+	bottom := math.Min(-descent, ink.Y0) - strokeWidth/2
+	top := math.Max(ascent, ink.Y1) + strokeWidth/2
+	w, h := right-left, top-bottom
 
 	// Create a small canvas just for the text
-	c := canvas.New(bounds.W(), bounds.H())
+	c := canvas.New(w, h)
 	ctx := canvas.NewContext(c)
 
 	// Parse the color
@@ -243,7 +402,12 @@ func (g *Generator) generateTextPathSVG(text string, face *canvas.FontFace, hexC
 
 	// Draw the text path
 	ctx.SetFillColor(fillColor)
-	ctx.DrawPath(0, bounds.H(), textPath)
+	if strokeWidth > 0 {
+		ctx.SetStrokeColor(fillColor)
+		ctx.SetStrokeWidth(strokeWidth)
+		ctx.SetStrokeJoiner(canvas.RoundJoin)
+	}
+	ctx.DrawPath(-left, -bottom, textPath)
 
 	// Render to SVG
 	var buf bytes.Buffer
@@ -255,7 +419,30 @@ func (g *Generator) generateTextPathSVG(text string, face *canvas.FontFace, hexC
 	svgContent := buf.String()
 	pathContent := extractPathElements(svgContent)
 
-	return pathContent, nil
+	return pathContent, w, h, nil
+}
+
+// ParseFontWeight parses a CSS font weight: normal, bold, or 100 to 900.
+func ParseFontWeight(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "normal", "regular":
+		return 400, nil
+	case "medium":
+		return 500, nil
+	case "semibold":
+		return 600, nil
+	case "bold":
+		return 700, nil
+	case "extrabold":
+		return 800, nil
+	case "black":
+		return 900, nil
+	}
+	weight, err := strconv.Atoi(s)
+	if err != nil || weight < 100 || weight > 900 {
+		return 0, fmt.Errorf("invalid font weight %q (use normal, bold, or 100-900)", s)
+	}
+	return weight, nil
 }
 
 // extractPathElements extracts path elements from an SVG string.
@@ -266,44 +453,34 @@ func extractPathElements(svgContent string) string {
 	return strings.Join(matches, "\n    ")
 }
 
-// parseSVG reads an SVG file and extracts its dimensions and inner content.
+// parseSVG reads an SVG file and extracts its dimensions and inner content,
+// without editor metadata.
 func (g *Generator) parseSVG(path string) (*SVGDimensions, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // path from user config
 	if err != nil {
 		return nil, err
 	}
 
-	content := string(data)
-	dims := &SVGDimensions{}
-
-	// Extract width and height attributes
-	widthRe := regexp.MustCompile(`\bwidth="([^"]+)"`)
-	heightRe := regexp.MustCompile(`\bheight="([^"]+)"`)
-	viewBoxRe := regexp.MustCompile(`\bviewBox="([^"]+)"`)
-
-	if m := widthRe.FindStringSubmatch(content); len(m) > 1 {
-		dims.Width, _ = strconv.ParseFloat(strings.TrimSuffix(m[1], "px"), 64)
-	}
-	if m := heightRe.FindStringSubmatch(content); len(m) > 1 {
-		dims.Height, _ = strconv.ParseFloat(strings.TrimSuffix(m[1], "px"), 64)
-	}
-	if m := viewBoxRe.FindStringSubmatch(content); len(m) > 1 {
-		dims.ViewBox = m[1]
+	root, namespaces, err := decodeSVG(data)
+	if err != nil {
+		return nil, err
 	}
+	root.stripEditorData(namespaces)
 
-	// Extract the inner content (everything between <svg ...> and </svg>)
-	svgStartRe := regexp.MustCompile(`(?s)<svg[^>]*>`)
-	svgEndRe := regexp.MustCompile(`(?s)</svg>`)
-
-	startMatch := svgStartRe.FindStringIndex(content)
-	endMatch := svgEndRe.FindStringIndex(content)
-
-	if startMatch != nil && endMatch != nil && startMatch[1] < endMatch[0] {
-		dims.Content = strings.TrimSpace(content[startMatch[1]:endMatch[0]])
+	dims := &SVGDimensions{
+		Width:   parseLength(root.attr("width")),
+		Height:  parseLength(root.attr("height")),
+		ViewBox: strings.TrimSpace(root.attr("viewBox")),
 	}
+	var content bytes.Buffer
+	root.writeChildren(&content)
+	dims.Content = strings.TrimSpace(content.String())
 
-	// Strip Inkscape/Sodipodi metadata elements (they use namespaces not declared in our output)
-	dims.Content = stripInkscapeMetadata(dims.Content)
+	// The content is moved into a new document, so carry over the
+	// declarations of the namespaces it uses (e.g. xlink)
+	used := make(map[string]bool)
+	root.usedPrefixes(used)
+	dims.Namespaces = namespaceDeclarations(namespaces, used)
 
 	// Default dimensions if not found
 	if dims.Width == 0 {
@@ -316,27 +493,6 @@ func (g *Generator) parseSVG(path string) (*SVGDimensions, error) {
 	return dims, nil
 }
 
-// stripInkscapeMetadata removes Inkscape and Sodipodi specific elements that aren't needed for rendering.
-func stripInkscapeMetadata(content string) string {
-	// Remove sodipodi:namedview elements
-	namedViewRe := regexp.MustCompile(`(?s)<sodipodi:namedview[^>]*/>|<sodipodi:namedview[^>]*>.*?</sodipodi:namedview>`)
-	content = namedViewRe.ReplaceAllString(content, "")
-
-	// Remove empty defs elements
-	emptyDefsRe := regexp.MustCompile(`(?s)<defs[^>]*/>\s*|<defs[^>]*>\s*</defs>`)
-	content = emptyDefsRe.ReplaceAllString(content, "")
-
-	// Remove metadata elements
-	metadataRe := regexp.MustCompile(`(?s)<metadata[^>]*>.*?</metadata>`)
-	content = metadataRe.ReplaceAllString(content, "")
-
-	// Remove inkscape: and sodipodi: attributes from remaining elements
-	inkscapeAttrRe := regexp.MustCompile(`\s+(inkscape|sodipodi):[a-zA-Z-]+="[^"]*"`)
-	content = inkscapeAttrRe.ReplaceAllString(content, "")
-
-	return content
-}
-
 // extractDominantColor extracts the first non-black, non-white hex color from SVG content.
 // It looks for colors in fill attributes, style attributes, and stop-color (for gradients).
 // Returns empty string if no suitable color is found.
@@ -0,0 +1,203 @@
+package logo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tdewolff/canvas"
+	"github.com/tdewolff/canvas/renderers/rasterizer"
+)
+
+// AppleTouchIconSize is the size iOS uses for home screen icons; smaller
+// devices scale it down.
+const AppleTouchIconSize = 180
+
+// DefaultIconSizes are the PNG sizes of an icon set: the favicon sizes, the
+// Apple touch icon and a large icon for web app manifests.
+var DefaultIconSizes = []int{16, 32, 180, 512}
+
+// maxICOSize is the largest image put in favicon.ico. Browsers only use the
+// small sizes; larger ones would just bloat the file.
+const maxICOSize = 64
+
+// IconSetConfig holds the configuration for an icon set.
+type IconSetConfig struct {
+	InputPath  string // Path to the logo SVG
+	OutputDir  string // Directory the icons are written to
+	Sizes      []int  // PNG sizes in pixels (defaults to DefaultIconSizes)
+	Background string // Apple touch icon background, which iOS shows black when transparent (defaults to #ffffff)
+}
+
+// GenerateIconSet rasterizes a logo into square PNGs (<name>-<size>.png), a
+// favicon.ico holding the small sizes, and an apple-touch-icon.png, and
+// returns the paths written. The logo is centered on a transparent square.
+func (g *Generator) GenerateIconSet(cfg IconSetConfig) ([]string, error) {
+	data, err := os.ReadFile(cfg.InputPath) //nolint:gosec // path from user config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo: %w", err)
+	}
+	sizes := append([]int(nil), cfg.Sizes...)
+	if len(sizes) == 0 {
+		sizes = append(sizes, DefaultIconSizes...)
+	}
+	sort.Ints(sizes)
+	if cfg.Background == "" {
+		cfg.Background = "#ffffff"
+	}
+	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil { //nolint:gosec // internal doc tool
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(cfg.InputPath), filepath.Ext(cfg.InputPath))
+	var written []string
+	var icoImages []image.Image
+	for _, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("invalid icon size %d", size)
+		}
+		img, err := g.RasterizeIcon(data, size, "")
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(cfg.OutputDir, fmt.Sprintf("%s-%d.png", base, size))
+		if err := WritePNG(path, img); err != nil {
+			return nil, err
+		}
+		written = append(written, path)
+		if size <= maxICOSize {
+			icoImages = append(icoImages, img)
+		}
+	}
+
+	if len(icoImages) == 0 {
+		img, err := g.RasterizeIcon(data, 32, "")
+		if err != nil {
+			return nil, err
+		}
+		icoImages = append(icoImages, img)
+	}
+	ico, err := EncodeICO(icoImages)
+	if err != nil {
+		return nil, err
+	}
+	icoPath := filepath.Join(cfg.OutputDir, "favicon.ico")
+	if err := os.WriteFile(icoPath, ico, 0o644); err != nil { //nolint:gosec // internal doc tool output
+		return nil, fmt.Errorf("failed to write favicon.ico: %w", err)
+	}
+	written = append(written, icoPath)
+
+	touch, err := g.RasterizeIcon(data, AppleTouchIconSize, cfg.Background)
+	if err != nil {
+		return nil, err
+	}
+	touchPath := filepath.Join(cfg.OutputDir, "apple-touch-icon.png")
+	if err := WritePNG(touchPath, touch); err != nil {
+		return nil, err
+	}
+	written = append(written, touchPath)
+
+	g.logger.Debugf("Generated icon set for %s in %s", cfg.InputPath, cfg.OutputDir)
+	return written, nil
+}
+
+// ExportPNG writes the combined logo generated for cfg as a PNG next to it,
+// at twice its width for high-density screens, and returns the PNG's path.
+func (g *Generator) ExportPNG(cfg Config) (string, error) {
+	combined, err := os.ReadFile(cfg.OutputPath) //nolint:gosec // file just generated
+	if err != nil {
+		return "", fmt.Errorf("failed to read generated logo: %w", err)
+	}
+	width := cfg.Width
+	if width == 0 {
+		width = DefaultConfig().Width
+	}
+	img, err := g.Rasterize(combined, int(width*2))
+	if err != nil {
+		return "", fmt.Errorf("failed to rasterize %s: %w", cfg.OutputPath, err)
+	}
+	pngPath := strings.TrimSuffix(cfg.OutputPath, filepath.Ext(cfg.OutputPath)) + ".png"
+	if err := WritePNG(pngPath, img); err != nil {
+		return "", err
+	}
+	return pngPath, nil
+}
+
+// RasterizeIcon renders an SVG centered on a size x size square, filled with
+// background or transparent when it is empty.
+func (g *Generator) RasterizeIcon(svgData []byte, size int, background string) (*image.RGBA, error) {
+	src, err := canvas.ParseSVG(bytes.NewReader(svgData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SVG: %w", err)
+	}
+	if src.W <= 0 || src.H <= 0 {
+		return nil, fmt.Errorf("SVG has no size")
+	}
+	side := math.Max(src.W, src.H)
+	c := canvas.New(side, side)
+	if background != "" {
+		ctx := canvas.NewContext(c)
+		ctx.SetFillColor(canvas.Hex(background))
+		ctx.DrawPath(0, 0, canvas.Rectangle(side, side))
+	}
+	src.RenderViewTo(c, canvas.Identity.Translate((side-src.W)/2, (side-src.H)/2))
+	return rasterizer.Draw(c, canvas.DPMM(float64(size)/side), canvas.DefaultColorSpace), nil
+}
+
+// Rasterize renders an SVG to an image width pixels wide, keeping its aspect
+// ratio.
+func (g *Generator) Rasterize(svgData []byte, width int) (*image.RGBA, error) {
+	c, err := canvas.ParseSVG(bytes.NewReader(svgData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SVG: %w", err)
+	}
+	if c.W <= 0 || c.H <= 0 {
+		return nil, fmt.Errorf("SVG has no size")
+	}
+	return rasterizer.Draw(c, canvas.DPMM(float64(width)/c.W), canvas.DefaultColorSpace), nil
+}
+
+// EncodeICO encodes images into an ICO file, storing each as PNG, which
+// every browser supports. Images must be at most 256 pixels on a side.
+func EncodeICO(images []image.Image) ([]byte, error) {
+	const headerSize, entrySize = 6, 16
+	var header, body bytes.Buffer
+	_ = binary.Write(&header, binary.LittleEndian, [3]uint16{0, 1, uint16(len(images))}) // reserved, type (icon), count
+	offset := headerSize + entrySize*len(images)
+	for _, img := range images {
+		b := img.Bounds()
+		if b.Dx() > 256 || b.Dy() > 256 {
+			return nil, fmt.Errorf("ICO images must be at most 256 pixels, got %dx%d", b.Dx(), b.Dy())
+		}
+		var data bytes.Buffer
+		if err := png.Encode(&data, img); err != nil {
+			return nil, fmt.Errorf("failed to encode icon: %w", err)
+		}
+		// A dimension of 0 means 256
+		header.Write([]byte{byte(b.Dx()), byte(b.Dy()), 0, 0})
+		_ = binary.Write(&header, binary.LittleEndian, [2]uint16{1, 32}) // planes, bits per pixel
+		_ = binary.Write(&header, binary.LittleEndian, [2]uint32{uint32(data.Len()), uint32(offset)})
+		offset += data.Len()
+		body.Write(data.Bytes())
+	}
+	return append(header.Bytes(), body.Bytes()...), nil
+}
+
+// WritePNG encodes img as a PNG file at path.
+func WritePNG(path string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil { //nolint:gosec // internal doc tool output
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package logo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// editorNamespaces are the namespaces of drawing editors' private data,
+// which is stripped from logos since it does not affect rendering.
+var editorNamespaces = map[string]bool{
+	"http://www.inkscape.org/namespaces/inkscape":            true,
+	"http://sodipodi.sourceforge.net/DTD/sodipodi-0.dtd":     true,
+	"http://ns.adobe.com/AdobeIllustrator/10.0/":             true,
+	"http://ns.adobe.com/AdobeSVGViewerExtensions/3.0/":      true,
+	"http://www.bohemiancoding.com/sketch/ns":                true,
+	"http://ns.adobe.com/Extensibility/1.0/":                 true,
+	"http://ns.adobe.com/Graphs/1.0/":                        true,
+	"http://ns.adobe.com/SaveForWeb/1.0/":                    true,
+	"http://ns.adobe.com/Variables/1.0/":                     true,
+	"http://ns.adobe.com/ImageReplacement/1.0/":              true,
+	"http://ns.adobe.com/xap/1.0/":                           true,
+	"http://creativecommons.org/ns#":                         true,
+	"http://purl.org/dc/elements/1.1/":                       true,
+	"http://www.w3.org/1999/02/22-rdf-syntax-ns#":            true,
+	"http://www.serif.com/":                                  true,
+	"https://boxy-svg.com":                                   true,
+	"http://www.figma.com/figma/ns":                          true,
+	"http://schemas.microsoft.com/visio/2003/SVGExtensions/": true,
+}
+
+// Escapers for writing parsed content back. Unlike xml.EscapeText they keep
+// newlines, so the source's formatting survives.
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\n", "&#xA;", "\t", "&#x9;")
+)
+
+// svgNode is an element of a parsed SVG, or a text, CDATA or comment node
+// when start is nil.
+type svgNode struct {
+	start    *xml.StartElement
+	children []*svgNode
+	text     []byte // Character data, escaped when written
+	comment  []byte
+}
+
+// decodeSVG parses an SVG document and returns its root <svg> element and
+// the namespace declarations (prefix to URL) made anywhere in it.
+func decodeSVG(data []byte) (*svgNode, map[string]string, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	namespaces := make(map[string]string)
+	var root *svgNode
+	var stack []*svgNode
+	for {
+		// RawToken keeps prefixes as written, so elements and attributes can
+		// be written back the way the source spells them
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			el := t.Copy()
+			for _, attr := range el.Attr {
+				if attr.Name.Space == "xmlns" {
+					namespaces[attr.Name.Local] = attr.Value
+				}
+			}
+			node := &svgNode{start: &el}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil && el.Name.Local == "svg" {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &svgNode{text: bytes.Clone(t)})
+			}
+		case xml.Comment:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &svgNode{comment: bytes.Clone(t)})
+			}
+		}
+	}
+	if root == nil {
+		return nil, nil, fmt.Errorf("no <svg> element found")
+	}
+	return root, namespaces, nil
+}
+
+// attr returns the value of an unprefixed attribute.
+func (n *svgNode) attr(name string) string {
+	for _, a := range n.start.Attr {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// stripEditorData removes the elements and attributes of editor namespaces,
+// <metadata> elements, and <defs> left empty, from n's children.
+func (n *svgNode) stripEditorData(namespaces map[string]string) {
+	isEditor := func(prefix string) bool {
+		if prefix == "inkscape" || prefix == "sodipodi" {
+			return true
+		}
+		return prefix != "" && prefix != "xmlns" && editorNamespaces[namespaces[prefix]]
+	}
+	kept := n.children[:0]
+	for _, child := range n.children {
+		if child.start == nil {
+			kept = append(kept, child)
+			continue
+		}
+		name := child.start.Name
+		if isEditor(name.Space) || (name.Space == "" && name.Local == "metadata") {
+			kept = dropIndent(kept)
+			continue
+		}
+		attrs := child.start.Attr[:0]
+		for _, a := range child.start.Attr {
+			if isEditor(a.Name.Space) || (a.Name.Space == "xmlns" && isEditor(a.Name.Local)) {
+				continue
+			}
+			attrs = append(attrs, a)
+		}
+		child.start.Attr = attrs
+		child.stripEditorData(namespaces)
+		if name.Space == "" && name.Local == "defs" && !child.hasElements() {
+			kept = dropIndent(kept)
+			continue
+		}
+		kept = append(kept, child)
+	}
+	n.children = kept
+}
+
+// dropIndent removes the whitespace before an element that is being
+// dropped, so stripping does not leave blank lines behind.
+func dropIndent(nodes []*svgNode) []*svgNode {
+	if n := len(nodes); n > 0 && nodes[n-1].start == nil && nodes[n-1].comment == nil && len(bytes.TrimSpace(nodes[n-1].text)) == 0 {
+		return nodes[:n-1]
+	}
+	return nodes
+}
+
+// hasElements reports whether n has element children.
+func (n *svgNode) hasElements() bool {
+	for _, child := range n.children {
+		if child.start != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// usedPrefixes adds the namespace prefixes used by n's descendants to used.
+func (n *svgNode) usedPrefixes(used map[string]bool) {
+	for _, child := range n.children {
+		if child.start == nil {
+			continue
+		}
+		if child.start.Name.Space != "" {
+			used[child.start.Name.Space] = true
+		}
+		for _, a := range child.start.Attr {
+			if a.Name.Space != "" && a.Name.Space != "xmlns" {
+				used[a.Name.Space] = true
+			}
+		}
+		child.usedPrefixes(used)
+	}
+}
+
+// writeChildren writes n's children as XML, elements without children
+// self-closed. Stylesheets and scripts are written as CDATA so they stay
+// readable.
+func (n *svgNode) writeChildren(buf *bytes.Buffer) {
+	cdata := n.start != nil && n.start.Name.Space == "" && (n.start.Name.Local == "style" || n.start.Name.Local == "script")
+	for _, child := range n.children {
+		switch {
+		case child.start != nil:
+			buf.WriteByte('<')
+			buf.WriteString(qualifiedName(child.start.Name))
+			for _, a := range child.start.Attr {
+				buf.WriteByte(' ')
+				buf.WriteString(qualifiedName(a.Name))
+				buf.WriteString(`="`)
+				buf.WriteString(attrEscaper.Replace(a.Value))
+				buf.WriteByte('"')
+			}
+			if len(child.children) == 0 {
+				buf.WriteString("/>")
+				continue
+			}
+			buf.WriteByte('>')
+			child.writeChildren(buf)
+			buf.WriteString("</")
+			buf.WriteString(qualifiedName(child.start.Name))
+			buf.WriteByte('>')
+		case child.comment != nil:
+			buf.WriteString("<!--")
+			buf.Write(child.comment)
+			buf.WriteString("-->")
+		case cdata && strings.ContainsAny(string(child.text), "<>&") && !bytes.Contains(child.text, []byte("]]>")):
+			buf.WriteString("<![CDATA[")
+			buf.Write(child.text)
+			buf.WriteString("]]>")
+		default:
+			buf.WriteString(textEscaper.Replace(string(child.text)))
+		}
+	}
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// parseLength parses an SVG length such as "200", "200px" or "12.5pt",
+// ignoring the unit. It returns 0 for lengths it cannot parse, like "100%".
+func parseLength(s string) float64 {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		return 0
+	}
+	end := len(s)
+	for end > 0 && (s[end-1] < '0' || s[end-1] > '9') && s[end-1] != '.' {
+		end--
+	}
+	v, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// namespaceDeclarations returns the xmlns attributes declaring the used
+// prefixes, sorted by prefix.
+func namespaceDeclarations(namespaces map[string]string, used map[string]bool) string {
+	var prefixes []string
+	for prefix := range used {
+		if prefix == "xml" || namespaces[prefix] == "" {
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	var sb strings.Builder
+	for _, prefix := range prefixes {
+		fmt.Fprintf(&sb, ` xmlns:%s="%s"`, prefix, attrEscaper.Replace(namespaces[prefix]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFile is the name of the manifest aggregation writes at the root of
+// its output directory.
+const ManifestFile = "manifest.json"
+
+// HashFiles records the sha256 of every file under dir (the aggregate output
+// directory) in m.Files, keyed by slash-separated path relative to dir, and
+// sets each section's SHA256 from it. The manifest itself is not listed.
+func (m *Manifest) HashFiles(dir string) error {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ManifestFile || strings.HasPrefix(filepath.Base(rel), "."+ManifestFile+".tmp-") {
+			return nil
+		}
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = sum
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", dir, err)
+	}
+	m.Files = files
+
+	for i := range m.Packages {
+		for j := range m.Packages[i].Sections {
+			s := &m.Packages[i].Sections[j]
+			s.SHA256 = files[strings.TrimPrefix(s.Path, "./")]
+		}
+	}
+	return nil
+}
+
+// ComputeDigest returns the digest of the manifest: the sha256 of its JSON
+// encoding with Digest left empty. Since Files lists every output file's
+// hash, the digest covers the whole build.
+func (m *Manifest) ComputeDigest() (string, error) {
+	c := *m
+	c.Digest = ""
+	data, err := json.MarshalIndent(&c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// Verify checks the manifest against its digest and, when it lists file
+// hashes, against the files under dir. It returns one problem per mismatch:
+// a manifest edited after it was written, and files missing, truncated or
+// changed; files added since are not problems.
+func (m *Manifest) Verify(dir string) ([]string, error) {
+	var problems []string
+	if m.Digest != "" {
+		digest, err := m.ComputeDigest()
+		if err != nil {
+			return nil, err
+		}
+		if digest != m.Digest {
+			problems = append(problems, fmt.Sprintf("manifest digest mismatch: recorded %s, computed %s", m.Digest, digest))
+		}
+	}
+
+	paths := make([]string, 0, len(m.Files))
+	for p := range m.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		sum, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(p)))
+		if errors.Is(err, os.ErrNotExist) {
+			problems = append(problems, fmt.Sprintf("%s: missing", p))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sum != m.Files[p] {
+			problems = append(problems, fmt.Sprintf("%s: checksum mismatch", p))
+		}
+	}
+	return problems, nil
+}
+
+// FileDiff lists the files that differ between two builds.
+type FileDiff struct {
+	Added   []string `json:"added"`
+	Changed []string `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// Empty reports whether the builds have the same files.
+func (d FileDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// DiffFiles compares the file hashes of m with those of prev, the manifest
+// of the previous build, for incremental deploys. Paths are sorted.
+func (m *Manifest) DiffFiles(prev *Manifest) FileDiff {
+	d := FileDiff{Added: []string{}, Changed: []string{}, Removed: []string{}}
+	for p, sum := range m.Files {
+		old, ok := prev.Files[p]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, p)
+		case old != sum:
+			d.Changed = append(d.Changed, p)
+		}
+	}
+	for p := range prev.Files {
+		if _, ok := m.Files[p]; !ok {
+			d.Removed = append(d.Removed, p)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Changed)
+	sort.Strings(d.Removed)
+	return d
+}
+
+// fileSHA256 returns the hex sha256 of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // path under the output directory
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck // read-only
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,132 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	sumHello = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	sumWorld = "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHashFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pkg/overview.md": "hello",
+		"pkg/usage.md":    "world",
+		ManifestFile:      "{}",
+	})
+	m := &Manifest{Packages: []PackageManifest{{Name: "pkg", Sections: []SectionManifest{
+		{Name: "overview", Path: "./pkg/overview.md"},
+		{Name: "gone", Path: "./pkg/gone.md"},
+	}}}}
+	if err := m.HashFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"pkg/overview.md": sumHello, "pkg/usage.md": sumWorld}; !reflect.DeepEqual(m.Files, want) {
+		t.Errorf("files = %v, want %v", m.Files, want)
+	}
+	if got := []string{m.Packages[0].Sections[0].SHA256, m.Packages[0].Sections[1].SHA256}; !reflect.DeepEqual(got, []string{sumHello, ""}) {
+		t.Errorf("section hashes = %q", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cases := []struct {
+		name string
+		edit func(t *testing.T, dir string, m *Manifest)
+		want []string
+	}{
+		{
+			name: "unchanged",
+			edit: func(*testing.T, string, *Manifest) {},
+		},
+		{
+			name: "file added",
+			edit: func(t *testing.T, dir string, _ *Manifest) {
+				writeFiles(t, dir, map[string]string{"b/new.md": "new"})
+			},
+		},
+		{
+			name: "file changed",
+			edit: func(t *testing.T, dir string, _ *Manifest) {
+				writeFiles(t, dir, map[string]string{"a/overview.md": "hello!"})
+			},
+			want: []string{"a/overview.md: checksum mismatch"},
+		},
+		{
+			name: "file removed",
+			edit: func(t *testing.T, dir string, _ *Manifest) {
+				if err := os.Remove(filepath.Join(dir, "a", "usage.md")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"a/usage.md: missing"},
+		},
+		{
+			name: "manifest edited",
+			edit: func(_ *testing.T, _ string, m *Manifest) { m.Mode = "prod" },
+			want: []string{"manifest digest mismatch"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"a/overview.md": "hello", "a/usage.md": "world"})
+			m := &Manifest{Mode: "dev"}
+			if err := m.HashFiles(dir); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Save(filepath.Join(dir, ManifestFile)); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := Load(filepath.Join(dir, ManifestFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.edit(t, dir, loaded)
+
+			problems, err := loaded.Verify(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(problems) != len(c.want) {
+				t.Fatalf("problems = %q, want %q", problems, c.want)
+			}
+			for i, p := range problems {
+				if !strings.HasPrefix(p, c.want[i]) {
+					t.Errorf("problem %d = %q, want %q", i, p, c.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	prev := &Manifest{Files: map[string]string{"a.md": "1", "b.md": "2", "c.md": "3"}}
+	cur := &Manifest{Files: map[string]string{"a.md": "1", "b.md": "2'", "d.md": "4"}}
+	want := FileDiff{Added: []string{"d.md"}, Changed: []string{"b.md"}, Removed: []string{"c.md"}}
+	if got := cur.DiffFiles(prev); !reflect.DeepEqual(got, want) {
+		t.Errorf("diff = %+v, want %+v", got, want)
+	}
+	if d := cur.DiffFiles(cur); !d.Empty() {
+		t.Errorf("diff with itself = %+v, want empty", d)
+	}
+}
@@ -3,15 +3,49 @@ package manifest
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
+	"sort"
 	"time"
+
+	"github.com/grovetools/docgen/internal/fsutil"
+	"github.com/grovetools/docgen/pkg/changelog"
 )
 
+// SplitPagesFile lists the pages of a section split into multiple files
+// (e.g. tui_keymaps with split: per_tui) as a JSON array of SectionManifest,
+// so aggregation can add a manifest entry for each page. It is named apart
+// from ManifestFile, the aggregate manifest, which has another schema.
+const SplitPagesFile = "pages.json"
+
+// TranslationsFile is the state file docgen translate writes in each
+// docs/{lang}/ directory. It maps section outputs to the source hash they
+// were translated from, and marks the directory as a translation.
+const TranslationsFile = ".translations.json"
+
 // Manifest represents the complete documentation manifest for all packages
 type Manifest struct {
 	Packages        []PackageManifest `json:"packages"`
 	WebsiteSections []WebsiteSection  `json:"website_sections,omitempty"`
 	Sidebar         *SidebarConfig    `json:"sidebar,omitempty"`
 	GeneratedAt     time.Time         `json:"generated_at"`
+	// Mode is the build mode, dev or prod, the manifest was aggregated in.
+	// Every package and website section in it is published in that mode.
+	Mode string `json:"mode,omitempty"`
+	// Profile is the output profile, internal or public, the manifest was
+	// aggregated with. A public manifest has no internal sections.
+	Profile string `json:"profile,omitempty"`
+	// Audiences are the audiences the manifest was aggregated for; none
+	// means every audience. Each section records its own audience.
+	Audiences []string `json:"audiences,omitempty"`
+	// Nav is the navigation tree (categories, packages, sections and their
+	// headings) for sidebars and "on this page" widgets; see BuildNav.
+	Nav []NavNode `json:"nav,omitempty"`
+	// Files maps every file of the build, by path relative to the output
+	// directory, to its sha256 (see HashFiles).
+	Files map[string]string `json:"files,omitempty"`
+	// Digest is the sha256 of the manifest itself, set by Save (see
+	// ComputeDigest).
+	Digest string `json:"digest,omitempty"`
 }
 
 // SidebarConfig defines the sidebar ordering and display configuration for the website.
@@ -46,16 +80,18 @@ type WebsiteSection struct {
 
 // PackageManifest represents documentation manifest for a single package
 type PackageManifest struct {
-	Name          string            `json:"name"`
-	Title         string            `json:"title"`
-	Description   string            `json:"description"`
-	Category      string            `json:"category"`
-	DocsPath      string            `json:"docs_path"`
-	Version       string            `json:"version"`
-	RepoURL       string            `json:"repo_url,omitempty"`
-	ChangelogPath string            `json:"changelog_path,omitempty"`
-	TocDepth      int               `json:"toc_depth,omitempty"`
-	Sections      []SectionManifest `json:"sections"`
+	Name          string              `json:"name"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	Category      string              `json:"category"`
+	DocsPath      string              `json:"docs_path"`
+	Version       string              `json:"version"`
+	RepoURL       string              `json:"repo_url,omitempty"`
+	ChangelogPath string              `json:"changelog_path,omitempty"`
+	Releases      []changelog.Release `json:"releases,omitempty"` // Parsed from the changelog, newest first; see changelog.Parse
+	TocDepth      int                 `json:"toc_depth,omitempty"`
+	Languages     []string            `json:"languages,omitempty"` // Translations available under {docs_path}/{lang}/
+	Sections      []SectionManifest   `json:"sections"`
 }
 
 // SectionManifest represents a single documentation section
@@ -66,13 +102,47 @@ type SectionManifest struct {
 	Path     string    `json:"path"`
 	JSONKey  string    `json:"json_key,omitempty"`
 	Modified time.Time `json:"modified"`
+	SHA256   string    `json:"sha256,omitempty"`   // Of the section's file; see HashFiles
+	Anchors  []string  `json:"anchors,omitempty"`  // Deep-link targets in the section; see RecordAnchors
+	Status   string    `json:"status,omitempty"`   // Publication status of the section: dev or production
+	Audience []string  `json:"audience,omitempty"` // Audiences the section is for; none means every audience
+
+	// Placeholder marks a page published in place of a doc not generated yet
+	Placeholder bool `json:"placeholder,omitempty"`
+}
+
+// Languages returns the languages translated into docsDir, i.e. the
+// subdirectories holding a TranslationsFile, sorted.
+func Languages(docsDir string) []string {
+	entries, err := os.ReadDir(docsDir)
+	if err != nil {
+		return nil
+	}
+	var langs []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(docsDir, e.Name(), TranslationsFile)); err == nil {
+			langs = append(langs, e.Name())
+		}
+	}
+	sort.Strings(langs)
+	return langs
 }
 
-// Save saves the manifest to a JSON file
+// Save saves the manifest to a JSON file, first updating its Digest. The
+// file is replaced atomically, so a site build reading it alongside a watch
+// rebuild never sees half of it.
 func (m *Manifest) Save(path string) error {
+	digest, err := m.ComputeDigest()
+	if err != nil {
+		return err
+	}
+	m.Digest = digest
 	data, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o644) //nolint:gosec // internal doc tool output
+	return fsutil.WriteFileAtomic(path, data, 0o644)
 }
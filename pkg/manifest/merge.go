@@ -0,0 +1,140 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Load reads a manifest written by Save. A missing file is reported with an
+// error satisfying errors.Is(err, os.ErrNotExist).
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // manifest path from the caller
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Merge patches m with the manifest of a partial build. rebuilt names the
+// packages the partial build processed: each is replaced by its entry in
+// partial, in place, or removed when partial has none (it has no docs in this
+// mode any more). Packages of partial not yet in m are appended; all other
+// packages are kept. Website sections of partial replace those of the same
+// name, partial's sidebar replaces m's when set, and GeneratedAt and Mode
+// are taken from partial.
+func (m *Manifest) Merge(partial *Manifest, rebuilt []string) {
+	for _, name := range rebuilt {
+		if partial.Package(name) == nil {
+			m.RemovePackage(name)
+		}
+	}
+	for _, p := range partial.Packages {
+		m.SetPackage(p)
+	}
+	for _, s := range partial.WebsiteSections {
+		m.SetWebsiteSection(s)
+	}
+	if partial.Sidebar != nil {
+		m.Sidebar = partial.Sidebar
+	}
+	if !partial.GeneratedAt.IsZero() {
+		m.GeneratedAt = partial.GeneratedAt
+	}
+	if partial.Mode != "" {
+		m.Mode = partial.Mode
+	}
+}
+
+// Package returns the entry of the named package, or nil.
+func (m *Manifest) Package(name string) *PackageManifest {
+	for i := range m.Packages {
+		if m.Packages[i].Name == name {
+			return &m.Packages[i]
+		}
+	}
+	return nil
+}
+
+// SetPackage replaces the entry of p's package, or appends p.
+func (m *Manifest) SetPackage(p PackageManifest) {
+	if existing := m.Package(p.Name); existing != nil {
+		*existing = p
+		return
+	}
+	m.Packages = append(m.Packages, p)
+}
+
+// PatchPackage updates the entry of p's package with the fields p sets,
+// keeping the existing values of those it leaves empty (a watch rebuild, for
+// one, knows nothing of the changelog or translations). Sections are
+// replaced when p has any. Unknown packages are appended.
+func (m *Manifest) PatchPackage(p PackageManifest) {
+	existing := m.Package(p.Name)
+	if existing == nil {
+		m.Packages = append(m.Packages, p)
+		return
+	}
+	patch := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	patch(&existing.Title, p.Title)
+	patch(&existing.Description, p.Description)
+	patch(&existing.Category, p.Category)
+	patch(&existing.DocsPath, p.DocsPath)
+	patch(&existing.Version, p.Version)
+	patch(&existing.RepoURL, p.RepoURL)
+	patch(&existing.ChangelogPath, p.ChangelogPath)
+	if p.TocDepth != 0 {
+		existing.TocDepth = p.TocDepth
+	}
+	if p.Languages != nil {
+		existing.Languages = p.Languages
+	}
+	if p.Releases != nil {
+		existing.Releases = p.Releases
+	}
+	if len(p.Sections) > 0 {
+		existing.Sections = p.Sections
+	}
+}
+
+// RemovePackage removes the entry of the named package and reports whether
+// there was one.
+func (m *Manifest) RemovePackage(name string) bool {
+	for i := range m.Packages {
+		if m.Packages[i].Name == name {
+			m.Packages = append(m.Packages[:i], m.Packages[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SetWebsiteSection replaces the website section of s's name, or appends s.
+func (m *Manifest) SetWebsiteSection(s WebsiteSection) {
+	for i := range m.WebsiteSections {
+		if m.WebsiteSections[i].Name == s.Name {
+			m.WebsiteSections[i] = s
+			return
+		}
+	}
+	m.WebsiteSections = append(m.WebsiteSections, s)
+}
+
+// FileModTime returns the modification time of path, or the zero time when
+// it cannot be read, for SectionManifest.Modified.
+func FileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
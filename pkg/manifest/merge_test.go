@@ -0,0 +1,144 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	base := func() *Manifest {
+		return &Manifest{
+			Packages: []PackageManifest{
+				{Name: "a", Title: "A"},
+				{Name: "b", Title: "B"},
+				{Name: "c", Title: "C"},
+			},
+			WebsiteSections: []WebsiteSection{{Name: "overview", Title: "Overview"}},
+			Mode:            "dev",
+		}
+	}
+	cases := []struct {
+		name         string
+		partial      *Manifest
+		rebuilt      []string
+		wantPackages []string // name:title, in order
+		wantSections []string
+		wantMode     string
+		wantAt       time.Time
+	}{
+		{
+			name:         "rebuilt package replaced in place",
+			partial:      &Manifest{Packages: []PackageManifest{{Name: "b", Title: "B2"}}},
+			rebuilt:      []string{"b"},
+			wantPackages: []string{"a:A", "b:B2", "c:C"},
+			wantSections: []string{"overview:Overview"},
+			wantMode:     "dev",
+		},
+		{
+			name:         "rebuilt package without docs removed",
+			partial:      &Manifest{},
+			rebuilt:      []string{"a"},
+			wantPackages: []string{"b:B", "c:C"},
+			wantSections: []string{"overview:Overview"},
+			wantMode:     "dev",
+		},
+		{
+			name:         "new package appended",
+			partial:      &Manifest{Packages: []PackageManifest{{Name: "d", Title: "D"}}},
+			rebuilt:      []string{"d"},
+			wantPackages: []string{"a:A", "b:B", "c:C", "d:D"},
+			wantSections: []string{"overview:Overview"},
+			wantMode:     "dev",
+		},
+		{
+			name: "sections, mode and time taken from partial",
+			partial: &Manifest{
+				WebsiteSections: []WebsiteSection{{Name: "overview", Title: "Intro"}, {Name: "concepts", Title: "Concepts"}},
+				Mode:            "prod",
+				GeneratedAt:     at,
+			},
+			wantPackages: []string{"a:A", "b:B", "c:C"},
+			wantSections: []string{"overview:Intro", "concepts:Concepts"},
+			wantMode:     "prod",
+			wantAt:       at,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := base()
+			m.Merge(c.partial, c.rebuilt)
+			var packages, sections []string
+			for _, p := range m.Packages {
+				packages = append(packages, p.Name+":"+p.Title)
+			}
+			for _, s := range m.WebsiteSections {
+				sections = append(sections, s.Name+":"+s.Title)
+			}
+			if !reflect.DeepEqual(packages, c.wantPackages) {
+				t.Errorf("packages = %q, want %q", packages, c.wantPackages)
+			}
+			if !reflect.DeepEqual(sections, c.wantSections) {
+				t.Errorf("website sections = %q, want %q", sections, c.wantSections)
+			}
+			if m.Mode != c.wantMode || !m.GeneratedAt.Equal(c.wantAt) {
+				t.Errorf("mode %q at %v, want %q at %v", m.Mode, m.GeneratedAt, c.wantMode, c.wantAt)
+			}
+		})
+	}
+}
+
+func TestPatchPackage(t *testing.T) {
+	existing := PackageManifest{
+		Name:          "a",
+		Title:         "A",
+		Description:   "The a package",
+		Version:       "v1.0.0",
+		ChangelogPath: "a/CHANGELOG.md",
+		TocDepth:      3,
+		Languages:     []string{"fr"},
+		Sections:      []SectionManifest{{Name: "overview"}},
+	}
+	cases := []struct {
+		name  string
+		patch PackageManifest
+		want  PackageManifest
+	}{
+		{
+			name:  "empty fields kept",
+			patch: PackageManifest{Name: "a", Title: "A2"},
+			want: PackageManifest{
+				Name: "a", Title: "A2", Description: "The a package", Version: "v1.0.0",
+				ChangelogPath: "a/CHANGELOG.md", TocDepth: 3, Languages: []string{"fr"},
+				Sections: []SectionManifest{{Name: "overview"}},
+			},
+		},
+		{
+			name:  "set fields and sections replaced",
+			patch: PackageManifest{Name: "a", Version: "v1.1.0", TocDepth: 2, Languages: []string{}, Sections: []SectionManifest{{Name: "usage"}}},
+			want: PackageManifest{
+				Name: "a", Title: "A", Description: "The a package", Version: "v1.1.0",
+				ChangelogPath: "a/CHANGELOG.md", TocDepth: 2, Languages: []string{},
+				Sections: []SectionManifest{{Name: "usage"}},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &Manifest{Packages: []PackageManifest{existing}}
+			m.PatchPackage(c.patch)
+			if len(m.Packages) != 1 || !reflect.DeepEqual(m.Packages[0], c.want) {
+				t.Errorf("packages = %+v, want %+v", m.Packages, c.want)
+			}
+		})
+	}
+
+	t.Run("unknown package appended", func(t *testing.T) {
+		m := &Manifest{Packages: []PackageManifest{existing}}
+		m.PatchPackage(PackageManifest{Name: "b"})
+		if len(m.Packages) != 2 || m.Packages[1].Name != "b" {
+			t.Errorf("packages = %+v, want b appended", m.Packages)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/transformer"
+)
+
+// Navigation node types.
+const (
+	NavCategory = "category"
+	NavPackage  = "package"
+	NavSection  = "section"
+	NavHeading  = "heading"
+)
+
+// defaultNavDepth is the deepest heading level in the navigation tree when a
+// package sets no toc_depth, matching Starlight's "On this page".
+const defaultNavDepth = 3
+
+// NavNode is a node of the navigation tree: categories hold packages,
+// packages hold sections, and sections hold their headings.
+type NavNode struct {
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Name     string    `json:"name,omitempty"`   // Package name
+	Path     string    `json:"path,omitempty"`   // Section file, as in SectionManifest.Path
+	Anchor   string    `json:"anchor,omitempty"` // Heading slug
+	Level    int       `json:"level,omitempty"`  // Heading level
+	Icon     string    `json:"icon,omitempty"`
+	Children []NavNode `json:"children,omitempty"`
+}
+
+// BuildNav sets m.Nav from the manifest's packages and sidebar and the
+// headings of the section files under dir (the aggregate output directory).
+// Categories follow sidebar.category_order, then the rest by name; packages
+// follow their sidebar category's packages list, then the rest by name.
+// Headings from level 2 down to the package's toc_depth (default 3) are
+// nested under their parent headings.
+func (m *Manifest) BuildNav(dir string) {
+	byCategory := make(map[string][]PackageManifest)
+	for _, p := range m.Packages {
+		cat := p.Category
+		if m.Sidebar != nil {
+			if override, ok := m.Sidebar.PackageCategoryOverride[p.Name]; ok {
+				cat = override
+			}
+		}
+		if cat == "" {
+			cat = "Other"
+		}
+		byCategory[cat] = append(byCategory[cat], p)
+	}
+
+	var nav []NavNode
+	for _, cat := range m.navCategoryOrder(byCategory) {
+		node := NavNode{Type: NavCategory, Title: cat}
+		if m.Sidebar != nil {
+			node.Icon = m.Sidebar.Categories[cat].Icon
+		}
+		for _, p := range m.navPackageOrder(cat, byCategory[cat]) {
+			node.Children = append(node.Children, packageNav(p, dir, m.Sidebar))
+		}
+		nav = append(nav, node)
+	}
+	m.Nav = nav
+}
+
+// navCategoryOrder returns the categories in sidebar order.
+func (m *Manifest) navCategoryOrder(byCategory map[string][]PackageManifest) []string {
+	var order []string
+	seen := make(map[string]bool)
+	if m.Sidebar != nil {
+		for _, cat := range m.Sidebar.CategoryOrder {
+			if _, ok := byCategory[cat]; ok && !seen[cat] {
+				order = append(order, cat)
+				seen[cat] = true
+			}
+		}
+	}
+	var rest []string
+	for cat := range byCategory {
+		if !seen[cat] {
+			rest = append(rest, cat)
+		}
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
+
+// navPackageOrder returns a category's packages in sidebar order.
+func (m *Manifest) navPackageOrder(cat string, pkgs []PackageManifest) []PackageManifest {
+	rank := make(map[string]int)
+	if m.Sidebar != nil {
+		for i, name := range m.Sidebar.Categories[cat].Packages {
+			rank[name] = i + 1
+		}
+	}
+	sorted := append([]PackageManifest{}, pkgs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank[sorted[i].Name], rank[sorted[j].Name]
+		switch {
+		case ri > 0 && rj > 0:
+			return ri < rj
+		case ri > 0 || rj > 0:
+			return ri > 0
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// packageNav builds a package's node with its sections and their headings.
+func packageNav(p PackageManifest, dir string, sidebar *SidebarConfig) NavNode {
+	node := NavNode{Type: NavPackage, Title: p.Title, Name: p.Name}
+	if node.Title == "" {
+		node.Title = p.Name
+	}
+	if sidebar != nil {
+		node.Icon = sidebar.Packages[p.Name].Icon
+	}
+	depth := p.TocDepth
+	if depth <= 0 {
+		depth = defaultNavDepth
+	}
+	for _, s := range p.Sections {
+		section := NavNode{Type: NavSection, Title: s.Title, Path: s.Path}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(s.Path, "./")))) //nolint:gosec // path from the manifest
+		if err == nil {
+			section.Children = headingNav(transformer.ExtractHeadings(data), depth)
+		}
+		node.Children = append(node.Children, section)
+	}
+	return node
+}
+
+// headingNav nests headings of levels 2 to depth under their parents.
+func headingNav(headings []transformer.Heading, depth int) []NavNode {
+	var roots []NavNode
+	// stack holds the open headings, outermost first
+	var stack []*NavNode
+	for _, h := range headings {
+		if h.Level < 2 || h.Level > depth {
+			continue
+		}
+		node := NavNode{Type: NavHeading, Title: h.Text, Anchor: h.Slug, Level: h.Level}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+			stack = append(stack, &roots[len(roots)-1])
+			continue
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, &parent.Children[len(parent.Children)-1])
+	}
+	return roots
+}
+
+// RecordAnchors sets the Anchors of every package and website section from
+// its file under dir: the slugs of its headings and the ids of its HTML
+// elements, so external tools can deep-link into a page and check their
+// links against a build.
+func (m *Manifest) RecordAnchors(dir string) {
+	record := func(sections []SectionManifest) {
+		for i := range sections {
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(sections[i].Path, "./")))) //nolint:gosec // path from the manifest
+			if err == nil && !strings.HasSuffix(sections[i].Path, ".json") {
+				sections[i].Anchors = transformer.Anchors(data)
+			}
+		}
+	}
+	for i := range m.Packages {
+		record(m.Packages[i].Sections)
+	}
+	for i := range m.WebsiteSections {
+		record(m.WebsiteSections[i].Files)
+	}
+}
@@ -0,0 +1,179 @@
+// Package ollama is a client of the Ollama API, which serves local models.
+// docgen routes models named ollama/<model> to it, so docs can be drafted
+// without a network connection or a provider API key.
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultHost is the address of a local Ollama server.
+const DefaultHost = "http://localhost:11434"
+
+// Prefix marks the models served by Ollama, as in ollama/llama3.1:8b.
+const Prefix = "ollama/"
+
+// IsModel reports whether model is served by Ollama.
+func IsModel(model string) bool {
+	return strings.HasPrefix(model, Prefix)
+}
+
+// ModelName returns the Ollama name of model, without the ollama/ prefix.
+func ModelName(model string) string {
+	return strings.TrimPrefix(model, Prefix)
+}
+
+// Client makes requests to an Ollama server.
+type Client struct {
+	host string
+	http *http.Client
+}
+
+// New returns a client of the server at host; an empty host is $OLLAMA_HOST
+// or DefaultHost. Requests are bounded by their context only, since local
+// models can take minutes to respond.
+func New(host string) *Client {
+	if host == "" {
+		host = os.Getenv("OLLAMA_HOST")
+	}
+	if host == "" {
+		host = DefaultHost
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return &Client{host: strings.TrimRight(host, "/"), http: &http.Client{}}
+}
+
+// Host returns the address of the server.
+func (c *Client) Host() string {
+	return c.host
+}
+
+// Options are the generation parameters of a request. Nil and zero values
+// leave the model's defaults.
+type Options struct {
+	NumCtx      int      `json:"num_ctx,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+	TopK        *int32   `json:"top_k,omitempty"`
+	NumPredict  *int32   `json:"num_predict,omitempty"`
+}
+
+// Response is the outcome of a request.
+type Response struct {
+	Text         string
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// Version returns the version of the server, which checks it is reachable.
+func (c *Client) Version(ctx context.Context) (string, error) {
+	var resp struct {
+		Version string `json:"version"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/version", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Version, nil
+}
+
+// ContextWindow returns the context window of model in tokens: the num_ctx
+// parameter of its Modelfile when it sets one, otherwise the context length
+// the model was trained with.
+func (c *Client) ContextWindow(ctx context.Context, model string) (int, error) {
+	var resp struct {
+		Parameters string         `json:"parameters"`
+		ModelInfo  map[string]any `json:"model_info"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/show", map[string]string{"model": model}, &resp); err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(resp.Parameters, "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "num_ctx" {
+			if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+				return n, nil
+			}
+		}
+	}
+	for key, v := range resp.ModelInfo {
+		if !strings.HasSuffix(key, ".context_length") {
+			continue
+		}
+		if n, ok := v.(float64); ok && n > 0 {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("ollama does not report the context length of %s", model)
+}
+
+// Generate sends prompt to model and returns its complete response.
+func (c *Client) Generate(ctx context.Context, model, prompt string, opts Options) (*Response, error) {
+	req := struct {
+		Model   string  `json:"model"`
+		Prompt  string  `json:"prompt"`
+		Stream  bool    `json:"stream"`
+		Options Options `json:"options"`
+	}{Model: model, Prompt: prompt, Options: opts}
+	var resp struct {
+		Response        string `json:"response"`
+		DoneReason      string `json:"done_reason"`
+		PromptEvalCount int64  `json:"prompt_eval_count"`
+		EvalCount       int64  `json:"eval_count"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/generate", req, &resp); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(resp.Response) == "" {
+		return nil, fmt.Errorf("ollama returned an empty response (done reason %q)", resp.DoneReason)
+	}
+	return &Response{Text: resp.Response, InputTokens: resp.PromptEvalCount, OutputTokens: resp.EvalCount}, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.host+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("ollama is not reachable at %s: %w", c.host, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response of %s: %w", path, err)
+	}
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			return fmt.Errorf("ollama %s: %s", path, e.Error)
+		}
+		return fmt.Errorf("ollama %s: %s", path, resp.Status)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package ollama
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewHost(t *testing.T) {
+	cases := []struct {
+		host, env, want string
+	}{
+		{"", "", DefaultHost},
+		{"", "gpu-box:11434", "http://gpu-box:11434"},
+		{"https://ollama.internal/", "gpu-box:11434", "https://ollama.internal"},
+		{"127.0.0.1:9999", "", "http://127.0.0.1:9999"},
+	}
+	for _, c := range cases {
+		t.Setenv("OLLAMA_HOST", c.env)
+		if got := New(c.host).Host(); got != c.want {
+			t.Errorf("New(%q) with OLLAMA_HOST=%q has host %q, want %q", c.host, c.env, got, c.want)
+		}
+	}
+}
+
+func TestModelName(t *testing.T) {
+	if !IsModel("ollama/llama3.1:8b") || IsModel("claude-sonnet-4") {
+		t.Error("IsModel does not go by the ollama/ prefix")
+	}
+	if got := ModelName("ollama/llama3.1:8b"); got != "llama3.1:8b" {
+		t.Errorf("ModelName() = %q", got)
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	cases := []struct {
+		name string
+		show string
+		want int
+		err  string
+	}{
+		{"modelfile num_ctx", `{"parameters": "stop \"<|eot|>\"\nnum_ctx 16384\ntemperature 0.7", "model_info": {"llama.context_length": 131072}}`, 16384, ""},
+		{"trained length", `{"parameters": "temperature 0.7", "model_info": {"general.architecture": "qwen2", "qwen2.context_length": 32768}}`, 32768, ""},
+		{"unknown", `{"model_info": {"general.architecture": "x"}}`, 0, "does not report the context length"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]string
+				if r.URL.Path != "/api/show" || json.NewDecoder(r.Body).Decode(&req) != nil || req["model"] != "llama-test" {
+					http.Error(w, `{"error": "bad request"}`, http.StatusBadRequest)
+					return
+				}
+				_, _ = io.WriteString(w, c.show)
+			}))
+			defer srv.Close()
+
+			got, err := New(srv.URL).ContextWindow(t.Context(), "llama-test")
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("ContextWindow() = %d, %v; want an error with %q", got, err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("ContextWindow() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var sent map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Error(err)
+		}
+		switch sent["prompt"] {
+		case "empty":
+			_, _ = io.WriteString(w, `{"response": "  ", "done_reason": "length"}`)
+		case "missing":
+			http.Error(w, `{"error": "model \"nope\" not found, try pulling it first"}`, http.StatusNotFound)
+		default:
+			_, _ = io.WriteString(w, `{"response": "Hello", "prompt_eval_count": 12, "eval_count": 3}`)
+		}
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+
+	temp := float32(0.5)
+	resp, err := c.Generate(t.Context(), "llama-test", "hi", Options{NumCtx: 4096, Temperature: &temp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *resp != (Response{Text: "Hello", InputTokens: 12, OutputTokens: 3}) {
+		t.Errorf("Generate() = %+v", resp)
+	}
+	if sent["model"] != "llama-test" || sent["stream"] != false {
+		t.Errorf("request = %v, want a non-streaming request for llama-test", sent)
+	}
+	if opts, _ := sent["options"].(map[string]any); opts["num_ctx"] != float64(4096) || opts["temperature"] != 0.5 || len(opts) != 2 {
+		t.Errorf("request options = %v", sent["options"])
+	}
+
+	if _, err := c.Generate(t.Context(), "llama-test", "empty", Options{}); err == nil || !strings.Contains(err.Error(), `done reason "length"`) {
+		t.Errorf("Generate() of an empty response = %v", err)
+	}
+	if _, err := c.Generate(t.Context(), "nope", "missing", Options{}); err == nil || !strings.Contains(err.Error(), "try pulling it first") {
+		t.Errorf("Generate() of a missing model = %v, want the server's error", err)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	host := srv.URL
+	srv.Close()
+	if _, err := New(host).Version(t.Context()); err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Errorf("Version() of a stopped server = %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+// Package progress shows a live status line for multi-section runs (generate
+// and aggregate) on a terminal: sections completed out of the total, the
+// section in progress and how many wait on a rate limit, elapsed time and an
+// ETA, and token throughput.
+//
+// Like report.Recorder, every method is a no-op on a nil *Tracker, and New
+// returns nil when the output is not a terminal, so runs piped to a file or
+// in CI log plainly with no checks in the instrumented code.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshInterval is how often the line is redrawn to keep the elapsed time
+// and ETA current while a section runs.
+const refreshInterval = 500 * time.Millisecond
+
+// barWidth is the width of the bar, in cells.
+const barWidth = 20
+
+// Tracker draws the status line of one run.
+type Tracker struct {
+	out   io.Writer
+	width func() int
+	title string
+	start time.Time
+
+	mu      sync.Mutex
+	total   int
+	done    int
+	failed  int
+	tokens  int64
+	waiting int     // Running tasks queued on a rate limit
+	running []*Task // In start order; the line shows the latest
+	drawn   bool
+	paused  bool
+	stopped bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Task is one section in progress. End it exactly once.
+type Task struct {
+	tracker *Tracker
+	name    string
+	failed  bool
+	waiting bool
+}
+
+// New returns a tracker drawing on out titled title (e.g. "Generating"), or
+// nil when out is not a terminal or TERM is dumb. Stop it when the run ends.
+func New(out *os.File, title string) *Tracker {
+	if !IsTerminal(out) || os.Getenv("TERM") == "dumb" {
+		return nil
+	}
+	t := &Tracker{
+		out:   out,
+		width: func() int { return terminalWidth(out) },
+		title: title,
+		start: time.Now(),
+		stop:  make(chan struct{}),
+	}
+	t.wg.Add(1)
+	go t.refresh()
+	return t
+}
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (t *Tracker) refresh() {
+	defer t.wg.Done()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.Redraw()
+		}
+	}
+}
+
+// AddTotal adds n sections to the run's total, as each package's sections
+// become known.
+func (t *Tracker) AddTotal(n int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total += n
+	t.drawLocked()
+}
+
+// Start marks a section as in progress.
+func (t *Tracker) Start(name string) *Task {
+	if t == nil {
+		return nil
+	}
+	task := &Task{tracker: t, name: name}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running = append(t.running, task)
+	t.drawLocked()
+	return task
+}
+
+// AddTokens adds LLM tokens (input and output) to the throughput.
+func (t *Tracker) AddTokens(n int64) {
+	if t == nil || n <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tokens += n
+}
+
+// Fail marks the task's section as failed; End still has to be called.
+func (k *Task) Fail() {
+	if k == nil {
+		return
+	}
+	k.tracker.mu.Lock()
+	defer k.tracker.mu.Unlock()
+	k.failed = true
+}
+
+// SetWaiting marks the task's section as queued on a provider rate limit,
+// or as running again.
+func (k *Task) SetWaiting(waiting bool) {
+	if k == nil {
+		return
+	}
+	t := k.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if k.waiting == waiting {
+		return
+	}
+	k.waiting = waiting
+	if waiting {
+		t.waiting++
+	} else {
+		t.waiting--
+	}
+	t.drawLocked()
+}
+
+// End marks the task's section as completed. Later calls do nothing.
+func (k *Task) End() {
+	if k == nil {
+		return
+	}
+	t := k.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, r := range t.running {
+		if r == k {
+			t.running = append(t.running[:i], t.running[i+1:]...)
+			t.done++
+			if k.waiting {
+				t.waiting--
+			}
+			if k.failed {
+				t.failed++
+			}
+			t.drawLocked()
+			return
+		}
+	}
+}
+
+// Stop erases the line and stops redrawing it.
+func (t *Tracker) Stop() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+		return
+	}
+	t.stopped = true
+	t.clearLocked()
+	t.mu.Unlock()
+	close(t.stop)
+	t.wg.Wait()
+}
+
+// Clear erases the line so other output can be written; Redraw draws it
+// again below that output.
+func (t *Tracker) Clear() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clearLocked()
+}
+
+// Redraw draws the line.
+func (t *Tracker) Redraw() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.drawLocked()
+}
+
+// Pause erases the line and keeps it hidden until Resume, while the run
+// reads input from the terminal.
+func (t *Tracker) Pause() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paused = true
+	t.clearLocked()
+}
+
+// Resume draws the line again after Pause.
+func (t *Tracker) Resume() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paused = false
+	t.drawLocked()
+}
+
+func (t *Tracker) clearLocked() {
+	if t.drawn {
+		fmt.Fprint(t.out, "\r\033[K")
+		t.drawn = false
+	}
+}
+
+func (t *Tracker) drawLocked() {
+	if t.stopped || t.paused {
+		return
+	}
+	line := t.line(time.Since(t.start))
+	if w := t.width(); w > 0 {
+		line = truncate(line, w-1)
+	}
+	fmt.Fprint(t.out, "\r\033[K"+line)
+	t.drawn = true
+}
+
+// line renders the status line after elapsed:
+//
+//	Generating [########------------] 3/8  api-reference  1m02s, ETA 1m43s  420 tok/s
+func (t *Tracker) line(elapsed time.Duration) string {
+	var sb strings.Builder
+	sb.WriteString(t.title)
+	sb.WriteString(" [")
+	filled := 0
+	if t.total > 0 {
+		filled = min(barWidth*t.done/t.total, barWidth)
+	}
+	sb.WriteString(strings.Repeat("#", filled))
+	sb.WriteString(strings.Repeat("-", barWidth-filled))
+	fmt.Fprintf(&sb, "] %d/%d", t.done, t.total)
+	if t.failed > 0 {
+		fmt.Fprintf(&sb, " (%d failed)", t.failed)
+	}
+	if n := len(t.running); n > 0 {
+		sb.WriteString("  " + t.running[n-1].name)
+		if n > 1 {
+			fmt.Fprintf(&sb, " +%d", n-1)
+		}
+		if t.waiting > 0 {
+			fmt.Fprintf(&sb, " (%d rate-limited)", t.waiting)
+		}
+	}
+	sb.WriteString("  " + formatDuration(elapsed))
+	if eta, ok := t.eta(elapsed); ok {
+		sb.WriteString(", ETA " + formatDuration(eta))
+	}
+	if t.tokens > 0 && elapsed >= time.Second {
+		fmt.Fprintf(&sb, "  %s tok/s", formatCount(float64(t.tokens)/elapsed.Seconds()))
+	}
+	return sb.String()
+}
+
+// eta estimates the time left from the average time of the sections done so
+// far.
+func (t *Tracker) eta(elapsed time.Duration) (time.Duration, bool) {
+	if t.done == 0 || t.done >= t.total {
+		return 0, false
+	}
+	perSection := elapsed / time.Duration(t.done)
+	return perSection * time.Duration(t.total-t.done), true
+}
+
+// formatDuration formats d to the second, e.g. 1m02s.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// formatCount formats a rate, e.g. 420 or 1.2k.
+func formatCount(n float64) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%.1fk", n/1000)
+	}
+	return fmt.Sprintf("%.0f", n)
+}
+
+// truncate cuts s to width runes.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width])
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package progress
+
+import "os"
+
+// terminalWidth returns 0: the width is unknown on this platform, so the
+// line is not truncated.
+func terminalWidth(*os.File) int {
+	return 0
+}
@@ -0,0 +1,18 @@
+//go:build linux || darwin
+
+package progress
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalWidth returns the width of the terminal f, or 0 when unknown.
+func terminalWidth(f *os.File) int {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ) //nolint:gosec // file descriptors fit in an int
+	if err != nil {
+		return 0
+	}
+	return int(ws.Col)
+}
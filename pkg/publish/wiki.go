@@ -0,0 +1,365 @@
+// Package publish pushes a package's generated docs to external doc hosts.
+package publish
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/grovetools/docgen/pkg/manifest"
+	"github.com/grovetools/docgen/pkg/transformer"
+	"github.com/sirupsen/logrus"
+)
+
+// Publisher publishes generated docs.
+type Publisher struct {
+	logger *logrus.Logger
+}
+
+// New creates a new Publisher instance.
+func New(logger *logrus.Logger) *Publisher {
+	return &Publisher{logger: logger}
+}
+
+// WikiOptions configures a wiki publish.
+type WikiOptions struct {
+	Remote  string // Wiki git remote (default: origin's URL with .wiki.git)
+	Dir     string // Directory to clone the wiki into (default: a temp dir, removed afterwards)
+	Message string // Commit message
+	NoPush  bool   // Commit locally but do not push
+	DryRun  bool   // Write the pages but do not commit or push (implies a kept Dir)
+}
+
+// WikiResult reports what a wiki publish wrote.
+type WikiResult struct {
+	Remote  string   `json:"remote"`
+	Dir     string   `json:"dir,omitempty"` // Set when the clone is kept
+	Pages   []string `json:"pages"`
+	Changed bool     `json:"changed"`
+	Pushed  bool     `json:"pushed"`
+}
+
+// WikiPage is one generated section mapped to a wiki page.
+type WikiPage struct {
+	Name    string // Wiki page name (file name without .md)
+	Section manifest.SectionManifest
+}
+
+var (
+	wikiFrontmatterRe = regexp.MustCompile(`(?s)\A---\n.*?\n---\n*`)
+	wikiLinkRe        = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)\s]+)\)`)
+	wikiUnsafeRe      = regexp.MustCompile(`[\\/:*?"<>|#%]+`)
+)
+
+// PackageManifest describes the package's generated markdown sections in
+// section order, in the shape aggregation writes to the docs manifest.
+// Section paths are absolute. Sections not generated yet and concat sections
+// (which repeat the others) are left out.
+func PackageManifest(packageDir string) (manifest.PackageManifest, error) {
+	targets, err := generator.ResolveSectionTargets(packageDir)
+	if err != nil {
+		return manifest.PackageManifest{}, err
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].Section.Order < targets[j].Section.Order
+	})
+
+	pkg := manifest.PackageManifest{Name: filepath.Base(packageDir), Title: filepath.Base(packageDir)}
+	if len(targets) > 0 {
+		cfg := targets[0].Config
+		if cfg.Title != "" {
+			pkg.Title = cfg.Title
+		}
+		pkg.Description = cfg.Description
+		pkg.Category = cfg.Category
+		pkg.TocDepth = cfg.Settings.TocDepth
+	}
+
+	for _, t := range targets {
+		if t.Section.Type == "concat" || !strings.HasSuffix(t.Section.Output, ".md") {
+			continue
+		}
+		sectionPath := filepath.Join(t.OutputDir, t.Section.Output)
+		info, err := os.Stat(sectionPath)
+		if err != nil {
+			continue
+		}
+		title := t.Section.Title
+		if title == "" {
+			title = t.Section.Name
+		}
+		pkg.Sections = append(pkg.Sections, manifest.SectionManifest{
+			Name:     t.Section.Name,
+			Title:    title,
+			Order:    t.Section.Order,
+			Path:     sectionPath,
+			Modified: info.ModTime(),
+		})
+	}
+	if len(pkg.Sections) == 0 {
+		return pkg, fmt.Errorf("no generated markdown sections found for %s (run 'docgen generate' first)", packageDir)
+	}
+	return pkg, nil
+}
+
+// WikiPages names a wiki page for each section. The first section becomes
+// Home; the rest are named after their titles, as GitHub wikis do.
+func WikiPages(pkg manifest.PackageManifest) []WikiPage {
+	used := make(map[string]bool)
+	pages := make([]WikiPage, 0, len(pkg.Sections))
+	for i, s := range pkg.Sections {
+		name := "Home"
+		if i > 0 {
+			name = strings.Join(strings.Fields(wikiUnsafeRe.ReplaceAllString(s.Title, " ")), "-")
+			if name == "" {
+				name = s.Name
+			}
+		}
+		base := name
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[strings.ToLower(name)] = true
+		pages = append(pages, WikiPage{Name: name, Section: s})
+	}
+	return pages
+}
+
+// WikiSidebar renders _Sidebar.md listing every page in order.
+func WikiSidebar(pkg manifest.PackageManifest, pages []WikiPage) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s**\n\n", pkg.Title)
+	for _, p := range pages {
+		if p.Name == p.Section.Title {
+			fmt.Fprintf(&sb, "- [[%s]]\n", p.Name)
+		} else {
+			fmt.Fprintf(&sb, "- [[%s|%s]]\n", p.Section.Title, p.Name)
+		}
+	}
+	return sb.String()
+}
+
+// wikiContent converts a generated section to a wiki page: frontmatter is
+// dropped, callouts become plain blockquotes, code fences keep only their
+// language (a title moves above the block), links to other sections'
+// markdown files become [[Title|Page]] wiki links, and local images and
+// downloads are collected into images/ and downloads/ (returned as wiki path
+// -> source path) so the page can reference them from the wiki repo.
+// Download links are annotated with the file's size and hash.
+func wikiContent(content, sourceDir string, pageByFile map[string]string, files map[string]string) string {
+	content = wikiFrontmatterRe.ReplaceAllString(content, "")
+	content = transformer.NormalizeCallouts(content, transformer.CalloutGitHub)
+	content = transformer.SimplifyCodeFences(content)
+
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		line = wikiLinkRe.ReplaceAllStringFunc(line, func(m string) string {
+			sub := wikiLinkRe.FindStringSubmatch(m)
+			image, text, target := sub[1] == "!", sub[2], sub[3]
+			if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "mailto:") {
+				return m
+			}
+			if image {
+				src := filepath.Join(sourceDir, filepath.FromSlash(target))
+				if _, err := os.Stat(src); err != nil {
+					return m
+				}
+				name := "images/" + path.Base(target)
+				if existing, ok := files[name]; ok && existing != src {
+					name = fmt.Sprintf("images/%d-%s", len(files), path.Base(target))
+				}
+				files[name] = src
+				return fmt.Sprintf("![%s](%s)", text, name)
+			}
+			if name, ok := transformer.DownloadName(target); ok {
+				src := wikiDownloadSource(sourceDir, target, name)
+				if src == "" {
+					return m
+				}
+				files[transformer.DownloadsDir+"/"+name] = src
+				link := fmt.Sprintf("[%s](%s/%s)", text, transformer.DownloadsDir, name)
+				return transformer.AnnotateDownloads(link, func(string) (transformer.DownloadInfo, bool) {
+					info, err := transformer.DescribeDownload(src)
+					return info, err == nil
+				})
+			}
+			file, fragment, _ := strings.Cut(target, "#")
+			page, ok := pageByFile[path.Base(file)]
+			if !ok {
+				return m
+			}
+			if fragment != "" {
+				page += "#" + fragment
+			}
+			return fmt.Sprintf("[[%s|%s]]", text, page)
+		})
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// wikiDownloadSource finds the file a download link points at: relative to
+// the section, or in the downloads/ directory next to the docs directory, as
+// in a notebook's docgen/ directory. It returns "" when neither exists.
+func wikiDownloadSource(sourceDir, target, name string) string {
+	for _, src := range []string{
+		filepath.Join(sourceDir, filepath.FromSlash(target)),
+		filepath.Join(filepath.Dir(sourceDir), transformer.DownloadsDir, filepath.FromSlash(name)),
+	} {
+		if info, err := os.Stat(src); err == nil && !info.IsDir() {
+			return src
+		}
+	}
+	return ""
+}
+
+// WikiRemote derives a repository's GitHub wiki remote from its origin URL,
+// keeping the same transport (SSH or HTTPS).
+func WikiRemote(packageDir string) (string, error) {
+	url, err := git(packageDir, "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("could not determine origin remote (use --remote): %w", err)
+	}
+	return strings.TrimSuffix(url, ".git") + ".wiki.git", nil
+}
+
+// Wiki clones the package's GitHub wiki, writes every generated section as a
+// wiki page with a generated _Sidebar.md, and commits and pushes the result.
+// Pages docgen did not write are left untouched.
+func (p *Publisher) Wiki(packageDir string, opts WikiOptions) (*WikiResult, error) {
+	pkg, err := PackageManifest(packageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := opts.Remote
+	if remote == "" {
+		if remote, err = WikiRemote(packageDir); err != nil {
+			return nil, err
+		}
+	}
+	result := &WikiResult{Remote: remote}
+
+	dir := opts.Dir
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "docgen-wiki-"); err != nil {
+			return nil, fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		if opts.DryRun {
+			result.Dir = dir
+		} else {
+			defer os.RemoveAll(dir) //nolint:errcheck // best-effort temp cleanup
+		}
+		dir = filepath.Join(dir, "wiki")
+	} else {
+		result.Dir = dir
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		p.logger.Infof("Updating wiki clone in %s", dir)
+		if _, err := git(dir, "pull", "--ff-only"); err != nil {
+			return nil, err
+		}
+	} else {
+		p.logger.Infof("Cloning %s", remote)
+		if _, err := git("", "clone", "--depth", "1", remote, dir); err != nil {
+			return nil, fmt.Errorf("failed to clone wiki (create its first page on GitHub to initialize it): %w", err)
+		}
+	}
+
+	pages := WikiPages(pkg)
+	pageByFile := make(map[string]string, len(pages))
+	for _, page := range pages {
+		pageByFile[filepath.Base(page.Section.Path)] = page.Name
+	}
+
+	files := make(map[string]string)
+	for _, page := range pages {
+		data, err := os.ReadFile(page.Section.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read section '%s': %w", page.Section.Name, err)
+		}
+		conditioned, err := transformer.ApplyConditions(string(data), transformer.Conditions{Target: transformer.TargetWiki, Mode: config.ModeProd, Profile: config.ProfilePublic})
+		if err != nil {
+			return nil, fmt.Errorf("invalid conditional block in section '%s': %w", page.Section.Name, err)
+		}
+		content := wikiContent(conditioned, filepath.Dir(page.Section.Path), pageByFile, files)
+		if err := os.WriteFile(filepath.Join(dir, page.Name+".md"), []byte(content), 0o644); err != nil { //nolint:gosec // wiki page
+			return nil, fmt.Errorf("failed to write wiki page %s: %w", page.Name, err)
+		}
+		result.Pages = append(result.Pages, page.Name)
+	}
+	for name, src := range files {
+		data, err := os.ReadFile(src) //nolint:gosec // file referenced by generated docs
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil { //nolint:gosec // wiki checkout
+			return nil, fmt.Errorf("failed to create wiki directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(dest, data, 0o644); err != nil { //nolint:gosec // wiki asset
+			return nil, fmt.Errorf("failed to write wiki file %s: %w", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "_Sidebar.md"), []byte(WikiSidebar(pkg, pages)), 0o644); err != nil { //nolint:gosec // wiki page
+		return nil, fmt.Errorf("failed to write wiki sidebar: %w", err)
+	}
+
+	status, err := git(dir, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	result.Changed = status != ""
+	if !result.Changed || opts.DryRun {
+		return result, nil
+	}
+
+	message := opts.Message
+	if message == "" {
+		message = fmt.Sprintf("Update %s docs", pkg.Title)
+	}
+	if _, err := git(dir, "add", "-A"); err != nil {
+		return nil, err
+	}
+	if _, err := git(dir, "commit", "-m", message); err != nil {
+		return nil, err
+	}
+	if opts.NoPush {
+		return result, nil
+	}
+	if _, err := git(dir, "push"); err != nil {
+		return nil, err
+	}
+	result.Pushed = true
+	return result, nil
+}
+
+// git runs a git command in dir and returns its trimmed stdout.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w\n%s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
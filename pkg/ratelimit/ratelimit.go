@@ -0,0 +1,123 @@
+// Package ratelimit keeps the LLM requests of a run within a provider's
+// quota: requests and tokens per minute, shared by every concurrent
+// generation in the process. Requests over the limit queue until the
+// sliding one-minute window has room for them.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// window is the period the limits apply to.
+const window = time.Minute
+
+// Limits are the quota of a provider; zero means unlimited.
+type Limits struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+// Limiter admits requests within Limits over a sliding window. It is safe
+// for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	limits Limits
+	sent   []request // Admitted in the last window, oldest first
+	now    func() time.Time
+}
+
+type request struct {
+	at     time.Time
+	tokens int
+}
+
+// New returns a limiter for limits.
+func New(limits Limits) *Limiter {
+	return &Limiter{limits: limits, now: time.Now}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*Limiter)
+)
+
+// For returns the process-wide limiter of provider, creating it with limits.
+// When generations configure different limits for the same provider, the
+// lowest of each applies, so no configuration's quota is exceeded.
+func For(provider string, limits Limits) *Limiter {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	l, ok := registry[provider]
+	if !ok {
+		l = New(limits)
+		registry[provider] = l
+		return l
+	}
+	l.mu.Lock()
+	l.limits.RequestsPerMinute = lowest(l.limits.RequestsPerMinute, limits.RequestsPerMinute)
+	l.limits.TokensPerMinute = lowest(l.limits.TokensPerMinute, limits.TokensPerMinute)
+	l.mu.Unlock()
+	return l
+}
+
+// lowest returns the lower of two limits, where zero is unlimited.
+func lowest(a, b int) int {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
+
+// Wait blocks until a request of tokens estimated tokens fits the limits,
+// then counts it. A request larger than the token limit is admitted alone
+// once the window is empty. wait, when not nil, is called with the expected
+// delay each time the request has to queue. It returns ctx's error if ctx
+// ends first.
+func (l *Limiter) Wait(ctx context.Context, tokens int, wait func(time.Duration)) error {
+	for {
+		delay := l.reserve(tokens)
+		if delay == 0 {
+			return nil
+		}
+		if wait != nil {
+			wait(delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve counts the request and returns 0 when it fits, or how long until
+// the oldest request in the window expires otherwise.
+func (l *Limiter) reserve(tokens int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for len(l.sent) > 0 && now.Sub(l.sent[0].at) >= window {
+		l.sent = l.sent[1:]
+	}
+
+	used := 0
+	for _, r := range l.sent {
+		used += r.tokens
+	}
+	fits := len(l.sent) == 0 ||
+		((l.limits.RequestsPerMinute == 0 || len(l.sent) < l.limits.RequestsPerMinute) &&
+			(l.limits.TokensPerMinute == 0 || used+tokens <= l.limits.TokensPerMinute))
+	if fits {
+		l.sent = append(l.sent, request{at: now, tokens: tokens})
+		return 0
+	}
+	return max(window-now.Sub(l.sent[0].at), time.Millisecond)
+}
@@ -0,0 +1,163 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a limiter clock the test moves by hand.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(limits Limits) (*Limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := New(limits)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestReserve(t *testing.T) {
+	type step struct {
+		advance time.Duration
+		tokens  int
+		delay   time.Duration // 0 when the request is admitted
+	}
+	cases := []struct {
+		name   string
+		limits Limits
+		steps  []step
+	}{
+		{
+			name:   "unlimited",
+			limits: Limits{},
+			steps:  []step{{0, 1000000, 0}, {0, 1000000, 0}, {0, 1000000, 0}},
+		},
+		{
+			name:   "requests per minute",
+			limits: Limits{RequestsPerMinute: 2},
+			steps: []step{
+				{0, 10, 0},
+				{10 * time.Second, 10, 0},
+				{10 * time.Second, 10, 40 * time.Second}, // until the first expires
+				{40 * time.Second, 10, 0},
+				{0, 10, 10 * time.Second}, // until the second expires
+			},
+		},
+		{
+			name:   "tokens per minute",
+			limits: Limits{TokensPerMinute: 100},
+			steps: []step{
+				{0, 60, 0},
+				{30 * time.Second, 40, 0},
+				{0, 1, 30 * time.Second},
+				{30 * time.Second, 60, 0}, // the first 60 tokens expired
+				{0, 1, 30 * time.Second},
+			},
+		},
+		{
+			name:   "oversized request runs alone",
+			limits: Limits{TokensPerMinute: 100},
+			steps: []step{
+				{0, 10, 0},
+				{0, 500, time.Minute},
+				{time.Minute, 500, 0},
+				{0, 10, time.Minute},
+			},
+		},
+		{
+			name:   "both limits",
+			limits: Limits{RequestsPerMinute: 3, TokensPerMinute: 100},
+			steps: []step{
+				{0, 10, 0},
+				{0, 10, 0},
+				{0, 90, time.Minute}, // tokens
+				{0, 10, 0},
+				{0, 1, time.Minute}, // requests
+			},
+		},
+		{
+			name:   "delay is at least a millisecond",
+			limits: Limits{RequestsPerMinute: 1},
+			steps: []step{
+				{0, 1, 0},
+				{time.Minute - time.Nanosecond, 1, time.Millisecond},
+				{time.Nanosecond, 1, 0},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l, clock := newTestLimiter(c.limits)
+			for i, s := range c.steps {
+				clock.advance(s.advance)
+				if got := l.reserve(s.tokens); got != s.delay {
+					t.Fatalf("step %d: reserve(%d) = %v, want %v", i, s.tokens, got, s.delay)
+				}
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	l, clock := newTestLimiter(Limits{RequestsPerMinute: 1})
+	if err := l.Wait(context.Background(), 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The window is full: Wait reports the delay and gives up with ctx
+	ctx, cancel := context.WithCancel(context.Background())
+	var waits []time.Duration
+	err := l.Wait(ctx, 1, func(d time.Duration) {
+		waits = append(waits, d)
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() = %v, want context.Canceled", err)
+	}
+	if len(waits) != 1 || waits[0] != time.Minute {
+		t.Errorf("wait callbacks = %v, want [1m0s]", waits)
+	}
+
+	// Once the window passes, the queued request is admitted
+	clock.advance(time.Minute)
+	if err := l.Wait(context.Background(), 1, func(d time.Duration) { t.Errorf("waited %v after the window passed", d) }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestForSharesLowestLimits checks generations configuring one provider
+// share its limiter, limited by the lowest of their limits.
+func TestForSharesLowestLimits(t *testing.T) {
+	a := For("test-provider-shared", Limits{RequestsPerMinute: 10, TokensPerMinute: 0})
+	b := For("test-provider-shared", Limits{RequestsPerMinute: 0, TokensPerMinute: 500})
+	c := For("test-provider-shared", Limits{RequestsPerMinute: 20, TokensPerMinute: 1000})
+	if a != b || b != c {
+		t.Fatal("For returned different limiters for one provider")
+	}
+	want := Limits{RequestsPerMinute: 10, TokensPerMinute: 500}
+	if a.limits != want {
+		t.Errorf("shared limits = %+v, want %+v", a.limits, want)
+	}
+	if For("test-provider-other", Limits{}) == a {
+		t.Error("For shared a limiter across providers")
+	}
+}
+
+func TestLowest(t *testing.T) {
+	cases := []struct{ a, b, want int }{
+		{0, 0, 0},
+		{0, 5, 5},
+		{5, 0, 5},
+		{3, 5, 3},
+		{5, 3, 3},
+	}
+	for _, c := range cases {
+		if got := lowest(c.a, c.b); got != c.want {
+			t.Errorf("lowest(%d, %d) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
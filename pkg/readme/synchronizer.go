@@ -9,6 +9,7 @@ import (
 
 	"github.com/grovetools/docgen/pkg/config"
 	"github.com/grovetools/docgen/pkg/logo"
+	"github.com/grovetools/docgen/pkg/transformer"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,9 +25,7 @@ func New(logger *logrus.Logger) *Synchronizer {
 
 // Sync performs the README synchronization for a given package directory.
 func (s *Synchronizer) Sync(packageDir string) error {
-	fmt.Println("DEBUG: Sync called with packageDir:", packageDir)
 	cfg, configPath, err := config.LoadWithNotebook(packageDir)
-	fmt.Printf("DEBUG: LoadWithNotebook returned - configPath=%s, err=%v\n", configPath, err)
 	if err != nil {
 		if os.IsNotExist(err) {
 			s.logger.Debugf("Skipping README sync: no docgen.config.yml found in %s", packageDir)
@@ -35,17 +34,6 @@ func (s *Synchronizer) Sync(packageDir string) error {
 		return fmt.Errorf("failed to load docgen.config.yml: %w", err)
 	}
 
-	// DEBUG: Log config path and logo config status
-	fmt.Printf("DEBUG: cfg.Readme is nil? %v\n", cfg.Readme == nil)
-	if cfg.Readme != nil {
-		if cfg.Readme.Logo != nil {
-			fmt.Printf("DEBUG: Logo config found - input=%s, output=%s, text=%s\n",
-				cfg.Readme.Logo.Input, cfg.Readme.Logo.Output, cfg.Readme.Logo.Text)
-		} else {
-			fmt.Println("DEBUG: cfg.Readme.Logo is NIL")
-		}
-	}
-
 	if cfg.Readme == nil {
 		s.logger.Info("Skipping README sync: 'readme' section not configured in docgen.config.yml")
 		return nil
@@ -111,6 +99,13 @@ func (s *Synchronizer) Sync(packageDir string) error {
 		}
 	}
 
+	// Keep the blocks meant for the README
+	conditioned, err := transformer.ApplyConditions(string(sourceContent), transformer.Conditions{Target: transformer.TargetReadme, Mode: config.ModeProd, Profile: config.ProfilePublic})
+	if err != nil {
+		return fmt.Errorf("invalid conditional block in %s: %w", sourceDocPath, err)
+	}
+	sourceContent = []byte(conditioned)
+
 	// Read template content
 	// If template path starts with docgen/, resolve from config location (notebook)
 	// Otherwise resolve from package directory (legacy)
@@ -136,13 +131,11 @@ func (s *Synchronizer) Sync(packageDir string) error {
 
 	// --- Perform Replacements ---
 
-	// 1. Replace metadata variables
-	replacer := strings.NewReplacer(
-		"{{ .Title }}", cfg.Title,
-		"{{ .Description }}", cfg.Description,
-		"{{ .PackageName }}", filepath.Base(packageDir),
-	)
-	composedContent := replacer.Replace(templateContent)
+	// 1. Render the template against the package data model
+	composedContent, err := s.renderTemplate(templateContent, templatePath, newTemplateData(cfg, packageDir))
+	if err != nil {
+		return err
+	}
 
 	// 2. Replace source section content
 	startMarker := fmt.Sprintf("<!-- DOCGEN:%s:START -->", strings.ToUpper(cfg.Readme.SourceSection))
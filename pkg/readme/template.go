@@ -0,0 +1,149 @@
+package readme
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// TemplateData is the data model exposed to README templates.
+type TemplateData struct {
+	Title       string
+	Description string
+	PackageName string
+	Version     string
+	RepoURL     string
+	Badges      []config.BadgeConfig
+	Sections    []TemplateSection
+	Config      *config.DocgenConfig
+}
+
+// TemplateSection describes a configured documentation section for templates.
+type TemplateSection struct {
+	Name   string
+	Title  string
+	Output string
+	Path   string // Path to the generated doc, relative to the package root
+	Order  int
+}
+
+// newTemplateData builds the template data model for a package.
+func newTemplateData(cfg *config.DocgenConfig, packageDir string) TemplateData {
+	outputDir := cfg.Settings.OutputDir
+	if outputDir == "" {
+		outputDir = "docs"
+	}
+
+	sections := make([]TemplateSection, 0, len(cfg.Sections))
+	for _, section := range cfg.Sections {
+		sections = append(sections, TemplateSection{
+			Name:   section.Name,
+			Title:  section.Title,
+			Output: section.Output,
+			Path:   filepath.ToSlash(filepath.Join(outputDir, section.Output)),
+			Order:  section.Order,
+		})
+	}
+
+	data := TemplateData{
+		Title:       cfg.Title,
+		Description: cfg.Description,
+		PackageName: filepath.Base(packageDir),
+		Version:     gitOutput(packageDir, "describe", "--tags", "--abbrev=0"),
+		RepoURL:     repoURL(packageDir),
+		Sections:    sections,
+		Config:      cfg,
+	}
+	if cfg.Readme != nil {
+		data.Badges = cfg.Readme.Badges
+	}
+	return data
+}
+
+// renderTemplate executes a README template against the data model. Templates
+// that are not valid Go templates (e.g. READMEs documenting other template
+// languages) fall back to the legacy placeholder replacement. A valid
+// template that fails to execute, on a missing include or a field
+// TemplateData lacks, is an error: falling back would ship the raw actions.
+func (s *Synchronizer) renderTemplate(content, templatePath string, data TemplateData) (string, error) {
+	tmpl, err := template.New(filepath.Base(templatePath)).
+		Funcs(templateFuncs(filepath.Dir(templatePath))).
+		Parse(content)
+	if err != nil {
+		s.logger.Warnf("README template is not a valid Go template, using legacy placeholders: %v", err)
+		return legacyReplace(content, data), nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute README template %s: %w", templatePath, err)
+	}
+	return buf.String(), nil
+}
+
+// legacyReplace substitutes the three placeholders supported before README
+// templates were executed with text/template.
+func legacyReplace(content string, data TemplateData) string {
+	replacer := strings.NewReplacer(
+		"{{ .Title }}", data.Title,
+		"{{ .Description }}", data.Description,
+		"{{ .PackageName }}", data.PackageName,
+	)
+	return replacer.Replace(content)
+}
+
+// templateFuncs returns the custom functions available to README templates.
+// include paths are resolved relative to the template's directory.
+func templateFuncs(baseDir string) template.FuncMap {
+	return template.FuncMap{
+		"upper":     strings.ToUpper,
+		"trimLines": trimLines,
+		"include": func(path string) (string, error) {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+			data, err := os.ReadFile(path) //nolint:gosec // path from trusted template
+			if err != nil {
+				return "", fmt.Errorf("include %s: %w", path, err)
+			}
+			return string(data), nil
+		},
+	}
+}
+
+// trimLines strips trailing whitespace from every line and drops leading and
+// trailing blank lines.
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// repoURL returns the origin remote as an HTTPS URL, or "" if unavailable.
+func repoURL(dir string) string {
+	url := gitOutput(dir, "remote", "get-url", "origin")
+	if strings.HasPrefix(url, "git@github.com:") {
+		url = strings.Replace(url, "git@github.com:", "https://github.com/", 1)
+	}
+	return strings.TrimSuffix(url, ".git")
+}
+
+// gitOutput runs a git command in dir and returns its trimmed output, or ""
+// on failure.
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,79 @@
+package readme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "install.md"), []byte("go install example.com/flow@latest\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := TemplateData{
+		Title:       "Flow",
+		Description: "Runs jobs.",
+		PackageName: "flow",
+		Sections:    []TemplateSection{{Title: "Overview", Path: "docs/overview.md"}},
+	}
+
+	cases := []struct {
+		name    string
+		content string
+		want    string
+		warned  bool
+		wantErr string
+	}{
+		{
+			name:    "valid template",
+			content: "# {{ .Title | upper }}\n\n{{ .Description }}\n{{ range .Sections }}- [{{ .Title }}]({{ .Path }})\n{{ end }}\n{{ include \"install.md\" | trimLines }}\n",
+			want:    "# FLOW\n\nRuns jobs.\n- [Overview](docs/overview.md)\n\ngo install example.com/flow@latest\n",
+		},
+		{
+			name:    "parse error falls back to placeholders",
+			content: "# {{ .Title }}\n\nWrite {{ if }} blocks in your templates.\n",
+			want:    "# Flow\n\nWrite {{ if }} blocks in your templates.\n",
+			warned:  true,
+		},
+		{
+			name:    "unknown field is an error",
+			content: "# {{ .PackageName }}\n\nGreet with {{ .User.Name }}.\n",
+			wantErr: "can't evaluate field User",
+		},
+		{
+			name:    "missing include is an error",
+			content: "{{ .Description }}\n{{ include \"missing.md\" }}\n",
+			wantErr: "include " + filepath.Join(dir, "missing.md"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			got, err := New(logger).renderTemplate(c.content, filepath.Join(dir, "README.md.tpl"), data)
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Fatalf("renderTemplate() = %q, %v; want an error containing %q", got, err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("renderTemplate() = %q, want %q", got, c.want)
+			}
+			warned := false
+			for _, e := range hook.AllEntries() {
+				warned = warned || e.Level == logrus.WarnLevel
+			}
+			if warned != c.warned {
+				t.Errorf("warned = %v, want %v", warned, c.warned)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package recipes
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/generator"
+)
+
+// SectionsRecipeName is the recipe built from a package's configured
+// sections (see SectionsRecipe).
+const SectionsRecipeName = "docgen-sections"
+
+var (
+	jobNameRegex   = regexp.MustCompile(`[^a-z0-9]+`)
+	shellWordRegex = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+)
+
+// SectionsRecipe builds a recipe from a package's sections, as
+// generator.ResolveSectionTargets returns them: one shell job per section
+// running docgen generate for it, then a review job that depends on all of
+// them. Concat sections combine the others' output, so their jobs depend on
+// every other section job.
+func SectionsRecipe(targets []generator.SectionTarget) (RecipeDefinition, error) {
+	if len(targets) == 0 {
+		return RecipeDefinition{}, fmt.Errorf("no sections configured")
+	}
+	recipe := RecipeDefinition{
+		Description: "Generate each configured documentation section as its own job, then review and approve the results",
+		Jobs:        make(map[string]string),
+	}
+
+	var files, plain []string
+	for i, t := range targets {
+		file := fmt.Sprintf("%02d-generate-%s.md", i+1, jobSlug(t.Name))
+		files = append(files, file)
+		if t.Section.Type != "concat" {
+			plain = append(plain, file)
+		}
+	}
+
+	for i, t := range targets {
+		var sb strings.Builder
+		sb.WriteString("---\n")
+		fmt.Fprintf(&sb, "id: generate-%s\n", jobSlug(t.Name))
+		fmt.Fprintf(&sb, "title: %q\n", "Generate "+sectionTitle(t.Section))
+		sb.WriteString("status: pending\ntype: shell\n")
+		if t.Section.Type == "concat" {
+			writeDependsOn(&sb, plain)
+		}
+		sb.WriteString("---\n\n")
+		// Sections keep their configured models; the model variable is
+		// only the review chat's
+		fmt.Fprintf(&sb, "docgen generate --section %s\n", shellQuote(t.Name))
+		recipe.Jobs[files[i]] = sb.String()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("---\nid: review-docs\ntitle: \"Review Documentation\"\nstatus: pending_user\ntype: chat\n")
+	sb.WriteString("template: chat{{ if .Vars.model }}\nmodel: \"{{ .Vars.model }}\"{{ end }}\n")
+	writeDependsOn(&sb, files)
+	sb.WriteString("---\n\n")
+	sb.WriteString("Review the documentation generated by the previous jobs, one section at a time:\n\n")
+	sb.WriteString("| Section | Output | Prompt |\n| :--- | :--- | :--- |\n")
+	for _, t := range targets {
+		prompt := "(none)"
+		if t.Section.Prompt != "" {
+			prompt = "`" + filepath.ToSlash(t.Section.Prompt) + "`"
+		}
+		fmt.Fprintf(&sb, "| `%s` | `%s` | %s |\n", t.Name, filepath.ToSlash(filepath.Join(t.OutputDir, t.Section.Output)), prompt)
+	}
+	sb.WriteString(`
+For each section, check that the doc is accurate against the code, complete
+for its prompt, and consistent in tone with the other sections. List the
+problems you find per section.
+
+For a section that needs another pass, propose the change to its prompt.
+Once the prompt is updated, regenerate just that section with
+` + "`docgen generate --section <name>`" + ` and review it again.
+
+When every section is accepted, reply with APPROVED and a one-line summary
+of each section's state.
+`)
+	recipe.Jobs[fmt.Sprintf("%02d-review-docs.md", len(files)+1)] = sb.String()
+	return recipe, nil
+}
+
+// sectionTitle returns a section's title, or its name when it has none.
+func sectionTitle(section config.SectionConfig) string {
+	if section.Title != "" {
+		return section.Title
+	}
+	return section.Name
+}
+
+// jobSlug turns a section name into a job id and file name part.
+func jobSlug(name string) string {
+	return strings.Trim(jobNameRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func writeDependsOn(sb *strings.Builder, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	sb.WriteString("depends_on:\n")
+	for _, f := range files {
+		fmt.Fprintf(sb, "  - %s\n", f)
+	}
+}
+
+// shellQuote quotes s for a POSIX shell when it is not a plain word.
+func shellQuote(s string) string {
+	if shellWordRegex.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
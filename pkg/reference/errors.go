@@ -0,0 +1,288 @@
+package reference
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// Error kinds.
+const (
+	ErrorSentinel    = "sentinel"    // var ErrX = errors.New(...)
+	ErrorType        = "type"        // a type with an Error() string method
+	ErrorConstructor = "constructor" // a function that builds and returns an error
+)
+
+// Error is one exported error of a module.
+type Error struct {
+	Code       string   `json:"code"` // pkg.Name
+	Kind       string   `json:"kind"`
+	Package    string   `json:"package"` // Import path
+	Message    string   `json:"message,omitempty"`
+	Doc        string   `json:"doc,omitempty"`
+	Defined    string   `json:"defined"`               // file:line
+	ReturnedBy []string `json:"returned_by,omitempty"` // Functions returning the error
+}
+
+// errorDef is an Error being collected, keyed by package path and name.
+type errorDef struct {
+	*Error
+	name    string
+	callers map[string]bool
+}
+
+// Errors scans the Go files under root's dirs (all of root when dirs is
+// empty) for exported sentinel errors, error types and error constructors,
+// and records the functions that return each.
+func Errors(root string, dirs []string) ([]Error, error) {
+	fset, files, err := parseGoFiles(root, dirs)
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]map[string]*errorDef) // pkgPath -> name -> def
+	add := func(f *goFile, name, kind, message string, doc *ast.CommentGroup, pos token.Pos) {
+		if defs[f.pkgPath] == nil {
+			defs[f.pkgPath] = make(map[string]*errorDef)
+		}
+		if _, ok := defs[f.pkgPath][name]; ok {
+			return
+		}
+		defs[f.pkgPath][name] = &errorDef{
+			Error: &Error{
+				Code:    f.ast.Name.Name + "." + name,
+				Kind:    kind,
+				Package: f.pkgPath,
+				Message: message,
+				Doc:     docText(doc),
+				Defined: fmt.Sprintf("%s:%d", f.path, fset.Position(pos).Line),
+			},
+			name:    name,
+			callers: make(map[string]bool),
+		}
+	}
+
+	// Error types first, so constructors returning them are recognized.
+	for _, f := range files {
+		for _, decl := range f.ast.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Name.Name != "Error" || !returnsString(fn) {
+				continue
+			}
+			name := typeName(fn.Recv.List[0].Type)
+			if !ast.IsExported(name) {
+				continue
+			}
+			spec := findType(files, f.pkgPath, name)
+			var doc *ast.CommentGroup
+			pos := fn.Pos()
+			if spec != nil {
+				doc, pos = spec.doc, spec.pos
+			}
+			add(f, name, ErrorType, errorMethodMessage(fn), doc, pos)
+		}
+	}
+
+	for _, f := range files {
+		for _, decl := range f.ast.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.VAR {
+					continue
+				}
+				for _, s := range d.Specs {
+					vs := s.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if !name.IsExported() {
+							continue
+						}
+						doc := vs.Doc
+						if doc == nil && len(d.Specs) == 1 {
+							doc = d.Doc
+						}
+						if i < len(vs.Values) {
+							if call, ok := newErrorCall(f, vs.Values[i]); ok {
+								msg, _ := stringLit(call.Args[0])
+								add(f, name.Name, ErrorSentinel, msg, doc, name.Pos())
+								continue
+							}
+						}
+						if id, ok := vs.Type.(*ast.Ident); ok && id.Name == "error" {
+							add(f, name.Name, ErrorSentinel, "", doc, name.Pos())
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv != nil || !d.Name.IsExported() || d.Type.Results == nil || len(d.Type.Results.List) != 1 {
+					continue
+				}
+				result := typeName(d.Type.Results.List[0].Type)
+				if t := defs[f.pkgPath][result]; result != "error" && (t == nil || t.Kind != ErrorType) {
+					continue
+				}
+				if !strings.HasPrefix(d.Name.Name, "New") && !strings.HasPrefix(d.Name.Name, "Err") && !strings.HasSuffix(d.Name.Name, "Error") {
+					continue
+				}
+				add(f, d.Name.Name, ErrorConstructor, constructorMessage(f, d), d.Doc, d.Pos())
+			}
+		}
+	}
+
+	// Record the functions whose return statements reference each error.
+	for _, f := range files {
+		pkg := f.ast.Name.Name
+		for _, decl := range f.ast.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			caller := funcName(pkg, fn)
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				ret, ok := n.(*ast.ReturnStmt)
+				if !ok {
+					return true
+				}
+				var visit func(ast.Node) bool
+				visit = func(n ast.Node) bool {
+					var def *errorDef
+					switch e := n.(type) {
+					case *ast.SelectorExpr:
+						// Only the qualifier of x.Name can name an error of
+						// this package; Name itself is a field or method.
+						if x, ok := e.X.(*ast.Ident); ok {
+							def = defs[f.imports[x.Name]][e.Sel.Name]
+							if def == nil {
+								def = defs[f.pkgPath][x.Name]
+							}
+						} else {
+							ast.Inspect(e.X, visit)
+						}
+						if def == nil {
+							return false
+						}
+					case *ast.Ident:
+						def = defs[f.pkgPath][e.Name]
+					}
+					if def != nil && def.Code != caller {
+						def.callers[fmt.Sprintf("%s (%s:%d)", caller, f.path, fset.Position(ret.Pos()).Line)] = true
+					}
+					return def == nil
+				}
+				for _, res := range ret.Results {
+					ast.Inspect(res, visit)
+				}
+				return true
+			})
+		}
+	}
+
+	var errs []Error
+	for _, byName := range defs {
+		for _, def := range byName {
+			for c := range def.callers {
+				def.ReturnedBy = append(def.ReturnedBy, c)
+			}
+			sort.Strings(def.ReturnedBy)
+			errs = append(errs, *def.Error)
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Package != errs[j].Package {
+			return errs[i].Package < errs[j].Package
+		}
+		return errs[i].Code < errs[j].Code
+	})
+	return errs, nil
+}
+
+// newErrorCall matches errors.New("...") and fmt.Errorf("...", ...).
+func newErrorCall(f *goFile, expr ast.Expr) (*ast.CallExpr, bool) {
+	call, ok := isCall(f, expr, "errors", "New")
+	if !ok {
+		call, ok = isCall(f, expr, "fmt", "Errorf")
+	}
+	if !ok || len(call.Args) == 0 {
+		return nil, false
+	}
+	return call, true
+}
+
+func returnsString(fn *ast.FuncDecl) bool {
+	if len(fn.Type.Params.List) != 0 || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+		return false
+	}
+	id, ok := fn.Type.Results.List[0].Type.(*ast.Ident)
+	return ok && id.Name == "string"
+}
+
+// errorMethodMessage returns the literal or format string an Error method
+// returns, if any.
+func errorMethodMessage(fn *ast.FuncDecl) string {
+	var msg string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || msg != "" || len(ret.Results) != 1 {
+			return msg == ""
+		}
+		if s, ok := stringLit(ret.Results[0]); ok {
+			msg = s
+		} else if call, ok := ret.Results[0].(*ast.CallExpr); ok && len(call.Args) > 0 {
+			msg, _ = stringLit(call.Args[0])
+		}
+		return false
+	})
+	return msg
+}
+
+// constructorMessage returns the message of the first errors.New or
+// fmt.Errorf call a constructor returns.
+func constructorMessage(f *goFile, fn *ast.FuncDecl) string {
+	var msg string
+	if fn.Body == nil {
+		return ""
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || msg != "" || len(ret.Results) != 1 {
+			return msg == ""
+		}
+		if call, ok := newErrorCall(f, ret.Results[0]); ok {
+			msg, _ = stringLit(call.Args[0])
+		}
+		return false
+	})
+	return msg
+}
+
+type typeSpec struct {
+	doc *ast.CommentGroup
+	pos token.Pos
+}
+
+// findType locates the declaration of a named type in a package.
+func findType(files []*goFile, pkgPath, name string) *typeSpec {
+	for _, f := range files {
+		if f.pkgPath != pkgPath {
+			continue
+		}
+		for _, decl := range f.ast.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gd.Specs {
+				ts := s.(*ast.TypeSpec)
+				if ts.Name.Name != name {
+					continue
+				}
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				return &typeSpec{doc: doc, pos: ts.Pos()}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package reference
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// UnknownLicense is the license of a module whose license files match no
+// known license.
+const UnknownLicense = "Unknown"
+
+// ModuleLicense is a third-party Go module linked into the package's
+// binaries and the license it is distributed under.
+type ModuleLicense struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	License string `json:"license"` // SPDX identifier, or UnknownLicense
+	// Files are the module's license and notice files, keyed by file name,
+	// which distribution of the binaries must reproduce.
+	Files map[string]string `json:"-"`
+}
+
+var licenseFileRe = regexp.MustCompile(`(?i)^(licen[cs]e|copying|notice)([-._].*)?$`)
+
+// licenseMatchers identify a license from its normalized text, most
+// specific first.
+var licenseMatchers = []struct {
+	id   string
+	test func(text string) bool
+}{
+	{"AGPL-3.0", containsAll("gnu affero general public license")},
+	{"LGPL-3.0", containsAll("gnu lesser general public license", "version 3")},
+	{"LGPL-2.1", containsAll("gnu lesser general public license")},
+	{"GPL-3.0", containsAll("gnu general public license", "version 3")},
+	{"GPL-2.0", containsAll("gnu general public license")},
+	{"MPL-2.0", containsAll("mozilla public license", "2.0")},
+	{"Apache-2.0", containsAll("apache license", "version 2.0")},
+	{"BSD-3-Clause", containsAll("redistribution and use in source and binary forms", "neither the name")},
+	{"BSD-3-Clause", containsAll("redistribution and use in source and binary forms", "names of its contributors")},
+	{"BSD-2-Clause", containsAll("redistribution and use in source and binary forms")},
+	{"MIT", containsAll("permission is hereby granted, free of charge")},
+	{"ISC", containsAll("permission to use, copy, modify, and/or distribute this software for any purpose with or without fee is hereby granted")},
+	{"Unlicense", containsAll("this is free and unencumbered software released into the public domain")},
+	{"CC0-1.0", containsAll("creative commons", "cc0")},
+}
+
+func containsAll(phrases ...string) func(string) bool {
+	return func(text string) bool {
+		for _, p := range phrases {
+			if !strings.Contains(text, p) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DetectLicense returns the SPDX identifier of a license text, or
+// UnknownLicense.
+func DetectLicense(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, m := range licenseMatchers {
+		if m.test(normalized) {
+			return m.id
+		}
+	}
+	return UnknownLicense
+}
+
+// Licenses returns the third-party modules providing the packages matched
+// by patterns (default: ./...) of the Go module at root and their
+// dependencies, with the licenses found in the module cache. Standard
+// library and main module packages are left out. The modules must have been
+// downloaded (go mod download).
+func Licenses(root string, patterns []string) ([]ModuleLicense, error) {
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+		return nil, fmt.Errorf("no go.mod in %s", root)
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	args := append([]string{"list", "-deps", "-f", `{{with .Module}}{{if not .Main}}{{.Path}}	{{.Version}}	{{.Dir}}{{end}}{{end}}`}, patterns...)
+	cmd := exec.Command("go", args...)
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("go list failed: %s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("go list failed: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var modules []ModuleLicense
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		mod := ModuleLicense{Path: fields[0], Version: fields[1], License: UnknownLicense}
+		if fields[2] == "" {
+			return nil, fmt.Errorf("module %s is not downloaded; run go mod download", mod.Path)
+		}
+		mod.Files, err = licenseFiles(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range mod.FileNames() {
+			if strings.HasPrefix(strings.ToLower(name), "notice") {
+				continue
+			}
+			if id := DetectLicense(mod.Files[name]); id != UnknownLicense {
+				mod.License = id
+				break
+			}
+		}
+		modules = append(modules, mod)
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Path < modules[j].Path })
+	return modules, nil
+}
+
+// FileNames returns the names of the module's license and notice files in
+// order.
+func (m ModuleLicense) FileNames() []string {
+	names := make([]string, 0, len(m.Files))
+	for name := range m.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// licenseFiles reads the license and notice files at the root of a module.
+func licenseFiles(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module directory %s: %w", dir, err)
+	}
+	files := make(map[string]string)
+	for _, e := range entries {
+		if e.IsDir() || !licenseFileRe.MatchString(e.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name())) //nolint:gosec // file in the module cache
+		if err != nil {
+			return nil, err
+		}
+		files[e.Name()] = string(data)
+	}
+	return files, nil
+}
@@ -0,0 +1,246 @@
+package reference
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Metric is one exported metric.
+type Metric struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"` // counter, gauge, histogram, summary, ...
+	Help    string   `json:"help,omitempty"`
+	Labels  []string `json:"labels,omitempty"`
+	Defined string   `json:"defined,omitempty"` // file:line
+}
+
+// promConstructors maps Prometheus client constructors to metric types.
+var promConstructors = map[string]string{
+	"NewCounter": "counter", "NewCounterVec": "counter", "NewCounterFunc": "counter",
+	"NewGauge": "gauge", "NewGaugeVec": "gauge", "NewGaugeFunc": "gauge",
+	"NewHistogram": "histogram", "NewHistogramVec": "histogram",
+	"NewSummary": "summary", "NewSummaryVec": "summary",
+	"NewUntypedFunc": "untyped",
+}
+
+const (
+	promPath     = "github.com/prometheus/client_golang/prometheus"
+	promautoPath = "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics scans the Go files under root's dirs (all of root when dirs is
+// empty) for Prometheus metric registrations made with the prometheus and
+// promauto constructors. Names, help text and labels built from string
+// constants are resolved.
+func Metrics(root string, dirs []string) ([]Metric, error) {
+	fset, files, err := parseGoFiles(root, dirs)
+	if err != nil {
+		return nil, err
+	}
+	consts := stringConsts(files)
+
+	var metrics []Metric
+	for _, f := range files {
+		ast.Inspect(f.ast, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			typ, ok := promConstructors[sel.Sel.Name]
+			if !ok || !isPromReceiver(f, sel.X) {
+				return true
+			}
+			opts, ok := call.Args[0].(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			m := Metric{Type: typ, Defined: fmt.Sprintf("%s:%d", f.path, fset.Position(call.Pos()).Line)}
+			var namespace, subsystem, name string
+			for _, elt := range opts.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, _ := kv.Key.(*ast.Ident)
+				if key == nil {
+					continue
+				}
+				switch key.Name {
+				case "Namespace":
+					namespace, _ = resolveString(f, kv.Value, consts)
+				case "Subsystem":
+					subsystem, _ = resolveString(f, kv.Value, consts)
+				case "Name":
+					name, _ = resolveString(f, kv.Value, consts)
+				case "Help":
+					m.Help, _ = resolveString(f, kv.Value, consts)
+				case "ConstLabels":
+					if lit, ok := kv.Value.(*ast.CompositeLit); ok {
+						for _, e := range lit.Elts {
+							if kv, ok := e.(*ast.KeyValueExpr); ok {
+								if k, ok := resolveString(f, kv.Key, consts); ok {
+									m.Labels = append(m.Labels, k)
+								}
+							}
+						}
+					}
+				}
+			}
+			if name == "" {
+				return true
+			}
+			m.Name = joinMetricName(namespace, subsystem, name)
+			if strings.HasSuffix(sel.Sel.Name, "Vec") && len(call.Args) > 1 {
+				if labels, ok := stringList(f, call.Args[1], consts); ok {
+					m.Labels = append(m.Labels, labels...)
+				}
+			}
+			metrics = append(metrics, m)
+			return true
+		})
+	}
+	sortMetrics(metrics)
+	return metrics, nil
+}
+
+// isPromReceiver reports whether expr is the prometheus or promauto package,
+// or a promauto.With(...) factory.
+func isPromReceiver(f *goFile, expr ast.Expr) bool {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		p := f.imports[x.Name]
+		return p == promPath || p == promautoPath
+	case *ast.CallExpr:
+		_, ok := isCall(f, x, promautoPath, "With")
+		return ok
+	}
+	return false
+}
+
+func joinMetricName(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "_")
+}
+
+var (
+	openMetricsMetaRe   = regexp.MustCompile(`^#\s*(HELP|TYPE|UNIT)\s+(\S+)\s*(.*)$`)
+	openMetricsSampleRe = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(?:\{([^}]*)\})?`)
+	openMetricsLabelRe  = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*=`)
+)
+
+// sampleSuffixes are appended to a metric family's name by its samples.
+var sampleSuffixes = []string{"_total", "_created", "_bucket", "_count", "_sum", "_info"}
+
+// ParseOpenMetrics reads metric families from a Prometheus text or
+// OpenMetrics exposition (a descriptor file or a scrape): names and types
+// from # TYPE, help from # HELP, and labels from the samples.
+func ParseOpenMetrics(file, content string) []Metric {
+	byName := make(map[string]*Metric)
+	var order []string
+	get := func(name string, line int) *Metric {
+		if m, ok := byName[name]; ok {
+			return m
+		}
+		m := &Metric{Name: name, Type: "untyped", Defined: fmt.Sprintf("%s:%d", file, line)}
+		byName[name] = m
+		order = append(order, name)
+		return m
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if m := openMetricsMetaRe.FindStringSubmatch(text); m != nil {
+			metric := get(m[2], line)
+			switch m[1] {
+			case "HELP":
+				metric.Help = strings.ReplaceAll(m[3], `\n`, "\n")
+			case "TYPE":
+				metric.Type = m[3]
+			}
+			continue
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		m := openMetricsSampleRe.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		name := m[1]
+		if _, ok := byName[name]; !ok {
+			for _, suffix := range sampleSuffixes {
+				if base := strings.TrimSuffix(name, suffix); base != name {
+					if _, ok := byName[base]; ok {
+						name = base
+						break
+					}
+				}
+			}
+		}
+		metric := get(name, line)
+		for _, l := range openMetricsLabelRe.FindAllStringSubmatch(m[2], -1) {
+			if l[1] != "le" && l[1] != "quantile" && !contains(metric.Labels, l[1]) {
+				metric.Labels = append(metric.Labels, l[1])
+			}
+		}
+	}
+
+	metrics := make([]Metric, 0, len(order))
+	for _, name := range order {
+		metrics = append(metrics, *byName[name])
+	}
+	sortMetrics(metrics)
+	return metrics
+}
+
+// MergeMetrics combines metric lists; the first definition of a name wins and
+// later ones only fill in its missing help and labels.
+func MergeMetrics(lists ...[]Metric) []Metric {
+	byName := make(map[string]int)
+	var merged []Metric
+	for _, list := range lists {
+		for _, m := range list {
+			i, ok := byName[m.Name]
+			if !ok {
+				byName[m.Name] = len(merged)
+				merged = append(merged, m)
+				continue
+			}
+			if merged[i].Help == "" {
+				merged[i].Help = m.Help
+			}
+			if len(merged[i].Labels) == 0 {
+				merged[i].Labels = m.Labels
+			}
+		}
+	}
+	sortMetrics(merged)
+	return merged
+}
+
+func sortMetrics(metrics []Metric) {
+	sort.SliceStable(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+// Package reference extracts reference material from a package's sources —
+// the facts reference pages are built from, found by parsing the code rather
+// than asking an LLM to remember it.
+package reference
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// goFile is one parsed non-test Go file.
+type goFile struct {
+	path    string // Relative to the scanned root, slash-separated
+	pkgPath string // Import path of the file's package
+	ast     *ast.File
+	imports map[string]string // Local name -> import path
+}
+
+var moduleRe = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// modulePath returns the module path declared in root's go.mod, or "" when
+// root is not a module root.
+func modulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod")) //nolint:gosec // module root from caller
+	if err != nil {
+		return ""
+	}
+	if m := moduleRe.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// parseGoFiles parses the non-test Go files under root's dirs (all of root
+// when dirs is empty). Hidden, vendor and testdata directories are skipped.
+func parseGoFiles(root string, dirs []string) (*token.FileSet, []*goFile, error) {
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	module := modulePath(root)
+	fset := token.NewFileSet()
+	var files []*goFile
+	seen := make(map[string]bool)
+
+	for _, dir := range dirs {
+		start := filepath.Join(root, dir)
+		err := filepath.WalkDir(start, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := d.Name()
+			if d.IsDir() {
+				if path != start && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || seen[path] {
+				return nil
+			}
+			seen[path] = true
+			f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			rel, _ := filepath.Rel(root, path)
+			rel = filepath.ToSlash(rel)
+			pkgPath := filepath.ToSlash(filepath.Dir(rel))
+			if module != "" {
+				pkgPath = strings.TrimSuffix(module+"/"+pkgPath, "/.")
+			}
+			gf := &goFile{path: rel, pkgPath: pkgPath, ast: f, imports: make(map[string]string)}
+			for _, imp := range f.Imports {
+				p := strings.Trim(imp.Path.Value, `"`)
+				local := p[strings.LastIndex(p, "/")+1:]
+				if imp.Name != nil {
+					local = imp.Name.Name
+				}
+				gf.imports[local] = p
+			}
+			files = append(files, gf)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return fset, files, nil
+}
+
+// funcName names a function declaration as pkg.Func or pkg.Type.Method.
+func funcName(pkg string, fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return pkg + "." + fn.Name.Name
+	}
+	return pkg + "." + typeName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+}
+
+// typeName returns the name of a (possibly pointer or generic) named type
+// expression, or "" for other types.
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return typeName(t.X)
+	case *ast.IndexExpr:
+		return typeName(t.X)
+	case *ast.IndexListExpr:
+		return typeName(t.X)
+	}
+	return ""
+}
+
+// stringLit returns the value of a string literal expression.
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	if strings.HasPrefix(lit.Value, "`") {
+		return strings.Trim(lit.Value, "`"), true
+	}
+	var s string
+	if _, err := fmt.Sscanf(lit.Value, "%q", &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// isCall reports whether expr calls pkg.fn, where pkg is the local name of
+// importPath in f.
+func isCall(f *goFile, expr ast.Expr, importPath string, fns ...string) (*ast.CallExpr, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || f.imports[x.Name] != importPath {
+		return nil, false
+	}
+	for _, fn := range fns {
+		if sel.Sel.Name == fn {
+			return call, true
+		}
+	}
+	return nil, false
+}
+
+// docText returns the first sentence of a doc comment.
+func docText(doc *ast.CommentGroup) string {
+	text := strings.Join(strings.Fields(doc.Text()), " ")
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+	return text
+}
+
+// stringConsts collects the string constants of every package, keyed by
+// package path and name, so values built from constants can be resolved.
+func stringConsts(files []*goFile) map[string]map[string]string {
+	consts := make(map[string]map[string]string)
+	// Constants may be defined from other constants; resolve until stable.
+	for changed := true; changed; {
+		changed = false
+		for _, f := range files {
+			for _, decl := range f.ast.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.CONST {
+					continue
+				}
+				for _, s := range gd.Specs {
+					vs := s.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if i >= len(vs.Values) {
+							continue
+						}
+						if _, done := consts[f.pkgPath][name.Name]; done {
+							continue
+						}
+						if v, ok := resolveString(f, vs.Values[i], consts); ok {
+							if consts[f.pkgPath] == nil {
+								consts[f.pkgPath] = make(map[string]string)
+							}
+							consts[f.pkgPath][name.Name] = v
+							changed = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// resolveString evaluates a string expression made of literals, constants
+// and concatenations.
+func resolveString(f *goFile, expr ast.Expr, consts map[string]map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return stringLit(e)
+	case *ast.ParenExpr:
+		return resolveString(f, e.X, consts)
+	case *ast.Ident:
+		v, ok := consts[f.pkgPath][e.Name]
+		return v, ok
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			v, ok := consts[f.imports[x.Name]][e.Sel.Name]
+			return v, ok
+		}
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		l, ok := resolveString(f, e.X, consts)
+		if !ok {
+			return "", false
+		}
+		r, ok := resolveString(f, e.Y, consts)
+		return l + r, ok
+	}
+	return "", false
+}
+
+// stringList evaluates a []string{...} literal.
+func stringList(f *goFile, expr ast.Expr, consts map[string]map[string]string) ([]string, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	var out []string
+	for _, elt := range lit.Elts {
+		v, ok := resolveString(f, elt, consts)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, v)
+	}
+	return out, true
+}
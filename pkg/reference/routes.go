@@ -0,0 +1,350 @@
+package reference
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Route is one HTTP endpoint.
+type Route struct {
+	Method      string   `json:"method"` // Upper-case verb, or ANY
+	Path        string   `json:"path"`   // With parameters written as {name}
+	Handler     string   `json:"handler,omitempty"`
+	Middleware  []string `json:"middleware,omitempty"`
+	Summary     string   `json:"summary,omitempty"` // From the OpenAPI spec
+	OperationID string   `json:"operation_id,omitempty"`
+	Defined     string   `json:"defined,omitempty"` // file:line
+}
+
+// routerCtx is what a router variable contributes to the routes registered
+// on it: the path prefix of its group and the middleware in effect.
+type routerCtx struct {
+	prefix     string
+	middleware []string
+}
+
+func (c routerCtx) with(prefix string, middleware ...string) routerCtx {
+	return routerCtx{
+		prefix:     joinRoute(c.prefix, prefix),
+		middleware: append(append([]string(nil), c.middleware...), middleware...),
+	}
+}
+
+var (
+	httpMethods = map[string]bool{
+		"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+		"HEAD": true, "OPTIONS": true, "CONNECT": true, "TRACE": true,
+	}
+	routerImports = []string{"net/http", "github.com/go-chi/chi", "github.com/gin-gonic/gin"}
+	paramRe       = regexp.MustCompile(`[:*]([A-Za-z_]\w*)|\{([A-Za-z_]\w*)(?:\.\.\.|:[^}]*)?\}`)
+)
+
+// Routes statically finds the HTTP route registrations of net/http,
+// chi and gin routers in the Go files under root's dirs (all of root when
+// dirs is empty). Group prefixes (chi Route, gin Group) and middleware (Use,
+// With, group handlers) are followed within a function.
+func Routes(root string, dirs []string) ([]Route, error) {
+	fset, files, err := parseGoFiles(root, dirs)
+	if err != nil {
+		return nil, err
+	}
+	consts := stringConsts(files)
+
+	var routes []Route
+	for _, f := range files {
+		if !importsRouter(f) {
+			continue
+		}
+		s := &routeScanner{f: f, fset: fset, consts: consts}
+		for _, decl := range f.ast.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				s.block(fn.Body, map[string]routerCtx{})
+			}
+		}
+		routes = append(routes, s.routes...)
+	}
+	sortRoutes(routes)
+	return routes, nil
+}
+
+func importsRouter(f *goFile) bool {
+	for _, p := range f.imports {
+		for _, r := range routerImports {
+			if p == r || strings.HasPrefix(p, r+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type routeScanner struct {
+	f      *goFile
+	fset   *token.FileSet
+	consts map[string]map[string]string
+	routes []Route
+}
+
+// block walks a function body in source order, tracking router variables.
+func (s *routeScanner) block(body ast.Node, env map[string]routerCtx) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// g := r.Group("/api", mw) and x := r.With(mw) derive routers.
+			for i, rhs := range n.Rhs {
+				if i >= len(n.Lhs) {
+					break
+				}
+				id, ok := n.Lhs[i].(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if ctx, ok := s.derived(rhs, env); ok {
+					env[id.Name] = ctx
+				}
+			}
+		case *ast.CallExpr:
+			return s.call(n, env)
+		}
+		return true
+	})
+}
+
+// derived returns the router context of an expression that derives a router
+// from another (gin Group, chi With).
+func (s *routeScanner) derived(expr ast.Expr, env map[string]routerCtx) (routerCtx, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return routerCtx{}, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return routerCtx{}, false
+	}
+	switch sel.Sel.Name {
+	case "Group":
+		if len(call.Args) > 0 {
+			if prefix, ok := resolveString(s.f, call.Args[0], s.consts); ok {
+				return s.ctx(sel.X, env).with(prefix, s.names(call.Args[1:])...), true
+			}
+		}
+	case "With":
+		return s.ctx(sel.X, env).with("", s.names(call.Args)...), true
+	}
+	return routerCtx{}, false
+}
+
+// ctx returns the router context of a receiver expression.
+func (s *routeScanner) ctx(expr ast.Expr, env map[string]routerCtx) routerCtx {
+	if id, ok := expr.(*ast.Ident); ok {
+		return env[id.Name]
+	}
+	if ctx, ok := s.derived(expr, env); ok {
+		return ctx
+	}
+	return routerCtx{}
+}
+
+// call handles one call: middleware, nested groups and route registrations.
+// It reports whether the walk should descend into the call.
+func (s *routeScanner) call(call *ast.CallExpr, env map[string]routerCtx) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	name, args := sel.Sel.Name, call.Args
+
+	switch name {
+	case "Use":
+		if id, ok := sel.X.(*ast.Ident); ok {
+			ctx := env[id.Name]
+			env[id.Name] = ctx.with("", s.names(args)...)
+		}
+		return true
+	case "Route", "Group":
+		// chi: r.Route("/p", func(r chi.Router) {...}) and r.Group(func(r chi.Router) {...})
+		prefix := ""
+		if name == "Route" && len(args) == 2 {
+			prefix, ok = resolveString(s.f, args[0], s.consts)
+			if !ok {
+				return true
+			}
+		}
+		lit, ok := args[len(args)-1].(*ast.FuncLit)
+		if !ok || len(args) > 2 || lit.Type.Params.NumFields() != 1 || len(lit.Type.Params.List[0].Names) != 1 {
+			return true
+		}
+		inner := make(map[string]routerCtx, len(env))
+		for k, v := range env {
+			inner[k] = v
+		}
+		inner[lit.Type.Params.List[0].Names[0].Name] = s.ctx(sel.X, env).with(prefix)
+		s.block(lit.Body, inner)
+		return false
+	}
+
+	method, pattern, handlers := s.registration(name, args)
+	if pattern == "" {
+		return true
+	}
+	if m, rest, ok := strings.Cut(pattern, " "); ok && httpMethods[m] {
+		// Go 1.22 net/http patterns: "GET /items/{id}".
+		method, pattern = m, strings.TrimSpace(rest)
+	}
+	if !strings.HasPrefix(pattern, "/") {
+		return true
+	}
+
+	ctx := s.ctx(sel.X, env)
+	route := Route{
+		Method:     method,
+		Path:       normalizeRoute(joinRoute(ctx.prefix, pattern)),
+		Middleware: ctx.middleware,
+		Defined:    fmt.Sprintf("%s:%d", s.f.path, s.fset.Position(call.Pos()).Line),
+	}
+	if len(handlers) > 0 {
+		route.Handler = handlerName(handlers[len(handlers)-1])
+		route.Middleware = append(append([]string(nil), route.Middleware...), s.names(handlers[:len(handlers)-1])...)
+	}
+	if name == "Mount" {
+		route.Path = joinRoute(route.Path, "*")
+	}
+	s.routes = append(s.routes, route)
+	return true
+}
+
+// registration decodes a route-registering call into its method, path
+// pattern and handler chain.
+func (s *routeScanner) registration(name string, args []ast.Expr) (method, pattern string, handlers []ast.Expr) {
+	str := func(i int) string {
+		if i >= len(args) {
+			return ""
+		}
+		v, _ := resolveString(s.f, args[i], s.consts)
+		return v
+	}
+	upper := strings.ToUpper(name)
+	switch {
+	case httpMethods[upper] && len(args) >= 2:
+		// chi r.Get("/p", h) and gin r.GET("/p", mw..., h)
+		return upper, str(0), args[1:]
+	case name == "Any" && len(args) >= 2:
+		return "ANY", str(0), args[1:]
+	case (name == "Method" || name == "MethodFunc") && len(args) == 3:
+		return strings.ToUpper(str(0)), str(1), args[2:]
+	case name == "Handle" && len(args) >= 3 && httpMethods[str(0)]:
+		// gin r.Handle("GET", "/p", h...)
+		return str(0), str(1), args[2:]
+	case (name == "Handle" || name == "HandleFunc" || name == "Mount") && len(args) == 2:
+		return "ANY", str(0), args[1:]
+	}
+	return "", "", nil
+}
+
+// names renders middleware or handler expressions.
+func (s *routeScanner) names(exprs []ast.Expr) []string {
+	out := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		out = append(out, handlerName(e))
+	}
+	return out
+}
+
+func handlerName(expr ast.Expr) string {
+	if _, ok := expr.(*ast.FuncLit); ok {
+		return "func literal"
+	}
+	return types.ExprString(expr)
+}
+
+func joinRoute(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" || path == "/" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// normalizeRoute writes path parameters in OpenAPI style: gin's :id and
+// *path, chi's {id:[0-9]+} and net/http's {path...} all become {name}.
+func normalizeRoute(path string) string {
+	return paramRe.ReplaceAllStringFunc(path, func(m string) string {
+		sub := paramRe.FindStringSubmatch(m)
+		return "{" + sub[1] + sub[2] + "}"
+	})
+}
+
+func sortRoutes(routes []Route) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+}
+
+// MergeOpenAPI annotates routes with the summaries and operation IDs of an
+// OpenAPI document (JSON or YAML) and appends the operations the code scan
+// did not find. Paths are matched after normalizing parameters, and a route
+// registered for ANY method matches every operation on its path.
+func MergeOpenAPI(routes []Route, spec []byte) ([]Route, error) {
+	var doc struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	// YAML is a superset of JSON, so one decoder reads both.
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	index := make(map[string]int)
+	for i, r := range routes {
+		index[r.Method+" "+normalizeRoute(r.Path)] = i
+	}
+	merged := append([]Route(nil), routes...)
+	for path, ops := range doc.Paths {
+		norm := normalizeRoute(path)
+		for method, node := range ops {
+			method = strings.ToUpper(method)
+			if !httpMethods[method] {
+				continue // parameters, servers, summary, ...
+			}
+			var op struct {
+				Summary     string `yaml:"summary"`
+				Description string `yaml:"description"`
+				OperationID string `yaml:"operationId"`
+			}
+			if err := node.Decode(&op); err != nil {
+				return nil, fmt.Errorf("failed to parse OpenAPI operation %s %s: %w", method, path, err)
+			}
+			summary := op.Summary
+			if summary == "" {
+				summary = op.Description
+			}
+			i, ok := index[method+" "+norm]
+			if !ok {
+				i, ok = index["ANY "+norm]
+			}
+			if !ok {
+				merged = append(merged, Route{Method: method, Path: norm, Summary: summary, OperationID: op.OperationID})
+				continue
+			}
+			if merged[i].Summary == "" {
+				merged[i].Summary = summary
+			}
+			if merged[i].OperationID == "" {
+				merged[i].OperationID = op.OperationID
+			}
+		}
+	}
+	sortRoutes(merged)
+	return merged, nil
+}
@@ -0,0 +1,653 @@
+package reference
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SQLSchema is the database schema built up by replaying DDL.
+type SQLSchema struct {
+	Tables []*SQLTable `json:"tables"`
+}
+
+// SQLTable is one table of a schema.
+type SQLTable struct {
+	Name        string          `json:"name"`
+	Comment     string          `json:"comment,omitempty"`
+	Columns     []*SQLColumn    `json:"columns"`
+	Indexes     []SQLIndex      `json:"indexes,omitempty"`
+	ForeignKeys []SQLForeignKey `json:"foreign_keys,omitempty"`
+}
+
+// SQLColumn is one column of a table.
+type SQLColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	Default    string `json:"default,omitempty"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// SQLIndex is an index or unique constraint.
+type SQLIndex struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// SQLForeignKey is a reference from columns of a table to another table.
+type SQLForeignKey struct {
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns,omitempty"`
+}
+
+// Table returns the named table, or nil.
+func (s *SQLSchema) Table(name string) *SQLTable {
+	for _, t := range s.Tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// Column returns the named column, or nil.
+func (t *SQLTable) Column(name string) *SQLColumn {
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// MigrationFiles lists the up-migration SQL files at paths relative to root,
+// in the order they apply: directories contribute their *.sql files sorted
+// by name, and golang-migrate down files (*.down.sql) are skipped.
+func MigrationFiles(root string, paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		full := filepath.Join(root, p)
+		info, err := os.Stat(full)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, full)
+			continue
+		}
+		var found []string
+		err = filepath.WalkDir(full, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(path, ".sql") && !strings.HasSuffix(path, ".down.sql") {
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(found, func(i, j int) bool { return filepath.Base(found[i]) < filepath.Base(found[j]) })
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+var (
+	gooseUpRe   = regexp.MustCompile(`(?m)^--\s*(?:\+goose\s+Up|migrate:up)\b`)
+	gooseDownRe = regexp.MustCompile(`(?m)^--\s*(?:\+goose\s+Down|migrate:down)\b`)
+)
+
+// UpMigration returns the part of a migration file that applies it: the Up
+// section of goose and dbmate files, or the whole file.
+func UpMigration(content string) string {
+	if loc := gooseUpRe.FindStringIndex(content); loc != nil {
+		content = content[loc[1]:]
+	}
+	if loc := gooseDownRe.FindStringIndex(content); loc != nil {
+		content = content[:loc[0]]
+	}
+	return content
+}
+
+// DumpSchema prints the DDL of a development database with the database's
+// own client: pg_dump for postgres:// DSNs, mysqldump for mysql:// DSNs and
+// sqlite3 for sqlite:// DSNs or paths to a database file.
+func DumpSchema(dsn string) (string, error) {
+	var cmd *exec.Cmd
+	switch {
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		cmd = exec.Command("pg_dump", "--schema-only", "--no-owner", "--no-privileges", dsn) //nolint:gosec // DSN from config
+	case strings.HasPrefix(dsn, "mysql://"):
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("invalid DSN: %w", err)
+		}
+		args := []string{"--no-data", "--skip-comments", "-h", u.Hostname()}
+		if port := u.Port(); port != "" {
+			args = append(args, "-P", port)
+		}
+		if user := u.User.Username(); user != "" {
+			args = append(args, "-u", user)
+		}
+		cmd = exec.Command("mysqldump", append(args, strings.TrimPrefix(u.Path, "/"))...) //nolint:gosec // DSN from config
+		if pass, ok := u.User.Password(); ok {
+			// Keep the password off the command line.
+			cmd.Env = append(os.Environ(), "MYSQL_PWD="+pass)
+		}
+	default:
+		cmd = exec.Command("sqlite3", strings.TrimPrefix(dsn, "sqlite://"), ".schema") //nolint:gosec // DSN from config
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		var stderr string
+		if ee, ok := err.(*exec.ExitError); ok {
+			stderr = strings.TrimSpace(string(ee.Stderr))
+		}
+		return "", fmt.Errorf("%s failed: %w %s", cmd.Args[0], err, stderr)
+	}
+	return string(out), nil
+}
+
+// Apply replays DDL onto the schema: CREATE/ALTER/DROP TABLE, CREATE/DROP
+// INDEX and COMMENT ON. Other statements are ignored.
+func (s *SQLSchema) Apply(ddl string) {
+	for _, stmt := range splitSQL(stripSQLComments(ddl)) {
+		stmt = strings.Join(strings.Fields(stmt), " ")
+		switch {
+		case createTableRe.MatchString(stmt):
+			m := createTableRe.FindStringSubmatch(stmt)
+			s.createTable(sqlName(m[1]), m[2])
+		case createIndexRe.MatchString(stmt):
+			m := createIndexRe.FindStringSubmatch(stmt)
+			if t := s.Table(sqlName(m[3])); t != nil {
+				t.Indexes = append(t.Indexes, SQLIndex{Name: sqlName(m[2]), Columns: sqlNames(m[4]), Unique: m[1] != ""})
+			}
+		case alterTableRe.MatchString(stmt):
+			m := alterTableRe.FindStringSubmatch(stmt)
+			if t := s.Table(sqlName(m[1])); t != nil {
+				for _, action := range splitTopLevel(m[2], ',') {
+					s.alter(t, strings.TrimSpace(action))
+				}
+			}
+		case dropTableRe.MatchString(stmt):
+			for _, name := range sqlNames(dropTableRe.FindStringSubmatch(stmt)[1]) {
+				s.dropTable(name)
+			}
+		case dropIndexRe.MatchString(stmt):
+			for _, name := range sqlNames(dropIndexRe.FindStringSubmatch(stmt)[1]) {
+				for _, t := range s.Tables {
+					t.Indexes = removeIndex(t.Indexes, name)
+				}
+			}
+		case commentRe.MatchString(stmt):
+			m := commentRe.FindStringSubmatch(stmt)
+			s.comment(strings.ToUpper(m[1]), m[2], unquoteSQL(m[3]))
+		}
+	}
+}
+
+const sqlIdent = `(?:"[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\]|[\w$]+)`
+
+var (
+	createTableRe = regexp.MustCompile(`(?is)^CREATE (?:OR REPLACE )?(?:(?:GLOBAL|LOCAL) )?(?:TEMP(?:ORARY)? |UNLOGGED )?TABLE (?:IF NOT EXISTS )?(` + sqlIdent + `(?:\.` + sqlIdent + `)?) ?\((.*)\)[^)]*$`)
+	createIndexRe = regexp.MustCompile(`(?is)^CREATE (UNIQUE )?INDEX (?:CONCURRENTLY )?(?:IF NOT EXISTS )?(` + sqlIdent + `(?:\.` + sqlIdent + `)?)? ?ON (?:ONLY )?(` + sqlIdent + `(?:\.` + sqlIdent + `)?) ?(?:USING \w+ ?)?\((.*)\)`)
+	alterTableRe  = regexp.MustCompile(`(?is)^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?(` + sqlIdent + `(?:\.` + sqlIdent + `)?) (.*)$`)
+	dropTableRe   = regexp.MustCompile(`(?is)^DROP TABLE (?:IF EXISTS )?(.+?)(?: CASCADE| RESTRICT)?$`)
+	dropIndexRe   = regexp.MustCompile(`(?is)^DROP INDEX (?:CONCURRENTLY )?(?:IF EXISTS )?(.+?)(?: ON .*)?(?: CASCADE| RESTRICT)?$`)
+	commentRe     = regexp.MustCompile(`(?is)^COMMENT ON (TABLE|COLUMN) (\S+) IS ('(?:[^']|'')*')$`)
+	fkRe          = regexp.MustCompile(`(?is)REFERENCES (` + sqlIdent + `(?:\.` + sqlIdent + `)?) ?(?:\(([^)]*)\))?`)
+	parenListRe   = regexp.MustCompile(`\(([^)]*)\)`)
+
+	// columnStops are the keywords that end a column's type.
+	columnStops = map[string]bool{
+		"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "REFERENCES": true,
+		"UNIQUE": true, "CHECK": true, "CONSTRAINT": true, "COLLATE": true, "GENERATED": true,
+		"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "COMMENT": true, "ON": true, "IDENTITY": true,
+	}
+)
+
+func (s *SQLSchema) createTable(name, body string) {
+	s.dropTable(name)
+	t := &SQLTable{Name: name}
+	for _, def := range splitTopLevel(body, ',') {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		if !s.tableConstraint(t, def) {
+			if c := parseColumn(t, def); c != nil {
+				t.Columns = append(t.Columns, c)
+			}
+		}
+	}
+	s.Tables = append(s.Tables, t)
+}
+
+// tableConstraint applies a table-level constraint or index definition and
+// reports whether def was one.
+func (s *SQLSchema) tableConstraint(t *SQLTable, def string) bool {
+	words := strings.Fields(strings.ToUpper(def))
+	if len(words) == 0 {
+		return false
+	}
+	name := ""
+	if words[0] == "CONSTRAINT" && len(words) > 2 {
+		name = sqlName(strings.Fields(def)[1])
+		words = words[2:]
+	}
+	lists := parenListRe.FindAllStringSubmatch(def, -1)
+	switch words[0] {
+	case "PRIMARY":
+		if len(lists) > 0 {
+			for _, col := range sqlNames(lists[0][1]) {
+				if c := t.Column(col); c != nil {
+					c.PrimaryKey, c.Nullable = true, false
+				}
+			}
+		}
+	case "FOREIGN":
+		if m := fkRe.FindStringSubmatch(def); m != nil && len(lists) > 0 {
+			t.ForeignKeys = append(t.ForeignKeys, SQLForeignKey{Columns: sqlNames(lists[0][1]), RefTable: sqlName(m[1]), RefColumns: sqlNames(m[2])})
+		}
+	case "UNIQUE", "KEY", "INDEX":
+		if len(lists) > 0 {
+			if name == "" && words[0] != "UNIQUE" && len(words) > 1 && !strings.HasPrefix(words[1], "(") {
+				name = sqlName(strings.Fields(def)[1])
+			}
+			t.Indexes = append(t.Indexes, SQLIndex{Name: name, Columns: sqlNames(lists[0][1]), Unique: words[0] == "UNIQUE"})
+		}
+	case "CHECK", "EXCLUDE", "FULLTEXT", "SPATIAL":
+	default:
+		return name != ""
+	}
+	return true
+}
+
+// parseColumn parses a column definition, recording inline foreign keys.
+func parseColumn(t *SQLTable, def string) *SQLColumn {
+	tokens := splitTopLevel(def, ' ')
+	if len(tokens) < 1 {
+		return nil
+	}
+	c := &SQLColumn{Name: sqlName(tokens[0]), Nullable: true}
+	i := 1
+	var typ []string
+	for ; i < len(tokens) && !columnStops[strings.ToUpper(tokens[i])]; i++ {
+		typ = append(typ, tokens[i])
+	}
+	c.Type = strings.Join(typ, " ")
+	for ; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "NOT":
+			if i+1 < len(tokens) && strings.EqualFold(tokens[i+1], "NULL") {
+				c.Nullable = false
+				i++
+			}
+		case "PRIMARY":
+			c.PrimaryKey, c.Nullable = true, false
+		case "DEFAULT":
+			if i+1 < len(tokens) {
+				c.Default = tokens[i+1]
+				i++
+			}
+		case "COMMENT":
+			if i+1 < len(tokens) {
+				c.Comment = unquoteSQL(tokens[i+1])
+				i++
+			}
+		}
+	}
+	if m := fkRe.FindStringSubmatch(def); m != nil {
+		t.ForeignKeys = append(t.ForeignKeys, SQLForeignKey{Columns: []string{c.Name}, RefTable: sqlName(m[1]), RefColumns: sqlNames(m[2])})
+	}
+	upper := strings.ToUpper(def)
+	if strings.Contains(upper, " UNIQUE") && !c.PrimaryKey {
+		t.Indexes = append(t.Indexes, SQLIndex{Columns: []string{c.Name}, Unique: true})
+	}
+	return c
+}
+
+// alter applies one ALTER TABLE action.
+func (s *SQLSchema) alter(t *SQLTable, action string) {
+	words := strings.Fields(action)
+	upper := strings.Fields(strings.ToUpper(action))
+	if len(upper) < 2 {
+		return
+	}
+	// Drop optional COLUMN / IF [NOT] EXISTS noise words.
+	skip := func(from int) int {
+		for from < len(upper) && (upper[from] == "COLUMN" || upper[from] == "IF" || upper[from] == "NOT" || upper[from] == "EXISTS") {
+			from++
+		}
+		return from
+	}
+	switch upper[0] {
+	case "ADD":
+		rest := strings.Join(words[1:], " ")
+		if s.tableConstraint(t, rest) {
+			return
+		}
+		i := skip(1)
+		if i < len(words) {
+			if c := parseColumn(t, strings.Join(words[i:], " ")); c != nil && t.Column(c.Name) == nil {
+				t.Columns = append(t.Columns, c)
+			}
+		}
+	case "DROP":
+		i := skip(1)
+		if (upper[1] == "INDEX" || upper[1] == "KEY") && len(words) > 2 {
+			t.Indexes = removeIndex(t.Indexes, sqlName(words[2]))
+			return
+		}
+		if upper[1] == "CONSTRAINT" || upper[1] == "PRIMARY" || upper[1] == "FOREIGN" || i >= len(words) {
+			return
+		}
+		name := sqlName(words[i])
+		for j, c := range t.Columns {
+			if strings.EqualFold(c.Name, name) {
+				t.Columns = append(t.Columns[:j], t.Columns[j+1:]...)
+				break
+			}
+		}
+	case "RENAME":
+		if upper[1] == "TO" && len(words) > 2 {
+			t.Name = sqlName(words[2])
+			return
+		}
+		i := skip(1)
+		if i+2 < len(words) && upper[i+1] == "TO" {
+			if c := t.Column(sqlName(words[i])); c != nil {
+				c.Name = sqlName(words[i+2])
+			}
+		}
+	case "ALTER", "MODIFY", "CHANGE":
+		i := skip(1)
+		if i >= len(words) {
+			return
+		}
+		c := t.Column(sqlName(words[i]))
+		if c == nil {
+			return
+		}
+		rest := strings.ToUpper(strings.Join(upper[i+1:], " "))
+		switch {
+		case upper[0] == "CHANGE" && i+2 < len(words):
+			// MySQL: CHANGE old new type ...
+			if nc := parseColumn(t, strings.Join(words[i+1:], " ")); nc != nil {
+				*c = *nc
+			}
+		case upper[0] == "MODIFY":
+			if nc := parseColumn(t, strings.Join(words[i:], " ")); nc != nil {
+				*c = *nc
+			}
+		case strings.HasPrefix(rest, "TYPE ") || strings.HasPrefix(rest, "SET DATA TYPE "):
+			typ := words[i+2:]
+			if strings.HasPrefix(rest, "SET DATA TYPE ") {
+				typ = words[i+4:]
+			}
+			for j, w := range typ {
+				if strings.EqualFold(w, "USING") || strings.EqualFold(w, "COLLATE") {
+					typ = typ[:j]
+					break
+				}
+			}
+			c.Type = strings.Join(typ, " ")
+		case rest == "SET NOT NULL":
+			c.Nullable = false
+		case rest == "DROP NOT NULL":
+			c.Nullable = true
+		case strings.HasPrefix(rest, "SET DEFAULT "):
+			c.Default = strings.Join(words[i+3:], " ")
+		case rest == "DROP DEFAULT":
+			c.Default = ""
+		}
+	}
+}
+
+func (s *SQLSchema) dropTable(name string) {
+	for i, t := range s.Tables {
+		if strings.EqualFold(t.Name, name) {
+			s.Tables = append(s.Tables[:i], s.Tables[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *SQLSchema) comment(kind, target, text string) {
+	parts := strings.Split(target, ".")
+	for i := range parts {
+		parts[i] = sqlName(parts[i])
+	}
+	if kind == "TABLE" {
+		if t := s.Table(strings.Join(parts, ".")); t != nil {
+			t.Comment = text
+		} else if t := s.Table(parts[len(parts)-1]); t != nil {
+			t.Comment = text
+		}
+		return
+	}
+	if len(parts) < 2 {
+		return
+	}
+	t := s.Table(strings.Join(parts[:len(parts)-1], "."))
+	if t == nil {
+		t = s.Table(parts[len(parts)-2])
+	}
+	if t != nil {
+		if c := t.Column(parts[len(parts)-1]); c != nil {
+			c.Comment = text
+		}
+	}
+}
+
+func removeIndex(indexes []SQLIndex, name string) []SQLIndex {
+	out := indexes[:0]
+	for _, idx := range indexes {
+		if !strings.EqualFold(idx.Name, name) {
+			out = append(out, idx)
+		}
+	}
+	return out
+}
+
+// sqlName unquotes an identifier and drops the default "public" schema.
+func sqlName(name string) string {
+	var parts []string
+	for _, p := range strings.Split(strings.TrimSpace(name), ".") {
+		parts = append(parts, strings.Trim(p, "\"`[]"))
+	}
+	if len(parts) == 2 && strings.EqualFold(parts[0], "public") {
+		parts = parts[1:]
+	}
+	return strings.Join(parts, ".")
+}
+
+// sqlNames splits a comma-separated identifier list, dropping sort orders
+// and expressions' extra words.
+func sqlNames(list string) []string {
+	var names []string
+	for _, part := range splitTopLevel(list, ',') {
+		fields := strings.Fields(part)
+		if len(fields) > 0 {
+			names = append(names, sqlName(fields[0]))
+		}
+	}
+	return names
+}
+
+func unquoteSQL(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		s = strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// stripSQLComments removes -- and /* */ comments outside quoted text.
+func stripSQLComments(sql string) string {
+	var sb strings.Builder
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			sb.WriteByte('\n')
+			continue
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return sb.String()
+			}
+			i += end + 3
+			sb.WriteByte(' ')
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+var dollarQuoteRe = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+// splitSQL splits a script into statements on semicolons outside quotes and
+// dollar-quoted bodies.
+func splitSQL(sql string) []string {
+	var stmts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '$':
+			if tag := dollarQuoteRe.FindString(sql[i:]); tag != "" {
+				end := strings.Index(sql[i+len(tag):], tag)
+				if end < 0 {
+					i = len(sql)
+				} else {
+					i += len(tag) + end + len(tag) - 1
+				}
+			}
+		case c == ';':
+			if s := strings.TrimSpace(sql[start:i]); s != "" {
+				stmts = append(stmts, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(sql[start:]); s != "" {
+		stmts = append(stmts, s)
+	}
+	return stmts
+}
+
+// splitTopLevel splits s on sep outside parentheses and quotes. Splitting on
+// a space also collapses runs of whitespace.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			if part := s[start:i]; sep != ' ' || strings.TrimSpace(part) != "" {
+				parts = append(parts, strings.TrimSpace(part))
+			}
+			start = i + 1
+		}
+	}
+	if part := strings.TrimSpace(s[start:]); part != "" || sep != ' ' {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+var mermaidNameRe = regexp.MustCompile(`[^\w-]+`)
+
+// Mermaid renders the schema as a Mermaid erDiagram.
+func (s *SQLSchema) Mermaid() string {
+	name := func(n string) string { return mermaidNameRe.ReplaceAllString(n, "_") }
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+	for _, t := range s.Tables {
+		fks := make(map[string]bool)
+		for _, fk := range t.ForeignKeys {
+			for _, c := range fk.Columns {
+				fks[strings.ToLower(c)] = true
+			}
+		}
+		fmt.Fprintf(&sb, "    %s {\n", name(t.Name))
+		for _, c := range t.Columns {
+			typ := strings.NewReplacer(" ", "_", ",", "_").Replace(c.Type)
+			if typ == "" {
+				typ = "unknown"
+			}
+			var keys []string
+			if c.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if fks[strings.ToLower(c.Name)] {
+				keys = append(keys, "FK")
+			}
+			fmt.Fprintf(&sb, "        %s %s", typ, name(c.Name))
+			if len(keys) > 0 {
+				sb.WriteString(" " + strings.Join(keys, ","))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("    }\n")
+	}
+	for _, t := range s.Tables {
+		for _, fk := range t.ForeignKeys {
+			if s.Table(fk.RefTable) == nil {
+				continue
+			}
+			parent := "||"
+			for _, col := range fk.Columns {
+				if c := t.Column(col); c != nil && c.Nullable {
+					parent = "|o"
+				}
+			}
+			fmt.Fprintf(&sb, "    %s %s--o{ %s : %q\n", name(fk.RefTable), parent, name(t.Name), strings.Join(fk.Columns, ", "))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,266 @@
+package reference
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target is one target of a Makefile, Taskfile or justfile.
+type Target struct {
+	Name        string   `json:"name"`
+	Runner      string   `json:"runner"` // make, task or just
+	Description string   `json:"description,omitempty"`
+	Deps        []string `json:"deps,omitempty"`
+	Commands    []string `json:"commands,omitempty"` // Recipe lines, as written
+	Defined     string   `json:"defined"`            // file:line
+}
+
+// TaskFiles are the task runner files looked for when none are configured.
+var TaskFiles = []string{"Makefile", "makefile", "GNUmakefile", "Taskfile.yml", "Taskfile.yaml", "justfile", "Justfile", ".justfile"}
+
+// maxCommands caps the recipe lines kept per target.
+const maxCommands = 10
+
+// Targets parses a Makefile, Taskfile or justfile, chosen by file name.
+func Targets(path string) ([]Target, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // task file from caller
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(path)
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "taskfile"):
+		return parseTaskfile(name, data)
+	case strings.Contains(lower, "justfile"):
+		return parseJustfile(name, string(data)), nil
+	default:
+		return parseMakefile(name, string(data)), nil
+	}
+}
+
+var (
+	makeTargetRe = regexp.MustCompile(`^([^\s:=#][^:=#]*?)\s*::?(.*)$`)
+)
+
+// parseMakefile finds explicit targets. A target's description is the text
+// after "##" on its line, or else the comment lines right above it. Pattern
+// rules, special targets (.PHONY, ...) and variable assignments are skipped.
+func parseMakefile(file, content string) []Target {
+	var targets []Target
+	seen := make(map[string]int)
+	var comment []string
+	var current *Target
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "\t") {
+			if current != nil && len(current.Commands) < maxCommands {
+				current.Commands = append(current.Commands, strings.TrimSpace(line))
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			comment = append(comment, strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+			continue
+		}
+		m := makeTargetRe.FindStringSubmatch(line)
+		// Variable assignments (VAR := x, VAR ::= x) are not rules.
+		if m == nil || strings.HasPrefix(m[2], "=") || strings.HasPrefix(m[2], ":=") {
+			comment, current = nil, nil
+			continue
+		}
+		rest, desc, _ := strings.Cut(m[2], "##")
+		rest, _, _ = strings.Cut(rest, "#")
+		// Target-specific variables (target: VAR = value) are not rules.
+		if strings.Contains(rest, "=") {
+			comment, current = nil, nil
+			continue
+		}
+		desc = strings.TrimSpace(desc)
+		if desc == "" {
+			desc = strings.Join(comment, " ")
+		}
+		comment, current = nil, nil
+		deps, _, _ := strings.Cut(rest, ";")
+		for _, name := range strings.Fields(m[1]) {
+			if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "%$") {
+				continue
+			}
+			if j, ok := seen[name]; ok {
+				if targets[j].Description == "" {
+					targets[j].Description = desc
+				}
+				continue
+			}
+			seen[name] = len(targets)
+			targets = append(targets, Target{
+				Name:        name,
+				Runner:      "make",
+				Description: desc,
+				Deps:        strings.Fields(deps),
+				Defined:     fmt.Sprintf("%s:%d", file, i+1),
+			})
+			current = &targets[len(targets)-1]
+		}
+	}
+	return targets
+}
+
+// parseTaskfile reads the tasks of a go-task Taskfile in file order.
+// Internal tasks are skipped.
+func parseTaskfile(file string, data []byte) ([]Target, error) {
+	var doc struct {
+		Tasks yaml.Node `yaml:"tasks"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	var targets []Target
+	for i := 0; i+1 < len(doc.Tasks.Content); i += 2 {
+		key, node := doc.Tasks.Content[i], doc.Tasks.Content[i+1]
+		var task struct {
+			Desc     string      `yaml:"desc"`
+			Summary  string      `yaml:"summary"`
+			Internal bool        `yaml:"internal"`
+			Deps     []yaml.Node `yaml:"deps"`
+			Cmds     []yaml.Node `yaml:"cmds"`
+		}
+		if node.Kind == yaml.MappingNode {
+			if err := node.Decode(&task); err != nil {
+				return nil, fmt.Errorf("%s: task %s: %w", file, key.Value, err)
+			}
+		} else {
+			// Shorthand: a task given as a command or list of commands.
+			task.Cmds = []yaml.Node{*node}
+		}
+		if task.Internal {
+			continue
+		}
+		t := Target{Name: key.Value, Runner: "task", Description: task.Desc, Defined: fmt.Sprintf("%s:%d", file, key.Line)}
+		if t.Description == "" {
+			t.Description = strings.TrimSpace(task.Summary)
+		}
+		for _, d := range task.Deps {
+			if name := taskRef(&d, "task"); name != "" {
+				t.Deps = append(t.Deps, name)
+			}
+		}
+		for _, c := range task.Cmds {
+			for _, cmd := range taskCmds(&c) {
+				if len(t.Commands) < maxCommands {
+					t.Commands = append(t.Commands, cmd)
+				}
+			}
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// taskRef returns a scalar node's value, or the value of key in a mapping.
+func taskRef(n *yaml.Node, key string) string {
+	if n.Kind == yaml.ScalarNode {
+		return n.Value
+	}
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key {
+				return n.Content[i+1].Value
+			}
+		}
+	}
+	return ""
+}
+
+// taskCmds flattens a cmds entry: a command, a {cmd: ...} or {task: ...}
+// mapping, or a list of them.
+func taskCmds(n *yaml.Node) []string {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		var out []string
+		for _, c := range n.Content {
+			out = append(out, taskCmds(c)...)
+		}
+		return out
+	case yaml.MappingNode:
+		if cmd := taskRef(n, "cmd"); cmd != "" {
+			return []string{cmd}
+		}
+		if task := taskRef(n, "task"); task != "" {
+			return []string{"task " + task}
+		}
+		return nil
+	}
+	return []string{strings.TrimSpace(n.Value)}
+}
+
+var (
+	justRecipeRe = regexp.MustCompile(`^@?([A-Za-z_][\w-]*)((?:\s+[^:]*?)?)\s*:([^=].*)?$`)
+	justDocRe    = regexp.MustCompile(`^\[(?:.*,\s*)?doc\(\s*["'](.*)["']\s*\)`)
+)
+
+// parseJustfile finds the public recipes of a justfile. A recipe's
+// description is its [doc("...")] attribute or the comment lines right above
+// it; parameters are kept in the name.
+func parseJustfile(file, content string) []Target {
+	var targets []Target
+	var comment []string
+	doc, private := "", false
+	var current *Target
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if line != "" && (line[0] == ' ' || line[0] == '\t') {
+			if current != nil && trimmed != "" && len(current.Commands) < maxCommands {
+				current.Commands = append(current.Commands, trimmed)
+			}
+			continue
+		}
+		current = nil
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			if !strings.HasPrefix(trimmed, "#!") {
+				comment = append(comment, strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+			}
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			if m := justDocRe.FindStringSubmatch(trimmed); m != nil {
+				doc = m[1]
+			}
+			if strings.Contains(trimmed, "private") {
+				private = true
+			}
+			continue
+		}
+		m := justRecipeRe.FindStringSubmatch(trimmed)
+		isRecipe := m != nil && !strings.Contains(trimmed, ":=") &&
+			!strings.HasPrefix(trimmed, "set ") && !strings.HasPrefix(trimmed, "alias ") &&
+			!strings.HasPrefix(trimmed, "export ") && !strings.HasPrefix(trimmed, "import ") && !strings.HasPrefix(trimmed, "mod ")
+		if isRecipe && !private && !strings.HasPrefix(m[1], "_") {
+			desc := doc
+			if desc == "" {
+				desc = strings.Join(comment, " ")
+			}
+			name := m[1]
+			if params := strings.TrimSpace(m[2]); params != "" {
+				name += " " + params
+			}
+			targets = append(targets, Target{
+				Name:        name,
+				Runner:      "just",
+				Description: desc,
+				Deps:        strings.Fields(strings.TrimSpace(m[3])),
+				Defined:     fmt.Sprintf("%s:%d", file, i+1),
+			})
+			current = &targets[len(targets)-1]
+		}
+		comment, doc, private = nil, "", false
+	}
+	return targets
+}
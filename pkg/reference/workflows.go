@@ -0,0 +1,205 @@
+package reference
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowDir is where GitHub Actions workflows live in a repository.
+const WorkflowDir = ".github/workflows"
+
+// Workflow is one GitHub Actions workflow.
+type Workflow struct {
+	Name      string        `json:"name"`
+	File      string        `json:"file"`
+	Triggers  []string      `json:"triggers"`
+	Jobs      []WorkflowJob `json:"jobs"`
+	Secrets   []string      `json:"secrets,omitempty"`   // Secrets the workflow reads, except GITHUB_TOKEN
+	Artifacts []string      `json:"artifacts,omitempty"` // Artifacts uploaded by its steps
+}
+
+// WorkflowJob is one job of a workflow.
+type WorkflowJob struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name,omitempty"`
+	RunsOn string   `json:"runs_on,omitempty"`
+	Needs  []string `json:"needs,omitempty"`
+	Uses   string   `json:"uses,omitempty"` // Reusable workflow called by the job
+	Steps  []string `json:"steps,omitempty"`
+}
+
+var secretRe = regexp.MustCompile(`\$\{\{[^}]*?\bsecrets\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Workflows parses the workflow files at paths relative to root. A directory
+// contributes its *.yml and *.yaml files.
+func Workflows(root string, paths []string) ([]Workflow, error) {
+	var files []string
+	for _, p := range paths {
+		full := filepath.Join(root, p)
+		info, err := os.Stat(full)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, full)
+			continue
+		}
+		for _, pattern := range []string{"*.yml", "*.yaml"} {
+			matches, _ := filepath.Glob(filepath.Join(full, pattern))
+			files = append(files, matches...)
+		}
+	}
+	sort.Strings(files)
+
+	var workflows []Workflow
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec // workflow file from config
+		if err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, file)
+		w, err := ParseWorkflow(filepath.ToSlash(rel), data)
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, *w)
+	}
+	return workflows, nil
+}
+
+// ParseWorkflow reads a workflow's triggers, jobs, secrets and uploaded
+// artifacts.
+func ParseWorkflow(file string, data []byte) (*Workflow, error) {
+	var doc struct {
+		Name string    `yaml:"name"`
+		On   yaml.Node `yaml:"on"`
+		Jobs yaml.Node `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	w := &Workflow{Name: doc.Name, File: file, Triggers: workflowTriggers(&doc.On)}
+	if w.Name == "" {
+		w.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	for i := 0; i+1 < len(doc.Jobs.Content); i += 2 {
+		var job struct {
+			Name   string    `yaml:"name"`
+			RunsOn yaml.Node `yaml:"runs-on"`
+			Needs  yaml.Node `yaml:"needs"`
+			Uses   string    `yaml:"uses"`
+			Steps  []struct {
+				Name string                 `yaml:"name"`
+				Uses string                 `yaml:"uses"`
+				Run  string                 `yaml:"run"`
+				With map[string]interface{} `yaml:"with"`
+			} `yaml:"steps"`
+		}
+		id := doc.Jobs.Content[i].Value
+		if err := doc.Jobs.Content[i+1].Decode(&job); err != nil {
+			return nil, fmt.Errorf("%s: job %s: %w", file, id, err)
+		}
+		j := WorkflowJob{ID: id, Name: job.Name, RunsOn: strings.Join(nodeStrings(&job.RunsOn), ", "), Needs: nodeStrings(&job.Needs), Uses: job.Uses}
+		for _, step := range job.Steps {
+			name := step.Name
+			if name == "" && step.Uses != "" {
+				name = step.Uses
+			}
+			if name == "" {
+				name, _, _ = strings.Cut(strings.TrimSpace(step.Run), "\n")
+			}
+			j.Steps = append(j.Steps, name)
+			if strings.HasPrefix(step.Uses, "actions/upload-artifact@") {
+				artifact, _ := step.With["name"].(string)
+				if artifact == "" {
+					artifact = "artifact"
+				}
+				if !contains(w.Artifacts, artifact) {
+					w.Artifacts = append(w.Artifacts, artifact)
+				}
+			}
+		}
+		w.Jobs = append(w.Jobs, j)
+	}
+
+	for _, m := range secretRe.FindAllStringSubmatch(string(data), -1) {
+		if m[1] != "GITHUB_TOKEN" && !contains(w.Secrets, m[1]) {
+			w.Secrets = append(w.Secrets, m[1])
+		}
+	}
+	sort.Strings(w.Secrets)
+	return w, nil
+}
+
+// workflowTriggers describes the events of an on: block — a single event,
+// a list, or a mapping with filters.
+func workflowTriggers(on *yaml.Node) []string {
+	switch on.Kind {
+	case yaml.ScalarNode, yaml.SequenceNode:
+		return nodeStrings(on)
+	case yaml.MappingNode:
+	default:
+		return nil
+	}
+	var triggers []string
+	for i := 0; i+1 < len(on.Content); i += 2 {
+		event, cfg := on.Content[i].Value, on.Content[i+1]
+		var details []string
+		switch event {
+		case "schedule":
+			for _, entry := range cfg.Content {
+				if cron := taskRef(entry, "cron"); cron != "" {
+					details = append(details, "cron `"+cron+"`")
+				}
+			}
+		default:
+			for j := 0; cfg.Kind == yaml.MappingNode && j+1 < len(cfg.Content); j += 2 {
+				key, val := cfg.Content[j].Value, cfg.Content[j+1]
+				switch key {
+				case "inputs", "secrets", "outputs":
+					var names []string
+					for k := 0; k+1 < len(val.Content); k += 2 {
+						names = append(names, "`"+val.Content[k].Value+"`")
+					}
+					details = append(details, key+": "+strings.Join(names, ", "))
+				default:
+					values := nodeStrings(val)
+					for k, v := range values {
+						values[k] = "`" + v + "`"
+					}
+					details = append(details, key+": "+strings.Join(values, ", "))
+				}
+			}
+		}
+		if len(details) > 0 {
+			event += " (" + strings.Join(details, "; ") + ")"
+		}
+		triggers = append(triggers, event)
+	}
+	return triggers
+}
+
+// nodeStrings returns a scalar node's value or a sequence's scalar values.
+func nodeStrings(n *yaml.Node) []string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Value == "" {
+			return nil
+		}
+		return []string{n.Value}
+	case yaml.SequenceNode:
+		var out []string
+		for _, c := range n.Content {
+			out = append(out, nodeStrings(c)...)
+		}
+		return out
+	}
+	return nil
+}
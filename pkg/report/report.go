@@ -0,0 +1,342 @@
+// Package report builds machine-readable reports of docgen runs (generate,
+// aggregate, watch) for CI pipelines and dashboards: which sections were
+// processed, how long each took, token usage and cost, errors, and the files
+// written.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Section statuses.
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Section is one processed section (or, for watch, one rebuilt package).
+type Section struct {
+	Package          string       `json:"package,omitempty"`
+	Name             string       `json:"name,omitempty"`
+	Type             string       `json:"type,omitempty"` // "prose" for prompt-driven sections
+	Status           string       `json:"status"`
+	DurationMs       int64        `json:"duration_ms"`
+	Model            string       `json:"model,omitempty"`
+	InputTokens      int64        `json:"input_tokens,omitempty"`
+	OutputTokens     int64        `json:"output_tokens,omitempty"`
+	CacheWriteTokens int64        `json:"cache_write_tokens,omitempty"`
+	CacheReadTokens  int64        `json:"cache_read_tokens,omitempty"`
+	EstCostUSD       float64      `json:"est_cost_usd,omitempty"`
+	Output           string       `json:"output,omitempty"`
+	Error            string       `json:"error,omitempty"`
+	Reason           string       `json:"reason,omitempty"` // Why a section was skipped
+	Context          *ContextTrim `json:"context,omitempty"`
+}
+
+// ContextTrim records how a section's context was reduced to its budget.
+type ContextTrim struct {
+	MaxTokens      int      `json:"max_tokens,omitempty"`
+	OriginalTokens int      `json:"original_tokens"`
+	Tokens         int      `json:"tokens"`
+	Files          int      `json:"files"`                // Files in the context
+	Selected       []string `json:"selected,omitempty"`   // Files chosen by relevance, most relevant first
+	Dropped        []string `json:"dropped,omitempty"`    // Files left out to fit the cap
+	Summarized     []string `json:"summarized,omitempty"` // Files reduced to an outline
+}
+
+// Totals sums a run's sections.
+type Totals struct {
+	Sections     int     `json:"sections"`
+	Succeeded    int     `json:"succeeded"`
+	Failed       int     `json:"failed"`
+	Skipped      int     `json:"skipped"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	EstCostUSD   float64 `json:"est_cost_usd"`
+}
+
+// Run is the report of one run. Sections, Outputs and Errors are always
+// present (empty on a run that did nothing) so consumers need no nil checks.
+type Run struct {
+	Command    string    `json:"command"`
+	Status     string    `json:"status"` // ok or failed
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Sections   []Section `json:"sections"`
+	Outputs    []string  `json:"outputs"`
+	Errors     []string  `json:"errors"`
+	Totals     Totals    `json:"totals"`
+}
+
+// Recorder collects a run's report as it progresses. Sections are recorded
+// start to end: StartSection opens one, and it stays open, collecting usage,
+// until it fails, is skipped, or the next section starts. This fits the
+// generation loops, where a section's outcome is decided in many places.
+//
+// All methods are safe for concurrent use and are no-ops on a nil Recorder,
+// so instrumented code needs no checks when reporting is off.
+type Recorder struct {
+	mu      sync.Mutex
+	run     Run
+	open    *Section
+	started time.Time // When the open section started
+}
+
+// New starts recording a run of command.
+func New(command string) *Recorder {
+	return &Recorder{
+		run: Run{Command: command, StartedAt: time.Now(), Sections: []Section{}, Outputs: []string{}, Errors: []string{}},
+	}
+}
+
+// StartSection opens a section, closing the previously open one as
+// succeeded. typ is the section type; an empty type records "prose". output is
+// the section's output file, recorded as written when the section succeeds.
+func (r *Recorder) StartSection(pkg, name, typ, output string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeLocked(StatusOK, "", "")
+	if typ == "" {
+		typ = "prose"
+	}
+	r.open = &Section{Package: pkg, Name: name, Type: typ, Output: output}
+	r.started = time.Now()
+}
+
+// EndSection closes the open section as succeeded.
+func (r *Recorder) EndSection() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeLocked(StatusOK, "", "")
+}
+
+// FailSection closes the open section as failed and records err.
+func (r *Recorder) FailSection(err error) {
+	if r == nil || err == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeLocked(StatusFailed, err.Error(), "")
+}
+
+// SkipSection closes the open section as skipped, for example a section with
+// nothing to aggregate.
+func (r *Recorder) SkipSection(reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeLocked(StatusSkipped, "", reason)
+}
+
+// AddUsage adds token usage and cost to the open section.
+func (r *Recorder) AddUsage(model string, input, output, cacheWrite, cacheRead int64, costUSD float64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.open == nil {
+		return
+	}
+	if model != "" {
+		r.open.Model = model
+	}
+	r.open.InputTokens += input
+	r.open.OutputTokens += output
+	r.open.CacheWriteTokens += cacheWrite
+	r.open.CacheReadTokens += cacheRead
+	r.open.EstCostUSD += costUSD
+}
+
+// SetModel records the model the open section was generated with.
+func (r *Recorder) SetModel(model string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.open != nil && r.open.Model == "" {
+		r.open.Model = model
+	}
+}
+
+// SetContext records how the open section's context was trimmed.
+func (r *Recorder) SetContext(trim *ContextTrim) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.open != nil {
+		r.open.Context = trim
+	}
+}
+
+// AddOutput records a file written outside a section, such as a manifest.
+func (r *Recorder) AddOutput(path string) {
+	if r == nil || path == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Outputs = append(r.run.Outputs, path)
+}
+
+// AddError records an error that did not fail a section.
+func (r *Recorder) AddError(err error) {
+	if r == nil || err == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Errors = append(r.run.Errors, err.Error())
+}
+
+// AddRun folds the report of a sub-run, such as one package of generate
+// --all, into r: its sections, outputs and errors, and its totals. pkg is
+// recorded as the package of sections that name none and prefixes the
+// sub-run's errors.
+func (r *Recorder) AddRun(pkg string, run *Run) {
+	if r == nil || run == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range run.Sections {
+		if s.Package == "" {
+			s.Package = pkg
+		}
+		r.run.Sections = append(r.run.Sections, s)
+	}
+	r.run.Outputs = append(r.run.Outputs, run.Outputs...)
+	for _, e := range run.Errors {
+		r.run.Errors = append(r.run.Errors, fmt.Sprintf("%s: %s", pkg, e))
+	}
+	t := &r.run.Totals
+	t.Sections += run.Totals.Sections
+	t.Succeeded += run.Totals.Succeeded
+	t.Failed += run.Totals.Failed
+	t.Skipped += run.Totals.Skipped
+	t.InputTokens += run.Totals.InputTokens
+	t.OutputTokens += run.Totals.OutputTokens
+	t.EstCostUSD += run.Totals.EstCostUSD
+}
+
+// Finish closes the run and returns its report. runErr is the run's result:
+// a still-open section is closed as failed with it, or as succeeded when nil.
+func (r *Recorder) Finish(runErr error) *Run {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if runErr != nil && r.open != nil {
+		r.closeLocked(StatusFailed, runErr.Error(), "")
+	} else if runErr != nil {
+		r.run.Errors = append(r.run.Errors, runErr.Error())
+	} else {
+		r.closeLocked(StatusOK, "", "")
+	}
+	r.run.FinishedAt = time.Now()
+	r.run.DurationMs = r.run.FinishedAt.Sub(r.run.StartedAt).Milliseconds()
+	r.run.Status = StatusOK
+	if runErr != nil || r.run.Totals.Failed > 0 {
+		r.run.Status = StatusFailed
+	}
+	run := r.run
+	run.Sections = append([]Section{}, r.run.Sections...)
+	run.Outputs = append([]string{}, r.run.Outputs...)
+	run.Errors = append([]string{}, r.run.Errors...)
+	return &run
+}
+
+// closeLocked closes the open section, if any, with the given outcome.
+func (r *Recorder) closeLocked(status, errText, reason string) {
+	if r.open == nil {
+		return
+	}
+	s := *r.open
+	r.open = nil
+	s.Status = status
+	s.Error = errText
+	s.Reason = reason
+	s.DurationMs = time.Since(r.started).Milliseconds()
+	if status != StatusOK {
+		s.Output = ""
+	} else if s.Output != "" {
+		r.run.Outputs = append(r.run.Outputs, s.Output)
+	}
+	if errText != "" {
+		label := s.Name
+		if s.Package != "" && s.Name != "" {
+			label = s.Package + "/" + s.Name
+		} else if s.Package != "" {
+			label = s.Package
+		}
+		r.run.Errors = append(r.run.Errors, fmt.Sprintf("%s: %s", label, errText))
+	}
+
+	t := &r.run.Totals
+	t.Sections++
+	switch status {
+	case StatusOK:
+		t.Succeeded++
+	case StatusFailed:
+		t.Failed++
+	case StatusSkipped:
+		t.Skipped++
+	}
+	t.InputTokens += s.InputTokens
+	t.OutputTokens += s.OutputTokens
+	t.EstCostUSD += s.EstCostUSD
+	r.run.Sections = append(r.run.Sections, s)
+}
+
+// Write writes the report as indented JSON to dest, or to stdout when dest
+// is empty or "-".
+func (run *Run) Write(dest string) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run report: %w", err)
+	}
+	data = append(data, '\n')
+	if dest == "" || dest == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write run report: %w", err)
+	}
+	return nil
+}
+
+// WriteLine writes the report as a single line of JSON, for streams of
+// reports such as one per watch rebuild.
+func (run *Run) WriteLine(w io.Writer) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run report: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
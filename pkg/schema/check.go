@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Issue severities reported by Check.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// DefaultBannedWords are filler words flagged in descriptions by default.
+var DefaultBannedWords = []string{"simply", "just", "obviously", "easily", "basically", "etc"}
+
+// CheckOptions configures the schema quality rules.
+type CheckOptions struct {
+	RequireExamples bool     // Leaf properties must declare examples
+	BannedWords     []string // Words that may not appear in descriptions
+	MinLength       int      // Minimum description length in characters (0 disables)
+	MaxLength       int      // Maximum description length in characters (0 disables)
+}
+
+// DefaultCheckOptions returns the rules used by `docgen schema check`.
+func DefaultCheckOptions() CheckOptions {
+	return CheckOptions{
+		RequireExamples: true,
+		BannedWords:     DefaultBannedWords,
+		MinLength:       10,
+		MaxLength:       200,
+	}
+}
+
+// Issue is a single schema quality problem.
+type Issue struct {
+	Path     string `json:"path"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Check validates that every property in the schema is documented and that
+// descriptions follow the style rules in opts.
+func (p *Parser) Check(opts CheckOptions) ([]Issue, error) {
+	props, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	var banned []*regexp.Regexp
+	for _, word := range opts.BannedWords {
+		banned = append(banned, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(word)+`\b`))
+	}
+
+	var issues []Issue
+	if desc, _ := p.schemaData["description"].(string); strings.TrimSpace(desc) == "" {
+		issues = append(issues, Issue{Path: "(root)", Rule: "missing-description", Severity: SeverityError, Message: "schema has no top-level description"})
+	}
+	checkProperties(props, "", opts, banned, &issues)
+	return issues, nil
+}
+
+func checkProperties(props []Property, prefix string, opts CheckOptions, banned []*regexp.Regexp, issues *[]Issue) {
+	for _, prop := range props {
+		path := prop.Name
+		if prefix != "" {
+			path = prefix + "." + prop.Name
+		}
+		add := func(rule, severity, format string, args ...interface{}) {
+			*issues = append(*issues, Issue{Path: path, Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+		}
+
+		desc := strings.TrimSpace(prop.Description)
+		if desc == "" {
+			add("missing-description", SeverityError, "property has no description")
+		} else {
+			if opts.MinLength > 0 && len(desc) < opts.MinLength {
+				add("description-too-short", SeverityWarning, "description is %d characters (minimum %d)", len(desc), opts.MinLength)
+			}
+			if opts.MaxLength > 0 && len(desc) > opts.MaxLength {
+				add("description-too-long", SeverityWarning, "description is %d characters (maximum %d)", len(desc), opts.MaxLength)
+			}
+			for i, re := range banned {
+				if re.MatchString(desc) {
+					add("banned-word", SeverityWarning, "description uses banned word %q", opts.BannedWords[i])
+				}
+			}
+		}
+
+		isLeaf := len(prop.Properties) == 0 && prop.Type != "object"
+		if opts.RequireExamples && isLeaf && len(prop.Examples) == 0 && !prop.Deprecated {
+			add("missing-examples", SeverityError, "property has no examples")
+		}
+
+		if len(prop.Properties) > 0 {
+			checkProperties(prop.Properties, path, opts, banned, issues)
+		}
+	}
+}
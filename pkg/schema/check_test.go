@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema string
+		opts   CheckOptions
+		want   []Issue
+	}{
+		{
+			name:   "documented",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Port to listen on.", "examples": [8080]}}}`,
+			opts:   DefaultCheckOptions(),
+		},
+		{
+			name:   "missing root description",
+			schema: `{"properties": {}}`,
+			opts:   DefaultCheckOptions(),
+			want:   []Issue{{Path: "(root)", Rule: "missing-description", Severity: SeverityError, Message: "schema has no top-level description"}},
+		},
+		{
+			name:   "missing property description",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "  ", "examples": [8080]}}}`,
+			opts:   DefaultCheckOptions(),
+			want:   []Issue{{Path: "port", Rule: "missing-description", Severity: SeverityError, Message: "property has no description"}},
+		},
+		{
+			name:   "banned words",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Simply the port, etc.", "examples": [8080]}}}`,
+			opts:   DefaultCheckOptions(),
+			want: []Issue{
+				{Path: "port", Rule: "banned-word", Severity: SeverityWarning, Message: `description uses banned word "simply"`},
+				{Path: "port", Rule: "banned-word", Severity: SeverityWarning, Message: `description uses banned word "etc"`},
+			},
+		},
+		{
+			name:   "banned words match whole words only",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Adjusts the justification.", "examples": [8080]}}}`,
+			opts:   DefaultCheckOptions(),
+		},
+		{
+			name:   "custom banned words",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Simply the port.", "examples": [8080]}}}`,
+			opts:   CheckOptions{BannedWords: []string{"port"}},
+			want:   []Issue{{Path: "port", Rule: "banned-word", Severity: SeverityWarning, Message: `description uses banned word "port"`}},
+		},
+		{
+			name:   "too short",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Port.", "examples": [8080]}}}`,
+			opts:   DefaultCheckOptions(),
+			want:   []Issue{{Path: "port", Rule: "description-too-short", Severity: SeverityWarning, Message: "description is 5 characters (minimum 10)"}},
+		},
+		{
+			name:   "too long",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "The port to listen on.", "examples": [8080]}}}`,
+			opts:   CheckOptions{MaxLength: 12},
+			want:   []Issue{{Path: "port", Rule: "description-too-long", Severity: SeverityWarning, Message: "description is 22 characters (maximum 12)"}},
+		},
+		{
+			name:   "length limits disabled",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "P.", "examples": [8080]}}}`,
+			opts:   CheckOptions{},
+		},
+		{
+			name:   "missing examples",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Port to listen on."}}}`,
+			opts:   DefaultCheckOptions(),
+			want:   []Issue{{Path: "port", Rule: "missing-examples", Severity: SeverityError, Message: "property has no examples"}},
+		},
+		{
+			name:   "examples not required",
+			schema: `{"description": "Root.", "properties": {"port": {"type": "integer", "description": "Port to listen on."}}}`,
+			opts:   CheckOptions{},
+		},
+		{
+			name:   "deprecated and object properties need no examples",
+			schema: `{"description": "Root.", "properties": {"old": {"type": "string", "description": "Replaced setting.", "deprecated": true}, "meta": {"type": "object", "description": "Free-form metadata."}}}`,
+			opts:   DefaultCheckOptions(),
+		},
+		{
+			name:   "nested properties",
+			schema: `{"description": "Root.", "properties": {"server": {"type": "object", "description": "Server settings.", "properties": {"host": {"type": "string", "examples": ["0.0.0.0"]}}}}}`,
+			opts:   DefaultCheckOptions(),
+			want:   []Issue{{Path: "server.host", Rule: "missing-description", Severity: SeverityError, Message: "property has no description"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(c.schema), &data); err != nil {
+				t.Fatal(err)
+			}
+			issues, err := (&Parser{schemaData: data}).Check(c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(issues, c.want) {
+				t.Errorf("Check() =\n%+v\nwant\n%+v", issues, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MarkdownOptions configures deterministic Markdown rendering.
+type MarkdownOptions struct {
+	HeadingLevel int    // Level used for nested object subsections (default: 3)
+	AnchorPrefix string // Prefix for generated anchor IDs, to keep them unique across schemas
+}
+
+// RenderMarkdown renders the schema as Markdown reference tables without an
+// LLM. Properties keep the parser's priority/name ordering, and nested objects
+// get their own subsection with a stable anchor derived from the property path,
+// so the output is byte-for-byte reproducible for the same schema.
+func (p *Parser) RenderMarkdown(opts MarkdownOptions) (string, error) {
+	props, err := p.Parse()
+	if err != nil {
+		return "", err
+	}
+	if opts.HeadingLevel <= 0 {
+		opts.HeadingLevel = 3
+	}
+
+	var sb strings.Builder
+	if description, ok := p.schemaData["description"].(string); ok && description != "" {
+		sb.WriteString(description + "\n\n")
+	}
+	renderMarkdownTable(&sb, props, "", opts)
+	renderMarkdownSubsections(&sb, props, "", opts)
+	return sb.String(), nil
+}
+
+func renderMarkdownTable(sb *strings.Builder, props []Property, prefix string, opts MarkdownOptions) {
+	sb.WriteString("| Property | Type | Default | Description | Examples |\n")
+	sb.WriteString("| :--- | :--- | :--- | :--- | :--- |\n")
+	for _, prop := range props {
+		path := joinPath(prefix, prop.Name)
+
+		name := fmt.Sprintf("<a id=\"%s\"></a>`%s`", anchorID(opts.AnchorPrefix, path), prop.Name)
+		if prop.Required {
+			name += " (required)"
+		}
+
+		var desc []string
+		if prop.Description != "" {
+			desc = append(desc, escapeCell(prop.Description))
+		}
+		if prop.Deprecated || prop.Status == "deprecated" {
+			notice := "**Deprecated.**"
+			if prop.StatusReplacedBy != "" {
+				notice += fmt.Sprintf(" Use `%s` instead.", prop.StatusReplacedBy)
+			}
+			desc = append(desc, notice)
+		}
+		if len(prop.Enum) > 0 {
+			values := []string{"Allowed values:"}
+			for _, v := range prop.Enum {
+				value := "- " + formatValue(v.Value)
+				if v.Description != "" {
+					value += ": " + escapeCell(v.Description)
+				}
+				values = append(values, value)
+			}
+			desc = append(desc, strings.Join(values, "<br>"))
+		}
+		if len(prop.Properties) > 0 {
+			desc = append(desc, fmt.Sprintf("See [%s](#%s).", path, sectionID(opts.AnchorPrefix, path)))
+		}
+
+		var examples []string
+		for _, ex := range prop.Examples {
+			examples = append(examples, formatValue(ex))
+		}
+
+		defaultValue := ""
+		if prop.Default != nil {
+			defaultValue = formatValue(prop.Default)
+		}
+
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | %s |\n",
+			name, typeLabel(prop), defaultValue, strings.Join(desc, "<br><br>"), strings.Join(examples, "<br>"))
+	}
+	sb.WriteString("\n")
+}
+
+// renderMarkdownSubsections writes a heading and table for every property with
+// nested properties, depth-first in table order.
+func renderMarkdownSubsections(sb *strings.Builder, props []Property, prefix string, opts MarkdownOptions) {
+	heading := strings.Repeat("#", opts.HeadingLevel)
+	for _, prop := range props {
+		if len(prop.Properties) == 0 {
+			continue
+		}
+		path := joinPath(prefix, prop.Name)
+		fmt.Fprintf(sb, "%s <a id=\"%s\"></a>`%s`\n\n", heading, sectionID(opts.AnchorPrefix, path), path)
+		if prop.Description != "" {
+			sb.WriteString(prop.Description + "\n\n")
+		}
+		renderMarkdownTable(sb, prop.Properties, path, opts)
+		renderMarkdownSubsections(sb, prop.Properties, path, opts)
+	}
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func typeLabel(prop Property) string {
+	switch {
+	case prop.Type == "array" && prop.Items != nil && prop.Items.Type != "":
+		return "array of " + prop.Items.Type
+	case prop.Type == "array" && len(prop.Properties) > 0:
+		return "array of object"
+	case prop.Type == "object" && len(prop.Properties) > 0:
+		return "object"
+	case prop.Type == "":
+		return "any"
+	default:
+		return prop.Type
+	}
+}
+
+var anchorUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// anchorID returns the anchor for a property row, e.g. "settings-model".
+func anchorID(prefix, path string) string {
+	slug := strings.Trim(anchorUnsafe.ReplaceAllString(strings.ToLower(path), "-"), "-")
+	if prefix != "" {
+		return prefix + "-" + slug
+	}
+	return slug
+}
+
+// sectionID returns the anchor for a nested object's subsection. It differs
+// from the row anchor so both can appear on the same page.
+func sectionID(prefix, path string) string {
+	return anchorID(prefix, path) + "-properties"
+}
+
+// formatValue renders a JSON value as inline code.
+func formatValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return escapeCell(fmt.Sprintf("`%v`", v))
+	}
+	return "`" + escapeCell(string(data)) + "`"
+}
+
+// escapeCell makes text safe to place inside a Markdown table cell.
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRenderMarkdownGolden renders testdata/markdown.schema.json and compares
+// it with the golden file for each set of options. The schema covers priority
+// and name ordering, required markers, defaults and examples, enum and
+// anyOf/oneOf value descriptions, deprecation notices, $ref and map objects,
+// arrays of scalars and of objects, and cell escaping.
+func TestRenderMarkdownGolden(t *testing.T) {
+	cases := []struct {
+		golden string
+		opts   MarkdownOptions
+	}{
+		{"markdown.golden.md", MarkdownOptions{}},
+		{"markdown-prefixed.golden.md", MarkdownOptions{HeadingLevel: 4, AnchorPrefix: "svc"}},
+	}
+	for _, c := range cases {
+		t.Run(c.golden, func(t *testing.T) {
+			parser, err := NewParser(filepath.Join("testdata", "markdown.schema.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parser.RenderMarkdown(c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", c.golden)
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("RenderMarkdown(%+v) =\n%s\nwant:\n%s", c.opts, got, want)
+			}
+		})
+	}
+}
+
+// TestRenderMarkdownStable renders the same schema repeatedly: properties come
+// from JSON objects, so any dependence on map order shows up as a difference.
+func TestRenderMarkdownStable(t *testing.T) {
+	var first string
+	for i := 0; i < 20; i++ {
+		parser, err := NewParser(filepath.Join("testdata", "markdown.schema.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := parser.RenderMarkdown(MarkdownOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = got
+		} else if got != first {
+			t.Fatalf("render %d differs from the first:\n%s\nfirst:\n%s", i, got, first)
+		}
+	}
+}
+
+func TestAnchorID(t *testing.T) {
+	cases := []struct {
+		prefix, path, want string
+	}{
+		{"", "settings.model", "settings-model"},
+		{"", "Settings.Rules_File", "settings-rules-file"},
+		{"", "a..b", "a-b"},
+		{"", "_private.", "private"},
+		{"docgen", "sections.output", "docgen-sections-output"},
+	}
+	for _, c := range cases {
+		if got := anchorID(c.prefix, c.path); got != c.want {
+			t.Errorf("anchorID(%q, %q) = %q, want %q", c.prefix, c.path, got, c.want)
+		}
+	}
+	if got := sectionID("docgen", "settings"); got != "docgen-settings-properties" {
+		t.Errorf("sectionID = %q, want docgen-settings-properties", got)
+	}
+}
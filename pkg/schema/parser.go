@@ -15,14 +15,16 @@ type Parser struct {
 
 // Property represents a schema property with extended metadata.
 type Property struct {
-	Name        string      `json:"name"`
-	Type        string      `json:"type"`
-	Description string      `json:"description"`
-	Required    bool        `json:"required"`
-	Default     interface{} `json:"default,omitempty"`
-	Deprecated  bool        `json:"deprecated,omitempty"`
-	Properties  []Property  `json:"properties,omitempty"`
-	Items       *Property   `json:"items,omitempty"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description"`
+	Required    bool          `json:"required"`
+	Default     interface{}   `json:"default,omitempty"`
+	Deprecated  bool          `json:"deprecated,omitempty"`
+	Examples    []interface{} `json:"examples,omitempty"`
+	Enum        []EnumValue   `json:"enum,omitempty"`
+	Properties  []Property    `json:"properties,omitempty"`
+	Items       *Property     `json:"items,omitempty"`
 
 	// x-* Extensions
 	Layer            string `json:"x-layer,omitempty"`
@@ -37,6 +39,13 @@ type Property struct {
 	StatusReplacedBy string `json:"x-status-replaced-by,omitempty"`
 }
 
+// EnumValue is an allowed value of a property, with the description carried by
+// an anyOf/oneOf const entry when the schema has been enriched.
+type EnumValue struct {
+	Value       interface{} `json:"value"`
+	Description string      `json:"description,omitempty"`
+}
+
 // NewParser creates a new schema parser.
 func NewParser(schemaPath string) (*Parser, error) {
 	data, err := os.ReadFile(schemaPath) //nolint:gosec // path from trusted config
@@ -127,6 +136,8 @@ func (p *Parser) extractProperties(rawProps map[string]interface{}, required []s
 			Required:    requiredSet[key],
 			Default:     rawProp["default"],
 			Deprecated:  getBool(rawProp, "deprecated"),
+			Examples:    getSlice(rawProp, "examples"),
+			Enum:        getEnum(rawProp),
 
 			// x-* Extensions
 			Layer:            getString(rawProp, "x-layer"),
@@ -248,6 +259,37 @@ func getBool(m map[string]interface{}, key string) bool {
 	return false
 }
 
+// getEnum reads allowed values from "enum" or from const entries of
+// "anyOf"/"oneOf" (the form written by the schema enricher).
+func getEnum(m map[string]interface{}) []EnumValue {
+	var values []EnumValue
+	for _, v := range getSlice(m, "enum") {
+		values = append(values, EnumValue{Value: v})
+	}
+	if len(values) > 0 {
+		return values
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		for _, entry := range getSlice(m, key) {
+			obj, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if c, ok := obj["const"]; ok {
+				values = append(values, EnumValue{Value: c, Description: getString(obj, "description")})
+			}
+		}
+	}
+	return values
+}
+
+func getSlice(m map[string]interface{}, key string) []interface{} {
+	if v, ok := m[key].([]interface{}); ok {
+		return v
+	}
+	return nil
+}
+
 func getInt(m map[string]interface{}, key string) int {
 	switch v := m[key].(type) {
 	case int:
@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ReflectOptions configures JSON schema generation from a Go type.
+type ReflectOptions struct {
+	Type                 string // Fully qualified type, e.g. github.com/org/repo/pkg/config.Config
+	Out                  string // Output path for the schema JSON
+	FieldTag             string // Struct tag used for property names (default: yaml)
+	Title                string
+	Description          string
+	AdditionalProperties bool
+	ExpandedStruct       bool
+}
+
+// ParseTypeRef splits "import/path.Type" into its import path and type name.
+func ParseTypeRef(ref string) (importPath, typeName string, err error) {
+	slash := strings.LastIndex(ref, "/")
+	dot := strings.LastIndex(ref, ".")
+	if dot <= slash || dot == len(ref)-1 {
+		return "", "", fmt.Errorf("invalid type %q: expected <import/path>.<Type>", ref)
+	}
+	return ref[:dot], ref[dot+1:], nil
+}
+
+var reflectProgram = template.Must(template.New("reflect").Parse(`// Code generated by docgen schema reflect. DO NOT EDIT.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	target {{ printf "%q" .ImportPath }}
+	"github.com/invopop/jsonschema"
+)
+
+func main() {
+	r := &jsonschema.Reflector{
+		AllowAdditionalProperties: {{ .AdditionalProperties }},
+		ExpandedStruct:            {{ .ExpandedStruct }},
+		FieldNameTag:              {{ printf "%q" .FieldTag }},
+	}
+
+	schema := r.Reflect(&target.{{ .TypeName }}{})
+	{{- if .Title }}
+	schema.Title = {{ printf "%q" .Title }}
+	{{- end }}
+	{{- if .Description }}
+	schema.Description = {{ printf "%q" .Description }}
+	{{- end }}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		log.Fatalf("Error marshaling schema: %v", err)
+	}
+	out := {{ printf "%q" .Out }}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		log.Fatalf("Error creating output directory: %v", err)
+	}
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		log.Fatalf("Error writing schema file: %v", err)
+	}
+}
+`))
+
+// ReflectProgram renders the Go program that reflects opts.Type into a schema.
+func ReflectProgram(opts ReflectOptions) ([]byte, error) {
+	importPath, typeName, err := ParseTypeRef(opts.Type)
+	if err != nil {
+		return nil, err
+	}
+	fieldTag := opts.FieldTag
+	if fieldTag == "" {
+		fieldTag = "yaml"
+	}
+
+	var buf bytes.Buffer
+	err = reflectProgram.Execute(&buf, map[string]interface{}{
+		"ImportPath":           importPath,
+		"TypeName":             typeName,
+		"FieldTag":             fieldTag,
+		"Title":                opts.Title,
+		"Description":          opts.Description,
+		"AdditionalProperties": opts.AdditionalProperties,
+		"ExpandedStruct":       opts.ExpandedStruct,
+		"Out":                  opts.Out,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render reflect program: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Reflect generates a JSON schema for a Go type by writing a throwaway program
+// into moduleDir and running it with `go run`, so the type is compiled with
+// the module's own dependencies. The module must require
+// github.com/invopop/jsonschema.
+func Reflect(moduleDir string, opts ReflectOptions) error {
+	if opts.Out == "" {
+		return fmt.Errorf("an output path is required")
+	}
+	if !filepath.IsAbs(opts.Out) {
+		opts.Out = filepath.Join(moduleDir, opts.Out)
+	}
+
+	program, err := ReflectProgram(opts)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(moduleDir, ".docgen-reflect-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck // best-effort temp cleanup
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), program, 0o644); err != nil { //nolint:gosec // generated source
+		return fmt.Errorf("failed to write reflect program: %w", err)
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(tmpDir))
+	cmd.Dir = moduleDir
+	var stderr bytes.Buffer
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("go run failed for %s: %w\n%s", opts.Type, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
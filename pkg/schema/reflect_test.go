@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"flag"
+	"go/format"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+func TestParseTypeRef(t *testing.T) {
+	cases := []struct {
+		ref        string
+		importPath string
+		typeName   string
+		wantErr    bool
+	}{
+		{ref: "github.com/grovetools/docgen/pkg/config.DocgenConfig", importPath: "github.com/grovetools/docgen/pkg/config", typeName: "DocgenConfig"},
+		{ref: "example.com/svc.v2/config.Config", importPath: "example.com/svc.v2/config", typeName: "Config"},
+		{ref: "config.Config", importPath: "config", typeName: "Config"},
+		{ref: "github.com/org/repo/pkg/config", wantErr: true},
+		{ref: "example.com/svc.v2/config", wantErr: true},
+		{ref: "github.com/org/repo/pkg/config.", wantErr: true},
+		{ref: "", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.ref, func(t *testing.T) {
+			importPath, typeName, err := ParseTypeRef(c.ref)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTypeRef(%q) = %q, %q; want an error", c.ref, importPath, typeName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if importPath != c.importPath || typeName != c.typeName {
+				t.Errorf("ParseTypeRef(%q) = %q, %q; want %q, %q", c.ref, importPath, typeName, c.importPath, c.typeName)
+			}
+		})
+	}
+}
+
+// TestReflectProgram compares the rendered programs with their golden files
+// and checks each is gofmt-clean Go source.
+func TestReflectProgram(t *testing.T) {
+	cases := []struct {
+		name string
+		opts ReflectOptions
+	}{
+		{"docgen", ReflectOptions{
+			Type:                 "github.com/grovetools/docgen/pkg/config.DocgenConfig",
+			Out:                  "schema/docgen.config.schema.json",
+			Title:                "Grove Docgen Configuration",
+			Description:          "Configuration schema for grove-docgen documentation generation.",
+			AdditionalProperties: true,
+			ExpandedStruct:       true,
+		}},
+		{"json-tag", ReflectOptions{
+			Type:     "example.com/svc/config.Config",
+			Out:      "/abs/config.schema.json",
+			FieldTag: "json",
+			Title:    `Service "Config"`,
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ReflectProgram(c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			formatted, err := format.Source(got)
+			if err != nil {
+				t.Fatalf("program does not parse: %v\n%s", err, got)
+			}
+			if string(formatted) != string(got) {
+				t.Errorf("program is not gofmt-clean:\n%s", got)
+			}
+
+			golden := filepath.Join("testdata", "reflect-"+c.name+".go.golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("ReflectProgram(%s) =\n%s\nwant:\n%s", c.name, got, want)
+			}
+		})
+	}
+
+	if _, err := ReflectProgram(ReflectOptions{Type: "Config"}); err == nil {
+		t.Error("ReflectProgram accepted a type without an import path")
+	}
+}
+
+// TestReflectDocgenConfig runs the reflection tools/schema-generator performs
+// and checks it reproduces the committed schema.
+func TestReflectDocgenConfig(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles and runs a program with go run")
+	}
+	out := filepath.Join(t.TempDir(), "docgen.config.schema.json")
+	err := Reflect(filepath.Join("..", ".."), ReflectOptions{
+		Type:                 "github.com/grovetools/docgen/pkg/config.DocgenConfig",
+		Out:                  out,
+		FieldTag:             "yaml",
+		Title:                "Grove Docgen Configuration",
+		Description:          "Configuration schema for grove-docgen documentation generation.",
+		AdditionalProperties: true,
+		ExpandedStruct:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("..", "..", "schema", "docgen.config.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Error("reflected schema differs from schema/docgen.config.schema.json; run go generate ./pkg/config")
+	}
+}
+
+func TestReflectRequiresOut(t *testing.T) {
+	if err := Reflect(t.TempDir(), ReflectOptions{Type: "example.com/svc/config.Config"}); err == nil {
+		t.Error("Reflect accepted options without an output path")
+	}
+}
@@ -0,0 +1,128 @@
+package schema_enricher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grovetools/docgen/pkg/config"
+)
+
+// DefaultBatchSize is the number of properties sent to the LLM per request.
+const DefaultBatchSize = 25
+
+// enrichProgress records the results of completed batches so an interrupted
+// enrichment can resume without re-generating them. It is discarded when the
+// source file changes.
+type enrichProgress struct {
+	SourceHash string                      `json:"source_hash"`
+	Results    map[string]enrichmentResult `json:"results"`
+}
+
+// progressPath returns the resume state file for a schema.
+func progressPath(schemaPath string) string {
+	return schemaPath + ".enrich-progress.json"
+}
+
+func hashSource(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadProgress returns saved results for the source, or an empty set when no
+// state exists or the source has changed since it was written.
+func loadProgress(path, sourceHash string) *enrichProgress {
+	empty := &enrichProgress{SourceHash: sourceHash, Results: make(map[string]enrichmentResult)}
+	data, err := os.ReadFile(path) //nolint:gosec // derived from the schema path
+	if err != nil {
+		return empty
+	}
+	var p enrichProgress
+	if err := json.Unmarshal(data, &p); err != nil || p.SourceHash != sourceHash || p.Results == nil {
+		return empty
+	}
+	return &p
+}
+
+func (p *enrichProgress) save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal enrichment progress: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil { //nolint:gosec // non-sensitive state file
+		return fmt.Errorf("failed to write enrichment progress: %w", err)
+	}
+	return nil
+}
+
+// filterProperties keeps the properties matching any of the --only paths. A
+// path matches itself and everything nested beneath it.
+func filterProperties(props []propertyInfo, only []string) []propertyInfo {
+	if len(only) == 0 {
+		return props
+	}
+	var filtered []propertyInfo
+	for _, prop := range props {
+		for _, path := range only {
+			if prop.path == path || strings.HasPrefix(prop.path, path+".") {
+				filtered = append(filtered, prop)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// enrichInBatches generates results for props in chunks of batchSize,
+// skipping properties already present in progress. Progress is saved after
+// each batch so a failed run can be resumed.
+func (e *Enricher) enrichInBatches(projectDir string, props []propertyInfo, cfg *config.DocgenConfig, batchSize int, progress *enrichProgress, statePath string) error {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	var pending []propertyInfo
+	for _, prop := range props {
+		if _, done := progress.Results[prop.path]; !done {
+			pending = append(pending, prop)
+		}
+	}
+	if resumed := len(props) - len(pending); resumed > 0 {
+		e.logger.Infof("Resuming enrichment: %d properties already generated", resumed)
+	}
+
+	total := (len(pending) + batchSize - 1) / batchSize
+	for i := 0; i < len(pending); i += batchSize {
+		batch := pending[i:min(i+batchSize, len(pending))]
+		batchNum := i/batchSize + 1
+		e.logger.Infof("Generating descriptions for batch %d/%d (%d properties)...", batchNum, total, len(batch))
+		e.ulog.Info("Enrichment batch").
+			Field("batch", batchNum).
+			Field("total_batches", total).
+			Field("properties", len(batch)).
+			Emit()
+
+		results, err := e.generateDescriptionsBatch(projectDir, batch, cfg)
+		if err != nil {
+			if statePath != "" && len(progress.Results) > 0 {
+				return fmt.Errorf("batch %d/%d failed (progress saved to %s; re-run to resume): %w", batchNum, total, statePath, err)
+			}
+			return fmt.Errorf("batch %d/%d failed: %w", batchNum, total, err)
+		}
+		for j, result := range results {
+			if j < len(batch) {
+				progress.Results[batch[j].path] = result
+			}
+		}
+
+		if statePath != "" {
+			if err := progress.save(statePath); err != nil {
+				e.logger.Warnf("Could not save enrichment progress: %v", err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,29 +1,37 @@
 package schema_enricher
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 
-	grovelogging "github.com/grovetools/core/logging"
+	"github.com/grovetools/docgen/internal/clilog"
 	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/descriptions"
+	"github.com/grovetools/docgen/pkg/diff"
 	"github.com/grovetools/docgen/pkg/generator"
 	"github.com/sirupsen/logrus"
 )
 
-var ulog = grovelogging.NewUnifiedLogger("grove-docgen.enricher")
-
-// Enricher handles the process of enriching a JSON schema.
+// Enricher handles the process of enriching a JSON schema or example config.
 type Enricher struct {
 	logger    *logrus.Logger
 	generator *generator.Generator
+	// ulog, when set, receives the batch milestones and prints the enriched
+	// document or diff (see SetEventLogger).
+	ulog *clilog.EventLogger
 }
 
 // propertyInfo holds information about a property that needs a description
 type propertyInfo struct {
-	path   string
-	schema map[string]interface{}
+	path      string
+	schema    map[string]interface{}
+	configKey bool // an example config key rather than a schema property
 }
 
 // New creates a new Enricher instance.
@@ -34,8 +42,50 @@ func New(logger *logrus.Logger) *Enricher {
 	}
 }
 
+// SetEventLogger makes the enricher log its milestones to l, and print the
+// enriched document or diff when it is not written in place. Without one,
+// as for library callers, neither is printed.
+func (e *Enricher) SetEventLogger(l *clilog.EventLogger) {
+	e.ulog = l
+	e.generator.SetEventLogger(l)
+}
+
+// EnrichOptions controls how enrichment results are reviewed and written.
+type EnrichOptions struct {
+	InPlace     bool // Write the enriched document back to its source file
+	Diff        bool // Print a unified diff of the proposed changes instead of the full document
+	Interactive bool // Accept or reject each proposed property change before applying it
+
+	Only      []string // Restrict enrichment to these property paths (and their children)
+	BatchSize int      // Properties per LLM request (default: DefaultBatchSize)
+	NoResume  bool     // Ignore progress saved by a previous failed run
+
+	NoSourceComments bool // Skip describing properties from Go struct doc comments
+
+	// DescriptionsStore is a descriptions store (see pkg/descriptions) whose
+	// fresh "schema:" entries are reused instead of calling the LLM, and to
+	// which newly generated descriptions are saved.
+	DescriptionsStore string
+}
+
 // Enrich finds properties without descriptions and generates them using an LLM.
 func (e *Enricher) Enrich(projectDir, schemaPath string, inPlace bool) error {
+	return e.EnrichWithOptions(projectDir, schemaPath, EnrichOptions{InPlace: inPlace})
+}
+
+// EnrichWithOptions finds properties without descriptions and generates them
+// using an LLM. JSON and YAML schemas are supported, as are annotated example
+// configs in YAML or TOML, where descriptions are written as comments above
+// each key.
+func (e *Enricher) EnrichWithOptions(projectDir, schemaPath string, opts EnrichOptions) error {
+	return e.EnrichContext(context.Background(), projectDir, schemaPath, opts)
+}
+
+// EnrichContext is EnrichWithOptions bounded by ctx. Cancelling ctx kills the
+// in-flight LLM request; batches finished before it are kept in the saved
+// progress, so re-running resumes from there.
+func (e *Enricher) EnrichContext(ctx context.Context, projectDir, schemaPath string, opts EnrichOptions) error {
+	e.generator.SetContext(ctx)
 	e.logger.Infof("Enriching schema: %s", schemaPath)
 
 	data, err := os.ReadFile(schemaPath)
@@ -43,9 +93,15 @@ func (e *Enricher) Enrich(projectDir, schemaPath string, inPlace bool) error {
 		return fmt.Errorf("failed to read schema file: %w", err)
 	}
 
-	var schemaData map[string]interface{}
-	if err := json.Unmarshal(data, &schemaData); err != nil {
-		return fmt.Errorf("failed to parse schema JSON: %w", err)
+	doc, err := parseDocument(schemaPath, data)
+	if err != nil {
+		return err
+	}
+
+	// Marshal before enrichment so --diff compares like-for-like formatting.
+	originalData, err := doc.marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
 	}
 
 	// Load notebook-aware config and resolve its explicit context selection.
@@ -53,6 +109,10 @@ func (e *Enricher) Enrich(projectDir, schemaPath string, inPlace bool) error {
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to load docgen config: %w", err)
 	}
+	if cfg != nil {
+		// Share the provider quotas with concurrent generations
+		e.generator.UseConfig(cfg)
+	}
 	rulesPath, err := config.ResolveDocsRulesFile(projectDir)
 	if err != nil {
 		return fmt.Errorf("failed to resolve docs rules: %w", err)
@@ -65,48 +125,111 @@ func (e *Enricher) Enrich(projectDir, schemaPath string, inPlace bool) error {
 	}
 
 	// Collect all properties that need descriptions
-	propsNeedingDescriptions := e.collectPropertiesNeedingDescriptions(schemaData, "")
-
-	// Add top-level schema description if missing
-	if _, hasDesc := schemaData["description"]; !hasDesc {
-		propsNeedingDescriptions = append([]propertyInfo{{
-			path:   "_schema",
-			schema: schemaData,
-		}}, propsNeedingDescriptions...)
-	}
+	propsNeedingDescriptions := filterProperties(doc.collect(), opts.Only)
 
-	// Generate all descriptions in a single batch call
 	if len(propsNeedingDescriptions) > 0 {
-		e.logger.Infof("Generating descriptions for %d properties in batch...", len(propsNeedingDescriptions))
-		descriptions, err := e.generateDescriptionsBatch(projectDir, propsNeedingDescriptions, cfg)
-		if err != nil {
+		// Prefer descriptions documented on the Go structs the schema was
+		// reflected from; only the rest go to the LLM.
+		var props []propertyInfo
+		var results []enrichmentResult
+		if !opts.NoSourceComments {
+			propsNeedingDescriptions, props, results = describeFromSource(projectDir, propsNeedingDescriptions)
+			if len(props) > 0 {
+				e.logger.Infof("Described %d properties from Go struct comments", len(props))
+			}
+		}
+		var store *descriptions.Store
+		if opts.DescriptionsStore != "" {
+			store, err = descriptions.Load(opts.DescriptionsStore)
+			if err != nil {
+				return err
+			}
+			var stored []propertyInfo
+			var storedResults []enrichmentResult
+			propsNeedingDescriptions, stored, storedResults = describeFromStore(store, propsNeedingDescriptions)
+			if len(stored) > 0 {
+				e.logger.Infof("Reused %d descriptions from %s", len(stored), opts.DescriptionsStore)
+			}
+			props = append(props, stored...)
+			results = append(results, storedResults...)
+		}
+		if len(propsNeedingDescriptions) > 0 {
+			e.logger.Infof("Generating descriptions for %d properties...", len(propsNeedingDescriptions))
+		}
+
+		statePath := progressPath(schemaPath)
+		progress := loadProgress(statePath, hashSource(data))
+		if opts.NoResume {
+			progress.Results = make(map[string]enrichmentResult)
+		}
+		if err := e.enrichInBatches(projectDir, propsNeedingDescriptions, cfg, opts.BatchSize, progress, statePath); err != nil {
 			return fmt.Errorf("failed to generate descriptions: %w", err)
 		}
 
-		// Apply the descriptions
-		for i, propInfo := range propsNeedingDescriptions {
-			if i < len(descriptions) {
-				propInfo.schema["description"] = descriptions[i]
-				e.logger.Infof("Updated description for: %s", propInfo.path)
+		var generatedProps []propertyInfo
+		var generatedResults []enrichmentResult
+		for _, prop := range propsNeedingDescriptions {
+			if result, ok := progress.Results[prop.path]; ok {
+				generatedProps = append(generatedProps, prop)
+				generatedResults = append(generatedResults, result)
 			}
 		}
+		props = append(props, generatedProps...)
+		results = append(results, generatedResults...)
+
+		if store != nil && len(generatedProps) > 0 {
+			recordInStore(store, generatedProps, generatedResults)
+			if err := store.Save(); err != nil {
+				return err
+			}
+		}
+
+		if opts.Interactive {
+			props, results, err = reviewInteractively(os.Stdin, os.Stderr, props, results)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Apply the descriptions
+		doc.apply(props, results)
+		for _, propInfo := range props {
+			e.logger.Infof("Updated description for: %s", propInfo.path)
+		}
+
+		// All batches succeeded; the resume state is no longer needed.
+		if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+			e.logger.Warnf("Could not remove enrichment progress file %s: %v", statePath, err)
+		}
 	} else {
 		e.logger.Info("All properties already have descriptions")
 	}
 
-	// Marshal the updated schema back to JSON
-	updatedData, err := json.MarshalIndent(schemaData, "", "  ")
+	// Marshal the updated document back to its original format
+	updatedData, err := doc.marshal()
 	if err != nil {
 		return fmt.Errorf("failed to marshal updated schema: %w", err)
 	}
 
-	if inPlace {
+	if opts.Diff {
+		patch := diff.Unified("a/"+schemaPath, "b/"+schemaPath, string(originalData), string(updatedData))
+		if patch == "" {
+			patch = "No changes proposed.\n"
+		}
+		e.ulog.Info("Proposed schema changes").
+			Field("schema_path", schemaPath).
+			PrettyOnly().
+			Pretty(patch).
+			Emit()
+	}
+
+	if opts.InPlace {
 		if err := os.WriteFile(schemaPath, updatedData, 0o644); err != nil {
 			return fmt.Errorf("failed to write updated schema file: %w", err)
 		}
 		e.logger.Infof("Successfully enriched schema in-place: %s", schemaPath)
-	} else {
-		ulog.Info("Enriched schema output").
+	} else if !opts.Diff {
+		e.ulog.Info("Enriched schema output").
 			Field("schema_path", schemaPath).
 			PrettyOnly().
 			Pretty(string(updatedData)).
@@ -116,7 +239,138 @@ func (e *Enricher) Enrich(projectDir, schemaPath string, inPlace bool) error {
 	return nil
 }
 
-func (e *Enricher) collectPropertiesNeedingDescriptions(node interface{}, path string) []propertyInfo {
+// reviewInteractively asks whether to accept each proposed change and returns
+// only the accepted properties and their results. Answering "q" rejects the
+// remaining proposals.
+func reviewInteractively(in io.Reader, out io.Writer, props []propertyInfo, results []enrichmentResult) ([]propertyInfo, []enrichmentResult, error) {
+	reader := bufio.NewReader(in)
+	var acceptedProps []propertyInfo
+	var acceptedResults []enrichmentResult
+
+	for i, prop := range props {
+		if i >= len(results) {
+			break
+		}
+		result := results[i]
+		fmt.Fprintf(out, "\n[%d/%d] %s\n", i+1, len(props), prop.path)
+		fmt.Fprintf(out, "  description: %s\n", result.Description)
+		if len(result.Examples) > 0 && !prop.configKey {
+			fmt.Fprintf(out, "  examples:    %v\n", result.Examples)
+		}
+		for value, desc := range result.EnumDescriptions {
+			fmt.Fprintf(out, "  enum %q: %s\n", value, desc)
+		}
+		fmt.Fprint(out, "Accept? [y/N/q] ")
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, nil, fmt.Errorf("failed to read answer: %w", err)
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer == "q" || (err == io.EOF && answer == "") {
+			break
+		}
+		if answer == "y" || answer == "yes" {
+			acceptedProps = append(acceptedProps, prop)
+			acceptedResults = append(acceptedResults, result)
+		}
+	}
+	return acceptedProps, acceptedResults, nil
+}
+
+// collectSchemaProperties returns the schema properties lacking descriptions,
+// including the top-level schema description and the definitions of
+// $defs (or the older definitions), which reflected schemas reference
+// nested structs through. Definition properties have paths of the form
+// $defs.<Name>.<key>.
+func collectSchemaProperties(schemaData map[string]interface{}) []propertyInfo {
+	props := collectPropertiesNeedingDescriptions(schemaData, "")
+	for _, keyword := range []string{"$defs", "definitions"} {
+		defs, ok := schemaData[keyword].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		names := make([]string, 0, len(defs))
+		for name := range defs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			def, ok := defs[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			path := defsPrefix + name
+			if !hasDescription(def) {
+				props = append(props, propertyInfo{path: path, schema: def})
+			}
+			props = append(props, collectPropertiesNeedingDescriptions(def, path)...)
+		}
+	}
+
+	// Add top-level schema description if missing
+	if !hasDescription(schemaData) {
+		props = append([]propertyInfo{{
+			path:   "_schema",
+			schema: schemaData,
+		}}, props...)
+	}
+	return props
+}
+
+// applySchemaResults applies each enrichment result to its schema property:
+// the description, examples (when absent), and enum value descriptions as an
+// anyOf of const/description pairs.
+func applySchemaResults(props []propertyInfo, results []enrichmentResult) {
+	for i, result := range results {
+		if i >= len(props) {
+			break
+		}
+		prop := props[i]
+		prop.schema["description"] = result.Description
+
+		// Add examples if provided and property doesn't have them
+		if len(result.Examples) > 0 {
+			if _, hasExamples := prop.schema["examples"]; !hasExamples {
+				prop.schema["examples"] = result.Examples
+			}
+		}
+
+		// Add enum descriptions if provided
+		if len(result.EnumDescriptions) > 0 {
+			if enumArray, ok := prop.schema["enum"].([]interface{}); ok {
+				// Create an anyOf structure with const + description for each enum value
+				anyOf := make([]interface{}, 0, len(enumArray))
+				for _, val := range enumArray {
+					enumObj := map[string]interface{}{
+						"const": val,
+					}
+					if desc, hasDesc := result.EnumDescriptions[fmt.Sprintf("%v", val)]; hasDesc {
+						enumObj["description"] = desc
+					}
+					anyOf = append(anyOf, enumObj)
+				}
+				// Only replace if we have descriptions
+				if len(anyOf) > 0 {
+					delete(prop.schema, "enum")
+					prop.schema["anyOf"] = anyOf
+				}
+			}
+		}
+	}
+}
+
+// hasDescription reports whether a schema has a description. An empty or
+// null description, as left by a schema stub, is missing.
+func hasDescription(schema map[string]interface{}) bool {
+	desc, ok := schema["description"].(string)
+	return ok && strings.TrimSpace(desc) != ""
+}
+
+// defsPrefix starts the paths of the properties of schema definitions.
+const defsPrefix = "$defs."
+
+func collectPropertiesNeedingDescriptions(node interface{}, path string) []propertyInfo {
 	var results []propertyInfo
 
 	switch v := node.(type) {
@@ -133,7 +387,7 @@ func (e *Enricher) collectPropertiesNeedingDescriptions(node interface{}, path s
 				}
 
 				// If no description, add to list
-				if _, hasDesc := prop["description"]; !hasDesc {
+				if !hasDescription(prop) {
 					results = append(results, propertyInfo{
 						path:   newPath,
 						schema: prop,
@@ -141,9 +395,7 @@ func (e *Enricher) collectPropertiesNeedingDescriptions(node interface{}, path s
 				}
 
 				// Recurse for nested objects
-				if nestedProps, ok := prop["properties"].(map[string]interface{}); ok {
-					results = append(results, e.collectPropertiesNeedingDescriptions(nestedProps, newPath)...)
-				}
+				results = append(results, collectPropertiesNeedingDescriptions(prop, newPath)...)
 			}
 		}
 	}
@@ -156,7 +408,7 @@ type enrichmentResult struct {
 	EnumDescriptions map[string]string `json:"enum_descriptions,omitempty"`
 }
 
-func (e *Enricher) generateDescriptionsBatch(projectDir string, properties []propertyInfo, cfg *config.DocgenConfig) ([]string, error) {
+func (e *Enricher) generateDescriptionsBatch(projectDir string, properties []propertyInfo, cfg *config.DocgenConfig) ([]enrichmentResult, error) {
 	// Build the batch prompt
 	var promptBuilder strings.Builder
 	promptBuilder.WriteString("Given the project context, enrich the following JSON schema properties.\n\n")
@@ -177,6 +429,12 @@ func (e *Enricher) generateDescriptionsBatch(projectDir string, properties []pro
 			return nil, fmt.Errorf("failed to marshal property %s: %w", prop.path, err)
 		}
 
+		if prop.configKey {
+			promptBuilder.WriteString(fmt.Sprintf("Property %d: config key %s (describe what the key configures; omit examples)\n", i+1, prop.path))
+			promptBuilder.WriteString(fmt.Sprintf("Current value: %v\n\n", prop.schema["value"]))
+			continue
+		}
+
 		if prop.path == "_schema" {
 			promptBuilder.WriteString(fmt.Sprintf("Property %d: Top-level schema description\n", i+1))
 			promptBuilder.WriteString(fmt.Sprintf("Schema Title: %v\n", prop.schema["title"]))
@@ -221,53 +479,5 @@ func (e *Enricher) generateDescriptionsBatch(projectDir string, properties []pro
 		e.logger.Warnf("Expected %d results but got %d. Using what we have.", len(properties), len(results))
 	}
 
-	// Apply enrichments to the actual schema objects
-	for i, result := range results {
-		if i >= len(properties) {
-			break
-		}
-		prop := properties[i]
-
-		// Add examples if provided and property doesn't have them
-		if len(result.Examples) > 0 {
-			if _, hasExamples := prop.schema["examples"]; !hasExamples {
-				prop.schema["examples"] = result.Examples
-				e.logger.Debugf("Added %d examples for: %s", len(result.Examples), prop.path)
-			}
-		}
-
-		// Add enum descriptions if provided
-		if len(result.EnumDescriptions) > 0 {
-			if enumVals, hasEnum := prop.schema["enum"]; hasEnum {
-				// Create an anyOf structure with const + description for each enum value
-				var anyOf []map[string]interface{}
-				if enumArray, ok := enumVals.([]interface{}); ok {
-					for _, val := range enumArray {
-						valStr := fmt.Sprintf("%v", val)
-						enumObj := map[string]interface{}{
-							"const": val,
-						}
-						if desc, hasDesc := result.EnumDescriptions[valStr]; hasDesc {
-							enumObj["description"] = desc
-						}
-						anyOf = append(anyOf, enumObj)
-					}
-					// Only replace if we have descriptions
-					if len(anyOf) > 0 {
-						delete(prop.schema, "enum")
-						prop.schema["anyOf"] = anyOf
-						e.logger.Debugf("Added enum descriptions for: %s", prop.path)
-					}
-				}
-			}
-		}
-	}
-
-	// Extract just descriptions to return
-	descriptions := make([]string, 0, len(results))
-	for _, result := range results {
-		descriptions = append(descriptions, result.Description)
-	}
-
-	return descriptions, nil
+	return results, nil
 }
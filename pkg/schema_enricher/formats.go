@@ -0,0 +1,477 @@
+package schema_enricher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document is an enrichable file. Implementations preserve the file's original
+// format (and, where the format has them, its comments) when marshaling.
+type document interface {
+	// collect returns the entries lacking a description.
+	collect() []propertyInfo
+	// apply writes enrichment results back to the collected entries.
+	apply(props []propertyInfo, results []enrichmentResult)
+	marshal() ([]byte, error)
+}
+
+// parseDocument detects the file format from its extension and contents.
+// JSON and YAML files with schema keywords at the root are treated as JSON
+// Schemas; other YAML files and all TOML files are treated as annotated
+// example configs whose comments serve as descriptions.
+func parseDocument(path string, data []byte) (document, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		var schemaData map[string]interface{}
+		if err := root.Decode(&schemaData); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		if isJSONSchema(schemaData) {
+			return &yamlSchemaDoc{root: &root, data: schemaData}, nil
+		}
+		return &yamlConfigDoc{root: &root, keys: make(map[string]*yaml.Node)}, nil
+	case ".toml":
+		return newTOMLConfigDoc(data), nil
+	default:
+		var schemaData map[string]interface{}
+		if err := json.Unmarshal(data, &schemaData); err != nil {
+			return nil, fmt.Errorf("failed to parse schema JSON: %w", err)
+		}
+		return &jsonSchemaDoc{data: schemaData}, nil
+	}
+}
+
+// isJSONSchema reports whether a decoded document looks like a JSON Schema.
+func isJSONSchema(data map[string]interface{}) bool {
+	for _, key := range []string{"$schema", "properties", "$defs", "definitions"} {
+		if _, ok := data[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonSchemaDoc is a JSON Schema in JSON format.
+type jsonSchemaDoc struct {
+	data map[string]interface{}
+}
+
+func (d *jsonSchemaDoc) collect() []propertyInfo {
+	return collectSchemaProperties(d.data)
+}
+
+func (d *jsonSchemaDoc) apply(props []propertyInfo, results []enrichmentResult) {
+	applySchemaResults(props, results)
+}
+
+func (d *jsonSchemaDoc) marshal() ([]byte, error) {
+	return json.MarshalIndent(d.data, "", "  ")
+}
+
+// yamlSchemaDoc is a JSON Schema in YAML format. Enrichment mutates the
+// decoded map; marshal merges those changes back into the original node tree
+// so existing comments and key order survive.
+type yamlSchemaDoc struct {
+	root *yaml.Node
+	data map[string]interface{}
+}
+
+func (d *yamlSchemaDoc) collect() []propertyInfo {
+	return collectSchemaProperties(d.data)
+}
+
+func (d *yamlSchemaDoc) apply(props []propertyInfo, results []enrichmentResult) {
+	applySchemaResults(props, results)
+}
+
+func (d *yamlSchemaDoc) marshal() ([]byte, error) {
+	if err := syncYAMLNode(d.root, d.data); err != nil {
+		return nil, err
+	}
+	return encodeYAML(d.root)
+}
+
+// yamlConfigDoc is an example config file; descriptions are head comments.
+// A key with a comment on its line, as in `port: 8080 # Listen port`, is
+// already described.
+type yamlConfigDoc struct {
+	root *yaml.Node
+	keys map[string]*yaml.Node
+}
+
+func (d *yamlConfigDoc) collect() []propertyInfo {
+	var results []propertyInfo
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+			walk(node.Content[0], path)
+			return
+		}
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			if !hasCommentText(key.HeadComment) && !hasCommentText(key.LineComment) && !hasCommentText(val.LineComment) {
+				d.keys[keyPath] = key
+				results = append(results, configKeyInfo(keyPath, yamlValueSummary(val)))
+			}
+			walk(val, keyPath)
+		}
+	}
+	walk(d.root, "")
+	return results
+}
+
+func (d *yamlConfigDoc) apply(props []propertyInfo, results []enrichmentResult) {
+	for i, prop := range props {
+		if i >= len(results) || results[i].Description == "" {
+			continue
+		}
+		if key, ok := d.keys[prop.path]; ok {
+			key.HeadComment = commentLines(results[i].Description)
+		}
+	}
+}
+
+func (d *yamlConfigDoc) marshal() ([]byte, error) {
+	return encodeYAML(d.root)
+}
+
+// tomlConfigDoc is an example TOML config. TOML has no comment-preserving
+// round-trip in our dependencies, so it is edited line by line: comments are
+// written above keys and table headers that lack one, replacing the empty
+// comment lines ("#") above them, and every other line is left untouched. A
+// key with a comment on its line is already described.
+type tomlConfigDoc struct {
+	lines   []string
+	entries []tomlEntry
+	comment map[int]string // line index -> comment to write above it
+}
+
+type tomlEntry struct {
+	path      string
+	line      int
+	value     string
+	described bool // a comment above or on the line says what it is
+}
+
+func newTOMLConfigDoc(data []byte) *tomlConfigDoc {
+	d := &tomlConfigDoc{
+		lines:   strings.Split(string(data), "\n"),
+		comment: make(map[int]string),
+	}
+
+	seen := make(map[string]bool)
+	table := ""
+	for i := 0; i < len(d.lines); i++ {
+		trimmed := strings.TrimSpace(d.lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		var entry tomlEntry
+		if strings.HasPrefix(trimmed, "[") {
+			table = strings.Trim(stripTOMLComment(trimmed), "[] ")
+			entry = tomlEntry{path: table, line: i, value: "(table)", described: stripTOMLComment(trimmed) != trimmed}
+		} else {
+			key, value, ok := strings.Cut(trimmed, "=")
+			if !ok {
+				continue
+			}
+			key = strings.Trim(strings.TrimSpace(key), `"'`)
+			value = strings.TrimSpace(value)
+			path := key
+			if table != "" {
+				path = table + "." + key
+			}
+			entry = tomlEntry{path: path, line: i, value: stripTOMLComment(value), described: stripTOMLComment(value) != value}
+			i = skipTOMLContinuation(d.lines, i, value)
+		}
+		entry.described = entry.described || d.commentAbove(entry.line)
+
+		if !seen[entry.path] {
+			seen[entry.path] = true
+			d.entries = append(d.entries, entry)
+		}
+	}
+	return d
+}
+
+// emptyCommentsAbove returns the number of empty comment lines ("#") just
+// above line.
+func (d *tomlConfigDoc) emptyCommentsAbove(line int) int {
+	n := 0
+	for line-n > 0 && strings.TrimSpace(d.lines[line-n-1]) == "#" {
+		n++
+	}
+	return n
+}
+
+// commentAbove reports whether a comment with text is above line, past any
+// empty comment lines.
+func (d *tomlConfigDoc) commentAbove(line int) bool {
+	above := line - d.emptyCommentsAbove(line) - 1
+	return above >= 0 && strings.HasPrefix(strings.TrimSpace(d.lines[above]), "#")
+}
+
+func (d *tomlConfigDoc) collect() []propertyInfo {
+	var results []propertyInfo
+	for _, entry := range d.entries {
+		if entry.described {
+			continue
+		}
+		results = append(results, configKeyInfo(entry.path, entry.value))
+	}
+	return results
+}
+
+func (d *tomlConfigDoc) apply(props []propertyInfo, results []enrichmentResult) {
+	lineByPath := make(map[string]int, len(d.entries))
+	for _, entry := range d.entries {
+		lineByPath[entry.path] = entry.line
+	}
+	for i, prop := range props {
+		if i >= len(results) || results[i].Description == "" {
+			continue
+		}
+		if line, ok := lineByPath[prop.path]; ok {
+			d.comment[line] = results[i].Description
+		}
+	}
+}
+
+func (d *tomlConfigDoc) marshal() ([]byte, error) {
+	// Empty comment lines above a described key give way to its comment
+	replaced := make(map[int]bool)
+	for line := range d.comment {
+		for n := d.emptyCommentsAbove(line); n > 0; n-- {
+			replaced[line-n] = true
+		}
+	}
+	out := make([]string, 0, len(d.lines)+len(d.comment))
+	for i, line := range d.lines {
+		if replaced[i] {
+			continue
+		}
+		if desc, ok := d.comment[i]; ok {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			for _, c := range strings.Split(commentLines(desc), "\n") {
+				out = append(out, indent+c)
+			}
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+// skipTOMLContinuation returns the index of the last line belonging to the
+// value that starts on line i (multi-line arrays, inline tables and strings).
+func skipTOMLContinuation(lines []string, i int, value string) int {
+	for _, quote := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, quote) && strings.Count(value, quote)%2 == 1 {
+			for i+1 < len(lines) {
+				i++
+				if strings.Contains(lines[i], quote) {
+					return i
+				}
+			}
+			return i
+		}
+	}
+
+	depth := bracketDepth(stripTOMLComment(value))
+	for depth > 0 && i+1 < len(lines) {
+		i++
+		depth += bracketDepth(stripTOMLComment(lines[i]))
+	}
+	return i
+}
+
+// bracketDepth returns the net count of opening brackets and braces in s,
+// ignoring those inside quoted strings.
+func bracketDepth(s string) int {
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// stripTOMLComment removes a trailing comment that is not inside a string.
+func stripTOMLComment(s string) string {
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return strings.TrimSpace(s[:i])
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// configKeyInfo builds a propertyInfo for an example config key.
+func configKeyInfo(path, value string) propertyInfo {
+	return propertyInfo{
+		path:      path,
+		configKey: true,
+		schema:    map[string]interface{}{"key": path, "value": value},
+	}
+}
+
+// yamlValueSummary renders a short representation of a YAML value for the
+// enrichment prompt.
+func yamlValueSummary(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.MappingNode:
+		return "(mapping)"
+	case yaml.SequenceNode:
+		return fmt.Sprintf("(list of %d items)", len(node.Content))
+	default:
+		return ""
+	}
+}
+
+// hasCommentText reports whether a YAML comment says anything: it is not
+// empty or made of bare "#" lines.
+func hasCommentText(comment string) bool {
+	return strings.Trim(comment, "# \t\r\n") != ""
+}
+
+// commentLines turns a description into one or more "# " comment lines.
+func commentLines(desc string) string {
+	lines := strings.Split(strings.TrimSpace(desc), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("# "+strings.TrimSpace(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// syncYAMLNode merges a mutated decoded value back into its source node tree:
+// keys missing from the value are removed, new keys are appended in sorted
+// order, changed scalars and lists are rewritten, and nodes whose value is
+// unchanged are kept as they are. Rewritten nodes keep their comments.
+func syncYAMLNode(node *yaml.Node, value interface{}) error {
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return syncYAMLNode(node.Content[0], value)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if node.Kind != yaml.MappingNode {
+			return replaceYAMLNode(node, value)
+		}
+		seen := make(map[string]bool, len(v))
+		content := make([]*yaml.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			child, ok := v[key.Value]
+			if !ok {
+				continue
+			}
+			seen[key.Value] = true
+			if err := syncYAMLNode(val, child); err != nil {
+				return err
+			}
+			content = append(content, key, val)
+		}
+
+		added := make([]string, 0)
+		for key := range v {
+			if !seen[key] {
+				added = append(added, key)
+			}
+		}
+		sort.Strings(added)
+		for _, key := range added {
+			var valNode yaml.Node
+			if err := valNode.Encode(v[key]); err != nil {
+				return fmt.Errorf("failed to encode %s: %w", key, err)
+			}
+			content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &valNode)
+		}
+		node.Content = content
+	case []interface{}:
+		if node.Kind != yaml.SequenceNode || len(node.Content) != len(v) {
+			return replaceYAMLNode(node, value)
+		}
+		for i := range v {
+			if err := syncYAMLNode(node.Content[i], v[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		var current interface{}
+		if err := node.Decode(&current); err == nil && reflect.DeepEqual(current, value) {
+			return nil
+		}
+		return replaceYAMLNode(node, value)
+	}
+	return nil
+}
+
+// replaceYAMLNode rewrites node to hold value, keeping its comments and, for
+// scalars, its quoting style.
+func replaceYAMLNode(node *yaml.Node, value interface{}) error {
+	var fresh yaml.Node
+	if err := fresh.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %v: %w", value, err)
+	}
+	if fresh.Kind == yaml.ScalarNode && node.Kind == yaml.ScalarNode && fresh.Tag == "!!str" && !strings.Contains(fresh.Value, "\n") {
+		fresh.Style = node.Style &^ (yaml.LiteralStyle | yaml.FoldedStyle)
+	}
+	fresh.HeadComment, fresh.LineComment, fresh.FootComment = node.HeadComment, node.LineComment, node.FootComment
+	*node = fresh
+	return nil
+}
+
+func encodeYAML(root *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
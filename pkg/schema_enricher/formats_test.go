@@ -0,0 +1,96 @@
+package schema_enricher
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/formats")
+
+// TestDocumentGolden enriches each file of testdata/formats with a
+// description of every entry lacking one and compares the result with its
+// .golden file: new descriptions are added, empty ones updated, and the
+// comments and layout of the rest kept.
+func TestDocumentGolden(t *testing.T) {
+	cases := []struct {
+		file      string
+		collected []string
+	}{
+		{"schema.json", []string{"_schema", "mode", "port"}},
+		{"schema.yaml", []string{"_schema", "mode", "port"}},
+		{"config.yaml", []string{"retries", "server.host", "timeout"}},
+		{"config.toml", []string{"retry.count", "server", "server.host", "server.tags", "timeout"}},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			path := filepath.Join("testdata", "formats", c.file)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := parseDocument(path, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			props := doc.collect()
+			var paths []string
+			for _, p := range props {
+				paths = append(paths, p.path)
+			}
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, c.collected) {
+				t.Errorf("collected %q, want %q", paths, c.collected)
+			}
+
+			results := make([]enrichmentResult, len(props))
+			for i, p := range props {
+				results[i] = enrichmentResult{Description: "About " + p.path + "."}
+				if p.path == "mode" {
+					results[i].EnumDescriptions = map[string]string{"dev": "Local development.", "prod": "Production."}
+				}
+			}
+			doc.apply(props, results)
+			got, err := doc.marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := path + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("enriched %s:\n%s\nwant:\n%s", c.file, got, want)
+			}
+		})
+	}
+}
+
+func TestSyncYAMLNodeUpdatesScalars(t *testing.T) {
+	doc, err := parseDocument("s.yaml", []byte("$schema: x\n# Head\ndescription: 'old' # line\ntype: string\nexamples: [a]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := doc.(*yamlSchemaDoc).data
+	schema["description"] = "new"
+	schema["examples"] = []interface{}{"a", "b"}
+	got, err := doc.marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "$schema: x\n# Head\ndescription: 'new' # line\ntype: string\nexamples:\n  - a\n  - b\n"
+	if string(got) != want {
+		t.Errorf("marshal() =\n%s\nwant\n%s", got, want)
+	}
+}
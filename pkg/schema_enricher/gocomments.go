@@ -0,0 +1,382 @@
+package schema_enricher
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// goField is a struct field addressable by its serialized (yaml/json) name.
+type goField struct {
+	key      string // yaml or json tag name
+	doc      string
+	typeName string // qualified element type name for nested structs, "" for builtins
+	inline   bool
+}
+
+// goStructIndex maps Go struct types in a project to their tagged fields and
+// doc comments, so schema properties reflected from those structs (as
+// tools/schema-generator does) can be described from source. Types are keyed
+// by their package's import path and name, as in
+// example.com/app/pkg/config.Settings, so same-named types of different
+// packages are kept apart.
+type goStructIndex struct {
+	docs    map[string]string
+	structs map[string][]goField
+}
+
+// indexGoStructs parses every non-test Go file under dir.
+func indexGoStructs(dir string) *goStructIndex {
+	idx := &goStructIndex{
+		docs:    make(map[string]string),
+		structs: make(map[string][]goField),
+	}
+	module := goModulePath(dir)
+	fset := token.NewFileSet()
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		idx.addFile(file, packagePath(module, filepath.ToSlash(rel)))
+		return nil
+	})
+	return idx
+}
+
+// goModulePath returns the module path of the go.mod in dir, or "" when
+// there is none; packages are then keyed by their directory.
+func goModulePath(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod")) //nolint:gosec // project file
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// packagePath returns the import path of the package in directory rel of
+// module.
+func packagePath(module, rel string) string {
+	switch {
+	case rel == ".":
+		return module
+	case module == "":
+		return rel
+	default:
+		return module + "/" + rel
+	}
+}
+
+// goFile is the context type names of a file resolve in: its package and
+// the import paths of its imports by name.
+type goFile struct {
+	pkg     string
+	imports map[string]string
+}
+
+func (idx *goStructIndex) addFile(file *ast.File, pkg string) {
+	gf := goFile{pkg: pkg, imports: make(map[string]string)}
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		gf.imports[name] = path
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			doc := ts.Doc
+			if doc == nil {
+				doc = gen.Doc
+			}
+			name := qualifiedTypeName(pkg, ts.Name.Name)
+			idx.docs[name] = commentText(doc)
+			idx.structs[name] = gf.structFields(st)
+		}
+	}
+}
+
+// qualifiedTypeName returns the index key of type name of package pkg.
+func qualifiedTypeName(pkg, name string) string {
+	if pkg == "" || pkg == "." {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func (gf goFile) structFields(st *ast.StructType) []goField {
+	var fields []goField
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+		key, opts := tagName(reflect.StructTag(tag))
+		if key == "-" {
+			continue
+		}
+		doc := commentText(f.Doc)
+		if doc == "" {
+			doc = commentText(f.Comment)
+		}
+		fields = append(fields, goField{
+			key:      key,
+			doc:      doc,
+			typeName: gf.elemTypeName(f.Type),
+			inline:   key == "" && strings.Contains(opts, "inline"),
+		})
+	}
+	return fields
+}
+
+// tagName returns the serialized name from the yaml tag, falling back to json.
+func tagName(tag reflect.StructTag) (string, string) {
+	for _, key := range []string{"yaml", "json"} {
+		if v, ok := tag.Lookup(key); ok {
+			name, opts, _ := strings.Cut(v, ",")
+			return name, opts
+		}
+	}
+	return "", ""
+}
+
+// elemTypeName unwraps pointers, slices and maps to the qualified name of
+// the element type: a type of the file's package, or of one it imports.
+func (gf goFile) elemTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return qualifiedTypeName(gf.pkg, t.Name)
+	case *ast.StarExpr:
+		return gf.elemTypeName(t.X)
+	case *ast.ArrayType:
+		return gf.elemTypeName(t.Elt)
+	case *ast.MapType:
+		return gf.elemTypeName(t.Value)
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if path, ok := gf.imports[x.Name]; ok {
+				return qualifiedTypeName(path, t.Sel.Name)
+			}
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// fields returns a struct's fields with inline (embedded) fields flattened.
+func (idx *goStructIndex) fields(typeName string) []goField {
+	var out []goField
+	for _, f := range idx.structs[typeName] {
+		if f.inline {
+			out = append(out, idx.fields(f.typeName)...)
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// rootType picks the struct whose fields best cover the given top-level
+// keys: the one matching the most keys, then the one with the fewest fields
+// the keys do not use. It must match at least half of the keys, so a struct
+// sharing a few common names, such as name or version, is not taken for the
+// root.
+func (idx *goStructIndex) rootType(topKeys map[string]bool) string {
+	best, bestScore, bestExtra := "", 0, 0
+	for name := range idx.structs {
+		score, extra := 0, 0
+		for _, f := range idx.fields(name) {
+			if topKeys[f.key] {
+				score++
+			} else {
+				extra++
+			}
+		}
+		better := score > bestScore ||
+			(score == bestScore && score > 0 && (extra < bestExtra || (extra == bestExtra && name < best)))
+		if better {
+			best, bestScore, bestExtra = name, score, extra
+		}
+	}
+	if bestScore*2 < len(topKeys) {
+		return ""
+	}
+	return best
+}
+
+// describe returns the doc comment for a dotted property path resolved from
+// root, or "" when the path does not map to a documented field.
+func (idx *goStructIndex) describe(root, path string) string {
+	if path == "_schema" {
+		return idx.docs[root]
+	}
+	typeName := root
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		var match *goField
+		for _, f := range idx.fields(typeName) {
+			if f.key == part {
+				match = &f
+				break
+			}
+		}
+		if match == nil {
+			return ""
+		}
+		if i == len(parts)-1 {
+			if match.doc == "" {
+				// Fall back to the doc comment on the field's struct type.
+				return idx.docs[match.typeName]
+			}
+			return match.doc
+		}
+		typeName = match.typeName
+	}
+	return ""
+}
+
+// defType returns the struct a schema definition of the given name was
+// reflected from, keyed by bare type name as the reflector does. Same-named
+// types of several packages are told apart by the definition's keys, then
+// by root's package.
+func (idx *goStructIndex) defType(name string, keys map[string]bool, root string) string {
+	rootPkg := ""
+	if i := strings.LastIndex(root, "."); i >= 0 {
+		rootPkg = root[:i]
+	}
+	best, bestScore, bestLocal := "", -1, false
+	for typeName := range idx.structs {
+		if typeName != name && !strings.HasSuffix(typeName, "."+name) {
+			continue
+		}
+		score := 0
+		for _, f := range idx.fields(typeName) {
+			if keys[f.key] {
+				score++
+			}
+		}
+		local := rootPkg != "" && typeName == qualifiedTypeName(rootPkg, name)
+		if score > bestScore || (score == bestScore && local && !bestLocal) ||
+			(score == bestScore && local == bestLocal && typeName < best) {
+			best, bestScore, bestLocal = typeName, score, local
+		}
+	}
+	return best
+}
+
+// describeFromSource fills descriptions from Go doc comments and returns the
+// properties that still need one along with those that were resolved. Top
+// level properties are resolved from the struct best matching their keys,
+// and the properties of $defs from the struct each definition is named
+// after.
+func describeFromSource(projectDir string, props []propertyInfo) (remaining, described []propertyInfo, results []enrichmentResult) {
+	idx := indexGoStructs(projectDir)
+	if len(idx.structs) == 0 {
+		return props, nil, nil
+	}
+
+	topKeys := make(map[string]bool)
+	defKeys := make(map[string]map[string]bool)
+	for _, prop := range props {
+		if rest, ok := strings.CutPrefix(prop.path, defsPrefix); ok {
+			name, key, _ := strings.Cut(rest, ".")
+			if defKeys[name] == nil {
+				defKeys[name] = make(map[string]bool)
+			}
+			key, _, _ = strings.Cut(key, ".")
+			if key != "" {
+				defKeys[name][key] = true
+			}
+			if nested, ok := prop.schema["properties"].(map[string]interface{}); ok && key == "" {
+				for k := range nested {
+					defKeys[name][k] = true
+				}
+			}
+			continue
+		}
+		if prop.path != "_schema" {
+			top, _, _ := strings.Cut(prop.path, ".")
+			topKeys[top] = true
+		}
+	}
+	root := idx.rootType(topKeys)
+	defTypes := make(map[string]string)
+	for name, keys := range defKeys {
+		defTypes[name] = idx.defType(name, keys, root)
+	}
+
+	for _, prop := range props {
+		typeName, path := root, prop.path
+		if rest, ok := strings.CutPrefix(prop.path, defsPrefix); ok {
+			name, key, _ := strings.Cut(rest, ".")
+			typeName, path = defTypes[name], key
+			if path == "" {
+				path = "_schema"
+			}
+		}
+		if typeName != "" {
+			if doc := idx.describe(typeName, path); doc != "" {
+				described = append(described, prop)
+				results = append(results, enrichmentResult{Description: doc})
+				continue
+			}
+		}
+		remaining = append(remaining, prop)
+	}
+	return remaining, described, results
+}
@@ -0,0 +1,170 @@
+package schema_enricher
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/invopop/jsonschema"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDescribeFromSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"pkg/config/config.go": `package config
+
+import remote "example.com/app/pkg/client"
+
+// Config is the app's configuration file.
+type Config struct {
+	Name   string        ` + "`yaml:\"name\"`" + ` // Name of the app
+	Server ServerConfig  ` + "`yaml:\"server\"`" + `
+	Client remote.Config ` + "`yaml:\"client\"`" + ` // Connection to the upstream API
+}
+
+// ServerConfig configures the local server.
+type ServerConfig struct {
+	Port int ` + "`yaml:\"port\"`" + ` // Port the server listens on
+}
+`,
+		"pkg/client/client.go": `package client
+
+// ServerConfig addresses a remote server.
+type ServerConfig struct {
+	Port int ` + "`yaml:\"port\"`" + ` // Port of the remote server
+}
+
+// Config configures the API client.
+type Config struct {
+	Timeout string ` + "`yaml:\"timeout\"`" + ` // Request timeout
+	Server  ServerConfig ` + "`yaml:\"server\"`" + `
+}
+`,
+	})
+
+	props := []propertyInfo{
+		{path: "_schema"},
+		{path: "name"},
+		{path: "server"},
+		{path: "server.port"},
+		{path: "client.timeout"},
+		{path: "client.server.port"},
+		{path: "client.retries"},
+		{path: "$defs.ServerConfig"},
+		{path: "$defs.ServerConfig.port"},
+		{path: "$defs.Config.timeout"},
+	}
+	remaining, described, results := describeFromSource(dir, props)
+	got := make(map[string]string)
+	for i, p := range described {
+		got[p.path] = results[i].Description
+	}
+	want := map[string]string{
+		"_schema":            "Config is the app's configuration file.",
+		"name":               "Name of the app",
+		"server":             "ServerConfig configures the local server.",
+		"server.port":        "Port the server listens on",
+		"client.timeout":     "Request timeout",
+		"client.server.port": "Port of the remote server",
+		// Same-named definitions resolve to the root's package, or to the
+		// type with the definition's keys
+		"$defs.ServerConfig":      "ServerConfig configures the local server.",
+		"$defs.ServerConfig.port": "Port the server listens on",
+		"$defs.Config.timeout":    "Request timeout",
+	}
+	for path, desc := range want {
+		if got[path] != desc {
+			t.Errorf("%s = %q, want %q", path, got[path], desc)
+		}
+	}
+	if len(remaining) != 1 || remaining[0].path != "client.retries" {
+		t.Errorf("remaining = %+v, want client.retries", remaining)
+	}
+}
+
+func TestRootType(t *testing.T) {
+	idx := &goStructIndex{structs: map[string][]goField{
+		"a.Config":  {{key: "name"}, {key: "server"}, {key: "client"}},
+		"b.Wide":    {{key: "name"}, {key: "server"}, {key: "client"}, {key: "debug"}},
+		"c.Package": {{key: "name"}, {key: "version"}},
+	}}
+	cases := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"name", "server", "client"}, "a.Config"},
+		{[]string{"name", "server", "client", "debug"}, "b.Wide"},
+		{[]string{"name", "version"}, "c.Package"},
+		{[]string{"name", "paths", "hooks", "plugins"}, ""},
+	}
+	for _, c := range cases {
+		keys := make(map[string]bool)
+		for _, k := range c.keys {
+			keys[k] = true
+		}
+		if got := idx.rootType(keys); got != c.want {
+			t.Errorf("rootType(%q) = %q, want %q", c.keys, got, c.want)
+		}
+	}
+}
+
+// TestDescribeReflectedSchema describes a schema reflected from this
+// repository's config as tools/schema-generator reflects it, with nested
+// structs as $refs into $defs, from the config package's doc comments.
+func TestDescribeReflectedSchema(t *testing.T) {
+	r := &jsonschema.Reflector{AllowAdditionalProperties: true, ExpandedStruct: true, FieldNameTag: "yaml"}
+	data, err := json.Marshal(r.Reflect(&config.DocgenConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	// Drop the jsonschema tag descriptions, leaving the doc comments
+	var strip func(v interface{})
+	strip = func(v interface{}) {
+		if m, ok := v.(map[string]interface{}); ok {
+			if _, ok := m["description"].(string); ok {
+				delete(m, "description")
+			}
+			for _, child := range m {
+				strip(child)
+			}
+		}
+	}
+	strip(schema)
+
+	_, described, results := describeFromSource("../..", collectSchemaProperties(schema))
+	got := make(map[string]string)
+	for i, p := range described {
+		got[p.path] = results[i].Description
+	}
+	want := map[string]string{
+		"$defs.OllamaConfig":          "OllamaConfig configures the local models served by Ollama.",
+		"$defs.SettingsConfig.ollama": "OllamaConfig configures the local models served by Ollama.",
+		"$defs.TutorialConfig":        "TutorialConfig controls the execution of tutorial commands. The sandbox they run in is a working directory and a minimal environment, not a security boundary, so running them must be allowed explicitly.",
+		"$defs.ScrubRule":             "ScrubRule replaces the matches of a regular expression in public builds.",
+	}
+	for path, desc := range want {
+		if got[path] != desc {
+			t.Errorf("%s = %q, want %q", path, got[path], desc)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package schema_enricher
+
+import (
+	"github.com/grovetools/docgen/pkg/descriptions"
+)
+
+// propertyType returns the JSON schema type of a property, or "" for example
+// config keys and untyped properties.
+func propertyType(prop propertyInfo) string {
+	if typ, ok := prop.schema["type"].(string); ok {
+		return typ
+	}
+	return ""
+}
+
+// describeFromStore fills descriptions from "schema:" entries in a
+// descriptions store that are still fresh for the property's type, and
+// returns the properties that still need one along with those that were
+// resolved.
+func describeFromStore(store *descriptions.Store, props []propertyInfo) (remaining, described []propertyInfo, results []enrichmentResult) {
+	for _, prop := range props {
+		id := descriptions.ID(descriptions.KindSchema, prop.path)
+		if entry, ok := store.Get(id); ok && !store.Stale(id, descriptions.SchemaHash(propertyType(prop))) && entry.Description != "" {
+			described = append(described, prop)
+			results = append(results, enrichmentResult{Description: entry.Description})
+			continue
+		}
+		remaining = append(remaining, prop)
+	}
+	return remaining, described, results
+}
+
+// recordInStore saves LLM-generated descriptions to the store so later runs
+// and docgen sections reuse them.
+func recordInStore(store *descriptions.Store, props []propertyInfo, results []enrichmentResult) {
+	for i, prop := range props {
+		if i >= len(results) {
+			break
+		}
+		if prop.path == "_schema" {
+			continue
+		}
+		store.Put(descriptions.ID(descriptions.KindSchema, prop.path), descriptions.Entry{Description: results[i].Description}, descriptions.SchemaHash(propertyType(prop)))
+	}
+}
@@ -0,0 +1,241 @@
+// Package snippets extracts marked code regions from source files so docs can
+// inline the current code instead of a copy that drifts.
+//
+// A region starts at a comment line whose text begins with the marker
+// "docgen:snippet:" followed by the snippet name, and ends at the next
+// "docgen:snippet:end" comment line. Regions may nest; marker lines are not
+// part of any snippet. Any line comment style works (//, #, --, ;, /* and
+// <!--). Prompts and markdown reference a snippet as {{snippet name}}.
+package snippets
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Snippet is one marked region of a source file.
+type Snippet struct {
+	Name string `json:"name"`
+	File string `json:"file"` // Relative to the scanned root
+	Line int    `json:"line"` // Line of the start marker
+	Lang string `json:"lang"` // Fence language derived from the file extension
+	Code string `json:"code"`
+}
+
+// Index maps snippet names to snippets.
+type Index map[string]Snippet
+
+var (
+	markerRe = regexp.MustCompile(`^\s*(?://|#|--|;|/\*|<!--)\s*docgen:snippet:([\w.-]+)`)
+	refRe    = regexp.MustCompile(`\{\{\s*snippet\s+([\w.-]+)\s*\}\}`)
+	fenceRe  = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// skipDirs are never scanned for snippets.
+var skipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "dist": true, "build": true, "target": true,
+}
+
+var languages = map[string]string{
+	".go": "go", ".ts": "ts", ".tsx": "tsx", ".js": "js", ".jsx": "jsx", ".py": "python",
+	".rs": "rust", ".sh": "bash", ".bash": "bash", ".yml": "yaml", ".yaml": "yaml",
+	".toml": "toml", ".json": "json", ".sql": "sql", ".lua": "lua", ".rb": "ruby",
+	".java": "java", ".c": "c", ".h": "c", ".cpp": "cpp", ".css": "css", ".html": "html",
+}
+
+const maxFileSize = 1 << 20
+
+// Scan indexes the snippets in every text file under root. Hidden and
+// dependency directories are skipped, as are markdown files, which reference
+// snippets rather than define them. Duplicate names and unbalanced markers are
+// errors.
+func Scan(root string) (Index, error) {
+	idx := make(Index)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext == ".md" || ext == ".mdx" || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxFileSize {
+			return nil //nolint:nilerr // unreadable or large files hold no snippets
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // walking the package's own sources
+		if err != nil || bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 || !bytes.Contains(data, []byte("docgen:snippet:")) {
+			return nil //nolint:nilerr // unreadable and binary files hold no snippets
+		}
+		rel, _ := filepath.Rel(root, path)
+		found, err := parse(filepath.ToSlash(rel), languages[ext], string(data))
+		if err != nil {
+			return err
+		}
+		for _, s := range found {
+			if prev, ok := idx[s.Name]; ok {
+				return fmt.Errorf("snippet %q is defined in both %s:%d and %s:%d", s.Name, prev.File, prev.Line, s.File, s.Line)
+			}
+			idx[s.Name] = s
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// parse extracts the snippets of one file.
+func parse(file, lang, content string) ([]Snippet, error) {
+	type open struct {
+		snippet Snippet
+		lines   []string
+	}
+	var stack []*open
+	var found []Snippet
+
+	for i, line := range strings.Split(content, "\n") {
+		if m := markerRe.FindStringSubmatch(line); m != nil {
+			if m[1] != "end" {
+				stack = append(stack, &open{snippet: Snippet{Name: m[1], File: file, Line: i + 1, Lang: lang}})
+				continue
+			}
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%s:%d: docgen:snippet:end without a start marker", file, i+1)
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			top.snippet.Code = dedent(top.lines)
+			found = append(found, top.snippet)
+			continue
+		}
+		for _, o := range stack {
+			o.lines = append(o.lines, line)
+		}
+	}
+	if len(stack) > 0 {
+		s := stack[len(stack)-1].snippet
+		return nil, fmt.Errorf("%s:%d: snippet %q has no docgen:snippet:end marker", file, s.Line, s.Name)
+	}
+	return found, nil
+}
+
+// dedent removes the indentation shared by every non-blank line and trims
+// surrounding blank lines.
+func dedent(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	prefix, first := "", true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = indent, false
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.TrimRight(strings.TrimPrefix(line, prefix), " \t\r")
+	}
+	return strings.Join(out, "\n")
+}
+
+// Ref is one {{snippet name}} reference.
+type Ref struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// HasRefs reports whether content references any snippet.
+func HasRefs(content string) bool {
+	return refRe.MatchString(content)
+}
+
+// Refs returns the snippet references in content, in order. References
+// quoted as inline code are not references.
+func Refs(file, content string) []Ref {
+	var refs []Ref
+	for i, line := range strings.Split(content, "\n") {
+		for _, loc := range refRe.FindAllStringSubmatchIndex(line, -1) {
+			if !quoted(line, loc[0], loc[1]) {
+				refs = append(refs, Ref{Name: line[loc[2]:loc[3]], File: file, Line: i + 1})
+			}
+		}
+	}
+	return refs
+}
+
+// quoted reports whether line[start:end] is wrapped in backticks.
+func quoted(line string, start, end int) bool {
+	return start > 0 && end < len(line) && line[start-1] == '`' && line[end] == '`'
+}
+
+// Expand replaces every {{snippet name}} in content with the snippet's code:
+// as a fenced code block, or the bare code when the reference already sits in
+// a code block. References quoted as inline code are kept. References to
+// unknown snippets are left in place and their names returned, sorted and
+// deduplicated.
+func Expand(content string, idx Index) (string, []string) {
+	missing := make(map[string]bool)
+	lines := strings.Split(content, "\n")
+	inFence := false
+	for i, line := range lines {
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		var sb strings.Builder
+		last := 0
+		for _, loc := range refRe.FindAllStringSubmatchIndex(line, -1) {
+			name := line[loc[2]:loc[3]]
+			s, ok := idx[name]
+			if quoted(line, loc[0], loc[1]) && !inFence {
+				continue
+			}
+			if !ok {
+				missing[name] = true
+				continue
+			}
+			sb.WriteString(line[last:loc[0]])
+			if inFence {
+				sb.WriteString(s.Code)
+			} else {
+				fmt.Fprintf(&sb, "```%s\n%s\n```", s.Lang, s.Code)
+			}
+			last = loc[1]
+		}
+		sb.WriteString(line[last:])
+		lines[i] = sb.String()
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(lines, "\n"), names
+}
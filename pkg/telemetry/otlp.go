@@ -0,0 +1,197 @@
+package telemetry
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The OTLP/JSON message shapes docgen sends: the subset of
+// ExportTraceServiceRequest and ExportMetricsServiceRequest it uses. Trace
+// and span ids are hex strings and 64-bit integers decimal strings, as the
+// OTLP JSON encoding requires.
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpAttr struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttr `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []otlpAttr `json:"attributes,omitempty"`
+	Status            otlpStatus `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpDataPoint struct {
+	Attributes        []otlpAttr `json:"attributes,omitempty"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	TimeUnixNano      string     `json:"timeUnixNano"`
+	AsInt             string     `json:"asInt"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+type otlpMetric struct {
+	Name string  `json:"name"`
+	Unit string  `json:"unit,omitempty"`
+	Sum  otlpSum `json:"sum"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpMetrics struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// aggregationCumulative is OTLP's AGGREGATION_TEMPORALITY_CUMULATIVE:
+// counters report their total since the provider started.
+const aggregationCumulative = 2
+
+func (p *Provider) scope() otlpScope {
+	return otlpScope{Name: ScopeName, Version: p.version}
+}
+
+// encodeSpans builds the export request for finished spans.
+func (p *Provider) encodeSpans(spans []*Span) otlpTraces {
+	ss := otlpScopeSpans{Scope: p.scope()}
+	for _, s := range spans {
+		s.mu.Lock()
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Attributes:        encodeAttrs(s.attrs),
+			Status:            otlpStatus{Code: s.statusCode, Message: s.statusMsg},
+		}
+		s.mu.Unlock()
+		if s.parentID != [8]byte{} {
+			span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		ss.Spans = append(ss.Spans, span)
+	}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttrs(p.resource)},
+		ScopeSpans: []otlpScopeSpans{ss},
+	}}}
+}
+
+// encodeMetrics builds the export request for the counters' totals at now.
+func (p *Provider) encodeMetrics(counters []counter, now time.Time) otlpMetrics {
+	byName := make(map[string]*otlpMetric)
+	var order []string
+	for _, c := range counters {
+		m := byName[c.name]
+		if m == nil {
+			m = &otlpMetric{Name: c.name, Unit: c.unit, Sum: otlpSum{AggregationTemporality: aggregationCumulative, IsMonotonic: true}}
+			byName[c.name] = m
+			order = append(order, c.name)
+		}
+		m.Sum.DataPoints = append(m.Sum.DataPoints, otlpDataPoint{
+			Attributes:        encodeAttrs(c.attrs),
+			StartTimeUnixNano: unixNano(p.started),
+			TimeUnixNano:      unixNano(now),
+			AsInt:             strconv.FormatInt(c.value, 10),
+		})
+	}
+
+	sm := otlpScopeMetrics{Scope: p.scope()}
+	for _, name := range order {
+		sm.Metrics = append(sm.Metrics, *byName[name])
+	}
+	return otlpMetrics{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     otlpResource{Attributes: encodeAttrs(p.resource)},
+		ScopeMetrics: []otlpScopeMetrics{sm},
+	}}}
+}
+
+// encodeAttrs converts attributes to OTLP key-values; values of other types
+// are recorded as strings.
+func encodeAttrs(attrs []Attr) []otlpAttr {
+	out := make([]otlpAttr, 0, len(attrs))
+	for _, a := range attrs {
+		var v otlpValue
+		switch val := a.Value.(type) {
+		case string:
+			v.StringValue = &val
+		case bool:
+			v.BoolValue = &val
+		case int:
+			s := strconv.Itoa(val)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(val, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &val
+		default:
+			s := attrString(val)
+			v.StringValue = &s
+		}
+		out = append(out, otlpAttr{Key: a.Key, Value: v})
+	}
+	return out
+}
+
+func attrString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
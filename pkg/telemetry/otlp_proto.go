@@ -0,0 +1,204 @@
+package telemetry
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strconv"
+)
+
+// The OTLP/protobuf encoding of the messages in otlp.go, for the
+// http/protobuf protocol. It is written by hand from the field numbers of
+// opentelemetry-proto (collector/trace/v1, collector/metrics/v1 and their
+// dependencies), which is all docgen needs of protobuf.
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// protoWriter appends protobuf fields to buf. Like proto3, it leaves out
+// fields with their default value, except the members of a oneof.
+type protoWriter struct {
+	buf []byte
+}
+
+func (w *protoWriter) key(field, wire int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(field)<<3|uint64(wire))
+}
+
+// varint writes an integer, enum or bool field.
+func (w *protoWriter) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.key(field, wireVarint)
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+// fixed64 writes a fixed64, sfixed64 or double field.
+func (w *protoWriter) fixed64(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.key(field, wireFixed64)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, v)
+}
+
+// bytes writes a bytes or string field.
+func (w *protoWriter) bytes(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	w.key(field, wireBytes)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *protoWriter) string(field int, s string) {
+	w.bytes(field, []byte(s))
+}
+
+// message writes an embedded message, even an empty one.
+func (w *protoWriter) message(field int, encode func(m *protoWriter)) {
+	var m protoWriter
+	encode(&m)
+	w.key(field, wireBytes)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(m.buf)))
+	w.buf = append(w.buf, m.buf...)
+}
+
+// hexBytes decodes the ids otlp.go holds as hex strings.
+func hexBytes(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
+
+// nanos parses the timestamps otlp.go holds as decimal strings.
+func nanos(s string) uint64 {
+	n, _ := strconv.ParseUint(s, 10, 64)
+	return n
+}
+
+// marshalProto encodes an ExportTraceServiceRequest.
+func (t otlpTraces) marshalProto() []byte {
+	var w protoWriter
+	for _, rs := range t.ResourceSpans {
+		w.message(1, func(w *protoWriter) {
+			w.message(1, rs.Resource.encode)
+			for _, ss := range rs.ScopeSpans {
+				w.message(2, func(w *protoWriter) {
+					w.message(1, ss.Scope.encode)
+					for _, s := range ss.Spans {
+						w.message(2, s.encode)
+					}
+				})
+			}
+		})
+	}
+	return w.buf
+}
+
+func (s otlpSpan) encode(w *protoWriter) {
+	w.bytes(1, hexBytes(s.TraceID))
+	w.bytes(2, hexBytes(s.SpanID))
+	w.bytes(4, hexBytes(s.ParentSpanID))
+	w.string(5, s.Name)
+	w.varint(6, uint64(s.Kind))
+	w.fixed64(7, nanos(s.StartTimeUnixNano))
+	w.fixed64(8, nanos(s.EndTimeUnixNano))
+	encodeProtoAttrs(w, 9, s.Attributes)
+	w.message(15, func(w *protoWriter) {
+		w.string(2, s.Status.Message)
+		w.varint(3, uint64(s.Status.Code))
+	})
+}
+
+// marshalProto encodes an ExportMetricsServiceRequest.
+func (m otlpMetrics) marshalProto() []byte {
+	var w protoWriter
+	for _, rm := range m.ResourceMetrics {
+		w.message(1, func(w *protoWriter) {
+			w.message(1, rm.Resource.encode)
+			for _, sm := range rm.ScopeMetrics {
+				w.message(2, func(w *protoWriter) {
+					w.message(1, sm.Scope.encode)
+					for _, metric := range sm.Metrics {
+						w.message(2, metric.encode)
+					}
+				})
+			}
+		})
+	}
+	return w.buf
+}
+
+func (m otlpMetric) encode(w *protoWriter) {
+	w.string(1, m.Name)
+	w.string(3, m.Unit)
+	w.message(7, func(w *protoWriter) {
+		for _, dp := range m.Sum.DataPoints {
+			w.message(1, dp.encode)
+		}
+		w.varint(2, uint64(m.Sum.AggregationTemporality))
+		if m.Sum.IsMonotonic {
+			w.varint(3, 1)
+		}
+	})
+}
+
+func (dp otlpDataPoint) encode(w *protoWriter) {
+	w.fixed64(2, nanos(dp.StartTimeUnixNano))
+	w.fixed64(3, nanos(dp.TimeUnixNano))
+	// as_int is a oneof member, written even when zero
+	n, _ := strconv.ParseInt(dp.AsInt, 10, 64)
+	w.key(6, wireFixed64)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, uint64(n))
+	encodeProtoAttrs(w, 7, dp.Attributes)
+}
+
+func (r otlpResource) encode(w *protoWriter) {
+	encodeProtoAttrs(w, 1, r.Attributes)
+}
+
+func (s otlpScope) encode(w *protoWriter) {
+	w.string(1, s.Name)
+	w.string(2, s.Version)
+}
+
+// encodeProtoAttrs writes attributes as repeated KeyValue messages.
+func encodeProtoAttrs(w *protoWriter, field int, attrs []otlpAttr) {
+	for _, a := range attrs {
+		w.message(field, func(w *protoWriter) {
+			w.string(1, a.Key)
+			w.message(2, a.Value.encode)
+		})
+	}
+}
+
+// encode writes an AnyValue. Its value is a oneof, so it is written even
+// when it is the default.
+func (v otlpValue) encode(w *protoWriter) {
+	switch {
+	case v.StringValue != nil:
+		w.key(1, wireBytes)
+		w.buf = binary.AppendUvarint(w.buf, uint64(len(*v.StringValue)))
+		w.buf = append(w.buf, *v.StringValue...)
+	case v.BoolValue != nil:
+		w.key(2, wireVarint)
+		b := uint64(0)
+		if *v.BoolValue {
+			b = 1
+		}
+		w.buf = binary.AppendUvarint(w.buf, b)
+	case v.IntValue != nil:
+		n, _ := strconv.ParseInt(*v.IntValue, 10, 64)
+		w.key(3, wireVarint)
+		w.buf = binary.AppendUvarint(w.buf, uint64(n))
+	case v.DoubleValue != nil:
+		w.key(4, wireFixed64)
+		w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(*v.DoubleValue))
+	}
+}
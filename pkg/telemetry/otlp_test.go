@@ -0,0 +1,214 @@
+package telemetry
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// testProvider returns a provider with a fixed resource and start time, for
+// encoding tests; it exports nothing.
+func testProvider() *Provider {
+	return &Provider{
+		version: "1.2.3",
+		resource: []Attr{
+			String("service.name", "docgen"),
+			String("service.version", "1.2.3"),
+			String("deployment.environment", "ci"),
+		},
+		started: time.Unix(1700000000, 0),
+	}
+}
+
+// testSpans returns a finished root span and a failed client span under it,
+// with fixed ids and times and attributes of every type.
+func testSpans(p *Provider) []*Span {
+	start := time.Unix(1700000001, 500)
+	root := &Span{
+		provider:   p,
+		name:       "docgen generate",
+		kind:       KindInternal,
+		start:      start,
+		end:        start.Add(3 * time.Second),
+		attrs:      []Attr{String("docgen.args", "generate --all"), Bool("docgen.isolate", false)},
+		statusCode: statusOK,
+	}
+	copy(root.traceID[:], mustHex("0af7651916cd43dd8448eb211c80319c"))
+	copy(root.spanID[:], mustHex("b7ad6b7169203331"))
+
+	child := &Span{
+		provider:   p,
+		traceID:    root.traceID,
+		parentID:   root.spanID,
+		name:       "docgen.llm.request",
+		kind:       KindClient,
+		start:      start.Add(time.Second),
+		end:        start.Add(2 * time.Second),
+		attrs:      []Attr{Int("docgen.attempt", 0), Int("docgen.tokens", 1234), Float("docgen.temperature", 0.7), Int("docgen.delta", -2)},
+		statusCode: statusError,
+		statusMsg:  "grove llm request failed: exit status 1",
+	}
+	copy(child.spanID[:], mustHex("00f067aa0ba902b7"))
+	return []*Span{root, child}
+}
+
+// testCounters returns two data points of one counter and a zero counter.
+func testCounters() []counter {
+	return []counter{
+		{name: MetricTokens, unit: "{token}", attrs: []Attr{String("model", "gemini-2.5-pro"), String("type", "input")}, value: 5000},
+		{name: MetricTokens, unit: "{token}", attrs: []Attr{String("model", "gemini-2.5-pro"), String("type", "output")}, value: 700},
+		{name: MetricSectionFailure, unit: "{section}", value: 0},
+	}
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TestEncodeGolden compares both encodings of a trace and a metrics export
+// with the golden files. The protobuf golden is a dump of the fields the
+// test decodes, by field number, so it can be checked against
+// opentelemetry-proto.
+func TestEncodeGolden(t *testing.T) {
+	p := testProvider()
+	traces := p.encodeSpans(testSpans(p))
+	metrics := p.encodeMetrics(testCounters(), time.Unix(1700000060, 0))
+
+	cases := []struct {
+		golden string
+		encode func() (string, error)
+	}{
+		{"traces.golden.json", func() (string, error) { return indentJSON(traces) }},
+		{"metrics.golden.json", func() (string, error) { return indentJSON(metrics) }},
+		{"traces.golden.proto.txt", func() (string, error) {
+			return dumpProto(traces.marshalProto(), "ExportTraceServiceRequest")
+		}},
+		{"metrics.golden.proto.txt", func() (string, error) {
+			return dumpProto(metrics.marshalProto(), "ExportMetricsServiceRequest")
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.golden, func(t *testing.T) {
+			got, err := c.encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", c.golden)
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("encoding differs from %s (run with -update to accept):\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func indentJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	return string(data) + "\n", err
+}
+
+// protoMessages maps each OTLP message docgen sends to its fields that are
+// embedded messages, and their types, so dumpProto can tell them from
+// strings.
+var protoMessages = map[string]map[uint64]string{
+	"ExportTraceServiceRequest":   {1: "ResourceSpans"},
+	"ResourceSpans":               {1: "Resource", 2: "ScopeSpans"},
+	"ScopeSpans":                  {1: "InstrumentationScope", 2: "Span"},
+	"Span":                        {9: "KeyValue", 15: "Status"},
+	"ExportMetricsServiceRequest": {1: "ResourceMetrics"},
+	"ResourceMetrics":             {1: "Resource", 2: "ScopeMetrics"},
+	"ScopeMetrics":                {1: "InstrumentationScope", 2: "Metric"},
+	"Metric":                      {7: "Sum"},
+	"Sum":                         {1: "NumberDataPoint"},
+	"NumberDataPoint":             {7: "KeyValue"},
+	"Resource":                    {1: "KeyValue"},
+	"KeyValue":                    {2: "AnyValue"},
+}
+
+// protoIDs are the bytes fields that hold trace and span ids, dumped in hex.
+var protoIDs = map[string]map[uint64]bool{
+	"Span": {1: true, 2: true, 4: true},
+}
+
+// dumpProto decodes a protobuf message of type msg into one line per field,
+// failing on malformed input.
+func dumpProto(data []byte, msg string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(msg + "\n")
+	err := dumpFields(&sb, data, msg, "  ")
+	return sb.String(), err
+}
+
+func dumpFields(sb *strings.Builder, data []byte, msg, indent string) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("%s: bad field key", msg)
+		}
+		data = data[n:]
+		field := key >> 3
+		switch key & 7 {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("%s.%d: bad varint", msg, field)
+			}
+			data = data[n:]
+			fmt.Fprintf(sb, "%s%d: varint %d\n", indent, field, int64(v))
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("%s.%d: short fixed64", msg, field)
+			}
+			v := binary.LittleEndian.Uint64(data)
+			if msg == "AnyValue" && field == 4 {
+				fmt.Fprintf(sb, "%s%d: double %g\n", indent, field, math.Float64frombits(v))
+			} else {
+				fmt.Fprintf(sb, "%s%d: fixed64 %d\n", indent, field, int64(v))
+			}
+			data = data[8:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return fmt.Errorf("%s.%d: bad length", msg, field)
+			}
+			b := data[n : n+int(size)]
+			data = data[n+int(size):]
+			if sub, ok := protoMessages[msg][field]; ok {
+				fmt.Fprintf(sb, "%s%d: %s\n", indent, field, sub)
+				if err := dumpFields(sb, b, sub, indent+"  "); err != nil {
+					return err
+				}
+			} else if protoIDs[msg][field] || !utf8.Valid(b) {
+				fmt.Fprintf(sb, "%s%d: bytes %x\n", indent, field, b)
+			} else {
+				fmt.Fprintf(sb, "%s%d: %q\n", indent, field, b)
+			}
+		default:
+			return fmt.Errorf("%s.%d: unexpected wire type %d", msg, field, key&7)
+		}
+	}
+	return nil
+}
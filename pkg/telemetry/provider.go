@@ -0,0 +1,337 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// flushInterval is how often finished spans are exported while a
+	// long-running command such as watch runs.
+	flushInterval = 5 * time.Second
+	// metricInterval is how often counters are exported while a command runs;
+	// they are also exported on Shutdown.
+	metricInterval = time.Minute
+	// maxQueuedSpans caps the spans held for export; past it the oldest are
+	// dropped, so an unreachable collector cannot grow memory without bound.
+	maxQueuedSpans = 10000
+)
+
+// OTLP protocols, as OTEL_EXPORTER_OTLP_PROTOCOL names them.
+const (
+	protocolProtobuf = "http/protobuf"
+	protocolJSON     = "http/json"
+	protocolGRPC     = "grpc"
+)
+
+// Provider collects spans and counters and exports them to an OTLP/HTTP
+// endpoint in the background. Create it with FromEnv, install it with
+// SetProvider, and call Shutdown before the process exits.
+type Provider struct {
+	tracesURL  string
+	metricsURL string
+	protocol   string // protocolProtobuf or protocolJSON
+	headers    map[string]string
+	resource   []Attr
+	version    string
+	client     *http.Client
+	started    time.Time
+	// parent is the remote span of the process that started docgen, from
+	// TRACEPARENT; root spans are its children. Zero when there is none.
+	parent   spanParent
+	warnings []string
+
+	mu       sync.Mutex
+	spans    []*Span
+	counters map[string]*counter
+	dropped  int
+	lastErr  error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// FromEnv returns a provider configured from the standard OpenTelemetry
+// environment variables, or nil when telemetry is off: when neither
+// OTEL_EXPORTER_OTLP_ENDPOINT nor a signal-specific endpoint is set, or
+// OTEL_SDK_DISABLED is true. It reads
+//
+//   - OTEL_EXPORTER_OTLP_ENDPOINT, the base URL (/v1/traces and /v1/metrics
+//     are appended), or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT and
+//     OTEL_EXPORTER_OTLP_METRICS_ENDPOINT, the full URLs
+//   - OTEL_EXPORTER_OTLP_HEADERS, key=value pairs separated by commas
+//   - OTEL_SERVICE_NAME (default: docgen) and OTEL_RESOURCE_ATTRIBUTES
+//   - OTEL_EXPORTER_OTLP_PROTOCOL: http/protobuf (the default) or
+//     http/json. grpc is not supported; it is exported as http/protobuf,
+//     with a warning (see Warnings).
+//   - TRACEPARENT, the W3C trace context of the process that started
+//     docgen, whose trace the run's spans join
+//
+// An unknown protocol is an error. version is docgen's version, recorded as
+// service.version.
+func FromEnv(version string) (*Provider, error) {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil, nil
+	}
+	base := strings.TrimRight(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "/")
+	tracesURL := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	metricsURL := os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT")
+	if base != "" {
+		if tracesURL == "" {
+			tracesURL = base + "/v1/traces"
+		}
+		if metricsURL == "" {
+			metricsURL = base + "/v1/metrics"
+		}
+	}
+	if tracesURL == "" && metricsURL == "" {
+		return nil, nil
+	}
+	var warnings []string
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	switch protocol {
+	case "":
+		protocol = protocolProtobuf
+	case protocolProtobuf, protocolJSON:
+	case protocolGRPC:
+		protocol = protocolProtobuf
+		warnings = append(warnings, "OTEL_EXPORTER_OTLP_PROTOCOL grpc is not supported; exporting http/protobuf instead, which the endpoint must accept (an OpenTelemetry Collector serves it on port 4318)")
+	default:
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_PROTOCOL %q is not supported: use http/protobuf or http/json", protocol)
+	}
+
+	headers, err := parsePairs(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_HEADERS: %w", err)
+	}
+	resourceAttrs, err := parsePairs(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTEL_RESOURCE_ATTRIBUTES: %w", err)
+	}
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = resourceAttrs["service.name"]
+	}
+	if service == "" {
+		service = "docgen"
+	}
+	resource := []Attr{String("service.name", service), String("service.version", version)}
+	for k, v := range resourceAttrs {
+		if k != "service.name" && k != "service.version" {
+			resource = append(resource, String(k, v))
+		}
+	}
+	if host, err := os.Hostname(); err == nil {
+		resource = append(resource, String("host.name", host))
+	}
+
+	var parent spanParent
+	if tp := os.Getenv(TraceparentEnv); tp != "" {
+		var ok bool
+		if parent, ok = parseTraceparent(tp); !ok {
+			warnings = append(warnings, fmt.Sprintf("ignoring invalid %s %q", TraceparentEnv, tp))
+		}
+	}
+
+	p := &Provider{
+		tracesURL:  tracesURL,
+		metricsURL: metricsURL,
+		protocol:   protocol,
+		parent:     parent,
+		warnings:   warnings,
+		headers:    headers,
+		resource:   resource,
+		version:    version,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		started:    time.Now(),
+		counters:   make(map[string]*counter),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go p.loop()
+	return p, nil
+}
+
+// parsePairs parses a comma-separated list of URL-encoded key=value pairs.
+func parsePairs(s string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not key=value", item)
+		}
+		key, err := url.QueryUnescape(strings.TrimSpace(k))
+		if err != nil {
+			return nil, err
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// Warnings returns the problems with the configuration FromEnv worked
+// around, for the caller to log.
+func (p *Provider) Warnings() []string {
+	if p == nil {
+		return nil
+	}
+	return p.warnings
+}
+
+// loop exports spans every flushInterval and counters every metricInterval
+// until Shutdown.
+func (p *Provider) loop() {
+	defer close(p.done)
+	spans := time.NewTicker(flushInterval)
+	defer spans.Stop()
+	metrics := time.NewTicker(metricInterval)
+	defer metrics.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-spans.C:
+			p.recordErr(p.exportSpans(context.Background()))
+		case <-metrics.C:
+			p.recordErr(p.exportMetrics(context.Background()))
+		}
+	}
+}
+
+func (p *Provider) recordErr(err error) {
+	if err == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastErr = err
+}
+
+// queue holds a finished span for export.
+func (p *Provider) queue(s *Span) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.spans) >= maxQueuedSpans {
+		p.spans = p.spans[1:]
+		p.dropped++
+	}
+	p.spans = append(p.spans, s)
+}
+
+func (p *Provider) add(name, unit string, n int64, attrs []Attr) {
+	key, sorted := counterKey(name, attrs)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c := p.counters[key]
+	if c == nil {
+		c = &counter{name: name, unit: unit, attrs: sorted}
+		p.counters[key] = c
+	}
+	c.value += n
+}
+
+// Shutdown stops the background export and exports what is left: the
+// finished spans and the counters' totals. It returns the last export error
+// of the run, if any. Spans that have not ended are not exported.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	close(p.stop)
+	<-p.done
+	err := errors.Join(p.exportSpans(ctx), p.exportMetrics(ctx))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == nil && p.lastErr != nil {
+		err = p.lastErr
+	}
+	if p.dropped > 0 {
+		err = errors.Join(err, fmt.Errorf("%d span(s) dropped because the export queue was full", p.dropped))
+	}
+	return err
+}
+
+// exportSpans sends the queued spans.
+func (p *Provider) exportSpans(ctx context.Context) error {
+	p.mu.Lock()
+	spans := p.spans
+	p.spans = nil
+	p.mu.Unlock()
+	if len(spans) == 0 || p.tracesURL == "" {
+		return nil
+	}
+	return p.post(ctx, p.tracesURL, p.encodeSpans(spans))
+}
+
+// exportMetrics sends the counters' cumulative totals.
+func (p *Provider) exportMetrics(ctx context.Context) error {
+	p.mu.Lock()
+	counters := make([]counter, 0, len(p.counters))
+	for _, c := range p.counters {
+		counters = append(counters, *c)
+	}
+	p.mu.Unlock()
+	if len(counters) == 0 || p.metricsURL == "" {
+		return nil
+	}
+	return p.post(ctx, p.metricsURL, p.encodeMetrics(counters, time.Now()))
+}
+
+// otlpRequest is an export request, sent as JSON or protobuf.
+type otlpRequest interface {
+	marshalProto() []byte
+}
+
+// post sends an OTLP/HTTP request in the provider's protocol.
+func (p *Provider) post(ctx context.Context, endpoint string, body otlpRequest) error {
+	data, contentType := body.marshalProto(), "application/x-protobuf"
+	if p.protocol == protocolJSON {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode telemetry: %w", err)
+		}
+		contentType = "application/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid OTLP endpoint %s: %w", endpoint, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export telemetry to %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // response body is drained below
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to export telemetry to %s: %s: %s", endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// unixNano formats t as OTLP JSON encodes 64-bit integers: a decimal string.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
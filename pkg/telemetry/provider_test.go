@@ -0,0 +1,283 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// clearEnv unsets the variables FromEnv reads, so the host's don't leak in.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, k := range []string{
+		"OTEL_SDK_DISABLED", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+		"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "OTEL_EXPORTER_OTLP_PROTOCOL", "OTEL_EXPORTER_OTLP_HEADERS",
+		"OTEL_SERVICE_NAME", "OTEL_RESOURCE_ATTRIBUTES", TraceparentEnv,
+	} {
+		t.Setenv(k, "")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	cases := []struct {
+		name     string
+		env      map[string]string
+		off      bool
+		err      string
+		protocol string
+		warning  string
+		traces   string
+		metrics  string
+		parent   bool
+	}{
+		{
+			name: "no endpoint",
+			off:  true,
+		},
+		{
+			name: "disabled",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_SDK_DISABLED": "true"},
+			off:  true,
+		},
+		{
+			name:     "default protocol",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318/"},
+			protocol: protocolProtobuf,
+			traces:   "http://collector:4318/v1/traces",
+			metrics:  "http://collector:4318/v1/metrics",
+		},
+		{
+			name:     "json",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_PROTOCOL": "http/json"},
+			protocol: protocolJSON,
+			traces:   "http://collector:4318/v1/traces",
+			metrics:  "http://collector:4318/v1/metrics",
+		},
+		{
+			name:     "protobuf",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://tempo:4318/v1/traces", "OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf"},
+			protocol: protocolProtobuf,
+			traces:   "http://tempo:4318/v1/traces",
+		},
+		{
+			name:     "grpc falls back to protobuf",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_PROTOCOL": "grpc"},
+			protocol: protocolProtobuf,
+			warning:  "grpc is not supported",
+			traces:   "http://collector:4318/v1/traces",
+			metrics:  "http://collector:4318/v1/metrics",
+		},
+		{
+			name: "unknown protocol",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_PROTOCOL": "udp"},
+			err:  `"udp" is not supported`,
+		},
+		{
+			name: "bad headers",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_HEADERS": "novalue"},
+			err:  "invalid OTEL_EXPORTER_OTLP_HEADERS",
+		},
+		{
+			name:     "traceparent",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", TraceparentEnv: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			protocol: protocolProtobuf,
+			traces:   "http://collector:4318/v1/traces",
+			metrics:  "http://collector:4318/v1/metrics",
+			parent:   true,
+		},
+		{
+			name:     "invalid traceparent",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", TraceparentEnv: "garbage"},
+			protocol: protocolProtobuf,
+			warning:  `ignoring invalid TRACEPARENT "garbage"`,
+			traces:   "http://collector:4318/v1/traces",
+			metrics:  "http://collector:4318/v1/metrics",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+			p, err := FromEnv("1.2.3")
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("FromEnv error = %v, want one containing %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.off {
+				if p != nil {
+					t.Fatalf("FromEnv = %+v, want telemetry off", p)
+				}
+				return
+			}
+			defer p.Shutdown(context.Background()) //nolint:errcheck // nothing is exported
+
+			if p.protocol != c.protocol {
+				t.Errorf("protocol = %q, want %q", p.protocol, c.protocol)
+			}
+			if p.tracesURL != c.traces || p.metricsURL != c.metrics {
+				t.Errorf("endpoints = %q, %q; want %q, %q", p.tracesURL, p.metricsURL, c.traces, c.metrics)
+			}
+			warnings := strings.Join(p.Warnings(), "\n")
+			if (c.warning == "") != (warnings == "") || !strings.Contains(warnings, c.warning) {
+				t.Errorf("warnings = %q, want %q", warnings, c.warning)
+			}
+			if p.parent.valid() != c.parent {
+				t.Errorf("remote parent = %v, want %v", p.parent.valid(), c.parent)
+			}
+		})
+	}
+}
+
+func TestFromEnvResource(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-honeycomb-team=KEY,x-note=a%20b")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "service.name=docs-ci,deployment.environment=ci")
+	p, err := FromEnv("1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(context.Background()) //nolint:errcheck // nothing is exported
+
+	if p.headers["x-honeycomb-team"] != "KEY" || p.headers["x-note"] != "a b" {
+		t.Errorf("headers = %v", p.headers)
+	}
+	attrs := make(map[string]any)
+	for _, a := range p.resource {
+		attrs[a.Key] = a.Value
+	}
+	for k, want := range map[string]string{"service.name": "docs-ci", "service.version": "1.2.3", "deployment.environment": "ci"} {
+		if attrs[k] != want {
+			t.Errorf("resource %s = %v, want %s", k, attrs[k], want)
+		}
+	}
+}
+
+// collector records the OTLP requests it receives.
+type collector struct {
+	mu       sync.Mutex
+	requests map[string]collected
+}
+
+type collected struct {
+	contentType string
+	header      string
+	body        []byte
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests[r.URL.Path] = collected{r.Header.Get("Content-Type"), r.Header.Get("x-team"), body}
+}
+
+// TestExport runs a traced operation against a test collector with each
+// protocol and checks what it receives.
+func TestExport(t *testing.T) {
+	cases := []struct {
+		protocol    string
+		contentType string
+	}{
+		{"http/protobuf", "application/x-protobuf"},
+		{"http/json", "application/json"},
+	}
+	for _, c := range cases {
+		t.Run(c.protocol, func(t *testing.T) {
+			col := &collector{requests: make(map[string]collected)}
+			srv := httptest.NewServer(col)
+			defer srv.Close()
+
+			clearEnv(t)
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL)
+			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", c.protocol)
+			t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-team=docs")
+			t.Setenv(TraceparentEnv, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+			p, err := FromEnv("1.2.3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			useProvider(t, p)
+
+			ctx, root := Start(context.Background(), "docgen generate")
+			_, req := StartKind(ctx, "docgen.llm.request", KindClient)
+			req.End()
+			root.SetOK()
+			root.End()
+			Add(MetricLLMRequests, "{request}", 2, String("model", "gemini-2.5-pro"))
+			if err := p.Shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			col.mu.Lock()
+			defer col.mu.Unlock()
+			for _, path := range []string{"/v1/traces", "/v1/metrics"} {
+				got, ok := col.requests[path]
+				if !ok {
+					t.Fatalf("no request to %s", path)
+				}
+				if got.contentType != c.contentType || got.header != "docs" {
+					t.Errorf("%s: Content-Type %q, x-team %q; want %q, docs", path, got.contentType, got.header, c.contentType)
+				}
+			}
+
+			traces := col.requests["/v1/traces"].body
+			want := "0af7651916cd43dd8448eb211c80319c"
+			if c.protocol == "http/json" {
+				var decoded otlpTraces
+				if err := json.Unmarshal(traces, &decoded); err != nil {
+					t.Fatal(err)
+				}
+				spans := decoded.ResourceSpans[0].ScopeSpans[0].Spans
+				if len(spans) != 2 || spans[1].TraceID != want || spans[1].ParentSpanID != "b7ad6b7169203331" {
+					t.Errorf("spans = %+v, want the root span parented to TRACEPARENT", spans)
+				}
+				return
+			}
+			dump, err := dumpProto(traces, "ExportTraceServiceRequest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(dump, "1: bytes "+want) || !strings.Contains(dump, "4: bytes "+hex.EncodeToString(root.parentID[:])) {
+				t.Errorf("traces request is not parented to TRACEPARENT:\n%s", dump)
+			}
+			if _, err := dumpProto(col.requests["/v1/metrics"].body, "ExportMetricsServiceRequest"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestExportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	clearEnv(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL)
+	p, err := FromEnv("1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	useProvider(t, p)
+	_, span := Start(context.Background(), "docgen generate")
+	span.End()
+	err = p.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "429") || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("Shutdown = %v, want the collector's error", err)
+	}
+}
@@ -0,0 +1,290 @@
+// Package telemetry traces and meters docgen runs and exports them over
+// OTLP, so ecosystem-scale documentation builds can be profiled and
+// monitored in any OpenTelemetry backend (Jaeger, Tempo, Honeycomb, an
+// OpenTelemetry Collector, ...).
+//
+// It implements the small part of OpenTelemetry docgen needs: spans with
+// attributes and an error status, monotonic counters, W3C trace context
+// propagation through the TRACEPARENT environment variable, and an OTLP/HTTP
+// exporter using the protobuf or JSON encoding. It is configured with the
+// standard OTEL_* environment variables (see FromEnv) and is off unless an
+// OTLP endpoint is set.
+//
+// Like report.Recorder, every function is a no-op when telemetry is off and
+// every method is a no-op on a nil receiver, so instrumented code needs no
+// checks.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ScopeName is the instrumentation scope of docgen's spans and metrics.
+const ScopeName = "github.com/grovetools/docgen"
+
+// TraceparentEnv is the environment variable a W3C traceparent is passed
+// between processes in: docgen joins the trace it names and passes its own
+// spans on to the subprocesses it runs.
+const TraceparentEnv = "TRACEPARENT"
+
+// Metrics docgen records.
+const (
+	MetricLLMRequests    = "docgen.llm.requests"     // LLM requests, by model, route and error
+	MetricTokens         = "docgen.llm.tokens"       // Tokens, by model and type (input, output, cache_write, cache_read)
+	MetricCacheHits      = "docgen.llm.cache_hits"   // Requests that read the shared prompt prefix from cache
+	MetricSectionFailure = "docgen.section.failures" // Failed sections, by command
+)
+
+// Span status codes, as OTLP numbers them.
+const (
+	statusUnset = 0
+	statusOK    = 1
+	statusError = 2
+)
+
+// Span kinds, as OTLP numbers them.
+const (
+	KindInternal = 1
+	KindClient   = 3
+)
+
+// Attr is a span or data point attribute. Value is a string, bool, int,
+// int64 or float64.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr { return Attr{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int64) Attr { return Attr{Key: key, Value: value} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr { return Attr{Key: key, Value: value} }
+
+// Float returns a floating-point attribute.
+func Float(key string, value float64) Attr { return Attr{Key: key, Value: value} }
+
+// global is the provider Start and Add report to; nil means telemetry is off.
+var global atomic.Pointer[Provider]
+
+// SetProvider makes p the provider Start and Add report to. A nil p turns
+// telemetry off.
+func SetProvider(p *Provider) {
+	global.Store(p)
+}
+
+// Span is one timed operation of a trace. End it exactly once.
+type Span struct {
+	provider *Provider
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     int
+	start    time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attrs      []Attr
+	statusCode int
+	statusMsg  string
+	ended      bool
+}
+
+type spanKey struct{}
+
+// Start starts a span named name as a child of the span in ctx, or as the
+// root of a new trace, and returns a context carrying it. It returns ctx and
+// a nil span when telemetry is off.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return StartKind(ctx, name, KindInternal, attrs...)
+}
+
+// StartKind is Start for a span of the given kind, such as KindClient for a
+// request to an LLM.
+func StartKind(ctx context.Context, name string, kind int, attrs ...Attr) (context.Context, *Span) {
+	p := global.Load()
+	if p == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s := &Span{provider: p, name: name, kind: kind, start: time.Now(), attrs: attrs}
+	switch parent := SpanFromContext(ctx); {
+	case parent != nil:
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	case p.parent.valid():
+		s.traceID = p.parent.traceID
+		s.parentID = p.parent.spanID
+	default:
+		_, _ = rand.Read(s.traceID[:])
+	}
+	_, _ = rand.Read(s.spanID[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SpanFromContext returns the span ctx carries, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// RecordError marks the span as failed with err's message. A nil err does
+// nothing.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusCode = statusError
+	s.statusMsg = err.Error()
+}
+
+// SetOK marks the span as succeeded, unless it already failed.
+func (s *Span) SetOK() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.statusCode != statusError {
+		s.statusCode = statusOK
+	}
+}
+
+// End ends the span and queues it for export. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.provider.queue(s)
+}
+
+// Traceparent returns the W3C traceparent header of the span, for passing
+// the trace on to a subprocess or service, or "" for a nil span.
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	return "00-" + hex.EncodeToString(s.traceID[:]) + "-" + hex.EncodeToString(s.spanID[:]) + "-01"
+}
+
+// Environ returns env with TRACEPARENT set to the span ctx carries, so a
+// subprocess started with it joins the trace; a nil env stands for the
+// process's environment. Without a span, env is returned as is, and a
+// subprocess inherits the TRACEPARENT docgen was started with, if any.
+func Environ(ctx context.Context, env []string) []string {
+	tp := SpanFromContext(ctx).Traceparent()
+	if tp == "" {
+		return env
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	out := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, TraceparentEnv+"=") {
+			out = append(out, kv)
+		}
+	}
+	return append(out, TraceparentEnv+"="+tp)
+}
+
+// spanParent is the trace and span id of a remote parent span.
+type spanParent struct {
+	traceID [16]byte
+	spanID  [8]byte
+}
+
+func (p spanParent) valid() bool {
+	return p.traceID != [16]byte{} && p.spanID != [8]byte{}
+}
+
+// parseTraceparent parses a W3C traceparent header,
+// version-traceid-parentid-flags in lowercase hex. Versions after 00 may
+// append fields, which are ignored; version ff and all-zero ids are invalid.
+func parseTraceparent(s string) (spanParent, bool) {
+	var p spanParent
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return p, false
+	}
+	if parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) || strings.ToLower(s) != s {
+		return p, false
+	}
+	if _, err := hex.DecodeString(parts[0] + parts[3]); err != nil {
+		return p, false
+	}
+	if _, err := hex.Decode(p.traceID[:], []byte(parts[1])); err != nil {
+		return p, false
+	}
+	if _, err := hex.Decode(p.spanID[:], []byte(parts[2])); err != nil {
+		return p, false
+	}
+	return p, p.valid()
+}
+
+// Add adds n to the monotonic counter name for the given attributes. unit
+// follows the UCUM convention OTLP uses, e.g. "{token}".
+func Add(name, unit string, n int64, attrs ...Attr) {
+	if p := global.Load(); p != nil && n > 0 {
+		p.add(name, unit, n, attrs)
+	}
+}
+
+// counter is the running total of one counter for one attribute set.
+type counter struct {
+	name  string
+	unit  string
+	attrs []Attr
+	value int64
+}
+
+// counterKey identifies a counter's attribute set: its name and its
+// attributes sorted by key.
+func counterKey(name string, attrs []Attr) (string, []Attr) {
+	sorted := append([]Attr(nil), attrs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, a := range sorted {
+		sb.WriteString("\x00" + a.Key + "=")
+		sb.WriteString(attrString(a.Value))
+	}
+	return sb.String(), sorted
+}
@@ -0,0 +1,143 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// useProvider makes p the global provider for the test.
+func useProvider(t *testing.T, p *Provider) {
+	t.Helper()
+	SetProvider(p)
+	t.Cleanup(func() { SetProvider(nil) })
+}
+
+func TestParseTraceparent(t *testing.T) {
+	cases := []struct {
+		name   string
+		in     string
+		ok     bool
+		trace  string
+		parent string
+	}{
+		{"valid", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", true, "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331"},
+		{"not sampled", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00", true, "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331"},
+		{"later version with extra field", "01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra", true, "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331"},
+		{"version 00 with extra field", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra", false, "", ""},
+		{"version ff", "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", false, "", ""},
+		{"uppercase", "00-0AF7651916CD43DD8448EB211C80319C-B7AD6B7169203331-01", false, "", ""},
+		{"zero trace id", "00-00000000000000000000000000000000-b7ad6b7169203331-01", false, "", ""},
+		{"zero span id", "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01", false, "", ""},
+		{"short trace id", "00-0af7651916cd43dd8448eb211c8031-b7ad6b7169203331-01", false, "", ""},
+		{"not hex", "00-0af7651916cd43dd8448eb211c80319z-b7ad6b7169203331-01", false, "", ""},
+		{"bad flags", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-0g", false, "", ""},
+		{"missing flags", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331", false, "", ""},
+		{"empty", "", false, "", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := parseTraceparent(c.in)
+			if ok != c.ok {
+				t.Fatalf("parseTraceparent(%q) ok = %v, want %v", c.in, ok, c.ok)
+			}
+			if !ok {
+				return
+			}
+			if trace := hex.EncodeToString(got.traceID[:]); trace != c.trace {
+				t.Errorf("trace id = %s, want %s", trace, c.trace)
+			}
+			if parent := hex.EncodeToString(got.spanID[:]); parent != c.parent {
+				t.Errorf("span id = %s, want %s", parent, c.parent)
+			}
+		})
+	}
+}
+
+// TestStartParents checks that a root span joins the trace docgen was
+// started in, and that a child joins its parent's.
+func TestStartParents(t *testing.T) {
+	remote, _ := parseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	p := testProvider()
+	p.parent = remote
+	useProvider(t, p)
+
+	ctx, root := Start(context.Background(), "root")
+	if root.traceID != remote.traceID || root.parentID != remote.spanID {
+		t.Errorf("root span = %s, want a child of the remote parent", root.Traceparent())
+	}
+	_, child := StartKind(ctx, "child", KindClient)
+	if child.traceID != root.traceID || child.parentID != root.spanID {
+		t.Errorf("child span = %s, want a child of %s", child.Traceparent(), root.Traceparent())
+	}
+
+	p.parent = spanParent{}
+	_, other := Start(context.Background(), "other")
+	if other.traceID == remote.traceID || other.parentID != [8]byte{} {
+		t.Errorf("root span without a remote parent = %s, want a new trace", other.Traceparent())
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	useProvider(t, testProvider())
+	ctx, span := Start(context.Background(), "cx generate")
+	want := "TRACEPARENT=" + span.Traceparent()
+
+	got := Environ(ctx, []string{"HOME=/home/me", "TRACEPARENT=00-inherited-01", "PATH=/bin"})
+	if w := []string{"HOME=/home/me", "PATH=/bin", want}; !reflect.DeepEqual(got, w) {
+		t.Errorf("Environ = %q, want %q", got, w)
+	}
+
+	t.Setenv("DOCGEN_TEST_ENVIRON", "1")
+	got = Environ(ctx, nil)
+	if !contains(got, "DOCGEN_TEST_ENVIRON=1") || !contains(got, want) {
+		t.Errorf("Environ(nil) = %q, want the process environment and %s", got, want)
+	}
+
+	env := []string{"TRACEPARENT=00-inherited-01"}
+	if got := Environ(context.Background(), env); !reflect.DeepEqual(got, env) {
+		t.Errorf("Environ without a span = %q, want %q", got, env)
+	}
+	if got := Environ(context.Background(), nil); got != nil {
+		t.Errorf("Environ(nil) without a span = %q, want nil", got)
+	}
+}
+
+func contains(env []string, kv string) bool {
+	for _, e := range env {
+		if e == kv {
+			return true
+		}
+	}
+	return false
+}
+
+// TestDisabled checks that everything is a no-op when telemetry is off.
+func TestDisabled(t *testing.T) {
+	useProvider(t, nil)
+	ctx, span := Start(context.Background(), "docgen generate")
+	if span != nil {
+		t.Fatalf("Start = %v, want a nil span", span)
+	}
+	if SpanFromContext(ctx) != nil {
+		t.Error("context carries a span")
+	}
+	span.SetAttributes(String("k", "v"))
+	span.RecordError(errors.New("failed"))
+	span.SetOK()
+	span.End()
+	if tp := span.Traceparent(); tp != "" {
+		t.Errorf("Traceparent = %q, want empty", tp)
+	}
+	Add(MetricLLMRequests, "{request}", 1)
+
+	var p *Provider
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown = %v", err)
+	}
+	if w := p.Warnings(); w != nil {
+		t.Errorf("Warnings = %q", w)
+	}
+}
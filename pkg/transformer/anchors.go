@@ -0,0 +1,83 @@
+package transformer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	anchorLinkRegex = regexp.MustCompile(`\]\(#([^)\s]+)\)|href="#([^"]+)"`)
+	htmlIDRegex     = regexp.MustCompile(`<[a-zA-Z][^>]*\sid="([^"]+)"`)
+)
+
+// Anchors returns every anchor a document can be deep-linked to, in order:
+// the slugs of its headings, then the ids of HTML elements in it.
+func Anchors(content []byte) []string {
+	var anchors []string
+	seen := make(map[string]bool)
+	add := func(a string) {
+		if !seen[a] {
+			seen[a] = true
+			anchors = append(anchors, a)
+		}
+	}
+	for _, h := range ExtractHeadings(content) {
+		add(h.Slug)
+	}
+	for _, line := range proseLines(stripFrontmatterBlock(string(content))) {
+		for _, m := range htmlIDRegex.FindAllStringSubmatch(inlineCodeRegex.ReplaceAllString(line, ""), -1) {
+			add(m[1])
+		}
+	}
+	return anchors
+}
+
+// BrokenAnchorLinks returns the in-page links of a document, [text](#anchor)
+// and href="#anchor", whose anchor is not in Anchors, sorted. Links in code
+// are ignored.
+func BrokenAnchorLinks(content []byte) []string {
+	valid := make(map[string]bool)
+	for _, a := range Anchors(content) {
+		valid[a] = true
+	}
+	broken := make(map[string]bool)
+	for _, line := range proseLines(stripFrontmatterBlock(string(content))) {
+		for _, m := range anchorLinkRegex.FindAllStringSubmatch(inlineCodeRegex.ReplaceAllString(line, ""), -1) {
+			anchor := m[1] + m[2]
+			if !valid[anchor] {
+				broken["#"+anchor] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(broken))
+	for a := range broken {
+		result = append(result, a)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// rewriteHeadingIDs turns explicit heading ids, which Astro's markdown does
+// not understand, into an anchor element before the heading:
+// "## Install {#setup}" becomes `<a id="setup"></a>` and "## Install".
+func rewriteHeadingIDs(content string) string {
+	if !strings.Contains(content, "{#") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	var fences fenceTracker
+	for _, line := range lines {
+		if !fences.code(line) && atxHeadingRegex.MatchString(line) {
+			trimmed := strings.TrimRight(line, " \t#")
+			if id := headingIDRegex.FindStringSubmatch(trimmed); id != nil {
+				out = append(out, fmt.Sprintf(`<a id="%s"></a>`, id[1]), "")
+				line = strings.TrimSuffix(trimmed, id[0])
+			}
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -18,11 +18,31 @@ type TransformOptions struct {
 	Version     string
 	Category    string
 	Order       int
+	Image       string // Social card URL, written as og:image and twitter:image
+	Placeholder bool   // The page stands in for a doc not generated yet
+
+	// Build metadata written as a docgen: block, and optionally meta tags
+	Metadata *BuildMetadata
 
 	// For website sections (overview, concepts)
 	SectionName string
+
+	// Asset directories whose relative links are rewritten; nil means
+	// images, asciicasts, videos and downloads
+	AssetDirs []string
+
+	// Components the code blocks of mapped languages are replaced with,
+	// which makes the doc MDX
+	Components []Component
+
+	// The doc is MDX: its comments are rewritten and the components it
+	// uses imported (see PrepareMDX)
+	MDX bool
 }
 
+// defaultAssetDirs are the asset directories every package can use.
+var defaultAssetDirs = []string{"images", "asciicasts", "videos", DownloadsDir}
+
 // AstroTransformer handles content transformations for Astro
 type AstroTransformer struct{}
 
@@ -34,12 +54,23 @@ func NewAstroTransformer() *AstroTransformer {
 // TransformStandardDoc applies transformations for standard package documentation:
 // - Rewrites relative asset paths to absolute /docs/{pkg}/... paths
 // - Replaces any existing frontmatter with a new one
+// - Turns explicit heading ids ({#id}) into anchors
+// - Rewrites callouts as Starlight asides
+// - Canonicalizes code fence titles and line highlights for Expressive Code
+// - Replaces the code blocks of mapped languages with their components
+// - Imports the components an MDX doc uses
 func (t *AstroTransformer) TransformStandardDoc(content []byte, opts TransformOptions) []byte {
 	s := string(content)
+	// The frontmatter is replaced, so read its components first
+	declared := FrontmatterComponents(s)
 	baseURL := fmt.Sprintf("/docs/%s", opts.PackageName)
 
-	s = t.rewritePaths(s, baseURL)
+	s = t.rewritePaths(s, baseURL, opts.AssetDirs)
+	s = rewriteHeadingIDs(s)
+	s = NormalizeCallouts(s, CalloutStarlight)
+	s = NormalizeCodeFences(s)
 	s = t.ensureFrontmatter(s, opts)
+	s = toMDX(s, declared, opts)
 
 	return []byte(s)
 }
@@ -47,40 +78,89 @@ func (t *AstroTransformer) TransformStandardDoc(content []byte, opts TransformOp
 // TransformWebsiteSection applies transformations for website sections (overview, concepts):
 // - Rewrites relative asset paths to absolute /docs/{section}/... paths
 // - Augments existing frontmatter (preserves manual fields) with category and package
+// - Turns explicit heading ids ({#id}) into anchors
+// - Rewrites callouts as Starlight asides
+// - Canonicalizes code fence titles and line highlights for Expressive Code
+// - Replaces the code blocks of mapped languages with their components
+// - Imports the components an MDX doc uses
 func (t *AstroTransformer) TransformWebsiteSection(content []byte, opts TransformOptions) []byte {
 	s := string(content)
 	// For sections like "overview", the base URL is /docs/overview
 	baseURL := fmt.Sprintf("/docs/%s", opts.SectionName)
 
-	s = t.rewritePaths(s, baseURL)
+	s = t.rewritePaths(s, baseURL, opts.AssetDirs)
+	s = rewriteHeadingIDs(s)
+	s = NormalizeCallouts(s, CalloutStarlight)
+	s = NormalizeCodeFences(s)
 	s = t.augmentFrontmatter(s, opts)
+	s = toMDX(s, FrontmatterComponents(s), opts)
 
 	return []byte(s)
 }
 
-// rewritePaths rewrites all relative asset paths to absolute website paths
-func (t *AstroTransformer) rewritePaths(content, baseURL string) string {
-	// 1. Rewrite markdown image syntax: ![alt](./images/file.ext)
-	imageRegex := regexp.MustCompile(`!\[([^\]]*)\]\(\./images/([^)]+)\)`)
-	content = imageRegex.ReplaceAllString(content, fmt.Sprintf("![$1](%s/images/$2)", baseURL))
+// toMDX finishes an MDX doc, and turns a markdown doc using mapped
+// components into one, escaping what MDX would read as syntax in it.
+func toMDX(s string, declared []string, opts TransformOptions) string {
+	if !opts.MDX {
+		if !UsesComponents(s, opts.Components) {
+			return s
+		}
+		s = EscapeMDX(s, nil)
+	}
+	s, _ = ApplyComponents(s, opts.Components)
+	return PrepareMDX(s, declared, opts.Components)
+}
 
-	// 2. Rewrite HTML img tags: <img src="./images/file.ext" ...>
-	htmlImgRegex := regexp.MustCompile(`<img\s+([^>]*\s)?src="\./images/([^"]+)"([^>]*)>`)
-	content = htmlImgRegex.ReplaceAllString(content, fmt.Sprintf(`<img $1src="%s/images/$2"$3>`, baseURL))
+// rewritePaths rewrites all relative paths into the asset directories to
+// absolute website paths
+func (t *AstroTransformer) rewritePaths(content, baseURL string, assetDirs []string) string {
+	if assetDirs == nil {
+		assetDirs = defaultAssetDirs
+	}
+	for _, dir := range assetDirs {
+		d := regexp.QuoteMeta(dir)
 
-	// 3. Rewrite asciinema blocks' src paths: "src": "./asciicasts/file.cast"
-	asciiRegex := regexp.MustCompile(`("src":\s*")(\./asciicasts/)([^"]+)(")`)
-	content = asciiRegex.ReplaceAllString(content, fmt.Sprintf("${1}%s/asciicasts/$3$4", baseURL))
+		// 1. Markdown images and links: ![alt](./images/file.ext), [file](./downloads/file.pdf)
+		linkRegex := regexp.MustCompile(`(\]\()\./` + d + `/([^)]+\))`)
+		content = linkRegex.ReplaceAllString(content, fmt.Sprintf("${1}%s/%s/$2", baseURL, dir))
 
-	// 4. Rewrite video paths (markdown image syntax): ![alt](./videos/file.mp4)
-	videoRegex := regexp.MustCompile(`!\[([^\]]*)\]\(\./videos/([^)]+)\)`)
-	content = videoRegex.ReplaceAllString(content, fmt.Sprintf("![$1](%s/videos/$2)", baseURL))
+		// 2. HTML attributes: <img src="./images/file.ext">, <a href="./downloads/file.pdf">
+		htmlRegex := regexp.MustCompile(`(<[a-zA-Z][^>]*\s(?:src|href|poster)=")\./` + d + `/`)
+		for htmlRegex.MatchString(content) {
+			content = htmlRegex.ReplaceAllString(content, fmt.Sprintf("${1}%s/%s/", baseURL, dir))
+		}
 
+		// 3. JSON src fields, e.g. asciinema blocks: "src": "./asciicasts/file.cast"
+		srcRegex := regexp.MustCompile(`("src":\s*")\./` + d + `/`)
+		content = srcRegex.ReplaceAllString(content, fmt.Sprintf("${1}%s/%s/", baseURL, dir))
+	}
 	return content
 }
 
+// socialImageHead returns the Starlight head entries that point share
+// previews at image, without the head: key, or "" when there is no image.
+func socialImageHead(image string) string {
+	if image == "" {
+		return ""
+	}
+	var sb strings.Builder
+	for _, attr := range []string{"property: og:image", "name: twitter:image"} {
+		fmt.Fprintf(&sb, "  - tag: meta\n    attrs:\n      %s\n      content: \"%s\"\n", attr, escapeYAMLString(image))
+	}
+	sb.WriteString("  - tag: meta\n    attrs:\n      name: twitter:card\n      content: summary_large_image\n")
+	return sb.String()
+}
+
 // ensureFrontmatter replaces any existing frontmatter with a new one for package docs
 func (t *AstroTransformer) ensureFrontmatter(content string, opts TransformOptions) string {
+	placeholder := ""
+	if opts.Placeholder {
+		placeholder = "placeholder: true\n"
+	}
+	head := socialImageHead(opts.Image) + opts.Metadata.headEntries()
+	if head != "" {
+		head = "head:\n" + head
+	}
 	frontmatter := fmt.Sprintf(`---
 title: "%s"
 description: "%s"
@@ -88,9 +168,9 @@ package: "%s"
 version: "%s"
 category: "%s"
 order: %d
----
+%s%s%s---
 
-`, escapeYAMLString(opts.Title), escapeYAMLString(opts.Description), escapeYAMLString(opts.PackageName), opts.Version, opts.Category, opts.Order)
+`, escapeYAMLString(opts.Title), escapeYAMLString(opts.Description), escapeYAMLString(opts.PackageName), opts.Version, opts.Category, opts.Order, placeholder, opts.Metadata.frontmatter(), head)
 
 	// Remove existing frontmatter if present
 	if strings.HasPrefix(content, "---\n") {
@@ -104,7 +184,8 @@ order: %d
 
 // augmentFrontmatter merges additional fields into existing frontmatter for website sections.
 // If no frontmatter exists, it creates new frontmatter.
-// Existing fields are preserved; only category and package are added if missing.
+// Existing fields are preserved; only category, package and the build
+// metadata are added if missing.
 func (t *AstroTransformer) augmentFrontmatter(content string, opts TransformOptions) string {
 	// Map section names to sidebar category names
 	category := opts.Category
@@ -119,9 +200,14 @@ func (t *AstroTransformer) augmentFrontmatter(content string, opts TransformOpti
 		}
 	}
 
+	metadata := opts.Metadata.frontmatter()
+	if head := opts.Metadata.headEntries(); head != "" {
+		metadata += "head:\n" + head
+	}
+
 	if !strings.HasPrefix(content, "---\n") {
 		// No frontmatter, create new
-		newFrontmatter := fmt.Sprintf("---\ncategory: \"%s\"\npackage: \"Grove Ecosystem\"\n---\n\n", category)
+		newFrontmatter := fmt.Sprintf("---\ncategory: \"%s\"\npackage: \"Grove Ecosystem\"\n%s---\n\n", category, metadata)
 		return newFrontmatter + content
 	}
 
@@ -146,6 +232,10 @@ func (t *AstroTransformer) augmentFrontmatter(content string, opts TransformOpti
 	if !hasPackage {
 		newFields = append(newFields, "package: \"Grove Ecosystem\"")
 	}
+	// Manual docgen: and head: fields win over the build metadata
+	if metadata != "" && !hasFrontmatterKey(existingFrontmatter, "docgen") && !hasFrontmatterKey(existingFrontmatter, "head") {
+		newFields = append(newFields, strings.TrimSuffix(metadata, "\n"))
+	}
 
 	// If no new fields needed, return as-is
 	if len(newFields) == 0 {
@@ -0,0 +1,174 @@
+package transformer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CalloutStyle is the callout (admonition) syntax of an output target.
+type CalloutStyle string
+
+// Callout styles.
+const (
+	// CalloutStarlight renders Starlight asides: :::note, :::tip, :::caution
+	// and :::danger.
+	CalloutStarlight CalloutStyle = "starlight"
+	// CalloutDocusaurus renders Docusaurus admonitions: :::note, :::tip,
+	// :::info, :::warning and :::danger.
+	CalloutDocusaurus CalloutStyle = "docusaurus"
+	// CalloutGitHub renders a plain blockquote with a bold label, readable
+	// on GitHub and anywhere else markdown is rendered.
+	CalloutGitHub CalloutStyle = "github"
+)
+
+// calloutTypes maps each callout type docgen accepts to its name in each
+// style. The accepted types are the union of GitHub's alerts and the
+// directive names of Starlight and Docusaurus.
+var calloutTypes = map[string]map[CalloutStyle]string{
+	"note":      {CalloutStarlight: "note", CalloutDocusaurus: "note", CalloutGitHub: "Note"},
+	"info":      {CalloutStarlight: "note", CalloutDocusaurus: "info", CalloutGitHub: "Note"},
+	"tip":       {CalloutStarlight: "tip", CalloutDocusaurus: "tip", CalloutGitHub: "Tip"},
+	"important": {CalloutStarlight: "note", CalloutDocusaurus: "info", CalloutGitHub: "Important"},
+	"warning":   {CalloutStarlight: "caution", CalloutDocusaurus: "warning", CalloutGitHub: "Warning"},
+	"caution":   {CalloutStarlight: "caution", CalloutDocusaurus: "warning", CalloutGitHub: "Caution"},
+	"danger":    {CalloutStarlight: "danger", CalloutDocusaurus: "danger", CalloutGitHub: "Danger"},
+}
+
+var (
+	alertOpenRegex     = regexp.MustCompile(`^>[ \t]*\[!([A-Za-z]+)\][ \t]*(.*)$`)
+	directiveOpenRegex = regexp.MustCompile(`^:::[ \t]*([A-Za-z]+)(?:\[([^\]]*)\]|[ \t]+(.+))?[ \t]*$`)
+)
+
+const directiveCloseLine = ":::"
+
+// callout is one parsed callout.
+type callout struct {
+	kind  string // Key of calloutTypes
+	title string
+	body  []string
+}
+
+// NormalizeCallouts rewrites the callouts of a markdown document into the
+// native syntax of style. Two source syntaxes are recognized, whatever the
+// target: GitHub alerts ("> [!NOTE]" followed by quoted lines) and
+// directives (":::tip[Title]" or ":::tip Title" up to a closing ":::").
+// Callouts of unknown types and anything inside fenced code are left as is.
+func NormalizeCallouts(content string, style CalloutStyle) string {
+	if !strings.Contains(content, "[!") && !strings.Contains(content, ":::") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	var fences fenceTracker
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if fences.code(line) {
+			out = append(out, line)
+			continue
+		}
+		c, next, ok := parseCallout(lines, i)
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+		c.body = strings.Split(NormalizeCallouts(strings.Join(c.body, "\n"), style), "\n")
+		// Blank lines around the callout keep a following paragraph from
+		// continuing a blockquote, and the directive from joining a paragraph
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "" {
+			out = append(out, "")
+		}
+		out = append(out, renderCallout(c, style)...)
+		if next < len(lines) && strings.TrimSpace(lines[next]) != "" {
+			out = append(out, "")
+		}
+		i = next - 1
+	}
+	return strings.Join(out, "\n")
+}
+
+// parseCallout parses a callout starting at lines[i] and returns it with the
+// index of the line after it.
+func parseCallout(lines []string, i int) (callout, int, bool) {
+	if m := alertOpenRegex.FindStringSubmatch(lines[i]); m != nil {
+		kind := strings.ToLower(m[1])
+		if _, ok := calloutTypes[kind]; !ok {
+			return callout{}, 0, false
+		}
+		c := callout{kind: kind, title: strings.TrimSpace(m[2])}
+		j := i + 1
+		for ; j < len(lines) && strings.HasPrefix(lines[j], ">"); j++ {
+			body := strings.TrimPrefix(lines[j], ">")
+			c.body = append(c.body, strings.TrimPrefix(body, " "))
+		}
+		return c, j, true
+	}
+
+	m := directiveOpenRegex.FindStringSubmatch(lines[i])
+	if m == nil {
+		return callout{}, 0, false
+	}
+	kind := strings.ToLower(m[1])
+	if _, ok := calloutTypes[kind]; !ok {
+		return callout{}, 0, false
+	}
+	c := callout{kind: kind, title: strings.TrimSpace(m[2] + m[3])}
+	depth := 1
+	var fences fenceTracker
+	for j := i + 1; j < len(lines); j++ {
+		if !fences.code(lines[j]) {
+			switch trimmed := strings.TrimSpace(lines[j]); {
+			case trimmed == directiveCloseLine:
+				depth--
+			case directiveOpenRegex.MatchString(trimmed):
+				depth++
+			}
+			if depth == 0 {
+				return c, j + 1, true
+			}
+		}
+		c.body = append(c.body, lines[j])
+	}
+	return callout{}, 0, false // Unclosed: leave it to the renderer
+}
+
+// renderCallout renders a callout in style.
+func renderCallout(c callout, style CalloutStyle) []string {
+	name := calloutTypes[c.kind][style]
+	body := trimBlankLines(c.body)
+	if style == CalloutGitHub {
+		label := name
+		if c.title != "" {
+			label = c.title
+		}
+		out := []string{fmt.Sprintf("> **%s**", label)}
+		if len(body) > 0 {
+			out = append(out, ">")
+		}
+		for _, line := range body {
+			if strings.TrimSpace(line) == "" {
+				out = append(out, ">")
+			} else {
+				out = append(out, "> "+line)
+			}
+		}
+		return out
+	}
+	opener := ":::" + name
+	if c.title != "" {
+		opener += "[" + c.title + "]"
+	}
+	out := append([]string{opener}, body...)
+	return append(out, directiveCloseLine)
+}
+
+// trimBlankLines drops leading and trailing blank lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,74 @@
+package transformer
+
+import "testing"
+
+func TestNormalizeCallouts(t *testing.T) {
+	cases := []struct {
+		name  string
+		in    string
+		style CalloutStyle
+		want  string
+	}{
+		{
+			name:  "alert to starlight",
+			in:    "Intro\n> [!WARNING]\n> Back up first.\nAfter",
+			style: CalloutStarlight,
+			want:  "Intro\n\n:::caution\nBack up first.\n:::\n\nAfter",
+		},
+		{
+			name:  "alert to docusaurus",
+			in:    "> [!IMPORTANT] Heads up\n> Read this.",
+			style: CalloutDocusaurus,
+			want:  ":::info[Heads up]\nRead this.\n:::",
+		},
+		{
+			name:  "directive with bracket title to github",
+			in:    ":::tip[Faster builds]\nUse the cache.\n\nIt helps.\n:::",
+			style: CalloutGitHub,
+			want:  "> **Faster builds**\n>\n> Use the cache.\n>\n> It helps.",
+		},
+		{
+			name:  "directive with space title to starlight",
+			in:    ":::danger Data loss\nThis deletes everything.\n:::",
+			style: CalloutStarlight,
+			want:  ":::danger[Data loss]\nThis deletes everything.\n:::",
+		},
+		{
+			name:  "untitled directive to github",
+			in:    ":::info\nSee the FAQ.\n:::",
+			style: CalloutGitHub,
+			want:  "> **Note**\n>\n> See the FAQ.",
+		},
+		{
+			name:  "nested directive",
+			in:    ":::note\nOuter\n:::warning\nInner\n:::\n:::",
+			style: CalloutDocusaurus,
+			want:  ":::note\nOuter\n\n:::warning\nInner\n:::\n:::",
+		},
+		{
+			name:  "unknown type left alone",
+			in:    "> [!FOO]\n> Body\n\n:::details\nBody\n:::",
+			style: CalloutStarlight,
+			want:  "> [!FOO]\n> Body\n\n:::details\nBody\n:::",
+		},
+		{
+			name:  "unclosed directive left alone",
+			in:    ":::tip\nNo end",
+			style: CalloutGitHub,
+			want:  ":::tip\nNo end",
+		},
+		{
+			name:  "fenced code left alone",
+			in:    "```md\n> [!NOTE]\n> Example\n```",
+			style: CalloutStarlight,
+			want:  "```md\n> [!NOTE]\n> Example\n```",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := NormalizeCallouts(c.in, c.style); got != c.want {
+				t.Errorf("NormalizeCallouts(%q, %s) =\n%s\nwant\n%s", c.in, c.style, got, c.want)
+			}
+		})
+	}
+}
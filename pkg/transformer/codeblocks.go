@@ -0,0 +1,247 @@
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FenceInfo is the parsed info string of a fenced code block, e.g.
+// `go title="main.go" {3-5}`.
+type FenceInfo struct {
+	Lang      string
+	Title     string
+	Highlight string // Line ranges, e.g. "1,3-5"
+	Meta      string // Anything else, kept for the renderer
+}
+
+var (
+	fenceLineRegex   = regexp.MustCompile("^([ \t]{0,3})(```+|~~~+)[ \t]*(.*)$")
+	titleAttrRegex   = regexp.MustCompile(`(?:^|\s)(?:title|file|filename)=(?:"([^"]*)"|'([^']*)'|(\S+))`)
+	hlLinesAttrRegex = regexp.MustCompile(`(?:^|\s)hl_lines=(?:"([^"]*)"|'([^']*)')`)
+	highlightRegex   = regexp.MustCompile(`(?:^|\s)\{([\d,\s-]+)\}`)
+
+	shellCommandRegex = regexp.MustCompile(`^(\$ |(sudo|cd|go|git|npm|npx|yarn|pnpm|make|docker|kubectl|curl|wget|brew|pip|export|grove|docgen|cx|flow|nb|tmux)( |$))`)
+	goRegex           = regexp.MustCompile(`(?m)^(package \w+|func |import \(|type \w+ (struct|interface) \{)`)
+	pythonRegex       = regexp.MustCompile(`(?m)^(def \w+\(|class \w+(\(.*\))?:|from [\w.]+ import |import \w+$)`)
+	rustRegex         = regexp.MustCompile(`(?m)^(fn \w+|use \w+::|let mut |impl )`)
+	jsRegex           = regexp.MustCompile(`(?m)^(const |let |function |export |import .* from |console\.)`)
+	sqlRegex          = regexp.MustCompile(`(?i)^\s*(select .* from|insert into|create table|update \w+ set)`)
+	yamlLineRegex     = regexp.MustCompile(`^\s*(- )?[\w.\-"']+:(\s|$)|^\s*- `)
+	tomlSectionRegex  = regexp.MustCompile(`(?m)^\[[\w.\-]+\]$`)
+	tomlLineRegex     = regexp.MustCompile(`^[\w.\-]+\s*=\s*\S`)
+)
+
+// ParseFenceInfo parses a code fence's info string. Besides the canonical
+// `lang title="file" {1,3-5}`, it accepts the variants models and other
+// tools write: `lang:file`, `file=...`, `filename=...` and `hl_lines="1 3"`.
+func ParseFenceInfo(info string) FenceInfo {
+	var fi FenceInfo
+	rest := strings.TrimSpace(info)
+	if m := titleAttrRegex.FindStringSubmatch(rest); m != nil {
+		fi.Title = m[1] + m[2] + m[3]
+		rest = strings.Replace(rest, m[0], " ", 1)
+	}
+	if m := hlLinesAttrRegex.FindStringSubmatch(rest); m != nil {
+		fi.Highlight = strings.Join(strings.Fields(m[1]+m[2]), ",")
+		rest = strings.Replace(rest, m[0], " ", 1)
+	}
+	if m := highlightRegex.FindStringSubmatch(rest); m != nil {
+		fi.Highlight = strings.ReplaceAll(m[1], " ", "")
+		rest = strings.Replace(rest, m[0], " ", 1)
+	}
+	fields := strings.Fields(rest)
+	if len(fields) > 0 && !strings.Contains(fields[0], "=") {
+		fi.Lang = fields[0]
+		fields = fields[1:]
+		if lang, file, ok := strings.Cut(fi.Lang, ":"); ok && fi.Title == "" {
+			fi.Lang, fi.Title = lang, file
+		}
+	}
+	fi.Meta = strings.Join(fields, " ")
+	return fi
+}
+
+// String renders the info string in canonical form.
+func (fi FenceInfo) String() string {
+	parts := []string{}
+	if fi.Lang != "" {
+		parts = append(parts, fi.Lang)
+	}
+	if fi.Title != "" {
+		parts = append(parts, fmt.Sprintf("title=%q", fi.Title))
+	}
+	if fi.Highlight != "" {
+		parts = append(parts, "{"+fi.Highlight+"}")
+	}
+	if fi.Meta != "" {
+		parts = append(parts, fi.Meta)
+	}
+	return strings.Join(parts, " ")
+}
+
+// HighlightedLines expands Highlight into 1-based line numbers, sorted.
+func (fi FenceInfo) HighlightedLines() []int {
+	seen := make(map[int]bool)
+	for _, r := range strings.Split(fi.Highlight, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(r), "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil || end < start {
+				continue
+			}
+		}
+		for n := start; n <= end; n++ {
+			seen[n] = true
+		}
+	}
+	lines := make([]int, 0, len(seen))
+	for n := range seen {
+		lines = append(lines, n)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// codeBlock is a fenced code block found by rewriteCodeBlocks.
+type codeBlock struct {
+	open   string // Opening line as written
+	close  string // Closing line as written
+	indent string
+	fence  string
+	info   FenceInfo
+	code   []string
+}
+
+// lines returns the block as written.
+func (b codeBlock) lines() []string {
+	return append(append([]string{b.open}, b.code...), b.close)
+}
+
+// rewriteCodeBlocks calls rewrite for each fenced code block of a document
+// and replaces the block's lines with what it returns. Unclosed blocks are
+// left alone.
+func rewriteCodeBlocks(content string, rewrite func(codeBlock) []string) string {
+	if !strings.Contains(content, "```") && !strings.Contains(content, "~~~") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		m := fenceLineRegex.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, lines[i])
+			continue
+		}
+		block := codeBlock{open: lines[i], indent: m[1], fence: m[2], info: ParseFenceInfo(m[3])}
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(trimmed, block.fence) && strings.Trim(trimmed, block.fence[:1]) == "" {
+				end = j
+				block.close = lines[j]
+				break
+			}
+			block.code = append(block.code, lines[j])
+		}
+		if end == -1 {
+			out = append(out, lines[i:]...)
+			break
+		}
+		out = append(out, rewrite(block)...)
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
+
+// NormalizeCodeFences rewrites code fence info strings into the canonical
+// `lang title="file" {ranges}` form that Starlight's Expressive Code renders
+// as a titled frame with highlighted lines.
+func NormalizeCodeFences(content string) string {
+	return rewriteCodeBlocks(content, func(b codeBlock) []string {
+		return append(append([]string{b.indent + b.fence + b.info.String()}, b.code...), b.close)
+	})
+}
+
+// SimplifyCodeFences reduces code fences to their language for renderers
+// that know nothing else, such as GitHub's: a title becomes a bold line
+// above the block, and line highlights are dropped.
+func SimplifyCodeFences(content string) string {
+	return rewriteCodeBlocks(content, func(b codeBlock) []string {
+		var out []string
+		if b.info.Title != "" {
+			out = append(out, b.indent+"**"+b.info.Title+"**", "")
+		}
+		out = append(out, b.indent+b.fence+b.info.Lang)
+		return append(append(out, b.code...), b.close)
+	})
+}
+
+// InferCodeLanguages gives fenced code blocks without a language the one
+// their content most likely is (shell, go, json, yaml, toml, python, rust,
+// javascript, sql, diff), so generated docs get syntax highlighting.
+// Blocks that match nothing are left bare.
+func InferCodeLanguages(content string) string {
+	return rewriteCodeBlocks(content, func(b codeBlock) []string {
+		if b.info.Lang != "" {
+			return append(append([]string{b.open}, b.code...), b.close)
+		}
+		b.info.Lang = InferLanguage(strings.Join(b.code, "\n"))
+		return append(append([]string{b.indent + b.fence + b.info.String()}, b.code...), b.close)
+	})
+}
+
+// InferLanguage guesses the language of a code snippet, or returns "" when
+// nothing matches.
+func InferLanguage(code string) string {
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" {
+		return ""
+	}
+	first := strings.SplitN(trimmed, "\n", 2)[0]
+	switch {
+	case (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)):
+		return "json"
+	case strings.HasPrefix(first, "diff --git") || strings.HasPrefix(first, "--- ") || strings.HasPrefix(first, "@@ "):
+		return "diff"
+	case shellCommandRegex.MatchString(first):
+		return "bash"
+	case goRegex.MatchString(trimmed):
+		return "go"
+	case rustRegex.MatchString(trimmed):
+		return "rust"
+	case pythonRegex.MatchString(trimmed):
+		return "python"
+	case jsRegex.MatchString(trimmed):
+		return "javascript"
+	case sqlRegex.MatchString(trimmed):
+		return "sql"
+	case tomlSectionRegex.MatchString(trimmed) && allLines(trimmed, func(l string) bool {
+		return tomlSectionRegex.MatchString(l) || tomlLineRegex.MatchString(l)
+	}):
+		return "toml"
+	case allLines(trimmed, yamlLineRegex.MatchString):
+		return "yaml"
+	}
+	return ""
+}
+
+// allLines reports whether every non-blank, non-comment line satisfies ok.
+func allLines(code string, ok func(string) bool) bool {
+	for _, line := range strings.Split(code, "\n") {
+		if t := strings.TrimSpace(line); t == "" || strings.HasPrefix(t, "#") {
+			continue
+		}
+		if !ok(line) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package transformer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFenceInfo(t *testing.T) {
+	cases := []struct {
+		info string
+		want FenceInfo
+	}{
+		{"", FenceInfo{}},
+		{"go", FenceInfo{Lang: "go"}},
+		{`go title="main.go" {3-5}`, FenceInfo{Lang: "go", Title: "main.go", Highlight: "3-5"}},
+		{"go:cmd/main.go", FenceInfo{Lang: "go", Title: "cmd/main.go"}},
+		{"python filename='app.py'", FenceInfo{Lang: "python", Title: "app.py"}},
+		{"yaml file=config.yml", FenceInfo{Lang: "yaml", Title: "config.yml"}},
+		{`python hl_lines="1 3"`, FenceInfo{Lang: "python", Highlight: "1,3"}},
+		{"js {1, 4-6} showLineNumbers", FenceInfo{Lang: "js", Highlight: "1,4-6", Meta: "showLineNumbers"}},
+		{`title="notes.txt"`, FenceInfo{Title: "notes.txt"}},
+	}
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			if got := ParseFenceInfo(c.info); got != c.want {
+				t.Errorf("ParseFenceInfo(%q) = %+v, want %+v", c.info, got, c.want)
+			}
+		})
+	}
+}
+
+func TestHighlightedLines(t *testing.T) {
+	cases := map[string][]int{
+		"":          {},
+		"3":         {3},
+		"1,3-5":     {1, 3, 4, 5},
+		"4-5,2,4":   {2, 4, 5},
+		"x,5-3,7-8": {7, 8},
+	}
+	for in, want := range cases {
+		if got := (FenceInfo{Highlight: in}).HighlightedLines(); !reflect.DeepEqual(got, want) {
+			t.Errorf("HighlightedLines(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestCodeFences(t *testing.T) {
+	cases := []struct {
+		name    string
+		rewrite func(string) string
+		in      string
+		want    string
+	}{
+		{
+			name:    "normalize variants",
+			rewrite: NormalizeCodeFences,
+			in:      "```go:main.go hl_lines=\"2\"\npackage main\n```",
+			want:    "```go title=\"main.go\" {2}\npackage main\n```",
+		},
+		{
+			name:    "normalize keeps indent and tildes",
+			rewrite: NormalizeCodeFences,
+			in:      "  ~~~sh {1}\n  ls\n  ~~~",
+			want:    "  ~~~sh {1}\n  ls\n  ~~~",
+		},
+		{
+			name:    "simplify moves title above",
+			rewrite: SimplifyCodeFences,
+			in:      "```go title=\"main.go\" {2}\npackage main\n```",
+			want:    "**main.go**\n\n```go\npackage main\n```",
+		},
+		{
+			name:    "unclosed block left alone",
+			rewrite: SimplifyCodeFences,
+			in:      "```go title=\"main.go\"\npackage main",
+			want:    "```go title=\"main.go\"\npackage main",
+		},
+		{
+			name:    "infer bare fence",
+			rewrite: InferCodeLanguages,
+			in:      "```\n$ docgen generate\n```\n\n```ruby\nputs 1\n```",
+			want:    "```bash\n$ docgen generate\n```\n\n```ruby\nputs 1\n```",
+		},
+		{
+			name:    "infer keeps title",
+			rewrite: InferCodeLanguages,
+			in:      "```title=\"x.json\"\n{\"a\": 1}\n```",
+			want:    "```json title=\"x.json\"\n{\"a\": 1}\n```",
+		},
+		{
+			name:    "no match left bare",
+			rewrite: InferCodeLanguages,
+			in:      "```\nplain words\n```",
+			want:    "```\nplain words\n```",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.rewrite(c.in); got != c.want {
+				t.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestInferLanguage(t *testing.T) {
+	cases := []struct {
+		code, want string
+	}{
+		{`{"name": "docgen"}`, "json"},
+		{"[1, 2]", "json"},
+		{"diff --git a/x b/x\n+y", "diff"},
+		{"@@ -1 +1 @@\n-a\n+b", "diff"},
+		{"go build ./...", "bash"},
+		{"package main\n\nfunc main() {}", "go"},
+		{"fn main() {\n    let mut x = 1;\n}", "rust"},
+		{"def run():\n    pass", "python"},
+		{"const x = require('x')", "javascript"},
+		{"SELECT id FROM users", "sql"},
+		{"[server]\nport = 8080\n# comment", "toml"},
+		{"name: docgen\nitems:\n  - a", "yaml"},
+		{"Hello, world.", ""},
+		{"   ", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.want+" "+c.code, func(t *testing.T) {
+			if got := InferLanguage(c.code); got != c.want {
+				t.Errorf("InferLanguage(%q) = %q, want %q", c.code, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package transformer
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// How the body of a mapped code block is passed to its component.
+const (
+	ComponentBodyChildren = "children" // As markdown children (the default)
+	ComponentBodyProps    = "props"    // As props, from a JSON or YAML object
+	ComponentBodyCode     = "code"     // As a code string prop
+)
+
+// Component maps the code blocks of a fence language to an Astro component.
+type Component struct {
+	Lang   string // Fence language, e.g. "asciinema"
+	Name   string // Component name, e.g. "Asciinema"
+	Import string // Module the component is imported from (see addImports)
+	Body   string // One of the ComponentBody constants; "" is children
+}
+
+var (
+	// fenceAttrRe matches key=value and key="value" attributes of a fence
+	// info string.
+	fenceAttrRe = regexp.MustCompile(`([A-Za-z_][\w-]*)=(?:"([^"]*)"|(\S+))`)
+	propNameRe  = regexp.MustCompile(`^[A-Za-z_][\w-]*$`)
+)
+
+// UsesComponents reports whether content has a code block that one of
+// components maps, i.e. whether ApplyComponents turns it into MDX.
+func UsesComponents(content string, components []Component) bool {
+	if len(components) == 0 {
+		return false
+	}
+	used := false
+	rewriteCodeBlocks(content, func(b codeBlock) []string {
+		if findComponent(components, b.info.Lang) != nil {
+			used = true
+		}
+		return b.lines()
+	})
+	return used
+}
+
+// ApplyComponents replaces the code blocks of content whose language one of
+// components maps with the component, and imports each component used after
+// the frontmatter. The fence title and key=value attributes become props:
+//
+//	```asciinema title="Install" autoplay=true
+//	{"src": "/docs/grove/asciicasts/install.cast"}
+//	```
+//
+// with Body "props" becomes
+//
+//	<Asciinema title="Install" autoplay={true} src="/docs/grove/asciicasts/install.cast" />
+//
+// A block whose body cannot be read as props is left alone. The result is
+// MDX when a component was used, which the second return value reports.
+func ApplyComponents(content string, components []Component) (string, bool) {
+	if len(components) == 0 {
+		return content, false
+	}
+	used := make(map[string]string)
+	content = rewriteCodeBlocks(content, func(b codeBlock) []string {
+		c := findComponent(components, b.info.Lang)
+		if c == nil {
+			return b.lines()
+		}
+		markup, ok := c.render(b)
+		if !ok {
+			return b.lines()
+		}
+		used[c.Name] = c.Import
+		return markup
+	})
+	if len(used) == 0 {
+		return content, false
+	}
+
+	return addImports(content, used), true
+}
+
+func findComponent(components []Component, lang string) *Component {
+	if lang == "" {
+		return nil
+	}
+	for i := range components {
+		if components[i].Lang == lang {
+			return &components[i]
+		}
+	}
+	return nil
+}
+
+// render returns the lines of the component standing in for b, or false
+// when b's body cannot be passed as c.Body says.
+func (c *Component) render(b codeBlock) ([]string, bool) {
+	type prop struct{ name, value string }
+	var props []prop
+	if b.info.Title != "" {
+		props = append(props, prop{"title", jsxValue(b.info.Title)})
+	}
+	for _, m := range fenceAttrRe.FindAllStringSubmatch(b.info.Meta, -1) {
+		value := m[2]
+		if m[3] != "" {
+			value = jsxAttrValue(m[3])
+		} else {
+			value = jsxValue(value)
+		}
+		props = append(props, prop{m[1], value})
+	}
+
+	var children []string
+	switch c.Body {
+	case ComponentBodyProps:
+		var fields map[string]interface{}
+		if err := yaml.Unmarshal([]byte(strings.Join(b.code, "\n")), &fields); err != nil {
+			return nil, false
+		}
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			if !propNameRe.MatchString(k) {
+				return nil, false
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, err := json.Marshal(fields[k])
+			if err != nil {
+				return nil, false
+			}
+			if s, ok := fields[k].(string); ok {
+				props = append(props, prop{k, jsxValue(s)})
+			} else {
+				props = append(props, prop{k, "{" + string(value) + "}"})
+			}
+		}
+	case ComponentBodyCode:
+		props = append(props, prop{"code", "{" + jsonString(strings.Join(b.code, "\n")) + "}"})
+	default:
+		children = b.code
+	}
+
+	open := b.indent + "<" + c.Name
+	for _, p := range props {
+		open += " " + p.name + "=" + p.value
+	}
+	if len(children) == 0 {
+		return []string{open + " />"}, true
+	}
+	lines := []string{open + ">", ""}
+	lines = append(lines, children...)
+	return append(lines, "", b.indent+"</"+c.Name+">"), true
+}
+
+// jsxValue renders a string prop value.
+func jsxValue(s string) string {
+	if !strings.ContainsAny(s, "\"{}\\\n") {
+		return `"` + s + `"`
+	}
+	return "{" + jsonString(s) + "}"
+}
+
+// jsxAttrValue renders an unquoted fence attribute value: numbers and
+// booleans as expressions, anything else as a string.
+func jsxAttrValue(s string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		switch v.(type) {
+		case bool, float64:
+			return "{" + s + "}"
+		}
+	}
+	return jsxValue(s)
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// MDXPath returns the path of the MDX page published for the markdown doc
+// at path: its .md extension replaced by .mdx.
+func MDXPath(path string) string {
+	if strings.HasSuffix(path, ".md") {
+		return path + "x"
+	}
+	return path
+}
+
+// PageSibling returns the path the page at path has in the other format,
+// x.mdx for x.md and x.md for x.mdx, or "" for other files. A page
+// published in one format replaces the other's from an earlier run.
+func PageSibling(path string) string {
+	switch {
+	case strings.HasSuffix(path, ".md"):
+		return path + "x"
+	case strings.HasSuffix(path, ".mdx"):
+		return strings.TrimSuffix(path, "x")
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package transformer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Targets of conditional blocks: the writer a doc is rendered for.
+const (
+	TargetAstro    = "astro"    // The website: aggregate --transform astro and watch
+	TargetMarkdown = "markdown" // Plain aggregate output
+	TargetReadme   = "readme"   // The README written by sync-readme
+	TargetWiki     = "wiki"     // publish wiki
+	TargetEPUB     = "epub"     // export epub
+)
+
+// Conditions are what conditional blocks are evaluated against. Mode is the
+// build mode, "dev" or "prod", and Profile the output profile, "internal" or
+// "public"; outputs without them (README, wiki, EPUB) are published and
+// render as "prod" and "public".
+type Conditions struct {
+	Target  string
+	Mode    string
+	Profile string
+}
+
+var (
+	// conditionRe matches <!-- docgen:if target=astro mode=dev -->,
+	// <!-- docgen:else --> and <!-- docgen:endif --> on a line of their own.
+	conditionRe = regexp.MustCompile(`^\s*<!--\s*docgen:(if|else|endif)\b\s*(.*?)\s*-->\s*$`)
+	termRe      = regexp.MustCompile(`^(\w+)(!?=)([\w,.-]+)$`)
+)
+
+// HasConditions reports whether content has conditional blocks.
+func HasConditions(content string) bool {
+	return strings.Contains(content, "docgen:if")
+}
+
+// ApplyConditions keeps the conditional blocks of content whose condition
+// holds for c, drops the others, and removes the marker lines:
+//
+//	<!-- docgen:if target=astro -->
+//	Website-only content.
+//	<!-- docgen:else -->
+//	Fallback for every other writer.
+//	<!-- docgen:endif -->
+//
+// A condition is a space-separated list of target=, mode= and profile=
+// terms, all of which must hold. A term lists alternatives separated by commas
+// (target=readme,wiki), and != negates it. Blocks nest. Markers in code
+// blocks are left alone, so docs can show the syntax. A malformed or
+// unbalanced marker is an error, and content is then returned unchanged.
+func ApplyConditions(content string, c Conditions) (string, error) {
+	if !HasConditions(content) {
+		return content, nil
+	}
+	type block struct {
+		active  bool // The enclosing blocks are kept
+		holds   bool // The condition holds
+		hasElse bool
+		line    int
+	}
+	var stack []block
+	keep := func() bool {
+		if len(stack) == 0 {
+			return true
+		}
+		top := stack[len(stack)-1]
+		return top.active && top.holds != top.hasElse
+	}
+
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	var fence fenceTracker
+	for i, line := range lines {
+		if fence.code(line) {
+			if keep() {
+				out = append(out, line)
+			}
+			continue
+		}
+		m := conditionRe.FindStringSubmatch(line)
+		if m == nil {
+			if keep() {
+				out = append(out, line)
+			}
+			continue
+		}
+		switch m[1] {
+		case "if":
+			holds, err := c.holds(m[2])
+			if err != nil {
+				return content, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			stack = append(stack, block{active: keep(), holds: holds, line: i + 1})
+		case "else":
+			if len(stack) == 0 || stack[len(stack)-1].hasElse {
+				return content, fmt.Errorf("line %d: docgen:else without docgen:if", i+1)
+			}
+			stack[len(stack)-1].hasElse = true
+		case "endif":
+			if len(stack) == 0 {
+				return content, fmt.Errorf("line %d: docgen:endif without docgen:if", i+1)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		return content, fmt.Errorf("line %d: docgen:if without docgen:endif", stack[len(stack)-1].line)
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// holds evaluates a condition such as "target=astro mode=dev".
+func (c Conditions) holds(condition string) (bool, error) {
+	terms := strings.Fields(condition)
+	if len(terms) == 0 {
+		return false, fmt.Errorf("docgen:if without a condition")
+	}
+	holds := true
+	for _, term := range terms {
+		m := termRe.FindStringSubmatch(term)
+		if m == nil {
+			return false, fmt.Errorf("invalid condition %q", term)
+		}
+		var value string
+		switch m[1] {
+		case "target":
+			value = c.Target
+		case "mode":
+			value = c.Mode
+		case "profile":
+			value = c.Profile
+		default:
+			return false, fmt.Errorf("unknown condition %q (use target, mode or profile)", m[1])
+		}
+		matched := false
+		for _, v := range strings.Split(m[3], ",") {
+			if v == value {
+				matched = true
+			}
+		}
+		if matched == (m[2] == "!=") {
+			holds = false
+		}
+	}
+	return holds, nil
+}
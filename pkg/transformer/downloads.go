@@ -0,0 +1,164 @@
+package transformer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DownloadsDir is the asset directory for downloadable files: sample
+// configs, archives and binaries linked from the docs.
+const DownloadsDir = "downloads"
+
+// DownloadInfo describes a downloadable file.
+type DownloadInfo struct {
+	Size   int64
+	SHA256 string
+}
+
+var (
+	downloadLinkRegex       = regexp.MustCompile(`(!?)\[([^\]]+)\]\(([^)\s]+)(\s+"[^"]*")?\)`)
+	downloadAnnotationRegex = regexp.MustCompile(`\(\d+(\.\d)? (B|KB|MB|GB), sha256:[0-9a-f]+\)$`)
+)
+
+// DescribeDownload returns the size and SHA-256 hash of a file.
+func DescribeDownload(path string) (DownloadInfo, error) {
+	f, err := os.Open(path) //nolint:gosec // asset linked from the docs
+	if err != nil {
+		return DownloadInfo{}, err
+	}
+	defer f.Close() //nolint:errcheck // read-only
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return DownloadInfo{}, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return DownloadInfo{Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// DownloadDir returns a lookup for AnnotateDownloads that describes files
+// in dir, caching each file's description.
+func DownloadDir(dir string) func(name string) (DownloadInfo, bool) {
+	cache := make(map[string]DownloadInfo)
+	return func(name string) (DownloadInfo, bool) {
+		if info, ok := cache[name]; ok {
+			return info, true
+		}
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return DownloadInfo{}, false
+		}
+		info, err := DescribeDownload(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return DownloadInfo{}, false
+		}
+		cache[name] = info
+		return info, true
+	}
+}
+
+// AnnotateDownloads adds the size and a short hash of the linked file to the
+// text of every link into a downloads/ directory, e.g.
+// [Sample config (1.2 KB, sha256:3f2a9c1b0d4e)](./downloads/sample.yml).
+// lookup is given the link's path below downloads/; links it does not know
+// and links that are already annotated are left alone.
+func AnnotateDownloads(content string, lookup func(name string) (DownloadInfo, bool)) string {
+	if !strings.Contains(content, DownloadsDir+"/") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	var fences fenceTracker
+	for i, line := range lines {
+		if fences.code(line) {
+			continue
+		}
+		lines[i] = downloadLinkRegex.ReplaceAllStringFunc(line, func(m string) string {
+			sub := downloadLinkRegex.FindStringSubmatch(m)
+			image, text, target, title := sub[1] == "!", sub[2], sub[3], sub[4]
+			name, ok := DownloadName(target)
+			if image || !ok || downloadAnnotationRegex.MatchString(text) {
+				return m
+			}
+			info, ok := lookup(name)
+			if !ok {
+				return m
+			}
+			return fmt.Sprintf("[%s (%s, sha256:%s)](%s%s)", text, FormatSize(info.Size), info.SHA256[:12], target, title)
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AnnotateDownloadsInDir annotates the download links of every markdown file
+// (.md, .mdx) under docsDir with the files in downloadsDir. It does nothing
+// when downloadsDir does not exist.
+func AnnotateDownloadsInDir(docsDir, downloadsDir string) error {
+	if info, err := os.Stat(downloadsDir); err != nil || !info.IsDir() {
+		return nil
+	}
+	lookup := DownloadDir(downloadsDir)
+	return RewriteMarkdownFiles(docsDir, func(content string) string {
+		return AnnotateDownloads(content, lookup)
+	})
+}
+
+// RewriteMarkdownFiles rewrites every markdown file (.md, .mdx) under dir
+// with rewrite, writing back only the files it changes. It is used for
+// passes that need the published assets, which are copied after the docs.
+func RewriteMarkdownFiles(dir string, rewrite func(content string) string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (filepath.Ext(path) != ".md" && filepath.Ext(path) != ".mdx") {
+			return nil
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // walking the published docs
+		if err != nil {
+			return err
+		}
+		rewritten := rewrite(string(data))
+		if rewritten == string(data) {
+			return nil
+		}
+		return os.WriteFile(path, []byte(rewritten), 0o644) //nolint:gosec // internal doc tool output
+	})
+}
+
+// DownloadName returns the path of a local link target below its
+// downloads/ directory, e.g. "tool.zip" for ./downloads/tool.zip.
+func DownloadName(target string) (string, bool) {
+	if strings.Contains(target, "://") {
+		return "", false
+	}
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	marker := DownloadsDir + "/"
+	idx := strings.LastIndex("/"+target, "/"+marker)
+	if idx == -1 {
+		return "", false
+	}
+	name := target[idx+len(marker):]
+	return name, name != ""
+}
+
+// FormatSize formats a byte count for readers, e.g. 1.2 MB.
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, s := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
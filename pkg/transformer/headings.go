@@ -0,0 +1,146 @@
+package transformer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Heading is a markdown heading and the anchor a site renders it with.
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	Slug  string `json:"slug"`
+}
+
+var (
+	atxHeadingRegex  = regexp.MustCompile(`^(#{1,6})[ \t]+(.+?)[ \t]*#*[ \t]*$`)
+	headingIDRegex   = regexp.MustCompile(`[ \t]*\{#([A-Za-z0-9_-]+)\}$`)
+	inlineCodeRegex  = regexp.MustCompile("`([^`]*)`")
+	inlineLinkRegex  = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	emphasisRegex    = regexp.MustCompile(`(\*\*|__|\*|~~)`)
+	inlineHTMLRegex  = regexp.MustCompile(`<[^>]+>`)
+	fenceOpenerRegex = regexp.MustCompile("^[ \t]{0,3}(```+|~~~+)")
+)
+
+// ExtractHeadings returns the ATX headings of a markdown document in order,
+// with the slugs a GitHub-style slugger (the one Astro and Starlight use)
+// gives them, duplicates numbered "-1", "-2", .... A heading ending in an
+// explicit id, "## Install {#setup}", keeps that id as its slug, so links to
+// it survive rewording. Frontmatter and fenced code blocks are skipped.
+func ExtractHeadings(content []byte) []Heading {
+	var headings []Heading
+	slugger := NewSlugger()
+	for _, line := range proseLines(stripFrontmatterBlock(string(content))) {
+		m := atxHeadingRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		raw := m[2]
+		if id := headingIDRegex.FindStringSubmatch(raw); id != nil {
+			raw = strings.TrimSuffix(raw, id[0])
+			slugger.seen[id[1]] = 0
+			headings = append(headings, Heading{Level: len(m[1]), Text: plainHeadingText(raw), Slug: id[1]})
+			continue
+		}
+		text := plainHeadingText(raw)
+		headings = append(headings, Heading{Level: len(m[1]), Text: text, Slug: slugger.Slug(text)})
+	}
+	return headings
+}
+
+// proseLines returns the lines of a markdown document outside fenced code
+// blocks.
+func proseLines(content string) []string {
+	var lines []string
+	var fences fenceTracker
+	for _, line := range strings.Split(content, "\n") {
+		if !fences.code(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// fenceTracker follows fenced code blocks through a document line by line.
+type fenceTracker struct {
+	fence string // Opening delimiter of the current block, or ""
+}
+
+// code reports whether line is a fence delimiter or inside a fenced block.
+func (f *fenceTracker) code(line string) bool {
+	m := fenceOpenerRegex.FindStringSubmatch(line)
+	switch {
+	case m == nil:
+		return f.fence != ""
+	case f.fence == "":
+		f.fence = m[1]
+	case strings.HasPrefix(m[1], f.fence[:1]) && len(m[1]) >= len(f.fence) && strings.TrimSpace(line) == m[1]:
+		f.fence = ""
+	}
+	return true
+}
+
+// Slugger turns heading texts into unique anchors within one document.
+type Slugger struct {
+	seen map[string]int
+}
+
+// NewSlugger creates a slugger for one document.
+func NewSlugger() *Slugger {
+	return &Slugger{seen: make(map[string]int)}
+}
+
+// Slug returns the anchor of a heading: lowercased, punctuation removed,
+// spaces turned into hyphens, and numbered when the document already has it.
+func (s *Slugger) Slug(text string) string {
+	base := Slugify(text)
+	slug := base
+	for {
+		n, ok := s.seen[slug]
+		if !ok {
+			break
+		}
+		s.seen[slug] = n + 1
+		slug = base + "-" + strconv.Itoa(n+1)
+	}
+	s.seen[slug] = 0
+	return slug
+}
+
+// Slugify returns the GitHub-style slug of text, without deduplication.
+func Slugify(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// plainHeadingText strips inline markdown from a heading so it reads (and
+// slugs) as rendered.
+func plainHeadingText(s string) string {
+	s = inlineLinkRegex.ReplaceAllString(s, "$1")
+	s = inlineCodeRegex.ReplaceAllString(s, "$1")
+	s = inlineHTMLRegex.ReplaceAllString(s, "")
+	s = emphasisRegex.ReplaceAllString(s, "")
+	return strings.TrimSpace(s)
+}
+
+// stripFrontmatterBlock removes a leading YAML frontmatter block.
+func stripFrontmatterBlock(content string) string {
+	if !strings.HasPrefix(content, "---\n") {
+		return content
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return content
+	}
+	return content[end+8:]
+}
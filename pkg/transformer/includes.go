@@ -0,0 +1,73 @@
+package transformer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IncludeResolver returns the content of the file an include directive
+// names: path within the includes of package pkg, or of the including
+// package when pkg is empty.
+type IncludeResolver func(pkg, path string) ([]byte, error)
+
+// maxIncludeDepth bounds nested includes.
+const maxIncludeDepth = 8
+
+// includeRe matches {{include "shared/installation.md"}} and the
+// package-qualified {{include "grove-core:shared/installation.md"}}.
+var includeRe = regexp.MustCompile(`\{\{\s*include\s+"([^"\n]+)"\s*\}\}`)
+
+// HasIncludes reports whether content has an include directive.
+func HasIncludes(content string) bool {
+	return strings.Contains(content, "include") && includeRe.MatchString(content)
+}
+
+// ExpandIncludes replaces the include directives of content with the files
+// they name, read through resolve. Included files lose their frontmatter
+// and may include others; an unqualified directive in a package-qualified
+// include resolves in the same package. Directives in code blocks are left
+// alone, so docs can show the syntax. A directive that cannot be resolved,
+// or that includes itself, is left in place and reported in the returned
+// errors.
+func ExpandIncludes(content string, resolve IncludeResolver) (string, []error) {
+	var errs []error
+	out := expandIncludes(content, "", resolve, nil, &errs)
+	return out, errs
+}
+
+func expandIncludes(content, pkg string, resolve IncludeResolver, stack []string, errs *[]error) string {
+	lines := strings.Split(content, "\n")
+	var fence fenceTracker
+	for i, line := range lines {
+		if fence.code(line) || !strings.Contains(line, "include") {
+			continue
+		}
+		lines[i] = includeRe.ReplaceAllStringFunc(line, func(directive string) string {
+			target := includeRe.FindStringSubmatch(directive)[1]
+			incPkg, incPath := pkg, target
+			if p, rest, ok := strings.Cut(target, ":"); ok && !strings.Contains(p, "/") {
+				incPkg, incPath = p, rest
+			}
+			key := incPkg + ":" + incPath
+			for _, s := range stack {
+				if s == key {
+					*errs = append(*errs, fmt.Errorf("include %q includes itself", target))
+					return directive
+				}
+			}
+			if len(stack) >= maxIncludeDepth {
+				*errs = append(*errs, fmt.Errorf("include %q is nested more than %d deep", target, maxIncludeDepth))
+				return directive
+			}
+			data, err := resolve(incPkg, incPath)
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("include %q: %w", target, err))
+				return directive
+			}
+			body := strings.Trim(stripFrontmatterBlock(string(data)), "\n")
+			return expandIncludes(body, incPkg, resolve, append(stack, key), errs)
+		})
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,55 @@
+package transformer
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownLinkRegex = regexp.MustCompile(`(!?)\[[^\]]*\]\(<?([^)\s>]+)>?(?:\s+"[^"]*")?\)`)
+	htmlLinkRegex     = regexp.MustCompile(`<([a-zA-Z]+)[^>]*\s(?:src|href|poster)="([^"]+)"`)
+)
+
+// LocalLink is a link from a document to a file next to it.
+type LocalLink struct {
+	Target string // Path relative to the document, without #fragment or ?query
+	Embed  bool   // Embedded (an image, or an img/video/source/audio element) rather than linked
+}
+
+// LocalLinks returns the relative links of a document, markdown and HTML,
+// in order. URLs with a scheme, site-absolute paths, in-page anchors and
+// links in code are left out.
+func LocalLinks(content []byte) []LocalLink {
+	var links []LocalLink
+	add := func(target string, embed bool) {
+		if i := strings.IndexAny(target, "#?"); i >= 0 {
+			target = target[:i]
+		}
+		if target == "" || strings.HasPrefix(target, "/") || strings.Contains(target, "{") {
+			return
+		}
+		if u, err := url.Parse(target); err != nil || u.Scheme != "" {
+			return
+		}
+		if unescaped, err := url.PathUnescape(target); err == nil {
+			target = unescaped
+		}
+		links = append(links, LocalLink{Target: target, Embed: embed})
+	}
+	for _, line := range proseLines(stripFrontmatterBlock(string(content))) {
+		line = inlineCodeRegex.ReplaceAllString(line, "")
+		for _, m := range markdownLinkRegex.FindAllStringSubmatch(line, -1) {
+			add(m[2], m[1] == "!")
+		}
+		for _, m := range htmlLinkRegex.FindAllStringSubmatch(line, -1) {
+			switch strings.ToLower(m[1]) {
+			case "img", "video", "source", "audio":
+				add(m[2], true)
+			default:
+				add(m[2], false)
+			}
+		}
+	}
+	return links
+}
@@ -0,0 +1,513 @@
+package transformer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// componentFileExts are the extensions of component files, which are
+// imported as their default export. Components of any other module, such
+// as @astrojs/starlight/components, are imported by name.
+var componentFileExts = map[string]bool{".astro": true, ".jsx": true, ".tsx": true, ".svelte": true, ".vue": true, ".mdx": true}
+
+// htmlElements are the HTML elements MDX output may use as they are.
+var htmlElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "br": true, "code": true, "dd": true,
+	"del": true, "details": true, "div": true, "dl": true, "dt": true, "em": true, "figcaption": true,
+	"figure": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true,
+	"i": true, "iframe": true, "img": true, "ins": true, "kbd": true, "li": true, "mark": true, "ol": true,
+	"p": true, "picture": true, "pre": true, "s": true, "samp": true, "small": true, "source": true,
+	"span": true, "strong": true, "sub": true, "summary": true, "sup": true, "table": true, "tbody": true,
+	"td": true, "th": true, "thead": true, "tr": true, "u": true, "ul": true, "video": true, "wbr": true,
+}
+
+// voidElements are the HTML elements without content, which MDX, being
+// JSX, needs self-closed: <br />.
+var voidElements = map[string]bool{"br": true, "hr": true, "img": true, "source": true, "wbr": true}
+
+var (
+	tagRe        = regexp.MustCompile(`^<(/?)([A-Za-z][\w.]*)`)
+	autolinkRe   = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+	importLineRe = regexp.MustCompile(`^import\s+(?:(\w+)|\{([^}]*)\})\s+from\s+["']([^"']+)["'];?\s*$`)
+)
+
+// IsMDX reports whether the doc at path is MDX.
+func IsMDX(path string) bool {
+	return strings.HasSuffix(path, ".mdx")
+}
+
+// EscapeMDX escapes what MDX would read as syntax in markdown written for
+// it by a model: braces, which open expressions, and a < that does not
+// start an element of components or HTML, such as the one of a <command>
+// placeholder. Autolinks become links, void HTML elements are self-closed,
+// and an explicit heading id ({#id}) is kept. Code blocks, code spans and
+// HTML comments are left alone; PrepareMDX rewrites the comments.
+func EscapeMDX(content string, components []string) string {
+	allowed := make(map[string]bool, len(components))
+	for _, c := range components {
+		allowed[c] = true
+	}
+	body := stripFrontmatterBlock(content)
+	frontmatter := content[:len(content)-len(body)]
+
+	lines := strings.Split(body, "\n")
+	var fence fenceTracker
+	for i, line := range lines {
+		if fence.code(line) || importLineRe.MatchString(line) {
+			continue
+		}
+		id := ""
+		if atxHeadingRegex.MatchString(line) {
+			trimmed := strings.TrimRight(line, " \t#")
+			if m := headingIDRegex.FindString(trimmed); m != "" {
+				line, id = strings.TrimSuffix(trimmed, m), m
+			}
+		}
+		lines[i] = mapProse(line, func(s string) string { return escapeProse(s, allowed) }) + id
+	}
+	return frontmatter + strings.Join(lines, "\n")
+}
+
+// escapeProse escapes a line, or part of one, outside code spans.
+func escapeProse(s string, allowed map[string]bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			sb.WriteByte(c)
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case '{', '}':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '<':
+			rest := s[i:]
+			if strings.HasPrefix(rest, "<!--") {
+				end := strings.Index(rest, "-->")
+				if end < 0 {
+					end = len(rest) - 3
+				}
+				sb.WriteString(rest[:end+3])
+				i += end + 2
+				continue
+			}
+			if m := autolinkRe.FindStringSubmatch(rest); m != nil {
+				fmt.Fprintf(&sb, "[%s](%s)", m[1], m[1])
+				i += len(m[0]) - 1
+				continue
+			}
+			m := tagRe.FindStringSubmatch(rest)
+			if m == nil || !(allowed[m[2]] || htmlElements[strings.ToLower(m[2])]) {
+				sb.WriteString(`\<`)
+				continue
+			}
+			end := tagEnd(rest)
+			if end < 0 {
+				// The tag goes on over the next lines
+				sb.WriteString(rest)
+				return sb.String()
+			}
+			tag := rest[:end+1]
+			if m[1] == "" && voidElements[strings.ToLower(m[2])] && !strings.HasSuffix(tag, "/>") {
+				tag = strings.TrimRight(tag[:len(tag)-1], " ") + " />"
+			}
+			sb.WriteString(tag)
+			i += end
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// tagEnd returns the index of the > closing the tag s starts with, skipping
+// quoted attribute values and expressions, or -1 when s ends first.
+func tagEnd(s string) int {
+	var quote byte
+	depth := 0
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case c == '>' && depth <= 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// mapProse applies fn to the parts of line outside code spans.
+func mapProse(line string, fn func(string) string) string {
+	return mapSpans(line, fn, func(code string) string { return code })
+}
+
+// maskCodeSpans replaces the code spans of line with spaces.
+func maskCodeSpans(line string) string {
+	return mapSpans(line, func(s string) string { return s }, func(code string) string {
+		return strings.Repeat(" ", len(code))
+	})
+}
+
+// mapSpans applies prose to the parts of line outside code spans and code
+// to the code spans, backticks included.
+func mapSpans(line string, prose, code func(string) string) string {
+	var sb strings.Builder
+	for line != "" {
+		start := strings.IndexByte(line, '`')
+		if start < 0 {
+			sb.WriteString(prose(line))
+			break
+		}
+		sb.WriteString(prose(line[:start]))
+		n := start
+		for n < len(line) && line[n] == '`' {
+			n++
+		}
+		ticks := n - start
+		end := closingTicks(line[n:], ticks)
+		if end < 0 {
+			// Unmatched backticks are text
+			sb.WriteString(line[start:n])
+			line = line[n:]
+			continue
+		}
+		sb.WriteString(code(line[start : n+end+ticks]))
+		line = line[n+end+ticks:]
+	}
+	return sb.String()
+}
+
+// closingTicks returns the index of the first run of exactly n backticks in
+// s, or -1.
+func closingTicks(s string, n int) int {
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(s) && s[j] == '`' {
+			j++
+		}
+		if j-i == n {
+			return i
+		}
+		i = j
+	}
+	return -1
+}
+
+// PrepareMDX readies an MDX doc for the website: its HTML comments, which
+// MDX does not allow, become MDX comments ({/* ... */}), and the components
+// it uses as elements, or lists in a components: field of its frontmatter,
+// are imported from the module components maps them to. A component
+// already imported is left alone.
+func PrepareMDX(content string, declared []string, components []Component) string {
+	content = mdxComments(content)
+
+	imported := make(map[string]bool)
+	for name := range mdxImports(content) {
+		imported[name] = true
+	}
+	imports := make(map[string]string)
+	for _, name := range append(declared, usedComponents(content)...) {
+		if imported[name] {
+			continue
+		}
+		for _, c := range components {
+			if c.Name == name && c.Import != "" {
+				imports[name] = c.Import
+			}
+		}
+	}
+	return addImports(content, imports)
+}
+
+// FrontmatterComponents returns the component names a doc lists in the
+// components: field of its frontmatter.
+func FrontmatterComponents(content string) []string {
+	body := stripFrontmatterBlock(content)
+	if len(body) == len(content) {
+		return nil
+	}
+	var fm struct {
+		Components []string `yaml:"components"`
+	}
+	if err := yaml.Unmarshal([]byte(content[4:len(content)-len(body)-3]), &fm); err != nil {
+		return nil
+	}
+	return fm.Components
+}
+
+// mdxComments rewrites the HTML comments outside code as MDX comments.
+func mdxComments(content string) string {
+	if !strings.Contains(content, "<!--") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	var fence fenceTracker
+	inComment := false
+	for i, line := range lines {
+		if !inComment && fence.code(line) {
+			continue
+		}
+		lines[i] = mapProse(line, func(s string) string {
+			var sb strings.Builder
+			for s != "" {
+				if inComment {
+					end := strings.Index(s, "-->")
+					if end < 0 {
+						sb.WriteString(strings.ReplaceAll(s, "*/", "* /"))
+						return sb.String()
+					}
+					sb.WriteString(strings.ReplaceAll(s[:end], "*/", "* /") + "*/}")
+					s, inComment = s[end+3:], false
+					continue
+				}
+				start := strings.Index(s, "<!--")
+				if start < 0 || (start > 0 && s[start-1] == '\\') {
+					sb.WriteString(s)
+					break
+				}
+				sb.WriteString(s[:start] + "{/*")
+				s, inComment = s[start+4:], true
+			}
+			return sb.String()
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mdxImports returns the names an MDX doc imports, mapped to their modules.
+func mdxImports(content string) map[string]string {
+	imports := make(map[string]string)
+	var fence fenceTracker
+	for _, line := range strings.Split(stripFrontmatterBlock(content), "\n") {
+		if fence.code(line) {
+			continue
+		}
+		m := importLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if m[1] != "" {
+			imports[m[1]] = m[3]
+		}
+		for _, name := range strings.Split(m[2], ",") {
+			fields := strings.Fields(name)
+			if len(fields) > 0 {
+				imports[fields[len(fields)-1]] = m[3]
+			}
+		}
+	}
+	return imports
+}
+
+// usedComponents returns the names of the components an MDX doc uses as
+// elements: those whose name is capitalized, or the object of a dotted
+// name such as Tabs.Item.
+func usedComponents(content string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	var fence fenceTracker
+	for _, line := range strings.Split(stripFrontmatterBlock(content), "\n") {
+		if fence.code(line) {
+			continue
+		}
+		mapProse(line, func(s string) string {
+			for i := strings.IndexByte(s, '<'); i >= 0; i = nextByte(s, '<', i) {
+				if i > 0 && s[i-1] == '\\' {
+					continue
+				}
+				m := tagRe.FindStringSubmatch(s[i:])
+				if m == nil {
+					continue
+				}
+				name, _, _ := strings.Cut(m[2], ".")
+				if name[0] >= 'A' && name[0] <= 'Z' && !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+			return s
+		})
+	}
+	return names
+}
+
+func nextByte(s string, c byte, after int) int {
+	i := strings.IndexByte(s[after+1:], c)
+	if i < 0 {
+		return -1
+	}
+	return after + 1 + i
+}
+
+// addImports adds an import of each of imports, names mapped to modules,
+// after the frontmatter of content. Component files are imported as their
+// default export, the names of any other module in one named import.
+func addImports(content string, imports map[string]string) string {
+	if len(imports) == 0 {
+		return content
+	}
+	named := make(map[string][]string)
+	var lines []string
+	for name, module := range imports {
+		if componentFileExts[path.Ext(module)] {
+			lines = append(lines, fmt.Sprintf("import %s from %q;", name, module))
+		} else {
+			named[module] = append(named[module], name)
+		}
+	}
+	for module, names := range named {
+		sort.Strings(names)
+		lines = append(lines, fmt.Sprintf("import { %s } from %q;", strings.Join(names, ", "), module))
+	}
+	sort.Strings(lines)
+
+	body := stripFrontmatterBlock(content)
+	frontmatter := content[:len(content)-len(body)]
+	if frontmatter != "" {
+		frontmatter += "\n\n"
+	}
+	body = strings.TrimLeft(body, "\n")
+	if !importLineRe.MatchString(strings.SplitN(body, "\n", 2)[0]) {
+		lines = append(lines, "")
+	}
+	return frontmatter + strings.Join(lines, "\n") + "\n" + body
+}
+
+// ValidateMDX checks that content parses as MDX: its elements are closed,
+// in order, and void HTML elements self-closed; its braces are balanced;
+// and it has no HTML comment, autolink or < that starts no element. Code is
+// skipped. Errors name the line.
+func ValidateMDX(content string) []error {
+	body := stripFrontmatterBlock(content)
+	offset := strings.Count(content[:len(content)-len(body)], "\n")
+
+	// Blank out code, and the import lines, keeping the line numbers
+	lines := strings.Split(body, "\n")
+	var fence fenceTracker
+	for i, line := range lines {
+		if fence.code(line) || importLineRe.MatchString(line) {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = maskCodeSpans(line)
+	}
+	s := strings.Join(lines, "\n")
+
+	type open struct {
+		name string
+		line int
+	}
+	var (
+		errs  []error
+		tags  []open
+		depth int
+		from  int // Line of the outermost open brace
+		line  = offset + 1
+	)
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("line %d: "+format, append([]interface{}{line}, args...)...))
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n':
+			line++
+		case c == '\\':
+			i++
+		case c == '{':
+			if depth == 0 {
+				from = line
+			}
+			depth++
+		case c == '}':
+			if depth == 0 {
+				fail("unexpected }; escape it as \\}")
+				continue
+			}
+			depth--
+		case c == '<' && depth == 0:
+			rest := s[i:]
+			if strings.HasPrefix(rest, "<!--") {
+				fail("HTML comment; MDX needs {/* ... */}")
+				if end := strings.Index(rest, "-->"); end >= 0 {
+					line += strings.Count(rest[:end], "\n")
+					i += end + 2
+				}
+				continue
+			}
+			if m := autolinkRe.FindString(rest); m != "" {
+				fail("autolink %s; MDX needs a link, [url](url)", m)
+				i += len(m) - 1
+				continue
+			}
+			m := tagRe.FindStringSubmatch(rest)
+			if m == nil {
+				fail("< starts no element; escape it as \\<")
+				continue
+			}
+			end := tagEnd(rest)
+			if end < 0 {
+				fail("<%s%s> is not closed with >", m[1], m[2])
+				return errs
+			}
+			tag := rest[:end+1]
+			switch {
+			case m[1] == "/":
+				if len(tags) == 0 {
+					fail("</%s> closes no element", m[2])
+				} else if top := tags[len(tags)-1]; top.name != m[2] {
+					fail("</%s> closes <%s> of line %d", m[2], top.name, top.line)
+					tags = tags[:len(tags)-1]
+				} else {
+					tags = tags[:len(tags)-1]
+				}
+			case strings.HasSuffix(tag, "/>"):
+			case voidElements[strings.ToLower(m[2])]:
+				fail("<%s> must be self-closed: <%s />", m[2], m[2])
+			default:
+				tags = append(tags, open{m[2], line})
+			}
+			line += strings.Count(tag, "\n")
+			i += end
+		}
+	}
+	if depth > 0 {
+		errs = append(errs, fmt.Errorf("line %d: { is not closed", from))
+	}
+	for _, t := range tags {
+		errs = append(errs, fmt.Errorf("line %d: <%s> is not closed", t.line, t.name))
+	}
+	return errs
+}
+
+// UndefinedComponents returns the components an MDX doc uses as elements
+// without importing them, which fails the website build.
+func UndefinedComponents(content string) []string {
+	imported := mdxImports(content)
+	var undefined []string
+	for _, name := range usedComponents(content) {
+		if _, ok := imported[name]; !ok {
+			undefined = append(undefined, name)
+		}
+	}
+	return undefined
+}
@@ -0,0 +1,91 @@
+package transformer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildMetadata records the build that produced a published page. Empty
+// fields are left out.
+type BuildMetadata struct {
+	Commit      string // Git commit of the package
+	Version     string // docgen version
+	GeneratedAt string // RFC 3339 time the doc was generated
+	Model       string // Model that generated the doc
+	MetaTags    bool   // Also write the fields as docgen:* meta tags (Astro only)
+}
+
+// fields returns the non-empty fields as name/value pairs, in a fixed order.
+func (m *BuildMetadata) fields() [][2]string {
+	if m == nil {
+		return nil
+	}
+	var fields [][2]string
+	for _, f := range [][2]string{
+		{"commit", m.Commit},
+		{"version", m.Version},
+		{"generated_at", m.GeneratedAt},
+		{"model", m.Model},
+	} {
+		if f[1] != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// frontmatter returns the metadata as a docgen: frontmatter block, or "".
+func (m *BuildMetadata) frontmatter() string {
+	fields := m.fields()
+	if len(fields) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("docgen:\n")
+	for _, f := range fields {
+		fmt.Fprintf(&sb, "  %s: \"%s\"\n", f[0], escapeYAMLString(f[1]))
+	}
+	return sb.String()
+}
+
+// headEntries returns the Starlight head entries for the metadata's meta
+// tags, without the head: key, or "" when meta tags are off.
+func (m *BuildMetadata) headEntries() string {
+	if m == nil || !m.MetaTags {
+		return ""
+	}
+	var sb strings.Builder
+	for _, f := range m.fields() {
+		fmt.Fprintf(&sb, "  - tag: meta\n    attrs:\n      name: docgen:%s\n      content: \"%s\"\n", f[0], escapeYAMLString(f[1]))
+	}
+	return sb.String()
+}
+
+// InjectMetadata adds the metadata's docgen: block to the frontmatter of a
+// markdown page, creating the frontmatter when there is none. A page that
+// already has a docgen: block is left alone.
+func InjectMetadata(content []byte, m *BuildMetadata) []byte {
+	block := m.frontmatter()
+	if block == "" {
+		return content
+	}
+	s := string(content)
+	if !strings.HasPrefix(s, "---\n") {
+		return []byte("---\n" + block + "---\n\n" + s)
+	}
+	end := strings.Index(s[4:], "\n---")
+	if end == -1 || hasFrontmatterKey(s[4:end+4], "docgen") {
+		return content
+	}
+	return []byte(s[:end+5] + block + s[end+5:])
+}
+
+// hasFrontmatterKey reports whether frontmatter has a top-level key.
+func hasFrontmatterKey(frontmatter, key string) bool {
+	for _, line := range strings.Split(frontmatter, "\n") {
+		if strings.HasPrefix(line, key+":") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package transformer
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// VideoSource is one encoding of a video, in the order browsers should try
+// them.
+type VideoSource struct {
+	Name string // File name in the videos directory
+	Type string // MIME type, with codecs where they matter
+}
+
+// VideoVariants are the files published for one video.
+type VideoVariants struct {
+	Poster  string // File name of the poster frame, or ""
+	Sources []VideoSource
+}
+
+// videoEmbedRegex matches a markdown video embed on a line of its own:
+// ![alt](./videos/demo.mp4), with any path before videos/.
+var videoEmbedRegex = regexp.MustCompile(`^[ \t]*!\[([^\]]*)\]\(([^)\s#?]*?)videos/([^)\s#?]+\.mp4)\)[ \t]*$`)
+
+// VideoElements rewrites markdown video embeds into <video> elements with
+// the poster and sources lookup returns for the video's file name. Embeds
+// inside code blocks, inline in a paragraph, or with a URL fragment (which
+// the website handles itself, e.g. #themed) are left alone, as are videos
+// lookup does not know.
+func VideoElements(content string, lookup func(name string) (VideoVariants, bool)) string {
+	if !strings.Contains(content, "videos/") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	var fences fenceTracker
+	for i, line := range lines {
+		if fences.code(line) {
+			continue
+		}
+		m := videoEmbedRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		variants, ok := lookup(m[3])
+		if !ok || len(variants.Sources) == 0 {
+			continue
+		}
+		lines[i] = videoElement(m[1], m[2]+"videos/", variants)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// videoElement renders a <video> element whose files live under prefix.
+// Elements are self-closing where needed so the markup is valid MDX too.
+func videoElement(alt, prefix string, v VideoVariants) string {
+	var sb strings.Builder
+	sb.WriteString(`<video controls preload="metadata"`)
+	if v.Poster != "" {
+		fmt.Fprintf(&sb, ` poster="%s"`, html.EscapeString(prefix+v.Poster))
+	}
+	if alt != "" {
+		fmt.Fprintf(&sb, ` aria-label="%s"`, html.EscapeString(alt))
+	}
+	sb.WriteString(">\n")
+	for _, s := range v.Sources {
+		fmt.Fprintf(&sb, "  <source src=\"%s\" type='%s' />\n", html.EscapeString(prefix+s.Name), s.Type)
+	}
+	sb.WriteString("</video>")
+	return sb.String()
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/diff"
+	"github.com/grovetools/docgen/pkg/discovery"
+	"github.com/grovetools/docgen/pkg/generator"
+	"github.com/sirupsen/logrus"
+)
+
+// pkgState is a package and its sections as the TUI lists them.
+type pkgState struct {
+	name     string
+	path     string
+	sections []*sectionState
+	err      error // Why the sections could not be listed
+}
+
+// sectionState is a section as the TUI lists it.
+type sectionState struct {
+	info      generator.SectionInfo
+	configDir string // Directory of the docgen config the section comes from
+	baseName  string // Name within that config (without the subdir/ prefix)
+	stale     bool
+
+	running bool   // Generating now
+	lastErr error  // Error of the last generation in this session
+	diff    string // What the last generation in this session changed
+}
+
+// loadPackages lists the packages of dir: the docgen-enabled packages of the
+// ecosystems its docgen config names, or dir alone when it is a package
+// without ecosystems.
+func loadPackages(dir string, logger *logrus.Logger) ([]*pkgState, error) {
+	gen := generator.New(logger)
+	packages, err := gen.Packages(dir)
+	if err != nil {
+		if _, _, cerr := config.LoadWithNotebook(dir); cerr != nil {
+			return nil, err
+		}
+		packages = []discovery.Package{{Name: filepath.Base(dir), Path: dir}}
+	}
+	states := make([]*pkgState, 0, len(packages))
+	for _, p := range packages {
+		s := &pkgState{name: p.Name, path: p.Path}
+		s.sections, s.err = loadSections(gen, p.Path)
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+// loadSections lists the sections of the package at dir with their status
+// and staleness.
+func loadSections(gen *generator.Generator, dir string) ([]*sectionState, error) {
+	infos, err := generator.ListSections(dir)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := generator.ResolveSectionTargets(dir)
+	if err != nil {
+		return nil, err
+	}
+	staleNames, err := gen.StaleSections(dir)
+	if err != nil {
+		return nil, err
+	}
+	stale := make(map[string]bool, len(staleNames))
+	for _, name := range staleNames {
+		stale[name] = true
+	}
+	sections := make([]*sectionState, 0, len(infos))
+	for i, info := range infos {
+		s := &sectionState{info: info, stale: stale[info.Name], baseName: info.Name}
+		// ListSections lists the targets in order
+		if i < len(targets) && targets[i].Name == info.Name {
+			s.configDir = targets[i].ConfigDir
+			s.baseName = targets[i].Section.Name
+		}
+		sections = append(sections, s)
+	}
+	return sections, nil
+}
+
+// promote moves the section to its next status in its docgen config.
+func promote(s *sectionState) (string, error) {
+	next, ok := config.NextStatus(s.info.Status)
+	if !ok {
+		return "", fmt.Errorf("%s is already %s", s.info.Name, s.info.Status)
+	}
+	if s.configDir == "" {
+		return "", fmt.Errorf("the docgen config of %s was not found", s.info.Name)
+	}
+	if err := config.SetSectionStatus(filepath.Join(s.configDir, config.ConfigFileName), s.baseName, next); err != nil {
+		return "", err
+	}
+	return next, nil
+}
+
+// readDoc returns a section's generated doc, or "" when it was never
+// generated.
+func readDoc(s *sectionState) string {
+	data, err := os.ReadFile(s.info.Output)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// committedDiff returns how the section's doc differs from its last
+// committed version.
+func committedDiff(ctx context.Context, s *sectionState) (string, error) {
+	path := s.info.Output
+	cmd := exec.CommandContext(ctx, "git", "-C", filepath.Dir(path), "show", "HEAD:./"+filepath.Base(path)) //nolint:gosec // path from config
+	old, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s has no committed version to compare with", path)
+	}
+	current := readDoc(s)
+	d := diff.Unified("HEAD:"+filepath.Base(path), filepath.Base(path), string(old), current)
+	if d == "" {
+		return "", fmt.Errorf("%s is unchanged since the last commit", filepath.Base(path))
+	}
+	return d, nil
+}
+
+// statusLabel describes a section's doc for the list: missing, stale or
+// up to date with its age.
+func statusLabel(s *sectionState, now time.Time) string {
+	switch {
+	case s.info.LastGenerated == nil:
+		return "missing"
+	case s.stale:
+		return "stale"
+	}
+	return "fresh, " + age(now.Sub(*s.info.LastGenerated))
+}
+
+// age formats how long ago something happened, e.g. 3h.
+func age(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// lastLine returns the last line of an error message, for the status bar.
+func lastLine(err error) string {
+	msg := strings.TrimSpace(err.Error())
+	if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+		msg = msg[i+1:]
+	}
+	return msg
+}
@@ -0,0 +1,570 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/grovetools/docgen/pkg/diff"
+	"github.com/grovetools/docgen/pkg/docgen"
+	"github.com/grovetools/docgen/pkg/generator"
+)
+
+// maxLogLines caps the log lines kept for the log pane.
+const maxLogLines = 2000
+
+// logPaneHeight is the height of the log tail below the list.
+const logPaneHeight = 6
+
+type screen int
+
+const (
+	screenList screen = iota
+	screenDiff
+	screenLogs
+)
+
+// row is one line of the list: a package (section -1) or one of its
+// sections.
+type row struct {
+	pkg, section int
+}
+
+type model struct {
+	ctx  context.Context
+	opts Options
+	ulog *clilog.EventLogger
+
+	packages []*pkgState
+	rows     []row
+	cursor   int
+	offset   int
+
+	width, height int
+	screen        screen
+	viewport      viewport.Model
+	logs          []string
+	message       string
+	loading       bool
+	busy          bool // A generation is running
+	watchCancel   context.CancelFunc
+}
+
+// Messages of the background work.
+type (
+	loadedMsg struct {
+		packages []*pkgState
+		err      error
+	}
+	reloadedMsg struct {
+		pkg      int
+		sections []*sectionState
+		err      error
+	}
+	generatedMsg struct {
+		pkg     int
+		section int // -1 for the package's stale sections
+		err     error
+		diff    string
+	}
+	diffMsg struct {
+		title string
+		diff  string
+		err   error
+	}
+	watchStoppedMsg struct{ err error }
+)
+
+func newModel(ctx context.Context, opts Options) *model {
+	return &model{ctx: ctx, opts: opts, ulog: clilog.New(clilog.Component + ".tui"), loading: true}
+}
+
+func (m *model) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m *model) load() tea.Cmd {
+	dir, logger := m.opts.Dir, m.opts.Logger
+	return func() tea.Msg {
+		packages, err := loadPackages(dir, logger)
+		return loadedMsg{packages: packages, err: err}
+	}
+}
+
+func (m *model) reload(pi int) tea.Cmd {
+	path, logger := m.packages[pi].path, m.opts.Logger
+	return func() tea.Msg {
+		sections, err := loadSections(generator.New(logger), path)
+		return reloadedMsg{pkg: pi, sections: sections, err: err}
+	}
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.viewport.Width, m.viewport.Height = msg.Width, msg.Height-2
+		return m, nil
+
+	case logMsg:
+		m.logs = append(m.logs, string(msg))
+		if len(m.logs) > maxLogLines {
+			m.logs = m.logs[len(m.logs)-maxLogLines:]
+		}
+		if m.screen == screenLogs {
+			m.showLogs()
+		}
+		return m, nil
+
+	case loadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.message = "Failed to list packages: " + lastLine(msg.err)
+			return m, nil
+		}
+		m.packages = msg.packages
+		m.buildRows()
+		return m, nil
+
+	case reloadedMsg:
+		p := m.packages[msg.pkg]
+		// Keep what this session learned about each section
+		previous := make(map[string]*sectionState, len(p.sections))
+		for _, s := range p.sections {
+			previous[s.info.Name] = s
+		}
+		for _, s := range msg.sections {
+			if old := previous[s.info.Name]; old != nil {
+				s.running, s.lastErr, s.diff = old.running, old.lastErr, old.diff
+			}
+		}
+		p.sections, p.err = msg.sections, msg.err
+		m.buildRows()
+		return m, nil
+
+	case generatedMsg:
+		m.busy = false
+		p := m.packages[msg.pkg]
+		name := p.name
+		if msg.section >= 0 && msg.section < len(p.sections) {
+			s := p.sections[msg.section]
+			s.running, s.lastErr = false, msg.err
+			if msg.diff != "" {
+				s.diff = msg.diff
+			}
+			name = s.info.Name
+		}
+		if msg.err != nil {
+			m.message = fmt.Sprintf("Generating %s failed: %s", name, lastLine(msg.err))
+		} else {
+			m.message = "Generated " + name
+		}
+		return m, m.reload(msg.pkg)
+
+	case diffMsg:
+		if msg.err != nil {
+			m.message = lastLine(msg.err)
+			return m, nil
+		}
+		m.screen = screenDiff
+		m.viewport.SetContent(colorDiff(msg.diff))
+		m.viewport.GotoTop()
+		m.message = msg.title
+		return m, nil
+
+	case watchStoppedMsg:
+		m.watchCancel = nil
+		if msg.err != nil {
+			m.message = "Watch stopped: " + lastLine(msg.err)
+		} else {
+			m.message = "Watch stopped"
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+	if m.screen != screenList {
+		switch key {
+		case "esc", "q", "l", "d":
+			m.screen = screenList
+			m.message = ""
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	switch key {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.listHeight())
+	case "pgdown":
+		m.move(m.listHeight())
+	case "home", "g":
+		m.move(-len(m.rows))
+	case "end", "G":
+		m.move(len(m.rows))
+	case "enter":
+		return m, m.generate()
+	case "d":
+		return m, m.showDiff()
+	case "p":
+		return m, m.promote()
+	case "w":
+		return m, m.toggleWatch()
+	case "l":
+		m.screen = screenLogs
+		m.showLogs()
+		m.message = "Logs"
+	case "r":
+		if !m.loading {
+			m.loading = true
+			m.message = ""
+			return m, m.load()
+		}
+	}
+	return m, nil
+}
+
+// buildRows flattens the packages and sections into the list's rows.
+func (m *model) buildRows() {
+	m.rows = m.rows[:0]
+	for pi, p := range m.packages {
+		m.rows = append(m.rows, row{pkg: pi, section: -1})
+		for si := range p.sections {
+			m.rows = append(m.rows, row{pkg: pi, section: si})
+		}
+	}
+	m.move(0)
+}
+
+func (m *model) move(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.rows)-1))
+	h := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+}
+
+// selected returns the package and section under the cursor; the section
+// is nil on a package row.
+func (m *model) selected() (int, *pkgState, *sectionState) {
+	if len(m.rows) == 0 {
+		return -1, nil, nil
+	}
+	r := m.rows[m.cursor]
+	p := m.packages[r.pkg]
+	if r.section < 0 || r.section >= len(p.sections) {
+		return r.pkg, p, nil
+	}
+	return r.pkg, p, p.sections[r.section]
+}
+
+// generate generates the selected section, or the stale sections of the
+// selected package. One generation runs at a time: generations of one
+// package would overwrite each other's context.
+func (m *model) generate() tea.Cmd {
+	pi, p, s := m.selected()
+	if p == nil {
+		return nil
+	}
+	if m.busy {
+		m.message = "A generation is already running"
+		return nil
+	}
+	m.busy = true
+	opts := docgen.GenerateOptions{Logger: m.opts.Logger, UnifiedLog: true}
+	si, before := -1, ""
+	if s != nil {
+		si = m.rows[m.cursor].section
+		opts.Sections = []string{s.info.Name}
+		before = readDoc(s)
+		s.running = true
+		m.message = "Generating " + s.info.Name
+	} else {
+		opts.OnlyStale = true
+		m.message = "Generating the stale sections of " + p.name
+	}
+	ctx := m.ctx
+	return func() tea.Msg {
+		err := docgen.Generate(ctx, p.path, opts)
+		msg := generatedMsg{pkg: pi, section: si, err: err}
+		if s != nil {
+			msg.diff = diff.Unified(s.info.Name+" (before)", s.info.Name, before, readDoc(s))
+		}
+		return msg
+	}
+}
+
+// showDiff shows what the last generation of the selected section changed
+// or, before any, how its doc differs from the committed version.
+func (m *model) showDiff() tea.Cmd {
+	_, _, s := m.selected()
+	if s == nil {
+		m.message = "Select a section to see its diff"
+		return nil
+	}
+	if s.diff != "" {
+		d := s.diff
+		return func() tea.Msg { return diffMsg{title: "Changes of the last generation of " + s.info.Name, diff: d} }
+	}
+	ctx := m.ctx
+	return func() tea.Msg {
+		d, err := committedDiff(ctx, s)
+		return diffMsg{title: "Changes of " + s.info.Name + " since the last commit", diff: d, err: err}
+	}
+}
+
+// promote moves the selected section to its next status.
+func (m *model) promote() tea.Cmd {
+	pi, _, s := m.selected()
+	if s == nil {
+		m.message = "Select a section to promote"
+		return nil
+	}
+	status, err := promote(s)
+	if err != nil {
+		m.message = "Cannot promote: " + lastLine(err)
+		return nil
+	}
+	m.message = fmt.Sprintf("Promoted %s to %s", s.info.Name, status)
+	return m.reload(pi)
+}
+
+// toggleWatch starts or stops the watcher; its rebuilds are logged.
+func (m *model) toggleWatch() tea.Cmd {
+	if m.watchCancel != nil {
+		m.watchCancel()
+		m.message = "Stopping watch"
+		return nil
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.watchCancel = cancel
+	m.message = "Watching for documentation changes"
+	ulog := m.ulog
+	opts := docgen.WatchOptions{
+		WebsiteDir: m.opts.WebsiteDir,
+		Mode:       m.opts.Mode,
+		Logger:     m.opts.Logger,
+		Hooks: docgen.WatchHooks{
+			OnReady: func(packages int) {
+				ulog.Info("Watching for documentation changes").Event(clilog.EventWatchReady).
+					Field("mode", m.opts.Mode).Field("packages", packages).Emit()
+			},
+			OnRebuildStart: func(ev docgen.WatchEvent) {
+				ulog.Info("Rebuilding").Event(clilog.EventWatchRebuildStart).
+					Field("package", ev.Package).Field("kind", ev.Kind).Emit()
+			},
+			OnRebuild: func(ev docgen.WatchEvent) {
+				if ev.Err != nil {
+					ulog.Error("Rebuild failed").Event(clilog.EventWatchRebuildFailed).
+						Field("package", ev.Package).Field("kind", ev.Kind).Err(ev.Err).Emit()
+					return
+				}
+				ulog.Info("Done").Event(clilog.EventWatchRebuildDone).
+					Field("package", ev.Package).Field("kind", ev.Kind).
+					Field("duration", ev.Duration.Round(time.Millisecond).String()).Emit()
+			},
+		},
+	}
+	return func() tea.Msg {
+		return watchStoppedMsg{err: docgen.Watch(ctx, opts)}
+	}
+}
+
+func (m *model) showLogs() {
+	m.viewport.SetContent(strings.Join(m.logs, "\n"))
+	m.viewport.GotoBottom()
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	packageStyle  = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	okStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	warnStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	hunkStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+)
+
+// listHeight is the number of list rows that fit above the log pane.
+func (m *model) listHeight() int {
+	return max(1, m.height-logPaneHeight-5)
+}
+
+func (m *model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	if m.screen != screenList {
+		help := "up/down scroll  esc back"
+		return titleStyle.Render(m.message) + "\n" + m.viewport.View() + "\n" + faintStyle.Render(help)
+	}
+
+	var b strings.Builder
+	b.WriteString(m.header() + "\n\n")
+
+	h := m.listHeight()
+	switch {
+	case m.loading && len(m.rows) == 0:
+		b.WriteString("Loading packages...\n")
+		h--
+	case len(m.rows) == 0:
+		b.WriteString("No packages\n")
+		h--
+	}
+	for i := m.offset; i < len(m.rows) && i < m.offset+h; i++ {
+		line := m.renderRow(m.rows[i])
+		if i == m.cursor {
+			line = selectedStyle.Render(padRight(line, m.width))
+		}
+		b.WriteString(line + "\n")
+	}
+	for i := len(m.rows) - m.offset; i < h; i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(faintStyle.Render(strings.Repeat("─", m.width)) + "\n")
+	tail := m.logs[max(0, len(m.logs)-logPaneHeight):]
+	for i := 0; i < logPaneHeight; i++ {
+		if i < len(tail) {
+			b.WriteString(truncate(tail[i], m.width))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(truncate(m.message, m.width) + "\n")
+	b.WriteString(faintStyle.Render(truncate("enter generate  d diff  p promote  w watch  l logs  r refresh  q quit", m.width)))
+	return b.String()
+}
+
+func (m *model) header() string {
+	sections := 0
+	for _, p := range m.packages {
+		sections += len(p.sections)
+	}
+	h := titleStyle.Render("docgen") + fmt.Sprintf("  %d package(s), %d section(s)", len(m.packages), sections)
+	if m.watchCancel != nil {
+		h += "  " + okStyle.Render("[watching]")
+	}
+	if m.busy {
+		h += "  " + warnStyle.Render("[generating]")
+	}
+	return h
+}
+
+func (m *model) renderRow(r row) string {
+	p := m.packages[r.pkg]
+	if r.section < 0 {
+		line := packageStyle.Render(p.name) + "  " + faintStyle.Render(p.path)
+		if p.err != nil {
+			line += "  " + errorStyle.Render(lastLine(p.err))
+		}
+		return truncate(line, m.width)
+	}
+	s := p.sections[r.section]
+	status := s.info.Status
+	switch status {
+	case "production":
+		status = okStyle.Render(fmt.Sprintf("%-10s", status))
+	case "dev":
+		status = warnStyle.Render(fmt.Sprintf("%-10s", status))
+	default:
+		status = faintStyle.Render(fmt.Sprintf("%-10s", status))
+	}
+	label := statusLabel(s, time.Now())
+	switch {
+	case s.info.LastGenerated == nil:
+		label = errorStyle.Render(fmt.Sprintf("%-12s", label))
+	case s.stale:
+		label = warnStyle.Render(fmt.Sprintf("%-12s", label))
+	default:
+		label = fmt.Sprintf("%-12s", label)
+	}
+	line := fmt.Sprintf("  %-32s %s %s %-8s", truncate(s.info.Name, 32), status, label, s.info.Type)
+	switch {
+	case s.running:
+		line += "  " + warnStyle.Render("generating")
+	case s.lastErr != nil:
+		line += "  " + errorStyle.Render("failed: "+lastLine(s.lastErr))
+	case s.diff != "":
+		added, removed := diffStats(s.diff)
+		line += "  " + faintStyle.Render(fmt.Sprintf("generated +%d -%d", added, removed))
+	}
+	return line
+}
+
+// colorDiff colors the lines of a unified diff.
+func colorDiff(d string) string {
+	lines := strings.Split(strings.TrimRight(d, "\n"), "\n")
+	for i, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+			lines[i] = titleStyle.Render(l)
+		case strings.HasPrefix(l, "@@"):
+			lines[i] = hunkStyle.Render(l)
+		case strings.HasPrefix(l, "+"):
+			lines[i] = okStyle.Render(l)
+		case strings.HasPrefix(l, "-"):
+			lines[i] = errorStyle.Render(l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffStats counts the added and removed lines of a unified diff.
+func diffStats(d string) (added, removed int) {
+	for _, l := range strings.Split(d, "\n") {
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+		case strings.HasPrefix(l, "+"):
+			added++
+		case strings.HasPrefix(l, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// truncate cuts s to width cells.
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && lipgloss.Width(string(r)) > width {
+		r = r[:len(r)-1]
+	}
+	return string(r)
+}
+
+// padRight pads s with spaces to width cells.
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+// Package tui is docgen's interactive control center: a full-screen
+// terminal UI listing every package and section with its status and
+// staleness, from which sections are generated, their diffs reviewed, their
+// status promoted, and the watcher run with its rebuild log alongside.
+package tui
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/grovetools/docgen/internal/clilog"
+	"github.com/sirupsen/logrus"
+)
+
+// Options configures Run.
+type Options struct {
+	// Dir is the directory whose packages are listed: those of the
+	// ecosystems its docgen config names, or the package at Dir.
+	Dir string
+	// WebsiteDir and Mode configure the watcher, as for docgen watch.
+	WebsiteDir string
+	Mode       string
+	// Logger receives the logs of generation and watch runs; Run shows them
+	// in its log pane.
+	Logger *logrus.Logger
+}
+
+// Run runs the TUI until the user quits or ctx is cancelled. While it runs,
+// docgen's logs are shown in its log pane instead of the terminal.
+func Run(ctx context.Context, opts Options) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m := newModel(ctx, opts)
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx))
+	restore := clilog.Capture(&logSink{send: p.Send})
+	defer restore()
+	_, err := p.Run()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// logMsg is one line of log output.
+type logMsg string
+
+// logSink sends the lines written to it to the TUI.
+type logSink struct {
+	send func(tea.Msg)
+
+	mu      sync.Mutex
+	partial string
+}
+
+func (s *logSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	text := s.partial + string(p)
+	lines := strings.Split(text, "\n")
+	s.partial = lines[len(lines)-1]
+	s.mu.Unlock()
+	for _, line := range lines[:len(lines)-1] {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			s.send(logMsg(line))
+		}
+	}
+	return len(p), nil
+}
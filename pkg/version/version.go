@@ -0,0 +1,54 @@
+// Package version resolves the version a package's docs are published under,
+// shared by aggregate, watch and translate so every output names the same
+// version for the same checkout.
+package version
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/grovetools/core/config"
+)
+
+// Fallback is the version of a package with no tag and no configured version.
+const Fallback = "latest"
+
+// Sources a version can be resolved from.
+const (
+	SourceGit      = "git"
+	SourceConfig   = "grove.yml"
+	SourceFallback = "fallback"
+)
+
+// Info is a resolved version and where it came from.
+type Info struct {
+	Version string `json:"version"`
+	Source  string `json:"source"`
+}
+
+// Resolve returns the version of the package at dir: the nearest git tag
+// (git describe --tags --abbrev=0), else the version in its grove.yml, else
+// Fallback.
+func Resolve(dir string) string {
+	return ResolveInfo(dir).Version
+}
+
+// ResolveInfo is Resolve reporting where the version came from, for
+// provenance records.
+func ResolveInfo(dir string) Info {
+	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
+	cmd.Dir = dir
+	if output, err := cmd.Output(); err == nil {
+		if v := strings.TrimSpace(string(output)); v != "" {
+			return Info{Version: v, Source: SourceGit}
+		}
+	}
+
+	if configPath, err := config.FindConfigFile(dir); err == nil {
+		if cfg, err := config.Load(configPath); err == nil && cfg.Version != "" {
+			return Info{Version: cfg.Version, Source: SourceConfig}
+		}
+	}
+
+	return Info{Version: Fallback, Source: SourceFallback}
+}
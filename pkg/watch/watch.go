@@ -0,0 +1,950 @@
+// Package watch rebuilds a website's docs incrementally as the notebook docgen
+// directories of its packages change. It backs `docgen watch` and
+// docgen.Watch; progress is reported through Hooks and the logger rather than
+// printed, so it can run inside other tools.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	coreConfig "github.com/grovetools/core/config"
+	"github.com/grovetools/core/pkg/workspace"
+	"github.com/grovetools/docgen/pkg/config"
+	"github.com/grovetools/docgen/pkg/discovery"
+	"github.com/grovetools/docgen/pkg/frontmatter"
+	"github.com/grovetools/docgen/pkg/manifest"
+	"github.com/grovetools/docgen/pkg/transformer"
+	docgenVersion "github.com/grovetools/docgen/pkg/version"
+	"github.com/grovetools/docgen/pkg/watcher"
+	"github.com/grovetools/docgen/pkg/writer"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrInvalidMode is returned for a mode other than dev or prod.
+	ErrInvalidMode = config.ErrInvalidMode
+	// ErrNoPackages is returned when no docgen-enabled package with a
+	// notebook docgen directory was found to watch.
+	ErrNoPackages = errors.New("no packages found to watch")
+)
+
+// Rebuild kinds reported in Events.
+const (
+	KindPackage  = "package"  // A package's docs, assets and manifest entry
+	KindConcepts = "concepts" // A package's concept docs
+)
+
+// Event describes one rebuild of a package.
+type Event struct {
+	Package  string
+	Kind     string        // KindPackage or KindConcepts
+	Duration time.Duration // Zero in OnRebuildStart
+	Err      error         // Why the rebuild failed; nil on success
+}
+
+// Hooks are optional callbacks for following a watch. Rebuilds are
+// serialized, so hooks are never called concurrently with each other.
+type Hooks struct {
+	// OnWatching is called for every directory watched: each package's
+	// docgen directory and, when it has one, its concepts directory.
+	OnWatching func(pkg, dir string)
+	// OnReady is called once setup is done, with the number of packages
+	// watched, before the first change is handled.
+	OnReady func(packages int)
+	// OnRebuildStart and OnRebuild bracket each rebuild.
+	OnRebuildStart func(Event)
+	OnRebuild      func(Event)
+	// OnBatch is called after each debounced batch of rebuilds with the
+	// batch's events.
+	OnBatch func([]Event)
+}
+
+// Options configures Run.
+type Options struct {
+	WebsiteDir string        // Root of the Astro website written to
+	Mode       string        // "dev" (default) or "prod"
+	Debounce   time.Duration // Quiet period before rebuilding; default 100ms
+	// Audiences limit the rebuilt sections to those written for one of
+	// them (see config.ForAudience); none rebuilds every section.
+	Audiences []string
+
+	// Logger receives progress and warnings; nil discards them.
+	Logger *logrus.Logger
+	Hooks  Hooks
+}
+
+// watchedPackage holds cached information about a package being watched
+type watchedPackage struct {
+	wsPath      string // workspace path (e.g., /path/to/grove-flow)
+	docgenDir   string // docgen dir in notebook (e.g., /path/to/nb/workspaces/flow/docgen)
+	conceptsDir string // concepts dir in notebook (e.g., /path/to/nb/workspaces/flow/concepts)
+	pkgName     string // package name (e.g., "flow")
+	config      *config.DocgenConfig
+}
+
+// runner is the state of one Run.
+type runner struct {
+	opts    Options
+	logger  *logrus.Logger
+	astro   *writer.AstroWriter
+	watched map[string]*watchedPackage // docgenDir -> package info
+	// sharedIncludes are the notebook includes directories watched for
+	// every package (see config.SharedIncludesDir).
+	sharedIncludes map[string]bool
+	// components are the component mappings of the website's config,
+	// which every package's mappings extend.
+	components []config.ComponentMapping
+
+	buildMu sync.Mutex // Serializes rebuild batches
+}
+
+// Run discovers the packages aggregate would build (see package discovery),
+// watches their notebook docgen and concepts directories, and rebuilds a
+// package into the website whenever its sources change. It returns when ctx
+// is cancelled.
+func Run(ctx context.Context, opts Options) error {
+	if opts.Mode == "" {
+		opts.Mode = "dev"
+	}
+	if err := config.CheckMode(opts.Mode); err != nil {
+		return err
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = 100 * time.Millisecond
+	}
+	if opts.WebsiteDir == "" {
+		opts.WebsiteDir = "."
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(io.Discard)
+	}
+
+	w, err := watcher.New()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer w.Close() //nolint:errcheck // best-effort close on exit
+
+	// Load local config to get allowed packages and ecosystems
+	cwd, _ := os.Getwd()
+	localCfg, _, _ := config.LoadWithNotebook(cwd)
+
+	r := &runner{
+		opts:           opts,
+		logger:         logger,
+		astro:          writer.NewAstro(opts.WebsiteDir),
+		watched:        make(map[string]*watchedPackage),
+		sharedIncludes: make(map[string]bool),
+	}
+	if localCfg != nil {
+		r.components = localCfg.Settings.Components
+	}
+
+	// Discover the same packages aggregate builds and set up recursive watching
+	disc := discovery.New(logger)
+	ecosystems, err := disc.Ecosystems(localCfg)
+	if err != nil {
+		return fmt.Errorf("failed to discover ecosystems: %w", err)
+	}
+	packages, err := disc.Packages(ecosystems, discovery.Options{Allowed: discovery.AllowedPackages(localCfg)})
+	if err != nil {
+		return fmt.Errorf("failed to discover packages: %w", err)
+	}
+
+	// Load core config for notebook locator
+	coreCfg, err := coreConfig.LoadDefault()
+	if err != nil {
+		return fmt.Errorf("failed to load core config: %w", err)
+	}
+	locator := workspace.NewNotebookLocator(coreCfg)
+
+	for _, pkg := range packages {
+		r.setupWatchForPackage(pkg, w, locator)
+	}
+
+	if len(r.watched) == 0 {
+		return ErrNoPackages
+	}
+	if opts.Hooks.OnReady != nil {
+		opts.Hooks.OnReady(len(r.watched))
+	}
+
+	// Debounce state
+	var mu sync.Mutex
+	pending := make(map[string]bool) // docgenDir -> needs rebuild
+	var timer *time.Timer
+
+	// Track whether changes are to concepts or regular docs
+	pendingConcepts := make(map[string]bool) // docgenDir -> needs concept rebuild
+
+	processPending := func() {
+		r.buildMu.Lock()
+		defer r.buildMu.Unlock()
+
+		mu.Lock()
+		toProcess := pending
+		toProcessConcepts := pendingConcepts
+		pending = make(map[string]bool)
+		pendingConcepts = make(map[string]bool)
+		mu.Unlock()
+
+		var events []Event
+		for docgenDir := range toProcess {
+			if pkg := r.watched[docgenDir]; pkg != nil {
+				events = append(events, r.rebuild(pkg, KindPackage, r.rebuildPackage))
+			}
+		}
+		for docgenDir := range toProcessConcepts {
+			if pkg := r.watched[docgenDir]; pkg != nil {
+				events = append(events, r.rebuild(pkg, KindConcepts, r.rebuildConcepts))
+			}
+		}
+		if len(events) > 0 && opts.Hooks.OnBatch != nil {
+			opts.Hooks.OnBatch(events)
+		}
+	}
+
+	// Main event loop, until ctx is cancelled
+	for {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			if timer != nil {
+				timer.Stop()
+			}
+			mu.Unlock()
+			return nil
+
+		case event, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+
+			// Handle new directory creation (add to watcher)
+			if event.Has(fsnotify.Create) {
+				wsPath := w.FindWorkspace(event.Name)
+				if wsPath != "" {
+					w.HandleNewDirectory(event, wsPath)
+				}
+			}
+
+			// Only process write and create events for relevant files
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			// A shared include can be in any package's docs
+			if r.isSharedInclude(event.Name) {
+				if watcher.IsRelevantFile(event.Name) {
+					mu.Lock()
+					for docgenDir := range r.watched {
+						pending[docgenDir] = true
+					}
+					if timer != nil {
+						timer.Stop()
+					}
+					timer = time.AfterFunc(opts.Debounce, processPending)
+					mu.Unlock()
+				}
+				continue
+			}
+
+			// Find the docgen directory this file belongs to
+			docgenDir := findDocgenDir(event.Name, r.watched)
+			if docgenDir == "" {
+				continue
+			}
+
+			// Check if it's a relevant file, including assets of the
+			// package's configured asset types
+			if !watcher.IsRelevantFile(event.Name) && !isPackageAsset(event.Name, r.watched[docgenDir]) {
+				// Also handle config file changes
+				if filepath.Base(event.Name) != "docgen.config.yml" {
+					continue
+				}
+			}
+
+			// Queue for debounced processing
+			mu.Lock()
+			if isConceptFile(event.Name, r.watched) {
+				pendingConcepts[docgenDir] = true
+			} else {
+				pending[docgenDir] = true
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(opts.Debounce, processPending)
+			mu.Unlock()
+
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Errorf("Watcher error: %v", err)
+		}
+	}
+}
+
+// rebuild runs one rebuild of pkg between the rebuild hooks.
+func (r *runner) rebuild(pkg *watchedPackage, kind string, build func(*watchedPackage) error) Event {
+	ev := Event{Package: pkg.pkgName, Kind: kind}
+	if r.opts.Hooks.OnRebuildStart != nil {
+		r.opts.Hooks.OnRebuildStart(ev)
+	}
+	start := time.Now()
+	ev.Err = build(pkg)
+	ev.Duration = time.Since(start)
+	if r.opts.Hooks.OnRebuild != nil {
+		r.opts.Hooks.OnRebuild(ev)
+	}
+	return ev
+}
+
+// setupWatchForPackage watches a package's notebook docgen directory, and its
+// concepts directory when it has one
+func (r *runner) setupWatchForPackage(pkg discovery.Package, w *watcher.RecursiveWatcher, locator *workspace.NotebookLocator) {
+	wsPath, wsName, docCfg := pkg.Path, pkg.Name, pkg.Config
+	// Get workspace node for notebook locator
+	node, err := workspace.GetProjectByPath(wsPath)
+	if err != nil {
+		return
+	}
+
+	// Get docgen directory in notebook
+	docgenDir, err := locator.GetDocgenDir(node)
+	if err != nil {
+		return
+	}
+
+	// Check if docgen dir exists
+	if _, err := os.Stat(docgenDir); os.IsNotExist(err) {
+		return
+	}
+
+	// Add recursive watch for docgen directory
+	if err := w.AddRecursive(docgenDir, wsPath); err != nil {
+		r.logger.Warnf("Failed to watch %s: %v", wsName, err)
+		return
+	}
+
+	// Also watch concepts directory if it exists
+	// concepts is at the same level as docgen: {notebook}/workspaces/{name}/concepts/
+	workspaceDir := filepath.Dir(docgenDir)
+	conceptsDir := filepath.Join(workspaceDir, "concepts")
+	if _, err := os.Stat(conceptsDir); err == nil {
+		if err := w.AddRecursive(conceptsDir, wsPath); err != nil {
+			r.logger.Warnf("Failed to watch concepts of %s: %v", wsName, err)
+		} else if r.opts.Hooks.OnWatching != nil {
+			r.opts.Hooks.OnWatching(wsName, conceptsDir)
+		}
+	}
+
+	// And the notebook's shared includes, once
+	if shared := config.SharedIncludesDir(wsPath); shared != "" && !r.sharedIncludes[shared] {
+		if _, err := os.Stat(shared); err == nil {
+			if err := w.AddRecursive(shared, wsPath); err != nil {
+				r.logger.Warnf("Failed to watch shared includes %s: %v", shared, err)
+			} else {
+				r.sharedIncludes[shared] = true
+			}
+		}
+	}
+
+	r.watched[docgenDir] = &watchedPackage{
+		wsPath:      wsPath,
+		docgenDir:   docgenDir,
+		conceptsDir: conceptsDir,
+		pkgName:     wsName,
+		config:      docCfg,
+	}
+
+	if r.opts.Hooks.OnWatching != nil {
+		r.opts.Hooks.OnWatching(wsName, docgenDir)
+	}
+}
+
+// isSharedInclude reports whether a file is in a shared includes directory.
+func (r *runner) isSharedInclude(filePath string) bool {
+	for dir := range r.sharedIncludes {
+		if strings.HasPrefix(filePath, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandIncludes inlines the include directives of a doc of the package at
+// wsPath, warning about those that cannot be resolved.
+func (r *runner) expandIncludes(wsPath, pkgName, file string, content []byte) []byte {
+	if !transformer.HasIncludes(string(content)) {
+		return content
+	}
+	expanded, errs := transformer.ExpandIncludes(string(content), config.NewIncludeResolver(wsPath, r.logger))
+	for _, err := range errs {
+		r.logger.Warnf("Unresolved include in %s/%s: %v", pkgName, file, err)
+	}
+	return []byte(expanded)
+}
+
+// applyConditions keeps the conditional blocks of a doc that hold for the
+// website in the run's mode, warning about malformed ones.
+func (r *runner) applyConditions(pkgName, file string, content []byte) []byte {
+	if !transformer.HasConditions(string(content)) {
+		return content
+	}
+	out, err := transformer.ApplyConditions(string(content), transformer.Conditions{Target: transformer.TargetAstro, Mode: r.opts.Mode, Profile: config.ProfileInternal})
+	if err != nil {
+		r.logger.Warnf("Invalid conditional block in %s/%s: %v", pkgName, file, err)
+		return content
+	}
+	return []byte(out)
+}
+
+// findDocgenDir finds the docgen directory that contains the given file path
+func findDocgenDir(filePath string, watchedPkgs map[string]*watchedPackage) string {
+	for docgenDir, pkg := range watchedPkgs {
+		if strings.HasPrefix(filePath, docgenDir) {
+			return docgenDir
+		}
+		// Also check concepts directory
+		if pkg.conceptsDir != "" && strings.HasPrefix(filePath, pkg.conceptsDir) {
+			return docgenDir // Return docgenDir as the key
+		}
+	}
+	return ""
+}
+
+// isConceptFile checks if a file path is within a concepts directory
+func isConceptFile(filePath string, watchedPkgs map[string]*watchedPackage) bool {
+	for _, pkg := range watchedPkgs {
+		if pkg.conceptsDir != "" && strings.HasPrefix(filePath, pkg.conceptsDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildPackage rebuilds a single package and writes to the website
+func (r *runner) rebuildPackage(pkg *watchedPackage) error {
+	w, mode := r.astro, r.opts.Mode
+	// Reload config in case it changed - try notebook location first
+	docCfg, _, err := config.LoadWithNotebook(pkg.wsPath)
+	if err != nil || docCfg == nil {
+		return err
+	}
+
+	// Handle "sections" output mode (website content like overview, concepts)
+	if docCfg.Settings.OutputMode == "sections" {
+		return r.rebuildWebsiteSections(pkg, docCfg)
+	}
+
+	// Filter sections by status
+	sectionsToProcess := make([]config.SectionConfig, 0, len(docCfg.Sections))
+	for _, section := range docCfg.Sections {
+		if !config.Published(section.GetStatus(), mode) {
+			continue
+		}
+		sectionsToProcess = append(sectionsToProcess, section)
+	}
+
+	if len(sectionsToProcess) == 0 {
+		return nil
+	}
+
+	// Sort sections by order
+	sort.Slice(sectionsToProcess, func(i, j int) bool {
+		return sectionsToProcess[i].Order < sectionsToProcess[j].Order
+	})
+
+	// Get version from git
+	version := docgenVersion.Resolve(pkg.wsPath)
+
+	// Process each section, collecting the package's manifest entry
+	docsDir := filepath.Join(pkg.docgenDir, "docs")
+	entry := manifest.PackageManifest{
+		Name:        pkg.pkgName,
+		Title:       docCfg.Title,
+		Description: docCfg.Description,
+		Category:    docCfg.Category,
+		DocsPath:    fmt.Sprintf("./%s", pkg.pkgName),
+		Version:     version,
+		TocDepth:    docCfg.Settings.TocDepth,
+	}
+	for i, section := range sectionsToProcess {
+		srcFile := filepath.Join(docsDir, section.Output)
+		content, err := os.ReadFile(srcFile)
+		if err != nil {
+			r.logger.Warnf("Could not read section %s of %s: %v", section.Output, pkg.pkgName, err)
+			continue
+		}
+		audience := section.Audience
+		if len(audience) == 0 {
+			audience = frontmatter.Audience(content)
+		}
+		if !config.ForAudience(audience, r.opts.Audiences) {
+			continue
+		}
+
+		// Apply strip lines if configured
+		if section.AggStripLines > 0 {
+			lines := strings.Split(string(content), "\n")
+			if len(lines) > section.AggStripLines {
+				content = []byte(strings.Join(lines[section.AggStripLines:], "\n"))
+			}
+		}
+		content = r.expandIncludes(pkg.wsPath, pkg.pkgName, section.Output, content)
+		content = r.applyConditions(pkg.pkgName, section.Output, content)
+
+		meta := writer.DocMetadata{
+			Title:       section.Title,
+			Description: docCfg.Description,
+			Category:    docCfg.Category,
+			Version:     version,
+			Order:       i + 1,
+			Package:     docCfg.Title,
+			AssetDirs:   config.AssetDirs(docCfg.AssetTypes()),
+			Components:  config.Components(docCfg.ComponentMappings(r.components)),
+			MDX:         transformer.IsMDX(section.Output),
+		}
+
+		transformed, err := w.TransformContent(content, pkg.pkgName, meta)
+		if err != nil {
+			continue
+		}
+
+		// A page using mapped components is written as MDX
+		output := section.Output
+		if transformer.UsesComponents(string(content), meta.Components) {
+			output = transformer.MDXPath(output)
+		}
+		r.removeSibling(filepath.Join(w.WebsiteDir(), "src/content/docs", pkg.pkgName, output))
+		if err := w.WriteDoc(pkg.pkgName, output, transformed, meta); err != nil {
+			r.logger.Errorf("Failed to write doc %s of %s: %v", output, pkg.pkgName, err)
+			continue
+		}
+		entry.Sections = append(entry.Sections, manifest.SectionManifest{
+			Title:    section.Title,
+			Path:     fmt.Sprintf("./%s/%s", pkg.pkgName, output),
+			Modified: manifest.FileModTime(srcFile),
+			Status:   section.GetStatus(),
+			Audience: audience,
+		})
+	}
+
+	// Copy assets
+	copyAssets(pkg.docgenDir, pkg.pkgName, config.AssetDirs(docCfg.AssetTypes()), w)
+
+	// Annotate download links with the size and hash of the copied files
+	if err := transformer.AnnotateDownloadsInDir(
+		filepath.Join(w.WebsiteDir(), "src/content/docs", pkg.pkgName),
+		filepath.Join(w.WebsiteDir(), "public/docs", pkg.pkgName, transformer.DownloadsDir),
+	); err != nil {
+		r.logger.Warnf("Failed to annotate download links for %s: %v", pkg.pkgName, err)
+	}
+
+	// Copy additional logos from config
+	r.copyLogos(docCfg.Logos, pkg.pkgName)
+
+	// Patch the package's manifest entry
+	if err := updateManifest(w, entry, mode, r.opts.Audiences); err != nil {
+		r.logger.Warnf("Failed to update manifest for %s: %v", pkg.pkgName, err)
+	}
+
+	return nil
+}
+
+// rebuildConcepts rebuilds concepts for a package
+func (r *runner) rebuildConcepts(pkg *watchedPackage) error {
+	w, mode := r.astro, r.opts.Mode
+	if pkg.conceptsDir == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(pkg.conceptsDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	// Reload config
+	docCfg, _, err := config.LoadWithNotebook(pkg.wsPath)
+	if err != nil || docCfg == nil {
+		return err
+	}
+
+	// Scan for concept subdirectories
+	entries, err := os.ReadDir(pkg.conceptsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		conceptID := entry.Name()
+		conceptDir := filepath.Join(pkg.conceptsDir, conceptID)
+
+		// Read concept manifest
+		manifestPath := filepath.Join(conceptDir, "concept-manifest.yml")
+		manifestData, err := os.ReadFile(manifestPath)
+		if err != nil {
+			continue
+		}
+
+		// Parse manifest (simple YAML parsing)
+		var title, publish string
+		var docgenOrder []string
+		for _, line := range strings.Split(string(manifestData), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "title:") {
+				title = strings.Trim(strings.TrimPrefix(line, "title:"), " \"'")
+			} else if strings.HasPrefix(line, "docgen_publish:") {
+				publish = strings.TrimSpace(strings.Split(strings.TrimPrefix(line, "docgen_publish:"), "#")[0])
+			} else if strings.HasPrefix(line, "- ") && len(docgenOrder) > 0 || strings.HasPrefix(line, "docgen_order:") {
+				if strings.HasPrefix(line, "- ") {
+					docgenOrder = append(docgenOrder, strings.TrimPrefix(line, "- "))
+				}
+			}
+		}
+
+		// Check publish status
+		if publish == "" {
+			publish = config.StatusDraft
+		}
+		if !config.Published(publish, mode) {
+			continue
+		}
+
+		r.logger.Infof("Rebuilding concept %s of %s", conceptID, pkg.pkgName)
+
+		// Get list of .md files to process
+		var mdFiles []string
+		if len(docgenOrder) > 0 {
+			for _, f := range docgenOrder {
+				path := filepath.Join(conceptDir, f)
+				if _, err := os.Stat(path); err == nil {
+					mdFiles = append(mdFiles, path)
+				}
+			}
+		} else {
+			mdFiles, _ = filepath.Glob(filepath.Join(conceptDir, "*.md"))
+		}
+
+		// Process each .md file
+		for i, mdPath := range mdFiles {
+			mdFile := filepath.Base(mdPath)
+			content, err := os.ReadFile(mdPath)
+			if err != nil {
+				continue
+			}
+
+			// Strip existing frontmatter
+			body := stripFrontmatter(string(content))
+
+			// Generate title from filename
+			docTitle := formatConceptDocTitle(strings.TrimSuffix(mdFile, ".md"))
+
+			// Calculate order
+			order := 2000 + i + 1
+
+			// Build new content with frontmatter
+			newContent := fmt.Sprintf(`---
+title: "%s"
+package: "%s"
+category: "%s"
+order: %d
+concept_title: "%s"
+concept_id: "%s"
+---
+
+%s`, docTitle, pkg.pkgName, docCfg.Category, order, title, conceptID, body)
+
+			// Write to website
+			destPath := filepath.Join(w.WebsiteDir(), "src/content/docs", pkg.pkgName, "concepts", conceptID, mdFile)
+			if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+				continue
+			}
+			if err := os.WriteFile(destPath, []byte(newContent), 0o644); err != nil {
+				r.logger.Errorf("Failed to write concept doc %s: %v", destPath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// stripFrontmatter removes YAML frontmatter from markdown content
+func stripFrontmatter(content string) string {
+	if !strings.HasPrefix(content, "---\n") {
+		return content
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return content
+	}
+	return strings.TrimLeft(content[end+8:], "\n")
+}
+
+// formatConceptDocTitle formats a filename into a title
+func formatConceptDocTitle(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+
+	acronyms := map[string]string{
+		"cli": "CLI", "tui": "TUI", "api": "API",
+		"ui": "UI", "id": "ID", "llm": "LLM",
+	}
+
+	for i, part := range parts {
+		lower := strings.ToLower(part)
+		if acronym, ok := acronyms[lower]; ok {
+			parts[i] = acronym
+		} else if len(part) > 0 {
+			parts[i] = strings.ToUpper(string(part[0])) + part[1:]
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// rebuildWebsiteSections handles output_mode: sections (overview, concepts)
+// Discovers section subdirectories with their own docgen.config.yml and processes them.
+func (r *runner) rebuildWebsiteSections(pkg *watchedPackage, docCfg *config.DocgenConfig) error {
+	w, mode := r.astro, r.opts.Mode
+	// Discover section subdirectories that have their own docgen.config.yml
+	entries, err := os.ReadDir(pkg.docgenDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		sectionName := entry.Name()
+		sectionDir := filepath.Join(pkg.docgenDir, sectionName)
+
+		// Check if this subdirectory has its own docgen.config.yml
+		sectionConfigPath := filepath.Join(sectionDir, config.ConfigFileName)
+		if _, err := os.Stat(sectionConfigPath); os.IsNotExist(err) {
+			continue // Not a section directory
+		}
+
+		// Load the section's config
+		sectionCfg, err := config.LoadFromPath(sectionConfigPath)
+		if err != nil {
+			continue
+		}
+
+		if !sectionCfg.Enabled {
+			continue
+		}
+
+		// Resolve docs directory
+		docsSubdir := "docs"
+		if sectionCfg.Settings.OutputDir != "" {
+			docsSubdir = sectionCfg.Settings.OutputDir
+		}
+		docsDir := filepath.Join(sectionDir, docsSubdir)
+
+		// Process sections from the section's config
+		for _, sec := range sectionCfg.Sections {
+			if !config.Published(sec.GetStatus(), mode) {
+				continue
+			}
+
+			srcPath := filepath.Join(docsDir, sec.Output)
+			content, err := os.ReadFile(srcPath)
+			if err != nil {
+				continue
+			}
+			if !config.ForAudience(frontmatter.SectionAudience(sec, srcPath), r.opts.Audiences) {
+				continue
+			}
+			content = r.expandIncludes(pkg.wsPath, sectionName, sec.Output, content)
+			content = r.applyConditions(sectionName, sec.Output, content)
+
+			// Transform content (rewrite paths) using central transformer
+			components := config.Components(sectionCfg.ComponentMappings(r.components))
+			transformed := transformWebsiteSection(content, sectionName, sectionCfg, components, transformer.IsMDX(sec.Output))
+
+			// Write to website content collection, as MDX when the page
+			// uses mapped components
+			destPath := filepath.Join(w.WebsiteDir(), "src/content", sectionName, sec.Output)
+			if transformer.UsesComponents(string(content), components) {
+				destPath = transformer.MDXPath(destPath)
+			}
+			r.removeSibling(destPath)
+			if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+				continue
+			}
+			if err := os.WriteFile(destPath, transformed, 0o644); err != nil {
+				r.logger.Errorf("Failed to write section file %s: %v", destPath, err)
+			}
+		}
+
+		// Copy assets for this section
+		copyWebsiteSectionAssets(sectionDir, sectionName, config.AssetDirs(sectionCfg.AssetTypes()), w)
+		if err := transformer.AnnotateDownloadsInDir(
+			filepath.Join(w.WebsiteDir(), "src/content", sectionName),
+			filepath.Join(w.WebsiteDir(), "public/docs", sectionName, transformer.DownloadsDir),
+		); err != nil {
+			r.logger.Warnf("Failed to annotate download links for section %s: %v", sectionName, err)
+		}
+	}
+
+	return nil
+}
+
+// transformWebsiteSection transforms paths and augments frontmatter for website section content
+// using the central transformer package for consistency with aggregate command.
+func transformWebsiteSection(content []byte, sectionName string, sectionCfg *config.DocgenConfig, components []transformer.Component, mdx bool) []byte {
+	trans := transformer.NewAstroTransformer()
+	opts := transformer.TransformOptions{
+		SectionName: sectionName,
+		Category:    sectionCfg.Category,
+		AssetDirs:   config.AssetDirs(sectionCfg.AssetTypes()),
+		Components:  components,
+		MDX:         mdx,
+	}
+	return trans.TransformWebsiteSection(content, opts)
+}
+
+// removeSibling removes the page an earlier rebuild wrote for the doc whose
+// page is path in the other format (see transformer.PageSibling).
+func (r *runner) removeSibling(path string) {
+	sibling := transformer.PageSibling(path)
+	if sibling == "" {
+		return
+	}
+	if err := os.Remove(sibling); err != nil && !os.IsNotExist(err) {
+		r.logger.Warnf("Failed to remove %s: %v", sibling, err)
+	}
+}
+
+// isPackageAsset reports whether a file is routed to one of the package's
+// asset directories.
+func isPackageAsset(path string, pkg *watchedPackage) bool {
+	if pkg == nil {
+		return false
+	}
+	return config.AssetTypeFor(pkg.config.AssetTypes(), path) != ""
+}
+
+// copyAssets copies the package's asset directories (images, asciicasts,
+// videos, downloads and any from settings.assets) to the website public directory
+func copyAssets(docgenDir, pkgName string, assetTypes []string, w *writer.AstroWriter) {
+	for _, assetType := range assetTypes {
+		srcDir := filepath.Join(docgenDir, assetType)
+		if _, err := os.Stat(srcDir); os.IsNotExist(err) {
+			continue
+		}
+
+		_ = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			filename := filepath.Base(path)
+			_ = w.WriteAsset(pkgName, assetType, filename, data)
+			return nil
+		})
+	}
+}
+
+// copyLogos copies additional logo files specified in the logos: config
+func (r *runner) copyLogos(logos []string, pkgName string) {
+	for _, logoPath := range logos {
+		// Expand ~ in path
+		expandedPath := expandHomePath(logoPath)
+		data, err := os.ReadFile(expandedPath)
+		if err != nil {
+			r.logger.Warnf("Could not read logo file %s: %v", expandedPath, err)
+			continue
+		}
+		filename := filepath.Base(expandedPath)
+		_ = r.astro.WriteAsset(pkgName, "images", filename, data)
+	}
+}
+
+// expandHomePath expands ~ to user home directory
+func expandHomePath(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			return filepath.Join(home, p[2:])
+		}
+	}
+	return p
+}
+
+// copyWebsiteSectionAssets copies assets for a website section
+func copyWebsiteSectionAssets(srcDir, sectionName string, assetTypes []string, w *writer.AstroWriter) {
+	for _, assetType := range assetTypes {
+		assetDir := filepath.Join(srcDir, assetType)
+		if _, err := os.Stat(assetDir); os.IsNotExist(err) {
+			continue
+		}
+
+		_ = filepath.Walk(assetDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			filename := filepath.Base(path)
+			_ = w.WriteAsset(sectionName, assetType, filename, data)
+			return nil
+		})
+	}
+}
+
+// updateManifest patches a rebuilt package's entry (version, sections and
+// their modified times) into the website's manifest, leaving the other
+// packages and the fields a watch rebuild does not know as they are. A
+// manifest aggregated in another mode, for other audiences or with the
+// public profile is left alone, so a dev watch never adds dev sections to a
+// prod manifest nor internal sections to a public one.
+func updateManifest(w *writer.AstroWriter, entry manifest.PackageManifest, mode string, audiences []string) error {
+	manifestPath := filepath.Join(w.WebsiteDir(), "docgen-output/manifest.json")
+	m, err := manifest.Load(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // Manifest doesn't exist yet, will be created by full aggregate
+	}
+	if err != nil {
+		return err
+	}
+	if m.Mode != "" && m.Mode != mode {
+		return fmt.Errorf("manifest was aggregated in %s mode, not %s", m.Mode, mode)
+	}
+	if m.Profile == config.ProfilePublic {
+		return fmt.Errorf("manifest was aggregated with the %s profile", m.Profile)
+	}
+	if !slices.Equal(m.Audiences, audiences) {
+		return fmt.Errorf("manifest was aggregated for audiences %v, not %v", m.Audiences, audiences)
+	}
+	m.PatchPackage(entry)
+	m.GeneratedAt = time.Now()
+	return m.Save(manifestPath)
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/grovetools/docgen/pkg/config"
 )
 
 // RecursiveWatcher wraps fsnotify with recursive directory support.
@@ -123,35 +124,15 @@ func IsMarkdownFile(path string) bool {
 	return ext == ".md" || ext == ".mdx"
 }
 
-// IsAssetFile checks if a file is an asset file (image, video, cast)
+// IsAssetFile checks if a file is an asset file of one of the built-in asset
+// types (image, video, cast, download)
 func IsAssetFile(path string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	assetExtensions := map[string]bool{
-		".png":  true,
-		".jpg":  true,
-		".jpeg": true,
-		".gif":  true,
-		".svg":  true,
-		".webp": true,
-		".mp4":  true,
-		".webm": true,
-		".mov":  true,
-		".cast": true,
-	}
-	return assetExtensions[ext]
+	return GetAssetType(path) != ""
 }
 
-// GetAssetType returns the asset type directory based on file extension
+// GetAssetType returns the built-in asset type directory a file is routed
+// to, by extension or MIME type. Packages with asset types from
+// settings.assets use config.AssetTypeFor with their own types.
 func GetAssetType(path string) string {
-	ext := strings.ToLower(filepath.Ext(path))
-	switch ext {
-	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp":
-		return "images"
-	case ".mp4", ".webm", ".mov":
-		return "videos"
-	case ".cast":
-		return "asciicasts"
-	default:
-		return ""
-	}
+	return config.AssetTypeFor(config.DefaultAssetTypes(), path)
 }
@@ -14,6 +14,7 @@ import (
 // - Writing assets to public/docs/{pkg}/
 // - Rewriting relative paths to absolute paths
 // - Injecting/managing frontmatter
+// - Rewriting callouts as Starlight asides
 type AstroWriter struct {
 	websiteDir string // e.g., "./grove-website"
 }
@@ -28,9 +29,10 @@ func (w *AstroWriter) WebsiteDir() string {
 	return w.websiteDir
 }
 
-// WriteDoc writes a documentation file to src/content/docs/{pkg}/{filename}
+// WriteDoc writes a documentation file to src/content/docs/{pkg}/{filename},
+// or src/content/docs/{lang}/{pkg}/{filename} for a translation.
 func (w *AstroWriter) WriteDoc(pkg, filename string, content []byte, meta DocMetadata) error {
-	path := filepath.Join(w.websiteDir, "src/content/docs", pkg, filename)
+	path := filepath.Join(w.websiteDir, "src/content/docs", meta.Language, pkg, filename)
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // internal doc tool, predictable paths
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
@@ -66,6 +68,14 @@ func (w *AstroWriter) TransformContent(content []byte, pkg string, meta DocMetad
 		Version:     meta.Version,
 		Category:    meta.Category,
 		Order:       meta.Order,
+		AssetDirs:   meta.AssetDirs,
+		Components:  meta.Components,
+		MDX:         meta.MDX,
 	}
 	return trans.TransformStandardDoc(content, opts), nil
 }
+
+// CalloutStyle returns Starlight's aside syntax
+func (w *AstroWriter) CalloutStyle() transformer.CalloutStyle {
+	return transformer.CalloutStarlight
+}
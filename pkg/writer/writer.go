@@ -1,20 +1,28 @@
 package writer
 
+import "github.com/grovetools/docgen/pkg/transformer"
+
 // Writer abstracts output format for different static site generators.
 // This allows docgen to support multiple SSGs like Astro, Hugo, Docusaurus, etc.
 type Writer interface {
 	// WriteDoc writes transformed markdown to the appropriate location
 	WriteDoc(pkg, filename string, content []byte, meta DocMetadata) error
 
-	// WriteAsset copies an asset (image, video, cast) to the appropriate location
+	// WriteAsset copies an asset (image, video, cast, or a file of a
+	// configured asset type) to the appropriate location
 	WriteAsset(pkg, assetType, filename string, data []byte) error
 
 	// WriteManifest writes the manifest file
 	WriteManifest(manifest []byte) error
 
-	// TransformContent applies SSG-specific transformations (paths, frontmatter)
+	// TransformContent applies SSG-specific transformations (paths, frontmatter,
+	// callouts)
 	TransformContent(content []byte, pkg string, meta DocMetadata) ([]byte, error)
 
+	// CalloutStyle returns the SSG's native callout syntax, which
+	// TransformContent rewrites > [!NOTE] and :::note callouts into
+	CalloutStyle() transformer.CalloutStyle
+
 	// WebsiteDir returns the target website directory
 	WebsiteDir() string
 }
@@ -26,5 +34,9 @@ type DocMetadata struct {
 	Category    string
 	Version     string
 	Order       int
-	Package     string // Package title (for display)
+	Package     string                  // Package title (for display)
+	Language    string                  // Translation language; empty for the source docs
+	AssetDirs   []string                // Asset directories whose relative links are rewritten; nil means the built-in ones
+	Components  []transformer.Component // Components code blocks of mapped languages are replaced with
+	MDX         bool                    // The doc is MDX, whose component imports are managed
 }
@@ -1,34 +1,26 @@
 package main
 
 import (
-	"encoding/json"
 	"log"
-	"os"
 
-	"github.com/grovetools/docgen/pkg/config"
-	"github.com/invopop/jsonschema"
+	"github.com/grovetools/docgen/pkg/schema"
 )
 
+// Regenerates docgen's own config schema. This is the same reflection
+// `docgen schema reflect` performs, pinned to DocgenConfig and its output path.
 func main() {
-	r := &jsonschema.Reflector{
-		AllowAdditionalProperties: true,
-		ExpandedStruct:            true,
-		FieldNameTag:              "yaml",
+	opts := schema.ReflectOptions{
+		Type:                 "github.com/grovetools/docgen/pkg/config.DocgenConfig",
+		Out:                  "schema/docgen.config.schema.json",
+		FieldTag:             "yaml",
+		Title:                "Grove Docgen Configuration",
+		Description:          "Configuration schema for grove-docgen documentation generation.",
+		AdditionalProperties: true,
+		ExpandedStruct:       true,
 	}
-
-	schema := r.Reflect(&config.DocgenConfig{})
-	schema.Title = "Grove Docgen Configuration"
-	schema.Description = "Configuration schema for grove-docgen documentation generation."
-
-	data, err := json.MarshalIndent(schema, "", "  ")
-	if err != nil {
-		log.Fatalf("Error marshaling schema: %v", err)
-	}
-
-	// Write to schema directory
-	if err := os.WriteFile("schema/docgen.config.schema.json", data, 0o644); err != nil { //nolint:gosec // fixed output path
-		log.Fatalf("Error writing schema file: %v", err)
+	if err := schema.Reflect(".", opts); err != nil {
+		log.Fatalf("Error generating schema: %v", err)
 	}
 
-	log.Printf("Successfully generated docgen schema at schema/docgen.config.schema.json")
+	log.Printf("Successfully generated docgen schema at %s", opts.Out)
 }